@@ -75,9 +75,13 @@ func main() {
 	authService := services.NewAuthService(database.DB, redisClient, emailService)
 	clientService := services.NewClientService(database.DB)
 	addressService := services.NewAddressService(database.DB)
+	billingProfileService := services.NewBillingProfileService(database.DB, services.NewCUILookup(cfg.ANAF.VATRegistryURL))
 	cleanerService := services.NewCleanerService(database.DB, emailService)
 	pricingService := services.NewPricingService(database.DB)
+	feePolicyService := services.NewFeePolicyService(database.DB)
+	pricingService.SetFeePolicyService(feePolicyService) // Quote the platform fee from the shared fee policy
 	invoiceService := services.NewInvoiceService(database.DB, &cfg.Company, &cfg.ANAF)
+	invoiceService.SetEmailService(emailService) // Deliver invoices to clients by email
 	reviewService := services.NewReviewService(database.DB)
 	disputeService := services.NewDisputeService(database.DB)
 	photoService := services.NewPhotoService(database.DB, "./uploads")
@@ -85,24 +89,61 @@ func main() {
 	bookingService := services.NewBookingService(database.DB, pricingService, invoiceService, emailService)
 	paymentService := services.NewPaymentService(database.DB)
 	matchingService := services.NewCleanerMatchingService(database.DB, emailService)
-	bookingService.SetPaymentService(paymentService)   // Set payment service after creation
-	bookingService.SetMatchingService(matchingService) // Set matching service after creation
-	disputeService.SetPaymentService(paymentService)   // Set payment service for refunds
-	disputeService.SetBookingService(bookingService)   // Set booking service for recleans
-	disputeService.SetEmailService(emailService)       // Set email service for notifications
+	bookingService.SetPaymentService(paymentService)     // Set payment service after creation
+	bookingService.SetMatchingService(matchingService)   // Set matching service after creation
+	bookingService.SetFeePolicyService(feePolicyService) // Fix the platform fee when a cleaner is assigned
+	disputeService.SetPaymentService(paymentService)     // Set payment service for refunds
+	disputeService.SetBookingService(bookingService)     // Set booking service for recleans
+	disputeService.SetEmailService(emailService)         // Set email service for notifications
+	ledgerService := services.NewLedgerService(database.DB)
+	paymentService.SetLedgerService(ledgerService) // Record captures and refunds in the ledger
+	payoutService.SetLedgerService(ledgerService)  // Derive payout amounts from cleaner balances
+	walletService := services.NewWalletService(database.DB)
+	walletService.SetLedgerService(ledgerService)
+	bookingService.SetWalletService(walletService) // Spend store credit before charging the card
+	disputeService.SetWalletService(walletService) // Refund disputes to store credit
+	tipService := services.NewTipService(database.DB, paymentService)
+	tipService.SetLedgerService(ledgerService)
+	payoutAdjustmentService := services.NewPayoutAdjustmentService(database.DB)
+	payoutAdjustmentService.SetLedgerService(ledgerService)
+	disputeService.SetPayoutAdjustmentService(payoutAdjustmentService) // Claw back the cleaner's share of dispute refunds
 	availabilityService := services.NewAvailabilityService(database.DB)
 	companyService := services.NewCompanyService(database.DB)
 	checkinService := services.NewCheckinService(database.DB, bookingService)
+	checkinService.SetPaymentService(paymentService) // Confirm cash payments at check-out
 	adminAnalyticsService := services.NewAdminAnalyticsService(database.DB)
 	platformSettingsService := services.NewPlatformSettingsService(models.NewPlatformSettingsRepository(database.DB))
 	messagingService := services.NewMessagingService(database.DB)
 	cleanerApplicationService := services.NewCleanerApplicationService(database.DB)
+	idempotencyService := services.NewIdempotencyService(database.DB, redisClient)
+	bankReconciliationService := services.NewBankReconciliationService(database.DB, payoutService, invoiceService)
+	payoutBatchService := services.NewPayoutBatchService(database.DB, payoutService)
+	selfBillingService := services.NewSelfBillingService(database.DB, &cfg.Company, &cfg.ANAF)
+	payoutService.SetSelfBillingService(selfBillingService) // Issue invoices for self-billing cleaners
+	commissionInvoiceService := services.NewCommissionInvoiceService(database.DB, &cfg.Company, &cfg.ANAF)
+	payoutService.SetCommissionInvoiceService(commissionInvoiceService) // Invoice the fees retained from payouts
+	creditNoteService := services.NewCreditNoteService(database.DB, &cfg.Company, &cfg.ANAF)
+	paymentService.SetCreditNoteService(creditNoteService) // Credit the invoice of refunded bookings
+	anafWorker := services.NewANAFWorker(database.DB, invoiceService, emailService, &cfg.ANAF)
+	anafWorker.AddSubmitter(selfBillingService)
+	anafWorker.AddSubmitter(commissionInvoiceService)
+	anafWorker.AddSubmitter(creditNoteService)
+	invoiceSeriesService := services.NewInvoiceSeriesService(database.DB)
+	accountingExportService := services.NewAccountingExportService(database.DB, &cfg.Company, &cfg.Accounting)
+	proformaService := services.NewProformaService(database.DB, invoiceService, &cfg.Company, &cfg.Payment)
+	proformaService.SetLedgerService(ledgerService)
+	proformaService.SetEmailService(emailService)
+	bookingService.SetProformaService(proformaService)            // Issue proformas when bookings paid by bank transfer are confirmed
+	matchingService.SetProformaService(proformaService)           // ... and when they are auto-assigned
+	paymentService.SetProformaService(proformaService)            // ... or when the transfer is chosen after confirmation
+	bankReconciliationService.SetProformaService(proformaService) // Settle proformas from bank statements
 
 	// Initialize GraphQL resolver with dependencies
 	resolver := &graph.Resolver{
 		AuthService:               authService,
 		ClientService:             clientService,
 		AddressService:            addressService,
+		BillingProfileService:     billingProfileService,
 		CleanerService:            cleanerService,
 		BookingService:            bookingService,
 		PricingService:            pricingService,
@@ -119,6 +160,20 @@ func main() {
 		PlatformSettingsService:   platformSettingsService,
 		MessagingService:          messagingService,
 		CleanerApplicationService: cleanerApplicationService,
+		IdempotencyService:        idempotencyService,
+		LedgerService:             ledgerService,
+		BankReconciliationService: bankReconciliationService,
+		WalletService:             walletService,
+		TipService:                tipService,
+		PayoutAdjustmentService:   payoutAdjustmentService,
+		PayoutBatchService:        payoutBatchService,
+		SelfBillingService:        selfBillingService,
+		CommissionInvoiceService:  commissionInvoiceService,
+		CreditNoteService:         creditNoteService,
+		ANAFWorker:                anafWorker,
+		InvoiceSeriesService:      invoiceSeriesService,
+		AccountingExportService:   accountingExportService,
+		ProformaService:           proformaService,
 	}
 
 	// Create GraphQL server
@@ -137,7 +192,7 @@ func main() {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -158,15 +213,43 @@ func main() {
 	rateLimiter := middleware.NewRateLimiter(redisClient)
 	rateLimitMiddleware := rateLimiter.RateLimitMiddleware()
 
+	// Idempotency key middleware (makes money-moving mutations safe to retry)
+	idempotencyKeyMiddleware := middleware.IdempotencyKeyMiddleware()
+
 	// Security headers middleware
 	securityHeadersMiddleware := middleware.SecurityHeadersMiddleware()
 
 	// Start rate limiter fallback cache cleanup
 	go rateLimiter.CleanupFallbackCache(10 * time.Minute)
 
+	// Start expired idempotency key cleanup
+	go idempotencyService.CleanupExpiredKeys(1 * time.Hour)
+
+	// Start wallet credit expiry
+	go walletService.RunCreditExpiry(1 * time.Hour)
+
+	// Start overdue proforma reminders
+	if cfg.Payment.BankTransferEnabled {
+		go proformaService.RunOverdueReminders(6 * time.Hour)
+	}
+
+	// Start scheduled monthly payout generation
+	if cfg.Payout.ScheduleEnabled {
+		go payoutService.RunScheduledPayouts(6 * time.Hour)
+	}
+
+	// Start ANAF e-Factura submission, status polling and deadline alerts
+	if cfg.ANAF.Enabled {
+		interval := time.Duration(cfg.ANAF.WorkerIntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = 5 * time.Minute
+		}
+		go anafWorker.Run(interval)
+	}
+
 	// Setup routes
 	http.Handle("/", securityHeadersMiddleware(corsMiddleware(playground.Handler("GraphQL playground", "/graphql"))))
-	http.Handle("/graphql", securityHeadersMiddleware(corsMiddleware(rateLimitMiddleware(authMiddleware(responseWriterMiddleware(srv))))))
+	http.Handle("/graphql", securityHeadersMiddleware(corsMiddleware(rateLimitMiddleware(authMiddleware(idempotencyKeyMiddleware(responseWriterMiddleware(srv)))))))
 
 	// Serve uploaded files
 	fs := http.FileServer(http.Dir("./uploads"))
@@ -21,6 +21,8 @@ type Config struct {
 	Company      CompanyConfig      `yaml:"company"`
 	ANAF         ANAFConfig         `yaml:"anaf"`
 	Payment      PaymentConfig      `yaml:"payment"`
+	Payout       PayoutConfig       `yaml:"payout"`
+	Accounting   AccountingConfig   `yaml:"accounting"`
 	Notification NotificationConfig `yaml:"notifications"`
 	Features     FeaturesConfig     `yaml:"features"`
 	Business     BusinessConfig     `yaml:"business"`
@@ -52,7 +54,8 @@ type AuthConfig struct {
 type PricingConfig struct {
 	DefaultPlatformFeePercentage      float64             `yaml:"default_platform_fee_percentage"`
 	FirstBookingDiscountPercentage    float64             `yaml:"first_booking_discount_percentage"`
-	RepeatCustomerDiscountPercentage  float64             `yaml:"repeat_customer_discount_percentage"`
+	RepeatCustomerDiscountPercentage  float64             `yaml:"repeat_customer_discount_percentage"` // Platform fee on repeat clients' bookings
+	CleanerFeeTiers                   []CleanerFeeTier    `yaml:"cleaner_fee_tiers"`
 	StandardCleaning                  ServicePricing      `yaml:"standard_cleaning"`
 	DeepCleaning                      DeepCleaningPricing `yaml:"deep_cleaning"`
 	OfficeCleaning                    ServicePricing      `yaml:"office_cleaning"`
@@ -63,6 +66,14 @@ type PricingConfig struct {
 	FrequencyDiscounts                FrequencyDiscounts  `yaml:"frequency_discounts"`
 }
 
+// CleanerFeeTier lowers the platform fee for experienced, well-rated cleaners
+type CleanerFeeTier struct {
+	Name                  string  `yaml:"name"`
+	MinCompletedJobs      int     `yaml:"min_completed_jobs"`
+	MinRating             float64 `yaml:"min_rating"`
+	PlatformFeePercentage float64 `yaml:"platform_fee_percentage"`
+}
+
 type FrequencyDiscounts struct {
 	Weekly    float64 `yaml:"weekly"`     // Percentage discount for weekly bookings
 	Biweekly  float64 `yaml:"biweekly"`   // Percentage discount for biweekly bookings
@@ -96,13 +107,15 @@ type Multipliers struct {
 }
 
 type BookingConfig struct {
-	MinAdvanceBookingHours   int `yaml:"min_advance_booking_hours"`
-	MaxAdvanceBookingDays    int `yaml:"max_advance_booking_days"`
-	CancellationFreeHours    int `yaml:"cancellation_free_hours"`
-	CleanerSearchRadiusKm    int `yaml:"cleaner_search_radius_km"`
-	AutoAssignTimeoutMinutes int `yaml:"auto_assign_timeout_minutes"`
-	MinRating                int `yaml:"min_rating"`
-	MaxRating                int `yaml:"max_rating"`
+	MinAdvanceBookingHours        int     `yaml:"min_advance_booking_hours"`
+	MaxAdvanceBookingDays         int     `yaml:"max_advance_booking_days"`
+	CancellationFreeHours         int     `yaml:"cancellation_free_hours"`
+	LateCancellationFeePercentage float64 `yaml:"late_cancellation_fee_percentage"`
+	TipWindowDays                 int     `yaml:"tip_window_days"`
+	CleanerSearchRadiusKm         int     `yaml:"cleaner_search_radius_km"`
+	AutoAssignTimeoutMinutes      int     `yaml:"auto_assign_timeout_minutes"`
+	MinRating                     int     `yaml:"min_rating"`
+	MaxRating                     int     `yaml:"max_rating"`
 }
 
 type CleanerConfig struct {
@@ -126,6 +139,29 @@ type PaymentConfig struct {
 	PreauthEnabled       bool   `yaml:"preauth_enabled"`
 	CaptureOnCompletion  bool   `yaml:"capture_on_completion"`
 	RefundWindowDays     int    `yaml:"refund_window_days"`
+	CashEnabled          bool   `yaml:"cash_enabled"`
+	CashMaxJobsPerMonth  int    `yaml:"cash_max_jobs_per_month"`
+	BankTransferEnabled  bool   `yaml:"bank_transfer_enabled"`  // Company bookings may pay by bank transfer against a proforma
+	ProformaDueDays      int    `yaml:"proforma_due_days"`      // Days a proforma gives to pay, at most until the booking date
+	ProformaReminderDays int    `yaml:"proforma_reminder_days"` // Days between reminders for an overdue proforma
+	ProformaMaxReminders int    `yaml:"proforma_max_reminders"`
+}
+
+type PayoutConfig struct {
+	ScheduleEnabled bool `yaml:"schedule_enabled"`
+	GenerationDay   int  `yaml:"generation_day"` // Day of the month on which last month's payouts are generated
+}
+
+// AccountingConfig maps the ledger to the Romanian chart of accounts for accounting exports
+type AccountingConfig struct {
+	Accounts     map[string]string `yaml:"accounts"`       // Ledger account (CASH, ...) to chart of accounts code (5121, ...)
+	SAFTTaxCodes []SAFTTaxCode     `yaml:"saft_tax_codes"` // SAF-T (D406) VAT codes by rate
+}
+
+// SAFTTaxCode is the code ANAF's SAF-T nomenclature gives a VAT rate
+type SAFTTaxCode struct {
+	Rate float64 `yaml:"rate"`
+	Code string  `yaml:"code"`
 }
 
 type NotificationConfig struct {
@@ -166,11 +202,21 @@ type CompanyConfig struct {
 	RegistrationNumber string        `yaml:"registration_number"`
 	VATRegistered      bool          `yaml:"vat_registered"`
 	VATRate            float64       `yaml:"vat_rate"`
+	VATRates           []VATRateConfig `yaml:"vat_rates"`          // Rates by category and effective date; vat_rate is the fallback
+	PricesExcludeVAT   bool          `yaml:"prices_exclude_vat"` // Client prices are net and VAT is added on top
 	Address            CompanyAddress `yaml:"address"`
 	Contact            CompanyContact `yaml:"contact"`
 	Bank               CompanyBank    `yaml:"bank"`
 }
 
+// VATRateConfig is a VAT rate for a category of invoice lines (service, addons, supplies),
+// applying from EffectiveFrom (YYYY-MM-DD). An empty category applies to all lines.
+type VATRateConfig struct {
+	Category      string  `yaml:"category"`
+	Rate          float64 `yaml:"rate"`
+	EffectiveFrom string  `yaml:"effective_from"`
+}
+
 type CompanyAddress struct {
 	Street     string `yaml:"street"`
 	City       string `yaml:"city"`
@@ -204,6 +250,12 @@ type ANAFConfig struct {
 	MaxRetryAttempts       int    `yaml:"max_retry_attempts"`
 	RetryDelayMinutes      int    `yaml:"retry_delay_minutes"`
 	SubmissionDeadlineDays int    `yaml:"submission_deadline_days"`
+	DeadlineWarningDays    int    `yaml:"deadline_warning_days"`   // Alert admins this many days before the deadline
+	WorkerIntervalMinutes  int    `yaml:"worker_interval_minutes"` // How often the background worker runs
+	WorkerBatchSize        int    `yaml:"worker_batch_size"`       // Documents submitted per run and type
+	ArchiveDir             string `yaml:"archive_dir"`             // Where ANAF's signed responses are archived
+	RetentionYears         int    `yaml:"retention_years"`         // How long archived responses must be kept
+	VATRegistryURL         string `yaml:"vat_registry_url"`        // ANAF's public VAT payer registry; empty uses a local stub
 }
 
 var appConfig *Config
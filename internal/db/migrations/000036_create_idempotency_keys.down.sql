@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_idempotency_keys_updated_at ON idempotency_keys;
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,29 @@
+-- Create idempotency_keys table for replay-safe money-moving mutations
+-- A key is scoped to the user and the mutation it was sent with
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL,
+    operation VARCHAR(100) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+
+    -- SHA-256 of the mutation arguments, used to reject key reuse with a different request
+    request_fingerprint VARCHAR(64) NOT NULL,
+
+    -- Serialized GraphQL response (NULL while the first request is still executing)
+    response JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'IN_PROGRESS' CHECK (status IN ('IN_PROGRESS', 'COMPLETED')),
+
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE (user_id, operation, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+CREATE TRIGGER update_idempotency_keys_updated_at
+    BEFORE UPDATE ON idempotency_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
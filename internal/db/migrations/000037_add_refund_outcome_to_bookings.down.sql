@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_refund_status_check;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS refund_status,
+    DROP COLUMN IF EXISTS refund_amount,
+    DROP COLUMN IF EXISTS refund_processed_at;
@@ -0,0 +1,15 @@
+-- Record what happened to the client's payment when a booking is cancelled
+ALTER TABLE bookings
+    ADD COLUMN refund_status VARCHAR(30),
+    ADD COLUMN refund_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN refund_processed_at TIMESTAMP;
+
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_refund_status_check CHECK (
+        refund_status IS NULL OR refund_status IN (
+            'NO_PAYMENT', 'HOLD_RELEASED', 'REFUNDED', 'PARTIALLY_REFUNDED', 'NOT_ELIGIBLE', 'FAILED'
+        )
+    );
+
+COMMENT ON COLUMN bookings.refund_status IS 'Outcome of automatic refund processing on cancellation';
+COMMENT ON COLUMN bookings.refund_amount IS 'Amount returned to the client (released hold or refund) in RON';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON ledger_entries;
+DROP TRIGGER IF EXISTS ledger_transactions_append_only ON ledger_transactions;
+DROP FUNCTION IF EXISTS prevent_ledger_modification();
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
@@ -0,0 +1,59 @@
+-- Append-only double-entry ledger
+-- Every money movement (capture, refund, payout, adjustment) posts one transaction
+-- whose entries have equal total debits and credits.
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+
+    -- What caused the posting: CAPTURE, REFUND, PAYOUT, ADJUSTMENT
+    transaction_type VARCHAR(30) NOT NULL,
+    -- ID of the payment, payout or adjustment that caused it (one posting per source)
+    reference_id TEXT NOT NULL,
+    booking_id TEXT REFERENCES bookings(id) ON DELETE SET NULL,
+    description TEXT NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE (transaction_type, reference_id)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    transaction_id TEXT NOT NULL REFERENCES ledger_transactions(id),
+
+    account VARCHAR(50) NOT NULL CHECK (account IN (
+        'CLIENT_RECEIVABLES', 'CLEANER_PAYABLES', 'PLATFORM_REVENUE', 'REFUNDS', 'VAT_PAYABLE', 'CASH'
+    )),
+    -- Sub-ledger for CLEANER_PAYABLES (cleaners.id)
+    cleaner_id TEXT REFERENCES cleaners(id),
+
+    debit DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CHECK ((debit = 0) <> (credit = 0))
+);
+
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_account ON ledger_entries(account);
+CREATE INDEX idx_ledger_entries_cleaner_id ON ledger_entries(cleaner_id) WHERE cleaner_id IS NOT NULL;
+CREATE INDEX idx_ledger_transactions_booking_id ON ledger_transactions(booking_id);
+
+-- The ledger is append-only: corrections are posted as new transactions
+CREATE OR REPLACE FUNCTION prevent_ledger_modification()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_transactions_append_only
+    BEFORE UPDATE OR DELETE ON ledger_transactions
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
+
+CREATE TRIGGER ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_ledger_modification();
@@ -0,0 +1,46 @@
+-- Imported bank statements and their lines, used to reconcile outgoing payouts
+-- and incoming B2B invoice payments against the company bank account.
+
+CREATE TABLE IF NOT EXISTS bank_statement_imports (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('CAMT053', 'MT940')),
+    imported_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+
+    line_count INTEGER NOT NULL DEFAULT 0,
+    matched_count INTEGER NOT NULL DEFAULT 0,
+    -- Lines already present from an earlier import of an overlapping statement
+    duplicate_count INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    import_id TEXT NOT NULL REFERENCES bank_statement_imports(id) ON DELETE CASCADE,
+
+    booking_date DATE NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('CREDIT', 'DEBIT')),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+    counterparty_name VARCHAR(255),
+    counterparty_iban VARCHAR(34),
+    reference TEXT,
+    bank_reference VARCHAR(100),
+
+    -- Hash of date, direction, amount, IBAN and references; prevents importing the same line twice
+    fingerprint VARCHAR(64) NOT NULL UNIQUE,
+
+    match_status VARCHAR(20) NOT NULL DEFAULT 'UNMATCHED' CHECK (match_status IN ('UNMATCHED', 'MATCHED', 'IGNORED')),
+    matched_payout_id TEXT REFERENCES payouts(id) ON DELETE SET NULL,
+    matched_invoice_id TEXT REFERENCES invoices(id) ON DELETE SET NULL,
+    -- NULL when matched automatically during import
+    resolved_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    notes TEXT,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_bank_statement_lines_import ON bank_statement_lines(import_id);
+CREATE INDEX idx_bank_statement_lines_status ON bank_statement_lines(match_status, booking_date);
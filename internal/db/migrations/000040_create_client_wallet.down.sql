@@ -0,0 +1,12 @@
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_check CHECK (account IN (
+    'CLIENT_RECEIVABLES', 'CLEANER_PAYABLES', 'PLATFORM_REVENUE', 'REFUNDS', 'VAT_PAYABLE', 'CASH'
+));
+
+ALTER TABLE disputes DROP CONSTRAINT IF EXISTS disputes_resolution_type_check;
+ALTER TABLE disputes ADD CONSTRAINT disputes_resolution_type_check
+    CHECK (resolution_type IN ('PARTIAL_REFUND', 'FULL_REFUND', 'RECLEAN', 'REJECTED'));
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS credit_applied;
+
+DROP TABLE IF EXISTS wallet_transactions;
@@ -0,0 +1,48 @@
+-- Client wallet (store credit)
+-- Credits (referral rewards, goodwill, dispute refunds, gift cards) are added as lots with an
+-- optional expiry; debits consume the lots that expire first.
+
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Positive for credits, negative for debits
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount <> 0),
+    source VARCHAR(30) NOT NULL CHECK (source IN (
+        'REFERRAL', 'GOODWILL', 'DISPUTE_REFUND', 'GIFT_CARD',
+        'BOOKING_PAYMENT', 'BOOKING_CANCELLATION', 'EXPIRY'
+    )),
+    reason TEXT,
+
+    -- Unspent part of a credit lot (always 0 for debits)
+    remaining_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (remaining_amount >= 0),
+    expires_at TIMESTAMP,
+
+    booking_id TEXT REFERENCES bookings(id) ON DELETE SET NULL,
+    dispute_id TEXT REFERENCES disputes(id) ON DELETE SET NULL,
+    created_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CHECK (remaining_amount <= GREATEST(amount, 0))
+);
+
+CREATE INDEX idx_wallet_transactions_user ON wallet_transactions(user_id, created_at DESC);
+CREATE INDEX idx_wallet_transactions_open_lots ON wallet_transactions(user_id, expires_at) WHERE remaining_amount > 0;
+CREATE INDEX idx_wallet_transactions_booking ON wallet_transactions(booking_id) WHERE booking_id IS NOT NULL;
+-- A dispute can be refunded to credit only once
+CREATE UNIQUE INDEX idx_wallet_transactions_dispute ON wallet_transactions(dispute_id) WHERE source = 'DISPUTE_REFUND';
+
+-- Credit applied to a booking before the card is charged
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS credit_applied DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Disputes can be refunded to the wallet instead of the card
+ALTER TABLE disputes DROP CONSTRAINT IF EXISTS disputes_resolution_type_check;
+ALTER TABLE disputes ADD CONSTRAINT disputes_resolution_type_check
+    CHECK (resolution_type IN ('PARTIAL_REFUND', 'FULL_REFUND', 'REFUND_TO_CREDIT', 'RECLEAN', 'REJECTED'));
+
+-- Outstanding store credit is a liability in the ledger
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries ADD CONSTRAINT ledger_entries_account_check CHECK (account IN (
+    'CLIENT_RECEIVABLES', 'CLEANER_PAYABLES', 'PLATFORM_REVENUE', 'REFUNDS', 'VAT_PAYABLE', 'CASH', 'CLIENT_CREDITS'
+));
@@ -0,0 +1,3 @@
+ALTER TABLE payout_line_items DROP COLUMN IF EXISTS item_type;
+
+DROP TABLE IF EXISTS tips;
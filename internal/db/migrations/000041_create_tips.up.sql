@@ -0,0 +1,25 @@
+-- Tips paid by clients after a completed booking
+-- Tips go to the cleaner in full: no platform fee and no VAT on our service invoice.
+
+CREATE TABLE IF NOT EXISTS tips (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    booking_id TEXT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    client_id TEXT NOT NULL REFERENCES users(id),
+    -- cleaners.id of the cleaner who did the job
+    cleaner_id TEXT NOT NULL REFERENCES cleaners(id),
+    payment_id TEXT NOT NULL REFERENCES payments(id),
+
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- One tip per booking
+    UNIQUE (booking_id)
+);
+
+CREATE INDEX idx_tips_cleaner_id ON tips(cleaner_id, created_at);
+
+-- Payout line items are either a booking or a tip
+ALTER TABLE payout_line_items ADD COLUMN IF NOT EXISTS item_type VARCHAR(20) NOT NULL DEFAULT 'BOOKING'
+    CHECK (item_type IN ('BOOKING', 'TIP'));
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_payments_cash;
+
+COMMENT ON COLUMN payments.provider IS 'Payment gateway: NETOPIA, MANUAL';
+
+ALTER TABLE payout_line_items DROP CONSTRAINT IF EXISTS payout_line_items_item_type_check;
+ALTER TABLE payout_line_items ADD CONSTRAINT payout_line_items_item_type_check
+    CHECK (item_type IN ('BOOKING', 'TIP'));
+
+ALTER TABLE payouts DROP CONSTRAINT IF EXISTS payouts_status_check;
+ALTER TABLE payouts ADD CONSTRAINT payouts_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'SENT', 'FAILED', 'CANCELLED'));
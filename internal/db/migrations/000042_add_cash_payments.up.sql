@@ -0,0 +1,17 @@
+-- Cash-on-site payments
+-- The cleaner collects the full price; the platform fee is netted against their next payout,
+-- or the cleaner is invoiced for it when the payout would be negative.
+
+COMMENT ON COLUMN payments.provider IS 'Payment gateway: NETOPIA, MANUAL, CASH (collected by the cleaner on site)';
+
+-- Payouts where the cleaner owes the platform more than it owes them
+ALTER TABLE payouts DROP CONSTRAINT IF EXISTS payouts_status_check;
+ALTER TABLE payouts ADD CONSTRAINT payouts_status_check
+    CHECK (status IN ('PENDING', 'PROCESSING', 'SENT', 'FAILED', 'CANCELLED', 'INVOICED'));
+
+-- Line items for bookings the cleaner was paid for in cash
+ALTER TABLE payout_line_items DROP CONSTRAINT IF EXISTS payout_line_items_item_type_check;
+ALTER TABLE payout_line_items ADD CONSTRAINT payout_line_items_item_type_check
+    CHECK (item_type IN ('BOOKING', 'TIP', 'CASH_BOOKING'));
+
+CREATE INDEX IF NOT EXISTS idx_payments_cash ON payments(booking_id) WHERE provider = 'CASH';
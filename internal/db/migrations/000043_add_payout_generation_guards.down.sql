@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_disputes_resolved_at;
+DROP INDEX IF EXISTS idx_payout_line_items_tip_unique;
+DROP INDEX IF EXISTS idx_payout_line_items_booking_unique;
@@ -0,0 +1,13 @@
+-- Guards that make monthly payout generation safe to re-run
+-- payouts already has UNIQUE (cleaner_id, period_start, period_end).
+-- A booking is paid out at most once, and its tip at most once.
+-- Fails if duplicate line items already exist; they must be cleaned up by hand first.
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_line_items_booking_unique
+    ON payout_line_items(booking_id) WHERE item_type <> 'TIP';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_line_items_tip_unique
+    ON payout_line_items(booking_id) WHERE item_type = 'TIP';
+
+-- Released disputes are looked up by resolution date when catching up held bookings
+CREATE INDEX IF NOT EXISTS idx_disputes_resolved_at ON disputes(resolved_at) WHERE resolved_at IS NOT NULL;
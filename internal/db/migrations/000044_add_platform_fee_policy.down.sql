@@ -0,0 +1,3 @@
+ALTER TABLE companies DROP COLUMN IF EXISTS platform_fee_percentage;
+ALTER TABLE bookings DROP COLUMN IF EXISTS platform_fee_rule;
+ALTER TABLE bookings DROP COLUMN IF EXISTS platform_fee_rate;
@@ -0,0 +1,19 @@
+-- Platform fee policy: the fee rate is decided once per booking and stored with it,
+-- so pricing, payouts and analytics all read the same split.
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS platform_fee_rate DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS platform_fee_rule VARCHAR(30) NOT NULL DEFAULT 'DEFAULT'
+    CHECK (platform_fee_rule IN ('DEFAULT', 'REPEAT_CLIENT', 'CLEANER_TIER', 'COMPANY_CONTRACT'));
+
+-- Backfill the rate from the split already stored on existing bookings
+UPDATE bookings
+SET platform_fee_rate = ROUND(platform_fee / total_price * 100, 2)
+WHERE total_price > 0;
+
+-- Contracted fee for cleaners employed by the company (NULL = standard policy)
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS platform_fee_percentage DECIMAL(5, 2)
+    CHECK (platform_fee_percentage IS NULL OR (platform_fee_percentage >= 0 AND platform_fee_percentage <= 100));
+
+COMMENT ON COLUMN bookings.platform_fee_rate IS 'Platform fee percentage applied to this booking';
+COMMENT ON COLUMN bookings.platform_fee_rule IS 'Fee policy rule that set the rate: DEFAULT, REPEAT_CLIENT, CLEANER_TIER, COMPANY_CONTRACT';
+COMMENT ON COLUMN companies.platform_fee_percentage IS 'Contracted platform fee percentage for the company''s cleaners';
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_payout_line_items_adjustment_unique;
+
+DELETE FROM payout_line_items WHERE item_type IN ('ADJUSTMENT', 'CARRY_FORWARD');
+
+DROP INDEX IF EXISTS idx_payout_line_items_booking_unique;
+CREATE UNIQUE INDEX idx_payout_line_items_booking_unique
+    ON payout_line_items(booking_id) WHERE item_type <> 'TIP';
+
+ALTER TABLE payout_line_items DROP CONSTRAINT IF EXISTS payout_line_items_item_type_check;
+ALTER TABLE payout_line_items ADD CONSTRAINT payout_line_items_item_type_check
+    CHECK (item_type IN ('BOOKING', 'TIP', 'CASH_BOOKING'));
+
+ALTER TABLE payout_line_items DROP COLUMN IF EXISTS description;
+ALTER TABLE payout_line_items DROP COLUMN IF EXISTS adjustment_id;
+ALTER TABLE payout_line_items ALTER COLUMN booking_id SET NOT NULL;
+
+DROP TABLE IF EXISTS payout_adjustments;
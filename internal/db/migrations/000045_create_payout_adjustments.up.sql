@@ -0,0 +1,44 @@
+-- Payout adjustments: clawbacks, bonuses and manual corrections to a cleaner's earnings.
+-- Each adjustment is paid out once, as a line item of the next payout run.
+CREATE TABLE IF NOT EXISTS payout_adjustments (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    cleaner_id TEXT NOT NULL REFERENCES cleaners(id),
+    adjustment_type VARCHAR(20) NOT NULL
+        CHECK (adjustment_type IN ('CLAWBACK', 'BONUS', 'CORRECTION', 'CARRY_FORWARD')),
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount <> 0),
+    reason TEXT NOT NULL,
+    booking_id TEXT REFERENCES bookings(id),
+    dispute_id TEXT REFERENCES disputes(id),
+    carried_from_payout_id TEXT REFERENCES payouts(id),
+    created_by TEXT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payout_adjustments_cleaner_id ON payout_adjustments(cleaner_id, created_at);
+CREATE INDEX idx_payout_adjustments_booking_id ON payout_adjustments(booking_id) WHERE booking_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_payout_adjustments_dispute_clawback
+    ON payout_adjustments(dispute_id) WHERE adjustment_type = 'CLAWBACK';
+CREATE UNIQUE INDEX idx_payout_adjustments_carry_forward
+    ON payout_adjustments(carried_from_payout_id) WHERE carried_from_payout_id IS NOT NULL;
+
+COMMENT ON TABLE payout_adjustments IS 'Changes to cleaner earnings outside bookings and tips, paid with the next payout';
+COMMENT ON COLUMN payout_adjustments.amount IS 'Signed amount: positive is owed to the cleaner, negative is taken back';
+COMMENT ON COLUMN payout_adjustments.created_by IS 'Admin who made the adjustment; NULL when created by the system';
+
+-- Adjustment line items may not relate to a booking
+ALTER TABLE payout_line_items ALTER COLUMN booking_id DROP NOT NULL;
+ALTER TABLE payout_line_items ADD COLUMN IF NOT EXISTS adjustment_id TEXT REFERENCES payout_adjustments(id);
+ALTER TABLE payout_line_items ADD COLUMN IF NOT EXISTS description TEXT;
+
+ALTER TABLE payout_line_items DROP CONSTRAINT IF EXISTS payout_line_items_item_type_check;
+ALTER TABLE payout_line_items ADD CONSTRAINT payout_line_items_item_type_check
+    CHECK (item_type IN ('BOOKING', 'TIP', 'CASH_BOOKING', 'ADJUSTMENT', 'CARRY_FORWARD'));
+
+-- Adjustment line items can share a booking with its booking line item
+DROP INDEX IF EXISTS idx_payout_line_items_booking_unique;
+CREATE UNIQUE INDEX idx_payout_line_items_booking_unique
+    ON payout_line_items(booking_id) WHERE item_type IN ('BOOKING', 'CASH_BOOKING');
+
+-- An adjustment is paid out at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_line_items_adjustment_unique
+    ON payout_line_items(adjustment_id) WHERE item_type = 'ADJUSTMENT';
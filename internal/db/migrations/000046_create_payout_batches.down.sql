@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payouts_batch_id;
+ALTER TABLE payouts DROP COLUMN IF EXISTS batch_id;
+
+DROP TABLE IF EXISTS payout_batches;
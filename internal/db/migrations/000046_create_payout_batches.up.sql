@@ -0,0 +1,25 @@
+-- Payout batches: bulk payment files (pain.001 or bank CSV) exported for PENDING payouts.
+-- Exported payouts move to PROCESSING and are marked SENT together once the bank confirms.
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    reference VARCHAR(35) NOT NULL UNIQUE,
+    file_format VARCHAR(20) NOT NULL CHECK (file_format IN ('PAIN001', 'BT_CSV', 'BCR_CSV')),
+    status VARCHAR(20) NOT NULL DEFAULT 'PROCESSING' CHECK (status IN ('PROCESSING', 'SENT')),
+    payout_count INTEGER NOT NULL,
+    total_amount DECIMAL(12, 2) NOT NULL,
+    file_name VARCHAR(100) NOT NULL,
+    file_content TEXT NOT NULL,
+    transfer_reference VARCHAR(255),
+    created_by TEXT REFERENCES users(id),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_payout_batches_created_at ON payout_batches(created_at DESC);
+
+COMMENT ON COLUMN payout_batches.reference IS 'Message ID of the payment file, quoted by the bank';
+COMMENT ON COLUMN payout_batches.file_content IS 'Exported file, kept so the same file can be downloaded again';
+
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS batch_id TEXT REFERENCES payout_batches(id);
+CREATE INDEX IF NOT EXISTS idx_payouts_batch_id ON payouts(batch_id) WHERE batch_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS self_billed_invoices;
+DROP TABLE IF EXISTS cleaner_self_billing;
@@ -0,0 +1,56 @@
+-- Self-billing (autofacturare): PFA cleaners opt in and the platform issues their invoice to
+-- CleanBuddy in their name when payouts are generated, in a numbering series of their own.
+CREATE TABLE IF NOT EXISTS cleaner_self_billing (
+    cleaner_id TEXT PRIMARY KEY REFERENCES cleaners(id),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    legal_name VARCHAR(255) NOT NULL,
+    cui VARCHAR(20) NOT NULL,
+    registration_number VARCHAR(50),
+    address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    county VARCHAR(100) NOT NULL,
+    vat_payer BOOLEAN NOT NULL DEFAULT FALSE,
+    series VARCHAR(20) NOT NULL UNIQUE,
+    last_number INTEGER NOT NULL DEFAULT 0,
+    agreed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN cleaner_self_billing.agreed_at IS 'When the cleaner accepted the self-billing agreement';
+COMMENT ON COLUMN cleaner_self_billing.last_number IS 'Last number used in the cleaner''s invoice series';
+
+CREATE TABLE IF NOT EXISTS self_billed_invoices (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    payout_id TEXT NOT NULL UNIQUE REFERENCES payouts(id),
+    cleaner_id TEXT NOT NULL REFERENCES cleaners(id),
+    invoice_number VARCHAR(50) NOT NULL UNIQUE,
+    issue_date DATE NOT NULL,
+    supplier_name VARCHAR(255) NOT NULL,
+    supplier_cui VARCHAR(20) NOT NULL,
+    supplier_registration_number VARCHAR(50),
+    supplier_address TEXT NOT NULL,
+    supplier_vat_payer BOOLEAN NOT NULL DEFAULT FALSE,
+    subtotal DECIMAL(10, 2) NOT NULL,
+    tax_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+    pdf_url TEXT,
+    xml_url TEXT,
+    anaf_upload_index VARCHAR(100),
+    anaf_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (anaf_status IN ('pending', 'processing', 'accepted', 'rejected', 'failed')),
+    anaf_submitted_at TIMESTAMP WITH TIME ZONE,
+    anaf_processed_at TIMESTAMP WITH TIME ZONE,
+    anaf_download_id VARCHAR(100),
+    anaf_errors JSONB,
+    anaf_retry_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_self_billed_invoices_cleaner_id ON self_billed_invoices(cleaner_id, issue_date DESC);
+CREATE INDEX idx_self_billed_invoices_anaf_status ON self_billed_invoices(anaf_status)
+    WHERE anaf_status IN ('pending', 'processing', 'failed');
+
+COMMENT ON TABLE self_billed_invoices IS 'Invoices issued by CleanBuddy on behalf of PFA cleaners (UBL type 389)';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payouts_commission_invoice_id;
+ALTER TABLE payouts DROP COLUMN IF EXISTS commission_invoice_id;
+DROP TABLE IF EXISTS commission_invoices;
+DROP SEQUENCE IF EXISTS commission_invoice_number_seq;
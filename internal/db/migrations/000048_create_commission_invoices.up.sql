@@ -0,0 +1,44 @@
+-- Commission invoices: the platform fee retained from payouts, invoiced monthly to each payout
+-- recipient. Cleaners working for a company are invoiced through the company.
+CREATE SEQUENCE IF NOT EXISTS commission_invoice_number_seq START 1;
+
+CREATE TABLE IF NOT EXISTS commission_invoices (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    invoice_number VARCHAR(50) NOT NULL UNIQUE,
+    recipient_type VARCHAR(20) NOT NULL CHECK (recipient_type IN ('CLEANER', 'COMPANY')),
+    cleaner_id TEXT REFERENCES cleaners(id),
+    company_id TEXT REFERENCES companies(id),
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    issue_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    customer_name VARCHAR(255) NOT NULL,
+    customer_cui VARCHAR(20),
+    customer_address TEXT,
+    customer_email VARCHAR(255),
+    subtotal DECIMAL(10, 2) NOT NULL,
+    vat_rate DECIMAL(5, 4) NOT NULL,
+    tax_amount DECIMAL(10, 2) NOT NULL,
+    total_amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+    pdf_url TEXT,
+    xml_url TEXT,
+    anaf_upload_index VARCHAR(100),
+    anaf_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (anaf_status IN ('pending', 'processing', 'accepted', 'rejected', 'failed')),
+    anaf_submitted_at TIMESTAMP WITH TIME ZONE,
+    anaf_processed_at TIMESTAMP WITH TIME ZONE,
+    anaf_download_id VARCHAR(100),
+    anaf_errors JSONB,
+    anaf_retry_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((recipient_type = 'CLEANER' AND cleaner_id IS NOT NULL) OR (recipient_type = 'COMPANY' AND company_id IS NOT NULL))
+);
+
+CREATE INDEX idx_commission_invoices_cleaner_id ON commission_invoices(cleaner_id, period_start DESC) WHERE cleaner_id IS NOT NULL;
+CREATE INDEX idx_commission_invoices_company_id ON commission_invoices(company_id, period_start DESC) WHERE company_id IS NOT NULL;
+
+-- Each payout's fees are invoiced once
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS commission_invoice_id TEXT REFERENCES commission_invoices(id);
+CREATE INDEX idx_payouts_commission_invoice_id ON payouts(commission_invoice_id) WHERE commission_invoice_id IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_payouts_company_payout_id;
+ALTER TABLE payouts DROP COLUMN IF EXISTS company_payout_id;
+DROP TABLE IF EXISTS company_payouts;
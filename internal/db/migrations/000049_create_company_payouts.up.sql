@@ -0,0 +1,28 @@
+-- Company payouts: the monthly earnings of a company's cleaners, paid in one transfer to the
+-- company IBAN. Each cleaner keeps their own payout (with line items) as the per-cleaner breakdown.
+CREATE TABLE IF NOT EXISTS company_payouts (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    company_id TEXT NOT NULL REFERENCES companies(id),
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'FAILED', 'INVOICED')),
+    cleaner_count INTEGER NOT NULL DEFAULT 0,
+    total_bookings INTEGER NOT NULL DEFAULT 0,
+    total_earnings DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    platform_fees DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    iban TEXT,
+    transfer_reference TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    failed_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_company_payouts_company_id ON company_payouts(company_id, period_start DESC);
+CREATE INDEX idx_company_payouts_status ON company_payouts(status);
+
+COMMENT ON COLUMN company_payouts.iban IS 'Encrypted company IBAN the payout was sent to';
+
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS company_payout_id TEXT REFERENCES company_payouts(id);
+CREATE INDEX IF NOT EXISTS idx_payouts_company_payout_id ON payouts(company_payout_id) WHERE company_payout_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS credit_notes;
+DROP SEQUENCE IF EXISTS credit_note_number_seq;
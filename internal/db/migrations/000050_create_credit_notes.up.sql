@@ -0,0 +1,37 @@
+-- Credit notes (facturi storno): corrective documents for refunded or cancelled invoice amounts.
+-- An invoice can be credited in full or in several partial credit notes, never beyond its total.
+CREATE SEQUENCE IF NOT EXISTS credit_note_number_seq START 1;
+
+CREATE TABLE IF NOT EXISTS credit_notes (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    credit_note_number VARCHAR(50) NOT NULL UNIQUE,
+    invoice_id TEXT NOT NULL REFERENCES invoices(id),
+    refund_payment_id TEXT UNIQUE REFERENCES payments(id),
+    credit_type VARCHAR(10) NOT NULL CHECK (credit_type IN ('FULL', 'PARTIAL')),
+    reason TEXT NOT NULL,
+    issue_date DATE NOT NULL,
+    client_name VARCHAR(255) NOT NULL,
+    client_email VARCHAR(255),
+    subtotal DECIMAL(10, 2) NOT NULL CHECK (subtotal >= 0),
+    tax_amount DECIMAL(10, 2) NOT NULL CHECK (tax_amount >= 0),
+    total_amount DECIMAL(10, 2) NOT NULL CHECK (total_amount > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+    pdf_url TEXT,
+    xml_url TEXT,
+    anaf_upload_index VARCHAR(100),
+    anaf_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (anaf_status IN ('pending', 'processing', 'accepted', 'rejected', 'failed')),
+    anaf_submitted_at TIMESTAMP WITH TIME ZONE,
+    anaf_processed_at TIMESTAMP WITH TIME ZONE,
+    anaf_download_id VARCHAR(100),
+    anaf_errors JSONB,
+    anaf_retry_count INTEGER NOT NULL DEFAULT 0,
+    created_by TEXT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_credit_notes_invoice_id ON credit_notes(invoice_id);
+CREATE INDEX idx_credit_notes_anaf_status ON credit_notes(anaf_status) WHERE anaf_status IN ('pending', 'failed');
+
+COMMENT ON COLUMN credit_notes.total_amount IS 'Amount credited, VAT included; shown negative on the document';
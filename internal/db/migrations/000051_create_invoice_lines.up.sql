@@ -0,0 +1,19 @@
+-- Itemized invoice lines (cleaning service, add-ons, supplies) with the VAT rate applied to each,
+-- so the PDF, the UBL XML and accounting exports all show the same per-line VAT breakdown.
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    invoice_id TEXT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    vat_category VARCHAR(20) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    unit_code VARCHAR(3) NOT NULL,
+    vat_rate DECIMAL(5, 4) NOT NULL CHECK (vat_rate >= 0),
+    net_amount DECIMAL(10, 2) NOT NULL,
+    vat_amount DECIMAL(10, 2) NOT NULL,
+    gross_amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (invoice_id, line_number)
+);
+
+COMMENT ON COLUMN invoice_lines.vat_category IS 'Line category the VAT rate was looked up by: service, addons or supplies';
@@ -0,0 +1,17 @@
+-- Alerts raised by the ANAF e-Factura worker for invoices that need an admin: close to the
+-- legal submission deadline, rejected by ANAF, or out of automatic retries. An invoice has at
+-- most one open alert of each type; alerts are resolved once ANAF accepts the invoice.
+CREATE TABLE IF NOT EXISTS anaf_alerts (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    invoice_id TEXT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    alert_type VARCHAR(30) NOT NULL
+        CHECK (alert_type IN ('DEADLINE_APPROACHING', 'REJECTED', 'RETRIES_EXHAUSTED')),
+    message TEXT NOT NULL,
+    deadline DATE NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by TEXT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_anaf_alerts_open ON anaf_alerts(invoice_id, alert_type) WHERE resolved_at IS NULL;
+CREATE INDEX idx_anaf_alerts_created_at ON anaf_alerts(created_at DESC);
@@ -0,0 +1,15 @@
+-- Archive of the signed responses ANAF returns for accepted invoices (a ZIP with the invoice
+-- XML and ANAF's signature), the legally valid e-invoice. Files are kept for the legal
+-- retention period; the checksum detects an archived file that has changed.
+CREATE TABLE IF NOT EXISTS anaf_confirmations (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    invoice_id TEXT NOT NULL UNIQUE REFERENCES invoices(id) ON DELETE RESTRICT,
+    download_id TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    retain_until DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_anaf_confirmations_retain_until ON anaf_confirmations(retain_until);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS invoice_billing_details;
+ALTER TABLE bookings DROP COLUMN IF EXISTS billing_profile_id;
+DROP TABLE IF EXISTS billing_profiles;
@@ -0,0 +1,40 @@
+-- Company billing profiles: clients booking for their company (office cleaning) are invoiced
+-- to the company, identified by its CUI, instead of as private persons.
+CREATE TABLE IF NOT EXISTS billing_profiles (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    legal_name VARCHAR(255) NOT NULL,
+    cui VARCHAR(10) NOT NULL, -- Digits only, without the RO prefix
+    registration_number VARCHAR(50), -- Trade register number, e.g. J40/1234/2020
+    vat_payer BOOLEAN NOT NULL DEFAULT FALSE,
+    street_address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    county VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(10),
+    country VARCHAR(2) NOT NULL DEFAULT 'RO',
+    email VARCHAR(255), -- Where the company wants its invoices
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_billing_profiles_user_id ON billing_profiles(user_id);
+
+-- Billing profile chosen at booking (NULL = invoice the client as a private person)
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS billing_profile_id TEXT REFERENCES billing_profiles(id) ON DELETE SET NULL;
+
+-- Company details as billed on an invoice, kept even if the profile changes later
+CREATE TABLE IF NOT EXISTS invoice_billing_details (
+    invoice_id TEXT PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+    legal_name VARCHAR(255) NOT NULL,
+    cui VARCHAR(10) NOT NULL,
+    registration_number VARCHAR(50),
+    vat_payer BOOLEAN NOT NULL,
+    street_address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    county VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(10),
+    country VARCHAR(2) NOT NULL
+);
+
+COMMENT ON COLUMN bookings.billing_profile_id IS 'Company billing profile invoiced for the booking, NULL for private clients';
@@ -0,0 +1,17 @@
+-- Restore the global sequences past every number the series allocated
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq START WITH 1000;
+CREATE SEQUENCE IF NOT EXISTS credit_note_number_seq START 1;
+CREATE SEQUENCE IF NOT EXISTS commission_invoice_number_seq START 1;
+
+SELECT setval(seq.name, GREATEST(seq.minimum, COALESCE(MAX(c.last_number), 0) + 1), FALSE)
+FROM (VALUES
+    ('invoice_number_seq', 'INVOICE', 1000),
+    ('credit_note_number_seq', 'CREDIT_NOTE', 1),
+    ('commission_invoice_number_seq', 'COMMISSION_INVOICE', 1)
+) AS seq(name, document_type, minimum)
+LEFT JOIN invoice_series s ON s.document_type = seq.document_type
+LEFT JOIN invoice_series_counters c ON c.series_id = s.id
+GROUP BY seq.name, seq.minimum;
+
+DROP TABLE IF EXISTS invoice_series_counters;
+DROP TABLE IF EXISTS invoice_series;
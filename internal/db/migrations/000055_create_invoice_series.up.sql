@@ -0,0 +1,49 @@
+-- Numbering series for the documents CleanBuddy issues. Each document type has one active
+-- series; numbers are allocated from invoice_series_counters in the transaction that stores the
+-- document, so a failed insert rolls the counter back and leaves no gap.
+CREATE TABLE IF NOT EXISTS invoice_series (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    document_type VARCHAR(30) NOT NULL CHECK (document_type IN ('INVOICE', 'CREDIT_NOTE', 'COMMISSION_INVOICE', 'PROFORMA')),
+    prefix VARCHAR(20) NOT NULL UNIQUE,
+    yearly_reset BOOLEAN NOT NULL DEFAULT TRUE, -- Numbers restart at 1 each year (PREFIX-YYYY-NNNN)
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoice_series_active ON invoice_series(document_type) WHERE is_active;
+
+-- Last number allocated per series and year; year is 0 for series that never reset
+CREATE TABLE IF NOT EXISTS invoice_series_counters (
+    series_id TEXT NOT NULL REFERENCES invoice_series(id) ON DELETE RESTRICT,
+    year INT NOT NULL,
+    last_number INT NOT NULL DEFAULT 0 CHECK (last_number >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (series_id, year)
+);
+
+-- The existing prefixes carry on, starting this year's counters after the numbers the global
+-- sequences already handed out so they cannot collide
+INSERT INTO invoice_series (document_type, prefix, yearly_reset, is_active) VALUES
+    ('INVOICE', 'INV', TRUE, TRUE),
+    ('CREDIT_NOTE', 'CN', TRUE, TRUE),
+    ('COMMISSION_INVOICE', 'COM', TRUE, TRUE),
+    ('PROFORMA', 'PRO', TRUE, TRUE)
+ON CONFLICT (prefix) DO NOTHING;
+
+INSERT INTO invoice_series_counters (series_id, year, last_number)
+SELECT s.id, EXTRACT(YEAR FROM NOW())::int, seq.last_value
+FROM invoice_series s
+JOIN (
+    SELECT 'INVOICE' AS document_type, CASE WHEN is_called THEN last_value ELSE 0 END AS last_value FROM invoice_number_seq
+    UNION ALL
+    SELECT 'CREDIT_NOTE', CASE WHEN is_called THEN last_value ELSE 0 END FROM credit_note_number_seq
+    UNION ALL
+    SELECT 'COMMISSION_INVOICE', CASE WHEN is_called THEN last_value ELSE 0 END FROM commission_invoice_number_seq
+) seq ON seq.document_type = s.document_type
+ON CONFLICT (series_id, year) DO NOTHING;
+
+DROP SEQUENCE IF EXISTS invoice_number_seq;
+DROP SEQUENCE IF EXISTS credit_note_number_seq;
+DROP SEQUENCE IF EXISTS commission_invoice_number_seq;
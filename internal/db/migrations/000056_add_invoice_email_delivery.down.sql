@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_invoices_email_status;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS signed_copy_sent_at,
+    DROP COLUMN IF EXISTS email_last_error,
+    DROP COLUMN IF EXISTS email_send_count,
+    DROP COLUMN IF EXISTS email_sent_at,
+    DROP COLUMN IF EXISTS email_status;
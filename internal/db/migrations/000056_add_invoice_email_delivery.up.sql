@@ -0,0 +1,11 @@
+-- Delivery of invoices to clients by email: the issued invoice right after creation, then the
+-- copy validated by ANAF once its signed response is archived
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS email_status VARCHAR(20) NOT NULL DEFAULT 'NOT_SENT'
+        CHECK (email_status IN ('NOT_SENT', 'SENT', 'FAILED')),
+    ADD COLUMN IF NOT EXISTS email_sent_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS email_send_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS email_last_error TEXT,
+    ADD COLUMN IF NOT EXISTS signed_copy_sent_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_invoices_email_status ON invoices(email_status) WHERE email_status = 'FAILED';
@@ -0,0 +1,5 @@
+ALTER TABLE bank_statement_lines DROP COLUMN IF EXISTS matched_proforma_id;
+
+COMMENT ON COLUMN payments.provider IS 'Payment gateway: NETOPIA, MANUAL, CASH (collected by the cleaner on site)';
+
+DROP TABLE IF EXISTS proformas;
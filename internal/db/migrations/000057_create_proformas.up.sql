@@ -0,0 +1,42 @@
+-- Proformas: payment requests for company bookings paid by bank transfer. Issued when the
+-- booking is confirmed, settled when the transfer arrives, converted into the fiscal invoice
+-- when the booking is completed. A proforma is not a fiscal document and is never sent to ANAF.
+CREATE TABLE IF NOT EXISTS proformas (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    proforma_number VARCHAR(50) NOT NULL UNIQUE,
+    booking_id TEXT NOT NULL REFERENCES bookings(id),
+    payment_id TEXT NOT NULL REFERENCES payments(id),
+    billing_profile_id TEXT REFERENCES billing_profiles(id) ON DELETE SET NULL,
+    issue_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    client_name VARCHAR(255) NOT NULL,
+    client_email VARCHAR(255),
+    subtotal DECIMAL(10, 2) NOT NULL,
+    tax_amount DECIMAL(10, 2) NOT NULL,
+    total_amount DECIMAL(10, 2) NOT NULL,
+    amount_due DECIMAL(10, 2) NOT NULL CHECK (amount_due > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RON',
+    status VARCHAR(20) NOT NULL DEFAULT 'ISSUED' CHECK (status IN ('ISSUED', 'PAID', 'CANCELLED')),
+    pdf_url TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    payment_reference VARCHAR(255),
+    invoice_id TEXT REFERENCES invoices(id),
+    converted_at TIMESTAMP WITH TIME ZONE,
+    reminder_count INT NOT NULL DEFAULT 0,
+    last_reminder_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One open or paid proforma per booking; a cancelled one can be reissued
+CREATE UNIQUE INDEX idx_proformas_booking_id ON proformas(booking_id) WHERE status <> 'CANCELLED';
+CREATE INDEX idx_proformas_status_due ON proformas(due_date) WHERE status = 'ISSUED';
+
+COMMENT ON COLUMN proformas.total_amount IS 'Booking price, VAT included, as it will be invoiced';
+COMMENT ON COLUMN proformas.amount_due IS 'Amount to transfer: the total less wallet credit applied to the booking';
+
+COMMENT ON COLUMN payments.provider IS 'Payment gateway: NETOPIA, MANUAL, CASH (collected by the cleaner on site), BANK_TRANSFER (against a proforma)';
+
+-- Bank statement lines can settle a proforma
+ALTER TABLE bank_statement_lines
+    ADD COLUMN IF NOT EXISTS matched_proforma_id TEXT REFERENCES proformas(id);
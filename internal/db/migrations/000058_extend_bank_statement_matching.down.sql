@@ -0,0 +1,3 @@
+ALTER TABLE bank_statement_lines DROP COLUMN IF EXISTS reversal;
+
+ALTER TABLE bank_statement_lines DROP COLUMN IF EXISTS matched_company_payout_id;
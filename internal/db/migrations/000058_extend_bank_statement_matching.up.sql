@@ -0,0 +1,8 @@
+-- Bank statement lines can settle a company payout (paid to, or invoiced to, a cleaners' company)
+ALTER TABLE bank_statement_lines
+    ADD COLUMN IF NOT EXISTS matched_company_payout_id TEXT REFERENCES company_payouts(id) ON DELETE SET NULL;
+
+-- MT940 RD/RC entries reverse an earlier transfer (e.g. a payout returned by the beneficiary's bank).
+-- They stay in the reconciliation queue, linked to the payout they reverse.
+ALTER TABLE bank_statement_lines
+    ADD COLUMN IF NOT EXISTS reversal BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- Which credit lots paid for a booking, so credit returned on cancellation keeps the expiry
+-- of the lots it came from
+
+CREATE TABLE IF NOT EXISTS wallet_credit_allocations (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    booking_id TEXT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    lot_id TEXT NOT NULL REFERENCES wallet_transactions(id) ON DELETE CASCADE,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_wallet_credit_allocations_booking ON wallet_credit_allocations(booking_id);
@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS self_billing_series_seq;
@@ -0,0 +1,4 @@
+-- Self-billing series are numbered from a sequence (AFX00001, AFX00002, ...) instead of being
+-- derived from the cleaner ID, whose prefixes can collide. Series already handed out are AF plus
+-- hex digits, so the X keeps the new ones from clashing with them.
+CREATE SEQUENCE IF NOT EXISTS self_billing_series_seq START 1;
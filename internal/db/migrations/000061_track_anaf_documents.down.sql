@@ -0,0 +1,21 @@
+DELETE FROM anaf_confirmations WHERE invoice_id IS NULL;
+ALTER TABLE anaf_confirmations
+    DROP CONSTRAINT IF EXISTS anaf_confirmations_one_document,
+    DROP COLUMN IF EXISTS self_billed_invoice_id,
+    DROP COLUMN IF EXISTS commission_invoice_id,
+    DROP COLUMN IF EXISTS credit_note_id,
+    ALTER COLUMN invoice_id SET NOT NULL;
+
+DELETE FROM anaf_alerts WHERE invoice_id IS NULL;
+DROP INDEX IF EXISTS idx_anaf_alerts_open;
+ALTER TABLE anaf_alerts
+    DROP CONSTRAINT IF EXISTS anaf_alerts_one_document,
+    DROP COLUMN IF EXISTS self_billed_invoice_id,
+    DROP COLUMN IF EXISTS commission_invoice_id,
+    DROP COLUMN IF EXISTS credit_note_id,
+    ALTER COLUMN invoice_id SET NOT NULL;
+CREATE UNIQUE INDEX idx_anaf_alerts_open ON anaf_alerts(invoice_id, alert_type) WHERE resolved_at IS NULL;
+
+ALTER TABLE self_billed_invoices DROP COLUMN IF EXISTS anaf_last_retry_at;
+ALTER TABLE commission_invoices DROP COLUMN IF EXISTS anaf_last_retry_at;
+ALTER TABLE credit_notes DROP COLUMN IF EXISTS anaf_last_retry_at;
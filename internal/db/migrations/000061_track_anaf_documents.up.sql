@@ -0,0 +1,29 @@
+-- The ANAF worker files credit notes, commission invoices and self-billed invoices as well as
+-- client invoices: they get the same retry backoff, and their alerts and archived signed
+-- responses reference whichever document they belong to.
+ALTER TABLE credit_notes ADD COLUMN IF NOT EXISTS anaf_last_retry_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE commission_invoices ADD COLUMN IF NOT EXISTS anaf_last_retry_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE self_billed_invoices ADD COLUMN IF NOT EXISTS anaf_last_retry_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE anaf_alerts
+    ALTER COLUMN invoice_id DROP NOT NULL,
+    ADD COLUMN credit_note_id TEXT REFERENCES credit_notes(id) ON DELETE CASCADE,
+    ADD COLUMN commission_invoice_id TEXT REFERENCES commission_invoices(id) ON DELETE CASCADE,
+    ADD COLUMN self_billed_invoice_id TEXT REFERENCES self_billed_invoices(id) ON DELETE CASCADE,
+    ADD CONSTRAINT anaf_alerts_one_document CHECK (
+        num_nonnulls(invoice_id, credit_note_id, commission_invoice_id, self_billed_invoice_id) = 1
+    );
+
+DROP INDEX IF EXISTS idx_anaf_alerts_open;
+CREATE UNIQUE INDEX idx_anaf_alerts_open
+    ON anaf_alerts((COALESCE(invoice_id, credit_note_id, commission_invoice_id, self_billed_invoice_id)), alert_type)
+    WHERE resolved_at IS NULL;
+
+ALTER TABLE anaf_confirmations
+    ALTER COLUMN invoice_id DROP NOT NULL,
+    ADD COLUMN credit_note_id TEXT UNIQUE REFERENCES credit_notes(id) ON DELETE RESTRICT,
+    ADD COLUMN commission_invoice_id TEXT UNIQUE REFERENCES commission_invoices(id) ON DELETE RESTRICT,
+    ADD COLUMN self_billed_invoice_id TEXT UNIQUE REFERENCES self_billed_invoices(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT anaf_confirmations_one_document CHECK (
+        num_nonnulls(invoice_id, credit_note_id, commission_invoice_id, self_billed_invoice_id) = 1
+    );
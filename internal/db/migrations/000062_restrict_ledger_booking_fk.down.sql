@@ -0,0 +1,4 @@
+ALTER TABLE ledger_transactions
+    DROP CONSTRAINT IF EXISTS ledger_transactions_booking_id_fkey,
+    ADD CONSTRAINT ledger_transactions_booking_id_fkey
+        FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- The ledger is append-only, so deleting a booking must not try to null out its postings:
+-- ON DELETE SET NULL would fire the append-only trigger. Refuse the delete instead.
+ALTER TABLE ledger_transactions
+    DROP CONSTRAINT IF EXISTS ledger_transactions_booking_id_fkey,
+    ADD CONSTRAINT ledger_transactions_booking_id_fkey
+        FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE RESTRICT;
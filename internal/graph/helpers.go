@@ -2,10 +2,13 @@ package graph
 
 import (
 	"log"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/cleanbuddy/backend/internal/graph/model"
 	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/services"
 	"github.com/cleanbuddy/backend/internal/utils"
 )
 
@@ -94,6 +97,55 @@ func convertAddressToGraphQL(address *models.Address) *model.Address {
 	}
 }
 
+// convertBillingProfileToGraphQL converts database billing profile model to GraphQL model
+func convertBillingProfileToGraphQL(profile *models.BillingProfile) *model.BillingProfile {
+	result := &model.BillingProfile{
+		ID:            profile.ID,
+		UserID:        profile.UserID,
+		LegalName:     profile.LegalName,
+		Cui:           profile.CUI,
+		VatPayer:      profile.VATPayer,
+		StreetAddress: profile.StreetAddress,
+		City:          profile.City,
+		County:        profile.County,
+		Country:       profile.Country,
+		IsDefault:     profile.IsDefault,
+		CreatedAt:     profile.CreatedAt,
+		UpdatedAt:     profile.UpdatedAt,
+	}
+	if profile.RegistrationNumber.Valid {
+		result.RegistrationNumber = &profile.RegistrationNumber.String
+	}
+	if profile.PostalCode.Valid {
+		result.PostalCode = &profile.PostalCode.String
+	}
+	if profile.Email.Valid {
+		result.Email = &profile.Email.String
+	}
+	return result
+}
+
+// convertCompanyRegistryInfoToGraphQL converts a VAT registry lookup result to GraphQL model
+func convertCompanyRegistryInfoToGraphQL(info *services.CompanyRegistryInfo) *model.CompanyRegistryInfo {
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	return &model.CompanyRegistryInfo{
+		Cui:                info.CUI,
+		LegalName:          info.LegalName,
+		RegistrationNumber: optional(info.RegistrationNumber),
+		VatPayer:           info.VATPayer,
+		Active:             info.Active,
+		StreetAddress:      optional(info.StreetAddress),
+		City:               optional(info.City),
+		County:             optional(info.County),
+		PostalCode:         optional(info.PostalCode),
+	}
+}
+
 // convertCleanerToGraphQL converts database cleaner model to GraphQL model
 func convertCleanerToGraphQL(cleaner *models.Cleaner) *model.Cleaner {
 	var dateOfBirth, streetAddress, city, county, postalCode, bio *string
@@ -195,6 +247,8 @@ func convertBookingToGraphQL(booking *models.Booking) *model.Booking {
 	var scheduledDate, scheduledTime *time.Time
 	var confirmedAt, startedAt, completedAt, cancelledAt *time.Time
 	var cancelledBy, cancellationReason *string
+	var refundStatus *model.RefundStatus
+	var refundProcessedAt *time.Time
 	var clientRating, cleanerRating *int
 	var clientReview, cleanerReview *string
 	var areaSqm *int
@@ -205,6 +259,10 @@ func convertBookingToGraphQL(booking *models.Booking) *model.Booking {
 	if booking.ReservationCode.Valid {
 		reservationCode = &booking.ReservationCode.String
 	}
+	var billingProfileID *string
+	if booking.BillingProfileID.Valid {
+		billingProfileID = &booking.BillingProfileID.String
+	}
 	if booking.SpecialInstructions.Valid {
 		specialInstructions = &booking.SpecialInstructions.String
 	}
@@ -238,6 +296,13 @@ func convertBookingToGraphQL(booking *models.Booking) *model.Booking {
 	if booking.CancellationReason.Valid {
 		cancellationReason = &booking.CancellationReason.String
 	}
+	if booking.RefundStatus.Valid {
+		status := model.RefundStatus(booking.RefundStatus.String)
+		refundStatus = &status
+	}
+	if booking.RefundProcessedAt.Valid {
+		refundProcessedAt = &booking.RefundProcessedAt.Time
+	}
 	if booking.ClientRating.Valid {
 		rating := int(booking.ClientRating.Int32)
 		clientRating = &rating
@@ -281,8 +346,13 @@ func convertBookingToGraphQL(booking *models.Booking) *model.Booking {
 		AddonsPrice:            booking.AddonsPrice,
 		TotalPrice:             booking.TotalPrice,
 		PlatformFee:            booking.PlatformFee,
+		PlatformFeeRate:        booking.PlatformFeeRate,
+		PlatformFeeRule:        model.PlatformFeeRule(booking.PlatformFeeRule),
 		CleanerPayout:          booking.CleanerPayout,
 		DiscountApplied:        booking.DiscountApplied,
+		CreditApplied:          booking.CreditApplied,
+		BillingProfileID:       billingProfileID,
+		AmountDue:              math.Round(booking.AmountDue()*100) / 100,
 		Status:                 model.BookingStatus(booking.Status),
 		SpecialInstructions:    specialInstructions,
 		AccessInstructions:     accessInstructions,
@@ -292,6 +362,9 @@ func convertBookingToGraphQL(booking *models.Booking) *model.Booking {
 		CancelledAt:            cancelledAt,
 		CancelledBy:            cancelledBy,
 		CancellationReason:     cancellationReason,
+		RefundStatus:           refundStatus,
+		RefundAmount:           booking.RefundAmount,
+		RefundProcessedAt:      refundProcessedAt,
 		ClientRating:           clientRating,
 		ClientReview:           clientReview,
 		CleanerRating:          cleanerRating,
@@ -401,6 +474,7 @@ func convertCompanyToGraphQL(company *models.Company) *model.Company {
 	var idDocumentURL, registrationDocumentURL *string
 	var approvedBy *string
 	var approvedAt *time.Time
+	var platformFeePercentage *float64
 
 	if company.RegistrationNumber.Valid {
 		registrationNumber = &company.RegistrationNumber.String
@@ -442,6 +516,9 @@ func convertCompanyToGraphQL(company *models.Company) *model.Company {
 	if company.ApprovedAt.Valid {
 		approvedAt = &company.ApprovedAt.Time
 	}
+	if company.PlatformFeePercentage.Valid {
+		platformFeePercentage = &company.PlatformFeePercentage.Float64
+	}
 
 	return &model.Company{
 		ID:                          company.ID,
@@ -462,6 +539,7 @@ func convertCompanyToGraphQL(company *models.Company) *model.Company {
 		ApprovedBy:                  approvedBy,
 		ApprovedAt:                  approvedAt,
 		IsActive:                    company.IsActive,
+		PlatformFeePercentage:       platformFeePercentage,
 		CreatedAt:                   company.CreatedAt,
 		UpdatedAt:                   company.UpdatedAt,
 	}
@@ -572,6 +650,23 @@ func convertInvoiceToGraphQL(invoice *models.Invoice) *model.Invoice {
 		anafLastRetryAt = &invoice.ANAFLastRetryAt.Time
 	}
 
+	// Email delivery fields; invoices loaded without them count as not sent
+	emailStatus := model.InvoiceEmailStatusNotSent
+	if invoice.EmailStatus != "" {
+		emailStatus = model.InvoiceEmailStatus(invoice.EmailStatus)
+	}
+	var emailSentAt, signedCopySentAt *time.Time
+	var emailLastError *string
+	if invoice.EmailSentAt.Valid {
+		emailSentAt = &invoice.EmailSentAt.Time
+	}
+	if invoice.SignedCopySentAt.Valid {
+		signedCopySentAt = &invoice.SignedCopySentAt.Time
+	}
+	if invoice.EmailLastError.Valid {
+		emailLastError = &invoice.EmailLastError.String
+	}
+
 	// Convert ANAF errors
 	var anafErrors []*model.ANAFError
 	if len(invoice.ANAFErrors) > 0 {
@@ -619,6 +714,13 @@ func convertInvoiceToGraphQL(invoice *models.Invoice) *model.Invoice {
 		AnafErrors:          anafErrors,
 		AnafRetryCount:      invoice.ANAFRetryCount,
 		AnafLastRetryAt:     anafLastRetryAt,
+
+		// Email delivery
+		EmailStatus:      emailStatus,
+		EmailSentAt:      emailSentAt,
+		EmailSendCount:   invoice.EmailSendCount,
+		EmailLastError:   emailLastError,
+		SignedCopySentAt: signedCopySentAt,
 	}
 }
 
@@ -726,7 +828,7 @@ func convertPhotoToGraphQL(photo *models.Photo) *model.Photo {
 
 // convertPayoutToGraphQL converts database payout model to GraphQL model
 func convertPayoutToGraphQL(payout *models.Payout) *model.Payout {
-	var iban, transferRef, invoiceURL, failedReason *string
+	var iban, transferRef, invoiceURL, failedReason, companyPayoutID *string
 	var paidAt *time.Time
 
 	if payout.IBAN.Valid {
@@ -744,6 +846,9 @@ func convertPayoutToGraphQL(payout *models.Payout) *model.Payout {
 	if payout.FailedReason.Valid {
 		failedReason = &payout.FailedReason.String
 	}
+	if payout.CompanyPayoutID.Valid {
+		companyPayoutID = &payout.CompanyPayoutID.String
+	}
 
 	return &model.Payout{
 		ID:                   payout.ID,
@@ -763,6 +868,7 @@ func convertPayoutToGraphQL(payout *models.Payout) *model.Payout {
 		CreatedAt:            payout.CreatedAt,
 		UpdatedAt:            payout.UpdatedAt,
 		LineItems:            []*model.PayoutLineItem{}, // Will be loaded via field resolver
+		CompanyPayoutID:      companyPayoutID,
 	}
 }
 
@@ -778,12 +884,64 @@ func convertPayoutToGraphQLWithLineItems(payout *models.Payout, lineItems []*mod
 	return result
 }
 
+// convertCompanyPayoutToGraphQL converts a company payout and its per-cleaner breakdown to GraphQL model
+func convertCompanyPayoutToGraphQL(companyPayout *models.CompanyPayout, breakdown []*models.PayoutDraft) *model.CompanyPayout {
+	result := &model.CompanyPayout{
+		ID:            companyPayout.ID,
+		CompanyID:     companyPayout.CompanyID,
+		PeriodStart:   companyPayout.PeriodStart,
+		PeriodEnd:     companyPayout.PeriodEnd,
+		Status:        model.PayoutStatus(companyPayout.Status),
+		CleanerCount:  companyPayout.CleanerCount,
+		TotalBookings: companyPayout.TotalBookings,
+		TotalEarnings: companyPayout.TotalEarnings,
+		PlatformFees:  companyPayout.PlatformFees,
+		NetAmount:     companyPayout.NetAmount,
+		CreatedAt:     companyPayout.CreatedAt,
+		UpdatedAt:     companyPayout.UpdatedAt,
+		Payouts:       make([]*model.Payout, len(breakdown)),
+	}
+	if companyPayout.TransferReference.Valid {
+		result.TransferReference = &companyPayout.TransferReference.String
+	}
+	if companyPayout.PaidAt.Valid {
+		result.PaidAt = &companyPayout.PaidAt.Time
+	}
+	if companyPayout.FailedReason.Valid {
+		result.FailedReason = &companyPayout.FailedReason.String
+	}
+	for i, item := range breakdown {
+		result.Payouts[i] = convertPayoutToGraphQLWithLineItems(item.Payout, item.LineItems)
+	}
+	return result
+}
+
+// convertPayoutRunToGraphQL converts a payout run summary to GraphQL model
+func convertPayoutRunToGraphQL(run *services.PayoutRun) *model.PayoutRunSummary {
+	payouts := make([]*model.Payout, len(run.Drafts))
+	for i, draft := range run.Drafts {
+		payouts[i] = convertPayoutToGraphQLWithLineItems(draft.Payout, draft.LineItems)
+	}
+
+	return &model.PayoutRunSummary{
+		PeriodStart:     run.PeriodStart,
+		PeriodEnd:       run.PeriodEnd,
+		DryRun:          run.DryRun,
+		Payouts:         payouts,
+		SkippedCleaners: run.SkippedCleaners,
+		HeldBookings:    run.HeldBookings,
+		TotalEarnings:   run.TotalEarnings,
+		PlatformFees:    run.PlatformFees,
+		NetAmount:       run.NetAmount,
+	}
+}
+
 // convertPayoutLineItemToGraphQL converts line item to GraphQL model
 func convertPayoutLineItemToGraphQL(item *models.PayoutLineItem) *model.PayoutLineItem {
-	return &model.PayoutLineItem{
+	result := &model.PayoutLineItem{
 		ID:              item.ID,
 		PayoutID:        item.PayoutID,
-		BookingID:       item.BookingID,
+		ItemType:        model.PayoutLineItemType(item.ItemType),
 		BookingDate:     item.BookingDate,
 		ServiceType:     item.ServiceType,
 		BookingAmount:   item.BookingAmount,
@@ -792,6 +950,217 @@ func convertPayoutLineItemToGraphQL(item *models.PayoutLineItem) *model.PayoutLi
 		CleanerEarnings: item.CleanerEarnings,
 		CreatedAt:       item.CreatedAt,
 	}
+
+	if item.BookingID != "" {
+		result.BookingID = &item.BookingID
+	}
+	if item.AdjustmentID.Valid {
+		result.AdjustmentID = &item.AdjustmentID.String
+	}
+	if item.Description.Valid {
+		result.Description = &item.Description.String
+	}
+
+	return result
+}
+
+// convertPayoutAdjustmentToGraphQL converts a payout adjustment to GraphQL model
+func convertPayoutAdjustmentToGraphQL(adjustment *models.PayoutAdjustment) *model.PayoutAdjustment {
+	result := &model.PayoutAdjustment{
+		ID:        adjustment.ID,
+		CleanerID: adjustment.CleanerID,
+		Type:      model.PayoutAdjustmentType(adjustment.Type),
+		Amount:    adjustment.Amount,
+		Reason:    adjustment.Reason,
+		CreatedAt: adjustment.CreatedAt,
+	}
+
+	if adjustment.BookingID.Valid {
+		result.BookingID = &adjustment.BookingID.String
+	}
+	if adjustment.DisputeID.Valid {
+		result.DisputeID = &adjustment.DisputeID.String
+	}
+	if adjustment.CarriedFromPayoutID.Valid {
+		result.CarriedFromPayoutID = &adjustment.CarriedFromPayoutID.String
+	}
+	if adjustment.CreatedBy.Valid {
+		result.CreatedBy = &adjustment.CreatedBy.String
+	}
+	if adjustment.PayoutID.Valid {
+		result.PayoutID = &adjustment.PayoutID.String
+	}
+
+	return result
+}
+
+// convertPayoutBatchToGraphQL converts a payout batch and its payouts to GraphQL model
+func convertPayoutBatchToGraphQL(batch *models.PayoutBatch, payouts []*models.Payout) *model.PayoutBatch {
+	_, contentType := utils.PaymentFileName(batch.Reference, batch.Format)
+	result := &model.PayoutBatch{
+		ID:          batch.ID,
+		Reference:   batch.Reference,
+		Format:      model.PaymentFileFormat(batch.Format),
+		Status:      model.PayoutBatchStatus(batch.Status),
+		PayoutCount: batch.PayoutCount,
+		TotalAmount: batch.TotalAmount,
+		FileName:    batch.FileName,
+		ContentType: contentType,
+		FileContent: batch.FileContent,
+		CreatedAt:   batch.CreatedAt,
+		Payouts:     make([]*model.Payout, len(payouts)),
+	}
+
+	if batch.TransferReference.Valid {
+		result.TransferReference = &batch.TransferReference.String
+	}
+	if batch.CreatedBy.Valid {
+		result.CreatedBy = &batch.CreatedBy.String
+	}
+	if batch.SentAt.Valid {
+		result.SentAt = &batch.SentAt.Time
+	}
+	for i, payout := range payouts {
+		result.Payouts[i] = convertPayoutToGraphQL(payout)
+	}
+
+	return result
+}
+
+// convertTrialBalanceToGraphQL converts ledger trial balance to GraphQL model
+func convertTrialBalanceToGraphQL(balance *services.TrialBalance) *model.TrialBalance {
+	accounts := make([]*model.LedgerAccountBalance, len(balance.Accounts))
+	for i, account := range balance.Accounts {
+		accounts[i] = &model.LedgerAccountBalance{
+			Account:     model.LedgerAccount(account.Account),
+			TotalDebit:  account.TotalDebit,
+			TotalCredit: account.TotalCredit,
+			Balance:     math.Round((account.TotalDebit-account.TotalCredit)*100) / 100,
+		}
+	}
+
+	return &model.TrialBalance{
+		AsOf:        balance.AsOf,
+		Accounts:    accounts,
+		TotalDebit:  balance.TotalDebit,
+		TotalCredit: balance.TotalCredit,
+		Balanced:    balance.TotalDebit == balance.TotalCredit,
+	}
+}
+
+// convertWalletToGraphQL converts a client wallet to GraphQL model
+func convertWalletToGraphQL(wallet *services.Wallet) *model.Wallet {
+	transactions := make([]*model.WalletTransaction, len(wallet.Transactions))
+	for i, txn := range wallet.Transactions {
+		transactions[i] = convertWalletTransactionToGraphQL(txn)
+	}
+
+	return &model.Wallet{
+		Balance:      wallet.Balance,
+		Currency:     "RON",
+		Transactions: transactions,
+	}
+}
+
+// convertTipToGraphQL converts tip model to GraphQL model
+func convertTipToGraphQL(tip *models.Tip) *model.Tip {
+	return &model.Tip{
+		ID:        tip.ID,
+		BookingID: tip.BookingID,
+		CleanerID: tip.CleanerID,
+		PaymentID: tip.PaymentID,
+		Amount:    tip.Amount,
+		Currency:  tip.Currency,
+		CreatedAt: tip.CreatedAt,
+	}
+}
+
+// convertWalletTransactionToGraphQL converts a wallet transaction to GraphQL model
+func convertWalletTransactionToGraphQL(txn *models.WalletTransaction) *model.WalletTransaction {
+	result := &model.WalletTransaction{
+		ID:              txn.ID,
+		Amount:          txn.Amount,
+		Source:          model.WalletTransactionSource(txn.Source),
+		RemainingAmount: txn.RemainingAmount,
+		CreatedAt:       txn.CreatedAt,
+	}
+
+	if txn.Reason.Valid {
+		result.Reason = &txn.Reason.String
+	}
+	if txn.ExpiresAt.Valid {
+		result.ExpiresAt = &txn.ExpiresAt.Time
+	}
+	if txn.BookingID.Valid {
+		result.BookingID = &txn.BookingID.String
+	}
+	if txn.DisputeID.Valid {
+		result.DisputeID = &txn.DisputeID.String
+	}
+
+	return result
+}
+
+// convertBankStatementImportToGraphQL converts a bank statement import to GraphQL model
+func convertBankStatementImportToGraphQL(imp *models.BankStatementImport) *model.BankStatementImport {
+	return &model.BankStatementImport{
+		ID:             imp.ID,
+		FileName:       imp.FileName,
+		Format:         model.BankStatementFormat(imp.Format),
+		LineCount:      imp.LineCount,
+		MatchedCount:   imp.MatchedCount,
+		DuplicateCount: imp.DuplicateCount,
+		UnmatchedCount: imp.LineCount - imp.MatchedCount,
+		CreatedAt:      imp.CreatedAt,
+	}
+}
+
+// convertBankStatementLineToGraphQL converts a bank statement line to GraphQL model
+func convertBankStatementLineToGraphQL(line *models.BankStatementLine) *model.BankStatementLine {
+	result := &model.BankStatementLine{
+		ID:          line.ID,
+		ImportID:    line.ImportID,
+		BookingDate: line.BookingDate,
+		Direction:   model.BankTransactionDirection(line.Direction),
+		Amount:      line.Amount,
+		Currency:    line.Currency,
+		Reversal:    line.Reversal,
+		MatchStatus: model.BankLineMatchStatus(line.MatchStatus),
+		CreatedAt:   line.CreatedAt,
+	}
+
+	if line.CounterpartyName.Valid {
+		result.CounterpartyName = &line.CounterpartyName.String
+	}
+	if line.CounterpartyIBAN.Valid {
+		result.CounterpartyIban = &line.CounterpartyIBAN.String
+	}
+	if line.Reference.Valid {
+		result.Reference = &line.Reference.String
+	}
+	if line.BankReference.Valid {
+		result.BankReference = &line.BankReference.String
+	}
+	if line.MatchedPayoutID.Valid {
+		result.MatchedPayoutID = &line.MatchedPayoutID.String
+	}
+	if line.MatchedCompanyPayoutID.Valid {
+		result.MatchedCompanyPayoutID = &line.MatchedCompanyPayoutID.String
+	}
+	if line.MatchedInvoiceID.Valid {
+		result.MatchedInvoiceID = &line.MatchedInvoiceID.String
+	}
+	if line.MatchedProformaID.Valid {
+		result.MatchedProformaID = &line.MatchedProformaID.String
+	}
+	if line.ResolvedAt.Valid {
+		result.ResolvedAt = &line.ResolvedAt.Time
+	}
+	if line.Notes.Valid {
+		result.Notes = &line.Notes.String
+	}
+
+	return result
 }
 
 // convertPlatformSettingsToGraphQL converts database platform settings to GraphQL model
@@ -851,3 +1220,224 @@ func convertCleanerStatsToGraphQL(stats *models.CleanerStats) *model.CleanerStat
 		LastActiveDate:    lastActiveDate,
 	}
 }
+
+// convertCleanerSelfBillingToGraphQL converts self-billing settings to GraphQL model
+func convertCleanerSelfBillingToGraphQL(settings *models.CleanerSelfBilling) *model.CleanerSelfBilling {
+	result := &model.CleanerSelfBilling{
+		Enabled:   settings.Enabled,
+		LegalName: settings.LegalName,
+		Cui:       settings.CUI,
+		Address:   settings.Address,
+		City:      settings.City,
+		County:    settings.County,
+		VatPayer:  settings.VATPayer,
+		Series:    settings.Series,
+		AgreedAt:  settings.AgreedAt,
+	}
+	if settings.RegistrationNumber.Valid {
+		result.RegistrationNumber = &settings.RegistrationNumber.String
+	}
+	return result
+}
+
+// convertSelfBilledInvoiceToGraphQL converts a self-billed invoice to GraphQL model
+func convertSelfBilledInvoiceToGraphQL(invoice *models.SelfBilledInvoice) *model.SelfBilledInvoice {
+	result := &model.SelfBilledInvoice{
+		ID:            invoice.ID,
+		PayoutID:      invoice.PayoutID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		IssueDate:     invoice.IssueDate,
+		SupplierName:  invoice.SupplierName,
+		SupplierCui:   invoice.SupplierCUI,
+		Subtotal:      invoice.Subtotal,
+		TaxAmount:     invoice.TaxAmount,
+		TotalAmount:   invoice.TotalAmount,
+		Currency:      invoice.Currency,
+		AnafStatus:    model.ANAFStatus(strings.ToUpper(string(invoice.ANAFStatus))),
+		CreatedAt:     invoice.CreatedAt,
+	}
+	if invoice.PdfURL.Valid {
+		result.PDFURL = &invoice.PdfURL.String
+	}
+	if invoice.XmlURL.Valid {
+		result.XMLURL = &invoice.XmlURL.String
+	}
+	if invoice.ANAFUploadIndex.Valid {
+		result.AnafUploadIndex = &invoice.ANAFUploadIndex.String
+	}
+	if invoice.ANAFSubmittedAt.Valid {
+		result.AnafSubmittedAt = &invoice.ANAFSubmittedAt.Time
+	}
+	result.AnafErrors = convertANAFErrorsToGraphQL(invoice.ANAFErrors)
+	return result
+}
+
+// convertCommissionInvoiceToGraphQL converts a commission invoice to GraphQL model
+func convertCommissionInvoiceToGraphQL(invoice *models.CommissionInvoice) *model.CommissionInvoice {
+	result := &model.CommissionInvoice{
+		ID:            invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		RecipientType: model.CommissionRecipientType(invoice.RecipientType),
+		PeriodStart:   invoice.PeriodStart,
+		PeriodEnd:     invoice.PeriodEnd,
+		IssueDate:     invoice.IssueDate,
+		DueDate:       invoice.DueDate,
+		CustomerName:  invoice.CustomerName,
+		Subtotal:      invoice.Subtotal,
+		VatRate:       invoice.VATRate,
+		TaxAmount:     invoice.TaxAmount,
+		TotalAmount:   invoice.TotalAmount,
+		Currency:      invoice.Currency,
+		AnafStatus:    model.ANAFStatus(strings.ToUpper(string(invoice.ANAFStatus))),
+		AnafErrors:    convertANAFErrorsToGraphQL(invoice.ANAFErrors),
+		CreatedAt:     invoice.CreatedAt,
+	}
+	if invoice.CompanyID.Valid {
+		result.CompanyID = &invoice.CompanyID.String
+	}
+	if invoice.CustomerCUI.Valid {
+		result.CustomerCui = &invoice.CustomerCUI.String
+	}
+	if invoice.PdfURL.Valid {
+		result.PDFURL = &invoice.PdfURL.String
+	}
+	if invoice.XmlURL.Valid {
+		result.XMLURL = &invoice.XmlURL.String
+	}
+	if invoice.ANAFUploadIndex.Valid {
+		result.AnafUploadIndex = &invoice.ANAFUploadIndex.String
+	}
+	if invoice.ANAFSubmittedAt.Valid {
+		result.AnafSubmittedAt = &invoice.ANAFSubmittedAt.Time
+	}
+	return result
+}
+
+// convertCreditNoteToGraphQL converts a credit note to GraphQL model
+func convertCreditNoteToGraphQL(note *models.CreditNote) *model.CreditNote {
+	result := &model.CreditNote{
+		ID:               note.ID,
+		CreditNoteNumber: note.CreditNoteNumber,
+		InvoiceID:        note.InvoiceID,
+		CreditType:       model.CreditNoteType(note.CreditType),
+		Reason:           note.Reason,
+		IssueDate:        note.IssueDate,
+		ClientName:       note.ClientName,
+		Subtotal:         note.Subtotal,
+		TaxAmount:        note.TaxAmount,
+		TotalAmount:      note.TotalAmount,
+		Currency:         note.Currency,
+		AnafStatus:       model.ANAFStatus(strings.ToUpper(string(note.ANAFStatus))),
+		AnafErrors:       convertANAFErrorsToGraphQL(note.ANAFErrors),
+		CreatedAt:        note.CreatedAt,
+	}
+	if note.RefundPaymentID.Valid {
+		result.RefundPaymentID = &note.RefundPaymentID.String
+	}
+	if note.PdfURL.Valid {
+		result.PDFURL = &note.PdfURL.String
+	}
+	if note.XmlURL.Valid {
+		result.XMLURL = &note.XmlURL.String
+	}
+	if note.ANAFUploadIndex.Valid {
+		result.AnafUploadIndex = &note.ANAFUploadIndex.String
+	}
+	if note.ANAFSubmittedAt.Valid {
+		result.AnafSubmittedAt = &note.ANAFSubmittedAt.Time
+	}
+	return result
+}
+
+// convertProformaToGraphQL converts a proforma to GraphQL model
+func convertProformaToGraphQL(proforma *models.Proforma) *model.Proforma {
+	result := &model.Proforma{
+		ID:             proforma.ID,
+		ProformaNumber: proforma.ProformaNumber,
+		BookingID:      proforma.BookingID,
+		PaymentID:      proforma.PaymentID,
+		IssueDate:      proforma.IssueDate,
+		DueDate:        proforma.DueDate,
+		ClientName:     proforma.ClientName,
+		Subtotal:       proforma.Subtotal,
+		TaxAmount:      proforma.TaxAmount,
+		TotalAmount:    proforma.TotalAmount,
+		AmountDue:      proforma.AmountDue,
+		Currency:       proforma.Currency,
+		Status:         model.ProformaStatus(proforma.Status),
+		Overdue:        proforma.IsOverdue(time.Now()),
+		ReminderCount:  proforma.ReminderCount,
+		CreatedAt:      proforma.CreatedAt,
+	}
+	if proforma.ClientEmail.Valid {
+		result.ClientEmail = &proforma.ClientEmail.String
+	}
+	if proforma.PdfURL.Valid {
+		result.PDFURL = &proforma.PdfURL.String
+	}
+	if proforma.PaidAt.Valid {
+		result.PaidAt = &proforma.PaidAt.Time
+	}
+	if proforma.PaymentReference.Valid {
+		result.PaymentReference = &proforma.PaymentReference.String
+	}
+	if proforma.InvoiceID.Valid {
+		result.InvoiceID = &proforma.InvoiceID.String
+	}
+	if proforma.ConvertedAt.Valid {
+		result.ConvertedAt = &proforma.ConvertedAt.Time
+	}
+	if proforma.LastReminderAt.Valid {
+		result.LastReminderAt = &proforma.LastReminderAt.Time
+	}
+	return result
+}
+
+// convertANAFAlertToGraphQL converts an ANAF alert to GraphQL model
+func convertANAFAlertToGraphQL(alert *models.ANAFAlert) *model.ANAFAlert {
+	result := &model.ANAFAlert{
+		ID:            alert.ID,
+		DocumentType:  model.ANAFDocumentType(alert.DocumentType),
+		DocumentID:    alert.DocumentID,
+		InvoiceNumber: alert.DocumentNumber,
+		AlertType:     model.ANAFAlertType(alert.AlertType),
+		Message:       alert.Message,
+		Deadline:      alert.Deadline,
+		CreatedAt:     alert.CreatedAt,
+	}
+	if alert.DocumentType == models.ANAFDocumentInvoice {
+		result.InvoiceID = &alert.DocumentID
+	}
+	if alert.ResolvedAt.Valid {
+		result.ResolvedAt = &alert.ResolvedAt.Time
+	}
+	return result
+}
+
+// convertInvoiceSeriesToGraphQL converts database invoice series model to GraphQL model
+func convertInvoiceSeriesToGraphQL(series *models.InvoiceSeries) *model.InvoiceSeries {
+	return &model.InvoiceSeries{
+		ID:           series.ID,
+		DocumentType: model.DocumentType(series.DocumentType),
+		Prefix:       series.Prefix,
+		YearlyReset:  series.YearlyReset,
+		IsActive:     series.IsActive,
+		LastNumber:   series.LastNumber,
+		NextNumber:   series.NextNumber(),
+		CreatedAt:    series.CreatedAt,
+	}
+}
+
+// convertANAFErrorsToGraphQL converts stored ANAF errors to GraphQL model
+func convertANAFErrorsToGraphQL(anafErrors []models.ANAFError) []*model.ANAFError {
+	var result []*model.ANAFError
+	for _, e := range anafErrors {
+		anafError := &model.ANAFError{Code: e.Code, Message: e.Message}
+		if e.Field != "" {
+			field := e.Field
+			anafError.Field = &field
+		}
+		result = append(result, anafError)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cleanbuddy/backend/internal/middleware"
+)
+
+// withIdempotency runs fn at most once per Idempotency-Key header value.
+// Replays with the same key and arguments return the stored result of the first execution.
+// Without a key (or without an idempotency service), fn is executed directly.
+func withIdempotency[T any](ctx context.Context, r *Resolver, operation string, args interface{}, fn func() (T, error)) (T, error) {
+	var zero T
+
+	key, ok := middleware.GetIdempotencyKeyFromContext(ctx)
+	if !ok || r.IdempotencyService == nil {
+		return fn()
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return fn()
+	}
+
+	stored, err := r.IdempotencyService.Begin(ctx, userID, operation, key, args)
+	if err != nil {
+		return zero, err
+	}
+	if stored != nil {
+		var replay T
+		if err := json.Unmarshal(stored, &replay); err != nil {
+			return zero, fmt.Errorf("failed to decode stored response: %w", err)
+		}
+		return replay, nil
+	}
+
+	stop := r.IdempotencyService.KeepAlive(userID, operation, key)
+	result, err := fn()
+	stop()
+	if err != nil {
+		r.IdempotencyService.Abort(userID, operation, key)
+		return zero, err
+	}
+
+	// Complete already retried: report the failure rather than a result a retry cannot replay
+	if err := r.IdempotencyService.Complete(ctx, userID, operation, key, args, result); err != nil {
+		return zero, fmt.Errorf("%s succeeded but its response could not be stored for replay: %w", operation, err)
+	}
+
+	return result, nil
+}
@@ -10,12 +10,31 @@ import (
 	"time"
 )
 
+type ANAFAlert struct {
+	ID            string           `json:"id"`
+	DocumentType  ANAFDocumentType `json:"documentType"`
+	DocumentID    string           `json:"documentId"`
+	InvoiceID     *string          `json:"invoiceId,omitempty"`
+	InvoiceNumber string           `json:"invoiceNumber"`
+	AlertType     ANAFAlertType    `json:"alertType"`
+	Message       string           `json:"message"`
+	Deadline      time.Time        `json:"deadline"`
+	ResolvedAt    *time.Time       `json:"resolvedAt,omitempty"`
+	CreatedAt     time.Time        `json:"createdAt"`
+}
+
 type ANAFError struct {
 	Code    string  `json:"code"`
 	Message string  `json:"message"`
 	Field   *string `json:"field,omitempty"`
 }
 
+type AccountingExportFile struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
 type Address struct {
 	ID             string    `json:"id"`
 	UserID         string    `json:"userId"`
@@ -85,51 +104,111 @@ type AvailabilityInput struct {
 	TimeSlots    []string `json:"timeSlots"`
 }
 
+type BankStatementImport struct {
+	ID             string              `json:"id"`
+	FileName       string              `json:"fileName"`
+	Format         BankStatementFormat `json:"format"`
+	LineCount      int                 `json:"lineCount"`
+	MatchedCount   int                 `json:"matchedCount"`
+	DuplicateCount int                 `json:"duplicateCount"`
+	UnmatchedCount int                 `json:"unmatchedCount"`
+	CreatedAt      time.Time           `json:"createdAt"`
+}
+
+type BankStatementLine struct {
+	ID                     string                   `json:"id"`
+	ImportID               string                   `json:"importId"`
+	BookingDate            time.Time                `json:"bookingDate"`
+	Direction              BankTransactionDirection `json:"direction"`
+	Amount                 float64                  `json:"amount"`
+	Currency               string                   `json:"currency"`
+	CounterpartyName       *string                  `json:"counterpartyName,omitempty"`
+	CounterpartyIban       *string                  `json:"counterpartyIban,omitempty"`
+	Reference              *string                  `json:"reference,omitempty"`
+	BankReference          *string                  `json:"bankReference,omitempty"`
+	MatchStatus            BankLineMatchStatus      `json:"matchStatus"`
+	Reversal               bool                     `json:"reversal"`
+	MatchedPayoutID        *string                  `json:"matchedPayoutId,omitempty"`
+	MatchedCompanyPayoutID *string                  `json:"matchedCompanyPayoutId,omitempty"`
+	MatchedInvoiceID       *string                  `json:"matchedInvoiceId,omitempty"`
+	MatchedProformaID      *string                  `json:"matchedProformaId,omitempty"`
+	ResolvedAt             *time.Time               `json:"resolvedAt,omitempty"`
+	Notes                  *string                  `json:"notes,omitempty"`
+	CreatedAt              time.Time                `json:"createdAt"`
+}
+
+type BillingProfile struct {
+	ID                 string    `json:"id"`
+	UserID             string    `json:"userId"`
+	LegalName          string    `json:"legalName"`
+	Cui                string    `json:"cui"`
+	RegistrationNumber *string   `json:"registrationNumber,omitempty"`
+	VatPayer           bool      `json:"vatPayer"`
+	StreetAddress      string    `json:"streetAddress"`
+	City               string    `json:"city"`
+	County             string    `json:"county"`
+	PostalCode         *string   `json:"postalCode,omitempty"`
+	Country            string    `json:"country"`
+	Email              *string   `json:"email,omitempty"`
+	IsDefault          bool      `json:"isDefault"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
 type Booking struct {
-	ID                     string        `json:"id"`
-	ReservationCode        *string       `json:"reservationCode,omitempty"`
-	ClientID               string        `json:"clientId"`
-	Client                 *User         `json:"client,omitempty"`
-	CleanerID              *string       `json:"cleanerId,omitempty"`
-	Cleaner                *User         `json:"cleaner,omitempty"`
-	AddressID              string        `json:"addressId"`
-	Address                *Address      `json:"address,omitempty"`
-	ServiceType            ServiceType   `json:"serviceType"`
-	AreaSqm                *int          `json:"areaSqm,omitempty"`
-	EstimatedHours         int           `json:"estimatedHours"`
-	Frequency              *string       `json:"frequency,omitempty"`
-	ScheduledDate          *time.Time    `json:"scheduledDate,omitempty"`
-	ScheduledTime          *time.Time    `json:"scheduledTime,omitempty"`
-	TimePreferences        *string       `json:"timePreferences,omitempty"`
-	IncludesDeepCleaning   bool          `json:"includesDeepCleaning"`
-	IncludesWindows        bool          `json:"includesWindows"`
-	IncludesCarpetCleaning bool          `json:"includesCarpetCleaning"`
-	IncludesFridge         bool          `json:"includesFridge"`
-	IncludesOven           bool          `json:"includesOven"`
-	IncludesBalcony        bool          `json:"includesBalcony"`
-	NumberOfWindows        int           `json:"numberOfWindows"`
-	CarpetAreaSqm          int           `json:"carpetAreaSqm"`
-	BasePrice              float64       `json:"basePrice"`
-	AddonsPrice            float64       `json:"addonsPrice"`
-	TotalPrice             float64       `json:"totalPrice"`
-	PlatformFee            float64       `json:"platformFee"`
-	CleanerPayout          float64       `json:"cleanerPayout"`
-	DiscountApplied        float64       `json:"discountApplied"`
-	Status                 BookingStatus `json:"status"`
-	SpecialInstructions    *string       `json:"specialInstructions,omitempty"`
-	AccessInstructions     *string       `json:"accessInstructions,omitempty"`
-	ConfirmedAt            *time.Time    `json:"confirmedAt,omitempty"`
-	StartedAt              *time.Time    `json:"startedAt,omitempty"`
-	CompletedAt            *time.Time    `json:"completedAt,omitempty"`
-	CancelledAt            *time.Time    `json:"cancelledAt,omitempty"`
-	CancelledBy            *string       `json:"cancelledBy,omitempty"`
-	CancellationReason     *string       `json:"cancellationReason,omitempty"`
-	ClientRating           *int          `json:"clientRating,omitempty"`
-	ClientReview           *string       `json:"clientReview,omitempty"`
-	CleanerRating          *int          `json:"cleanerRating,omitempty"`
-	CleanerReview          *string       `json:"cleanerReview,omitempty"`
-	CreatedAt              time.Time     `json:"createdAt"`
-	UpdatedAt              time.Time     `json:"updatedAt"`
+	ID                     string          `json:"id"`
+	ReservationCode        *string         `json:"reservationCode,omitempty"`
+	ClientID               string          `json:"clientId"`
+	Client                 *User           `json:"client,omitempty"`
+	CleanerID              *string         `json:"cleanerId,omitempty"`
+	Cleaner                *User           `json:"cleaner,omitempty"`
+	AddressID              string          `json:"addressId"`
+	Address                *Address        `json:"address,omitempty"`
+	ServiceType            ServiceType     `json:"serviceType"`
+	AreaSqm                *int            `json:"areaSqm,omitempty"`
+	EstimatedHours         int             `json:"estimatedHours"`
+	Frequency              *string         `json:"frequency,omitempty"`
+	ScheduledDate          *time.Time      `json:"scheduledDate,omitempty"`
+	ScheduledTime          *time.Time      `json:"scheduledTime,omitempty"`
+	TimePreferences        *string         `json:"timePreferences,omitempty"`
+	IncludesDeepCleaning   bool            `json:"includesDeepCleaning"`
+	IncludesWindows        bool            `json:"includesWindows"`
+	IncludesCarpetCleaning bool            `json:"includesCarpetCleaning"`
+	IncludesFridge         bool            `json:"includesFridge"`
+	IncludesOven           bool            `json:"includesOven"`
+	IncludesBalcony        bool            `json:"includesBalcony"`
+	NumberOfWindows        int             `json:"numberOfWindows"`
+	CarpetAreaSqm          int             `json:"carpetAreaSqm"`
+	BasePrice              float64         `json:"basePrice"`
+	AddonsPrice            float64         `json:"addonsPrice"`
+	TotalPrice             float64         `json:"totalPrice"`
+	PlatformFee            float64         `json:"platformFee"`
+	PlatformFeeRate        float64         `json:"platformFeeRate"`
+	PlatformFeeRule        PlatformFeeRule `json:"platformFeeRule"`
+	CleanerPayout          float64         `json:"cleanerPayout"`
+	DiscountApplied        float64         `json:"discountApplied"`
+	CreditApplied          float64         `json:"creditApplied"`
+	BillingProfileID       *string         `json:"billingProfileId,omitempty"`
+	AmountDue              float64         `json:"amountDue"`
+	Tip                    *Tip            `json:"tip,omitempty"`
+	Status                 BookingStatus   `json:"status"`
+	SpecialInstructions    *string         `json:"specialInstructions,omitempty"`
+	AccessInstructions     *string         `json:"accessInstructions,omitempty"`
+	ConfirmedAt            *time.Time      `json:"confirmedAt,omitempty"`
+	StartedAt              *time.Time      `json:"startedAt,omitempty"`
+	CompletedAt            *time.Time      `json:"completedAt,omitempty"`
+	CancelledAt            *time.Time      `json:"cancelledAt,omitempty"`
+	CancelledBy            *string         `json:"cancelledBy,omitempty"`
+	CancellationReason     *string         `json:"cancellationReason,omitempty"`
+	RefundStatus           *RefundStatus   `json:"refundStatus,omitempty"`
+	RefundAmount           float64         `json:"refundAmount"`
+	RefundProcessedAt      *time.Time      `json:"refundProcessedAt,omitempty"`
+	ClientRating           *int            `json:"clientRating,omitempty"`
+	ClientReview           *string         `json:"clientReview,omitempty"`
+	CleanerRating          *int            `json:"cleanerRating,omitempty"`
+	CleanerReview          *string         `json:"cleanerReview,omitempty"`
+	CreatedAt              time.Time       `json:"createdAt"`
+	UpdatedAt              time.Time       `json:"updatedAt"`
 }
 
 type Checkin struct {
@@ -213,6 +292,19 @@ type CleanerApplicationInput struct {
 	Documents    *DocumentInput     `json:"documents,omitempty"`
 }
 
+type CleanerSelfBilling struct {
+	Enabled            bool      `json:"enabled"`
+	LegalName          string    `json:"legalName"`
+	Cui                string    `json:"cui"`
+	RegistrationNumber *string   `json:"registrationNumber,omitempty"`
+	Address            string    `json:"address"`
+	City               string    `json:"city"`
+	County             string    `json:"county"`
+	VatPayer           bool      `json:"vatPayer"`
+	Series             string    `json:"series"`
+	AgreedAt           time.Time `json:"agreedAt"`
+}
+
 type CleanerStats struct {
 	TotalBookings     int        `json:"totalBookings"`
 	CompletedBookings int        `json:"completedBookings"`
@@ -237,6 +329,31 @@ type Client struct {
 	UpdatedAt         time.Time `json:"updatedAt"`
 }
 
+type CommissionInvoice struct {
+	ID              string                  `json:"id"`
+	InvoiceNumber   string                  `json:"invoiceNumber"`
+	RecipientType   CommissionRecipientType `json:"recipientType"`
+	CompanyID       *string                 `json:"companyId,omitempty"`
+	PeriodStart     time.Time               `json:"periodStart"`
+	PeriodEnd       time.Time               `json:"periodEnd"`
+	IssueDate       time.Time               `json:"issueDate"`
+	DueDate         time.Time               `json:"dueDate"`
+	CustomerName    string                  `json:"customerName"`
+	CustomerCui     *string                 `json:"customerCui,omitempty"`
+	Subtotal        float64                 `json:"subtotal"`
+	VatRate         float64                 `json:"vatRate"`
+	TaxAmount       float64                 `json:"taxAmount"`
+	TotalAmount     float64                 `json:"totalAmount"`
+	Currency        string                  `json:"currency"`
+	PDFURL          *string                 `json:"pdfUrl,omitempty"`
+	XMLURL          *string                 `json:"xmlUrl,omitempty"`
+	AnafUploadIndex *string                 `json:"anafUploadIndex,omitempty"`
+	AnafStatus      ANAFStatus              `json:"anafStatus"`
+	AnafSubmittedAt *time.Time              `json:"anafSubmittedAt,omitempty"`
+	AnafErrors      []*ANAFError            `json:"anafErrors,omitempty"`
+	CreatedAt       time.Time               `json:"createdAt"`
+}
+
 type Company struct {
 	ID                           string                `json:"id"`
 	Name                         string                `json:"name"`
@@ -256,6 +373,7 @@ type Company struct {
 	ApprovedBy                   *string               `json:"approvedBy,omitempty"`
 	ApprovedAt                   *time.Time            `json:"approvedAt,omitempty"`
 	IsActive                     bool                  `json:"isActive"`
+	PlatformFeePercentage        *float64              `json:"platformFeePercentage,omitempty"`
 	CreatedAt                    time.Time             `json:"createdAt"`
 	UpdatedAt                    time.Time             `json:"updatedAt"`
 }
@@ -270,6 +388,37 @@ type CompanyCleaner struct {
 	LeftAt    *time.Time `json:"leftAt,omitempty"`
 }
 
+type CompanyPayout struct {
+	ID                string       `json:"id"`
+	CompanyID         string       `json:"companyId"`
+	PeriodStart       time.Time    `json:"periodStart"`
+	PeriodEnd         time.Time    `json:"periodEnd"`
+	Status            PayoutStatus `json:"status"`
+	CleanerCount      int          `json:"cleanerCount"`
+	TotalBookings     int          `json:"totalBookings"`
+	TotalEarnings     float64      `json:"totalEarnings"`
+	PlatformFees      float64      `json:"platformFees"`
+	NetAmount         float64      `json:"netAmount"`
+	TransferReference *string      `json:"transferReference,omitempty"`
+	PaidAt            *time.Time   `json:"paidAt,omitempty"`
+	FailedReason      *string      `json:"failedReason,omitempty"`
+	CreatedAt         time.Time    `json:"createdAt"`
+	UpdatedAt         time.Time    `json:"updatedAt"`
+	Payouts           []*Payout    `json:"payouts"`
+}
+
+type CompanyRegistryInfo struct {
+	Cui                string  `json:"cui"`
+	LegalName          string  `json:"legalName"`
+	RegistrationNumber *string `json:"registrationNumber,omitempty"`
+	VatPayer           bool    `json:"vatPayer"`
+	Active             bool    `json:"active"`
+	StreetAddress      *string `json:"streetAddress,omitempty"`
+	City               *string `json:"city,omitempty"`
+	County             *string `json:"county,omitempty"`
+	PostalCode         *string `json:"postalCode,omitempty"`
+}
+
 type CompanyStats struct {
 	TotalTeamMembers  int      `json:"totalTeamMembers"`
 	ActiveTeamMembers int      `json:"activeTeamMembers"`
@@ -302,6 +451,19 @@ type CreateAvailabilityInput struct {
 	Notes        *string          `json:"notes,omitempty"`
 }
 
+type CreateBillingProfileInput struct {
+	LegalName          string  `json:"legalName"`
+	Cui                string  `json:"cui"`
+	RegistrationNumber *string `json:"registrationNumber,omitempty"`
+	VatPayer           bool    `json:"vatPayer"`
+	StreetAddress      string  `json:"streetAddress"`
+	City               string  `json:"city"`
+	County             string  `json:"county"`
+	PostalCode         *string `json:"postalCode,omitempty"`
+	Email              *string `json:"email,omitempty"`
+	IsDefault          *bool   `json:"isDefault,omitempty"`
+}
+
 type CreateBookingInput struct {
 	AddressID            string      `json:"addressId"`
 	ServiceType          ServiceType `json:"serviceType"`
@@ -322,6 +484,7 @@ type CreateBookingInput struct {
 	AccessInstructions   *string     `json:"accessInstructions,omitempty"`
 	Supplies             string      `json:"supplies"`
 	Frequency            *string     `json:"frequency,omitempty"`
+	BillingProfileID     *string     `json:"billingProfileId,omitempty"`
 }
 
 type CreateCleanerProfileInput struct {
@@ -348,18 +511,62 @@ type CreateCompanyInput struct {
 	ContactPhone       *string `json:"contactPhone,omitempty"`
 }
 
+type CreateCreditNoteInput struct {
+	InvoiceID string  `json:"invoiceId"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+}
+
 type CreateDisputeInput struct {
 	BookingID   string      `json:"bookingId"`
 	DisputeType DisputeType `json:"disputeType"`
 	Description string      `json:"description"`
 }
 
+type CreateInvoiceSeriesInput struct {
+	DocumentType DocumentType `json:"documentType"`
+	Prefix       string       `json:"prefix"`
+	YearlyReset  *bool        `json:"yearlyReset,omitempty"`
+	StartNumber  *int         `json:"startNumber,omitempty"`
+	Activate     *bool        `json:"activate,omitempty"`
+}
+
+type CreatePayoutAdjustmentInput struct {
+	CleanerID string               `json:"cleanerId"`
+	Type      PayoutAdjustmentType `json:"type"`
+	Amount    float64              `json:"amount"`
+	Reason    string               `json:"reason"`
+	BookingID *string              `json:"bookingId,omitempty"`
+}
+
 type CreateReviewInput struct {
 	BookingID string  `json:"bookingId"`
 	Rating    int     `json:"rating"`
 	Comment   *string `json:"comment,omitempty"`
 }
 
+type CreditNote struct {
+	ID               string         `json:"id"`
+	CreditNoteNumber string         `json:"creditNoteNumber"`
+	InvoiceID        string         `json:"invoiceId"`
+	RefundPaymentID  *string        `json:"refundPaymentId,omitempty"`
+	CreditType       CreditNoteType `json:"creditType"`
+	Reason           string         `json:"reason"`
+	IssueDate        time.Time      `json:"issueDate"`
+	ClientName       string         `json:"clientName"`
+	Subtotal         float64        `json:"subtotal"`
+	TaxAmount        float64        `json:"taxAmount"`
+	TotalAmount      float64        `json:"totalAmount"`
+	Currency         string         `json:"currency"`
+	PDFURL           *string        `json:"pdfUrl,omitempty"`
+	XMLURL           *string        `json:"xmlUrl,omitempty"`
+	AnafUploadIndex  *string        `json:"anafUploadIndex,omitempty"`
+	AnafStatus       ANAFStatus     `json:"anafStatus"`
+	AnafSubmittedAt  *time.Time     `json:"anafSubmittedAt,omitempty"`
+	AnafErrors       []*ANAFError   `json:"anafErrors,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+}
+
 type Dispute struct {
 	ID                 string                 `json:"id"`
 	BookingID          string                 `json:"bookingId"`
@@ -414,39 +621,95 @@ type EligibilityInput struct {
 	Experience string `json:"experience"`
 }
 
+type EnableSelfBillingInput struct {
+	LegalName          string  `json:"legalName"`
+	Cui                string  `json:"cui"`
+	RegistrationNumber *string `json:"registrationNumber,omitempty"`
+	Address            string  `json:"address"`
+	City               string  `json:"city"`
+	County             string  `json:"county"`
+	VatPayer           bool    `json:"vatPayer"`
+	AcceptAgreement    bool    `json:"acceptAgreement"`
+}
+
+type ExportPayoutBatchInput struct {
+	Format    PaymentFileFormat `json:"format"`
+	PayoutIds []string          `json:"payoutIds,omitempty"`
+}
+
 type GeneratePayoutsInput struct {
 	Year  int `json:"year"`
 	Month int `json:"month"`
 }
 
+type GrantWalletCreditInput struct {
+	UserID    string             `json:"userId"`
+	Amount    float64            `json:"amount"`
+	Source    WalletCreditSource `json:"source"`
+	Reason    string             `json:"reason"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+}
+
 type Invoice struct {
-	ID                  string        `json:"id"`
-	BookingID           string        `json:"bookingId"`
-	InvoiceNumber       string        `json:"invoiceNumber"`
-	IssueDate           time.Time     `json:"issueDate"`
-	DueDate             time.Time     `json:"dueDate"`
-	ClientName          string        `json:"clientName"`
-	ClientEmail         *string       `json:"clientEmail,omitempty"`
-	CleanerName         string        `json:"cleanerName"`
-	ServiceDescription  string        `json:"serviceDescription"`
-	Subtotal            float64       `json:"subtotal"`
-	TaxAmount           float64       `json:"taxAmount"`
-	TotalAmount         float64       `json:"totalAmount"`
-	Currency            string        `json:"currency"`
-	Status              InvoiceStatus `json:"status"`
-	PDFURL              *string       `json:"pdfUrl,omitempty"`
-	XMLURL              *string       `json:"xmlUrl,omitempty"`
-	CreatedAt           time.Time     `json:"createdAt"`
-	UpdatedAt           time.Time     `json:"updatedAt"`
-	AnafUploadIndex     *string       `json:"anafUploadIndex,omitempty"`
-	AnafStatus          ANAFStatus    `json:"anafStatus"`
-	AnafSubmittedAt     *time.Time    `json:"anafSubmittedAt,omitempty"`
-	AnafProcessedAt     *time.Time    `json:"anafProcessedAt,omitempty"`
-	AnafDownloadID      *string       `json:"anafDownloadId,omitempty"`
-	AnafConfirmationURL *string       `json:"anafConfirmationUrl,omitempty"`
-	AnafErrors          []*ANAFError  `json:"anafErrors,omitempty"`
-	AnafRetryCount      int           `json:"anafRetryCount"`
-	AnafLastRetryAt     *time.Time    `json:"anafLastRetryAt,omitempty"`
+	ID                  string             `json:"id"`
+	BookingID           string             `json:"bookingId"`
+	InvoiceNumber       string             `json:"invoiceNumber"`
+	IssueDate           time.Time          `json:"issueDate"`
+	DueDate             time.Time          `json:"dueDate"`
+	ClientName          string             `json:"clientName"`
+	ClientEmail         *string            `json:"clientEmail,omitempty"`
+	CleanerName         string             `json:"cleanerName"`
+	ServiceDescription  string             `json:"serviceDescription"`
+	Subtotal            float64            `json:"subtotal"`
+	TaxAmount           float64            `json:"taxAmount"`
+	TotalAmount         float64            `json:"totalAmount"`
+	Currency            string             `json:"currency"`
+	Status              InvoiceStatus      `json:"status"`
+	PDFURL              *string            `json:"pdfUrl,omitempty"`
+	XMLURL              *string            `json:"xmlUrl,omitempty"`
+	CreatedAt           time.Time          `json:"createdAt"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+	AnafUploadIndex     *string            `json:"anafUploadIndex,omitempty"`
+	AnafStatus          ANAFStatus         `json:"anafStatus"`
+	AnafSubmittedAt     *time.Time         `json:"anafSubmittedAt,omitempty"`
+	AnafProcessedAt     *time.Time         `json:"anafProcessedAt,omitempty"`
+	AnafDownloadID      *string            `json:"anafDownloadId,omitempty"`
+	AnafConfirmationURL *string            `json:"anafConfirmationUrl,omitempty"`
+	AnafErrors          []*ANAFError       `json:"anafErrors,omitempty"`
+	AnafRetryCount      int                `json:"anafRetryCount"`
+	AnafLastRetryAt     *time.Time         `json:"anafLastRetryAt,omitempty"`
+	EmailStatus         InvoiceEmailStatus `json:"emailStatus"`
+	EmailSentAt         *time.Time         `json:"emailSentAt,omitempty"`
+	EmailSendCount      int                `json:"emailSendCount"`
+	EmailLastError      *string            `json:"emailLastError,omitempty"`
+	SignedCopySentAt    *time.Time         `json:"signedCopySentAt,omitempty"`
+	CreditNotes         []*CreditNote      `json:"creditNotes"`
+}
+
+type InvoiceConfirmationFile struct {
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Content     string    `json:"content"`
+	Sha256      string    `json:"sha256"`
+	RetainUntil time.Time `json:"retainUntil"`
+}
+
+type InvoiceSeries struct {
+	ID           string       `json:"id"`
+	DocumentType DocumentType `json:"documentType"`
+	Prefix       string       `json:"prefix"`
+	YearlyReset  bool         `json:"yearlyReset"`
+	IsActive     bool         `json:"isActive"`
+	LastNumber   int          `json:"lastNumber"`
+	NextNumber   string       `json:"nextNumber"`
+	CreatedAt    time.Time    `json:"createdAt"`
+}
+
+type LedgerAccountBalance struct {
+	Account     LedgerAccount `json:"account"`
+	TotalDebit  float64       `json:"totalDebit"`
+	TotalCredit float64       `json:"totalCredit"`
+	Balance     float64       `json:"balance"`
 }
 
 type LegalData struct {
@@ -506,36 +769,90 @@ type Payment struct {
 }
 
 type Payout struct {
-	ID                   string            `json:"id"`
-	CleanerID            string            `json:"cleanerId"`
-	PeriodStart          time.Time         `json:"periodStart"`
-	PeriodEnd            time.Time         `json:"periodEnd"`
-	Status               PayoutStatus      `json:"status"`
-	TotalBookings        int               `json:"totalBookings"`
-	TotalEarnings        float64           `json:"totalEarnings"`
-	PlatformFees         float64           `json:"platformFees"`
-	NetAmount            float64           `json:"netAmount"`
-	Iban                 *string           `json:"iban,omitempty"`
-	TransferReference    *string           `json:"transferReference,omitempty"`
-	SettlementInvoiceURL *string           `json:"settlementInvoiceUrl,omitempty"`
-	PaidAt               *time.Time        `json:"paidAt,omitempty"`
-	FailedReason         *string           `json:"failedReason,omitempty"`
-	CreatedAt            time.Time         `json:"createdAt"`
-	UpdatedAt            time.Time         `json:"updatedAt"`
-	LineItems            []*PayoutLineItem `json:"lineItems"`
+	ID                   string             `json:"id"`
+	CleanerID            string             `json:"cleanerId"`
+	PeriodStart          time.Time          `json:"periodStart"`
+	PeriodEnd            time.Time          `json:"periodEnd"`
+	Status               PayoutStatus       `json:"status"`
+	TotalBookings        int                `json:"totalBookings"`
+	TotalEarnings        float64            `json:"totalEarnings"`
+	PlatformFees         float64            `json:"platformFees"`
+	NetAmount            float64            `json:"netAmount"`
+	Iban                 *string            `json:"iban,omitempty"`
+	TransferReference    *string            `json:"transferReference,omitempty"`
+	SettlementInvoiceURL *string            `json:"settlementInvoiceUrl,omitempty"`
+	PaidAt               *time.Time         `json:"paidAt,omitempty"`
+	FailedReason         *string            `json:"failedReason,omitempty"`
+	CreatedAt            time.Time          `json:"createdAt"`
+	UpdatedAt            time.Time          `json:"updatedAt"`
+	LineItems            []*PayoutLineItem  `json:"lineItems"`
+	SelfBilledInvoice    *SelfBilledInvoice `json:"selfBilledInvoice,omitempty"`
+	CompanyPayoutID      *string            `json:"companyPayoutId,omitempty"`
+}
+
+type PayoutAdjustment struct {
+	ID                  string               `json:"id"`
+	CleanerID           string               `json:"cleanerId"`
+	Type                PayoutAdjustmentType `json:"type"`
+	Amount              float64              `json:"amount"`
+	Reason              string               `json:"reason"`
+	BookingID           *string              `json:"bookingId,omitempty"`
+	DisputeID           *string              `json:"disputeId,omitempty"`
+	CarriedFromPayoutID *string              `json:"carriedFromPayoutId,omitempty"`
+	CreatedBy           *string              `json:"createdBy,omitempty"`
+	PayoutID            *string              `json:"payoutId,omitempty"`
+	CreatedAt           time.Time            `json:"createdAt"`
+}
+
+type PayoutBatch struct {
+	ID                string            `json:"id"`
+	Reference         string            `json:"reference"`
+	Format            PaymentFileFormat `json:"format"`
+	Status            PayoutBatchStatus `json:"status"`
+	PayoutCount       int               `json:"payoutCount"`
+	TotalAmount       float64           `json:"totalAmount"`
+	FileName          string            `json:"fileName"`
+	ContentType       string            `json:"contentType"`
+	FileContent       string            `json:"fileContent"`
+	TransferReference *string           `json:"transferReference,omitempty"`
+	CreatedBy         *string           `json:"createdBy,omitempty"`
+	SentAt            *time.Time        `json:"sentAt,omitempty"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	Payouts           []*Payout         `json:"payouts"`
 }
 
 type PayoutLineItem struct {
-	ID              string    `json:"id"`
-	PayoutID        string    `json:"payoutId"`
-	BookingID       string    `json:"bookingId"`
-	BookingDate     time.Time `json:"bookingDate"`
-	ServiceType     string    `json:"serviceType"`
-	BookingAmount   float64   `json:"bookingAmount"`
-	PlatformFeeRate float64   `json:"platformFeeRate"`
-	PlatformFee     float64   `json:"platformFee"`
-	CleanerEarnings float64   `json:"cleanerEarnings"`
-	CreatedAt       time.Time `json:"createdAt"`
+	ID              string             `json:"id"`
+	PayoutID        string             `json:"payoutId"`
+	BookingID       *string            `json:"bookingId,omitempty"`
+	ItemType        PayoutLineItemType `json:"itemType"`
+	AdjustmentID    *string            `json:"adjustmentId,omitempty"`
+	Description     *string            `json:"description,omitempty"`
+	BookingDate     time.Time          `json:"bookingDate"`
+	ServiceType     string             `json:"serviceType"`
+	BookingAmount   float64            `json:"bookingAmount"`
+	PlatformFeeRate float64            `json:"platformFeeRate"`
+	PlatformFee     float64            `json:"platformFee"`
+	CleanerEarnings float64            `json:"cleanerEarnings"`
+	CreatedAt       time.Time          `json:"createdAt"`
+}
+
+type PayoutRunSummary struct {
+	PeriodStart     time.Time `json:"periodStart"`
+	PeriodEnd       time.Time `json:"periodEnd"`
+	DryRun          bool      `json:"dryRun"`
+	Payouts         []*Payout `json:"payouts"`
+	SkippedCleaners int       `json:"skippedCleaners"`
+	HeldBookings    int       `json:"heldBookings"`
+	TotalEarnings   float64   `json:"totalEarnings"`
+	PlatformFees    float64   `json:"platformFees"`
+	NetAmount       float64   `json:"netAmount"`
+}
+
+type PayoutStatementFile struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
 }
 
 type Photo struct {
@@ -606,6 +923,7 @@ type PriceQuote struct {
 	Discount       float64         `json:"discount"`
 	PlatformFee    float64         `json:"platformFee"`
 	TotalPrice     float64         `json:"totalPrice"`
+	VatAmount      float64         `json:"vatAmount"`
 	CleanerPayout  float64         `json:"cleanerPayout"`
 	EstimatedHours int             `json:"estimatedHours"`
 	Breakdown      *PriceBreakdown `json:"breakdown"`
@@ -638,6 +956,32 @@ type ProfileInput struct {
 	Equipment []string `json:"equipment"`
 }
 
+type Proforma struct {
+	ID               string         `json:"id"`
+	ProformaNumber   string         `json:"proformaNumber"`
+	BookingID        string         `json:"bookingId"`
+	PaymentID        string         `json:"paymentId"`
+	IssueDate        time.Time      `json:"issueDate"`
+	DueDate          time.Time      `json:"dueDate"`
+	ClientName       string         `json:"clientName"`
+	ClientEmail      *string        `json:"clientEmail,omitempty"`
+	Subtotal         float64        `json:"subtotal"`
+	TaxAmount        float64        `json:"taxAmount"`
+	TotalAmount      float64        `json:"totalAmount"`
+	AmountDue        float64        `json:"amountDue"`
+	Currency         string         `json:"currency"`
+	Status           ProformaStatus `json:"status"`
+	Overdue          bool           `json:"overdue"`
+	PDFURL           *string        `json:"pdfUrl,omitempty"`
+	PaidAt           *time.Time     `json:"paidAt,omitempty"`
+	PaymentReference *string        `json:"paymentReference,omitempty"`
+	InvoiceID        *string        `json:"invoiceId,omitempty"`
+	ConvertedAt      *time.Time     `json:"convertedAt,omitempty"`
+	ReminderCount    int            `json:"reminderCount"`
+	LastReminderAt   *time.Time     `json:"lastReminderAt,omitempty"`
+	CreatedAt        time.Time      `json:"createdAt"`
+}
+
 type Query struct {
 }
 
@@ -659,6 +1003,26 @@ type Review struct {
 	UpdatedAt    time.Time    `json:"updatedAt"`
 }
 
+type SelfBilledInvoice struct {
+	ID              string       `json:"id"`
+	PayoutID        string       `json:"payoutId"`
+	InvoiceNumber   string       `json:"invoiceNumber"`
+	IssueDate       time.Time    `json:"issueDate"`
+	SupplierName    string       `json:"supplierName"`
+	SupplierCui     string       `json:"supplierCui"`
+	Subtotal        float64      `json:"subtotal"`
+	TaxAmount       float64      `json:"taxAmount"`
+	TotalAmount     float64      `json:"totalAmount"`
+	Currency        string       `json:"currency"`
+	PDFURL          *string      `json:"pdfUrl,omitempty"`
+	XMLURL          *string      `json:"xmlUrl,omitempty"`
+	AnafUploadIndex *string      `json:"anafUploadIndex,omitempty"`
+	AnafStatus      ANAFStatus   `json:"anafStatus"`
+	AnafSubmittedAt *time.Time   `json:"anafSubmittedAt,omitempty"`
+	AnafErrors      []*ANAFError `json:"anafErrors,omitempty"`
+	CreatedAt       time.Time    `json:"createdAt"`
+}
+
 type SendMessageInput struct {
 	BookingID  string `json:"bookingId"`
 	ReceiverID string `json:"receiverId"`
@@ -670,6 +1034,16 @@ type Session struct {
 	User  *User  `json:"user"`
 }
 
+type Tip struct {
+	ID        string    `json:"id"`
+	BookingID string    `json:"bookingId"`
+	CleanerID string    `json:"cleanerId"`
+	PaymentID string    `json:"paymentId"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type TopCleanerStat struct {
 	CleanerID     string   `json:"cleanerId"`
 	CleanerName   string   `json:"cleanerName"`
@@ -678,6 +1052,14 @@ type TopCleanerStat struct {
 	AverageRating *float64 `json:"averageRating,omitempty"`
 }
 
+type TrialBalance struct {
+	AsOf        time.Time               `json:"asOf"`
+	Accounts    []*LedgerAccountBalance `json:"accounts"`
+	TotalDebit  float64                 `json:"totalDebit"`
+	TotalCredit float64                 `json:"totalCredit"`
+	Balanced    bool                    `json:"balanced"`
+}
+
 type UpdateAddressInput struct {
 	Label          *string `json:"label,omitempty"`
 	StreetAddress  *string `json:"streetAddress,omitempty"`
@@ -696,6 +1078,19 @@ type UpdateAvailabilityInput struct {
 	Notes     *string `json:"notes,omitempty"`
 }
 
+type UpdateBillingProfileInput struct {
+	LegalName          *string `json:"legalName,omitempty"`
+	Cui                *string `json:"cui,omitempty"`
+	RegistrationNumber *string `json:"registrationNumber,omitempty"`
+	VatPayer           *bool   `json:"vatPayer,omitempty"`
+	StreetAddress      *string `json:"streetAddress,omitempty"`
+	City               *string `json:"city,omitempty"`
+	County             *string `json:"county,omitempty"`
+	PostalCode         *string `json:"postalCode,omitempty"`
+	Email              *string `json:"email,omitempty"`
+	IsDefault          *bool   `json:"isDefault,omitempty"`
+}
+
 type UpdateCleanerProfileInput struct {
 	PhoneNumber       *string  `json:"phoneNumber,omitempty"`
 	DateOfBirth       *string  `json:"dateOfBirth,omitempty"`
@@ -755,6 +1150,140 @@ type User struct {
 	Client    *Client   `json:"client,omitempty"`
 }
 
+type Wallet struct {
+	Balance      float64              `json:"balance"`
+	Currency     string               `json:"currency"`
+	Transactions []*WalletTransaction `json:"transactions"`
+}
+
+type WalletTransaction struct {
+	ID              string                  `json:"id"`
+	Amount          float64                 `json:"amount"`
+	Source          WalletTransactionSource `json:"source"`
+	Reason          *string                 `json:"reason,omitempty"`
+	RemainingAmount float64                 `json:"remainingAmount"`
+	ExpiresAt       *time.Time              `json:"expiresAt,omitempty"`
+	BookingID       *string                 `json:"bookingId,omitempty"`
+	DisputeID       *string                 `json:"disputeId,omitempty"`
+	CreatedAt       time.Time               `json:"createdAt"`
+}
+
+type ANAFAlertType string
+
+const (
+	ANAFAlertTypeDeadlineApproaching ANAFAlertType = "DEADLINE_APPROACHING"
+	ANAFAlertTypeRejected            ANAFAlertType = "REJECTED"
+	ANAFAlertTypeRetriesExhausted    ANAFAlertType = "RETRIES_EXHAUSTED"
+)
+
+var AllANAFAlertType = []ANAFAlertType{
+	ANAFAlertTypeDeadlineApproaching,
+	ANAFAlertTypeRejected,
+	ANAFAlertTypeRetriesExhausted,
+}
+
+func (e ANAFAlertType) IsValid() bool {
+	switch e {
+	case ANAFAlertTypeDeadlineApproaching, ANAFAlertTypeRejected, ANAFAlertTypeRetriesExhausted:
+		return true
+	}
+	return false
+}
+
+func (e ANAFAlertType) String() string {
+	return string(e)
+}
+
+func (e *ANAFAlertType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ANAFAlertType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ANAFAlertType", str)
+	}
+	return nil
+}
+
+func (e ANAFAlertType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ANAFAlertType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ANAFAlertType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ANAFDocumentType string
+
+const (
+	ANAFDocumentTypeInvoice           ANAFDocumentType = "INVOICE"
+	ANAFDocumentTypeCreditNote        ANAFDocumentType = "CREDIT_NOTE"
+	ANAFDocumentTypeCommissionInvoice ANAFDocumentType = "COMMISSION_INVOICE"
+	ANAFDocumentTypeSelfBilledInvoice ANAFDocumentType = "SELF_BILLED_INVOICE"
+)
+
+var AllANAFDocumentType = []ANAFDocumentType{
+	ANAFDocumentTypeInvoice,
+	ANAFDocumentTypeCreditNote,
+	ANAFDocumentTypeCommissionInvoice,
+	ANAFDocumentTypeSelfBilledInvoice,
+}
+
+func (e ANAFDocumentType) IsValid() bool {
+	switch e {
+	case ANAFDocumentTypeInvoice, ANAFDocumentTypeCreditNote, ANAFDocumentTypeCommissionInvoice, ANAFDocumentTypeSelfBilledInvoice:
+		return true
+	}
+	return false
+}
+
+func (e ANAFDocumentType) String() string {
+	return string(e)
+}
+
+func (e *ANAFDocumentType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ANAFDocumentType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ANAFDocumentType", str)
+	}
+	return nil
+}
+
+func (e ANAFDocumentType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ANAFDocumentType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ANAFDocumentType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type ANAFStatus string
 
 const (
@@ -816,6 +1345,63 @@ func (e ANAFStatus) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type AccountingExportFormat string
+
+const (
+	AccountingExportFormatSaga      AccountingExportFormat = "SAGA"
+	AccountingExportFormatWinmentor AccountingExportFormat = "WINMENTOR"
+	AccountingExportFormatSaft      AccountingExportFormat = "SAFT"
+)
+
+var AllAccountingExportFormat = []AccountingExportFormat{
+	AccountingExportFormatSaga,
+	AccountingExportFormatWinmentor,
+	AccountingExportFormatSaft,
+}
+
+func (e AccountingExportFormat) IsValid() bool {
+	switch e {
+	case AccountingExportFormatSaga, AccountingExportFormatWinmentor, AccountingExportFormatSaft:
+		return true
+	}
+	return false
+}
+
+func (e AccountingExportFormat) String() string {
+	return string(e)
+}
+
+func (e *AccountingExportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountingExportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountingExportFormat", str)
+	}
+	return nil
+}
+
+func (e AccountingExportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AccountingExportFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AccountingExportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type ApplicationStatus string
 
 const (
@@ -993,52 +1579,50 @@ func (e AvailabilityType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-type BookingFilter string
+type BankLineMatchStatus string
 
 const (
-	BookingFilterAll      BookingFilter = "ALL"
-	BookingFilterUpcoming BookingFilter = "UPCOMING"
-	BookingFilterPast     BookingFilter = "PAST"
-	BookingFilterActive   BookingFilter = "ACTIVE"
+	BankLineMatchStatusUnmatched BankLineMatchStatus = "UNMATCHED"
+	BankLineMatchStatusMatched   BankLineMatchStatus = "MATCHED"
+	BankLineMatchStatusIgnored   BankLineMatchStatus = "IGNORED"
 )
 
-var AllBookingFilter = []BookingFilter{
-	BookingFilterAll,
-	BookingFilterUpcoming,
-	BookingFilterPast,
-	BookingFilterActive,
+var AllBankLineMatchStatus = []BankLineMatchStatus{
+	BankLineMatchStatusUnmatched,
+	BankLineMatchStatusMatched,
+	BankLineMatchStatusIgnored,
 }
 
-func (e BookingFilter) IsValid() bool {
+func (e BankLineMatchStatus) IsValid() bool {
 	switch e {
-	case BookingFilterAll, BookingFilterUpcoming, BookingFilterPast, BookingFilterActive:
+	case BankLineMatchStatusUnmatched, BankLineMatchStatusMatched, BankLineMatchStatusIgnored:
 		return true
 	}
 	return false
 }
 
-func (e BookingFilter) String() string {
+func (e BankLineMatchStatus) String() string {
 	return string(e)
 }
 
-func (e *BookingFilter) UnmarshalGQL(v any) error {
+func (e *BankLineMatchStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = BookingFilter(str)
+	*e = BankLineMatchStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid BookingFilter", str)
+		return fmt.Errorf("%s is not a valid BankLineMatchStatus", str)
 	}
 	return nil
 }
 
-func (e BookingFilter) MarshalGQL(w io.Writer) {
+func (e BankLineMatchStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *BookingFilter) UnmarshalJSON(b []byte) error {
+func (e *BankLineMatchStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1046,68 +1630,54 @@ func (e *BookingFilter) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e BookingFilter) MarshalJSON() ([]byte, error) {
+func (e BankLineMatchStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type BookingStatus string
+type BankStatementFormat string
 
 const (
-	BookingStatusPending       BookingStatus = "PENDING"
-	BookingStatusConfirmed     BookingStatus = "CONFIRMED"
-	BookingStatusInProgress    BookingStatus = "IN_PROGRESS"
-	BookingStatusCompleted     BookingStatus = "COMPLETED"
-	BookingStatusCancelled     BookingStatus = "CANCELLED"
-	BookingStatusDisputed      BookingStatus = "DISPUTED"
-	BookingStatusRefunded      BookingStatus = "REFUNDED"
-	BookingStatusNoShowClient  BookingStatus = "NO_SHOW_CLIENT"
-	BookingStatusNoShowCleaner BookingStatus = "NO_SHOW_CLEANER"
+	BankStatementFormatCamt053 BankStatementFormat = "CAMT053"
+	BankStatementFormatMt940   BankStatementFormat = "MT940"
 )
 
-var AllBookingStatus = []BookingStatus{
-	BookingStatusPending,
-	BookingStatusConfirmed,
-	BookingStatusInProgress,
-	BookingStatusCompleted,
-	BookingStatusCancelled,
-	BookingStatusDisputed,
-	BookingStatusRefunded,
-	BookingStatusNoShowClient,
-	BookingStatusNoShowCleaner,
+var AllBankStatementFormat = []BankStatementFormat{
+	BankStatementFormatCamt053,
+	BankStatementFormatMt940,
 }
 
-func (e BookingStatus) IsValid() bool {
+func (e BankStatementFormat) IsValid() bool {
 	switch e {
-	case BookingStatusPending, BookingStatusConfirmed, BookingStatusInProgress, BookingStatusCompleted, BookingStatusCancelled, BookingStatusDisputed, BookingStatusRefunded, BookingStatusNoShowClient, BookingStatusNoShowCleaner:
+	case BankStatementFormatCamt053, BankStatementFormatMt940:
 		return true
 	}
 	return false
 }
 
-func (e BookingStatus) String() string {
+func (e BankStatementFormat) String() string {
 	return string(e)
 }
 
-func (e *BookingStatus) UnmarshalGQL(v any) error {
+func (e *BankStatementFormat) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = BookingStatus(str)
+	*e = BankStatementFormat(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid BookingStatus", str)
+		return fmt.Errorf("%s is not a valid BankStatementFormat", str)
 	}
 	return nil
 }
 
-func (e BookingStatus) MarshalGQL(w io.Writer) {
+func (e BankStatementFormat) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *BookingStatus) UnmarshalJSON(b []byte) error {
+func (e *BankStatementFormat) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1115,56 +1685,54 @@ func (e *BookingStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e BookingStatus) MarshalJSON() ([]byte, error) {
+func (e BankStatementFormat) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type CompanyApprovalStatus string
+type BankTransactionDirection string
 
 const (
-	CompanyApprovalStatusPending  CompanyApprovalStatus = "PENDING"
-	CompanyApprovalStatusApproved CompanyApprovalStatus = "APPROVED"
-	CompanyApprovalStatusRejected CompanyApprovalStatus = "REJECTED"
+	BankTransactionDirectionCredit BankTransactionDirection = "CREDIT"
+	BankTransactionDirectionDebit  BankTransactionDirection = "DEBIT"
 )
 
-var AllCompanyApprovalStatus = []CompanyApprovalStatus{
-	CompanyApprovalStatusPending,
-	CompanyApprovalStatusApproved,
-	CompanyApprovalStatusRejected,
+var AllBankTransactionDirection = []BankTransactionDirection{
+	BankTransactionDirectionCredit,
+	BankTransactionDirectionDebit,
 }
 
-func (e CompanyApprovalStatus) IsValid() bool {
+func (e BankTransactionDirection) IsValid() bool {
 	switch e {
-	case CompanyApprovalStatusPending, CompanyApprovalStatusApproved, CompanyApprovalStatusRejected:
+	case BankTransactionDirectionCredit, BankTransactionDirectionDebit:
 		return true
 	}
 	return false
 }
 
-func (e CompanyApprovalStatus) String() string {
+func (e BankTransactionDirection) String() string {
 	return string(e)
 }
 
-func (e *CompanyApprovalStatus) UnmarshalGQL(v any) error {
+func (e *BankTransactionDirection) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = CompanyApprovalStatus(str)
+	*e = BankTransactionDirection(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid CompanyApprovalStatus", str)
+		return fmt.Errorf("%s is not a valid BankTransactionDirection", str)
 	}
 	return nil
 }
 
-func (e CompanyApprovalStatus) MarshalGQL(w io.Writer) {
+func (e BankTransactionDirection) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *CompanyApprovalStatus) UnmarshalJSON(b []byte) error {
+func (e *BankTransactionDirection) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1172,58 +1740,707 @@ func (e *CompanyApprovalStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e CompanyApprovalStatus) MarshalJSON() ([]byte, error) {
+func (e BankTransactionDirection) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type DisputeResolutionType string
+type BookingFilter string
 
 const (
-	DisputeResolutionTypePartialRefund DisputeResolutionType = "PARTIAL_REFUND"
-	DisputeResolutionTypeFullRefund    DisputeResolutionType = "FULL_REFUND"
-	DisputeResolutionTypeReclean       DisputeResolutionType = "RECLEAN"
-	DisputeResolutionTypeRejected      DisputeResolutionType = "REJECTED"
+	BookingFilterAll      BookingFilter = "ALL"
+	BookingFilterUpcoming BookingFilter = "UPCOMING"
+	BookingFilterPast     BookingFilter = "PAST"
+	BookingFilterActive   BookingFilter = "ACTIVE"
 )
 
-var AllDisputeResolutionType = []DisputeResolutionType{
-	DisputeResolutionTypePartialRefund,
-	DisputeResolutionTypeFullRefund,
-	DisputeResolutionTypeReclean,
-	DisputeResolutionTypeRejected,
+var AllBookingFilter = []BookingFilter{
+	BookingFilterAll,
+	BookingFilterUpcoming,
+	BookingFilterPast,
+	BookingFilterActive,
+}
+
+func (e BookingFilter) IsValid() bool {
+	switch e {
+	case BookingFilterAll, BookingFilterUpcoming, BookingFilterPast, BookingFilterActive:
+		return true
+	}
+	return false
+}
+
+func (e BookingFilter) String() string {
+	return string(e)
+}
+
+func (e *BookingFilter) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BookingFilter(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BookingFilter", str)
+	}
+	return nil
+}
+
+func (e BookingFilter) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BookingFilter) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BookingFilter) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type BookingStatus string
+
+const (
+	BookingStatusPending       BookingStatus = "PENDING"
+	BookingStatusConfirmed     BookingStatus = "CONFIRMED"
+	BookingStatusInProgress    BookingStatus = "IN_PROGRESS"
+	BookingStatusCompleted     BookingStatus = "COMPLETED"
+	BookingStatusCancelled     BookingStatus = "CANCELLED"
+	BookingStatusDisputed      BookingStatus = "DISPUTED"
+	BookingStatusRefunded      BookingStatus = "REFUNDED"
+	BookingStatusNoShowClient  BookingStatus = "NO_SHOW_CLIENT"
+	BookingStatusNoShowCleaner BookingStatus = "NO_SHOW_CLEANER"
+)
+
+var AllBookingStatus = []BookingStatus{
+	BookingStatusPending,
+	BookingStatusConfirmed,
+	BookingStatusInProgress,
+	BookingStatusCompleted,
+	BookingStatusCancelled,
+	BookingStatusDisputed,
+	BookingStatusRefunded,
+	BookingStatusNoShowClient,
+	BookingStatusNoShowCleaner,
+}
+
+func (e BookingStatus) IsValid() bool {
+	switch e {
+	case BookingStatusPending, BookingStatusConfirmed, BookingStatusInProgress, BookingStatusCompleted, BookingStatusCancelled, BookingStatusDisputed, BookingStatusRefunded, BookingStatusNoShowClient, BookingStatusNoShowCleaner:
+		return true
+	}
+	return false
+}
+
+func (e BookingStatus) String() string {
+	return string(e)
+}
+
+func (e *BookingStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BookingStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BookingStatus", str)
+	}
+	return nil
+}
+
+func (e BookingStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BookingStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BookingStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type CommissionRecipientType string
+
+const (
+	CommissionRecipientTypeCleaner CommissionRecipientType = "CLEANER"
+	CommissionRecipientTypeCompany CommissionRecipientType = "COMPANY"
+)
+
+var AllCommissionRecipientType = []CommissionRecipientType{
+	CommissionRecipientTypeCleaner,
+	CommissionRecipientTypeCompany,
+}
+
+func (e CommissionRecipientType) IsValid() bool {
+	switch e {
+	case CommissionRecipientTypeCleaner, CommissionRecipientTypeCompany:
+		return true
+	}
+	return false
+}
+
+func (e CommissionRecipientType) String() string {
+	return string(e)
+}
+
+func (e *CommissionRecipientType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CommissionRecipientType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CommissionRecipientType", str)
+	}
+	return nil
+}
+
+func (e CommissionRecipientType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CommissionRecipientType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CommissionRecipientType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type CompanyApprovalStatus string
+
+const (
+	CompanyApprovalStatusPending  CompanyApprovalStatus = "PENDING"
+	CompanyApprovalStatusApproved CompanyApprovalStatus = "APPROVED"
+	CompanyApprovalStatusRejected CompanyApprovalStatus = "REJECTED"
+)
+
+var AllCompanyApprovalStatus = []CompanyApprovalStatus{
+	CompanyApprovalStatusPending,
+	CompanyApprovalStatusApproved,
+	CompanyApprovalStatusRejected,
+}
+
+func (e CompanyApprovalStatus) IsValid() bool {
+	switch e {
+	case CompanyApprovalStatusPending, CompanyApprovalStatusApproved, CompanyApprovalStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e CompanyApprovalStatus) String() string {
+	return string(e)
+}
+
+func (e *CompanyApprovalStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CompanyApprovalStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CompanyApprovalStatus", str)
+	}
+	return nil
+}
+
+func (e CompanyApprovalStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CompanyApprovalStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CompanyApprovalStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type CreditNoteType string
+
+const (
+	CreditNoteTypeFull    CreditNoteType = "FULL"
+	CreditNoteTypePartial CreditNoteType = "PARTIAL"
+)
+
+var AllCreditNoteType = []CreditNoteType{
+	CreditNoteTypeFull,
+	CreditNoteTypePartial,
+}
+
+func (e CreditNoteType) IsValid() bool {
+	switch e {
+	case CreditNoteTypeFull, CreditNoteTypePartial:
+		return true
+	}
+	return false
+}
+
+func (e CreditNoteType) String() string {
+	return string(e)
+}
+
+func (e *CreditNoteType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CreditNoteType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CreditNoteType", str)
+	}
+	return nil
+}
+
+func (e CreditNoteType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CreditNoteType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CreditNoteType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DisputeResolutionType string
+
+const (
+	DisputeResolutionTypePartialRefund  DisputeResolutionType = "PARTIAL_REFUND"
+	DisputeResolutionTypeFullRefund     DisputeResolutionType = "FULL_REFUND"
+	DisputeResolutionTypeRefundToCredit DisputeResolutionType = "REFUND_TO_CREDIT"
+	DisputeResolutionTypeReclean        DisputeResolutionType = "RECLEAN"
+	DisputeResolutionTypeRejected       DisputeResolutionType = "REJECTED"
+)
+
+var AllDisputeResolutionType = []DisputeResolutionType{
+	DisputeResolutionTypePartialRefund,
+	DisputeResolutionTypeFullRefund,
+	DisputeResolutionTypeRefundToCredit,
+	DisputeResolutionTypeReclean,
+	DisputeResolutionTypeRejected,
 }
 
 func (e DisputeResolutionType) IsValid() bool {
 	switch e {
-	case DisputeResolutionTypePartialRefund, DisputeResolutionTypeFullRefund, DisputeResolutionTypeReclean, DisputeResolutionTypeRejected:
+	case DisputeResolutionTypePartialRefund, DisputeResolutionTypeFullRefund, DisputeResolutionTypeRefundToCredit, DisputeResolutionTypeReclean, DisputeResolutionTypeRejected:
+		return true
+	}
+	return false
+}
+
+func (e DisputeResolutionType) String() string {
+	return string(e)
+}
+
+func (e *DisputeResolutionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DisputeResolutionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DisputeResolutionType", str)
+	}
+	return nil
+}
+
+func (e DisputeResolutionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DisputeResolutionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DisputeResolutionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen        DisputeStatus = "OPEN"
+	DisputeStatusUnderReview DisputeStatus = "UNDER_REVIEW"
+	DisputeStatusResolved    DisputeStatus = "RESOLVED"
+	DisputeStatusClosed      DisputeStatus = "CLOSED"
+)
+
+var AllDisputeStatus = []DisputeStatus{
+	DisputeStatusOpen,
+	DisputeStatusUnderReview,
+	DisputeStatusResolved,
+	DisputeStatusClosed,
+}
+
+func (e DisputeStatus) IsValid() bool {
+	switch e {
+	case DisputeStatusOpen, DisputeStatusUnderReview, DisputeStatusResolved, DisputeStatusClosed:
+		return true
+	}
+	return false
+}
+
+func (e DisputeStatus) String() string {
+	return string(e)
+}
+
+func (e *DisputeStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DisputeStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DisputeStatus", str)
+	}
+	return nil
+}
+
+func (e DisputeStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DisputeStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DisputeStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DisputeType string
+
+const (
+	DisputeTypeQualityIssue DisputeType = "QUALITY_ISSUE"
+	DisputeTypeDamage       DisputeType = "DAMAGE"
+	DisputeTypeNoShow       DisputeType = "NO_SHOW"
+	DisputeTypePricing      DisputeType = "PRICING"
+	DisputeTypeOther        DisputeType = "OTHER"
+)
+
+var AllDisputeType = []DisputeType{
+	DisputeTypeQualityIssue,
+	DisputeTypeDamage,
+	DisputeTypeNoShow,
+	DisputeTypePricing,
+	DisputeTypeOther,
+}
+
+func (e DisputeType) IsValid() bool {
+	switch e {
+	case DisputeTypeQualityIssue, DisputeTypeDamage, DisputeTypeNoShow, DisputeTypePricing, DisputeTypeOther:
+		return true
+	}
+	return false
+}
+
+func (e DisputeType) String() string {
+	return string(e)
+}
+
+func (e *DisputeType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DisputeType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DisputeType", str)
+	}
+	return nil
+}
+
+func (e DisputeType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DisputeType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DisputeType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DocumentType string
+
+const (
+	DocumentTypeInvoice           DocumentType = "INVOICE"
+	DocumentTypeCreditNote        DocumentType = "CREDIT_NOTE"
+	DocumentTypeCommissionInvoice DocumentType = "COMMISSION_INVOICE"
+	DocumentTypeProforma          DocumentType = "PROFORMA"
+)
+
+var AllDocumentType = []DocumentType{
+	DocumentTypeInvoice,
+	DocumentTypeCreditNote,
+	DocumentTypeCommissionInvoice,
+	DocumentTypeProforma,
+}
+
+func (e DocumentType) IsValid() bool {
+	switch e {
+	case DocumentTypeInvoice, DocumentTypeCreditNote, DocumentTypeCommissionInvoice, DocumentTypeProforma:
+		return true
+	}
+	return false
+}
+
+func (e DocumentType) String() string {
+	return string(e)
+}
+
+func (e *DocumentType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DocumentType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DocumentType", str)
+	}
+	return nil
+}
+
+func (e DocumentType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DocumentType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DocumentType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type InvoiceEmailStatus string
+
+const (
+	InvoiceEmailStatusNotSent InvoiceEmailStatus = "NOT_SENT"
+	InvoiceEmailStatusSent    InvoiceEmailStatus = "SENT"
+	InvoiceEmailStatusFailed  InvoiceEmailStatus = "FAILED"
+)
+
+var AllInvoiceEmailStatus = []InvoiceEmailStatus{
+	InvoiceEmailStatusNotSent,
+	InvoiceEmailStatusSent,
+	InvoiceEmailStatusFailed,
+}
+
+func (e InvoiceEmailStatus) IsValid() bool {
+	switch e {
+	case InvoiceEmailStatusNotSent, InvoiceEmailStatusSent, InvoiceEmailStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e InvoiceEmailStatus) String() string {
+	return string(e)
+}
+
+func (e *InvoiceEmailStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvoiceEmailStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvoiceEmailStatus", str)
+	}
+	return nil
+}
+
+func (e InvoiceEmailStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InvoiceEmailStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InvoiceEmailStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft     InvoiceStatus = "DRAFT"
+	InvoiceStatusIssued    InvoiceStatus = "ISSUED"
+	InvoiceStatusPaid      InvoiceStatus = "PAID"
+	InvoiceStatusCancelled InvoiceStatus = "CANCELLED"
+)
+
+var AllInvoiceStatus = []InvoiceStatus{
+	InvoiceStatusDraft,
+	InvoiceStatusIssued,
+	InvoiceStatusPaid,
+	InvoiceStatusCancelled,
+}
+
+func (e InvoiceStatus) IsValid() bool {
+	switch e {
+	case InvoiceStatusDraft, InvoiceStatusIssued, InvoiceStatusPaid, InvoiceStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e InvoiceStatus) String() string {
+	return string(e)
+}
+
+func (e *InvoiceStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = InvoiceStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid InvoiceStatus", str)
+	}
+	return nil
+}
+
+func (e InvoiceStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *InvoiceStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e InvoiceStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type KPIPeriod string
+
+const (
+	KPIPeriodToday KPIPeriod = "TODAY"
+	KPIPeriodWeek  KPIPeriod = "WEEK"
+	KPIPeriodMonth KPIPeriod = "MONTH"
+)
+
+var AllKPIPeriod = []KPIPeriod{
+	KPIPeriodToday,
+	KPIPeriodWeek,
+	KPIPeriodMonth,
+}
+
+func (e KPIPeriod) IsValid() bool {
+	switch e {
+	case KPIPeriodToday, KPIPeriodWeek, KPIPeriodMonth:
 		return true
 	}
 	return false
 }
 
-func (e DisputeResolutionType) String() string {
+func (e KPIPeriod) String() string {
 	return string(e)
 }
 
-func (e *DisputeResolutionType) UnmarshalGQL(v any) error {
+func (e *KPIPeriod) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = DisputeResolutionType(str)
+	*e = KPIPeriod(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid DisputeResolutionType", str)
+		return fmt.Errorf("%s is not a valid KPIPeriod", str)
 	}
 	return nil
 }
 
-func (e DisputeResolutionType) MarshalGQL(w io.Writer) {
+func (e KPIPeriod) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *DisputeResolutionType) UnmarshalJSON(b []byte) error {
+func (e *KPIPeriod) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1231,58 +2448,64 @@ func (e *DisputeResolutionType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e DisputeResolutionType) MarshalJSON() ([]byte, error) {
+func (e KPIPeriod) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type DisputeStatus string
+type LedgerAccount string
 
 const (
-	DisputeStatusOpen        DisputeStatus = "OPEN"
-	DisputeStatusUnderReview DisputeStatus = "UNDER_REVIEW"
-	DisputeStatusResolved    DisputeStatus = "RESOLVED"
-	DisputeStatusClosed      DisputeStatus = "CLOSED"
+	LedgerAccountClientReceivables LedgerAccount = "CLIENT_RECEIVABLES"
+	LedgerAccountCleanerPayables   LedgerAccount = "CLEANER_PAYABLES"
+	LedgerAccountPlatformRevenue   LedgerAccount = "PLATFORM_REVENUE"
+	LedgerAccountRefunds           LedgerAccount = "REFUNDS"
+	LedgerAccountVatPayable        LedgerAccount = "VAT_PAYABLE"
+	LedgerAccountCash              LedgerAccount = "CASH"
+	LedgerAccountClientCredits     LedgerAccount = "CLIENT_CREDITS"
 )
 
-var AllDisputeStatus = []DisputeStatus{
-	DisputeStatusOpen,
-	DisputeStatusUnderReview,
-	DisputeStatusResolved,
-	DisputeStatusClosed,
+var AllLedgerAccount = []LedgerAccount{
+	LedgerAccountClientReceivables,
+	LedgerAccountCleanerPayables,
+	LedgerAccountPlatformRevenue,
+	LedgerAccountRefunds,
+	LedgerAccountVatPayable,
+	LedgerAccountCash,
+	LedgerAccountClientCredits,
 }
 
-func (e DisputeStatus) IsValid() bool {
+func (e LedgerAccount) IsValid() bool {
 	switch e {
-	case DisputeStatusOpen, DisputeStatusUnderReview, DisputeStatusResolved, DisputeStatusClosed:
+	case LedgerAccountClientReceivables, LedgerAccountCleanerPayables, LedgerAccountPlatformRevenue, LedgerAccountRefunds, LedgerAccountVatPayable, LedgerAccountCash, LedgerAccountClientCredits:
 		return true
 	}
 	return false
 }
 
-func (e DisputeStatus) String() string {
+func (e LedgerAccount) String() string {
 	return string(e)
 }
 
-func (e *DisputeStatus) UnmarshalGQL(v any) error {
+func (e *LedgerAccount) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = DisputeStatus(str)
+	*e = LedgerAccount(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid DisputeStatus", str)
+		return fmt.Errorf("%s is not a valid LedgerAccount", str)
 	}
 	return nil
 }
 
-func (e DisputeStatus) MarshalGQL(w io.Writer) {
+func (e LedgerAccount) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *DisputeStatus) UnmarshalJSON(b []byte) error {
+func (e *LedgerAccount) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1290,60 +2513,298 @@ func (e *DisputeStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e DisputeStatus) MarshalJSON() ([]byte, error) {
+func (e LedgerAccount) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type DisputeType string
+type PaymentFileFormat string
 
 const (
-	DisputeTypeQualityIssue DisputeType = "QUALITY_ISSUE"
-	DisputeTypeDamage       DisputeType = "DAMAGE"
-	DisputeTypeNoShow       DisputeType = "NO_SHOW"
-	DisputeTypePricing      DisputeType = "PRICING"
-	DisputeTypeOther        DisputeType = "OTHER"
+	PaymentFileFormatPain001 PaymentFileFormat = "PAIN001"
+	PaymentFileFormatBtCSV   PaymentFileFormat = "BT_CSV"
+	PaymentFileFormatBcrCSV  PaymentFileFormat = "BCR_CSV"
 )
 
-var AllDisputeType = []DisputeType{
-	DisputeTypeQualityIssue,
-	DisputeTypeDamage,
-	DisputeTypeNoShow,
-	DisputeTypePricing,
-	DisputeTypeOther,
+var AllPaymentFileFormat = []PaymentFileFormat{
+	PaymentFileFormatPain001,
+	PaymentFileFormatBtCSV,
+	PaymentFileFormatBcrCSV,
+}
+
+func (e PaymentFileFormat) IsValid() bool {
+	switch e {
+	case PaymentFileFormatPain001, PaymentFileFormatBtCSV, PaymentFileFormatBcrCSV:
+		return true
+	}
+	return false
+}
+
+func (e PaymentFileFormat) String() string {
+	return string(e)
+}
+
+func (e *PaymentFileFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentFileFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentFileFormat", str)
+	}
+	return nil
+}
+
+func (e PaymentFileFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaymentFileFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaymentFileFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PaymentProvider string
+
+const (
+	PaymentProviderNetopia      PaymentProvider = "NETOPIA"
+	PaymentProviderManual       PaymentProvider = "MANUAL"
+	PaymentProviderCash         PaymentProvider = "CASH"
+	PaymentProviderBankTransfer PaymentProvider = "BANK_TRANSFER"
+)
+
+var AllPaymentProvider = []PaymentProvider{
+	PaymentProviderNetopia,
+	PaymentProviderManual,
+	PaymentProviderCash,
+	PaymentProviderBankTransfer,
+}
+
+func (e PaymentProvider) IsValid() bool {
+	switch e {
+	case PaymentProviderNetopia, PaymentProviderManual, PaymentProviderCash, PaymentProviderBankTransfer:
+		return true
+	}
+	return false
+}
+
+func (e PaymentProvider) String() string {
+	return string(e)
+}
+
+func (e *PaymentProvider) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentProvider(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentProvider", str)
+	}
+	return nil
+}
+
+func (e PaymentProvider) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaymentProvider) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaymentProvider) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "PENDING"
+	PaymentStatusAuthorized PaymentStatus = "AUTHORIZED"
+	PaymentStatusCaptured   PaymentStatus = "CAPTURED"
+	PaymentStatusFailed     PaymentStatus = "FAILED"
+	PaymentStatusRefunded   PaymentStatus = "REFUNDED"
+	PaymentStatusCancelled  PaymentStatus = "CANCELLED"
+)
+
+var AllPaymentStatus = []PaymentStatus{
+	PaymentStatusPending,
+	PaymentStatusAuthorized,
+	PaymentStatusCaptured,
+	PaymentStatusFailed,
+	PaymentStatusRefunded,
+	PaymentStatusCancelled,
+}
+
+func (e PaymentStatus) IsValid() bool {
+	switch e {
+	case PaymentStatusPending, PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusFailed, PaymentStatusRefunded, PaymentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e PaymentStatus) String() string {
+	return string(e)
+}
+
+func (e *PaymentStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentStatus", str)
+	}
+	return nil
+}
+
+func (e PaymentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaymentStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaymentStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PaymentType string
+
+const (
+	PaymentTypePreauthorization PaymentType = "PREAUTHORIZATION"
+	PaymentTypeCapture          PaymentType = "CAPTURE"
+	PaymentTypeRefund           PaymentType = "REFUND"
+	PaymentTypeCancellation     PaymentType = "CANCELLATION"
+	PaymentTypeTip              PaymentType = "TIP"
+)
+
+var AllPaymentType = []PaymentType{
+	PaymentTypePreauthorization,
+	PaymentTypeCapture,
+	PaymentTypeRefund,
+	PaymentTypeCancellation,
+	PaymentTypeTip,
+}
+
+func (e PaymentType) IsValid() bool {
+	switch e {
+	case PaymentTypePreauthorization, PaymentTypeCapture, PaymentTypeRefund, PaymentTypeCancellation, PaymentTypeTip:
+		return true
+	}
+	return false
+}
+
+func (e PaymentType) String() string {
+	return string(e)
+}
+
+func (e *PaymentType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentType", str)
+	}
+	return nil
+}
+
+func (e PaymentType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaymentType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaymentType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PayoutAdjustmentType string
+
+const (
+	PayoutAdjustmentTypeClawback     PayoutAdjustmentType = "CLAWBACK"
+	PayoutAdjustmentTypeBonus        PayoutAdjustmentType = "BONUS"
+	PayoutAdjustmentTypeCorrection   PayoutAdjustmentType = "CORRECTION"
+	PayoutAdjustmentTypeCarryForward PayoutAdjustmentType = "CARRY_FORWARD"
+)
+
+var AllPayoutAdjustmentType = []PayoutAdjustmentType{
+	PayoutAdjustmentTypeClawback,
+	PayoutAdjustmentTypeBonus,
+	PayoutAdjustmentTypeCorrection,
+	PayoutAdjustmentTypeCarryForward,
 }
 
-func (e DisputeType) IsValid() bool {
+func (e PayoutAdjustmentType) IsValid() bool {
 	switch e {
-	case DisputeTypeQualityIssue, DisputeTypeDamage, DisputeTypeNoShow, DisputeTypePricing, DisputeTypeOther:
+	case PayoutAdjustmentTypeClawback, PayoutAdjustmentTypeBonus, PayoutAdjustmentTypeCorrection, PayoutAdjustmentTypeCarryForward:
 		return true
 	}
 	return false
 }
 
-func (e DisputeType) String() string {
+func (e PayoutAdjustmentType) String() string {
 	return string(e)
 }
 
-func (e *DisputeType) UnmarshalGQL(v any) error {
+func (e *PayoutAdjustmentType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = DisputeType(str)
+	*e = PayoutAdjustmentType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid DisputeType", str)
+		return fmt.Errorf("%s is not a valid PayoutAdjustmentType", str)
 	}
 	return nil
 }
 
-func (e DisputeType) MarshalGQL(w io.Writer) {
+func (e PayoutAdjustmentType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *DisputeType) UnmarshalJSON(b []byte) error {
+func (e *PayoutAdjustmentType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1351,58 +2812,54 @@ func (e *DisputeType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e DisputeType) MarshalJSON() ([]byte, error) {
+func (e PayoutAdjustmentType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type InvoiceStatus string
+type PayoutBatchStatus string
 
 const (
-	InvoiceStatusDraft     InvoiceStatus = "DRAFT"
-	InvoiceStatusIssued    InvoiceStatus = "ISSUED"
-	InvoiceStatusPaid      InvoiceStatus = "PAID"
-	InvoiceStatusCancelled InvoiceStatus = "CANCELLED"
+	PayoutBatchStatusProcessing PayoutBatchStatus = "PROCESSING"
+	PayoutBatchStatusSent       PayoutBatchStatus = "SENT"
 )
 
-var AllInvoiceStatus = []InvoiceStatus{
-	InvoiceStatusDraft,
-	InvoiceStatusIssued,
-	InvoiceStatusPaid,
-	InvoiceStatusCancelled,
+var AllPayoutBatchStatus = []PayoutBatchStatus{
+	PayoutBatchStatusProcessing,
+	PayoutBatchStatusSent,
 }
 
-func (e InvoiceStatus) IsValid() bool {
+func (e PayoutBatchStatus) IsValid() bool {
 	switch e {
-	case InvoiceStatusDraft, InvoiceStatusIssued, InvoiceStatusPaid, InvoiceStatusCancelled:
+	case PayoutBatchStatusProcessing, PayoutBatchStatusSent:
 		return true
 	}
 	return false
 }
 
-func (e InvoiceStatus) String() string {
+func (e PayoutBatchStatus) String() string {
 	return string(e)
 }
 
-func (e *InvoiceStatus) UnmarshalGQL(v any) error {
+func (e *PayoutBatchStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = InvoiceStatus(str)
+	*e = PayoutBatchStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid InvoiceStatus", str)
+		return fmt.Errorf("%s is not a valid PayoutBatchStatus", str)
 	}
 	return nil
 }
 
-func (e InvoiceStatus) MarshalGQL(w io.Writer) {
+func (e PayoutBatchStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *InvoiceStatus) UnmarshalJSON(b []byte) error {
+func (e *PayoutBatchStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1410,56 +2867,60 @@ func (e *InvoiceStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e InvoiceStatus) MarshalJSON() ([]byte, error) {
+func (e PayoutBatchStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type KPIPeriod string
+type PayoutLineItemType string
 
 const (
-	KPIPeriodToday KPIPeriod = "TODAY"
-	KPIPeriodWeek  KPIPeriod = "WEEK"
-	KPIPeriodMonth KPIPeriod = "MONTH"
+	PayoutLineItemTypeBooking      PayoutLineItemType = "BOOKING"
+	PayoutLineItemTypeTip          PayoutLineItemType = "TIP"
+	PayoutLineItemTypeCashBooking  PayoutLineItemType = "CASH_BOOKING"
+	PayoutLineItemTypeAdjustment   PayoutLineItemType = "ADJUSTMENT"
+	PayoutLineItemTypeCarryForward PayoutLineItemType = "CARRY_FORWARD"
 )
 
-var AllKPIPeriod = []KPIPeriod{
-	KPIPeriodToday,
-	KPIPeriodWeek,
-	KPIPeriodMonth,
+var AllPayoutLineItemType = []PayoutLineItemType{
+	PayoutLineItemTypeBooking,
+	PayoutLineItemTypeTip,
+	PayoutLineItemTypeCashBooking,
+	PayoutLineItemTypeAdjustment,
+	PayoutLineItemTypeCarryForward,
 }
 
-func (e KPIPeriod) IsValid() bool {
+func (e PayoutLineItemType) IsValid() bool {
 	switch e {
-	case KPIPeriodToday, KPIPeriodWeek, KPIPeriodMonth:
+	case PayoutLineItemTypeBooking, PayoutLineItemTypeTip, PayoutLineItemTypeCashBooking, PayoutLineItemTypeAdjustment, PayoutLineItemTypeCarryForward:
 		return true
 	}
 	return false
 }
 
-func (e KPIPeriod) String() string {
+func (e PayoutLineItemType) String() string {
 	return string(e)
 }
 
-func (e *KPIPeriod) UnmarshalGQL(v any) error {
+func (e *PayoutLineItemType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = KPIPeriod(str)
+	*e = PayoutLineItemType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid KPIPeriod", str)
+		return fmt.Errorf("%s is not a valid PayoutLineItemType", str)
 	}
 	return nil
 }
 
-func (e KPIPeriod) MarshalGQL(w io.Writer) {
+func (e PayoutLineItemType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *KPIPeriod) UnmarshalJSON(b []byte) error {
+func (e *PayoutLineItemType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1467,54 +2928,62 @@ func (e *KPIPeriod) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e KPIPeriod) MarshalJSON() ([]byte, error) {
+func (e PayoutLineItemType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PaymentProvider string
+type PayoutStatus string
 
 const (
-	PaymentProviderNetopia PaymentProvider = "NETOPIA"
-	PaymentProviderManual  PaymentProvider = "MANUAL"
+	PayoutStatusPending    PayoutStatus = "PENDING"
+	PayoutStatusProcessing PayoutStatus = "PROCESSING"
+	PayoutStatusSent       PayoutStatus = "SENT"
+	PayoutStatusFailed     PayoutStatus = "FAILED"
+	PayoutStatusCancelled  PayoutStatus = "CANCELLED"
+	PayoutStatusInvoiced   PayoutStatus = "INVOICED"
 )
 
-var AllPaymentProvider = []PaymentProvider{
-	PaymentProviderNetopia,
-	PaymentProviderManual,
+var AllPayoutStatus = []PayoutStatus{
+	PayoutStatusPending,
+	PayoutStatusProcessing,
+	PayoutStatusSent,
+	PayoutStatusFailed,
+	PayoutStatusCancelled,
+	PayoutStatusInvoiced,
 }
 
-func (e PaymentProvider) IsValid() bool {
+func (e PayoutStatus) IsValid() bool {
 	switch e {
-	case PaymentProviderNetopia, PaymentProviderManual:
+	case PayoutStatusPending, PayoutStatusProcessing, PayoutStatusSent, PayoutStatusFailed, PayoutStatusCancelled, PayoutStatusInvoiced:
 		return true
 	}
 	return false
 }
 
-func (e PaymentProvider) String() string {
+func (e PayoutStatus) String() string {
 	return string(e)
 }
 
-func (e *PaymentProvider) UnmarshalGQL(v any) error {
+func (e *PayoutStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PaymentProvider(str)
+	*e = PayoutStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PaymentProvider", str)
+		return fmt.Errorf("%s is not a valid PayoutStatus", str)
 	}
 	return nil
 }
 
-func (e PaymentProvider) MarshalGQL(w io.Writer) {
+func (e PayoutStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PaymentProvider) UnmarshalJSON(b []byte) error {
+func (e *PayoutStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1522,62 +2991,56 @@ func (e *PaymentProvider) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PaymentProvider) MarshalJSON() ([]byte, error) {
+func (e PayoutStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PaymentStatus string
+type PhotoType string
 
 const (
-	PaymentStatusPending    PaymentStatus = "PENDING"
-	PaymentStatusAuthorized PaymentStatus = "AUTHORIZED"
-	PaymentStatusCaptured   PaymentStatus = "CAPTURED"
-	PaymentStatusFailed     PaymentStatus = "FAILED"
-	PaymentStatusRefunded   PaymentStatus = "REFUNDED"
-	PaymentStatusCancelled  PaymentStatus = "CANCELLED"
+	PhotoTypeBefore          PhotoType = "BEFORE"
+	PhotoTypeAfter           PhotoType = "AFTER"
+	PhotoTypeDisputeEvidence PhotoType = "DISPUTE_EVIDENCE"
 )
 
-var AllPaymentStatus = []PaymentStatus{
-	PaymentStatusPending,
-	PaymentStatusAuthorized,
-	PaymentStatusCaptured,
-	PaymentStatusFailed,
-	PaymentStatusRefunded,
-	PaymentStatusCancelled,
+var AllPhotoType = []PhotoType{
+	PhotoTypeBefore,
+	PhotoTypeAfter,
+	PhotoTypeDisputeEvidence,
 }
 
-func (e PaymentStatus) IsValid() bool {
+func (e PhotoType) IsValid() bool {
 	switch e {
-	case PaymentStatusPending, PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusFailed, PaymentStatusRefunded, PaymentStatusCancelled:
+	case PhotoTypeBefore, PhotoTypeAfter, PhotoTypeDisputeEvidence:
 		return true
 	}
 	return false
 }
 
-func (e PaymentStatus) String() string {
+func (e PhotoType) String() string {
 	return string(e)
 }
 
-func (e *PaymentStatus) UnmarshalGQL(v any) error {
+func (e *PhotoType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PaymentStatus(str)
+	*e = PhotoType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PaymentStatus", str)
+		return fmt.Errorf("%s is not a valid PhotoType", str)
 	}
 	return nil
 }
 
-func (e PaymentStatus) MarshalGQL(w io.Writer) {
+func (e PhotoType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PaymentStatus) UnmarshalJSON(b []byte) error {
+func (e *PhotoType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1585,58 +3048,58 @@ func (e *PaymentStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PaymentStatus) MarshalJSON() ([]byte, error) {
+func (e PhotoType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PaymentType string
+type PlatformFeeRule string
 
 const (
-	PaymentTypePreauthorization PaymentType = "PREAUTHORIZATION"
-	PaymentTypeCapture          PaymentType = "CAPTURE"
-	PaymentTypeRefund           PaymentType = "REFUND"
-	PaymentTypeCancellation     PaymentType = "CANCELLATION"
+	PlatformFeeRuleDefault         PlatformFeeRule = "DEFAULT"
+	PlatformFeeRuleRepeatClient    PlatformFeeRule = "REPEAT_CLIENT"
+	PlatformFeeRuleCleanerTier     PlatformFeeRule = "CLEANER_TIER"
+	PlatformFeeRuleCompanyContract PlatformFeeRule = "COMPANY_CONTRACT"
 )
 
-var AllPaymentType = []PaymentType{
-	PaymentTypePreauthorization,
-	PaymentTypeCapture,
-	PaymentTypeRefund,
-	PaymentTypeCancellation,
+var AllPlatformFeeRule = []PlatformFeeRule{
+	PlatformFeeRuleDefault,
+	PlatformFeeRuleRepeatClient,
+	PlatformFeeRuleCleanerTier,
+	PlatformFeeRuleCompanyContract,
 }
 
-func (e PaymentType) IsValid() bool {
+func (e PlatformFeeRule) IsValid() bool {
 	switch e {
-	case PaymentTypePreauthorization, PaymentTypeCapture, PaymentTypeRefund, PaymentTypeCancellation:
+	case PlatformFeeRuleDefault, PlatformFeeRuleRepeatClient, PlatformFeeRuleCleanerTier, PlatformFeeRuleCompanyContract:
 		return true
 	}
 	return false
 }
 
-func (e PaymentType) String() string {
+func (e PlatformFeeRule) String() string {
 	return string(e)
 }
 
-func (e *PaymentType) UnmarshalGQL(v any) error {
+func (e *PlatformFeeRule) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PaymentType(str)
+	*e = PlatformFeeRule(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PaymentType", str)
+		return fmt.Errorf("%s is not a valid PlatformFeeRule", str)
 	}
 	return nil
 }
 
-func (e PaymentType) MarshalGQL(w io.Writer) {
+func (e PlatformFeeRule) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PaymentType) UnmarshalJSON(b []byte) error {
+func (e *PlatformFeeRule) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1644,60 +3107,56 @@ func (e *PaymentType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PaymentType) MarshalJSON() ([]byte, error) {
+func (e PlatformFeeRule) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PayoutStatus string
+type ProformaStatus string
 
 const (
-	PayoutStatusPending    PayoutStatus = "PENDING"
-	PayoutStatusProcessing PayoutStatus = "PROCESSING"
-	PayoutStatusSent       PayoutStatus = "SENT"
-	PayoutStatusFailed     PayoutStatus = "FAILED"
-	PayoutStatusCancelled  PayoutStatus = "CANCELLED"
+	ProformaStatusIssued    ProformaStatus = "ISSUED"
+	ProformaStatusPaid      ProformaStatus = "PAID"
+	ProformaStatusCancelled ProformaStatus = "CANCELLED"
 )
 
-var AllPayoutStatus = []PayoutStatus{
-	PayoutStatusPending,
-	PayoutStatusProcessing,
-	PayoutStatusSent,
-	PayoutStatusFailed,
-	PayoutStatusCancelled,
+var AllProformaStatus = []ProformaStatus{
+	ProformaStatusIssued,
+	ProformaStatusPaid,
+	ProformaStatusCancelled,
 }
 
-func (e PayoutStatus) IsValid() bool {
+func (e ProformaStatus) IsValid() bool {
 	switch e {
-	case PayoutStatusPending, PayoutStatusProcessing, PayoutStatusSent, PayoutStatusFailed, PayoutStatusCancelled:
+	case ProformaStatusIssued, ProformaStatusPaid, ProformaStatusCancelled:
 		return true
 	}
 	return false
 }
 
-func (e PayoutStatus) String() string {
+func (e ProformaStatus) String() string {
 	return string(e)
 }
 
-func (e *PayoutStatus) UnmarshalGQL(v any) error {
+func (e *ProformaStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PayoutStatus(str)
+	*e = ProformaStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PayoutStatus", str)
+		return fmt.Errorf("%s is not a valid ProformaStatus", str)
 	}
 	return nil
 }
 
-func (e PayoutStatus) MarshalGQL(w io.Writer) {
+func (e ProformaStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PayoutStatus) UnmarshalJSON(b []byte) error {
+func (e *ProformaStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1705,56 +3164,62 @@ func (e *PayoutStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PayoutStatus) MarshalJSON() ([]byte, error) {
+func (e ProformaStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type PhotoType string
+type RefundStatus string
 
 const (
-	PhotoTypeBefore          PhotoType = "BEFORE"
-	PhotoTypeAfter           PhotoType = "AFTER"
-	PhotoTypeDisputeEvidence PhotoType = "DISPUTE_EVIDENCE"
+	RefundStatusNoPayment         RefundStatus = "NO_PAYMENT"
+	RefundStatusHoldReleased      RefundStatus = "HOLD_RELEASED"
+	RefundStatusRefunded          RefundStatus = "REFUNDED"
+	RefundStatusPartiallyRefunded RefundStatus = "PARTIALLY_REFUNDED"
+	RefundStatusNotEligible       RefundStatus = "NOT_ELIGIBLE"
+	RefundStatusFailed            RefundStatus = "FAILED"
 )
 
-var AllPhotoType = []PhotoType{
-	PhotoTypeBefore,
-	PhotoTypeAfter,
-	PhotoTypeDisputeEvidence,
+var AllRefundStatus = []RefundStatus{
+	RefundStatusNoPayment,
+	RefundStatusHoldReleased,
+	RefundStatusRefunded,
+	RefundStatusPartiallyRefunded,
+	RefundStatusNotEligible,
+	RefundStatusFailed,
 }
 
-func (e PhotoType) IsValid() bool {
+func (e RefundStatus) IsValid() bool {
 	switch e {
-	case PhotoTypeBefore, PhotoTypeAfter, PhotoTypeDisputeEvidence:
+	case RefundStatusNoPayment, RefundStatusHoldReleased, RefundStatusRefunded, RefundStatusPartiallyRefunded, RefundStatusNotEligible, RefundStatusFailed:
 		return true
 	}
 	return false
 }
 
-func (e PhotoType) String() string {
+func (e RefundStatus) String() string {
 	return string(e)
 }
 
-func (e *PhotoType) UnmarshalGQL(v any) error {
+func (e *RefundStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = PhotoType(str)
+	*e = RefundStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid PhotoType", str)
+		return fmt.Errorf("%s is not a valid RefundStatus", str)
 	}
 	return nil
 }
 
-func (e PhotoType) MarshalGQL(w io.Writer) {
+func (e RefundStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *PhotoType) UnmarshalJSON(b []byte) error {
+func (e *RefundStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -1762,7 +3227,7 @@ func (e *PhotoType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e PhotoType) MarshalJSON() ([]byte, error) {
+func (e RefundStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -1942,3 +3407,125 @@ func (e UserRole) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type WalletCreditSource string
+
+const (
+	WalletCreditSourceReferral WalletCreditSource = "REFERRAL"
+	WalletCreditSourceGoodwill WalletCreditSource = "GOODWILL"
+	WalletCreditSourceGiftCard WalletCreditSource = "GIFT_CARD"
+)
+
+var AllWalletCreditSource = []WalletCreditSource{
+	WalletCreditSourceReferral,
+	WalletCreditSourceGoodwill,
+	WalletCreditSourceGiftCard,
+}
+
+func (e WalletCreditSource) IsValid() bool {
+	switch e {
+	case WalletCreditSourceReferral, WalletCreditSourceGoodwill, WalletCreditSourceGiftCard:
+		return true
+	}
+	return false
+}
+
+func (e WalletCreditSource) String() string {
+	return string(e)
+}
+
+func (e *WalletCreditSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WalletCreditSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WalletCreditSource", str)
+	}
+	return nil
+}
+
+func (e WalletCreditSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WalletCreditSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WalletCreditSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WalletTransactionSource string
+
+const (
+	WalletTransactionSourceReferral            WalletTransactionSource = "REFERRAL"
+	WalletTransactionSourceGoodwill            WalletTransactionSource = "GOODWILL"
+	WalletTransactionSourceDisputeRefund       WalletTransactionSource = "DISPUTE_REFUND"
+	WalletTransactionSourceGiftCard            WalletTransactionSource = "GIFT_CARD"
+	WalletTransactionSourceBookingPayment      WalletTransactionSource = "BOOKING_PAYMENT"
+	WalletTransactionSourceBookingCancellation WalletTransactionSource = "BOOKING_CANCELLATION"
+	WalletTransactionSourceExpiry              WalletTransactionSource = "EXPIRY"
+)
+
+var AllWalletTransactionSource = []WalletTransactionSource{
+	WalletTransactionSourceReferral,
+	WalletTransactionSourceGoodwill,
+	WalletTransactionSourceDisputeRefund,
+	WalletTransactionSourceGiftCard,
+	WalletTransactionSourceBookingPayment,
+	WalletTransactionSourceBookingCancellation,
+	WalletTransactionSourceExpiry,
+}
+
+func (e WalletTransactionSource) IsValid() bool {
+	switch e {
+	case WalletTransactionSourceReferral, WalletTransactionSourceGoodwill, WalletTransactionSourceDisputeRefund, WalletTransactionSourceGiftCard, WalletTransactionSourceBookingPayment, WalletTransactionSourceBookingCancellation, WalletTransactionSourceExpiry:
+		return true
+	}
+	return false
+}
+
+func (e WalletTransactionSource) String() string {
+	return string(e)
+}
+
+func (e *WalletTransactionSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WalletTransactionSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WalletTransactionSource", str)
+	}
+	return nil
+}
+
+func (e WalletTransactionSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WalletTransactionSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WalletTransactionSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -12,6 +12,7 @@ type Resolver struct{
 	AuthService                  *services.AuthService
 	ClientService                *services.ClientService
 	AddressService               *services.AddressService
+	BillingProfileService        *services.BillingProfileService
 	CleanerService               *services.CleanerService
 	BookingService               *services.BookingService
 	PricingService               *services.PricingService
@@ -28,4 +29,18 @@ type Resolver struct{
 	PlatformSettingsService      *services.PlatformSettingsService
 	MessagingService             *services.MessagingService
 	CleanerApplicationService    *services.CleanerApplicationService
+	IdempotencyService           *services.IdempotencyService
+	LedgerService                *services.LedgerService
+	BankReconciliationService    *services.BankReconciliationService
+	WalletService                *services.WalletService
+	TipService                   *services.TipService
+	PayoutAdjustmentService      *services.PayoutAdjustmentService
+	PayoutBatchService           *services.PayoutBatchService
+	SelfBillingService           *services.SelfBillingService
+	CommissionInvoiceService     *services.CommissionInvoiceService
+	CreditNoteService            *services.CreditNoteService
+	ANAFWorker                   *services.ANAFWorker
+	InvoiceSeriesService         *services.InvoiceSeriesService
+	AccountingExportService      *services.AccountingExportService
+	ProformaService              *services.ProformaService
 }
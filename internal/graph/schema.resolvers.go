@@ -6,7 +6,9 @@ package graph
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -14,6 +16,7 @@ import (
 	"github.com/cleanbuddy/backend/internal/graph/model"
 	"github.com/cleanbuddy/backend/internal/middleware"
 	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/services"
 	"github.com/google/uuid"
 )
 
@@ -62,6 +65,32 @@ func (r *bookingResolver) Address(ctx context.Context, obj *model.Booking) (*mod
 	return convertAddressToGraphQL(address), nil
 }
 
+// Tip is the resolver for the tip field.
+func (r *bookingResolver) Tip(ctx context.Context, obj *model.Booking) (*model.Tip, error) {
+	tip, err := r.TipService.GetTipForBooking(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		return nil, nil
+	}
+	return convertTipToGraphQL(tip), nil
+}
+
+// CreditNotes is the resolver for the creditNotes field.
+func (r *invoiceResolver) CreditNotes(ctx context.Context, obj *model.Invoice) ([]*model.CreditNote, error) {
+	notes, err := r.CreditNoteService.GetCreditNotesByInvoiceID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.CreditNote, len(notes))
+	for i, note := range notes {
+		result[i] = convertCreditNoteToGraphQL(note)
+	}
+	return result, nil
+}
+
 // RequestOtp is the resolver for the requestOtp field.
 func (r *mutationResolver) RequestOtp(ctx context.Context, email string) (bool, error) {
 	err := r.AuthService.RequestOTP(ctx, email)
@@ -218,6 +247,72 @@ func (r *mutationResolver) DeleteAddress(ctx context.Context, id string) (bool,
 	return true, nil
 }
 
+// CreateBillingProfile is the resolver for the createBillingProfile field.
+func (r *mutationResolver) CreateBillingProfile(ctx context.Context, input model.CreateBillingProfileInput) (*model.BillingProfile, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	profile, err := r.BillingProfileService.CreateBillingProfile(userID, services.BillingProfileInput{
+		LegalName:          &input.LegalName,
+		CUI:                &input.Cui,
+		RegistrationNumber: input.RegistrationNumber,
+		VATPayer:           &input.VatPayer,
+		StreetAddress:      &input.StreetAddress,
+		City:               &input.City,
+		County:             &input.County,
+		PostalCode:         input.PostalCode,
+		Email:              input.Email,
+		IsDefault:          input.IsDefault,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBillingProfileToGraphQL(profile), nil
+}
+
+// UpdateBillingProfile is the resolver for the updateBillingProfile field.
+func (r *mutationResolver) UpdateBillingProfile(ctx context.Context, id string, input model.UpdateBillingProfileInput) (*model.BillingProfile, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	profile, err := r.BillingProfileService.UpdateBillingProfile(id, userID, services.BillingProfileInput{
+		LegalName:          input.LegalName,
+		CUI:                input.Cui,
+		RegistrationNumber: input.RegistrationNumber,
+		VATPayer:           input.VatPayer,
+		StreetAddress:      input.StreetAddress,
+		City:               input.City,
+		County:             input.County,
+		PostalCode:         input.PostalCode,
+		Email:              input.Email,
+		IsDefault:          input.IsDefault,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBillingProfileToGraphQL(profile), nil
+}
+
+// DeleteBillingProfile is the resolver for the deleteBillingProfile field.
+func (r *mutationResolver) DeleteBillingProfile(ctx context.Context, id string) (bool, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return false, fmt.Errorf("authentication required")
+	}
+
+	if err := r.BillingProfileService.DeleteBillingProfile(id, userID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // CreateCleanerProfile is the resolver for the createCleanerProfile field.
 func (r *mutationResolver) CreateCleanerProfile(ctx context.Context, input model.CreateCleanerProfileInput) (*model.Cleaner, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
@@ -319,12 +414,19 @@ func (r *mutationResolver) CreateBooking(ctx context.Context, input model.Create
 		includesBalcony = *input.IncludesBalcony
 	}
 
-	booking, err := r.BookingService.CreateBooking(userID, input.AddressID, models.ServiceType(input.ServiceType), areaSqm, input.EstimatedHours, scheduledDate, scheduledTime, input.IncludesDeepCleaning, input.IncludesWindows, input.NumberOfWindows, input.IncludesCarpet, input.CarpetAreaSqm, includesFridge, includesOven, includesBalcony, specialInstructions, accessInstructions, input.Supplies, timePreferences, frequency)
-	if err != nil {
-		return nil, err
+	billingProfileID := ""
+	if input.BillingProfileID != nil {
+		billingProfileID = *input.BillingProfileID
 	}
 
-	return convertBookingToGraphQL(booking), nil
+	return withIdempotency(ctx, r.Resolver, "createBooking", input, func() (*model.Booking, error) {
+		booking, err := r.BookingService.CreateBooking(userID, input.AddressID, models.ServiceType(input.ServiceType), areaSqm, input.EstimatedHours, scheduledDate, scheduledTime, input.IncludesDeepCleaning, input.IncludesWindows, input.NumberOfWindows, input.IncludesCarpet, input.CarpetAreaSqm, includesFridge, includesOven, includesBalcony, specialInstructions, accessInstructions, input.Supplies, timePreferences, frequency, billingProfileID)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertBookingToGraphQL(booking), nil
+	})
 }
 
 // CancelBooking is the resolver for the cancelBooking field.
@@ -440,13 +542,13 @@ func (r *mutationResolver) CheckIn(ctx context.Context, bookingID string, latitu
 }
 
 // CheckOut is the resolver for the checkOut field.
-func (r *mutationResolver) CheckOut(ctx context.Context, bookingID string, latitude float64, longitude float64) (*model.Checkin, error) {
+func (r *mutationResolver) CheckOut(ctx context.Context, bookingID string, latitude float64, longitude float64, cashReceived *bool) (*model.Checkin, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	checkin, err := r.CheckinService.CheckOut(bookingID, userID, latitude, longitude)
+	checkin, err := r.CheckinService.CheckOut(bookingID, userID, latitude, longitude, cashReceived)
 	if err != nil {
 		return nil, err
 	}
@@ -464,32 +566,41 @@ func (r *mutationResolver) PreauthorizePayment(ctx context.Context, bookingID st
 	// Convert provider enum
 	convertedProvider := models.PaymentProvider(provider)
 
-	payment, err := r.PaymentService.PreauthorizePayment(bookingID, userID, amount, convertedProvider)
-	if err != nil {
-		return nil, err
-	}
+	args := map[string]interface{}{"bookingId": bookingID, "amount": amount, "provider": provider}
+	return withIdempotency(ctx, r.Resolver, "preauthorizePayment", args, func() (*model.Payment, error) {
+		payment, err := r.PaymentService.PreauthorizePayment(bookingID, userID, amount, convertedProvider)
+		if err != nil {
+			return nil, err
+		}
 
-	return convertPaymentToGraphQL(payment), nil
+		return convertPaymentToGraphQL(payment), nil
+	})
 }
 
 // CapturePayment is the resolver for the capturePayment field.
 func (r *mutationResolver) CapturePayment(ctx context.Context, paymentID string) (*model.Payment, error) {
-	payment, err := r.PaymentService.CapturePayment(paymentID)
-	if err != nil {
-		return nil, err
-	}
+	args := map[string]interface{}{"paymentId": paymentID}
+	return withIdempotency(ctx, r.Resolver, "capturePayment", args, func() (*model.Payment, error) {
+		payment, err := r.PaymentService.CapturePayment(paymentID)
+		if err != nil {
+			return nil, err
+		}
 
-	return convertPaymentToGraphQL(payment), nil
+		return convertPaymentToGraphQL(payment), nil
+	})
 }
 
 // RefundPayment is the resolver for the refundPayment field.
 func (r *mutationResolver) RefundPayment(ctx context.Context, paymentID string, amount float64, reason string) (*model.Payment, error) {
-	payment, err := r.PaymentService.RefundPayment(paymentID, amount, reason)
-	if err != nil {
-		return nil, err
-	}
+	args := map[string]interface{}{"paymentId": paymentID, "amount": amount, "reason": reason}
+	return withIdempotency(ctx, r.Resolver, "refundPayment", args, func() (*model.Payment, error) {
+		payment, err := r.PaymentService.RefundPayment(paymentID, amount, reason)
+		if err != nil {
+			return nil, err
+		}
 
-	return convertPaymentToGraphQL(payment), nil
+		return convertPaymentToGraphQL(payment), nil
+	})
 }
 
 // CancelPayment is the resolver for the cancelPayment field.
@@ -502,6 +613,26 @@ func (r *mutationResolver) CancelPayment(ctx context.Context, paymentID string)
 	return convertPaymentToGraphQL(payment), nil
 }
 
+// TipCleaner is the resolver for the tipCleaner field.
+func (r *mutationResolver) TipCleaner(ctx context.Context, bookingID string, amount float64, provider model.PaymentProvider, useSavedCard *bool) (*model.Tip, error) {
+	userID, err := middleware.RequireClientRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	savedCard := useSavedCard != nil && *useSavedCard
+
+	args := map[string]interface{}{"bookingId": bookingID, "amount": amount, "provider": provider, "useSavedCard": savedCard}
+	return withIdempotency(ctx, r.Resolver, "tipCleaner", args, func() (*model.Tip, error) {
+		tip, err := r.TipService.TipCleaner(bookingID, userID, amount, models.PaymentProvider(provider), savedCard)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertTipToGraphQL(tip), nil
+	})
+}
+
 // CreateAvailability is the resolver for the createAvailability field.
 func (r *mutationResolver) CreateAvailability(ctx context.Context, input model.CreateAvailabilityInput) (*model.Availability, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
@@ -739,6 +870,20 @@ func (r *mutationResolver) RejectCompany(ctx context.Context, companyID string,
 	return convertCompanyToGraphQL(company), nil
 }
 
+// SetCompanyPlatformFee is the resolver for the setCompanyPlatformFee field.
+func (r *mutationResolver) SetCompanyPlatformFee(ctx context.Context, companyID string, percentage *float64) (*model.Company, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	company, err := r.CompanyService.SetPlatformFee(companyID, percentage)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCompanyToGraphQL(company), nil
+}
+
 // SuspendCleaner is the resolver for the suspendCleaner field.
 func (r *mutationResolver) SuspendCleaner(ctx context.Context, cleanerID string, reason string) (*model.Cleaner, error) {
 	// Require admin authorization
@@ -1026,17 +1171,19 @@ func (r *mutationResolver) GenerateMonthlyPayouts(ctx context.Context, input mod
 		return nil, fmt.Errorf("unauthorized: admin access required")
 	}
 
-	payouts, err := r.PayoutService.GenerateMonthlyPayouts(input.Year, time.Month(input.Month))
-	if err != nil {
-		return nil, err
-	}
+	return withIdempotency(ctx, r.Resolver, "generateMonthlyPayouts", input, func() ([]*model.Payout, error) {
+		payouts, err := r.PayoutService.GenerateMonthlyPayouts(input.Year, time.Month(input.Month))
+		if err != nil {
+			return nil, err
+		}
 
-	result := make([]*model.Payout, len(payouts))
-	for i, payout := range payouts {
-		result[i] = convertPayoutToGraphQL(payout)
-	}
+		result := make([]*model.Payout, len(payouts))
+		for i, payout := range payouts {
+			result[i] = convertPayoutToGraphQL(payout)
+		}
 
-	return result, nil
+		return result, nil
+	})
 }
 
 // MarkPayoutAsSent is the resolver for the markPayoutAsSent field.
@@ -1047,16 +1194,19 @@ func (r *mutationResolver) MarkPayoutAsSent(ctx context.Context, id string, tran
 		return nil, err
 	}
 
-	if err := r.PayoutService.MarkPayoutAsSent(id, transferReference); err != nil {
-		return nil, err
-	}
+	args := map[string]interface{}{"id": id, "transferReference": transferReference}
+	return withIdempotency(ctx, r.Resolver, "markPayoutAsSent", args, func() (*model.Payout, error) {
+		if err := r.PayoutService.MarkPayoutAsSent(id, transferReference); err != nil {
+			return nil, err
+		}
 
-	payout, lineItems, err := r.PayoutService.GetPayoutWithLineItems(id)
-	if err != nil {
-		return nil, err
-	}
+		payout, lineItems, err := r.PayoutService.GetPayoutWithLineItems(id)
+		if err != nil {
+			return nil, err
+		}
 
-	return convertPayoutToGraphQLWithLineItems(payout, lineItems), nil
+		return convertPayoutToGraphQLWithLineItems(payout, lineItems), nil
+	})
 }
 
 // MarkPayoutAsFailed is the resolver for the markPayoutAsFailed field.
@@ -1079,677 +1229,1602 @@ func (r *mutationResolver) MarkPayoutAsFailed(ctx context.Context, id string, re
 	return convertPayoutToGraphQLWithLineItems(payout, lineItems), nil
 }
 
-// UpdatePlatformSettings is the resolver for the updatePlatformSettings field.
-func (r *mutationResolver) UpdatePlatformSettings(ctx context.Context, input model.UpdatePlatformSettingsInput) (*model.PlatformSettings, error) {
+// MarkPayoutInvoicePaid is the resolver for the markPayoutInvoicePaid field.
+func (r *mutationResolver) MarkPayoutInvoicePaid(ctx context.Context, id string, transferReference string) (*model.Payout, error) {
 	// Require admin authorization
 	_, err := middleware.RequireAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get current settings
-	settings, err := r.PlatformSettingsService.GetSettings(ctx)
-	if err != nil {
-		return nil, err
-	}
+	args := map[string]interface{}{"id": id, "transferReference": transferReference}
+	return withIdempotency(ctx, r.Resolver, "markPayoutInvoicePaid", args, func() (*model.Payout, error) {
+		if err := r.PayoutService.MarkPayoutInvoicePaid(id, transferReference); err != nil {
+			return nil, err
+		}
 
-	// Update fields if provided
-	if input.BasePrice != nil {
-		settings.BasePrice = *input.BasePrice
-	}
-	if input.WeekendMultiplier != nil {
-		settings.WeekendMultiplier = *input.WeekendMultiplier
-	}
-	if input.EveningMultiplier != nil {
-		settings.EveningMultiplier = *input.EveningMultiplier
-	}
-	if input.PlatformFeePercent != nil {
-		settings.PlatformFeePercent = *input.PlatformFeePercent
-	}
-	if input.EmailNotificationsEnabled != nil {
-		settings.EmailNotificationsEnabled = *input.EmailNotificationsEnabled
-	}
-	if input.AutoApprovalEnabled != nil {
-		settings.AutoApprovalEnabled = *input.AutoApprovalEnabled
-	}
-	if input.MaintenanceMode != nil {
-		settings.MaintenanceMode = *input.MaintenanceMode
-	}
+		payout, lineItems, err := r.PayoutService.GetPayoutWithLineItems(id)
+		if err != nil {
+			return nil, err
+		}
 
-	// Save settings
-	updated, err := r.PlatformSettingsService.UpdateSettings(ctx, settings)
+		return convertPayoutToGraphQLWithLineItems(payout, lineItems), nil
+	})
+}
+
+// CreatePayoutAdjustment is the resolver for the createPayoutAdjustment field.
+func (r *mutationResolver) CreatePayoutAdjustment(ctx context.Context, input model.CreatePayoutAdjustmentInput) (*model.PayoutAdjustment, error) {
+	adminID, err := middleware.RequireAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertPlatformSettingsToGraphQL(updated), nil
+	args := map[string]interface{}{"input": input}
+	return withIdempotency(ctx, r.Resolver, "createPayoutAdjustment", args, func() (*model.PayoutAdjustment, error) {
+		adjustment, err := r.PayoutAdjustmentService.CreateAdjustment(
+			input.CleanerID,
+			models.PayoutAdjustmentType(input.Type),
+			input.Amount,
+			input.Reason,
+			input.BookingID,
+			adminID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertPayoutAdjustmentToGraphQL(adjustment), nil
+	})
 }
 
-// UpdateUserProfile is the resolver for the updateUserProfile field.
-func (r *mutationResolver) UpdateUserProfile(ctx context.Context, input model.UpdateUserProfileInput) (*model.User, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+// GenerateCommissionInvoices is the resolver for the generateCommissionInvoices field.
+func (r *mutationResolver) GenerateCommissionInvoices(ctx context.Context, input model.GeneratePayoutsInput) ([]*model.CommissionInvoice, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	user, err := r.AuthService.UpdateUserProfile(userID, input.FirstName, input.LastName, input.Email)
+	return withIdempotency(ctx, r.Resolver, "generateCommissionInvoices", input, func() ([]*model.CommissionInvoice, error) {
+		invoices, err := r.CommissionInvoiceService.GenerateForPeriod(input.Year, time.Month(input.Month))
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]*model.CommissionInvoice, len(invoices))
+		for i, invoice := range invoices {
+			result[i] = convertCommissionInvoiceToGraphQL(invoice)
+		}
+		return result, nil
+	})
+}
+
+// ExportPayoutBatch is the resolver for the exportPayoutBatch field.
+func (r *mutationResolver) ExportPayoutBatch(ctx context.Context, input model.ExportPayoutBatchInput) (*model.PayoutBatch, error) {
+	adminID, err := middleware.RequireAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertUserToGraphQL(user), nil
-}
+	args := map[string]interface{}{"input": input}
+	return withIdempotency(ctx, r.Resolver, "exportPayoutBatch", args, func() (*model.PayoutBatch, error) {
+		batch, err := r.PayoutBatchService.ExportPayoutBatch(input.Format.String(), input.PayoutIds, adminID)
+		if err != nil {
+			return nil, err
+		}
 
-// RetryANAFSubmission is the resolver for the retryANAFSubmission field.
-func (r *mutationResolver) RetryANAFSubmission(ctx context.Context, invoiceID string) (*model.Invoice, error) {
-	// TODO: Add admin role check when auth middleware is ready
-	// userID, ok := middleware.GetUserIDFromContext(ctx)
-	// if !ok {
-	// 	return nil, fmt.Errorf("unauthorized")
-	// }
+		payouts, err := r.PayoutBatchService.GetBatchPayouts(batch.ID)
+		if err != nil {
+			return nil, err
+		}
 
-	// Retry ANAF submission
-	if err := r.InvoiceService.SubmitToANAF(invoiceID); err != nil {
-		return nil, fmt.Errorf("failed to retry ANAF submission: %w", err)
+		return convertPayoutBatchToGraphQL(batch, payouts), nil
+	})
+}
+
+// MarkPayoutBatchAsSent is the resolver for the markPayoutBatchAsSent field.
+func (r *mutationResolver) MarkPayoutBatchAsSent(ctx context.Context, id string, transferReference string) (*model.PayoutBatch, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	// Get updated invoice
-	invoice, err := r.InvoiceService.GetInvoiceByID(invoiceID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	args := map[string]interface{}{"id": id, "transferReference": transferReference}
+	return withIdempotency(ctx, r.Resolver, "markPayoutBatchAsSent", args, func() (*model.PayoutBatch, error) {
+		batch, err := r.PayoutBatchService.MarkPayoutBatchAsSent(id, transferReference)
+		if err != nil {
+			return nil, err
+		}
+
+		payouts, err := r.PayoutBatchService.GetBatchPayouts(batch.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertPayoutBatchToGraphQL(batch, payouts), nil
+	})
+}
+
+// MarkCompanyPayoutAsSent is the resolver for the markCompanyPayoutAsSent field.
+func (r *mutationResolver) MarkCompanyPayoutAsSent(ctx context.Context, id string, transferReference string) (*model.CompanyPayout, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	return convertInvoiceToGraphQL(invoice), nil
+	args := map[string]interface{}{"id": id, "transferReference": transferReference}
+	return withIdempotency(ctx, r.Resolver, "markCompanyPayoutAsSent", args, func() (*model.CompanyPayout, error) {
+		companyPayout, err := r.PayoutService.MarkCompanyPayoutAsSent(id, transferReference)
+		if err != nil {
+			return nil, err
+		}
+
+		breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertCompanyPayoutToGraphQL(companyPayout, breakdown), nil
+	})
 }
 
-// CheckANAFStatus is the resolver for the checkANAFStatus field.
-func (r *mutationResolver) CheckANAFStatus(ctx context.Context, invoiceID string) (*model.Invoice, error) {
-	// TODO: Add admin role check when auth middleware is ready
-	// userID, ok := middleware.GetUserIDFromContext(ctx)
-	// if !ok {
-	// 	return nil, fmt.Errorf("unauthorized")
-	// }
+// MarkCompanyPayoutAsFailed is the resolver for the markCompanyPayoutAsFailed field.
+func (r *mutationResolver) MarkCompanyPayoutAsFailed(ctx context.Context, id string, reason string) (*model.CompanyPayout, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
 
-	// Check ANAF status
-	if err := r.InvoiceService.CheckANAFStatus(invoiceID); err != nil {
-		return nil, fmt.Errorf("failed to check ANAF status: %w", err)
+	companyPayout, err := r.PayoutService.MarkCompanyPayoutAsFailed(id, reason)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get updated invoice
-	invoice, err := r.InvoiceService.GetInvoiceByID(invoiceID)
+	breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get invoice: %w", err)
+		return nil, err
 	}
 
-	return convertInvoiceToGraphQL(invoice), nil
+	return convertCompanyPayoutToGraphQL(companyPayout, breakdown), nil
 }
 
-// SaveCleanerApplication is the resolver for the saveCleanerApplication field.
-func (r *mutationResolver) SaveCleanerApplication(ctx context.Context, input model.CleanerApplicationInput) (*model.CleanerApplication, error) {
-	// Get authenticated user ID if available (optional for draft saving)
-	userID, _ := middleware.GetUserIDFromContext(ctx)
-
-	// Convert GraphQL input to service input
-	serviceInput := &models.CleanerApplicationInput{
-		SessionID:   input.SessionID,
-		CurrentStep: input.CurrentStep,
+// MarkCompanyPayoutInvoicePaid is the resolver for the markCompanyPayoutInvoicePaid field.
+func (r *mutationResolver) MarkCompanyPayoutInvoicePaid(ctx context.Context, id string, transferReference string) (*model.CompanyPayout, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	// Set user ID if authenticated
-	if userID != "" {
-		uid, err := uuid.Parse(userID)
-		if err == nil {
-			serviceInput.UserID = &uid
+	args := map[string]interface{}{"id": id, "transferReference": transferReference}
+	return withIdempotency(ctx, r.Resolver, "markCompanyPayoutInvoicePaid", args, func() (*model.CompanyPayout, error) {
+		companyPayout, err := r.PayoutService.MarkCompanyPayoutInvoicePaid(id, transferReference)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	// Convert eligibility data if provided
-	if input.Eligibility != nil {
-		serviceInput.Eligibility = &models.EligibilityInput{
-			Age18Plus:  input.Eligibility.Age18Plus,
-			WorkRight:  input.Eligibility.WorkRight,
-			Experience: input.Eligibility.Experience,
+		breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
+		if err != nil {
+			return nil, err
 		}
+
+		return convertCompanyPayoutToGraphQL(companyPayout, breakdown), nil
+	})
+}
+
+// EnableSelfBilling is the resolver for the enableSelfBilling field.
+func (r *mutationResolver) EnableSelfBilling(ctx context.Context, input model.EnableSelfBillingInput) (*model.CleanerSelfBilling, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert availability data if provided
-	if input.Availability != nil {
-		serviceInput.Availability = &models.AvailabilityInput{
-			HoursPerWeek: input.Availability.HoursPerWeek,
-			Areas:        input.Availability.Areas,
-			Days:         input.Availability.Days,
-			TimeSlots:    input.Availability.TimeSlots,
-		}
+	settings, err := r.SelfBillingService.EnableSelfBilling(userID, services.SelfBillingSettingsInput{
+		LegalName:          input.LegalName,
+		CUI:                input.Cui,
+		RegistrationNumber: input.RegistrationNumber,
+		Address:            input.Address,
+		City:               input.City,
+		County:             input.County,
+		VATPayer:           input.VatPayer,
+		AcceptAgreement:    input.AcceptAgreement,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert profile data if provided
-	if input.Profile != nil {
-		serviceInput.Profile = &models.ProfileInput{
-			PhotoURL:  input.Profile.PhotoURL,
-			Bio:       input.Profile.Bio,
-			Languages: input.Profile.Languages,
-			Equipment: input.Profile.Equipment,
-		}
+	return convertCleanerSelfBillingToGraphQL(settings), nil
+}
+
+// DisableSelfBilling is the resolver for the disableSelfBilling field.
+func (r *mutationResolver) DisableSelfBilling(ctx context.Context) (*model.CleanerSelfBilling, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert legal data if provided
-	if input.Legal != nil {
-		serviceInput.Legal = &models.LegalInput{
-			Status:       input.Legal.Status,
-			CIF:          input.Legal.Cif,
-			CNPEncrypted: input.Legal.CnpEncrypted,
-			IBAN:         input.Legal.Iban,
-			BankName:     input.Legal.BankName,
-		}
+	settings, err := r.SelfBillingService.DisableSelfBilling(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert documents data if provided
-	if input.Documents != nil {
-		serviceInput.Documents = &models.DocumentInput{
-			CazierURL:    input.Documents.CazierURL,
-			IDFrontURL:   input.Documents.IDFrontURL,
-			IDBackURL:    input.Documents.IDBackURL,
-			InsuranceURL: input.Documents.InsuranceURL,
-		}
+	return convertCleanerSelfBillingToGraphQL(settings), nil
+}
+
+// GrantWalletCredit is the resolver for the grantWalletCredit field.
+func (r *mutationResolver) GrantWalletCredit(ctx context.Context, input model.GrantWalletCreditInput) (*model.WalletTransaction, error) {
+	// Require admin authorization
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Save application
-	app, err := r.CleanerApplicationService.SaveApplication(serviceInput)
+	txn, err := r.WalletService.GrantCredit(input.UserID, input.Amount, models.WalletSource(input.Source), input.Reason, input.ExpiresAt, adminID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to GraphQL model
-	return convertCleanerApplicationToGraphQL(app), nil
+	return convertWalletTransactionToGraphQL(txn), nil
 }
 
-// SubmitCleanerApplication is the resolver for the submitCleanerApplication field.
-func (r *mutationResolver) SubmitCleanerApplication(ctx context.Context, applicationID string) (*model.CleanerApplication, error) {
-	// Require authentication
+// ImportBankStatement is the resolver for the importBankStatement field.
+func (r *mutationResolver) ImportBankStatement(ctx context.Context, file graphql.Upload, format *model.BankStatementFormat) (*model.BankStatementImport, error) {
+	// Require admin authorization
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var formatStr string
+	if format != nil {
+		formatStr = format.String()
+	}
+
+	imp, err := r.BankReconciliationService.ImportStatement(file, formatStr, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBankStatementImportToGraphQL(imp), nil
+}
+
+// MatchBankStatementLine is the resolver for the matchBankStatementLine field.
+func (r *mutationResolver) MatchBankStatementLine(ctx context.Context, lineID string, payoutID *string, companyPayoutID *string, invoiceID *string, proformaID *string) (*model.BankStatementLine, error) {
+	// Require admin authorization
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var match models.BankLineMatch
+	if payoutID != nil {
+		match.PayoutID = *payoutID
+	}
+	if companyPayoutID != nil {
+		match.CompanyPayoutID = *companyPayoutID
+	}
+	if invoiceID != nil {
+		match.InvoiceID = *invoiceID
+	}
+	if proformaID != nil {
+		match.ProformaID = *proformaID
+	}
+
+	line, err := r.BankReconciliationService.MatchLine(lineID, match, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBankStatementLineToGraphQL(line), nil
+}
+
+// IgnoreBankStatementLine is the resolver for the ignoreBankStatementLine field.
+func (r *mutationResolver) IgnoreBankStatementLine(ctx context.Context, lineID string, reason string) (*model.BankStatementLine, error) {
+	// Require admin authorization
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	line, err := r.BankReconciliationService.IgnoreLine(lineID, reason, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBankStatementLineToGraphQL(line), nil
+}
+
+// UpdatePlatformSettings is the resolver for the updatePlatformSettings field.
+func (r *mutationResolver) UpdatePlatformSettings(ctx context.Context, input model.UpdatePlatformSettingsInput) (*model.PlatformSettings, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get current settings
+	settings, err := r.PlatformSettingsService.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if input.BasePrice != nil {
+		settings.BasePrice = *input.BasePrice
+	}
+	if input.WeekendMultiplier != nil {
+		settings.WeekendMultiplier = *input.WeekendMultiplier
+	}
+	if input.EveningMultiplier != nil {
+		settings.EveningMultiplier = *input.EveningMultiplier
+	}
+	if input.PlatformFeePercent != nil {
+		settings.PlatformFeePercent = *input.PlatformFeePercent
+	}
+	if input.EmailNotificationsEnabled != nil {
+		settings.EmailNotificationsEnabled = *input.EmailNotificationsEnabled
+	}
+	if input.AutoApprovalEnabled != nil {
+		settings.AutoApprovalEnabled = *input.AutoApprovalEnabled
+	}
+	if input.MaintenanceMode != nil {
+		settings.MaintenanceMode = *input.MaintenanceMode
+	}
+
+	// Save settings
+	updated, err := r.PlatformSettingsService.UpdateSettings(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPlatformSettingsToGraphQL(updated), nil
+}
+
+// UpdateUserProfile is the resolver for the updateUserProfile field.
+func (r *mutationResolver) UpdateUserProfile(ctx context.Context, input model.UpdateUserProfileInput) (*model.User, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	// Submit application
-	app, err := r.CleanerApplicationService.SubmitApplication(applicationID, userID)
+	user, err := r.AuthService.UpdateUserProfile(userID, input.FirstName, input.LastName, input.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to GraphQL model
-	return convertCleanerApplicationToGraphQL(app), nil
+	return convertUserToGraphQL(user), nil
 }
 
-// ReviewCleanerApplication is the resolver for the reviewCleanerApplication field.
-func (r *mutationResolver) ReviewCleanerApplication(ctx context.Context, applicationID string, approve bool, rejectionReason *string) (*model.CleanerApplication, error) {
-	// Require admin authorization
+// RetryANAFSubmission is the resolver for the retryANAFSubmission field.
+func (r *mutationResolver) RetryANAFSubmission(ctx context.Context, invoiceID string) (*model.Invoice, error) {
+	// TODO: Add admin role check when auth middleware is ready
+	// userID, ok := middleware.GetUserIDFromContext(ctx)
+	// if !ok {
+	// 	return nil, fmt.Errorf("unauthorized")
+	// }
+
+	// Retry ANAF submission
+	if err := r.InvoiceService.SubmitToANAF(invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to retry ANAF submission: %w", err)
+	}
+
+	// Get updated invoice
+	invoice, err := r.InvoiceService.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return convertInvoiceToGraphQL(invoice), nil
+}
+
+// CheckANAFStatus is the resolver for the checkANAFStatus field.
+func (r *mutationResolver) CheckANAFStatus(ctx context.Context, invoiceID string) (*model.Invoice, error) {
+	// TODO: Add admin role check when auth middleware is ready
+	// userID, ok := middleware.GetUserIDFromContext(ctx)
+	// if !ok {
+	// 	return nil, fmt.Errorf("unauthorized")
+	// }
+
+	// Check ANAF status
+	if err := r.InvoiceService.CheckANAFStatus(invoiceID); err != nil {
+		return nil, fmt.Errorf("failed to check ANAF status: %w", err)
+	}
+
+	// Get updated invoice
+	invoice, err := r.InvoiceService.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return convertInvoiceToGraphQL(invoice), nil
+}
+
+// ResolveANAFAlert is the resolver for the resolveANAFAlert field.
+func (r *mutationResolver) ResolveANAFAlert(ctx context.Context, id string) (*model.ANAFAlert, error) {
 	adminID, err := middleware.RequireAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert optional rejectionReason to string
-	rejectionReasonStr := ""
-	if rejectionReason != nil {
-		rejectionReasonStr = *rejectionReason
+	alert, err := r.ANAFWorker.ResolveAlert(id, adminID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Review application (adminNotes empty for now, can be added to GraphQL schema later)
-	app, err := r.CleanerApplicationService.ReviewApplication(applicationID, adminID, approve, rejectionReasonStr, "")
+	return convertANAFAlertToGraphQL(alert), nil
+}
+
+// MarkProformaPaid is the resolver for the markProformaPaid field.
+func (r *mutationResolver) MarkProformaPaid(ctx context.Context, proformaID string, reference *string) (*model.Proforma, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var referenceVal string
+	if reference != nil {
+		referenceVal = *reference
+	}
+
+	proforma, err := r.ProformaService.RecordTransfer(proformaID, referenceVal)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to GraphQL model
-	return convertCleanerApplicationToGraphQL(app), nil
+	return convertProformaToGraphQL(proforma), nil
 }
 
-// Ping is the resolver for the ping field.
-func (r *queryResolver) Ping(ctx context.Context) (string, error) {
-	return "pong", nil
+// ResendInvoiceEmail is the resolver for the resendInvoiceEmail field.
+func (r *mutationResolver) ResendInvoiceEmail(ctx context.Context, invoiceID string, toEmail *string) (*model.Invoice, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	email := ""
+	if toEmail != nil {
+		email = *toEmail
+	}
+
+	invoice, err := r.InvoiceService.ResendInvoiceEmail(invoiceID, email)
+	if err != nil {
+		return nil, err
+	}
+	return convertInvoiceToGraphQL(invoice), nil
+}
+
+// CreateCreditNote is the resolver for the createCreditNote field.
+func (r *mutationResolver) CreateCreditNote(ctx context.Context, input model.CreateCreditNoteInput) (*model.CreditNote, error) {
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args := map[string]interface{}{"invoiceId": input.InvoiceID, "amount": input.Amount, "reason": input.Reason}
+	return withIdempotency(ctx, r.Resolver, "createCreditNote", args, func() (*model.CreditNote, error) {
+		note, err := r.CreditNoteService.CreateCreditNote(input.InvoiceID, input.Amount, input.Reason, adminID)
+		if err != nil {
+			return nil, err
+		}
+		return convertCreditNoteToGraphQL(note), nil
+	})
+}
+
+// RetryCreditNoteANAFSubmission is the resolver for the retryCreditNoteANAFSubmission field.
+func (r *mutationResolver) RetryCreditNoteANAFSubmission(ctx context.Context, creditNoteID string) (*model.CreditNote, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := r.CreditNoteService.SubmitToANAF(creditNoteID); err != nil {
+		return nil, fmt.Errorf("failed to retry ANAF submission: %w", err)
+	}
+
+	note, err := r.CreditNoteService.GetCreditNote(creditNoteID)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, fmt.Errorf("credit note not found")
+	}
+	return convertCreditNoteToGraphQL(note), nil
+}
+
+// CreateInvoiceSeries is the resolver for the createInvoiceSeries field.
+func (r *mutationResolver) CreateInvoiceSeries(ctx context.Context, input model.CreateInvoiceSeriesInput) (*model.InvoiceSeries, error) {
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	yearlyReset := true
+	if input.YearlyReset != nil {
+		yearlyReset = *input.YearlyReset
+	}
+	startNumber := 1
+	if input.StartNumber != nil {
+		startNumber = *input.StartNumber
+	}
+	activate := false
+	if input.Activate != nil {
+		activate = *input.Activate
+	}
+
+	series, err := r.InvoiceSeriesService.CreateSeries(models.DocumentType(input.DocumentType), input.Prefix,
+		yearlyReset, activate, startNumber, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return convertInvoiceSeriesToGraphQL(series), nil
+}
+
+// ActivateInvoiceSeries is the resolver for the activateInvoiceSeries field.
+func (r *mutationResolver) ActivateInvoiceSeries(ctx context.Context, id string) (*model.InvoiceSeries, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	series, err := r.InvoiceSeriesService.ActivateSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	return convertInvoiceSeriesToGraphQL(series), nil
+}
+
+// SaveCleanerApplication is the resolver for the saveCleanerApplication field.
+func (r *mutationResolver) SaveCleanerApplication(ctx context.Context, input model.CleanerApplicationInput) (*model.CleanerApplication, error) {
+	// Get authenticated user ID if available (optional for draft saving)
+	userID, _ := middleware.GetUserIDFromContext(ctx)
+
+	// Convert GraphQL input to service input
+	serviceInput := &models.CleanerApplicationInput{
+		SessionID:   input.SessionID,
+		CurrentStep: input.CurrentStep,
+	}
+
+	// Set user ID if authenticated
+	if userID != "" {
+		uid, err := uuid.Parse(userID)
+		if err == nil {
+			serviceInput.UserID = &uid
+		}
+	}
+
+	// Convert eligibility data if provided
+	if input.Eligibility != nil {
+		serviceInput.Eligibility = &models.EligibilityInput{
+			Age18Plus:  input.Eligibility.Age18Plus,
+			WorkRight:  input.Eligibility.WorkRight,
+			Experience: input.Eligibility.Experience,
+		}
+	}
+
+	// Convert availability data if provided
+	if input.Availability != nil {
+		serviceInput.Availability = &models.AvailabilityInput{
+			HoursPerWeek: input.Availability.HoursPerWeek,
+			Areas:        input.Availability.Areas,
+			Days:         input.Availability.Days,
+			TimeSlots:    input.Availability.TimeSlots,
+		}
+	}
+
+	// Convert profile data if provided
+	if input.Profile != nil {
+		serviceInput.Profile = &models.ProfileInput{
+			PhotoURL:  input.Profile.PhotoURL,
+			Bio:       input.Profile.Bio,
+			Languages: input.Profile.Languages,
+			Equipment: input.Profile.Equipment,
+		}
+	}
+
+	// Convert legal data if provided
+	if input.Legal != nil {
+		serviceInput.Legal = &models.LegalInput{
+			Status:       input.Legal.Status,
+			CIF:          input.Legal.Cif,
+			CNPEncrypted: input.Legal.CnpEncrypted,
+			IBAN:         input.Legal.Iban,
+			BankName:     input.Legal.BankName,
+		}
+	}
+
+	// Convert documents data if provided
+	if input.Documents != nil {
+		serviceInput.Documents = &models.DocumentInput{
+			CazierURL:    input.Documents.CazierURL,
+			IDFrontURL:   input.Documents.IDFrontURL,
+			IDBackURL:    input.Documents.IDBackURL,
+			InsuranceURL: input.Documents.InsuranceURL,
+		}
+	}
+
+	// Save application
+	app, err := r.CleanerApplicationService.SaveApplication(serviceInput)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to GraphQL model
+	return convertCleanerApplicationToGraphQL(app), nil
+}
+
+// SubmitCleanerApplication is the resolver for the submitCleanerApplication field.
+func (r *mutationResolver) SubmitCleanerApplication(ctx context.Context, applicationID string) (*model.CleanerApplication, error) {
+	// Require authentication
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	// Submit application
+	app, err := r.CleanerApplicationService.SubmitApplication(applicationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to GraphQL model
+	return convertCleanerApplicationToGraphQL(app), nil
+}
+
+// ReviewCleanerApplication is the resolver for the reviewCleanerApplication field.
+func (r *mutationResolver) ReviewCleanerApplication(ctx context.Context, applicationID string, approve bool, rejectionReason *string) (*model.CleanerApplication, error) {
+	// Require admin authorization
+	adminID, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert optional rejectionReason to string
+	rejectionReasonStr := ""
+	if rejectionReason != nil {
+		rejectionReasonStr = *rejectionReason
+	}
+
+	// Review application (adminNotes empty for now, can be added to GraphQL schema later)
+	app, err := r.CleanerApplicationService.ReviewApplication(applicationID, adminID, approve, rejectionReasonStr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to GraphQL model
+	return convertCleanerApplicationToGraphQL(app), nil
+}
+
+// Ping is the resolver for the ping field.
+func (r *queryResolver) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	user, err := r.AuthService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return convertUserToGraphQL(user), nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.AuthService.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return convertUserToGraphQL(user), nil
+}
+
+// MyClientProfile is the resolver for the myClientProfile field.
+func (r *queryResolver) MyClientProfile(ctx context.Context) (*model.Client, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	client, err := r.ClientService.GetOrCreateClientProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertClientToGraphQL(client), nil
+}
+
+// MyAddresses is the resolver for the myAddresses field.
+func (r *queryResolver) MyAddresses(ctx context.Context) ([]*model.Address, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	addresses, err := r.AddressService.GetUserAddresses(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Address, len(addresses))
+	for i, addr := range addresses {
+		result[i] = convertAddressToGraphQL(addr)
+	}
+
+	return result, nil
+}
+
+// Address is the resolver for the address field.
+func (r *queryResolver) Address(ctx context.Context, id string) (*model.Address, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	address, err := r.AddressService.GetAddress(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAddressToGraphQL(address), nil
+}
+
+// MyBillingProfiles is the resolver for the myBillingProfiles field.
+func (r *queryResolver) MyBillingProfiles(ctx context.Context) ([]*model.BillingProfile, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	profiles, err := r.BillingProfileService.GetUserBillingProfiles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.BillingProfile, len(profiles))
+	for i, profile := range profiles {
+		result[i] = convertBillingProfileToGraphQL(profile)
+	}
+	return result, nil
+}
+
+// LookupCompanyByCui is the resolver for the lookupCompanyByCUI field.
+func (r *queryResolver) LookupCompanyByCui(ctx context.Context, cui string) (*model.CompanyRegistryInfo, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	info, err := r.BillingProfileService.LookupCUI(ctx, cui)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCompanyRegistryInfoToGraphQL(info), nil
+}
+
+// MyCleanerProfile is the resolver for the myCleanerProfile field.
+func (r *queryResolver) MyCleanerProfile(ctx context.Context) (*model.Cleaner, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	cleaner, err := r.CleanerService.GetOrCreateCleanerProfile(userID)
+	if err != nil {
+		return nil, nil
+	}
+
+	return convertCleanerToGraphQL(cleaner), nil
+}
+
+// Cleaner is the resolver for the cleaner field.
+func (r *queryResolver) Cleaner(ctx context.Context, id string) (*model.Cleaner, error) {
+	cleaner, err := r.CleanerService.GetCleanerByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cleaner == nil {
+		return nil, fmt.Errorf("cleaner not found")
+	}
+
+	return convertCleanerToGraphQL(cleaner), nil
+}
+
+// ApprovedCleaners is the resolver for the approvedCleaners field.
+func (r *queryResolver) ApprovedCleaners(ctx context.Context) ([]*model.Cleaner, error) {
+	// Get approved cleaners (no limit, no search filter)
+	cleaners, err := r.CleanerService.GetApprovedCleaners(1000, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Cleaner, len(cleaners))
+	for i, cleaner := range cleaners {
+		result[i] = convertCleanerToGraphQL(cleaner)
+	}
+
+	return result, nil
+}
+
+// MyBookings is the resolver for the myBookings field.
+func (r *queryResolver) MyBookings(ctx context.Context, filter *model.BookingFilter) ([]*model.Booking, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	role, ok := middleware.GetUserRoleFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("role not found")
+	}
+
+	var bookings []*models.Booking
+	var err error
+
+	// Determine which bookings to fetch based on user role
+	if role == "CLEANER" {
+		// Get cleaner profile
+		cleaner, err := r.CleanerService.GetOrCreateCleanerProfile(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cleaner profile: %w", err)
+		}
+
+		// Fetch cleaner's bookings using GetBookingsByCleanerIDs
+		var filterStr *string
+		if filter != nil {
+			str := string(*filter)
+			filterStr = &str
+		}
+		bookings, err = r.BookingService.GetBookingsByCleanerIDs([]string{cleaner.ID}, filterStr)
+	} else {
+		// Default to client bookings for CLIENT role or others
+		if filter != nil {
+			bookings, err = r.BookingService.GetClientBookingsFiltered(userID, string(*filter))
+		} else {
+			bookings, err = r.BookingService.GetClientBookings(userID)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Booking, len(bookings))
+	for i, booking := range bookings {
+		result[i] = convertBookingToGraphQL(booking)
+	}
+
+	return result, nil
+}
+
+// Booking is the resolver for the booking field.
+func (r *queryResolver) Booking(ctx context.Context, id string) (*model.Booking, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	// Check if user is admin - if so, bypass authorization check
+	user, err := r.AuthService.GetUserByID(userID)
+	if err == nil && user != nil && user.Role == models.RolePlatformAdmin {
+		// Admin can view any booking - call service method directly
+		booking, err := r.BookingService.AdminGetBooking(id)
+		if err != nil {
+			return nil, err
+		}
+		return convertBookingToGraphQL(booking), nil
+	}
+
+	// For non-admin users, use normal authorization
+	booking, err := r.BookingService.GetBooking(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBookingToGraphQL(booking), nil
+}
+
+// GetPriceQuote is the resolver for the getPriceQuote field.
+func (r *queryResolver) GetPriceQuote(ctx context.Context, input model.PriceQuoteInput) (*model.PriceQuote, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	// Handle optional areaSqm
+	areaSqm := 0
+	if input.AreaSqm != nil {
+		areaSqm = *input.AreaSqm
+	}
+
+	// Handle optional frequency
+	frequency := "one_time"
+	if input.Frequency != nil {
+		frequency = *input.Frequency
+	}
+
+	quote, err := r.PricingService.CalculatePrice(
+		userID,
+		models.ServiceType(input.ServiceType),
+		areaSqm,
+		input.EstimatedHours,
+		input.ScheduledDate,
+		input.ScheduledTime,
+		input.IncludesWindows,
+		input.NumberOfWindows,
+		input.IncludesCarpet,
+		input.CarpetAreaSqm,
+		false, // includesFridge - not available in PriceQuoteInput (legacy)
+		false, // includesOven - not available in PriceQuoteInput (legacy)
+		false, // includesBalcony - not available in PriceQuoteInput (legacy)
+		false, // includesSupplies - not available in PriceQuoteInput (legacy)
+		frequency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PriceQuote{
+		BasePrice:      quote.BasePrice,
+		AddonsPrice:    quote.AddonsPrice,
+		Subtotal:       quote.Subtotal,
+		Discount:       quote.Discount,
+		PlatformFee:    quote.PlatformFee,
+		TotalPrice:     quote.TotalPrice,
+		VatAmount:      quote.VATAmount,
+		CleanerPayout:  quote.CleanerPayout,
+		EstimatedHours: quote.EstimatedHours,
+		Breakdown: &model.PriceBreakdown{
+			BasePricePerHour:      quote.Breakdown.BasePricePerHour,
+			HoursCharged:          quote.Breakdown.HoursCharged,
+			AreaPrice:             quote.Breakdown.AreaPrice,
+			WindowsPrice:          quote.Breakdown.WindowsPrice,
+			CarpetPrice:           quote.Breakdown.CarpetPrice,
+			TimeMultiplier:        quote.Breakdown.TimeMultiplier,
+			DiscountPercentage:    quote.Breakdown.DiscountPercentage,
+			PlatformFeePercentage: quote.Breakdown.PlatformFeePercentage,
+		},
+	}, nil
+}
+
+// AvailableJobs is the resolver for the availableJobs field.
+func (r *queryResolver) AvailableJobs(ctx context.Context, limit *int, offset *int, city *string) ([]*model.Booking, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	limitVal := 20
+	offsetVal := 0
+	cityVal := ""
+
+	if limit != nil {
+		limitVal = *limit
+	}
+	if offset != nil {
+		offsetVal = *offset
+	}
+	if city != nil {
+		cityVal = *city
+	}
+
+	bookings, err := r.BookingService.GetAvailableJobs(userID, cityVal, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Booking, len(bookings))
+	for i, booking := range bookings {
+		result[i] = convertBookingToGraphQL(booking)
+	}
+
+	return result, nil
+}
+
+// Checkin is the resolver for the checkin field.
+func (r *queryResolver) Checkin(ctx context.Context, bookingID string) (*model.Checkin, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	checkin, err := r.CheckinService.GetCheckinByBookingID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkin == nil {
+		return nil, nil
+	}
+
+	return convertCheckinToGraphQL(checkin), nil
+}
+
+// BookingPayments is the resolver for the bookingPayments field.
+func (r *queryResolver) BookingPayments(ctx context.Context, bookingID string) ([]*model.Payment, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	payments, err := r.PaymentService.GetPaymentsByBooking(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Payment, len(payments))
+	for i, payment := range payments {
+		result[i] = convertPaymentToGraphQL(payment)
+	}
+
+	return result, nil
+}
+
+// Payment is the resolver for the payment field.
+func (r *queryResolver) Payment(ctx context.Context, id string) (*model.Payment, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	payment, err := r.PaymentService.GetPayment(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPaymentToGraphQL(payment), nil
+}
+
+// Invoice is the resolver for the invoice field.
+func (r *queryResolver) Invoice(ctx context.Context, id string) (*model.Invoice, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	invoice, err := r.InvoiceService.GetInvoice(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertInvoiceToGraphQL(invoice), nil
+}
+
+// InvoiceByBooking is the resolver for the invoiceByBooking field.
+func (r *queryResolver) InvoiceByBooking(ctx context.Context, bookingID string) (*model.Invoice, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	invoice, err := r.InvoiceService.GetInvoiceByBookingID(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, nil
+	}
+
+	return convertInvoiceToGraphQL(invoice), nil
+}
+
+// BookingProforma is the resolver for the bookingProforma field.
+func (r *queryResolver) BookingProforma(ctx context.Context, bookingID string) (*model.Proforma, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	// Admins see any booking's proforma, clients only their own
+	if _, err := middleware.RequireAdmin(ctx); err == nil {
+		userID = ""
+	}
+
+	proforma, err := r.ProformaService.GetProformaByBookingID(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if proforma == nil {
+		return nil, nil
+	}
+
+	return convertProformaToGraphQL(proforma), nil
+}
+
+// MyInvoices is the resolver for the myInvoices field.
+func (r *queryResolver) MyInvoices(ctx context.Context) ([]*model.Invoice, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	invoices, err := r.InvoiceService.GetMyInvoices(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Invoice, len(invoices))
+	for i, invoice := range invoices {
+		result[i] = convertInvoiceToGraphQL(invoice)
+	}
+
+	return result, nil
+}
+
+// InvoiceConfirmation is the resolver for the invoiceConfirmation field.
+func (r *queryResolver) InvoiceConfirmation(ctx context.Context, invoiceID string) (*model.InvoiceConfirmationFile, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	invoice, err := r.InvoiceService.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check authorization (only the invoice's client or admin can download)
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		isClient, err := r.InvoiceService.IsInvoiceClient(invoice, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isClient {
+			return nil, fmt.Errorf("unauthorized: you can only download your own invoices")
+		}
+	}
+
+	confirmation, content, err := r.InvoiceService.GetANAFConfirmation(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.InvoiceConfirmationFile{
+		FileName:    filepath.Base(confirmation.FilePath),
+		ContentType: "application/zip",
+		Content:     base64.StdEncoding.EncodeToString(content),
+		Sha256:      confirmation.SHA256,
+		RetainUntil: confirmation.RetainUntil,
+	}, nil
+}
+
+// ReviewByBooking is the resolver for the reviewByBooking field.
+func (r *queryResolver) ReviewByBooking(ctx context.Context, bookingID string) (*model.Review, error) {
+	review, err := r.ReviewService.GetReviewByBookingID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review == nil {
+		return nil, nil
+	}
+
+	return convertReviewToGraphQL(review), nil
+}
+
+// CleanerReviews is the resolver for the cleanerReviews field.
+func (r *queryResolver) CleanerReviews(ctx context.Context, cleanerID string, limit *int, offset *int) ([]*model.Review, error) {
+	limitVal := 20
+	offsetVal := 0
+
+	if limit != nil {
+		limitVal = *limit
+	}
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	reviews, err := r.ReviewService.GetCleanerReviews(cleanerID, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert array
+	result := make([]*model.Review, len(reviews))
+	for i, review := range reviews {
+		result[i] = convertReviewToGraphQL(review)
+	}
+
+	return result, nil
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+// Dispute is the resolver for the dispute field.
+func (r *queryResolver) Dispute(ctx context.Context, id string) (*model.Dispute, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	user, err := r.AuthService.GetUserByID(userID)
+	dispute, err := r.DisputeService.GetDispute(id, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if user == nil {
-		return nil, fmt.Errorf("user not found")
+	if dispute == nil {
+		return nil, nil
 	}
 
-	return convertUserToGraphQL(user), nil
+	return convertDisputeToGraphQL(dispute), nil
 }
 
-// User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
-	// Require admin authorization
-	_, err := middleware.RequireAdmin(ctx)
-	if err != nil {
-		return nil, err
+// DisputeByBooking is the resolver for the disputeByBooking field.
+func (r *queryResolver) DisputeByBooking(ctx context.Context, bookingID string) (*model.Dispute, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
 	}
 
-	user, err := r.AuthService.GetUserByID(id)
+	dispute, err := r.DisputeService.GetDisputeByBookingID(bookingID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if user == nil {
-		return nil, fmt.Errorf("user not found")
+	if dispute == nil {
+		return nil, nil
 	}
 
-	return convertUserToGraphQL(user), nil
+	return convertDisputeToGraphQL(dispute), nil
 }
 
-// MyClientProfile is the resolver for the myClientProfile field.
-func (r *queryResolver) MyClientProfile(ctx context.Context) (*model.Client, error) {
+// OpenDisputes is the resolver for the openDisputes field.
+func (r *queryResolver) OpenDisputes(ctx context.Context, limit *int) ([]*model.Dispute, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	client, err := r.ClientService.GetOrCreateClientProfile(userID)
+	// Check if user is admin
+	user, err := r.AuthService.GetUserByID(userID)
 	if err != nil {
 		return nil, err
 	}
+	if user == nil || user.Role != "PLATFORM_ADMIN" {
+		return nil, fmt.Errorf("unauthorized: admin access required")
+	}
 
-	return convertClientToGraphQL(client), nil
-}
-
-// MyAddresses is the resolver for the myAddresses field.
-func (r *queryResolver) MyAddresses(ctx context.Context) ([]*model.Address, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+	limitVal := 50
+	if limit != nil {
+		limitVal = *limit
 	}
 
-	addresses, err := r.AddressService.GetUserAddresses(userID)
+	disputes, err := r.DisputeService.GetOpenDisputes(limitVal)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert array
-	result := make([]*model.Address, len(addresses))
-	for i, addr := range addresses {
-		result[i] = convertAddressToGraphQL(addr)
+	result := make([]*model.Dispute, len(disputes))
+	for i, dispute := range disputes {
+		result[i] = convertDisputeToGraphQL(dispute)
 	}
 
 	return result, nil
 }
 
-// Address is the resolver for the address field.
-func (r *queryResolver) Address(ctx context.Context, id string) (*model.Address, error) {
+// BookingPhotos is the resolver for the bookingPhotos field.
+func (r *queryResolver) BookingPhotos(ctx context.Context, bookingID string) ([]*model.Photo, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	address, err := r.AddressService.GetAddress(id, userID)
+	photos, err := r.PhotoService.GetPhotosByBookingID(bookingID)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertAddressToGraphQL(address), nil
+	result := make([]*model.Photo, len(photos))
+	for i, photo := range photos {
+		result[i] = convertPhotoToGraphQL(photo)
+	}
+
+	return result, nil
 }
 
-// MyCleanerProfile is the resolver for the myCleanerProfile field.
-func (r *queryResolver) MyCleanerProfile(ctx context.Context) (*model.Cleaner, error) {
+// DisputePhotos is the resolver for the disputePhotos field.
+func (r *queryResolver) DisputePhotos(ctx context.Context, disputeID string) ([]*model.Photo, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	cleaner, err := r.CleanerService.GetOrCreateCleanerProfile(userID)
-	if err != nil {
-		return nil, nil
-	}
-
-	return convertCleanerToGraphQL(cleaner), nil
-}
-
-// Cleaner is the resolver for the cleaner field.
-func (r *queryResolver) Cleaner(ctx context.Context, id string) (*model.Cleaner, error) {
-	cleaner, err := r.CleanerService.GetCleanerByID(id)
+	photos, err := r.PhotoService.GetPhotosByDisputeID(disputeID)
 	if err != nil {
 		return nil, err
 	}
 
-	if cleaner == nil {
-		return nil, fmt.Errorf("cleaner not found")
+	result := make([]*model.Photo, len(photos))
+	for i, photo := range photos {
+		result[i] = convertPhotoToGraphQL(photo)
 	}
 
-	return convertCleanerToGraphQL(cleaner), nil
+	return result, nil
 }
 
-// ApprovedCleaners is the resolver for the approvedCleaners field.
-func (r *queryResolver) ApprovedCleaners(ctx context.Context) ([]*model.Cleaner, error) {
-	// Get approved cleaners (no limit, no search filter)
-	cleaners, err := r.CleanerService.GetApprovedCleaners(1000, 0, "")
+// BookingMessages is the resolver for the bookingMessages field.
+func (r *queryResolver) BookingMessages(ctx context.Context, bookingID string) ([]*model.Message, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	messages, err := r.MessagingService.GetBookingMessages(bookingID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Cleaner, len(cleaners))
-	for i, cleaner := range cleaners {
-		result[i] = convertCleanerToGraphQL(cleaner)
+	result := make([]*model.Message, len(messages))
+	for i, msg := range messages {
+		result[i] = convertMessageToGraphQL(msg)
 	}
 
 	return result, nil
 }
 
-// MyBookings is the resolver for the myBookings field.
-func (r *queryResolver) MyBookings(ctx context.Context, filter *model.BookingFilter) ([]*model.Booking, error) {
+// MyConversations is the resolver for the myConversations field.
+func (r *queryResolver) MyConversations(ctx context.Context) ([]*model.Message, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	role, ok := middleware.GetUserRoleFromContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("role not found")
-	}
-
-	var bookings []*models.Booking
-	var err error
-
-	// Determine which bookings to fetch based on user role
-	if role == "CLEANER" {
-		// Get cleaner profile
-		cleaner, err := r.CleanerService.GetOrCreateCleanerProfile(userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get cleaner profile: %w", err)
-		}
-
-		// Fetch cleaner's bookings using GetBookingsByCleanerIDs
-		var filterStr *string
-		if filter != nil {
-			str := string(*filter)
-			filterStr = &str
-		}
-		bookings, err = r.BookingService.GetBookingsByCleanerIDs([]string{cleaner.ID}, filterStr)
-	} else {
-		// Default to client bookings for CLIENT role or others
-		if filter != nil {
-			bookings, err = r.BookingService.GetClientBookingsFiltered(userID, string(*filter))
-		} else {
-			bookings, err = r.BookingService.GetClientBookings(userID)
-		}
-	}
-
+	messages, err := r.MessagingService.GetMyConversations(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert array
-	result := make([]*model.Booking, len(bookings))
-	for i, booking := range bookings {
-		result[i] = convertBookingToGraphQL(booking)
+	result := make([]*model.Message, len(messages))
+	for i, msg := range messages {
+		result[i] = convertMessageToGraphQL(msg)
 	}
 
 	return result, nil
 }
 
-// Booking is the resolver for the booking field.
-func (r *queryResolver) Booking(ctx context.Context, id string) (*model.Booking, error) {
+// UnreadMessagesCount is the resolver for the unreadMessagesCount field.
+func (r *queryResolver) UnreadMessagesCount(ctx context.Context) (int, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+		return 0, fmt.Errorf("authentication required")
 	}
 
-	// Check if user is admin - if so, bypass authorization check
-	user, err := r.AuthService.GetUserByID(userID)
-	if err == nil && user != nil && user.Role == models.RolePlatformAdmin {
-		// Admin can view any booking - call service method directly
-		booking, err := r.BookingService.AdminGetBooking(id)
-		if err != nil {
-			return nil, err
-		}
-		return convertBookingToGraphQL(booking), nil
+	count, err := r.MessagingService.GetUnreadCount(userID)
+	if err != nil {
+		return 0, err
 	}
 
-	// For non-admin users, use normal authorization
-	booking, err := r.BookingService.GetBooking(id, userID)
+	return count, nil
+}
+
+// BookingUnreadCount is the resolver for the bookingUnreadCount field.
+func (r *queryResolver) BookingUnreadCount(ctx context.Context, bookingID string) (int, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return 0, fmt.Errorf("authentication required")
+	}
+
+	count, err := r.MessagingService.GetUnreadCountForBooking(bookingID, userID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	return convertBookingToGraphQL(booking), nil
+	return count, nil
 }
 
-// GetPriceQuote is the resolver for the getPriceQuote field.
-func (r *queryResolver) GetPriceQuote(ctx context.Context, input model.PriceQuoteInput) (*model.PriceQuote, error) {
+// MyPayouts is the resolver for the myPayouts field.
+func (r *queryResolver) MyPayouts(ctx context.Context, limit *int, offset *int) ([]*model.Payout, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	// Handle optional areaSqm
-	areaSqm := 0
-	if input.AreaSqm != nil {
-		areaSqm = *input.AreaSqm
+	limitVal := 10
+	offsetVal := 0
+	if limit != nil {
+		limitVal = *limit
 	}
-
-	// Handle optional frequency
-	frequency := "one_time"
-	if input.Frequency != nil {
-		frequency = *input.Frequency
+	if offset != nil {
+		offsetVal = *offset
 	}
 
-	quote, err := r.PricingService.CalculatePrice(
-		userID,
-		models.ServiceType(input.ServiceType),
-		areaSqm,
-		input.EstimatedHours,
-		input.ScheduledDate,
-		input.ScheduledTime,
-		input.IncludesWindows,
-		input.NumberOfWindows,
-		input.IncludesCarpet,
-		input.CarpetAreaSqm,
-		false, // includesFridge - not available in PriceQuoteInput (legacy)
-		false, // includesOven - not available in PriceQuoteInput (legacy)
-		false, // includesBalcony - not available in PriceQuoteInput (legacy)
-		false, // includesSupplies - not available in PriceQuoteInput (legacy)
-		frequency,
-	)
+	payouts, err := r.PayoutService.GetPayoutsByCleanerID(userID, limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.PriceQuote{
-		BasePrice:      quote.BasePrice,
-		AddonsPrice:    quote.AddonsPrice,
-		Subtotal:       quote.Subtotal,
-		Discount:       quote.Discount,
-		PlatformFee:    quote.PlatformFee,
-		TotalPrice:     quote.TotalPrice,
-		CleanerPayout:  quote.CleanerPayout,
-		EstimatedHours: quote.EstimatedHours,
-		Breakdown: &model.PriceBreakdown{
-			BasePricePerHour:      quote.Breakdown.BasePricePerHour,
-			HoursCharged:          quote.Breakdown.HoursCharged,
-			AreaPrice:             quote.Breakdown.AreaPrice,
-			WindowsPrice:          quote.Breakdown.WindowsPrice,
-			CarpetPrice:           quote.Breakdown.CarpetPrice,
-			TimeMultiplier:        quote.Breakdown.TimeMultiplier,
-			DiscountPercentage:    quote.Breakdown.DiscountPercentage,
-			PlatformFeePercentage: quote.Breakdown.PlatformFeePercentage,
-		},
-	}, nil
+	result := make([]*model.Payout, len(payouts))
+	for i, payout := range payouts {
+		result[i] = convertPayoutToGraphQL(payout)
+	}
+
+	return result, nil
 }
 
-// AvailableJobs is the resolver for the availableJobs field.
-func (r *queryResolver) AvailableJobs(ctx context.Context, limit *int, offset *int, city *string) ([]*model.Booking, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+// MyPayoutAdjustments is the resolver for the myPayoutAdjustments field.
+func (r *queryResolver) MyPayoutAdjustments(ctx context.Context, limit *int, offset *int) ([]*model.PayoutAdjustment, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	limitVal := 20
 	offsetVal := 0
-	cityVal := ""
-
 	if limit != nil {
 		limitVal = *limit
 	}
 	if offset != nil {
 		offsetVal = *offset
 	}
-	if city != nil {
-		cityVal = *city
-	}
 
-	bookings, err := r.BookingService.GetAvailableJobs(userID, cityVal, limitVal, offsetVal)
+	adjustments, err := r.PayoutAdjustmentService.GetAdjustmentsForUser(userID, limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert array
-	result := make([]*model.Booking, len(bookings))
-	for i, booking := range bookings {
-		result[i] = convertBookingToGraphQL(booking)
+	result := make([]*model.PayoutAdjustment, len(adjustments))
+	for i, adjustment := range adjustments {
+		result[i] = convertPayoutAdjustmentToGraphQL(adjustment)
 	}
 
 	return result, nil
 }
 
-// Checkin is the resolver for the checkin field.
-func (r *queryResolver) Checkin(ctx context.Context, bookingID string) (*model.Checkin, error) {
+// Payout is the resolver for the payout field.
+func (r *queryResolver) Payout(ctx context.Context, id string) (*model.Payout, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	checkin, err := r.CheckinService.GetCheckinByBookingID(bookingID)
+	payout, lineItems, err := r.PayoutService.GetPayoutWithLineItems(id)
 	if err != nil {
 		return nil, err
 	}
-
-	if checkin == nil {
+	if payout == nil {
 		return nil, nil
 	}
 
-	return convertCheckinToGraphQL(checkin), nil
-}
-
-// BookingPayments is the resolver for the bookingPayments field.
-func (r *queryResolver) BookingPayments(ctx context.Context, bookingID string) ([]*model.Payment, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+	// Check authorization (only cleaner or admin can view)
+	user, err := r.AuthService.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if payout.CleanerID != userID && user.Role != "PLATFORM_ADMIN" {
+		return nil, fmt.Errorf("unauthorized: you can only view your own payouts")
 	}
 
-	payments, err := r.PaymentService.GetPaymentsByBooking(bookingID, userID)
+	result := convertPayoutToGraphQLWithLineItems(payout, lineItems)
+
+	selfBilledInvoice, err := r.SelfBillingService.GetInvoiceForPayout(payout.ID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Convert array
-	result := make([]*model.Payment, len(payments))
-	for i, payment := range payments {
-		result[i] = convertPaymentToGraphQL(payment)
+	if selfBilledInvoice != nil {
+		result.SelfBilledInvoice = convertSelfBilledInvoiceToGraphQL(selfBilledInvoice)
 	}
 
 	return result, nil
 }
 
-// Payment is the resolver for the payment field.
-func (r *queryResolver) Payment(ctx context.Context, id string) (*model.Payment, error) {
+// PayoutStatement is the resolver for the payoutStatement field.
+func (r *queryResolver) PayoutStatement(ctx context.Context, payoutID string) (*model.PayoutStatementFile, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	payment, err := r.PaymentService.GetPayment(id, userID)
+	payout, _, err := r.PayoutService.GetPayoutWithLineItems(payoutID)
 	if err != nil {
 		return nil, err
 	}
 
-	return convertPaymentToGraphQL(payment), nil
-}
-
-// Invoice is the resolver for the invoice field.
-func (r *queryResolver) Invoice(ctx context.Context, id string) (*model.Invoice, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
-	}
-
-	invoice, err := r.InvoiceService.GetInvoice(id, userID)
+	// Check authorization (the cleaner, the admin of the company the payout was paid to, or a platform admin)
+	user, err := r.AuthService.GetUserByID(userID)
 	if err != nil {
 		return nil, err
 	}
-
-	return convertInvoiceToGraphQL(invoice), nil
-}
-
-// InvoiceByBooking is the resolver for the invoiceByBooking field.
-func (r *queryResolver) InvoiceByBooking(ctx context.Context, bookingID string) (*model.Invoice, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+	if payout.CleanerID != userID && user.Role != "PLATFORM_ADMIN" {
+		isCompanyAdmin := false
+		if payout.CompanyPayoutID.Valid {
+			companyPayout, err := r.PayoutService.GetCompanyPayout(payout.CompanyPayoutID.String)
+			if err != nil {
+				return nil, err
+			}
+			if companyPayout != nil {
+				if isCompanyAdmin, err = r.PayoutService.IsCompanyAdmin(companyPayout.CompanyID, userID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if !isCompanyAdmin {
+			return nil, fmt.Errorf("unauthorized: you can only download your own payout statements")
+		}
 	}
 
-	invoice, err := r.InvoiceService.GetInvoiceByBookingID(bookingID, userID)
+	content, fileName, err := r.PayoutService.GeneratePayoutStatementPDF(payout.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	if invoice == nil {
-		return nil, nil
-	}
-
-	return convertInvoiceToGraphQL(invoice), nil
+	return &model.PayoutStatementFile{
+		FileName:    fileName,
+		ContentType: "application/pdf",
+		Content:     base64.StdEncoding.EncodeToString(content),
+	}, nil
 }
 
-// MyInvoices is the resolver for the myInvoices field.
-func (r *queryResolver) MyInvoices(ctx context.Context) ([]*model.Invoice, error) {
+// MyCommissionInvoices is the resolver for the myCommissionInvoices field.
+func (r *queryResolver) MyCommissionInvoices(ctx context.Context, limit *int, offset *int) ([]*model.CommissionInvoice, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	invoices, err := r.InvoiceService.GetMyInvoices(userID)
+	limitVal := 20
+	offsetVal := 0
+	if limit != nil {
+		limitVal = *limit
+	}
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	invoices, err := r.CommissionInvoiceService.GetInvoicesForUser(userID, limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert array
-	result := make([]*model.Invoice, len(invoices))
+	result := make([]*model.CommissionInvoice, len(invoices))
 	for i, invoice := range invoices {
-		result[i] = convertInvoiceToGraphQL(invoice)
+		result[i] = convertCommissionInvoiceToGraphQL(invoice)
 	}
-
 	return result, nil
 }
 
-// ReviewByBooking is the resolver for the reviewByBooking field.
-func (r *queryResolver) ReviewByBooking(ctx context.Context, bookingID string) (*model.Review, error) {
-	review, err := r.ReviewService.GetReviewByBookingID(bookingID)
+// MySelfBilling is the resolver for the mySelfBilling field.
+func (r *queryResolver) MySelfBilling(ctx context.Context) (*model.CleanerSelfBilling, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if review == nil {
+	settings, err := r.SelfBillingService.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
 		return nil, nil
 	}
 
-	return convertReviewToGraphQL(review), nil
+	return convertCleanerSelfBillingToGraphQL(settings), nil
 }
 
-// CleanerReviews is the resolver for the cleanerReviews field.
-func (r *queryResolver) CleanerReviews(ctx context.Context, cleanerID string, limit *int, offset *int) ([]*model.Review, error) {
+// MySelfBilledInvoices is the resolver for the mySelfBilledInvoices field.
+func (r *queryResolver) MySelfBilledInvoices(ctx context.Context, limit *int, offset *int) ([]*model.SelfBilledInvoice, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	limitVal := 20
 	offsetVal := 0
-
 	if limit != nil {
 		limitVal = *limit
 	}
@@ -1757,60 +2832,50 @@ func (r *queryResolver) CleanerReviews(ctx context.Context, cleanerID string, li
 		offsetVal = *offset
 	}
 
-	reviews, err := r.ReviewService.GetCleanerReviews(cleanerID, limitVal, offsetVal)
+	invoices, err := r.SelfBillingService.GetInvoicesForUser(userID, limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert array
-	result := make([]*model.Review, len(reviews))
-	for i, review := range reviews {
-		result[i] = convertReviewToGraphQL(review)
+	result := make([]*model.SelfBilledInvoice, len(invoices))
+	for i, invoice := range invoices {
+		result[i] = convertSelfBilledInvoiceToGraphQL(invoice)
 	}
 
 	return result, nil
 }
 
-// Dispute is the resolver for the dispute field.
-func (r *queryResolver) Dispute(ctx context.Context, id string) (*model.Dispute, error) {
+// PendingPayouts is the resolver for the pendingPayouts field.
+func (r *queryResolver) PendingPayouts(ctx context.Context) ([]*model.Payout, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	dispute, err := r.DisputeService.GetDispute(id, userID)
+	// Check if user is admin
+	user, err := r.AuthService.GetUserByID(userID)
 	if err != nil {
 		return nil, err
 	}
-
-	if dispute == nil {
-		return nil, nil
-	}
-
-	return convertDisputeToGraphQL(dispute), nil
-}
-
-// DisputeByBooking is the resolver for the disputeByBooking field.
-func (r *queryResolver) DisputeByBooking(ctx context.Context, bookingID string) (*model.Dispute, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+	if user == nil || user.Role != "PLATFORM_ADMIN" {
+		return nil, fmt.Errorf("unauthorized: admin access required")
 	}
 
-	dispute, err := r.DisputeService.GetDisputeByBookingID(bookingID, userID)
+	payouts, err := r.PayoutService.GetPendingPayouts()
 	if err != nil {
 		return nil, err
 	}
 
-	if dispute == nil {
-		return nil, nil
+	result := make([]*model.Payout, len(payouts))
+	for i, payout := range payouts {
+		result[i] = convertPayoutToGraphQL(payout)
 	}
 
-	return convertDisputeToGraphQL(dispute), nil
+	return result, nil
 }
 
-// OpenDisputes is the resolver for the openDisputes field.
-func (r *queryResolver) OpenDisputes(ctx context.Context, limit *int) ([]*model.Dispute, error) {
+// Payouts is the resolver for the payouts field.
+func (r *queryResolver) Payouts(ctx context.Context, status *model.PayoutStatus, limit *int, offset *int) ([]*model.Payout, error) {
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("authentication required")
@@ -1825,252 +2890,234 @@ func (r *queryResolver) OpenDisputes(ctx context.Context, limit *int) ([]*model.
 		return nil, fmt.Errorf("unauthorized: admin access required")
 	}
 
-	limitVal := 50
-	if limit != nil {
-		limitVal = *limit
+	// Convert status to string if provided
+	var statusStr string
+	if status != nil {
+		statusStr = status.String()
 	}
 
-	disputes, err := r.DisputeService.GetOpenDisputes(limitVal)
+	payouts, err := r.PayoutService.GetPayoutsByStatus(statusStr, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert array
-	result := make([]*model.Dispute, len(disputes))
-	for i, dispute := range disputes {
-		result[i] = convertDisputeToGraphQL(dispute)
+	result := make([]*model.Payout, len(payouts))
+	for i, payout := range payouts {
+		result[i] = convertPayoutToGraphQL(payout)
 	}
 
 	return result, nil
 }
 
-// BookingPhotos is the resolver for the bookingPhotos field.
-func (r *queryResolver) BookingPhotos(ctx context.Context, bookingID string) ([]*model.Photo, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
-	}
-
-	photos, err := r.PhotoService.GetPhotosByBookingID(bookingID)
-	if err != nil {
+// PreviewMonthlyPayouts is the resolver for the previewMonthlyPayouts field.
+func (r *queryResolver) PreviewMonthlyPayouts(ctx context.Context, input model.GeneratePayoutsInput) (*model.PayoutRunSummary, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Photo, len(photos))
-	for i, photo := range photos {
-		result[i] = convertPhotoToGraphQL(photo)
-	}
-
-	return result, nil
-}
-
-// DisputePhotos is the resolver for the disputePhotos field.
-func (r *queryResolver) DisputePhotos(ctx context.Context, disputeID string) ([]*model.Photo, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
-	}
-
-	photos, err := r.PhotoService.GetPhotosByDisputeID(disputeID)
+	run, err := r.PayoutService.RunMonthlyPayouts(input.Year, time.Month(input.Month), true)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Photo, len(photos))
-	for i, photo := range photos {
-		result[i] = convertPhotoToGraphQL(photo)
-	}
-
-	return result, nil
+	return convertPayoutRunToGraphQL(run), nil
 }
 
-// BookingMessages is the resolver for the bookingMessages field.
-func (r *queryResolver) BookingMessages(ctx context.Context, bookingID string) ([]*model.Message, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
-	}
-
-	messages, err := r.MessagingService.GetBookingMessages(bookingID, userID)
-	if err != nil {
+// PayoutBatches is the resolver for the payoutBatches field.
+func (r *queryResolver) PayoutBatches(ctx context.Context, limit *int, offset *int) ([]*model.PayoutBatch, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Message, len(messages))
-	for i, msg := range messages {
-		result[i] = convertMessageToGraphQL(msg)
+	limitVal := 20
+	if limit != nil {
+		limitVal = *limit
 	}
-
-	return result, nil
-}
-
-// MyConversations is the resolver for the myConversations field.
-func (r *queryResolver) MyConversations(ctx context.Context) ([]*model.Message, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+	offsetVal := 0
+	if offset != nil {
+		offsetVal = *offset
 	}
 
-	messages, err := r.MessagingService.GetMyConversations(userID)
+	batches, err := r.PayoutBatchService.GetPayoutBatches(limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Message, len(messages))
-	for i, msg := range messages {
-		result[i] = convertMessageToGraphQL(msg)
+	result := make([]*model.PayoutBatch, len(batches))
+	for i, batch := range batches {
+		payouts, err := r.PayoutBatchService.GetBatchPayouts(batch.ID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = convertPayoutBatchToGraphQL(batch, payouts)
 	}
 
 	return result, nil
 }
 
-// UnreadMessagesCount is the resolver for the unreadMessagesCount field.
-func (r *queryResolver) UnreadMessagesCount(ctx context.Context) (int, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return 0, fmt.Errorf("authentication required")
-	}
-
-	count, err := r.MessagingService.GetUnreadCount(userID)
-	if err != nil {
-		return 0, err
-	}
-
-	return count, nil
-}
-
-// BookingUnreadCount is the resolver for the bookingUnreadCount field.
-func (r *queryResolver) BookingUnreadCount(ctx context.Context, bookingID string) (int, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return 0, fmt.Errorf("authentication required")
+// PayoutBatch is the resolver for the payoutBatch field.
+func (r *queryResolver) PayoutBatch(ctx context.Context, id string) (*model.PayoutBatch, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	count, err := r.MessagingService.GetUnreadCountForBooking(bookingID, userID)
+	batch, payouts, err := r.PayoutBatchService.GetPayoutBatch(id)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	if batch == nil {
+		return nil, nil
 	}
 
-	return count, nil
+	return convertPayoutBatchToGraphQL(batch, payouts), nil
 }
 
-// MyPayouts is the resolver for the myPayouts field.
-func (r *queryResolver) MyPayouts(ctx context.Context, limit *int, offset *int) ([]*model.Payout, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+// PendingCompanyPayouts is the resolver for the pendingCompanyPayouts field.
+func (r *queryResolver) PendingCompanyPayouts(ctx context.Context, limit *int, offset *int) ([]*model.CompanyPayout, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	limitVal := 10
-	offsetVal := 0
+	limitVal := 50
 	if limit != nil {
 		limitVal = *limit
 	}
+	offsetVal := 0
 	if offset != nil {
 		offsetVal = *offset
 	}
 
-	payouts, err := r.PayoutService.GetPayoutsByCleanerID(userID, limitVal, offsetVal)
+	companyPayouts, err := r.PayoutService.GetCompanyPayoutsByStatus(models.PayoutStatusPending, limitVal, offsetVal)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Payout, len(payouts))
-	for i, payout := range payouts {
-		result[i] = convertPayoutToGraphQL(payout)
+	result := make([]*model.CompanyPayout, len(companyPayouts))
+	for i, companyPayout := range companyPayouts {
+		breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = convertCompanyPayoutToGraphQL(companyPayout, breakdown)
 	}
 
 	return result, nil
 }
 
-// Payout is the resolver for the payout field.
-func (r *queryResolver) Payout(ctx context.Context, id string) (*model.Payout, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+// MyLedgerBalance is the resolver for the myLedgerBalance field.
+func (r *queryResolver) MyLedgerBalance(ctx context.Context) (float64, error) {
+	userID, err := middleware.RequireCleanerRole(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	payout, lineItems, err := r.PayoutService.GetPayoutWithLineItems(id)
+	return r.LedgerService.GetCleanerBalanceByUserID(userID)
+}
+
+// CleanerLedgerBalance is the resolver for the cleanerLedgerBalance field.
+func (r *queryResolver) CleanerLedgerBalance(ctx context.Context, cleanerID string) (float64, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return r.LedgerService.GetCleanerBalance(cleanerID, time.Now())
+}
+
+// TrialBalance is the resolver for the trialBalance field.
+func (r *queryResolver) TrialBalance(ctx context.Context, asOf *time.Time) (*model.TrialBalance, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if payout == nil {
-		return nil, nil
+
+	at := time.Now()
+	if asOf != nil {
+		at = *asOf
 	}
 
-	// Check authorization (only cleaner or admin can view)
-	user, err := r.AuthService.GetUserByID(userID)
+	balance, err := r.LedgerService.GetTrialBalance(at)
 	if err != nil {
 		return nil, err
 	}
-	if payout.CleanerID != userID && user.Role != "PLATFORM_ADMIN" {
-		return nil, fmt.Errorf("unauthorized: you can only view your own payouts")
-	}
 
-	return convertPayoutToGraphQLWithLineItems(payout, lineItems), nil
+	return convertTrialBalanceToGraphQL(balance), nil
 }
 
-// PendingPayouts is the resolver for the pendingPayouts field.
-func (r *queryResolver) PendingPayouts(ctx context.Context) ([]*model.Payout, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
-	}
-
-	// Check if user is admin
-	user, err := r.AuthService.GetUserByID(userID)
+// MyWallet is the resolver for the myWallet field.
+func (r *queryResolver) MyWallet(ctx context.Context, limit *int, offset *int) (*model.Wallet, error) {
+	userID, err := middleware.RequireAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil || user.Role != "PLATFORM_ADMIN" {
-		return nil, fmt.Errorf("unauthorized: admin access required")
+
+	l, o := 50, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
 	}
 
-	payouts, err := r.PayoutService.GetPendingPayouts()
+	wallet, err := r.WalletService.GetWallet(userID, l, o)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Payout, len(payouts))
-	for i, payout := range payouts {
-		result[i] = convertPayoutToGraphQL(payout)
-	}
-
-	return result, nil
+	return convertWalletToGraphQL(wallet), nil
 }
 
-// Payouts is the resolver for the payouts field.
-func (r *queryResolver) Payouts(ctx context.Context, status *model.PayoutStatus, limit *int, offset *int) ([]*model.Payout, error) {
-	userID, ok := middleware.GetUserIDFromContext(ctx)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("authentication required")
+// ClientWallet is the resolver for the clientWallet field.
+func (r *queryResolver) ClientWallet(ctx context.Context, userID string, limit *int, offset *int) (*model.Wallet, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if user is admin
-	user, err := r.AuthService.GetUserByID(userID)
+	l, o := 50, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	wallet, err := r.WalletService.GetWallet(userID, l, o)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil || user.Role != "PLATFORM_ADMIN" {
-		return nil, fmt.Errorf("unauthorized: admin access required")
+
+	return convertWalletToGraphQL(wallet), nil
+}
+
+// BankReconciliationQueue is the resolver for the bankReconciliationQueue field.
+func (r *queryResolver) BankReconciliationQueue(ctx context.Context, limit *int, offset *int) ([]*model.BankStatementLine, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Convert status to string if provided
-	var statusStr string
-	if status != nil {
-		statusStr = status.String()
+	l, o := 50, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
 	}
 
-	payouts, err := r.PayoutService.GetPayoutsByStatus(statusStr, limit, offset)
+	lines, err := r.BankReconciliationService.GetReconciliationQueue(l, o)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*model.Payout, len(payouts))
-	for i, payout := range payouts {
-		result[i] = convertPayoutToGraphQL(payout)
+	result := make([]*model.BankStatementLine, len(lines))
+	for i, line := range lines {
+		result[i] = convertBankStatementLineToGraphQL(line)
 	}
-
 	return result, nil
 }
 
@@ -2310,6 +3357,83 @@ func (r *queryResolver) CompanyInvoices(ctx context.Context, companyID string) (
 	return invoices, nil
 }
 
+// CompanyPayouts is the resolver for the companyPayouts field.
+func (r *queryResolver) CompanyPayouts(ctx context.Context, companyID string, limit *int, offset *int) ([]*model.CompanyPayout, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	// Company admins see their own company, platform admins any
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		isAdmin, err := r.PayoutService.IsCompanyAdmin(companyID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, fmt.Errorf("unauthorized: not an admin of this company")
+		}
+	}
+
+	limitVal := 12
+	if limit != nil {
+		limitVal = *limit
+	}
+	offsetVal := 0
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	companyPayouts, err := r.PayoutService.GetCompanyPayouts(companyID, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.CompanyPayout, len(companyPayouts))
+	for i, companyPayout := range companyPayouts {
+		breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = convertCompanyPayoutToGraphQL(companyPayout, breakdown)
+	}
+
+	return result, nil
+}
+
+// CompanyPayout is the resolver for the companyPayout field.
+func (r *queryResolver) CompanyPayout(ctx context.Context, id string) (*model.CompanyPayout, error) {
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok || userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	companyPayout, err := r.PayoutService.GetCompanyPayout(id)
+	if err != nil {
+		return nil, err
+	}
+	if companyPayout == nil {
+		return nil, nil
+	}
+
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		isAdmin, err := r.PayoutService.IsCompanyAdmin(companyPayout.CompanyID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAdmin {
+			return nil, fmt.Errorf("unauthorized: not an admin of this company")
+		}
+	}
+
+	breakdown, err := r.PayoutService.GetCompanyPayoutBreakdown(companyPayout.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCompanyPayoutToGraphQL(companyPayout, breakdown), nil
+}
+
 // Cleaners is the resolver for the cleaners field.
 func (r *queryResolver) Cleaners(ctx context.Context, limit *int, offset *int, status *model.ApprovalStatus, search *string) ([]*model.Cleaner, error) {
 	// Check if user is authenticated (admin only)
@@ -2456,6 +3580,108 @@ func (r *queryResolver) PlatformSettings(ctx context.Context) (*model.PlatformSe
 	return convertPlatformSettingsToGraphQL(settings), nil
 }
 
+// AnafAlerts is the resolver for the anafAlerts field.
+func (r *queryResolver) AnafAlerts(ctx context.Context, includeResolved *bool, limit *int, offset *int) ([]*model.ANAFAlert, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	includeResolvedVal := false
+	if includeResolved != nil {
+		includeResolvedVal = *includeResolved
+	}
+	limitVal := 50
+	if limit != nil {
+		limitVal = *limit
+	}
+	offsetVal := 0
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	alerts, err := r.ANAFWorker.GetAlerts(includeResolvedVal, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.ANAFAlert, len(alerts))
+	for i, alert := range alerts {
+		result[i] = convertANAFAlertToGraphQL(alert)
+	}
+	return result, nil
+}
+
+// InvoiceSeries is the resolver for the invoiceSeries field.
+func (r *queryResolver) InvoiceSeries(ctx context.Context) ([]*model.InvoiceSeries, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	series, err := r.InvoiceSeriesService.ListSeries()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.InvoiceSeries, len(series))
+	for i, s := range series {
+		result[i] = convertInvoiceSeriesToGraphQL(s)
+	}
+	return result, nil
+}
+
+// Proformas is the resolver for the proformas field.
+func (r *queryResolver) Proformas(ctx context.Context, status *model.ProformaStatus, limit *int, offset *int) ([]*model.Proforma, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var statusVal models.ProformaStatus
+	if status != nil {
+		statusVal = models.ProformaStatus(*status)
+	}
+	limitVal := 50
+	if limit != nil {
+		limitVal = *limit
+	}
+	offsetVal := 0
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	proformas, err := r.ProformaService.GetProformas(statusVal, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Proforma, len(proformas))
+	for i, proforma := range proformas {
+		result[i] = convertProformaToGraphQL(proforma)
+	}
+	return result, nil
+}
+
+// AccountingExport is the resolver for the accountingExport field.
+func (r *queryResolver) AccountingExport(ctx context.Context, format model.AccountingExportFormat, periodStart time.Time, periodEnd time.Time) ([]*model.AccountingExportFile, error) {
+	if _, err := middleware.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	files, err := r.AccountingExportService.Export(services.AccountingExportFormat(format), periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.AccountingExportFile, len(files))
+	for i, file := range files {
+		result[i] = &model.AccountingExportFile{
+			FileName:    file.FileName,
+			ContentType: file.ContentType,
+			Content:     base64.StdEncoding.EncodeToString(file.Content),
+		}
+	}
+	return result, nil
+}
+
 // CleanerStats is the resolver for the cleanerStats field.
 func (r *queryResolver) CleanerStats(ctx context.Context, cleanerID string) (*model.CleanerStats, error) {
 	// Require admin authorization
@@ -2547,6 +3773,36 @@ func (r *queryResolver) CleanerPayouts(ctx context.Context, cleanerID string, li
 	return result, nil
 }
 
+// CleanerPayoutAdjustments is the resolver for the cleanerPayoutAdjustments field.
+func (r *queryResolver) CleanerPayoutAdjustments(ctx context.Context, cleanerID string, limit *int, offset *int) ([]*model.PayoutAdjustment, error) {
+	// Require admin authorization
+	_, err := middleware.RequireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limitVal := 50
+	offsetVal := 0
+	if limit != nil {
+		limitVal = *limit
+	}
+	if offset != nil {
+		offsetVal = *offset
+	}
+
+	adjustments, err := r.PayoutAdjustmentService.GetCleanerAdjustments(cleanerID, limitVal, offsetVal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.PayoutAdjustment, len(adjustments))
+	for i, adjustment := range adjustments {
+		result[i] = convertPayoutAdjustmentToGraphQL(adjustment)
+	}
+
+	return result, nil
+}
+
 // AdminKPIs is the resolver for the adminKPIs field.
 func (r *queryResolver) AdminKPIs(ctx context.Context, period model.KPIPeriod) (*model.AdminKPIs, error) {
 	// Require admin authorization
@@ -2718,6 +3974,7 @@ func (r *queryResolver) CalculateBookingPrice(ctx context.Context, input model.P
 		Discount:       quote.Discount,
 		PlatformFee:    quote.PlatformFee,
 		TotalPrice:     quote.TotalPrice,
+		VatAmount:      quote.VATAmount,
 		CleanerPayout:  quote.CleanerPayout,
 		EstimatedHours: quote.EstimatedHours,
 		Breakdown: &model.PriceBreakdown{
@@ -2826,6 +4083,9 @@ func (r *queryResolver) PendingCleanerApplications(ctx context.Context) ([]*mode
 // Booking returns generated.BookingResolver implementation.
 func (r *Resolver) Booking() generated.BookingResolver { return &bookingResolver{r} }
 
+// Invoice returns generated.InvoiceResolver implementation.
+func (r *Resolver) Invoice() generated.InvoiceResolver { return &invoiceResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -2833,5 +4093,6 @@ func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResol
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
 type bookingResolver struct{ *Resolver }
+type invoiceResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type idempotencyKeyKey struct{}
+
+// IdempotencyKeyHeader is the HTTP header clients use to make mutations safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength matches the idempotency_keys.idempotency_key column size
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyKeyMiddleware injects the Idempotency-Key header into context
+func IdempotencyKeyMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"INVALID_IDEMPOTENCY_KEY","message":"Idempotency-Key must be at most 255 characters."}`))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), idempotencyKeyKey{}, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetIdempotencyKeyFromContext extracts the idempotency key from context
+func GetIdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok && key != ""
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ANAFAlertType is the reason a document needs an admin's attention
+type ANAFAlertType string
+
+const (
+	ANAFAlertDeadlineApproaching ANAFAlertType = "DEADLINE_APPROACHING" // Not yet received by ANAF, deadline close or past
+	ANAFAlertRejected            ANAFAlertType = "REJECTED"             // Rejected by ANAF validation
+	ANAFAlertRetriesExhausted    ANAFAlertType = "RETRIES_EXHAUSTED"    // Submission kept failing
+)
+
+// ANAFAlert flags a document the ANAF worker could not get accepted on its own
+type ANAFAlert struct {
+	ID             string
+	DocumentType   ANAFDocumentType
+	DocumentID     string
+	DocumentNumber string // Joined from the document's table
+	AlertType      ANAFAlertType
+	Message        string
+	Deadline       time.Time
+	ResolvedAt     sql.NullTime
+	ResolvedBy     sql.NullString
+	CreatedAt      time.Time
+}
+
+// ANAFAlertRepository handles ANAF alert database operations
+type ANAFAlertRepository struct {
+	db *sql.DB
+}
+
+// NewANAFAlertRepository creates a new ANAF alert repository
+func NewANAFAlertRepository(db *sql.DB) *ANAFAlertRepository {
+	return &ANAFAlertRepository{db: db}
+}
+
+const anafAlertSelect = `
+	SELECT a.id, a.invoice_id, a.credit_note_id, a.commission_invoice_id, a.self_billed_invoice_id,
+	       COALESCE(i.invoice_number, cn.credit_note_number, ci.invoice_number, sb.invoice_number),
+	       a.alert_type, a.message, a.deadline, a.resolved_at, a.resolved_by, a.created_at
+	FROM anaf_alerts a
+	LEFT JOIN invoices i ON i.id = a.invoice_id
+	LEFT JOIN credit_notes cn ON cn.id = a.credit_note_id
+	LEFT JOIN commission_invoices ci ON ci.id = a.commission_invoice_id
+	LEFT JOIN self_billed_invoices sb ON sb.id = a.self_billed_invoice_id`
+
+// CreateIfNew stores an alert unless the document already has an open alert of the same type.
+// It reports whether the alert was created.
+func (r *ANAFAlertRepository) CreateIfNew(alert *ANAFAlert) (bool, error) {
+	err := r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO anaf_alerts (%s, alert_type, message, deadline)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ((COALESCE(invoice_id, credit_note_id, commission_invoice_id, self_billed_invoice_id)), alert_type)
+		WHERE resolved_at IS NULL DO NOTHING
+		RETURNING id, created_at
+	`, anafDocumentTables[alert.DocumentType].ref), alert.DocumentID, alert.AlertType, alert.Message, alert.Deadline,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create ANAF alert: %w", err)
+	}
+	return true, nil
+}
+
+// GetByID finds an alert by ID
+func (r *ANAFAlertRepository) GetByID(id string) (*ANAFAlert, error) {
+	alerts, err := r.query(anafAlertSelect+` WHERE a.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	return alerts[0], nil
+}
+
+// List returns alerts newest first, only open ones unless includeResolved is set
+func (r *ANAFAlertRepository) List(includeResolved bool, limit, offset int) ([]*ANAFAlert, error) {
+	return r.query(anafAlertSelect+`
+		WHERE $1 OR a.resolved_at IS NULL
+		ORDER BY a.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, includeResolved, limit, offset)
+}
+
+// Resolve closes an alert on behalf of an admin
+func (r *ANAFAlertRepository) Resolve(id, adminID string) error {
+	_, err := r.db.Exec(`
+		UPDATE anaf_alerts SET resolved_at = NOW(), resolved_by = $2
+		WHERE id = $1 AND resolved_at IS NULL
+	`, id, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve ANAF alert: %w", err)
+	}
+	return nil
+}
+
+// ResolveAccepted closes the open alerts of documents ANAF has since accepted
+func (r *ANAFAlertRepository) ResolveAccepted() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE anaf_alerts SET resolved_at = NOW()
+		WHERE resolved_at IS NULL AND (
+		    invoice_id IN (SELECT id FROM invoices WHERE anaf_status = 'accepted')
+		    OR credit_note_id IN (SELECT id FROM credit_notes WHERE anaf_status = 'accepted')
+		    OR commission_invoice_id IN (SELECT id FROM commission_invoices WHERE anaf_status = 'accepted')
+		    OR self_billed_invoice_id IN (SELECT id FROM self_billed_invoices WHERE anaf_status = 'accepted')
+		)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve ANAF alerts: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *ANAFAlertRepository) query(query string, args ...interface{}) ([]*ANAFAlert, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ANAF alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*ANAFAlert
+	for rows.Next() {
+		alert := &ANAFAlert{}
+		var invoiceID, creditNoteID, commissionInvoiceID, selfBilledInvoiceID sql.NullString
+		if err := rows.Scan(&alert.ID, &invoiceID, &creditNoteID, &commissionInvoiceID, &selfBilledInvoiceID,
+			&alert.DocumentNumber, &alert.AlertType, &alert.Message, &alert.Deadline, &alert.ResolvedAt,
+			&alert.ResolvedBy, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan ANAF alert: %w", err)
+		}
+		alert.DocumentType, alert.DocumentID = anafDocumentRef(invoiceID, creditNoteID, commissionInvoiceID, selfBilledInvoiceID)
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ANAFConfirmation is the archived signed response ANAF returned for an accepted document
+type ANAFConfirmation struct {
+	ID           string
+	DocumentType ANAFDocumentType
+	DocumentID   string
+	DownloadID   string
+	FilePath     string
+	SHA256       string // Hex encoded
+	SizeBytes    int64
+	RetainUntil  time.Time
+	CreatedAt    time.Time
+}
+
+// ANAFConfirmationRepository handles ANAF confirmation archive records
+type ANAFConfirmationRepository struct {
+	db *sql.DB
+}
+
+// NewANAFConfirmationRepository creates a new ANAF confirmation repository
+func NewANAFConfirmationRepository(db *sql.DB) *ANAFConfirmationRepository {
+	return &ANAFConfirmationRepository{db: db}
+}
+
+// Create records an archived confirmation. A document has a single confirmation; recording a
+// second one fails.
+func (r *ANAFConfirmationRepository) Create(confirmation *ANAFConfirmation) error {
+	err := r.db.QueryRow(fmt.Sprintf(`
+		INSERT INTO anaf_confirmations (%s, download_id, file_path, sha256, size_bytes, retain_until)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, anafDocumentTables[confirmation.DocumentType].ref), confirmation.DocumentID, confirmation.DownloadID, confirmation.FilePath, confirmation.SHA256,
+		confirmation.SizeBytes, confirmation.RetainUntil).Scan(&confirmation.ID, &confirmation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create ANAF confirmation: %w", err)
+	}
+	return nil
+}
+
+// GetByInvoiceID finds the confirmation archived for an invoice
+func (r *ANAFConfirmationRepository) GetByInvoiceID(invoiceID string) (*ANAFConfirmation, error) {
+	confirmation := &ANAFConfirmation{DocumentType: ANAFDocumentInvoice}
+	err := r.db.QueryRow(`
+		SELECT id, invoice_id, download_id, file_path, sha256, size_bytes, retain_until, created_at
+		FROM anaf_confirmations
+		WHERE invoice_id = $1
+	`, invoiceID).Scan(&confirmation.ID, &confirmation.DocumentID, &confirmation.DownloadID, &confirmation.FilePath,
+		&confirmation.SHA256, &confirmation.SizeBytes, &confirmation.RetainUntil, &confirmation.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ANAF confirmation: %w", err)
+	}
+	return confirmation, nil
+}
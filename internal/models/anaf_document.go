@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ANAFDocumentType is a kind of document filed with ANAF e-Factura
+type ANAFDocumentType string
+
+const (
+	ANAFDocumentInvoice           ANAFDocumentType = "INVOICE"
+	ANAFDocumentCreditNote        ANAFDocumentType = "CREDIT_NOTE"
+	ANAFDocumentCommissionInvoice ANAFDocumentType = "COMMISSION_INVOICE"
+	ANAFDocumentSelfBilledInvoice ANAFDocumentType = "SELF_BILLED_INVOICE"
+)
+
+// ANAFDocumentTypes lists every document type filed with ANAF
+var ANAFDocumentTypes = []ANAFDocumentType{
+	ANAFDocumentInvoice,
+	ANAFDocumentCreditNote,
+	ANAFDocumentCommissionInvoice,
+	ANAFDocumentSelfBilledInvoice,
+}
+
+// anafDocumentTable describes where a document type is stored
+type anafDocumentTable struct {
+	table  string // Table holding the documents
+	number string // Column holding the document number
+	ref    string // Column of anaf_alerts and anaf_confirmations referencing the document
+	filter string // Condition a document must meet to be filed with ANAF
+}
+
+var anafDocumentTables = map[ANAFDocumentType]anafDocumentTable{
+	ANAFDocumentInvoice:           {"invoices", "invoice_number", "invoice_id", "status <> 'CANCELLED'"},
+	ANAFDocumentCreditNote:        {"credit_notes", "credit_note_number", "credit_note_id", "TRUE"},
+	ANAFDocumentCommissionInvoice: {"commission_invoices", "invoice_number", "commission_invoice_id", "customer_cui LIKE 'RO%'"},
+	ANAFDocumentSelfBilledInvoice: {"self_billed_invoices", "invoice_number", "self_billed_invoice_id", "TRUE"},
+}
+
+// anafSubmissionDue selects documents due for (re)submission: new ones once the submission
+// delay ($1 seconds) has passed since they were created, and failed ones with retries left
+// ($3) once the retry delay ($2 seconds), doubled after every failed attempt, has passed
+const anafSubmissionDue = `
+	((anaf_status = 'pending' AND created_at <= NOW() - make_interval(secs => $1::float8))
+	 OR (anaf_status = 'failed' AND anaf_retry_count < $3
+	     AND COALESCE(anaf_last_retry_at, created_at) <= NOW() - make_interval(secs => $2::float8 * POWER(2, GREATEST(anaf_retry_count - 1, 0)))))`
+
+// ANAFDocument is the e-Factura state of a document of any type, as the ANAF worker tracks it
+type ANAFDocument struct {
+	Type            ANAFDocumentType
+	ID              string
+	Number          string
+	IssueDate       time.Time
+	ANAFUploadIndex sql.NullString
+	ANAFStatus      ANAFStatus
+	ANAFProcessedAt sql.NullTime
+	ANAFDownloadID  sql.NullString
+	ANAFErrors      []ANAFError
+	ANAFRetryCount  int
+}
+
+// ANAFDocumentRepository reads and updates the ANAF state of invoices, credit notes,
+// commission invoices and self-billed invoices alike
+type ANAFDocumentRepository struct {
+	db *sql.DB
+}
+
+// NewANAFDocumentRepository creates a new ANAF document repository
+func NewANAFDocumentRepository(db *sql.DB) *ANAFDocumentRepository {
+	return &ANAFDocumentRepository{db: db}
+}
+
+// GetProcessing returns the documents of a type ANAF is still processing
+func (r *ANAFDocumentRepository) GetProcessing(docType ANAFDocumentType) ([]*ANAFDocument, error) {
+	return r.query(docType, `anaf_status = 'processing' ORDER BY anaf_submitted_at ASC`)
+}
+
+// GetAwaitingConfirmation returns accepted documents of a type whose signed response has not
+// been archived yet
+func (r *ANAFDocumentRepository) GetAwaitingConfirmation(docType ANAFDocumentType, limit int) ([]*ANAFDocument, error) {
+	t := anafDocumentTables[docType]
+	return r.query(docType, fmt.Sprintf(`
+		anaf_status = 'accepted' AND anaf_download_id IS NOT NULL
+		AND id NOT IN (SELECT %s FROM anaf_confirmations WHERE %s IS NOT NULL)
+		ORDER BY anaf_processed_at ASC
+		LIMIT $1`, t.ref, t.ref), limit)
+}
+
+// GetNotSubmittedIssuedBefore returns documents of every type issued on or before a date that
+// ANAF has not received yet (pending, failed or rejected)
+func (r *ANAFDocumentRepository) GetNotSubmittedIssuedBefore(date time.Time) ([]*ANAFDocument, error) {
+	return r.queryAll(`anaf_status IN ('pending', 'failed', 'rejected') AND issue_date <= $1`, date)
+}
+
+// GetPermanentlyFailed returns documents of every type ANAF rejected or that ran out of
+// submission retries
+func (r *ANAFDocumentRepository) GetPermanentlyFailed(maxRetries int) ([]*ANAFDocument, error) {
+	return r.queryAll(`anaf_status = 'rejected' OR (anaf_status = 'failed' AND anaf_retry_count >= $1)`, maxRetries)
+}
+
+// UpdateStatus stores the outcome of an ANAF status check
+func (r *ANAFDocumentRepository) UpdateStatus(doc *ANAFDocument) error {
+	anafErrorsJSON, err := encodeANAFErrors(doc.ANAFErrors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(fmt.Sprintf(`
+		UPDATE %s
+		SET anaf_status = $2, anaf_processed_at = $3, anaf_download_id = $4, anaf_errors = $5, updated_at = NOW()
+		WHERE id = $1
+	`, anafDocumentTables[doc.Type].table), doc.ID, doc.ANAFStatus, doc.ANAFProcessedAt, doc.ANAFDownloadID, anafErrorsJSON)
+	if err != nil {
+		return fmt.Errorf("failed to update ANAF status of %s %s: %w", doc.Type, doc.Number, err)
+	}
+	return nil
+}
+
+// anafDocumentSelect selects the ANAF columns of a document type's table, filtered to the
+// documents that are filed with ANAF
+func anafDocumentSelect(docType ANAFDocumentType) string {
+	t := anafDocumentTables[docType]
+	return fmt.Sprintf(`
+		SELECT '%s', id, %s, issue_date, anaf_upload_index, anaf_status, anaf_processed_at,
+		       anaf_download_id, anaf_errors, anaf_retry_count
+		FROM %s
+		WHERE %s`, docType, t.number, t.table, t.filter)
+}
+
+func (r *ANAFDocumentRepository) query(docType ANAFDocumentType, condition string, args ...interface{}) ([]*ANAFDocument, error) {
+	return r.scan(anafDocumentSelect(docType)+` AND `+condition, args...)
+}
+
+// queryAll runs a condition against every document type, oldest issued first
+func (r *ANAFDocumentRepository) queryAll(condition string, args ...interface{}) ([]*ANAFDocument, error) {
+	parts := make([]string, len(ANAFDocumentTypes))
+	for i, docType := range ANAFDocumentTypes {
+		parts[i] = anafDocumentSelect(docType) + ` AND (` + condition + `)`
+	}
+	return r.scan(strings.Join(parts, "\nUNION ALL\n")+"\nORDER BY 4 ASC", args...)
+}
+
+func (r *ANAFDocumentRepository) scan(query string, args ...interface{}) ([]*ANAFDocument, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ANAF documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []*ANAFDocument
+	for rows.Next() {
+		doc := &ANAFDocument{}
+		var anafErrorsJSON sql.NullString
+		if err := rows.Scan(&doc.Type, &doc.ID, &doc.Number, &doc.IssueDate, &doc.ANAFUploadIndex, &doc.ANAFStatus,
+			&doc.ANAFProcessedAt, &doc.ANAFDownloadID, &anafErrorsJSON, &doc.ANAFRetryCount); err != nil {
+			return nil, fmt.Errorf("failed to scan ANAF document: %w", err)
+		}
+		doc.ANAFErrors = decodeANAFErrors(anafErrorsJSON)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// anafDocumentRef returns the document an alert or confirmation row references, given its
+// invoice_id, credit_note_id, commission_invoice_id and self_billed_invoice_id columns
+func anafDocumentRef(invoiceID, creditNoteID, commissionInvoiceID, selfBilledInvoiceID sql.NullString) (ANAFDocumentType, string) {
+	switch {
+	case creditNoteID.Valid:
+		return ANAFDocumentCreditNote, creditNoteID.String
+	case commissionInvoiceID.Valid:
+		return ANAFDocumentCommissionInvoice, commissionInvoiceID.String
+	case selfBilledInvoiceID.Valid:
+		return ANAFDocumentSelfBilledInvoice, selfBilledInvoiceID.String
+	default:
+		return ANAFDocumentInvoice, invoiceID.String
+	}
+}
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Bank statement line match statuses
+const (
+	BankLineStatusUnmatched = "UNMATCHED"
+	BankLineStatusMatched   = "MATCHED"
+	BankLineStatusIgnored   = "IGNORED"
+)
+
+// BankStatementImport is one uploaded bank statement file
+type BankStatementImport struct {
+	ID             string
+	FileName       string
+	Format         string
+	ImportedBy     sql.NullString
+	LineCount      int
+	MatchedCount   int
+	DuplicateCount int
+	CreatedAt      time.Time
+}
+
+// BankStatementLine is a transaction from an imported statement and its reconciliation state
+type BankStatementLine struct {
+	ID                     string
+	ImportID               string
+	BookingDate            time.Time
+	Direction              string
+	Amount                 float64
+	Currency               string
+	CounterpartyName       sql.NullString
+	CounterpartyIBAN       sql.NullString
+	Reference              sql.NullString
+	BankReference          sql.NullString
+	Fingerprint            string
+	MatchStatus            string
+	Reversal               bool // Reverses an earlier transfer; linked to its payout but left for an admin
+	MatchedPayoutID        sql.NullString
+	MatchedInvoiceID       sql.NullString
+	MatchedProformaID      sql.NullString
+	MatchedCompanyPayoutID sql.NullString
+	ResolvedBy             sql.NullString
+	ResolvedAt             sql.NullTime
+	Notes                  sql.NullString
+	CreatedAt              time.Time
+}
+
+// BankLineMatch is what a statement line settles; exactly one ID is set
+type BankLineMatch struct {
+	PayoutID        string
+	CompanyPayoutID string
+	InvoiceID       string
+	ProformaID      string
+}
+
+// BankStatementRepository handles bank statement database operations
+type BankStatementRepository struct {
+	db *sql.DB
+}
+
+// NewBankStatementRepository creates a new bank statement repository
+func NewBankStatementRepository(db *sql.DB) *BankStatementRepository {
+	return &BankStatementRepository{db: db}
+}
+
+const bankStatementLineColumns = `
+	id, import_id, booking_date, direction, amount, currency,
+	counterparty_name, counterparty_iban, reference, bank_reference, fingerprint, reversal,
+	match_status, matched_payout_id, matched_invoice_id, matched_proforma_id, matched_company_payout_id,
+	resolved_by, resolved_at, notes, created_at`
+
+// CreateImport stores a statement import and its lines in one transaction.
+// Lines already imported from an overlapping statement are counted as duplicates and skipped;
+// the newly stored lines are returned.
+func (r *BankStatementRepository) CreateImport(imp *BankStatementImport, lines []*BankStatementLine) ([]*BankStatementLine, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO bank_statement_imports (file_name, format, imported_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, imp.FileName, imp.Format, imp.ImportedBy).Scan(&imp.ID, &imp.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create statement import: %w", err)
+	}
+
+	var created []*BankStatementLine
+	for _, line := range lines {
+		line.ImportID = imp.ID
+		inserted, err := insertBankStatementLine(tx, line)
+		if err != nil {
+			return nil, err
+		}
+		if !inserted {
+			imp.DuplicateCount++
+			continue
+		}
+		imp.LineCount++
+		created = append(created, line)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE bank_statement_imports SET line_count = $2, duplicate_count = $3 WHERE id = $1
+	`, imp.ID, imp.LineCount, imp.DuplicateCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update import counts: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit statement import: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateImportCounts stores the line statistics of an import
+func (r *BankStatementRepository) UpdateImportCounts(imp *BankStatementImport) error {
+	_, err := r.db.Exec(`
+		UPDATE bank_statement_imports
+		SET line_count = $2, matched_count = $3, duplicate_count = $4
+		WHERE id = $1
+	`, imp.ID, imp.LineCount, imp.MatchedCount, imp.DuplicateCount)
+	if err != nil {
+		return fmt.Errorf("failed to update import counts: %w", err)
+	}
+	return nil
+}
+
+// insertBankStatementLine inserts a statement line.
+// Returns false if a line with the same fingerprint was already imported.
+func insertBankStatementLine(q rowQuerier, line *BankStatementLine) (bool, error) {
+	err := q.QueryRow(`
+		INSERT INTO bank_statement_lines (
+			import_id, booking_date, direction, amount, currency,
+			counterparty_name, counterparty_iban, reference, bank_reference, fingerprint, reversal, match_status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (fingerprint) DO NOTHING
+		RETURNING id, created_at
+	`, line.ImportID, line.BookingDate, line.Direction, line.Amount, line.Currency,
+		line.CounterpartyName, line.CounterpartyIBAN, line.Reference, line.BankReference,
+		line.Fingerprint, line.Reversal, line.MatchStatus).Scan(&line.ID, &line.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create statement line: %w", err)
+	}
+	return true, nil
+}
+
+// GetLineByID finds a statement line by ID
+func (r *BankStatementRepository) GetLineByID(id string) (*BankStatementLine, error) {
+	line, err := scanBankStatementLine(r.db.QueryRow(`
+		SELECT `+bankStatementLineColumns+`
+		FROM bank_statement_lines
+		WHERE id = $1
+	`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// GetUnmatchedLines returns lines waiting for manual reconciliation, oldest first
+func (r *BankStatementRepository) GetUnmatchedLines(limit, offset int) ([]*BankStatementLine, error) {
+	rows, err := r.db.Query(`
+		SELECT `+bankStatementLineColumns+`
+		FROM bank_statement_lines
+		WHERE match_status = $1
+		ORDER BY booking_date ASC, created_at ASC
+		LIMIT $2 OFFSET $3
+	`, BankLineStatusUnmatched, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []*BankStatementLine{}
+	for rows.Next() {
+		line, err := scanBankStatementLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+// MarkLineMatched links a line to the payout, company payout, invoice or proforma it settles.
+// resolvedBy is empty for automatic matches.
+func (r *BankStatementRepository) MarkLineMatched(lineID string, match BankLineMatch, resolvedBy string) error {
+	_, err := r.db.Exec(`
+		UPDATE bank_statement_lines
+		SET match_status = $2, matched_payout_id = $3, matched_invoice_id = $4, matched_proforma_id = $5,
+		    matched_company_payout_id = $6, resolved_by = $7, resolved_at = NOW()
+		WHERE id = $1
+	`, lineID, BankLineStatusMatched,
+		sql.NullString{String: match.PayoutID, Valid: match.PayoutID != ""},
+		sql.NullString{String: match.InvoiceID, Valid: match.InvoiceID != ""},
+		sql.NullString{String: match.ProformaID, Valid: match.ProformaID != ""},
+		sql.NullString{String: match.CompanyPayoutID, Valid: match.CompanyPayoutID != ""},
+		sql.NullString{String: resolvedBy, Valid: resolvedBy != ""})
+	if err != nil {
+		return fmt.Errorf("failed to mark statement line as matched: %w", err)
+	}
+	return nil
+}
+
+// LinkReversal links a reversal line to the payout it reverses. The line stays unmatched,
+// with notes, until an admin has failed and resent the payout.
+func (r *BankStatementRepository) LinkReversal(lineID string, match BankLineMatch, notes string) error {
+	_, err := r.db.Exec(`
+		UPDATE bank_statement_lines
+		SET matched_payout_id = $2, matched_company_payout_id = $3, notes = $4
+		WHERE id = $1
+	`, lineID,
+		sql.NullString{String: match.PayoutID, Valid: match.PayoutID != ""},
+		sql.NullString{String: match.CompanyPayoutID, Valid: match.CompanyPayoutID != ""},
+		notes)
+	if err != nil {
+		return fmt.Errorf("failed to link reversal line: %w", err)
+	}
+	return nil
+}
+
+// MarkLineIgnored removes a line from the reconciliation queue (bank fees, internal transfers, ...)
+func (r *BankStatementRepository) MarkLineIgnored(lineID, resolvedBy, notes string) error {
+	_, err := r.db.Exec(`
+		UPDATE bank_statement_lines
+		SET match_status = $2, resolved_by = $3, resolved_at = NOW(), notes = $4
+		WHERE id = $1
+	`, lineID, BankLineStatusIgnored, resolvedBy, sql.NullString{String: notes, Valid: notes != ""})
+	if err != nil {
+		return fmt.Errorf("failed to mark statement line as ignored: %w", err)
+	}
+	return nil
+}
+
+// SetLineNotes records why a line could not be matched automatically
+func (r *BankStatementRepository) SetLineNotes(lineID, notes string) error {
+	_, err := r.db.Exec(`UPDATE bank_statement_lines SET notes = $2 WHERE id = $1`, lineID, notes)
+	return err
+}
+
+type bankStatementRowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBankStatementLine(row bankStatementRowScanner) (*BankStatementLine, error) {
+	line := &BankStatementLine{}
+	err := row.Scan(
+		&line.ID, &line.ImportID, &line.BookingDate, &line.Direction, &line.Amount, &line.Currency,
+		&line.CounterpartyName, &line.CounterpartyIBAN, &line.Reference, &line.BankReference, &line.Fingerprint, &line.Reversal,
+		&line.MatchStatus, &line.MatchedPayoutID, &line.MatchedInvoiceID, &line.MatchedProformaID, &line.MatchedCompanyPayoutID,
+		&line.ResolvedBy, &line.ResolvedAt, &line.Notes, &line.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
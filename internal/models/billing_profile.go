@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// BillingProfile holds the details of a company a client books for, invoiced instead of the client
+type BillingProfile struct {
+	ID                 string
+	UserID             string
+	LegalName          string
+	CUI                string // Digits only, without the RO prefix
+	RegistrationNumber sql.NullString
+	VATPayer           bool
+	StreetAddress      string
+	City               string
+	County             string
+	PostalCode         sql.NullString
+	Country            string
+	Email              sql.NullString
+	IsDefault          bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// BillingProfileRepository handles billing profile database operations
+type BillingProfileRepository struct {
+	db *sql.DB
+}
+
+// NewBillingProfileRepository creates a new billing profile repository
+func NewBillingProfileRepository(db *sql.DB) *BillingProfileRepository {
+	return &BillingProfileRepository{db: db}
+}
+
+const billingProfileSelect = `
+	SELECT id, user_id, legal_name, cui, registration_number, vat_payer,
+	       street_address, city, county, postal_code, country, email, is_default,
+	       created_at, updated_at
+	FROM billing_profiles`
+
+// Create creates a new billing profile
+func (r *BillingProfileRepository) Create(profile *BillingProfile) error {
+	return r.db.QueryRow(`
+		INSERT INTO billing_profiles (
+			user_id, legal_name, cui, registration_number, vat_payer,
+			street_address, city, county, postal_code, country, email, is_default
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`, profile.UserID, profile.LegalName, profile.CUI, profile.RegistrationNumber, profile.VATPayer,
+		profile.StreetAddress, profile.City, profile.County, profile.PostalCode, profile.Country,
+		profile.Email, profile.IsDefault).
+		Scan(&profile.ID, &profile.CreatedAt, &profile.UpdatedAt)
+}
+
+// GetByID finds a billing profile by ID
+func (r *BillingProfileRepository) GetByID(id string) (*BillingProfile, error) {
+	profiles, err := r.query(billingProfileSelect+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return profiles[0], nil
+}
+
+// GetByUserID returns a user's billing profiles, the default first
+func (r *BillingProfileRepository) GetByUserID(userID string) ([]*BillingProfile, error) {
+	return r.query(billingProfileSelect+`
+		WHERE user_id = $1
+		ORDER BY is_default DESC, legal_name
+	`, userID)
+}
+
+// Update updates a billing profile
+func (r *BillingProfileRepository) Update(profile *BillingProfile) error {
+	_, err := r.db.Exec(`
+		UPDATE billing_profiles
+		SET legal_name = $2, cui = $3, registration_number = $4, vat_payer = $5,
+		    street_address = $6, city = $7, county = $8, postal_code = $9, country = $10,
+		    email = $11, is_default = $12, updated_at = NOW()
+		WHERE id = $1
+	`, profile.ID, profile.LegalName, profile.CUI, profile.RegistrationNumber, profile.VATPayer,
+		profile.StreetAddress, profile.City, profile.County, profile.PostalCode, profile.Country,
+		profile.Email, profile.IsDefault)
+	if err != nil {
+		return fmt.Errorf("failed to update billing profile: %w", err)
+	}
+	return nil
+}
+
+// ClearDefault unsets the default flag on a user's other billing profiles
+func (r *BillingProfileRepository) ClearDefault(userID, exceptID string) error {
+	_, err := r.db.Exec(`
+		UPDATE billing_profiles SET is_default = FALSE, updated_at = NOW()
+		WHERE user_id = $1 AND id <> $2 AND is_default
+	`, userID, exceptID)
+	if err != nil {
+		return fmt.Errorf("failed to clear default billing profile: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a billing profile. Bookings made with it fall back to no profile; invoices
+// already issued keep the details they were billed with.
+func (r *BillingProfileRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM billing_profiles WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete billing profile: %w", err)
+	}
+	return nil
+}
+
+func (r *BillingProfileRepository) query(query string, args ...interface{}) ([]*BillingProfile, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query billing profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*BillingProfile
+	for rows.Next() {
+		profile := &BillingProfile{}
+		if err := rows.Scan(&profile.ID, &profile.UserID, &profile.LegalName, &profile.CUI,
+			&profile.RegistrationNumber, &profile.VATPayer, &profile.StreetAddress, &profile.City,
+			&profile.County, &profile.PostalCode, &profile.Country, &profile.Email, &profile.IsDefault,
+			&profile.CreatedAt, &profile.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan billing profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
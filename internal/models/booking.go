@@ -23,6 +23,28 @@ const (
 	BookingStatusNoShowCleaner  BookingStatus = "NO_SHOW_CLEANER"
 )
 
+// RefundStatus records the outcome of refund processing for a cancelled booking
+type RefundStatus string
+
+const (
+	RefundStatusNoPayment         RefundStatus = "NO_PAYMENT"
+	RefundStatusHoldReleased      RefundStatus = "HOLD_RELEASED"
+	RefundStatusRefunded          RefundStatus = "REFUNDED"
+	RefundStatusPartiallyRefunded RefundStatus = "PARTIALLY_REFUNDED"
+	RefundStatusNotEligible       RefundStatus = "NOT_ELIGIBLE"
+	RefundStatusFailed            RefundStatus = "FAILED"
+)
+
+// PlatformFeeRule identifies which fee policy rule set a booking's platform fee
+type PlatformFeeRule string
+
+const (
+	PlatformFeeRuleDefault         PlatformFeeRule = "DEFAULT"
+	PlatformFeeRuleRepeatClient    PlatformFeeRule = "REPEAT_CLIENT"
+	PlatformFeeRuleCleanerTier     PlatformFeeRule = "CLEANER_TIER"
+	PlatformFeeRuleCompanyContract PlatformFeeRule = "COMPANY_CONTRACT"
+)
+
 // ServiceType represents type of cleaning service
 type ServiceType string
 
@@ -74,8 +96,14 @@ type Booking struct {
 	AddonsPrice     float64
 	TotalPrice      float64
 	PlatformFee     float64
+	PlatformFeeRate float64         // Percentage of TotalPrice kept by the platform
+	PlatformFeeRule PlatformFeeRule // Fee policy rule that set the rate
 	CleanerPayout   float64
 	DiscountApplied float64
+	CreditApplied   float64 // Wallet credit spent on the booking; the card covers the rest
+
+	// Company the client books for; invoices go to it instead of the client
+	BillingProfileID sql.NullString
 
 	// State
 	Status BookingStatus
@@ -90,6 +118,11 @@ type Booking struct {
 	CancellationReason sql.NullString
 	CancelledBy        sql.NullString
 
+	// Refund outcome (set when a cancelled booking's payment is processed)
+	RefundStatus      sql.NullString
+	RefundAmount      float64
+	RefundProcessedAt sql.NullTime
+
 	// Ratings
 	ClientRating  sql.NullInt32
 	ClientReview  sql.NullString
@@ -121,10 +154,10 @@ func (r *BookingRepository) Create(booking *Booking) error {
 			number_of_windows, carpet_area_sqm,
 			includes_fridge_cleaning, includes_oven_cleaning, includes_balcony_cleaning,
 			special_instructions, access_instructions, supplies,
-			base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
-			status, reservation_code
+			base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
+			status, reservation_code, billing_profile_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31)
 		RETURNING id, created_at, updated_at
 	`, booking.ClientID, booking.AddressID, booking.ServiceType, booking.AreaSqm, booking.EstimatedHours, booking.Frequency,
 		booking.ScheduledDate, booking.ScheduledTime, booking.TimePreferences,
@@ -132,8 +165,8 @@ func (r *BookingRepository) Create(booking *Booking) error {
 		booking.NumberOfWindows, booking.CarpetAreaSqm,
 		booking.IncludesFridgeCleaning, booking.IncludesOvenCleaning, booking.IncludesBalconyCleaning,
 		booking.SpecialInstructions, booking.AccessInstructions, booking.Supplies,
-		booking.BasePrice, booking.AddonsPrice, booking.TotalPrice, booking.PlatformFee, booking.CleanerPayout, booking.DiscountApplied,
-		booking.Status, booking.ReservationCode).
+		booking.BasePrice, booking.AddonsPrice, booking.TotalPrice, booking.PlatformFee, booking.PlatformFeeRate, booking.PlatformFeeRule, booking.CleanerPayout, booking.DiscountApplied,
+		booking.Status, booking.ReservationCode, booking.BillingProfileID).
 		Scan(&booking.ID, &booking.CreatedAt, &booking.UpdatedAt)
 }
 
@@ -148,10 +181,12 @@ func (r *BookingRepository) GetByID(id string) (*Booking, error) {
 		       number_of_windows, carpet_area_sqm,
 		       includes_fridge_cleaning, includes_oven_cleaning, includes_balcony_cleaning,
 		       special_instructions, access_instructions, supplies,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
+		       credit_applied, billing_profile_id,
 		       status, reservation_code,
 		       confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
+		       refund_status, refund_amount, refund_processed_at,
 		       client_rating, client_review, cleaner_rating, cleaner_review,
 		       created_at, updated_at
 		FROM bookings
@@ -164,10 +199,12 @@ func (r *BookingRepository) GetByID(id string) (*Booking, error) {
 		&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 		&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 		&booking.SpecialInstructions, &booking.AccessInstructions, &booking.Supplies,
-		&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+		&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
+		&booking.CreditApplied, &booking.BillingProfileID,
 		&booking.Status, &booking.ReservationCode,
 		&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 		&booking.CancellationReason, &booking.CancelledBy,
+		&booking.RefundStatus, &booking.RefundAmount, &booking.RefundProcessedAt,
 		&booking.ClientRating, &booking.ClientReview, &booking.CleanerRating, &booking.CleanerReview,
 		&booking.CreatedAt, &booking.UpdatedAt,
 	)
@@ -193,7 +230,7 @@ func (r *BookingRepository) GetByClientID(clientID string) ([]*Booking, error) {
 		       number_of_windows, carpet_area_sqm,
 		       includes_fridge_cleaning, includes_oven_cleaning, includes_balcony_cleaning,
 		       special_instructions, access_instructions,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 		       status,
 		       confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
@@ -220,7 +257,7 @@ func (r *BookingRepository) GetByClientID(clientID string) ([]*Booking, error) {
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -249,7 +286,8 @@ func (r *BookingRepository) Update(booking *Booking) error {
 		    status = $22,
 		    confirmed_at = $23, started_at = $24, completed_at = $25, cancelled_at = $26,
 		    cancellation_reason = $27, cancelled_by = $28,
-		    client_rating = $29, client_review = $30, cleaner_rating = $31, cleaner_review = $32
+		    client_rating = $29, client_review = $30, cleaner_rating = $31, cleaner_review = $32,
+		    platform_fee_rate = $33, platform_fee_rule = $34
 		WHERE id = $1
 	`, booking.ID, booking.CleanerID, booking.ServiceType, booking.AreaSqm, booking.EstimatedHours,
 		booking.ScheduledDate, booking.ScheduledTime, booking.EstimatedEndTime,
@@ -260,7 +298,18 @@ func (r *BookingRepository) Update(booking *Booking) error {
 		booking.Status,
 		booking.ConfirmedAt, booking.StartedAt, booking.CompletedAt, booking.CancelledAt,
 		booking.CancellationReason, booking.CancelledBy,
-		booking.ClientRating, booking.ClientReview, booking.CleanerRating, booking.CleanerReview)
+		booking.ClientRating, booking.ClientReview, booking.CleanerRating, booking.CleanerReview,
+		booking.PlatformFeeRate, booking.PlatformFeeRule)
+	return err
+}
+
+// UpdateRefundOutcome records the refund processing result on a booking
+func (r *BookingRepository) UpdateRefundOutcome(bookingID string, status RefundStatus, amount float64) error {
+	_, err := r.db.Exec(`
+		UPDATE bookings
+		SET refund_status = $2, refund_amount = $3, refund_processed_at = NOW()
+		WHERE id = $1
+	`, bookingID, status, amount)
 	return err
 }
 
@@ -274,7 +323,7 @@ func (r *BookingRepository) GetPendingBookings() ([]*Booking, error) {
 		       includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
 		       number_of_windows, carpet_area_sqm,
 		       special_instructions, access_instructions,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 		       status,
 		       confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
@@ -301,7 +350,7 @@ func (r *BookingRepository) GetPendingBookings() ([]*Booking, error) {
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -327,7 +376,7 @@ func (r *BookingRepository) GetAvailableJobs(city string, limit, offset int) ([]
 		       b.includes_deep_cleaning, b.includes_windows, b.includes_carpet_cleaning,
 		       b.number_of_windows, b.carpet_area_sqm,
 		       b.special_instructions, b.access_instructions,
-		       b.base_price, b.addons_price, b.total_price, b.platform_fee, b.cleaner_payout, b.discount_applied,
+		       b.base_price, b.addons_price, b.total_price, b.platform_fee, b.platform_fee_rate, b.platform_fee_rule, b.cleaner_payout, b.discount_applied,
 		       b.status,
 		       b.confirmed_at, b.started_at, b.completed_at, b.cancelled_at,
 		       b.cancellation_reason, b.cancelled_by,
@@ -379,7 +428,7 @@ func (r *BookingRepository) GetAvailableJobs(city string, limit, offset int) ([]
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -406,7 +455,7 @@ func (r *BookingRepository) GetByClientIDFiltered(clientID string, filter string
 		       number_of_windows, carpet_area_sqm,
 		       includes_fridge_cleaning, includes_oven_cleaning, includes_balcony_cleaning,
 		       special_instructions, access_instructions,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 		       status,
 		       confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
@@ -446,7 +495,7 @@ func (r *BookingRepository) GetByClientIDFiltered(clientID string, filter string
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -471,7 +520,7 @@ func (r *BookingRepository) GetCompletedBookingsByPeriod(startDate, endDate time
 			   includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
 			   number_of_windows, carpet_area_sqm,
 			   special_instructions, access_instructions,
-			   base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+			   base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 			   status,
 			   confirmed_at, started_at, completed_at, cancelled_at,
 			   cancellation_reason, cancelled_by,
@@ -497,7 +546,70 @@ func (r *BookingRepository) GetCompletedBookingsByPeriod(startDate, endDate time
 			&booking.IncludesDeepCleaning, &booking.IncludesWindows, &booking.IncludesCarpetCleaning,
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.Status,
+			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
+			&booking.CancellationReason, &booking.CancelledBy,
+			&booking.ClientRating, &booking.ClientReview, &booking.CleanerRating, &booking.CleanerReview,
+			&booking.CreatedAt, &booking.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, rows.Err()
+}
+
+// GetPayableBookingsByPeriod returns completed bookings not yet on a payout: those completed within the
+// date range, plus earlier ones whose dispute was resolved within it (they were held back until then)
+func (r *BookingRepository) GetPayableBookingsByPeriod(startDate, endDate time.Time) ([]*Booking, error) {
+	query := `
+		SELECT id, client_id, address_id, cleaner_id,
+			   service_type, area_sqm, estimated_hours,
+			   scheduled_date, scheduled_time, estimated_end_time,
+			   includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
+			   number_of_windows, carpet_area_sqm,
+			   special_instructions, access_instructions,
+			   base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
+			   status,
+			   confirmed_at, started_at, completed_at, cancelled_at,
+			   cancellation_reason, cancelled_by,
+			   client_rating, client_review, cleaner_rating, cleaner_review,
+			   created_at, updated_at
+		FROM bookings b
+		WHERE status = $1
+		  AND (
+			(completed_at >= $2 AND completed_at <= $3)
+			OR (completed_at < $2 AND EXISTS (
+				SELECT 1 FROM disputes d
+				WHERE d.booking_id = b.id AND d.resolved_at >= $2 AND d.resolved_at <= $3
+			))
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM payout_line_items li
+			WHERE li.booking_id = b.id AND li.item_type IN ('BOOKING', 'CASH_BOOKING')
+		  )
+		ORDER BY completed_at ASC
+	`
+	rows, err := r.db.Query(query, BookingStatusCompleted, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []*Booking
+	for rows.Next() {
+		booking := &Booking{}
+		err := rows.Scan(
+			&booking.ID, &booking.ClientID, &booking.AddressID, &booking.CleanerID,
+			&booking.ServiceType, &booking.AreaSqm, &booking.EstimatedHours,
+			&booking.ScheduledDate, &booking.ScheduledTime, &booking.EstimatedEndTime,
+			&booking.IncludesDeepCleaning, &booking.IncludesWindows, &booking.IncludesCarpetCleaning,
+			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
+			&booking.SpecialInstructions, &booking.AccessInstructions,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -523,7 +635,7 @@ func (r *BookingRepository) GetCompletedBookingsByClientBeforeDate(clientID stri
 			   includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
 			   number_of_windows, carpet_area_sqm,
 			   special_instructions, access_instructions,
-			   base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+			   base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 			   status,
 			   confirmed_at, started_at, completed_at, cancelled_at,
 			   cancellation_reason, cancelled_by,
@@ -549,7 +661,7 @@ func (r *BookingRepository) GetCompletedBookingsByClientBeforeDate(clientID stri
 			&booking.IncludesDeepCleaning, &booking.IncludesWindows, &booking.IncludesCarpetCleaning,
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -580,7 +692,7 @@ func (r *BookingRepository) GetByCleanerIDs(cleanerIDs []string, filter *string)
 		       number_of_windows, carpet_area_sqm,
 		       includes_fridge_cleaning, includes_oven_cleaning, includes_balcony_cleaning,
 		       special_instructions, access_instructions,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 		       status, confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
 		       client_rating, client_review, cleaner_rating, cleaner_review,
@@ -622,7 +734,7 @@ func (r *BookingRepository) GetByCleanerIDs(cleanerIDs []string, filter *string)
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -650,7 +762,7 @@ func (r *BookingRepository) GetExpiredPendingBookings(expirationHours int) ([]*B
 		       service_type, area_sqm, estimated_hours, scheduled_date, scheduled_time, estimated_end_time,
 		       includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
 		       number_of_windows, carpet_area_sqm, special_instructions, access_instructions,
-		       base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+		       base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 		       status, confirmed_at, started_at, completed_at, cancelled_at,
 		       cancellation_reason, cancelled_by,
 		       client_rating, client_review, cleaner_rating, cleaner_review,
@@ -679,7 +791,7 @@ func (r *BookingRepository) GetExpiredPendingBookings(expirationHours int) ([]*B
 			&booking.NumberOfWindows, &booking.CarpetAreaSqm,
 			&booking.IncludesFridgeCleaning, &booking.IncludesOvenCleaning, &booking.IncludesBalconyCleaning,
 			&booking.SpecialInstructions, &booking.AccessInstructions,
-			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.CleanerPayout, &booking.DiscountApplied,
+			&booking.BasePrice, &booking.AddonsPrice, &booking.TotalPrice, &booking.PlatformFee, &booking.PlatformFeeRate, &booking.PlatformFeeRule, &booking.CleanerPayout, &booking.DiscountApplied,
 			&booking.Status,
 			&booking.ConfirmedAt, &booking.StartedAt, &booking.CompletedAt, &booking.CancelledAt,
 			&booking.CancellationReason, &booking.CancelledBy,
@@ -715,7 +827,7 @@ func (r *BookingRepository) CountActiveBookingsByCleanerID(cleanerID string) (in
 func (r *BookingRepository) GetByCleanerID(cleanerID string, limit, offset int, status *BookingStatus, search *string) ([]*Booking, error) {
 	query := `
 		SELECT id, client_id, cleaner_id, address_id, scheduled_date, scheduled_time,
-		       estimated_hours, total_price, platform_fee,
+		       estimated_hours, total_price, platform_fee, platform_fee_rate, platform_fee_rule,
 		       cleaner_payout, status, service_type, area_sqm, special_instructions,
 		       started_at, completed_at,
 		       client_rating, client_review, cleaner_rating, cleaner_review,
@@ -773,6 +885,8 @@ func (r *BookingRepository) GetByCleanerID(cleanerID string, limit, offset int,
 			&booking.EstimatedHours,
 			&booking.TotalPrice,
 			&booking.PlatformFee,
+			&booking.PlatformFeeRate,
+			&booking.PlatformFeeRule,
 			&booking.CleanerPayout,
 			&booking.Status,
 			&booking.ServiceType,
@@ -796,3 +910,12 @@ func (r *BookingRepository) GetByCleanerID(cleanerID string, limit, offset int,
 
 	return bookings, rows.Err()
 }
+
+// AmountDue returns what is left to charge to the card after wallet credit
+func (b *Booking) AmountDue() float64 {
+	due := b.TotalPrice - b.CreditApplied
+	if due < 0 {
+		return 0
+	}
+	return due
+}
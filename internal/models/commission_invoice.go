@@ -0,0 +1,209 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Commission invoice recipients
+const (
+	CommissionRecipientCleaner = "CLEANER" // Independent cleaner
+	CommissionRecipientCompany = "COMPANY" // Company billed for its cleaners' payouts
+)
+
+// CommissionInvoice is the platform's monthly invoice for the fees retained from a recipient's payouts
+type CommissionInvoice struct {
+	ID              string
+	InvoiceNumber   string
+	RecipientType   string
+	CleanerID       sql.NullString // cleaners.id, for CLEANER invoices
+	CompanyID       sql.NullString // For COMPANY invoices
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	IssueDate       time.Time
+	DueDate         time.Time
+	CustomerName    string
+	CustomerCUI     sql.NullString
+	CustomerAddress sql.NullString
+	CustomerEmail   sql.NullString
+	Subtotal        float64
+	VATRate         float64
+	TaxAmount       float64
+	TotalAmount     float64
+	Currency        string
+	PdfURL          sql.NullString
+	XmlURL          sql.NullString
+	ANAFUploadIndex sql.NullString
+	ANAFStatus      ANAFStatus
+	ANAFSubmittedAt sql.NullTime
+	ANAFProcessedAt sql.NullTime
+	ANAFDownloadID  sql.NullString
+	ANAFErrors      []ANAFError
+	ANAFRetryCount  int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CommissionInvoiceRepository handles commission invoice database operations
+type CommissionInvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewCommissionInvoiceRepository creates a new commission invoice repository
+func NewCommissionInvoiceRepository(db *sql.DB) *CommissionInvoiceRepository {
+	return &CommissionInvoiceRepository{db: db}
+}
+
+// CreateForPayouts numbers and stores an invoice from the commission invoice series and links the invoiced payouts
+// to it in one transaction. It fails if any payout was invoiced in the meantime.
+func (r *CommissionInvoiceRepository) CreateForPayouts(invoice *CommissionInvoice, payoutIDs []string) error {
+	if invoice.ANAFStatus == "" {
+		invoice.ANAFStatus = ANAFStatusPending
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice.InvoiceNumber, err = allocateDocumentNumber(tx, DocumentTypeCommissionInvoice, invoice.IssueDate)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO commission_invoices (
+			invoice_number, recipient_type, cleaner_id, company_id, period_start, period_end,
+			issue_date, due_date, customer_name, customer_cui, customer_address, customer_email,
+			subtotal, vat_rate, tax_amount, total_amount, currency, anaf_status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at
+	`, invoice.InvoiceNumber, invoice.RecipientType, invoice.CleanerID, invoice.CompanyID, invoice.PeriodStart, invoice.PeriodEnd,
+		invoice.IssueDate, invoice.DueDate, invoice.CustomerName, invoice.CustomerCUI, invoice.CustomerAddress, invoice.CustomerEmail,
+		invoice.Subtotal, invoice.VATRate, invoice.TaxAmount, invoice.TotalAmount, invoice.Currency, invoice.ANAFStatus,
+	).Scan(&invoice.ID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create commission invoice: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE payouts SET commission_invoice_id = $2, updated_at = NOW()
+		WHERE id = ANY($1) AND commission_invoice_id IS NULL
+	`, pq.Array(payoutIDs), invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to link payouts to commission invoice: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(payoutIDs) {
+		return fmt.Errorf("%d of %d payouts were already invoiced", len(payoutIDs)-int(affected), len(payoutIDs))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit commission invoice: %w", err)
+	}
+	return nil
+}
+
+// UpdateFiles stores the paths of the generated PDF and XML
+func (r *CommissionInvoiceRepository) UpdateFiles(invoice *CommissionInvoice) error {
+	_, err := r.db.Exec(`
+		UPDATE commission_invoices SET pdf_url = $2, xml_url = $3, updated_at = NOW() WHERE id = $1
+	`, invoice.ID, invoice.PdfURL, invoice.XmlURL)
+	return err
+}
+
+// UpdateANAFStatus stores the outcome of an ANAF submission or status check. A failed upload
+// also records when it was attempted, for the retry backoff.
+func (r *CommissionInvoiceRepository) UpdateANAFStatus(invoice *CommissionInvoice) error {
+	anafErrorsJSON, err := encodeANAFErrors(invoice.ANAFErrors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE commission_invoices
+		SET anaf_upload_index = $2, anaf_status = $3, anaf_submitted_at = $4, anaf_processed_at = $5,
+		    anaf_download_id = $6, anaf_errors = $7, anaf_retry_count = $8,
+		    anaf_last_retry_at = CASE WHEN $3 = 'failed' THEN NOW() ELSE anaf_last_retry_at END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, invoice.ID, invoice.ANAFUploadIndex, invoice.ANAFStatus, invoice.ANAFSubmittedAt, invoice.ANAFProcessedAt,
+		invoice.ANAFDownloadID, anafErrorsJSON, invoice.ANAFRetryCount)
+	return err
+}
+
+const commissionInvoiceSelect = `
+	SELECT id, invoice_number, recipient_type, cleaner_id, company_id, period_start, period_end,
+	       issue_date, due_date, customer_name, customer_cui, customer_address, customer_email,
+	       subtotal, vat_rate, tax_amount, total_amount, currency, pdf_url, xml_url,
+	       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
+	       anaf_download_id, anaf_errors, anaf_retry_count, created_at, updated_at
+	FROM commission_invoices
+`
+
+// GetByID returns a commission invoice
+func (r *CommissionInvoiceRepository) GetByID(id string) (*CommissionInvoice, error) {
+	invoices, err := r.query(commissionInvoiceSelect+` WHERE id = $1`, id)
+	if err != nil || len(invoices) == 0 {
+		return nil, err
+	}
+	return invoices[0], nil
+}
+
+// GetByRecipients returns the invoices of a cleaner and of the given companies, newest first
+func (r *CommissionInvoiceRepository) GetByRecipients(cleanerID string, companyIDs []string, limit, offset int) ([]*CommissionInvoice, error) {
+	return r.query(commissionInvoiceSelect+`
+		WHERE cleaner_id = $1 OR company_id = ANY($2)
+		ORDER BY period_start DESC, invoice_number DESC
+		LIMIT $3 OFFSET $4
+	`, cleanerID, pq.Array(companyIDs), limit, offset)
+}
+
+// GetByPeriod returns the invoices issued for a payout period
+func (r *CommissionInvoiceRepository) GetByPeriod(periodStart time.Time) ([]*CommissionInvoice, error) {
+	return r.query(commissionInvoiceSelect+` WHERE period_start = $1 ORDER BY invoice_number ASC`, periodStart)
+}
+
+// GetPendingANAFSubmission returns B2B invoices due for (re)submission to ANAF, once the
+// submission delay or the backoff after their last failed attempt has passed
+func (r *CommissionInvoiceRepository) GetPendingANAFSubmission(submissionDelay, retryDelay time.Duration, maxRetries, limit int) ([]*CommissionInvoice, error) {
+	return r.query(commissionInvoiceSelect+`
+		WHERE `+anafSubmissionDue+` AND customer_cui LIKE 'RO%'
+		ORDER BY created_at ASC
+		LIMIT $4
+	`, submissionDelay.Seconds(), retryDelay.Seconds(), maxRetries, limit)
+}
+
+func (r *CommissionInvoiceRepository) query(query string, args ...interface{}) ([]*CommissionInvoice, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*CommissionInvoice
+	for rows.Next() {
+		invoice := &CommissionInvoice{}
+		var anafErrorsJSON sql.NullString
+		if err := rows.Scan(
+			&invoice.ID, &invoice.InvoiceNumber, &invoice.RecipientType, &invoice.CleanerID, &invoice.CompanyID, &invoice.PeriodStart, &invoice.PeriodEnd,
+			&invoice.IssueDate, &invoice.DueDate, &invoice.CustomerName, &invoice.CustomerCUI, &invoice.CustomerAddress, &invoice.CustomerEmail,
+			&invoice.Subtotal, &invoice.VATRate, &invoice.TaxAmount, &invoice.TotalAmount, &invoice.Currency, &invoice.PdfURL, &invoice.XmlURL,
+			&invoice.ANAFUploadIndex, &invoice.ANAFStatus, &invoice.ANAFSubmittedAt, &invoice.ANAFProcessedAt,
+			&invoice.ANAFDownloadID, &anafErrorsJSON, &invoice.ANAFRetryCount, &invoice.CreatedAt, &invoice.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		invoice.ANAFErrors = decodeANAFErrors(anafErrorsJSON)
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
@@ -33,6 +33,7 @@ type Company struct {
 	ApprovedBy                  sql.NullString
 	ApprovedAt                  sql.NullTime
 	IsActive                    bool
+	PlatformFeePercentage       sql.NullFloat64 // Contracted platform fee for the company's cleaners
 	CreatedAt                   time.Time
 	UpdatedAt                   time.Time
 }
@@ -67,7 +68,7 @@ func (r *CompanyRepository) Create(company *Company) error {
 			legal_address, contact_email, contact_phone
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, approval_status, id_document_verified, registration_document_verified,
-				  is_active, created_at, updated_at
+				  is_active, platform_fee_percentage, created_at, updated_at
 	`
 
 	err := r.db.QueryRow(
@@ -86,6 +87,7 @@ func (r *CompanyRepository) Create(company *Company) error {
 		&company.IDDocumentVerified,
 		&company.RegistrationDocumentVerified,
 		&company.IsActive,
+		&company.PlatformFeePercentage,
 		&company.CreatedAt,
 		&company.UpdatedAt,
 	)
@@ -105,7 +107,7 @@ func (r *CompanyRepository) GetByID(id string) (*Company, error) {
 			   id_document_url, registration_document_url,
 			   id_document_verified, registration_document_verified,
 			   approval_status, rejected_reason, approved_by, approved_at,
-			   is_active, created_at, updated_at
+			   is_active, platform_fee_percentage, created_at, updated_at
 		FROM companies
 		WHERE id = $1
 	`
@@ -130,6 +132,7 @@ func (r *CompanyRepository) GetByID(id string) (*Company, error) {
 		&company.ApprovedBy,
 		&company.ApprovedAt,
 		&company.IsActive,
+		&company.PlatformFeePercentage,
 		&company.CreatedAt,
 		&company.UpdatedAt,
 	)
@@ -152,7 +155,7 @@ func (r *CompanyRepository) GetByCUI(cui string) (*Company, error) {
 			   id_document_url, registration_document_url,
 			   id_document_verified, registration_document_verified,
 			   approval_status, rejected_reason, approved_by, approved_at,
-			   is_active, created_at, updated_at
+			   is_active, platform_fee_percentage, created_at, updated_at
 		FROM companies
 		WHERE cui = $1
 	`
@@ -177,6 +180,7 @@ func (r *CompanyRepository) GetByCUI(cui string) (*Company, error) {
 		&company.ApprovedBy,
 		&company.ApprovedAt,
 		&company.IsActive,
+		&company.PlatformFeePercentage,
 		&company.CreatedAt,
 		&company.UpdatedAt,
 	)
@@ -284,6 +288,66 @@ func (r *CompanyRepository) UpdateApprovalStatus(companyID, status, adminID stri
 	return nil
 }
 
+// UpdatePlatformFee sets or clears the contracted platform fee percentage of a company
+func (r *CompanyRepository) UpdatePlatformFee(companyID string, percentage sql.NullFloat64) error {
+	result, err := r.db.Exec(`
+		UPDATE companies
+		SET platform_fee_percentage = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`, percentage, companyID)
+	if err != nil {
+		return fmt.Errorf("failed to update platform fee: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("company not found")
+	}
+
+	return nil
+}
+
+// GetContractFeeForCleaner returns the contracted platform fee of the active, approved company
+// the cleaner works for. If the cleaner works for several, the lowest rate applies.
+func (r *CompanyRepository) GetContractFeeForCleaner(cleanerID string) (sql.NullFloat64, error) {
+	var percentage sql.NullFloat64
+	err := r.db.QueryRow(`
+		SELECT MIN(c.platform_fee_percentage)
+		FROM companies c
+		JOIN company_cleaners cc ON cc.company_id = c.id
+		WHERE cc.cleaner_id = $1 AND cc.status = $2
+		  AND c.is_active = true AND c.approval_status = $3
+		  AND c.platform_fee_percentage IS NOT NULL
+	`, cleanerID, CompanyCleanerStatusActive, CompanyApprovalStatusApproved).Scan(&percentage)
+	return percentage, err
+}
+
+// GetActiveByCleanerID returns the active, approved company the cleaner works for, or nil.
+// If the cleaner works for several, the one they joined first is returned.
+func (r *CompanyRepository) GetActiveByCleanerID(cleanerID string) (*Company, error) {
+	var companyID string
+	err := r.db.QueryRow(`
+		SELECT c.id
+		FROM companies c
+		JOIN company_cleaners cc ON cc.company_id = c.id
+		WHERE cc.cleaner_id = $1 AND cc.status = $2
+		  AND c.is_active = true AND c.approval_status = $3
+		ORDER BY cc.joined_at ASC
+		LIMIT 1
+	`, cleanerID, CompanyCleanerStatusActive, CompanyApprovalStatusApproved).Scan(&companyID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company for cleaner: %w", err)
+	}
+	return r.GetByID(companyID)
+}
+
 // GetAll retrieves all companies
 func (r *CompanyRepository) GetAll() ([]*Company, error) {
 	query := `
@@ -292,7 +356,7 @@ func (r *CompanyRepository) GetAll() ([]*Company, error) {
 			   id_document_url, registration_document_url,
 			   id_document_verified, registration_document_verified,
 			   approval_status, rejected_reason, approved_by, approved_at,
-			   is_active, created_at, updated_at
+			   is_active, platform_fee_percentage, created_at, updated_at
 		FROM companies
 		ORDER BY created_at DESC
 	`
@@ -325,6 +389,7 @@ func (r *CompanyRepository) GetAll() ([]*Company, error) {
 			&company.ApprovedBy,
 			&company.ApprovedAt,
 			&company.IsActive,
+			&company.PlatformFeePercentage,
 			&company.CreatedAt,
 			&company.UpdatedAt,
 		)
@@ -345,7 +410,7 @@ func (r *CompanyRepository) GetByApprovalStatus(status string) ([]*Company, erro
 			   id_document_url, registration_document_url,
 			   id_document_verified, registration_document_verified,
 			   approval_status, rejected_reason, approved_by, approved_at,
-			   is_active, created_at, updated_at
+			   is_active, platform_fee_percentage, created_at, updated_at
 		FROM companies
 		WHERE approval_status = $1
 		ORDER BY created_at DESC
@@ -379,6 +444,7 @@ func (r *CompanyRepository) GetByApprovalStatus(status string) ([]*Company, erro
 			&company.ApprovedBy,
 			&company.ApprovedAt,
 			&company.IsActive,
+			&company.PlatformFeePercentage,
 			&company.CreatedAt,
 			&company.UpdatedAt,
 		)
@@ -396,7 +462,7 @@ func (r *CompanyRepository) GetCompanies(limit, offset int, status *string, sear
 	query := `
 		SELECT id, name, cui, registration_number, iban, bank_name, legal_address,
 		       contact_email, contact_phone, approval_status, rejected_reason,
-		       is_active, created_at, updated_at
+		       is_active, platform_fee_percentage, created_at, updated_at
 		FROM companies
 		WHERE 1=1
 	`
@@ -446,6 +512,7 @@ func (r *CompanyRepository) GetCompanies(limit, offset int, status *string, sear
 			&company.ApprovalStatus,
 			&company.RejectedReason,
 			&company.IsActive,
+			&company.PlatformFeePercentage,
 			&company.CreatedAt,
 			&company.UpdatedAt,
 		)
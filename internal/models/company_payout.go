@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyPayout pays a company the month's earnings of its cleaners in one transfer.
+// The cleaners' own payouts, linked by company_payout_id, are the per-cleaner breakdown.
+type CompanyPayout struct {
+	ID                string
+	CompanyID         string
+	PeriodStart       time.Time
+	PeriodEnd         time.Time
+	Status            string // PayoutStatusPending, Sent, Failed or Invoiced
+	CleanerCount      int
+	TotalBookings     int
+	TotalEarnings     float64
+	PlatformFees      float64
+	NetAmount         float64
+	IBAN              sql.NullString // Encrypted company IBAN, recorded when sent
+	TransferReference sql.NullString
+	PaidAt            sql.NullTime
+	FailedReason      sql.NullString
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Add includes a cleaner's payout in the company totals
+func (c *CompanyPayout) Add(payout *Payout) {
+	c.CleanerCount++
+	c.TotalBookings += payout.TotalBookings
+	c.TotalEarnings += payout.TotalEarnings
+	c.PlatformFees += payout.PlatformFees
+	c.NetAmount += payout.NetAmount
+}
+
+// CompanyPayoutRepository handles company payout database operations
+type CompanyPayoutRepository struct {
+	db *sql.DB
+}
+
+// NewCompanyPayoutRepository creates a new company payout repository
+func NewCompanyPayoutRepository(db *sql.DB) *CompanyPayoutRepository {
+	return &CompanyPayoutRepository{db: db}
+}
+
+func insertCompanyPayout(q rowQuerier, payout *CompanyPayout) error {
+	if payout.ID == "" {
+		payout.ID = uuid.New().String()
+	}
+
+	return q.QueryRow(`
+		INSERT INTO company_payouts (
+			id, company_id, period_start, period_end, status, cleaner_count,
+			total_bookings, total_earnings, platform_fees, net_amount
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at
+	`, payout.ID, payout.CompanyID, payout.PeriodStart, payout.PeriodEnd, payout.Status, payout.CleanerCount,
+		payout.TotalBookings, payout.TotalEarnings, payout.PlatformFees, payout.NetAmount,
+	).Scan(&payout.CreatedAt, &payout.UpdatedAt)
+}
+
+// Update stores the status and transfer details of a company payout
+func (r *CompanyPayoutRepository) Update(payout *CompanyPayout) error {
+	return r.db.QueryRow(`
+		UPDATE company_payouts
+		SET status = $2, iban = $3, transfer_reference = $4, paid_at = $5, failed_reason = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, payout.ID, payout.Status, payout.IBAN, payout.TransferReference, payout.PaidAt, payout.FailedReason,
+	).Scan(&payout.UpdatedAt)
+}
+
+const companyPayoutSelect = `
+	SELECT id, company_id, period_start, period_end, status, cleaner_count,
+	       total_bookings, total_earnings, platform_fees, net_amount,
+	       iban, transfer_reference, paid_at, failed_reason, created_at, updated_at
+	FROM company_payouts
+`
+
+// GetByID returns a company payout
+func (r *CompanyPayoutRepository) GetByID(id string) (*CompanyPayout, error) {
+	payouts, err := r.query(companyPayoutSelect+` WHERE id = $1`, id)
+	if err != nil || len(payouts) == 0 {
+		return nil, err
+	}
+	return payouts[0], nil
+}
+
+// GetByCompanyID returns a company's payouts, newest period first
+func (r *CompanyPayoutRepository) GetByCompanyID(companyID string, limit, offset int) ([]*CompanyPayout, error) {
+	return r.query(companyPayoutSelect+`
+		WHERE company_id = $1
+		ORDER BY period_start DESC, created_at DESC
+		LIMIT $2 OFFSET $3
+	`, companyID, limit, offset)
+}
+
+// GetByStatus returns company payouts with a status, oldest first
+func (r *CompanyPayoutRepository) GetByStatus(status string, limit, offset int) ([]*CompanyPayout, error) {
+	return r.query(companyPayoutSelect+`
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+}
+
+// GetUnsettled returns company payouts still to be sent, or invoiced cash fees still to be paid,
+// oldest first
+func (r *CompanyPayoutRepository) GetUnsettled() ([]*CompanyPayout, error) {
+	return r.query(companyPayoutSelect+`
+		WHERE status IN ($1, $2, $3)
+		ORDER BY created_at ASC
+	`, PayoutStatusPending, PayoutStatusFailed, PayoutStatusInvoiced)
+}
+
+// GetPaidBetween returns the company payouts settled in [from, to), oldest first
+func (r *CompanyPayoutRepository) GetPaidBetween(from, to time.Time) ([]*CompanyPayout, error) {
+	return r.query(companyPayoutSelect+`
+		WHERE status = $1 AND paid_at >= $2 AND paid_at < $3
+		ORDER BY paid_at ASC
+	`, PayoutStatusSent, from, to)
+}
+
+func (r *CompanyPayoutRepository) query(query string, args ...interface{}) ([]*CompanyPayout, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*CompanyPayout
+	for rows.Next() {
+		payout := &CompanyPayout{}
+		if err := rows.Scan(
+			&payout.ID, &payout.CompanyID, &payout.PeriodStart, &payout.PeriodEnd, &payout.Status, &payout.CleanerCount,
+			&payout.TotalBookings, &payout.TotalEarnings, &payout.PlatformFees, &payout.NetAmount,
+			&payout.IBAN, &payout.TransferReference, &payout.PaidAt, &payout.FailedReason, &payout.CreatedAt, &payout.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Credit note types
+const (
+	CreditNoteTypeFull    = "FULL"    // Cancels the whole invoice
+	CreditNoteTypePartial = "PARTIAL" // Credits part of the invoice
+)
+
+// CreditNote is a corrective document (factura storno) crediting all or part of an invoice.
+// Amounts are stored positive.
+type CreditNote struct {
+	ID               string
+	CreditNoteNumber string
+	InvoiceID        string
+	RefundPaymentID  sql.NullString // Refund that triggered the credit note, if any
+	CreditType       string
+	Reason           string
+	IssueDate        time.Time
+	ClientName       string
+	ClientEmail      sql.NullString
+	Subtotal         float64
+	TaxAmount        float64
+	TotalAmount      float64
+	Currency         string
+	PdfURL           sql.NullString
+	XmlURL           sql.NullString
+	ANAFUploadIndex  sql.NullString
+	ANAFStatus       ANAFStatus
+	ANAFSubmittedAt  sql.NullTime
+	ANAFProcessedAt  sql.NullTime
+	ANAFDownloadID   sql.NullString
+	ANAFErrors       []ANAFError
+	ANAFRetryCount   int
+	CreatedBy        sql.NullString
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// CreditNoteRepository handles credit note database operations
+type CreditNoteRepository struct {
+	db *sql.DB
+}
+
+// NewCreditNoteRepository creates a new credit note repository
+func NewCreditNoteRepository(db *sql.DB) *CreditNoteRepository {
+	return &CreditNoteRepository{db: db}
+}
+
+// Create numbers a credit note from the credit note series and stores it. The invoice row is
+// locked so that concurrent credit notes can never credit more than the invoice total.
+func (r *CreditNoteRepository) Create(note *CreditNote) error {
+	if note.ANAFStatus == "" {
+		note.ANAFStatus = ANAFStatusPending
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var invoiceTotal, credited float64
+	if err := tx.QueryRow(`SELECT total_amount FROM invoices WHERE id = $1 FOR UPDATE`, note.InvoiceID).Scan(&invoiceTotal); err != nil {
+		return fmt.Errorf("failed to lock invoice: %w", err)
+	}
+	if err := tx.QueryRow(`
+		SELECT COALESCE(SUM(total_amount), 0) FROM credit_notes WHERE invoice_id = $1
+	`, note.InvoiceID).Scan(&credited); err != nil {
+		return fmt.Errorf("failed to get credited amount: %w", err)
+	}
+	if err := checkCreditLimit(note.TotalAmount, invoiceTotal, credited); err != nil {
+		return err
+	}
+
+	note.CreditNoteNumber, err = allocateDocumentNumber(tx, DocumentTypeCreditNote, note.IssueDate)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO credit_notes (
+			credit_note_number, invoice_id, refund_payment_id, credit_type, reason, issue_date,
+			client_name, client_email, subtotal, tax_amount, total_amount, currency, anaf_status, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at, updated_at
+	`, note.CreditNoteNumber, note.InvoiceID, note.RefundPaymentID, note.CreditType, note.Reason, note.IssueDate,
+		note.ClientName, note.ClientEmail, note.Subtotal, note.TaxAmount, note.TotalAmount, note.Currency, note.ANAFStatus, note.CreatedBy,
+	).Scan(&note.ID, &note.CreatedAt, &note.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create credit note: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit credit note: %w", err)
+	}
+	return nil
+}
+
+// checkCreditLimit fails when a credit would take the credit notes of an invoice past its total
+func checkCreditLimit(amount, invoiceTotal, credited float64) error {
+	remaining := math.Round((invoiceTotal-credited)*100) / 100
+	if amount > remaining {
+		return fmt.Errorf("credit of %.2f exceeds the %.2f left to credit on the invoice", amount, remaining)
+	}
+	return nil
+}
+
+// GetCreditedAmount returns the total already credited on an invoice
+func (r *CreditNoteRepository) GetCreditedAmount(invoiceID string) (float64, error) {
+	var credited float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(total_amount), 0) FROM credit_notes WHERE invoice_id = $1
+	`, invoiceID).Scan(&credited)
+	return credited, err
+}
+
+// UpdateFiles stores the paths of the generated PDF and XML
+func (r *CreditNoteRepository) UpdateFiles(note *CreditNote) error {
+	_, err := r.db.Exec(`
+		UPDATE credit_notes SET pdf_url = $2, xml_url = $3, updated_at = NOW() WHERE id = $1
+	`, note.ID, note.PdfURL, note.XmlURL)
+	return err
+}
+
+// UpdateANAFStatus stores the outcome of an ANAF submission or status check. A failed upload
+// also records when it was attempted, for the retry backoff.
+func (r *CreditNoteRepository) UpdateANAFStatus(note *CreditNote) error {
+	anafErrorsJSON, err := encodeANAFErrors(note.ANAFErrors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE credit_notes
+		SET anaf_upload_index = $2, anaf_status = $3, anaf_submitted_at = $4, anaf_processed_at = $5,
+		    anaf_download_id = $6, anaf_errors = $7, anaf_retry_count = $8,
+		    anaf_last_retry_at = CASE WHEN $3 = 'failed' THEN NOW() ELSE anaf_last_retry_at END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, note.ID, note.ANAFUploadIndex, note.ANAFStatus, note.ANAFSubmittedAt, note.ANAFProcessedAt,
+		note.ANAFDownloadID, anafErrorsJSON, note.ANAFRetryCount)
+	return err
+}
+
+const creditNoteSelect = `
+	SELECT id, credit_note_number, invoice_id, refund_payment_id, credit_type, reason, issue_date,
+	       client_name, client_email, subtotal, tax_amount, total_amount, currency, pdf_url, xml_url,
+	       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
+	       anaf_download_id, anaf_errors, anaf_retry_count, created_by, created_at, updated_at
+	FROM credit_notes
+`
+
+// GetByID returns a credit note
+func (r *CreditNoteRepository) GetByID(id string) (*CreditNote, error) {
+	notes, err := r.query(creditNoteSelect+` WHERE id = $1`, id)
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+	return notes[0], nil
+}
+
+// GetByRefundPaymentID returns the credit note issued for a refund
+func (r *CreditNoteRepository) GetByRefundPaymentID(paymentID string) (*CreditNote, error) {
+	notes, err := r.query(creditNoteSelect+` WHERE refund_payment_id = $1`, paymentID)
+	if err != nil || len(notes) == 0 {
+		return nil, err
+	}
+	return notes[0], nil
+}
+
+// GetByInvoiceID returns the credit notes of an invoice, oldest first
+func (r *CreditNoteRepository) GetByInvoiceID(invoiceID string) ([]*CreditNote, error) {
+	return r.query(creditNoteSelect+` WHERE invoice_id = $1 ORDER BY created_at ASC`, invoiceID)
+}
+
+// GetIssuedBetween returns the credit notes issued in [from, to), in numbering order
+func (r *CreditNoteRepository) GetIssuedBetween(from, to time.Time) ([]*CreditNote, error) {
+	return r.query(creditNoteSelect+`
+		WHERE issue_date >= $1 AND issue_date < $2
+		ORDER BY issue_date ASC, credit_note_number ASC
+	`, from, to)
+}
+
+// GetPendingANAFSubmission returns credit notes due for (re)submission to ANAF, once the
+// submission delay or the backoff after their last failed attempt has passed
+func (r *CreditNoteRepository) GetPendingANAFSubmission(submissionDelay, retryDelay time.Duration, maxRetries, limit int) ([]*CreditNote, error) {
+	return r.query(creditNoteSelect+`
+		WHERE `+anafSubmissionDue+`
+		ORDER BY created_at ASC
+		LIMIT $4
+	`, submissionDelay.Seconds(), retryDelay.Seconds(), maxRetries, limit)
+}
+
+func (r *CreditNoteRepository) query(query string, args ...interface{}) ([]*CreditNote, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []*CreditNote
+	for rows.Next() {
+		note := &CreditNote{}
+		var anafErrorsJSON sql.NullString
+		if err := rows.Scan(
+			&note.ID, &note.CreditNoteNumber, &note.InvoiceID, &note.RefundPaymentID, &note.CreditType, &note.Reason, &note.IssueDate,
+			&note.ClientName, &note.ClientEmail, &note.Subtotal, &note.TaxAmount, &note.TotalAmount, &note.Currency, &note.PdfURL, &note.XmlURL,
+			&note.ANAFUploadIndex, &note.ANAFStatus, &note.ANAFSubmittedAt, &note.ANAFProcessedAt,
+			&note.ANAFDownloadID, &anafErrorsJSON, &note.ANAFRetryCount, &note.CreatedBy, &note.CreatedAt, &note.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		note.ANAFErrors = decodeANAFErrors(anafErrorsJSON)
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
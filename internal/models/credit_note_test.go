@@ -0,0 +1,30 @@
+package models
+
+import "testing"
+
+func TestCheckCreditLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		total    float64
+		credited float64
+		wantErr  bool
+	}{
+		{"first credit within the total", 100, 238, 0, false},
+		{"credit of the whole invoice", 238, 238, 0, false},
+		{"credit of exactly what is left", 138, 238, 100, false},
+		{"credit past the total", 238.01, 238, 0, true},
+		{"credit past what earlier notes left", 150, 238, 100, true},
+		{"fully credited invoice", 0.01, 238, 238, true},
+		{"floating point remainder", 0.1, 0.3, 0.2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCreditLimit(tt.amount, tt.total, tt.credited)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCreditLimit(%.2f, %.2f, %.2f) error = %v, want error %v", tt.amount, tt.total, tt.credited, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Dispute types
@@ -26,10 +27,11 @@ const (
 
 // Dispute resolution types
 const (
-	DisputeResolutionPartialRefund = "PARTIAL_REFUND"
-	DisputeResolutionFullRefund    = "FULL_REFUND"
-	DisputeResolutionReclean       = "RECLEAN"
-	DisputeResolutionRejected      = "REJECTED"
+	DisputeResolutionPartialRefund  = "PARTIAL_REFUND"
+	DisputeResolutionFullRefund     = "FULL_REFUND"
+	DisputeResolutionRefundToCredit = "REFUND_TO_CREDIT" // Refund to the client's wallet instead of the card
+	DisputeResolutionReclean        = "RECLEAN"
+	DisputeResolutionRejected       = "REJECTED"
 )
 
 // Dispute represents a customer dispute for a completed booking
@@ -140,6 +142,33 @@ func (r *DisputeRepository) GetByBookingID(bookingID string) (*Dispute, error) {
 	return dispute, nil
 }
 
+// GetOpenBookingIDs returns which of the given bookings have a dispute that is not yet resolved
+func (r *DisputeRepository) GetOpenBookingIDs(bookingIDs []string) (map[string]bool, error) {
+	open := make(map[string]bool)
+	if len(bookingIDs) == 0 {
+		return open, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT DISTINCT booking_id
+		FROM disputes
+		WHERE booking_id = ANY($1) AND status IN ($2, $3)
+	`, pq.Array(bookingIDs), DisputeStatusOpen, DisputeStatusUnderReview)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookingID string
+		if err := rows.Scan(&bookingID); err != nil {
+			return nil, err
+		}
+		open[bookingID] = true
+	}
+	return open, rows.Err()
+}
+
 // Update updates a dispute
 func (r *DisputeRepository) Update(dispute *Dispute) error {
 	_, err := r.db.Exec(`
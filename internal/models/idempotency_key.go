@@ -0,0 +1,162 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Idempotency key statuses
+const (
+	IdempotencyStatusInProgress = "IN_PROGRESS"
+	IdempotencyStatusCompleted  = "COMPLETED"
+)
+
+// IdempotencyKey stores the outcome of a mutation executed under a client-supplied key
+type IdempotencyKey struct {
+	ID                 string          `json:"id"`
+	UserID             string          `json:"user_id"`
+	Operation          string          `json:"operation"`
+	Key                string          `json:"idempotency_key"`
+	RequestFingerprint string          `json:"request_fingerprint"`
+	Response           json.RawMessage `json:"response"`
+	Status             string          `json:"status"`
+	ExpiresAt          time.Time       `json:"expires_at"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// IdempotencyKeyRepository handles idempotency key database operations
+type IdempotencyKeyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *sql.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Reserve inserts an IN_PROGRESS record for the key, leased for ttl.
+// Returns false if a live record for the same user/operation/key already exists.
+func (r *IdempotencyKeyRepository) Reserve(userID, operation, key, fingerprint string, ttl time.Duration) (bool, error) {
+	// Expired keys may be reused
+	_, err := r.db.Exec(`
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND operation = $2 AND idempotency_key = $3 AND expires_at < NOW()
+	`, userID, operation, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear expired idempotency key: %w", err)
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (user_id, operation, idempotency_key, request_fingerprint, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, operation, idempotency_key) DO NOTHING
+	`, userID, operation, key, fingerprint, IdempotencyStatusInProgress, time.Now().Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+// Get retrieves a live idempotency record
+func (r *IdempotencyKeyRepository) Get(userID, operation, key string) (*IdempotencyKey, error) {
+	record := &IdempotencyKey{}
+	var response []byte
+
+	err := r.db.QueryRow(`
+		SELECT id, user_id, operation, idempotency_key, request_fingerprint, response,
+		       status, expires_at, created_at, updated_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND operation = $2 AND idempotency_key = $3 AND expires_at >= NOW()
+	`, userID, operation, key).Scan(
+		&record.ID,
+		&record.UserID,
+		&record.Operation,
+		&record.Key,
+		&record.RequestFingerprint,
+		&response,
+		&record.Status,
+		&record.ExpiresAt,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	record.Response = response
+	return record, nil
+}
+
+// Renew extends the lease of a key still IN_PROGRESS by ttl from now.
+// Returns false if the key is no longer reserved.
+func (r *IdempotencyKeyRepository) Renew(userID, operation, key string, ttl time.Duration) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE idempotency_keys
+		SET expires_at = $5
+		WHERE user_id = $1 AND operation = $2 AND idempotency_key = $3 AND status = $4
+	`, userID, operation, key, IdempotencyStatusInProgress, time.Now().Add(ttl))
+	if err != nil {
+		return false, fmt.Errorf("failed to renew idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew idempotency key: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// Complete stores the response for a reserved key and keeps it replayable for ttl
+func (r *IdempotencyKeyRepository) Complete(userID, operation, key string, response json.RawMessage, ttl time.Duration) error {
+	result, err := r.db.Exec(`
+		UPDATE idempotency_keys
+		SET response = $4, status = $5, expires_at = $6
+		WHERE user_id = $1 AND operation = $2 AND idempotency_key = $3
+	`, userID, operation, key, []byte(response), IdempotencyStatusCompleted, time.Now().Add(ttl))
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to complete idempotency key: key %s is no longer reserved", key)
+	}
+	return nil
+}
+
+// Delete removes a key so the request can be retried (used when execution fails)
+func (r *IdempotencyKeyRepository) Delete(userID, operation, key string) error {
+	_, err := r.db.Exec(`
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND operation = $2 AND idempotency_key = $3
+	`, userID, operation, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes all expired keys and returns how many were removed
+func (r *IdempotencyKeyRepository) DeleteExpired() (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
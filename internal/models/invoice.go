@@ -28,6 +28,15 @@ const (
 	ANAFStatusFailed     ANAFStatus = "failed"     // Technical failure (retry needed)
 )
 
+// InvoiceEmailStatus is the outcome of the last attempt to email an invoice to the client
+type InvoiceEmailStatus string
+
+const (
+	InvoiceEmailNotSent InvoiceEmailStatus = "NOT_SENT"
+	InvoiceEmailSent    InvoiceEmailStatus = "SENT"
+	InvoiceEmailFailed  InvoiceEmailStatus = "FAILED"
+)
+
 // ANAFError represents an error from ANAF
 type ANAFError struct {
 	Code    string `json:"code"`
@@ -65,6 +74,13 @@ type Invoice struct {
 	ANAFRetryCount      int
 	ANAFLastRetryAt     sql.NullTime
 
+	// Email delivery to the client
+	EmailStatus      InvoiceEmailStatus
+	EmailSentAt      sql.NullTime
+	EmailSendCount   int
+	EmailLastError   sql.NullString
+	SignedCopySentAt sql.NullTime // When the copy validated by ANAF was emailed
+
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
@@ -79,14 +95,51 @@ func NewInvoiceRepository(db *sql.DB) *InvoiceRepository {
 	return &InvoiceRepository{db: db}
 }
 
-// Create creates a new invoice
+// Create numbers and creates a new invoice
 func (r *InvoiceRepository) Create(invoice *Invoice) error {
+	return r.CreateWithLines(invoice, nil, nil)
+}
+
+// CreateWithLines numbers an invoice from the active invoice series and stores it together with
+// its itemized lines, and for B2B invoices the company details billed, in one transaction
+func (r *InvoiceRepository) CreateWithLines(invoice *Invoice, lines []*InvoiceLine, billing *InvoiceBillingDetails) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice.InvoiceNumber, err = allocateDocumentNumber(tx, DocumentTypeInvoice, invoice.IssueDate)
+	if err != nil {
+		return err
+	}
+	if err := insertInvoice(tx, invoice); err != nil {
+		return fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	for i, line := range lines {
+		line.InvoiceID = invoice.ID
+		line.LineNumber = i + 1
+		if err := insertInvoiceLine(tx, line); err != nil {
+			return fmt.Errorf("failed to insert invoice line %d: %w", line.LineNumber, err)
+		}
+	}
+	if billing != nil {
+		billing.InvoiceID = invoice.ID
+		if err := insertInvoiceBillingDetails(tx, billing); err != nil {
+			return fmt.Errorf("failed to insert invoice billing details: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertInvoice(q rowQuerier, invoice *Invoice) error {
 	// Set default ANAF status if not set
 	if invoice.ANAFStatus == "" {
 		invoice.ANAFStatus = ANAFStatusPending
 	}
 
-	return r.db.QueryRow(`
+	return q.QueryRow(`
 		INSERT INTO invoices (
 			booking_id, invoice_number, issue_date, due_date,
 			client_name, client_email, cleaner_name, service_description,
@@ -115,6 +168,7 @@ func (r *InvoiceRepository) GetByID(id string) (*Invoice, error) {
 		       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
 		       anaf_download_id, anaf_confirmation_url, anaf_errors,
 		       anaf_retry_count, anaf_last_retry_at,
+		       email_status, email_sent_at, email_send_count, email_last_error, signed_copy_sent_at,
 		       created_at, updated_at
 		FROM invoices
 		WHERE id = $1
@@ -126,6 +180,7 @@ func (r *InvoiceRepository) GetByID(id string) (*Invoice, error) {
 		&invoice.ANAFUploadIndex, &invoice.ANAFStatus, &invoice.ANAFSubmittedAt, &invoice.ANAFProcessedAt,
 		&invoice.ANAFDownloadID, &invoice.ANAFConfirmationURL, &anafErrorsJSON,
 		&invoice.ANAFRetryCount, &invoice.ANAFLastRetryAt,
+		&invoice.EmailStatus, &invoice.EmailSentAt, &invoice.EmailSendCount, &invoice.EmailLastError, &invoice.SignedCopySentAt,
 		&invoice.CreatedAt, &invoice.UpdatedAt,
 	)
 
@@ -201,18 +256,6 @@ func (r *InvoiceRepository) GetByInvoiceNumber(invoiceNumber string) (*Invoice,
 	return invoice, nil
 }
 
-// GenerateInvoiceNumber generates next invoice number in format INV-YYYY-NNNN
-func (r *InvoiceRepository) GenerateInvoiceNumber() (string, error) {
-	var nextVal int64
-	err := r.db.QueryRow("SELECT nextval('invoice_number_seq')").Scan(&nextVal)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate invoice number: %w", err)
-	}
-
-	year := time.Now().Year()
-	return fmt.Sprintf("INV-%d-%04d", year, nextVal), nil
-}
-
 // Update updates an invoice
 func (r *InvoiceRepository) Update(invoice *Invoice) error {
 	_, err := r.db.Exec(`
@@ -302,9 +345,44 @@ func (r *InvoiceRepository) UpdateANAFStatus(invoice *Invoice) error {
 	return err
 }
 
-// GetPendingANAFSubmission returns invoices that need to be submitted to ANAF
-func (r *InvoiceRepository) GetPendingANAFSubmission(limit int) ([]*Invoice, error) {
-	rows, err := r.db.Query(`
+// GetPendingANAFSubmission returns invoices due for (re)submission to ANAF: new invoices once
+// submissionDelay has passed since they were issued, and failed ones with retries left once the
+// retry delay, doubled after every failed attempt, has passed
+func (r *InvoiceRepository) GetPendingANAFSubmission(submissionDelay, retryDelay time.Duration, maxRetries, limit int) ([]*Invoice, error) {
+	return r.queryInvoices(invoiceSelect+`
+		WHERE status <> 'CANCELLED'
+		  AND (
+		    (anaf_status = 'pending' AND created_at <= NOW() - make_interval(secs => $1::float8))
+		    OR (anaf_status = 'failed' AND anaf_retry_count < $3
+		        AND COALESCE(anaf_last_retry_at, created_at) <= NOW() - make_interval(secs => $2::float8 * POWER(2, GREATEST(anaf_retry_count - 1, 0))))
+		  )
+		ORDER BY created_at ASC
+		LIMIT $4
+	`, submissionDelay.Seconds(), retryDelay.Seconds(), maxRetries, limit)
+}
+
+// GetANAFAwaitingConfirmation returns accepted invoices whose ANAF confirmation has not been archived yet
+func (r *InvoiceRepository) GetANAFAwaitingConfirmation(limit int) ([]*Invoice, error) {
+	return r.queryInvoices(invoiceSelect+`
+		WHERE anaf_status = 'accepted'
+		  AND anaf_download_id IS NOT NULL
+		  AND id NOT IN (SELECT invoice_id FROM anaf_confirmations WHERE invoice_id IS NOT NULL)
+		ORDER BY anaf_processed_at ASC
+		LIMIT $1
+	`, limit)
+}
+
+// GetIssuedBetween returns the invoices issued in [from, to), including cancelled ones, in
+// numbering order
+func (r *InvoiceRepository) GetIssuedBetween(from, to time.Time) ([]*Invoice, error) {
+	return r.queryInvoices(invoiceSelect+`
+		WHERE issue_date >= $1 AND issue_date < $2
+		ORDER BY issue_date ASC, invoice_number ASC
+	`, from, to)
+}
+
+// invoiceSelect lists the invoice columns in the order queryInvoices scans them
+const invoiceSelect = `
 		SELECT id, booking_id, invoice_number, issue_date, due_date,
 		       client_name, client_email, cleaner_name, service_description,
 		       subtotal, tax_amount, total_amount, currency, status,
@@ -312,14 +390,13 @@ func (r *InvoiceRepository) GetPendingANAFSubmission(limit int) ([]*Invoice, err
 		       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
 		       anaf_download_id, anaf_confirmation_url, anaf_errors,
 		       anaf_retry_count, anaf_last_retry_at,
+		       email_status, email_sent_at, email_send_count, email_last_error, signed_copy_sent_at,
 		       created_at, updated_at
-		FROM invoices
-		WHERE anaf_status IN ('pending', 'failed')
-		  AND status = 'ISSUED'
-		  AND (anaf_retry_count < 3 OR anaf_retry_count IS NULL)
-		ORDER BY created_at ASC
-		LIMIT $1
-	`, limit)
+		FROM invoices`
+
+// queryInvoices runs a query selecting invoiceSelect columns
+func (r *InvoiceRepository) queryInvoices(query string, args ...interface{}) ([]*Invoice, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -338,6 +415,7 @@ func (r *InvoiceRepository) GetPendingANAFSubmission(limit int) ([]*Invoice, err
 			&invoice.ANAFUploadIndex, &invoice.ANAFStatus, &invoice.ANAFSubmittedAt, &invoice.ANAFProcessedAt,
 			&invoice.ANAFDownloadID, &invoice.ANAFConfirmationURL, &anafErrorsJSON,
 			&invoice.ANAFRetryCount, &invoice.ANAFLastRetryAt,
+			&invoice.EmailStatus, &invoice.EmailSentAt, &invoice.EmailSendCount, &invoice.EmailLastError, &invoice.SignedCopySentAt,
 			&invoice.CreatedAt, &invoice.UpdatedAt,
 		)
 		if err != nil {
@@ -367,6 +445,7 @@ func (r *InvoiceRepository) GetANAFProcessingInvoices() ([]*Invoice, error) {
 		       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
 		       anaf_download_id, anaf_confirmation_url, anaf_errors,
 		       anaf_retry_count, anaf_last_retry_at,
+		       email_status, email_sent_at, email_send_count, email_last_error, signed_copy_sent_at,
 		       created_at, updated_at
 		FROM invoices
 		WHERE anaf_status = 'processing'
@@ -390,6 +469,7 @@ func (r *InvoiceRepository) GetANAFProcessingInvoices() ([]*Invoice, error) {
 			&invoice.ANAFUploadIndex, &invoice.ANAFStatus, &invoice.ANAFSubmittedAt, &invoice.ANAFProcessedAt,
 			&invoice.ANAFDownloadID, &invoice.ANAFConfirmationURL, &anafErrorsJSON,
 			&invoice.ANAFRetryCount, &invoice.ANAFLastRetryAt,
+			&invoice.EmailStatus, &invoice.EmailSentAt, &invoice.EmailSendCount, &invoice.EmailLastError, &invoice.SignedCopySentAt,
 			&invoice.CreatedAt, &invoice.UpdatedAt,
 		)
 		if err != nil {
@@ -408,3 +488,87 @@ func (r *InvoiceRepository) GetANAFProcessingInvoices() ([]*Invoice, error) {
 
 	return invoices, rows.Err()
 }
+
+// GetByStatus returns invoices with the given status, oldest first
+func (r *InvoiceRepository) GetByStatus(status InvoiceStatus) ([]*Invoice, error) {
+	rows, err := r.db.Query(`
+		SELECT id, booking_id, invoice_number, issue_date, due_date,
+		       client_name, client_email, cleaner_name, service_description,
+		       subtotal, tax_amount, total_amount, currency, status,
+		       pdf_url, xml_url, created_at, updated_at
+		FROM invoices
+		WHERE status = $1
+		ORDER BY issue_date ASC
+	`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []*Invoice{}
+	for rows.Next() {
+		invoice := &Invoice{}
+		err := rows.Scan(
+			&invoice.ID, &invoice.BookingID, &invoice.InvoiceNumber, &invoice.IssueDate, &invoice.DueDate,
+			&invoice.ClientName, &invoice.ClientEmail, &invoice.CleanerName, &invoice.ServiceDescription,
+			&invoice.Subtotal, &invoice.TaxAmount, &invoice.TotalAmount, &invoice.Currency, &invoice.Status,
+			&invoice.PdfURL, &invoice.XmlURL, &invoice.CreatedAt, &invoice.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+// RecordEmailDelivery stores the outcome of emailing an invoice; sendErr is nil if it was sent.
+// signedCopy marks the delivery of the copy validated by ANAF.
+func (r *InvoiceRepository) RecordEmailDelivery(invoice *Invoice, signedCopy bool, sendErr error) error {
+	invoice.EmailSendCount++
+	if sendErr != nil {
+		invoice.EmailStatus = InvoiceEmailFailed
+		invoice.EmailLastError = sql.NullString{String: sendErr.Error(), Valid: true}
+	} else {
+		now := time.Now()
+		invoice.EmailStatus = InvoiceEmailSent
+		invoice.EmailSentAt = sql.NullTime{Time: now, Valid: true}
+		invoice.EmailLastError = sql.NullString{}
+		if signedCopy {
+			invoice.SignedCopySentAt = sql.NullTime{Time: now, Valid: true}
+		}
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE invoices
+		SET email_status = $2, email_sent_at = $3, email_send_count = email_send_count + 1,
+		    email_last_error = $4, signed_copy_sent_at = $5, updated_at = NOW()
+		WHERE id = $1
+	`, invoice.ID, invoice.EmailStatus, invoice.EmailSentAt, invoice.EmailLastError, invoice.SignedCopySentAt)
+	if err != nil {
+		return fmt.Errorf("failed to record invoice email delivery: %w", err)
+	}
+	return nil
+}
+
+// MarkAsPaid sets an issued invoice to PAID
+func (r *InvoiceRepository) MarkAsPaid(id string) error {
+	result, err := r.db.Exec(`
+		UPDATE invoices
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, InvoiceStatusPaid, InvoiceStatusIssued)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("invoice is not awaiting payment")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// InvoiceBillingDetails are the company details a B2B invoice was issued to, copied from the
+// client's billing profile when the invoice was issued
+type InvoiceBillingDetails struct {
+	InvoiceID          string
+	LegalName          string
+	CUI                string
+	RegistrationNumber sql.NullString
+	VATPayer           bool
+	StreetAddress      string
+	City               string
+	County             string
+	PostalCode         sql.NullString
+	Country            string
+}
+
+// NewInvoiceBillingDetails copies a billing profile's details for an invoice
+func NewInvoiceBillingDetails(profile *BillingProfile) *InvoiceBillingDetails {
+	return &InvoiceBillingDetails{
+		LegalName:          profile.LegalName,
+		CUI:                profile.CUI,
+		RegistrationNumber: profile.RegistrationNumber,
+		VATPayer:           profile.VATPayer,
+		StreetAddress:      profile.StreetAddress,
+		City:               profile.City,
+		County:             profile.County,
+		PostalCode:         profile.PostalCode,
+		Country:            profile.Country,
+	}
+}
+
+func insertInvoiceBillingDetails(q rowQuerier, details *InvoiceBillingDetails) error {
+	var invoiceID string
+	return q.QueryRow(`
+		INSERT INTO invoice_billing_details (
+			invoice_id, legal_name, cui, registration_number, vat_payer,
+			street_address, city, county, postal_code, country
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING invoice_id
+	`, details.InvoiceID, details.LegalName, details.CUI, details.RegistrationNumber, details.VATPayer,
+		details.StreetAddress, details.City, details.County, details.PostalCode, details.Country).
+		Scan(&invoiceID)
+}
+
+// GetBillingDetails returns the company details an invoice was issued to, nil for invoices to
+// private clients
+func (r *InvoiceRepository) GetBillingDetails(invoiceID string) (*InvoiceBillingDetails, error) {
+	details := &InvoiceBillingDetails{}
+	err := r.db.QueryRow(`
+		SELECT invoice_id, legal_name, cui, registration_number, vat_payer,
+		       street_address, city, county, postal_code, country
+		FROM invoice_billing_details
+		WHERE invoice_id = $1
+	`, invoiceID).Scan(&details.InvoiceID, &details.LegalName, &details.CUI, &details.RegistrationNumber,
+		&details.VATPayer, &details.StreetAddress, &details.City, &details.County, &details.PostalCode,
+		&details.Country)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice billing details: %w", err)
+	}
+	return details, nil
+}
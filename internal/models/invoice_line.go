@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// InvoiceLine is one itemized line of a client invoice with the VAT applied to it
+type InvoiceLine struct {
+	ID          string
+	InvoiceID   string
+	LineNumber  int
+	Description string
+	VATCategory string // service, addons or supplies
+	Quantity    float64
+	UnitCode    string // UN/ECE Recommendation 20 unit
+	VATRate     float64
+	NetAmount   float64
+	VATAmount   float64
+	GrossAmount float64
+	CreatedAt   time.Time
+}
+
+// InvoiceLineRepository handles invoice line database operations
+type InvoiceLineRepository struct {
+	db *sql.DB
+}
+
+// NewInvoiceLineRepository creates a new invoice line repository
+func NewInvoiceLineRepository(db *sql.DB) *InvoiceLineRepository {
+	return &InvoiceLineRepository{db: db}
+}
+
+func insertInvoiceLine(q rowQuerier, line *InvoiceLine) error {
+	return q.QueryRow(`
+		INSERT INTO invoice_lines (
+			invoice_id, line_number, description, vat_category, quantity, unit_code,
+			vat_rate, net_amount, vat_amount, gross_amount
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`, line.InvoiceID, line.LineNumber, line.Description, line.VATCategory, line.Quantity, line.UnitCode,
+		line.VATRate, line.NetAmount, line.VATAmount, line.GrossAmount).
+		Scan(&line.ID, &line.CreatedAt)
+}
+
+// GetByInvoiceID returns an invoice's lines in order. Invoices issued before lines were
+// itemized have none.
+func (r *InvoiceLineRepository) GetByInvoiceID(invoiceID string) ([]*InvoiceLine, error) {
+	rows, err := r.db.Query(`
+		SELECT id, invoice_id, line_number, description, vat_category, quantity, unit_code,
+			vat_rate, net_amount, vat_amount, gross_amount, created_at
+		FROM invoice_lines
+		WHERE invoice_id = $1
+		ORDER BY line_number
+	`, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoice lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*InvoiceLine
+	for rows.Next() {
+		line := &InvoiceLine{}
+		if err := rows.Scan(&line.ID, &line.InvoiceID, &line.LineNumber, &line.Description, &line.VATCategory,
+			&line.Quantity, &line.UnitCode, &line.VATRate, &line.NetAmount, &line.VATAmount,
+			&line.GrossAmount, &line.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice line: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DocumentType is a kind of document numbered from its own series
+type DocumentType string
+
+const (
+	DocumentTypeInvoice           DocumentType = "INVOICE"
+	DocumentTypeCreditNote        DocumentType = "CREDIT_NOTE"
+	DocumentTypeCommissionInvoice DocumentType = "COMMISSION_INVOICE"
+	DocumentTypeProforma          DocumentType = "PROFORMA"
+)
+
+// InvoiceSeries is a numbering series. Documents of a type take their numbers from the type's
+// active series, as PREFIX-YYYY-NNNN when the series restarts every year, otherwise PREFIX-NNNN.
+type InvoiceSeries struct {
+	ID           string
+	DocumentType DocumentType
+	Prefix       string
+	YearlyReset  bool
+	IsActive     bool
+	LastNumber   int // Last number allocated in the current period, joined from the counters
+	CreatedBy    sql.NullString
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// counterYear is the counter period of a document issued on issueDate, 0 if the series never resets
+func (s *InvoiceSeries) counterYear(issueDate time.Time) int {
+	if s.YearlyReset {
+		return issueDate.Year()
+	}
+	return 0
+}
+
+// FormatNumber formats the number-th document of the series issued in year
+func (s *InvoiceSeries) FormatNumber(year, number int) string {
+	if s.YearlyReset {
+		return fmt.Sprintf("%s-%d-%04d", s.Prefix, year, number)
+	}
+	return fmt.Sprintf("%s-%04d", s.Prefix, number)
+}
+
+// NextNumber previews the number the series' next document issued today would get
+func (s *InvoiceSeries) NextNumber() string {
+	return s.FormatNumber(time.Now().Year(), s.LastNumber+1)
+}
+
+// InvoiceSeriesRepository handles numbering series database operations
+type InvoiceSeriesRepository struct {
+	db *sql.DB
+}
+
+// NewInvoiceSeriesRepository creates a new invoice series repository
+func NewInvoiceSeriesRepository(db *sql.DB) *InvoiceSeriesRepository {
+	return &InvoiceSeriesRepository{db: db}
+}
+
+const invoiceSeriesSelect = `
+	SELECT s.id, s.document_type, s.prefix, s.yearly_reset, s.is_active, COALESCE(c.last_number, 0),
+	       s.created_by, s.created_at, s.updated_at
+	FROM invoice_series s
+	LEFT JOIN invoice_series_counters c ON c.series_id = s.id
+	     AND c.year = CASE WHEN s.yearly_reset THEN EXTRACT(YEAR FROM CURRENT_DATE)::int ELSE 0 END`
+
+// List returns all series, active ones first within each document type
+func (r *InvoiceSeriesRepository) List() ([]*InvoiceSeries, error) {
+	rows, err := r.db.Query(invoiceSeriesSelect + ` ORDER BY s.document_type, s.is_active DESC, s.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice series: %w", err)
+	}
+	defer rows.Close()
+
+	var series []*InvoiceSeries
+	for rows.Next() {
+		s := &InvoiceSeries{}
+		if err := rows.Scan(&s.ID, &s.DocumentType, &s.Prefix, &s.YearlyReset, &s.IsActive, &s.LastNumber,
+			&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice series: %w", err)
+		}
+		series = append(series, s)
+	}
+	return series, rows.Err()
+}
+
+// GetByID finds a series by ID
+func (r *InvoiceSeriesRepository) GetByID(id string) (*InvoiceSeries, error) {
+	s := &InvoiceSeries{}
+	err := r.db.QueryRow(invoiceSeriesSelect+` WHERE s.id = $1`, id).Scan(
+		&s.ID, &s.DocumentType, &s.Prefix, &s.YearlyReset, &s.IsActive, &s.LastNumber,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice series: %w", err)
+	}
+	return s, nil
+}
+
+// Create stores a new series, its first document numbered startNumber. An active series
+// replaces the document type's current one.
+func (r *InvoiceSeriesRepository) Create(series *InvoiceSeries, startNumber int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if series.IsActive {
+		if _, err := tx.Exec(`
+			UPDATE invoice_series SET is_active = FALSE, updated_at = NOW()
+			WHERE document_type = $1 AND is_active
+		`, series.DocumentType); err != nil {
+			return fmt.Errorf("failed to deactivate current series: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO invoice_series (document_type, prefix, yearly_reset, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, series.DocumentType, series.Prefix, series.YearlyReset, series.IsActive, series.CreatedBy).
+		Scan(&series.ID, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create invoice series: %w", err)
+	}
+
+	if startNumber > 1 {
+		series.LastNumber = startNumber - 1
+		if _, err := tx.Exec(`
+			INSERT INTO invoice_series_counters (series_id, year, last_number) VALUES ($1, $2, $3)
+		`, series.ID, series.counterYear(time.Now()), series.LastNumber); err != nil {
+			return fmt.Errorf("failed to set starting number: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Activate makes a series the one its document type is numbered from
+func (r *InvoiceSeriesRepository) Activate(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE invoice_series SET is_active = FALSE, updated_at = NOW()
+		WHERE is_active AND id <> $1
+		  AND document_type = (SELECT document_type FROM invoice_series WHERE id = $1)
+	`, id); err != nil {
+		return fmt.Errorf("failed to deactivate current series: %w", err)
+	}
+	if _, err := tx.Exec(`
+		UPDATE invoice_series SET is_active = TRUE, updated_at = NOW() WHERE id = $1
+	`, id); err != nil {
+		return fmt.Errorf("failed to activate series: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// allocateDocumentNumber takes the next number of the document type's active series. Called in
+// the transaction storing the document, the counter row stays locked until it commits, so
+// numbers are handed out in order and a rolled back insert gives its number back.
+func allocateDocumentNumber(q rowQuerier, documentType DocumentType, issueDate time.Time) (string, error) {
+	series := &InvoiceSeries{DocumentType: documentType}
+	err := q.QueryRow(`
+		SELECT id, prefix, yearly_reset FROM invoice_series WHERE document_type = $1 AND is_active
+	`, documentType).Scan(&series.ID, &series.Prefix, &series.YearlyReset)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("no active numbering series for %s", documentType)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get numbering series: %w", err)
+	}
+
+	year := series.counterYear(issueDate)
+	var number int
+	err = q.QueryRow(`
+		INSERT INTO invoice_series_counters (series_id, year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (series_id, year) DO UPDATE
+		SET last_number = invoice_series_counters.last_number + 1, updated_at = NOW()
+		RETURNING last_number
+	`, series.ID, year).Scan(&number)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate %s number: %w", documentType, err)
+	}
+	return series.FormatNumber(issueDate.Year(), number), nil
+}
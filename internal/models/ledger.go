@@ -0,0 +1,271 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// LedgerAccount identifies an account in the double-entry ledger
+type LedgerAccount string
+
+const (
+	LedgerAccountClientReceivables LedgerAccount = "CLIENT_RECEIVABLES" // Client money collected via the payment provider
+	LedgerAccountCleanerPayables   LedgerAccount = "CLEANER_PAYABLES"   // Earnings owed to cleaners (sub-ledger per cleaner)
+	LedgerAccountPlatformRevenue   LedgerAccount = "PLATFORM_REVENUE"   // Platform commission, net of VAT
+	LedgerAccountRefunds           LedgerAccount = "REFUNDS"            // Platform share of money returned to clients
+	LedgerAccountVATPayable        LedgerAccount = "VAT_PAYABLE"        // VAT collected on platform commission
+	LedgerAccountCash              LedgerAccount = "CASH"               // Platform bank account
+	LedgerAccountClientCredits     LedgerAccount = "CLIENT_CREDITS"     // Unspent client wallet credit
+)
+
+// LedgerTransactionType identifies what caused a ledger posting
+type LedgerTransactionType string
+
+const (
+	LedgerTransactionCapture          LedgerTransactionType = "CAPTURE"
+	LedgerTransactionRefund           LedgerTransactionType = "REFUND"
+	LedgerTransactionPayout           LedgerTransactionType = "PAYOUT"
+	LedgerTransactionAdjustment       LedgerTransactionType = "ADJUSTMENT"
+	LedgerTransactionWalletCredit     LedgerTransactionType = "WALLET_CREDIT"
+	LedgerTransactionWalletRedemption LedgerTransactionType = "WALLET_REDEMPTION"
+	LedgerTransactionWalletExpiry     LedgerTransactionType = "WALLET_EXPIRY"
+	LedgerTransactionTip              LedgerTransactionType = "TIP"
+)
+
+// LedgerTransaction groups balanced ledger entries for a single money movement
+type LedgerTransaction struct {
+	ID              string
+	TransactionType LedgerTransactionType
+	ReferenceID     string
+	BookingID       sql.NullString
+	Description     string
+	CreatedAt       time.Time
+}
+
+// LedgerEntry is a single debit or credit line
+type LedgerEntry struct {
+	ID            string
+	TransactionID string
+	Account       LedgerAccount
+	CleanerID     sql.NullString
+	Debit         float64
+	Credit        float64
+	CreatedAt     time.Time
+}
+
+// LedgerAccountBalance is one row of the trial balance
+type LedgerAccountBalance struct {
+	Account     LedgerAccount
+	TotalDebit  float64
+	TotalCredit float64
+}
+
+// LedgerRepository handles ledger database operations
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Post writes a transaction and its entries atomically.
+// Returns false without writing if a transaction for the same type and reference already exists.
+func (r *LedgerRepository) Post(txn *LedgerTransaction, entries []*LedgerEntry) (bool, error) {
+	if err := validateBalanced(entries); err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin ledger transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO ledger_transactions (transaction_type, reference_id, booking_id, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transaction_type, reference_id) DO NOTHING
+		RETURNING id, created_at
+	`, txn.TransactionType, txn.ReferenceID, txn.BookingID, txn.Description).Scan(&txn.ID, &txn.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create ledger transaction: %w", err)
+	}
+
+	for _, entry := range entries {
+		entry.TransactionID = txn.ID
+		err := tx.QueryRow(`
+			INSERT INTO ledger_entries (transaction_id, account, cleaner_id, debit, credit)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at
+		`, entry.TransactionID, entry.Account, entry.CleanerID, entry.Debit, entry.Credit).Scan(&entry.ID, &entry.CreatedAt)
+		if err != nil {
+			return false, fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit ledger transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetTrialBalance returns total debits and credits per account up to asOf
+func (r *LedgerRepository) GetTrialBalance(asOf time.Time) ([]*LedgerAccountBalance, error) {
+	rows, err := r.db.Query(`
+		SELECT account, COALESCE(SUM(debit), 0), COALESCE(SUM(credit), 0)
+		FROM ledger_entries
+		WHERE created_at <= $1
+		GROUP BY account
+		ORDER BY account
+	`, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := []*LedgerAccountBalance{}
+	for rows.Next() {
+		balance := &LedgerAccountBalance{}
+		if err := rows.Scan(&balance.Account, &balance.TotalDebit, &balance.TotalCredit); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, rows.Err()
+}
+
+// GetTransactionsBetween returns the transactions posted in [from, to) with their entries, oldest first
+func (r *LedgerRepository) GetTransactionsBetween(from, to time.Time) ([]*LedgerTransaction, map[string][]*LedgerEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT t.id, t.transaction_type, t.reference_id, t.booking_id, t.description, t.created_at,
+		       e.id, e.account, e.cleaner_id, e.debit, e.credit, e.created_at
+		FROM ledger_transactions t
+		JOIN ledger_entries e ON e.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		ORDER BY t.created_at ASC, t.id, e.created_at ASC
+	`, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	txns := []*LedgerTransaction{}
+	entries := make(map[string][]*LedgerEntry)
+	for rows.Next() {
+		txn := &LedgerTransaction{}
+		entry := &LedgerEntry{}
+		if err := rows.Scan(&txn.ID, &txn.TransactionType, &txn.ReferenceID, &txn.BookingID, &txn.Description, &txn.CreatedAt,
+			&entry.ID, &entry.Account, &entry.CleanerID, &entry.Debit, &entry.Credit, &entry.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		entry.TransactionID = txn.ID
+		if _, seen := entries[txn.ID]; !seen {
+			txns = append(txns, txn)
+		}
+		entries[txn.ID] = append(entries[txn.ID], entry)
+	}
+
+	return txns, entries, rows.Err()
+}
+
+// GetCleanerBalance returns what the platform owes a cleaner (credits minus debits on CLEANER_PAYABLES) up to asOf
+func (r *LedgerRepository) GetCleanerBalance(cleanerID string, asOf time.Time) (float64, error) {
+	var balance float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(credit - debit), 0)
+		FROM ledger_entries
+		WHERE account = $1 AND cleaner_id = $2 AND created_at <= $3
+	`, LedgerAccountCleanerPayables, cleanerID, asOf).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetCleanerBalanceForBookings returns the part of a cleaner's balance that comes from the given bookings
+func (r *LedgerRepository) GetCleanerBalanceForBookings(cleanerID string, bookingIDs []string, asOf time.Time) (float64, error) {
+	if len(bookingIDs) == 0 {
+		return 0, nil
+	}
+
+	var balance float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(e.credit - e.debit), 0)
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE e.account = $1 AND e.cleaner_id = $2 AND e.created_at <= $3
+		  AND t.booking_id = ANY($4) AND t.transaction_type <> $5
+	`, LedgerAccountCleanerPayables, cleanerID, asOf, pq.Array(bookingIDs), LedgerTransactionTip).Scan(&balance)
+	return balance, err
+}
+
+// HasCleanerEntries reports whether any payable entries exist for a cleaner
+func (r *LedgerRepository) HasCleanerEntries(cleanerID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM ledger_entries WHERE account = $1 AND cleaner_id = $2)
+	`, LedgerAccountCleanerPayables, cleanerID).Scan(&exists)
+	return exists, err
+}
+
+// GetEntriesByBookingID returns all entries posted for a booking, oldest first
+func (r *LedgerRepository) GetEntriesByBookingID(bookingID string) ([]*LedgerEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT e.id, e.transaction_id, e.account, e.cleaner_id, e.debit, e.credit, e.created_at
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE t.booking_id = $1
+		ORDER BY e.created_at ASC
+	`, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*LedgerEntry{}
+	for rows.Next() {
+		entry := &LedgerEntry{}
+		if err := rows.Scan(&entry.ID, &entry.TransactionID, &entry.Account, &entry.CleanerID, &entry.Debit, &entry.Credit, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// validateBalanced ensures entries are well-formed and debits equal credits (to the cent)
+func validateBalanced(entries []*LedgerEntry) error {
+	if len(entries) < 2 {
+		return fmt.Errorf("ledger transaction needs at least two entries")
+	}
+
+	var debits, credits int64
+	for _, entry := range entries {
+		if entry.Debit < 0 || entry.Credit < 0 {
+			return fmt.Errorf("ledger entry amounts must not be negative")
+		}
+		if (entry.Debit == 0) == (entry.Credit == 0) {
+			return fmt.Errorf("ledger entry must be either a debit or a credit")
+		}
+		debits += int64(math.Round(entry.Debit * 100))
+		credits += int64(math.Round(entry.Credit * 100))
+	}
+
+	if debits != credits {
+		return fmt.Errorf("unbalanced ledger transaction: debits %.2f != credits %.2f", float64(debits)/100, float64(credits)/100)
+	}
+
+	return nil
+}
@@ -4,14 +4,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // PaymentProvider represents the payment gateway
 type PaymentProvider string
 
 const (
-	PaymentProviderNetopia PaymentProvider = "NETOPIA"
-	PaymentProviderManual  PaymentProvider = "MANUAL"
+	PaymentProviderNetopia      PaymentProvider = "NETOPIA"
+	PaymentProviderManual       PaymentProvider = "MANUAL"
+	PaymentProviderCash         PaymentProvider = "CASH"          // Collected by the cleaner on site
+	PaymentProviderBankTransfer PaymentProvider = "BANK_TRANSFER" // Paid by a company against a proforma
 )
 
 // PaymentType represents the type of payment transaction
@@ -22,6 +26,7 @@ const (
 	PaymentTypeCapture          PaymentType = "CAPTURE"
 	PaymentTypeRefund           PaymentType = "REFUND"
 	PaymentTypeCancellation     PaymentType = "CANCELLATION"
+	PaymentTypeTip              PaymentType = "TIP" // Charged and captured at once, paid in full to the cleaner
 )
 
 // PaymentStatus represents the current status of a payment
@@ -298,3 +303,106 @@ func (r *PaymentRepository) Update(payment *Payment) error {
 		payment.RefundedAt,
 	).Scan(&payment.UpdatedAt)
 }
+
+// GetSettledBetween returns the payments captured and the refunds paid out in [from, to),
+// oldest first
+func (r *PaymentRepository) GetSettledBetween(from, to time.Time) ([]*Payment, error) {
+	query := `
+		SELECT
+			id, booking_id, user_id, provider, provider_transaction_id, provider_order_id,
+			payment_type, status, amount, currency, card_last_four, card_brand,
+			error_code, error_message, provider_response,
+			authorized_at, captured_at, failed_at, refunded_at,
+			created_at, updated_at
+		FROM payments
+		WHERE (payment_type <> $1 AND captured_at >= $2 AND captured_at < $3)
+		   OR (payment_type = $1 AND status = $4 AND refunded_at >= $2 AND refunded_at < $3)
+		ORDER BY COALESCE(captured_at, refunded_at) ASC
+	`
+
+	rows, err := r.db.Query(query, PaymentTypeRefund, from, to, PaymentStatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []*Payment
+	for rows.Next() {
+		payment := &Payment{}
+		err := rows.Scan(
+			&payment.ID,
+			&payment.BookingID,
+			&payment.UserID,
+			&payment.Provider,
+			&payment.ProviderTransactionID,
+			&payment.ProviderOrderID,
+			&payment.PaymentType,
+			&payment.Status,
+			&payment.Amount,
+			&payment.Currency,
+			&payment.CardLastFour,
+			&payment.CardBrand,
+			&payment.ErrorCode,
+			&payment.ErrorMessage,
+			&payment.ProviderResponse,
+			&payment.AuthorizedAt,
+			&payment.CapturedAt,
+			&payment.FailedAt,
+			&payment.RefundedAt,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// CountCashJobsByCleaner counts a cleaner's bookings scheduled in [from, to) that are paid, or due to be paid, in cash.
+// excludeBookingID is left out of the count.
+func (r *PaymentRepository) CountCashJobsByCleaner(cleanerID string, from, to time.Time, excludeBookingID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT b.id)
+		FROM bookings b
+		JOIN payments p ON p.booking_id = b.id
+		WHERE b.cleaner_id = $1
+		  AND p.provider = $2 AND p.payment_type = $3 AND p.status IN ($4, $5)
+		  AND b.scheduled_date >= $6 AND b.scheduled_date < $7
+		  AND b.id <> $8
+	`, cleanerID, PaymentProviderCash, PaymentTypePreauthorization, PaymentStatusAuthorized, PaymentStatusCaptured,
+		from, to, excludeBookingID).Scan(&count)
+	return count, err
+}
+
+// GetCashCollectedByBookingIDs returns the cash cleaners confirmed receiving, per booking
+func (r *PaymentRepository) GetCashCollectedByBookingIDs(bookingIDs []string) (map[string]float64, error) {
+	collected := make(map[string]float64)
+	if len(bookingIDs) == 0 {
+		return collected, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT booking_id, SUM(amount)
+		FROM payments
+		WHERE booking_id = ANY($1) AND provider = $2 AND payment_type = $3 AND status = $4
+		GROUP BY booking_id
+	`, pq.Array(bookingIDs), PaymentProviderCash, PaymentTypePreauthorization, PaymentStatusCaptured)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookingID string
+		var amount float64
+		if err := rows.Scan(&bookingID, &amount); err != nil {
+			return nil, err
+		}
+		collected[bookingID] = amount
+	}
+	return collected, rows.Err()
+}
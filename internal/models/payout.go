@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ const (
 	PayoutStatusSent       = "SENT"
 	PayoutStatusFailed     = "FAILED"
 	PayoutStatusCancelled  = "CANCELLED"
+	PayoutStatusInvoiced   = "INVOICED" // The cleaner owes the platform (cash fees exceeded earnings)
 )
 
 type Payout struct {
@@ -30,14 +32,30 @@ type Payout struct {
 	SettlementInvoiceURL  sql.NullString
 	PaidAt                sql.NullTime
 	FailedReason          sql.NullString
+	BatchID               sql.NullString // Bulk payment file the payout was exported in
+	CompanyPayoutID       sql.NullString // Set when the earnings are paid to the cleaner's company
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
 }
 
+// PayoutLineItemType tells card-paid bookings, cash bookings, tips and adjustments apart
+type PayoutLineItemType string
+
+const (
+	PayoutLineItemTypeBooking      PayoutLineItemType = "BOOKING"
+	PayoutLineItemTypeTip          PayoutLineItemType = "TIP"           // Paid in full, no platform fee
+	PayoutLineItemTypeCash         PayoutLineItemType = "CASH_BOOKING"  // Collected in cash; only the fee is settled
+	PayoutLineItemTypeAdjustment   PayoutLineItemType = "ADJUSTMENT"    // Clawback, bonus or correction
+	PayoutLineItemTypeCarryForward PayoutLineItemType = "CARRY_FORWARD" // Negative balance moved to the next payout
+)
+
 type PayoutLineItem struct {
 	ID              string
 	PayoutID        string
-	BookingID       string
+	BookingID       string // Empty for adjustments not tied to a booking
+	AdjustmentID    sql.NullString
+	Description     sql.NullString
+	ItemType        PayoutLineItemType
 	BookingDate     time.Time
 	ServiceType     string
 	BookingAmount   float64
@@ -56,6 +74,91 @@ func NewPayoutRepository(db *sql.DB) *PayoutRepository {
 }
 
 func (r *PayoutRepository) Create(payout *Payout) error {
+	return insertPayout(r.db, payout)
+}
+
+// PayoutDraft is a payout with its line items, as built by payout generation.
+// CarryForward, if set, moves a negative balance to the next payout; it is stored with the payout
+// and linked to the draft's CARRY_FORWARD line item. CompanyPayout, if set, is shared by the drafts
+// of a company's cleaners and stored with the first of them.
+type PayoutDraft struct {
+	Payout        *Payout
+	LineItems     []*PayoutLineItem
+	CarryForward  *PayoutAdjustment
+	CompanyPayout *CompanyPayout
+}
+
+// CreateWithLineItems stores payouts and their line items in a single transaction:
+// either every payout is created or none is
+func (r *PayoutRepository) CreateWithLineItems(drafts []*PayoutDraft) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, draft := range drafts {
+		if company := draft.CompanyPayout; company != nil {
+			if company.ID == "" {
+				if err := insertCompanyPayout(tx, company); err != nil {
+					return fmt.Errorf("failed to create payout for company %s: %w", company.CompanyID, err)
+				}
+			}
+			draft.Payout.CompanyPayoutID = sql.NullString{String: company.ID, Valid: true}
+		}
+		if err := insertPayout(tx, draft.Payout); err != nil {
+			return fmt.Errorf("failed to create payout for cleaner %s: %w", draft.Payout.CleanerID, err)
+		}
+		if carry := draft.CarryForward; carry != nil {
+			carry.CarriedFromPayoutID = sql.NullString{String: draft.Payout.ID, Valid: true}
+			if err := insertPayoutAdjustment(tx, carry); err != nil {
+				return fmt.Errorf("failed to carry forward balance for cleaner %s: %w", carry.CleanerID, err)
+			}
+		}
+		for _, item := range draft.LineItems {
+			item.PayoutID = draft.Payout.ID
+			if item.ItemType == PayoutLineItemTypeCarryForward && draft.CarryForward != nil {
+				item.AdjustmentID = sql.NullString{String: draft.CarryForward.ID, Valid: true}
+			}
+			if err := insertPayoutLineItem(tx, item); err != nil {
+				return fmt.Errorf("failed to create %s line item %s: %w", item.ItemType, item.BookingID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payouts: %w", err)
+	}
+	return nil
+}
+
+// GetCleanerIDsByPeriod returns the cleaners (users.id) that already have a payout for the period
+func (r *PayoutRepository) GetCleanerIDsByPeriod(periodStart, periodEnd time.Time) (map[string]bool, error) {
+	rows, err := r.db.Query(`
+		SELECT cleaner_id FROM payouts WHERE period_start = $1 AND period_end = $2
+	`, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cleanerIDs := make(map[string]bool)
+	for rows.Next() {
+		var cleanerID string
+		if err := rows.Scan(&cleanerID); err != nil {
+			return nil, err
+		}
+		cleanerIDs[cleanerID] = true
+	}
+	return cleanerIDs, rows.Err()
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func insertPayout(q rowQuerier, payout *Payout) error {
 	if payout.ID == "" {
 		payout.ID = uuid.New().String()
 	}
@@ -64,13 +167,13 @@ func (r *PayoutRepository) Create(payout *Payout) error {
 		INSERT INTO payouts (
 			id, cleaner_id, period_start, period_end, status,
 			total_bookings, total_earnings, platform_fees, net_amount,
-			iban, transfer_reference, settlement_invoice_url,
+			iban, transfer_reference, settlement_invoice_url, company_payout_id,
 			created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
 		RETURNING created_at, updated_at
 	`
-	return r.db.QueryRow(
+	return q.QueryRow(
 		query,
 		payout.ID,
 		payout.CleanerID,
@@ -84,6 +187,7 @@ func (r *PayoutRepository) Create(payout *Payout) error {
 		payout.IBAN,
 		payout.TransferReference,
 		payout.SettlementInvoiceURL,
+		payout.CompanyPayoutID,
 	).Scan(&payout.CreatedAt, &payout.UpdatedAt)
 }
 
@@ -93,7 +197,7 @@ func (r *PayoutRepository) GetByID(id string) (*Payout, error) {
 		SELECT id, cleaner_id, period_start, period_end, status,
 			   total_bookings, total_earnings, platform_fees, net_amount,
 			   iban, transfer_reference, settlement_invoice_url,
-			   paid_at, failed_reason, created_at, updated_at
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
 		FROM payouts
 		WHERE id = $1
 	`
@@ -112,6 +216,8 @@ func (r *PayoutRepository) GetByID(id string) (*Payout, error) {
 		&payout.SettlementInvoiceURL,
 		&payout.PaidAt,
 		&payout.FailedReason,
+		&payout.BatchID,
+		&payout.CompanyPayoutID,
 		&payout.CreatedAt,
 		&payout.UpdatedAt,
 	)
@@ -129,7 +235,7 @@ func (r *PayoutRepository) GetByCleanerID(cleanerID string, limit, offset int) (
 		SELECT id, cleaner_id, period_start, period_end, status,
 			   total_bookings, total_earnings, platform_fees, net_amount,
 			   iban, transfer_reference, settlement_invoice_url,
-			   paid_at, failed_reason, created_at, updated_at
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
 		FROM payouts
 		WHERE cleaner_id = $1
 		ORDER BY period_start DESC
@@ -159,6 +265,8 @@ func (r *PayoutRepository) GetByCleanerID(cleanerID string, limit, offset int) (
 			&payout.SettlementInvoiceURL,
 			&payout.PaidAt,
 			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
 			&payout.CreatedAt,
 			&payout.UpdatedAt,
 		); err != nil {
@@ -174,7 +282,7 @@ func (r *PayoutRepository) GetByStatus(status string) ([]*Payout, error) {
 		SELECT id, cleaner_id, period_start, period_end, status,
 			   total_bookings, total_earnings, platform_fees, net_amount,
 			   iban, transfer_reference, settlement_invoice_url,
-			   paid_at, failed_reason, created_at, updated_at
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
 		FROM payouts
 		WHERE status = $1
 		ORDER BY created_at ASC
@@ -203,6 +311,8 @@ func (r *PayoutRepository) GetByStatus(status string) ([]*Payout, error) {
 			&payout.SettlementInvoiceURL,
 			&payout.PaidAt,
 			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
 			&payout.CreatedAt,
 			&payout.UpdatedAt,
 		); err != nil {
@@ -213,13 +323,155 @@ func (r *PayoutRepository) GetByStatus(status string) ([]*Payout, error) {
 	return payouts, nil
 }
 
+// GetByBatchID returns the payouts exported in a payment batch
+func (r *PayoutRepository) GetByBatchID(batchID string) ([]*Payout, error) {
+	query := `
+		SELECT id, cleaner_id, period_start, period_end, status,
+			   total_bookings, total_earnings, platform_fees, net_amount,
+			   iban, transfer_reference, settlement_invoice_url,
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
+		FROM payouts
+		WHERE batch_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*Payout
+	for rows.Next() {
+		payout := &Payout{}
+		if err := rows.Scan(
+			&payout.ID,
+			&payout.CleanerID,
+			&payout.PeriodStart,
+			&payout.PeriodEnd,
+			&payout.Status,
+			&payout.TotalBookings,
+			&payout.TotalEarnings,
+			&payout.PlatformFees,
+			&payout.NetAmount,
+			&payout.IBAN,
+			&payout.TransferReference,
+			&payout.SettlementInvoiceURL,
+			&payout.PaidAt,
+			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
+
+// GetByCompanyPayoutID returns the cleaner payouts paid through a company payout
+func (r *PayoutRepository) GetByCompanyPayoutID(companyPayoutID string) ([]*Payout, error) {
+	query := `
+		SELECT id, cleaner_id, period_start, period_end, status,
+			   total_bookings, total_earnings, platform_fees, net_amount,
+			   iban, transfer_reference, settlement_invoice_url,
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
+		FROM payouts
+		WHERE company_payout_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, companyPayoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*Payout
+	for rows.Next() {
+		payout := &Payout{}
+		if err := rows.Scan(
+			&payout.ID,
+			&payout.CleanerID,
+			&payout.PeriodStart,
+			&payout.PeriodEnd,
+			&payout.Status,
+			&payout.TotalBookings,
+			&payout.TotalEarnings,
+			&payout.PlatformFees,
+			&payout.NetAmount,
+			&payout.IBAN,
+			&payout.TransferReference,
+			&payout.SettlementInvoiceURL,
+			&payout.PaidAt,
+			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
+
+// GetWithoutCommissionInvoice returns the payouts of a period whose platform fees have not been
+// invoiced yet
+func (r *PayoutRepository) GetWithoutCommissionInvoice(periodStart time.Time) ([]*Payout, error) {
+	query := `
+		SELECT id, cleaner_id, period_start, period_end, status,
+			   total_bookings, total_earnings, platform_fees, net_amount,
+			   iban, transfer_reference, settlement_invoice_url,
+			   paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
+		FROM payouts
+		WHERE period_start = $1 AND commission_invoice_id IS NULL AND platform_fees > 0
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(query, periodStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*Payout
+	for rows.Next() {
+		payout := &Payout{}
+		if err := rows.Scan(
+			&payout.ID,
+			&payout.CleanerID,
+			&payout.PeriodStart,
+			&payout.PeriodEnd,
+			&payout.Status,
+			&payout.TotalBookings,
+			&payout.TotalEarnings,
+			&payout.PlatformFees,
+			&payout.NetAmount,
+			&payout.IBAN,
+			&payout.TransferReference,
+			&payout.SettlementInvoiceURL,
+			&payout.PaidAt,
+			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
+
 // GetAll returns all payouts with pagination
 func (r *PayoutRepository) GetAll(limit, offset int) ([]*Payout, error) {
 	query := `
 		SELECT id, cleaner_id, period_start, period_end, status,
 			total_bookings, total_earnings, platform_fees, net_amount,
 			iban, transfer_reference, settlement_invoice_url,
-			paid_at, failed_reason, created_at, updated_at
+			paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
 		FROM payouts
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -249,6 +501,8 @@ func (r *PayoutRepository) GetAll(limit, offset int) ([]*Payout, error) {
 			&payout.SettlementInvoiceURL,
 			&payout.PaidAt,
 			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
 			&payout.CreatedAt,
 			&payout.UpdatedAt,
 		); err != nil {
@@ -259,6 +513,65 @@ func (r *PayoutRepository) GetAll(limit, offset int) ([]*Payout, error) {
 	return payouts, nil
 }
 
+// GetPaidBetween returns the payouts transferred to cleaners in [from, to), oldest first
+func (r *PayoutRepository) GetPaidBetween(from, to time.Time) ([]*Payout, error) {
+	query := `
+		SELECT id, cleaner_id, period_start, period_end, status,
+			total_bookings, total_earnings, platform_fees, net_amount,
+			iban, transfer_reference, settlement_invoice_url,
+			paid_at, failed_reason, batch_id, company_payout_id, created_at, updated_at
+		FROM payouts
+		WHERE status = $1 AND paid_at >= $2 AND paid_at < $3
+		ORDER BY paid_at ASC
+	`
+
+	rows, err := r.db.Query(query, PayoutStatusSent, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*Payout
+	for rows.Next() {
+		payout := &Payout{}
+		if err := rows.Scan(
+			&payout.ID,
+			&payout.CleanerID,
+			&payout.PeriodStart,
+			&payout.PeriodEnd,
+			&payout.Status,
+			&payout.TotalBookings,
+			&payout.TotalEarnings,
+			&payout.PlatformFees,
+			&payout.NetAmount,
+			&payout.IBAN,
+			&payout.TransferReference,
+			&payout.SettlementInvoiceURL,
+			&payout.PaidAt,
+			&payout.FailedReason,
+			&payout.BatchID,
+			&payout.CompanyPayoutID,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, rows.Err()
+}
+
+// GetUnsettledAmountByCleanerID sums net amounts of payouts generated but not yet sent to (or paid by) a cleaner
+func (r *PayoutRepository) GetUnsettledAmountByCleanerID(cleanerID string) (float64, error) {
+	var amount float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(net_amount), 0)
+		FROM payouts
+		WHERE cleaner_id = $1 AND status IN ($2, $3, $4)
+	`, cleanerID, PayoutStatusPending, PayoutStatusProcessing, PayoutStatusInvoiced).Scan(&amount)
+	return amount, err
+}
+
 func (r *PayoutRepository) Update(payout *Payout) error {
 	query := `
 		UPDATE payouts
@@ -290,6 +603,10 @@ func NewPayoutLineItemRepository(db *sql.DB) *PayoutLineItemRepository {
 }
 
 func (r *PayoutLineItemRepository) Create(item *PayoutLineItem) error {
+	return insertPayoutLineItem(r.db, item)
+}
+
+func insertPayoutLineItem(q rowQuerier, item *PayoutLineItem) error {
 	if item.ID == "" {
 		item.ID = uuid.New().String()
 	}
@@ -298,12 +615,15 @@ func (r *PayoutLineItemRepository) Create(item *PayoutLineItem) error {
 		INSERT INTO payout_line_items (
 			id, payout_id, booking_id, booking_date, service_type,
 			booking_amount, platform_fee_rate, platform_fee, cleaner_earnings,
-			created_at
+			item_type, adjustment_id, description, created_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
 		RETURNING created_at
 	`
-	return r.db.QueryRow(
+	if item.ItemType == "" {
+		item.ItemType = PayoutLineItemTypeBooking
+	}
+	return q.QueryRow(
 		query,
 		item.ID,
 		item.PayoutID,
@@ -314,14 +634,17 @@ func (r *PayoutLineItemRepository) Create(item *PayoutLineItem) error {
 		item.PlatformFeeRate,
 		item.PlatformFee,
 		item.CleanerEarnings,
+		item.ItemType,
+		item.AdjustmentID,
+		item.Description,
 	).Scan(&item.CreatedAt)
 }
 
 func (r *PayoutLineItemRepository) GetByPayoutID(payoutID string) ([]*PayoutLineItem, error) {
 	query := `
-		SELECT id, payout_id, booking_id, booking_date, service_type,
+		SELECT id, payout_id, COALESCE(booking_id, ''), booking_date, service_type,
 			   booking_amount, platform_fee_rate, platform_fee, cleaner_earnings,
-			   created_at
+			   item_type, adjustment_id, description, created_at
 		FROM payout_line_items
 		WHERE payout_id = $1
 		ORDER BY booking_date ASC
@@ -345,6 +668,9 @@ func (r *PayoutLineItemRepository) GetByPayoutID(payoutID string) ([]*PayoutLine
 			&item.PlatformFeeRate,
 			&item.PlatformFee,
 			&item.CleanerEarnings,
+			&item.ItemType,
+			&item.AdjustmentID,
+			&item.Description,
 			&item.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayoutAdjustmentType says why a cleaner's earnings were changed outside bookings and tips
+type PayoutAdjustmentType string
+
+const (
+	PayoutAdjustmentTypeClawback     PayoutAdjustmentType = "CLAWBACK"      // Taken back, e.g. after a dispute refund
+	PayoutAdjustmentTypeBonus        PayoutAdjustmentType = "BONUS"         // Extra pay granted by an admin
+	PayoutAdjustmentTypeCorrection   PayoutAdjustmentType = "CORRECTION"    // Manual fix in either direction
+	PayoutAdjustmentTypeCarryForward PayoutAdjustmentType = "CARRY_FORWARD" // Negative balance left over from a payout
+)
+
+// PayoutAdjustment is a signed change to a cleaner's earnings, paid with the next payout run
+type PayoutAdjustment struct {
+	ID                  string
+	CleanerID           string // cleaners.id
+	Type                PayoutAdjustmentType
+	Amount              float64 // Positive is owed to the cleaner, negative is taken back
+	Reason              string
+	BookingID           sql.NullString
+	DisputeID           sql.NullString
+	CarriedFromPayoutID sql.NullString
+	CreatedBy           sql.NullString // NULL for system adjustments
+	PayoutID            sql.NullString // Payout the adjustment was paid with, if any (read only)
+	CreatedAt           time.Time
+}
+
+// PayoutAdjustmentRepository handles payout adjustment database operations
+type PayoutAdjustmentRepository struct {
+	db *sql.DB
+}
+
+// NewPayoutAdjustmentRepository creates a new payout adjustment repository
+func NewPayoutAdjustmentRepository(db *sql.DB) *PayoutAdjustmentRepository {
+	return &PayoutAdjustmentRepository{db: db}
+}
+
+// Create stores an adjustment
+func (r *PayoutAdjustmentRepository) Create(adjustment *PayoutAdjustment) error {
+	return insertPayoutAdjustment(r.db, adjustment)
+}
+
+func insertPayoutAdjustment(q rowQuerier, adjustment *PayoutAdjustment) error {
+	if adjustment.ID == "" {
+		adjustment.ID = uuid.New().String()
+	}
+
+	return q.QueryRow(`
+		INSERT INTO payout_adjustments (
+			id, cleaner_id, adjustment_type, amount, reason,
+			booking_id, dispute_id, carried_from_payout_id, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`, adjustment.ID, adjustment.CleanerID, adjustment.Type, adjustment.Amount, adjustment.Reason,
+		adjustment.BookingID, adjustment.DisputeID, adjustment.CarriedFromPayoutID, adjustment.CreatedBy,
+	).Scan(&adjustment.CreatedAt)
+}
+
+const payoutAdjustmentSelect = `
+	SELECT a.id, a.cleaner_id, a.adjustment_type, a.amount, a.reason,
+	       a.booking_id, a.dispute_id, a.carried_from_payout_id, a.created_by,
+	       li.payout_id, a.created_at
+	FROM payout_adjustments a
+	LEFT JOIN payout_line_items li ON li.adjustment_id = a.id AND li.item_type = 'ADJUSTMENT'
+`
+
+// GetByID returns an adjustment
+func (r *PayoutAdjustmentRepository) GetByID(id string) (*PayoutAdjustment, error) {
+	adjustments, err := r.query(payoutAdjustmentSelect+` WHERE a.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(adjustments) == 0 {
+		return nil, nil
+	}
+	return adjustments[0], nil
+}
+
+// GetByDisputeID returns the clawback made for a dispute, if any
+func (r *PayoutAdjustmentRepository) GetByDisputeID(disputeID string) (*PayoutAdjustment, error) {
+	adjustments, err := r.query(payoutAdjustmentSelect+` WHERE a.dispute_id = $1 AND a.adjustment_type = $2`,
+		disputeID, PayoutAdjustmentTypeClawback)
+	if err != nil {
+		return nil, err
+	}
+	if len(adjustments) == 0 {
+		return nil, nil
+	}
+	return adjustments[0], nil
+}
+
+// GetByCleanerID returns a cleaner's adjustments, newest first
+func (r *PayoutAdjustmentRepository) GetByCleanerID(cleanerID string, limit, offset int) ([]*PayoutAdjustment, error) {
+	return r.query(payoutAdjustmentSelect+`
+		WHERE a.cleaner_id = $1
+		ORDER BY a.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, cleanerID, limit, offset)
+}
+
+// GetUnpaid returns adjustments made up to asOf that are not on a payout yet, oldest first
+func (r *PayoutAdjustmentRepository) GetUnpaid(asOf time.Time) ([]*PayoutAdjustment, error) {
+	return r.query(payoutAdjustmentSelect+`
+		WHERE a.created_at <= $1 AND li.id IS NULL
+		ORDER BY a.created_at ASC
+	`, asOf)
+}
+
+func (r *PayoutAdjustmentRepository) query(query string, args ...interface{}) ([]*PayoutAdjustment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []*PayoutAdjustment
+	for rows.Next() {
+		adjustment := &PayoutAdjustment{}
+		if err := rows.Scan(
+			&adjustment.ID, &adjustment.CleanerID, &adjustment.Type, &adjustment.Amount, &adjustment.Reason,
+			&adjustment.BookingID, &adjustment.DisputeID, &adjustment.CarriedFromPayoutID, &adjustment.CreatedBy,
+			&adjustment.PayoutID, &adjustment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	return adjustments, rows.Err()
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	PayoutBatchStatusProcessing = "PROCESSING" // File exported, waiting for the bank
+	PayoutBatchStatusSent       = "SENT"       // Every payout in the batch was sent
+)
+
+// PayoutBatch is a bulk payment file exported for a set of PENDING payouts
+type PayoutBatch struct {
+	ID                string
+	Reference         string // Message ID of the payment file
+	Format            string // utils.PaymentFileFormat*
+	Status            string
+	PayoutCount       int
+	TotalAmount       float64
+	FileName          string
+	FileContent       string
+	TransferReference sql.NullString
+	CreatedBy         sql.NullString
+	SentAt            sql.NullTime
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// PayoutBatchRepository handles payout batch database operations
+type PayoutBatchRepository struct {
+	db *sql.DB
+}
+
+// NewPayoutBatchRepository creates a new payout batch repository
+func NewPayoutBatchRepository(db *sql.DB) *PayoutBatchRepository {
+	return &PayoutBatchRepository{db: db}
+}
+
+// CreateWithPayouts stores a batch and moves its payouts from PENDING to PROCESSING in one
+// transaction. It fails if any payout was changed or exported by someone else meanwhile.
+func (r *PayoutBatchRepository) CreateWithPayouts(batch *PayoutBatch, payoutIDs []string) error {
+	if batch.ID == "" {
+		batch.ID = uuid.New().String()
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO payout_batches (
+			id, reference, file_format, status, payout_count, total_amount,
+			file_name, file_content, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at
+	`, batch.ID, batch.Reference, batch.Format, batch.Status, batch.PayoutCount, batch.TotalAmount,
+		batch.FileName, batch.FileContent, batch.CreatedBy,
+	).Scan(&batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create payout batch: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		UPDATE payouts
+		SET status = $2, batch_id = $3, updated_at = NOW()
+		WHERE id = ANY($1) AND status = $4 AND batch_id IS NULL
+	`, pq.Array(payoutIDs), PayoutStatusProcessing, batch.ID, PayoutStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to move payouts to processing: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(affected) != len(payoutIDs) {
+		return fmt.Errorf("%d of %d payouts are no longer pending", len(payoutIDs)-int(affected), len(payoutIDs))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit payout batch: %w", err)
+	}
+	return nil
+}
+
+const payoutBatchSelect = `
+	SELECT id, reference, file_format, status, payout_count, total_amount,
+	       file_name, file_content, transfer_reference, created_by, sent_at,
+	       created_at, updated_at
+	FROM payout_batches
+`
+
+// GetByID returns a payout batch
+func (r *PayoutBatchRepository) GetByID(id string) (*PayoutBatch, error) {
+	batches, err := r.query(payoutBatchSelect+` WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+	return batches[0], nil
+}
+
+// GetAll returns payout batches, newest first
+func (r *PayoutBatchRepository) GetAll(limit, offset int) ([]*PayoutBatch, error) {
+	return r.query(payoutBatchSelect+` ORDER BY created_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+}
+
+// MarkSent records the bank's transfer reference for the whole batch
+func (r *PayoutBatchRepository) MarkSent(batch *PayoutBatch, transferReference string) error {
+	return r.db.QueryRow(`
+		UPDATE payout_batches
+		SET status = $2, transfer_reference = $3, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, transfer_reference, sent_at, updated_at
+	`, batch.ID, PayoutBatchStatusSent, transferReference,
+	).Scan(&batch.Status, &batch.TransferReference, &batch.SentAt, &batch.UpdatedAt)
+}
+
+func (r *PayoutBatchRepository) query(query string, args ...interface{}) ([]*PayoutBatch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []*PayoutBatch
+	for rows.Next() {
+		batch := &PayoutBatch{}
+		if err := rows.Scan(
+			&batch.ID, &batch.Reference, &batch.Format, &batch.Status, &batch.PayoutCount, &batch.TotalAmount,
+			&batch.FileName, &batch.FileContent, &batch.TransferReference, &batch.CreatedBy, &batch.SentAt,
+			&batch.CreatedAt, &batch.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, rows.Err()
+}
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ProformaStatus is the payment state of a proforma
+type ProformaStatus string
+
+const (
+	ProformaStatusIssued    ProformaStatus = "ISSUED"    // Waiting for the bank transfer
+	ProformaStatusPaid      ProformaStatus = "PAID"      // Transfer received
+	ProformaStatusCancelled ProformaStatus = "CANCELLED" // Booking cancelled or paid another way
+)
+
+// Proforma is a payment request sent to a company before its booking, to be paid by bank
+// transfer. It is not a fiscal document: the booking is invoiced when completed and the
+// invoice is linked back to the proforma.
+type Proforma struct {
+	ID               string
+	ProformaNumber   string
+	BookingID        string
+	PaymentID        string // BANK_TRANSFER payment settled by the transfer
+	BillingProfileID sql.NullString
+	IssueDate        time.Time
+	DueDate          time.Time
+	ClientName       string
+	ClientEmail      sql.NullString
+	Subtotal         float64
+	TaxAmount        float64
+	TotalAmount      float64
+	AmountDue        float64 // Total less wallet credit applied to the booking
+	Currency         string
+	Status           ProformaStatus
+	PdfURL           sql.NullString
+	PaidAt           sql.NullTime
+	PaymentReference sql.NullString
+	InvoiceID        sql.NullString
+	ConvertedAt      sql.NullTime
+	ReminderCount    int
+	LastReminderAt   sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// IsOverdue reports whether an issued proforma is past its due date
+func (p *Proforma) IsOverdue(now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return p.Status == ProformaStatusIssued && p.DueDate.Before(today)
+}
+
+// ProformaRepository handles proforma database operations
+type ProformaRepository struct {
+	db *sql.DB
+}
+
+// NewProformaRepository creates a new proforma repository
+func NewProformaRepository(db *sql.DB) *ProformaRepository {
+	return &ProformaRepository{db: db}
+}
+
+// Create numbers a proforma from the proforma series and stores it
+func (r *ProformaRepository) Create(proforma *Proforma) error {
+	if proforma.Status == "" {
+		proforma.Status = ProformaStatusIssued
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	proforma.ProformaNumber, err = allocateDocumentNumber(tx, DocumentTypeProforma, proforma.IssueDate)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO proformas (
+			proforma_number, booking_id, payment_id, billing_profile_id, issue_date, due_date,
+			client_name, client_email, subtotal, tax_amount, total_amount, amount_due, currency, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at, updated_at
+	`, proforma.ProformaNumber, proforma.BookingID, proforma.PaymentID, proforma.BillingProfileID, proforma.IssueDate, proforma.DueDate,
+		proforma.ClientName, proforma.ClientEmail, proforma.Subtotal, proforma.TaxAmount, proforma.TotalAmount, proforma.AmountDue,
+		proforma.Currency, proforma.Status,
+	).Scan(&proforma.ID, &proforma.CreatedAt, &proforma.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create proforma: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit proforma: %w", err)
+	}
+	return nil
+}
+
+// UpdatePdfURL stores the path of the generated PDF
+func (r *ProformaRepository) UpdatePdfURL(id string, pdfURL string) error {
+	_, err := r.db.Exec(`UPDATE proformas SET pdf_url = $2, updated_at = NOW() WHERE id = $1`, id, pdfURL)
+	return err
+}
+
+// MarkPaid records that the transfer for an issued proforma arrived
+func (r *ProformaRepository) MarkPaid(id, reference string) error {
+	result, err := r.db.Exec(`
+		UPDATE proformas
+		SET status = $2, paid_at = NOW(), payment_reference = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`, id, ProformaStatusPaid, sql.NullString{String: reference, Valid: reference != ""}, ProformaStatusIssued)
+	if err != nil {
+		return fmt.Errorf("failed to mark proforma as paid: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("proforma is not awaiting payment")
+	}
+	return nil
+}
+
+// MarkConverted links a proforma to the invoice issued for its booking
+func (r *ProformaRepository) MarkConverted(id, invoiceID string) error {
+	_, err := r.db.Exec(`
+		UPDATE proformas SET invoice_id = $2, converted_at = NOW(), updated_at = NOW() WHERE id = $1
+	`, id, invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to link proforma to invoice: %w", err)
+	}
+	return nil
+}
+
+// Cancel closes an issued proforma that will no longer be paid
+func (r *ProformaRepository) Cancel(id string) error {
+	_, err := r.db.Exec(`
+		UPDATE proformas SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3
+	`, id, ProformaStatusCancelled, ProformaStatusIssued)
+	if err != nil {
+		return fmt.Errorf("failed to cancel proforma: %w", err)
+	}
+	return nil
+}
+
+// CancelForClosedPayments cancels issued proformas whose payment was cancelled or failed,
+// such as when the booking was cancelled, and returns how many were closed
+func (r *ProformaRepository) CancelForClosedPayments() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE proformas p
+		SET status = $1, updated_at = NOW()
+		FROM payments pay
+		WHERE pay.id = p.payment_id AND p.status = $2 AND pay.status IN ($3, $4)
+	`, ProformaStatusCancelled, ProformaStatusIssued, PaymentStatusCancelled, PaymentStatusFailed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel proformas: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// RecordReminder counts a payment reminder sent for an overdue proforma
+func (r *ProformaRepository) RecordReminder(id string) error {
+	_, err := r.db.Exec(`
+		UPDATE proformas
+		SET reminder_count = reminder_count + 1, last_reminder_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+const proformaSelect = `
+	SELECT id, proforma_number, booking_id, payment_id, billing_profile_id, issue_date, due_date,
+	       client_name, client_email, subtotal, tax_amount, total_amount, amount_due, currency, status,
+	       pdf_url, paid_at, payment_reference, invoice_id, converted_at, reminder_count, last_reminder_at,
+	       created_at, updated_at
+	FROM proformas
+`
+
+// GetByID returns a proforma
+func (r *ProformaRepository) GetByID(id string) (*Proforma, error) {
+	proformas, err := r.query(proformaSelect+` WHERE id = $1`, id)
+	if err != nil || len(proformas) == 0 {
+		return nil, err
+	}
+	return proformas[0], nil
+}
+
+// GetByBookingID returns the open or paid proforma of a booking
+func (r *ProformaRepository) GetByBookingID(bookingID string) (*Proforma, error) {
+	proformas, err := r.query(proformaSelect+` WHERE booking_id = $1 AND status <> $2`, bookingID, ProformaStatusCancelled)
+	if err != nil || len(proformas) == 0 {
+		return nil, err
+	}
+	return proformas[0], nil
+}
+
+// GetByInvoiceID returns the proforma an invoice was converted from
+func (r *ProformaRepository) GetByInvoiceID(invoiceID string) (*Proforma, error) {
+	proformas, err := r.query(proformaSelect+` WHERE invoice_id = $1`, invoiceID)
+	if err != nil || len(proformas) == 0 {
+		return nil, err
+	}
+	return proformas[0], nil
+}
+
+// GetByStatus returns proformas newest first, all of them when status is empty
+func (r *ProformaRepository) GetByStatus(status ProformaStatus, limit, offset int) ([]*Proforma, error) {
+	return r.query(proformaSelect+`
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+}
+
+// GetUnpaid returns the issued proformas still waiting for their transfer
+func (r *ProformaRepository) GetUnpaid() ([]*Proforma, error) {
+	return r.query(proformaSelect+` WHERE status = $1 ORDER BY due_date ASC`, ProformaStatusIssued)
+}
+
+// GetOverdue returns issued proformas past their due date that are due another reminder:
+// fewer than maxReminders sent and none since remindedBefore
+func (r *ProformaRepository) GetOverdue(today, remindedBefore time.Time, maxReminders int) ([]*Proforma, error) {
+	return r.query(proformaSelect+`
+		WHERE status = $1 AND due_date < $2 AND reminder_count < $3
+		  AND (last_reminder_at IS NULL OR last_reminder_at < $4)
+		ORDER BY due_date ASC
+	`, ProformaStatusIssued, today, maxReminders, remindedBefore)
+}
+
+func (r *ProformaRepository) query(query string, args ...interface{}) ([]*Proforma, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proformas []*Proforma
+	for rows.Next() {
+		p := &Proforma{}
+		if err := rows.Scan(
+			&p.ID, &p.ProformaNumber, &p.BookingID, &p.PaymentID, &p.BillingProfileID, &p.IssueDate, &p.DueDate,
+			&p.ClientName, &p.ClientEmail, &p.Subtotal, &p.TaxAmount, &p.TotalAmount, &p.AmountDue, &p.Currency, &p.Status,
+			&p.PdfURL, &p.PaidAt, &p.PaymentReference, &p.InvoiceID, &p.ConvertedAt, &p.ReminderCount, &p.LastReminderAt,
+			&p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		proformas = append(proformas, p)
+	}
+	return proformas, rows.Err()
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CleanerSelfBilling holds a PFA cleaner's consent and legal details for self-billing
+type CleanerSelfBilling struct {
+	CleanerID          string
+	Enabled            bool
+	LegalName          string // e.g. "POPESCU MARIA PFA"
+	CUI                string
+	RegistrationNumber sql.NullString // Trade register number (F40/123/2020)
+	Address            string
+	City               string
+	County             string
+	VATPayer           bool
+	Series             string // Invoice series of the cleaner
+	LastNumber         int
+	AgreedAt           time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// SelfBilledInvoice is an invoice CleanBuddy issues to itself on behalf of a PFA cleaner for a payout
+type SelfBilledInvoice struct {
+	ID                         string
+	PayoutID                   string
+	CleanerID                  string // cleaners.id
+	InvoiceNumber              string
+	IssueDate                  time.Time
+	SupplierName               string
+	SupplierCUI                string
+	SupplierRegistrationNumber sql.NullString
+	SupplierAddress            string
+	SupplierVATPayer           bool
+	Subtotal                   float64
+	TaxAmount                  float64
+	TotalAmount                float64
+	Currency                   string
+	PdfURL                     sql.NullString
+	XmlURL                     sql.NullString
+	ANAFUploadIndex            sql.NullString
+	ANAFStatus                 ANAFStatus
+	ANAFSubmittedAt            sql.NullTime
+	ANAFProcessedAt            sql.NullTime
+	ANAFDownloadID             sql.NullString
+	ANAFErrors                 []ANAFError
+	ANAFRetryCount             int
+	CreatedAt                  time.Time
+	UpdatedAt                  time.Time
+}
+
+// SelfBillingRepository handles self-billing settings and invoices
+type SelfBillingRepository struct {
+	db *sql.DB
+}
+
+// NewSelfBillingRepository creates a new self-billing repository
+func NewSelfBillingRepository(db *sql.DB) *SelfBillingRepository {
+	return &SelfBillingRepository{db: db}
+}
+
+// GetSettings returns a cleaner's self-billing settings, or nil if they never opted in
+func (r *SelfBillingRepository) GetSettings(cleanerID string) (*CleanerSelfBilling, error) {
+	s := &CleanerSelfBilling{}
+	err := r.db.QueryRow(`
+		SELECT cleaner_id, enabled, legal_name, cui, registration_number,
+		       address, city, county, vat_payer, series, last_number,
+		       agreed_at, created_at, updated_at
+		FROM cleaner_self_billing
+		WHERE cleaner_id = $1
+	`, cleanerID).Scan(
+		&s.CleanerID, &s.Enabled, &s.LegalName, &s.CUI, &s.RegistrationNumber,
+		&s.Address, &s.City, &s.County, &s.VATPayer, &s.Series, &s.LastNumber,
+		&s.AgreedAt, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveSettings creates or updates a cleaner's settings. A new cleaner gets the next series from
+// self_billing_series_seq; the series and its counter are kept once assigned, so numbering
+// continues after the cleaner opts out and back in.
+func (r *SelfBillingRepository) SaveSettings(s *CleanerSelfBilling) error {
+	return r.db.QueryRow(`
+		INSERT INTO cleaner_self_billing (
+			cleaner_id, enabled, legal_name, cui, registration_number,
+			address, city, county, vat_payer, series, agreed_at
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9,
+			COALESCE(
+				(SELECT series FROM cleaner_self_billing WHERE cleaner_id = $1),
+				'AFX' || LPAD(nextval('self_billing_series_seq')::text, 5, '0')
+			),
+			$10
+		)
+		ON CONFLICT (cleaner_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled, legal_name = EXCLUDED.legal_name, cui = EXCLUDED.cui,
+		    registration_number = EXCLUDED.registration_number, address = EXCLUDED.address,
+		    city = EXCLUDED.city, county = EXCLUDED.county, vat_payer = EXCLUDED.vat_payer,
+		    agreed_at = EXCLUDED.agreed_at, updated_at = NOW()
+		RETURNING series, last_number, created_at, updated_at
+	`, s.CleanerID, s.Enabled, s.LegalName, s.CUI, s.RegistrationNumber,
+		s.Address, s.City, s.County, s.VATPayer, s.AgreedAt,
+	).Scan(&s.Series, &s.LastNumber, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// SetEnabled turns self-billing on or off for a cleaner
+func (r *SelfBillingRepository) SetEnabled(cleanerID string, enabled bool) error {
+	_, err := r.db.Exec(`
+		UPDATE cleaner_self_billing SET enabled = $2, updated_at = NOW() WHERE cleaner_id = $1
+	`, cleanerID, enabled)
+	return err
+}
+
+// CreateInvoice takes the next number in the cleaner's series (SERIES-NNNN) and stores the
+// invoice in the same transaction, so a failed insert leaves no gap in the series
+func (r *SelfBillingRepository) CreateInvoice(invoice *SelfBilledInvoice) error {
+	if invoice.ANAFStatus == "" {
+		invoice.ANAFStatus = ANAFStatusPending
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var series string
+	var number int
+	err = tx.QueryRow(`
+		UPDATE cleaner_self_billing
+		SET last_number = last_number + 1, updated_at = NOW()
+		WHERE cleaner_id = $1
+		RETURNING series, last_number
+	`, invoice.CleanerID).Scan(&series, &number)
+	if err != nil {
+		return fmt.Errorf("failed to allocate self-billing invoice number: %w", err)
+	}
+	invoice.InvoiceNumber = fmt.Sprintf("%s-%04d", series, number)
+
+	err = tx.QueryRow(`
+		INSERT INTO self_billed_invoices (
+			payout_id, cleaner_id, invoice_number, issue_date,
+			supplier_name, supplier_cui, supplier_registration_number, supplier_address, supplier_vat_payer,
+			subtotal, tax_amount, total_amount, currency, anaf_status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at, updated_at
+	`, invoice.PayoutID, invoice.CleanerID, invoice.InvoiceNumber, invoice.IssueDate,
+		invoice.SupplierName, invoice.SupplierCUI, invoice.SupplierRegistrationNumber, invoice.SupplierAddress, invoice.SupplierVATPayer,
+		invoice.Subtotal, invoice.TaxAmount, invoice.TotalAmount, invoice.Currency, invoice.ANAFStatus,
+	).Scan(&invoice.ID, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create self-billed invoice: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// UpdateFiles stores the paths of the generated PDF and XML
+func (r *SelfBillingRepository) UpdateFiles(invoice *SelfBilledInvoice) error {
+	_, err := r.db.Exec(`
+		UPDATE self_billed_invoices SET pdf_url = $2, xml_url = $3, updated_at = NOW() WHERE id = $1
+	`, invoice.ID, invoice.PdfURL, invoice.XmlURL)
+	return err
+}
+
+// UpdateANAFStatus stores the outcome of an ANAF submission or status check. A failed upload
+// also records when it was attempted, for the retry backoff.
+func (r *SelfBillingRepository) UpdateANAFStatus(invoice *SelfBilledInvoice) error {
+	anafErrorsJSON, err := encodeANAFErrors(invoice.ANAFErrors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE self_billed_invoices
+		SET anaf_upload_index = $2, anaf_status = $3, anaf_submitted_at = $4, anaf_processed_at = $5,
+		    anaf_download_id = $6, anaf_errors = $7, anaf_retry_count = $8,
+		    anaf_last_retry_at = CASE WHEN $3 = 'failed' THEN NOW() ELSE anaf_last_retry_at END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, invoice.ID, invoice.ANAFUploadIndex, invoice.ANAFStatus, invoice.ANAFSubmittedAt, invoice.ANAFProcessedAt,
+		invoice.ANAFDownloadID, anafErrorsJSON, invoice.ANAFRetryCount)
+	return err
+}
+
+const selfBilledInvoiceSelect = `
+	SELECT id, payout_id, cleaner_id, invoice_number, issue_date,
+	       supplier_name, supplier_cui, supplier_registration_number, supplier_address, supplier_vat_payer,
+	       subtotal, tax_amount, total_amount, currency, pdf_url, xml_url,
+	       anaf_upload_index, anaf_status, anaf_submitted_at, anaf_processed_at,
+	       anaf_download_id, anaf_errors, anaf_retry_count, created_at, updated_at
+	FROM self_billed_invoices
+`
+
+// GetInvoiceByID returns a self-billed invoice
+func (r *SelfBillingRepository) GetInvoiceByID(id string) (*SelfBilledInvoice, error) {
+	invoices, err := r.queryInvoices(selfBilledInvoiceSelect+` WHERE id = $1`, id)
+	if err != nil || len(invoices) == 0 {
+		return nil, err
+	}
+	return invoices[0], nil
+}
+
+// GetInvoiceByPayoutID returns the self-billed invoice issued for a payout, if any
+func (r *SelfBillingRepository) GetInvoiceByPayoutID(payoutID string) (*SelfBilledInvoice, error) {
+	invoices, err := r.queryInvoices(selfBilledInvoiceSelect+` WHERE payout_id = $1`, payoutID)
+	if err != nil || len(invoices) == 0 {
+		return nil, err
+	}
+	return invoices[0], nil
+}
+
+// GetInvoicesByCleanerID returns a cleaner's self-billed invoices, newest first
+func (r *SelfBillingRepository) GetInvoicesByCleanerID(cleanerID string, limit, offset int) ([]*SelfBilledInvoice, error) {
+	return r.queryInvoices(selfBilledInvoiceSelect+`
+		WHERE cleaner_id = $1
+		ORDER BY issue_date DESC, invoice_number DESC
+		LIMIT $2 OFFSET $3
+	`, cleanerID, limit, offset)
+}
+
+// GetPendingANAFSubmission returns self-billed invoices due for (re)submission to ANAF, once the
+// submission delay or the backoff after their last failed attempt has passed
+func (r *SelfBillingRepository) GetPendingANAFSubmission(submissionDelay, retryDelay time.Duration, maxRetries, limit int) ([]*SelfBilledInvoice, error) {
+	return r.queryInvoices(selfBilledInvoiceSelect+`
+		WHERE `+anafSubmissionDue+`
+		ORDER BY created_at ASC
+		LIMIT $4
+	`, submissionDelay.Seconds(), retryDelay.Seconds(), maxRetries, limit)
+}
+
+func (r *SelfBillingRepository) queryInvoices(query string, args ...interface{}) ([]*SelfBilledInvoice, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []*SelfBilledInvoice
+	for rows.Next() {
+		invoice := &SelfBilledInvoice{}
+		var anafErrorsJSON sql.NullString
+		if err := rows.Scan(
+			&invoice.ID, &invoice.PayoutID, &invoice.CleanerID, &invoice.InvoiceNumber, &invoice.IssueDate,
+			&invoice.SupplierName, &invoice.SupplierCUI, &invoice.SupplierRegistrationNumber, &invoice.SupplierAddress, &invoice.SupplierVATPayer,
+			&invoice.Subtotal, &invoice.TaxAmount, &invoice.TotalAmount, &invoice.Currency, &invoice.PdfURL, &invoice.XmlURL,
+			&invoice.ANAFUploadIndex, &invoice.ANAFStatus, &invoice.ANAFSubmittedAt, &invoice.ANAFProcessedAt,
+			&invoice.ANAFDownloadID, &anafErrorsJSON, &invoice.ANAFRetryCount, &invoice.CreatedAt, &invoice.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		invoice.ANAFErrors = decodeANAFErrors(anafErrorsJSON)
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
+
+// encodeANAFErrors turns ANAF errors into the JSONB value stored with an invoice
+func encodeANAFErrors(anafErrors []ANAFError) (sql.NullString, error) {
+	if len(anafErrors) == 0 {
+		return sql.NullString{}, nil
+	}
+	errorsBytes, err := json.Marshal(anafErrors)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal ANAF errors: %w", err)
+	}
+	return sql.NullString{String: string(errorsBytes), Valid: true}, nil
+}
+
+// decodeANAFErrors reads ANAF errors stored as JSONB
+func decodeANAFErrors(value sql.NullString) []ANAFError {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	var anafErrors []ANAFError
+	if err := json.Unmarshal([]byte(value.String), &anafErrors); err != nil {
+		fmt.Printf("Warning: failed to unmarshal ANAF errors: %v\n", err)
+	}
+	return anafErrors
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Tip is a gratuity a client paid to the cleaner of a completed booking
+type Tip struct {
+	ID        string
+	BookingID string
+	ClientID  string
+	CleanerID string // cleaners.id
+	PaymentID string
+	Amount    float64
+	Currency  string
+	CreatedAt time.Time
+}
+
+// TipRepository handles tip database operations
+type TipRepository struct {
+	db *sql.DB
+}
+
+// NewTipRepository creates a new tip repository
+func NewTipRepository(db *sql.DB) *TipRepository {
+	return &TipRepository{db: db}
+}
+
+const tipColumns = `id, booking_id, client_id, cleaner_id, payment_id, amount, currency, created_at`
+
+// Create stores a paid tip
+func (r *TipRepository) Create(tip *Tip) error {
+	return r.db.QueryRow(`
+		INSERT INTO tips (booking_id, client_id, cleaner_id, payment_id, amount, currency)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, tip.BookingID, tip.ClientID, tip.CleanerID, tip.PaymentID, tip.Amount, tip.Currency).Scan(&tip.ID, &tip.CreatedAt)
+}
+
+// Delete removes a tip whose payment could not be captured
+func (r *TipRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM tips WHERE id = $1`, id)
+	return err
+}
+
+// GetByBookingID returns the tip left on a booking
+func (r *TipRepository) GetByBookingID(bookingID string) (*Tip, error) {
+	tip := &Tip{}
+	err := r.db.QueryRow(`SELECT `+tipColumns+` FROM tips WHERE booking_id = $1`, bookingID).Scan(
+		&tip.ID, &tip.BookingID, &tip.ClientID, &tip.CleanerID, &tip.PaymentID, &tip.Amount, &tip.Currency, &tip.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tip, nil
+}
+
+// GetUnpaidByPeriod returns tips paid within a date range that are not on a payout yet, oldest first
+func (r *TipRepository) GetUnpaidByPeriod(startDate, endDate time.Time) ([]*Tip, error) {
+	rows, err := r.db.Query(`
+		SELECT `+tipColumns+`
+		FROM tips t
+		WHERE created_at >= $1 AND created_at <= $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM payout_line_items li
+			WHERE li.booking_id = t.booking_id AND li.item_type = 'TIP'
+		  )
+		ORDER BY created_at ASC
+	`, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tips []*Tip
+	for rows.Next() {
+		tip := &Tip{}
+		if err := rows.Scan(
+			&tip.ID, &tip.BookingID, &tip.ClientID, &tip.CleanerID, &tip.PaymentID, &tip.Amount, &tip.Currency, &tip.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		tips = append(tips, tip)
+	}
+	return tips, rows.Err()
+}
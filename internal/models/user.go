@@ -112,6 +112,36 @@ func (r *UserRepository) GetByID(id string) (*User, error) {
 	return user, nil
 }
 
+// GetActiveByRole returns the active users with a role
+func (r *UserRepository) GetActiveByRole(role UserRole) ([]*User, error) {
+	rows, err := r.db.Query(`
+		SELECT id, phone, email, first_name, last_name, role, is_active,
+		       email_verified, phone_verified, created_at, updated_at
+		FROM users
+		WHERE role = $1 AND is_active = true
+		ORDER BY created_at
+	`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		user := &User{}
+		if err := rows.Scan(
+			&user.ID, &user.Phone, &user.Email, &user.FirstName, &user.LastName,
+			&user.Role, &user.IsActive, &user.EmailVerified, &user.PhoneVerified,
+			&user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(user *User) error {
 	return r.db.QueryRow(`
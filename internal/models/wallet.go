@@ -0,0 +1,404 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// WalletSource describes why a wallet balance changed
+type WalletSource string
+
+const (
+	WalletSourceReferral            WalletSource = "REFERRAL"
+	WalletSourceGoodwill            WalletSource = "GOODWILL"
+	WalletSourceDisputeRefund       WalletSource = "DISPUTE_REFUND"
+	WalletSourceGiftCard            WalletSource = "GIFT_CARD"
+	WalletSourceBookingPayment      WalletSource = "BOOKING_PAYMENT"      // Credit spent on a booking
+	WalletSourceBookingCancellation WalletSource = "BOOKING_CANCELLATION" // Credit returned from a cancelled booking
+	WalletSourceExpiry              WalletSource = "EXPIRY"
+)
+
+// WalletTransaction is a credit (positive amount) or debit (negative amount) on a client wallet
+type WalletTransaction struct {
+	ID              string
+	UserID          string
+	Amount          float64
+	Source          WalletSource
+	Reason          sql.NullString
+	RemainingAmount float64 // Unspent part of a credit lot
+	ExpiresAt       sql.NullTime
+	BookingID       sql.NullString
+	DisputeID       sql.NullString
+	CreatedBy       sql.NullString
+	CreatedAt       time.Time
+}
+
+// WalletRepository handles wallet database operations
+type WalletRepository struct {
+	db *sql.DB
+}
+
+// NewWalletRepository creates a new wallet repository
+func NewWalletRepository(db *sql.DB) *WalletRepository {
+	return &WalletRepository{db: db}
+}
+
+const walletTransactionColumns = `
+	id, user_id, amount, source, reason, remaining_amount, expires_at,
+	booking_id, dispute_id, created_by, created_at`
+
+// AddCredit stores a new credit lot; the whole amount is spendable until it expires
+func (r *WalletRepository) AddCredit(txn *WalletTransaction) error {
+	if txn.Amount <= 0 {
+		return fmt.Errorf("credit amount must be positive")
+	}
+	txn.RemainingAmount = txn.Amount
+
+	return r.db.QueryRow(`
+		INSERT INTO wallet_transactions (
+			user_id, amount, source, reason, remaining_amount, expires_at,
+			booking_id, dispute_id, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, txn.UserID, txn.Amount, txn.Source, txn.Reason, txn.RemainingAmount, txn.ExpiresAt,
+		txn.BookingID, txn.DisputeID, txn.CreatedBy).Scan(&txn.ID, &txn.CreatedAt)
+}
+
+// GetBalance returns the spendable (unexpired) credit of a user
+func (r *WalletRepository) GetBalance(userID string) (float64, error) {
+	var balance float64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(remaining_amount), 0)
+		FROM wallet_transactions
+		WHERE user_id = $1 AND remaining_amount > 0
+		  AND (expires_at IS NULL OR expires_at > NOW())
+	`, userID).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetTransactions returns a user's wallet history, newest first
+func (r *WalletRepository) GetTransactions(userID string, limit, offset int) ([]*WalletTransaction, error) {
+	rows, err := r.db.Query(`
+		SELECT `+walletTransactionColumns+`
+		FROM wallet_transactions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanWalletTransactions(rows)
+}
+
+// GetByBookingID returns the wallet transactions linked to a booking, oldest first
+func (r *WalletRepository) GetByBookingID(bookingID string) ([]*WalletTransaction, error) {
+	rows, err := r.db.Query(`
+		SELECT `+walletTransactionColumns+`
+		FROM wallet_transactions
+		WHERE booking_id = $1
+		ORDER BY created_at ASC
+	`, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanWalletTransactions(rows)
+}
+
+// ApplyToBooking spends up to maxAmount of a client's credit on a booking, consuming the lots
+// that expire first, and records the amount on bookings.credit_applied.
+// Returns the amount applied (0 if the wallet is empty or credit was already applied).
+func (r *WalletRepository) ApplyToBooking(userID, bookingID string, maxAmount float64) (float64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the booking so concurrent calls cannot apply credit twice
+	var alreadyApplied float64
+	err = tx.QueryRow(`SELECT credit_applied FROM bookings WHERE id = $1 FOR UPDATE`, bookingID).Scan(&alreadyApplied)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	if alreadyApplied > 0 {
+		return 0, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT id, remaining_amount
+		FROM wallet_transactions
+		WHERE user_id = $1 AND remaining_amount > 0
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY expires_at ASC NULLS LAST, created_at ASC
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock credit lots: %w", err)
+	}
+
+	type lot struct {
+		id        string
+		remaining float64
+	}
+	var lots []lot
+	for rows.Next() {
+		var l lot
+		if err := rows.Scan(&l.id, &l.remaining); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		lots = append(lots, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	applied := 0.0
+	for _, l := range lots {
+		if applied >= maxAmount {
+			break
+		}
+		use := math.Min(l.remaining, roundCents(maxAmount-applied))
+		if _, err := tx.Exec(`
+			UPDATE wallet_transactions SET remaining_amount = remaining_amount - $2 WHERE id = $1
+		`, l.id, use); err != nil {
+			return 0, fmt.Errorf("failed to consume credit lot: %w", err)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO wallet_credit_allocations (booking_id, lot_id, amount) VALUES ($1, $2, $3)
+		`, bookingID, l.id, use); err != nil {
+			return 0, fmt.Errorf("failed to record credit allocation: %w", err)
+		}
+		applied = roundCents(applied + use)
+	}
+
+	if applied <= 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO wallet_transactions (user_id, amount, source, reason, booking_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`, userID, -applied, WalletSourceBookingPayment, "Credit applied to booking", bookingID); err != nil {
+		return 0, fmt.Errorf("failed to record credit debit: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE bookings SET credit_applied = $2, updated_at = NOW() WHERE id = $1
+	`, bookingID, applied); err != nil {
+		return 0, fmt.Errorf("failed to update booking credit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit credit application: %w", err)
+	}
+	return applied, nil
+}
+
+// ReleaseBookingCredit returns credit spent on a cancelled booking to the wallet, as one lot per
+// lot it was spent from with that lot's expiry, so cancelling cannot extend promotional credit.
+// feePercentage of the credit is kept as a cancellation fee and stays on bookings.credit_applied.
+// Returns the amounts returned and kept; calling it again for the same booking returns nothing.
+func (r *WalletRepository) ReleaseBookingCredit(userID, bookingID string, feePercentage float64) (float64, float64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var applied float64
+	var alreadyReleased bool
+	err = tx.QueryRow(`
+		SELECT credit_applied,
+		       EXISTS (SELECT 1 FROM wallet_transactions WHERE booking_id = $1 AND source = $2)
+		FROM bookings WHERE id = $1 FOR UPDATE
+	`, bookingID, WalletSourceBookingCancellation).Scan(&applied, &alreadyReleased)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to lock booking: %w", err)
+	}
+	if applied <= 0 || alreadyReleased {
+		return 0, 0, nil
+	}
+
+	rows, err := tx.Query(`
+		SELECT a.lot_id, a.amount, w.expires_at
+		FROM wallet_credit_allocations a
+		JOIN wallet_transactions w ON w.id = a.lot_id
+		WHERE a.booking_id = $1
+		ORDER BY w.expires_at ASC NULLS LAST, w.created_at ASC
+	`, bookingID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get credit allocations: %w", err)
+	}
+	var allocations []WalletAllocation
+	for rows.Next() {
+		var a WalletAllocation
+		if err := rows.Scan(&a.LotID, &a.Amount, &a.ExpiresAt); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		allocations = append(allocations, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	// Credit applied before allocations were recorded comes back without an expiry
+	if len(allocations) == 0 {
+		allocations = []WalletAllocation{{Amount: applied}}
+	}
+
+	released, kept := SplitReleasedCredit(allocations, feePercentage)
+	returned := 0.0
+	for _, lot := range released {
+		if _, err := tx.Exec(`
+			INSERT INTO wallet_transactions (user_id, amount, source, reason, remaining_amount, expires_at, booking_id)
+			VALUES ($1, $2, $3, $4, $2, $5, $6)
+		`, userID, lot.Amount, WalletSourceBookingCancellation, "Credit returned from cancelled booking", lot.ExpiresAt, bookingID); err != nil {
+			return 0, 0, fmt.Errorf("failed to record credit return: %w", err)
+		}
+		returned = roundCents(returned + lot.Amount)
+	}
+	if returned <= 0 {
+		return 0, kept, tx.Commit()
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE bookings SET credit_applied = $2, updated_at = NOW() WHERE id = $1
+	`, bookingID, kept); err != nil {
+		return 0, 0, fmt.Errorf("failed to update booking credit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit credit return: %w", err)
+	}
+	return returned, kept, nil
+}
+
+// WalletAllocation is the part of a credit lot spent on a booking
+type WalletAllocation struct {
+	LotID     string
+	Amount    float64
+	ExpiresAt sql.NullTime
+}
+
+// SplitReleasedCredit keeps feePercentage of the credit spent on a booking, taken from the lots
+// that expire first, and returns what goes back to each lot with its expiry, and the amount kept
+func SplitReleasedCredit(allocations []WalletAllocation, feePercentage float64) ([]WalletAllocation, float64) {
+	total := 0.0
+	for _, a := range allocations {
+		total += a.Amount
+	}
+	kept := roundCents(total * feePercentage / 100)
+
+	var released []WalletAllocation
+	toKeep := kept
+	for _, a := range allocations {
+		keep := math.Min(toKeep, a.Amount)
+		toKeep = roundCents(toKeep - keep)
+		if amount := roundCents(a.Amount - keep); amount > 0 {
+			released = append(released, WalletAllocation{LotID: a.LotID, Amount: amount, ExpiresAt: a.ExpiresAt})
+		}
+	}
+	return released, kept
+}
+
+// ExpireLots zeroes credit lots past their expiry and records an EXPIRY debit for each.
+// Returns the expiry debits that were created.
+func (r *WalletRepository) ExpireLots() ([]*WalletTransaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		WITH expired AS (
+			SELECT id, user_id, remaining_amount
+			FROM wallet_transactions
+			WHERE remaining_amount > 0 AND expires_at IS NOT NULL AND expires_at <= NOW()
+			FOR UPDATE
+		)
+		UPDATE wallet_transactions w
+		SET remaining_amount = 0
+		FROM expired e
+		WHERE w.id = e.id
+		RETURNING e.id, e.user_id, e.remaining_amount
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire credit lots: %w", err)
+	}
+
+	type expired struct {
+		lotID  string
+		userID string
+		amount float64
+	}
+	var lots []expired
+	for rows.Next() {
+		var e expired
+		if err := rows.Scan(&e.lotID, &e.userID, &e.amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lots = append(lots, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var debits []*WalletTransaction
+	for _, lot := range lots {
+		debit := &WalletTransaction{
+			UserID: lot.userID,
+			Amount: -lot.amount,
+			Source: WalletSourceExpiry,
+			Reason: sql.NullString{String: fmt.Sprintf("Credit %s expired", lot.lotID), Valid: true},
+		}
+		if err := tx.QueryRow(`
+			INSERT INTO wallet_transactions (user_id, amount, source, reason)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`, debit.UserID, debit.Amount, debit.Source, debit.Reason).Scan(&debit.ID, &debit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to record credit expiry: %w", err)
+		}
+		debits = append(debits, debit)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit credit expiry: %w", err)
+	}
+	return debits, nil
+}
+
+func scanWalletTransactions(rows *sql.Rows) ([]*WalletTransaction, error) {
+	transactions := []*WalletTransaction{}
+	for rows.Next() {
+		txn := &WalletTransaction{}
+		if err := rows.Scan(
+			&txn.ID, &txn.UserID, &txn.Amount, &txn.Source, &txn.Reason, &txn.RemainingAmount, &txn.ExpiresAt,
+			&txn.BookingID, &txn.DisputeID, &txn.CreatedBy, &txn.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, txn)
+	}
+	return transactions, rows.Err()
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestSplitReleasedCredit(t *testing.T) {
+	promoExpiry := sql.NullTime{Time: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	giftExpiry := sql.NullTime{Time: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	allocations := []WalletAllocation{
+		{LotID: "promo", Amount: 20, ExpiresAt: promoExpiry},
+		{LotID: "gift", Amount: 50, ExpiresAt: giftExpiry},
+		{LotID: "goodwill", Amount: 30},
+	}
+
+	t.Run("full release keeps every lot's expiry", func(t *testing.T) {
+		released, kept := SplitReleasedCredit(allocations, 0)
+		if kept != 0 {
+			t.Errorf("kept = %.2f, want 0", kept)
+		}
+		if len(released) != 3 {
+			t.Fatalf("released %d lots, want 3", len(released))
+		}
+		for i, lot := range released {
+			if lot.LotID != allocations[i].LotID || lot.Amount != allocations[i].Amount || lot.ExpiresAt != allocations[i].ExpiresAt {
+				t.Errorf("lot %d = %+v, want %+v", i, lot, allocations[i])
+			}
+		}
+	})
+
+	t.Run("fee is kept from the lots expiring first", func(t *testing.T) {
+		released, kept := SplitReleasedCredit(allocations, 25)
+		if kept != 25 {
+			t.Errorf("kept = %.2f, want 25.00", kept)
+		}
+		// The promo lot is used up by the fee; 5 comes out of the gift lot
+		if len(released) != 2 {
+			t.Fatalf("released %d lots, want 2: %+v", len(released), released)
+		}
+		if released[0].LotID != "gift" || released[0].Amount != 45 || released[0].ExpiresAt != giftExpiry {
+			t.Errorf("first released lot = %+v, want 45.00 of gift with its expiry", released[0])
+		}
+		if released[1].LotID != "goodwill" || released[1].Amount != 30 || released[1].ExpiresAt.Valid {
+			t.Errorf("second released lot = %+v, want 30.00 of goodwill without expiry", released[1])
+		}
+	})
+
+	t.Run("full fee returns nothing", func(t *testing.T) {
+		released, kept := SplitReleasedCredit(allocations, 100)
+		if kept != 100 || len(released) != 0 {
+			t.Errorf("released %+v and kept %.2f, want nothing released and 100.00 kept", released, kept)
+		}
+	})
+
+	t.Run("amounts add up after rounding", func(t *testing.T) {
+		lots := []WalletAllocation{{LotID: "a", Amount: 10.01}, {LotID: "b", Amount: 10.01}, {LotID: "c", Amount: 10.01}}
+		released, kept := SplitReleasedCredit(lots, 33.3)
+		total := kept
+		for _, lot := range released {
+			total += lot.Amount
+		}
+		if roundCents(total) != 30.03 {
+			t.Errorf("released plus kept = %.2f, want 30.03", total)
+		}
+	})
+}
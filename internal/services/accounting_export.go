@@ -0,0 +1,654 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// AccountingExportFormat is the accounting software an export is made for
+type AccountingExportFormat string
+
+const (
+	AccountingExportSAGA      AccountingExportFormat = "SAGA"
+	AccountingExportWinMentor AccountingExportFormat = "WINMENTOR"
+	AccountingExportSAFT      AccountingExportFormat = "SAFT" // D406 declaration
+)
+
+// AccountingExportFile is one file of an accounting export
+type AccountingExportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// accountingPartner is a client or cleaner as the accounting exports identify them: by CUI
+// for companies and PFAs, by user or cleaner ID for individuals
+type accountingPartner struct {
+	ID                 string
+	Name               string
+	CUI                string // Without the RO prefix; empty for individuals
+	RegistrationNumber string
+	StreetAddress      string
+	City               string
+	County             string
+	PostalCode         string
+	Country            string
+	IBAN               string
+}
+
+// accountingPeriod holds the documents and money movements of an export period, [From, To)
+type accountingPeriod struct {
+	From        time.Time
+	To          time.Time
+	Invoices    []*models.Invoice
+	Lines       map[string][]*models.InvoiceLine // By invoice ID
+	CreditNotes []*models.CreditNote
+	CreditRates map[string]float64 // VAT rate of each credit note, by credit note ID
+	Payments    []*models.Payment
+	Payouts     []*models.Payout
+	Customers   map[string]*accountingPartner // By invoice, credit note and payment ID
+	Suppliers   map[string]*accountingPartner // By cleaner ID
+}
+
+// AccountingExportService exports a period's invoices, credit notes, payments and payouts for
+// the accountant: import files for SAGA and WinMentor, and the SAF-T (D406) declaration
+type AccountingExportService struct {
+	invoiceRepo     *models.InvoiceRepository
+	lineRepo        *models.InvoiceLineRepository
+	creditNoteRepo  *models.CreditNoteRepository
+	paymentRepo     *models.PaymentRepository
+	payoutRepo      *models.PayoutRepository
+	ledgerRepo      *models.LedgerRepository
+	bookingRepo     *models.BookingRepository
+	addressRepo     *models.AddressRepository
+	userRepo        *models.UserRepository
+	cleanerRepo     *models.CleanerRepository
+	selfBillingRepo *models.SelfBillingRepository
+	company         *config.CompanyConfig
+	cfg             *config.AccountingConfig
+}
+
+// NewAccountingExportService creates a new accounting export service
+func NewAccountingExportService(db *sql.DB, companyConfig *config.CompanyConfig, accountingConfig *config.AccountingConfig) *AccountingExportService {
+	return &AccountingExportService{
+		invoiceRepo:     models.NewInvoiceRepository(db),
+		lineRepo:        models.NewInvoiceLineRepository(db),
+		creditNoteRepo:  models.NewCreditNoteRepository(db),
+		paymentRepo:     models.NewPaymentRepository(db),
+		payoutRepo:      models.NewPayoutRepository(db),
+		ledgerRepo:      models.NewLedgerRepository(db),
+		bookingRepo:     models.NewBookingRepository(db),
+		addressRepo:     models.NewAddressRepository(db),
+		userRepo:        models.NewUserRepository(db),
+		cleanerRepo:     models.NewCleanerRepository(db),
+		selfBillingRepo: models.NewSelfBillingRepository(db),
+		company:         companyConfig,
+		cfg:             accountingConfig,
+	}
+}
+
+// Export builds the files importing the documents of [from, to) into the given accounting
+// software. SAGA gets its invoice XML, WinMentor its invoice TXT, and both a CSV each of
+// payments and payouts; SAF-T is a single D406 XML.
+func (s *AccountingExportService) Export(format AccountingExportFormat, from, to time.Time) ([]*AccountingExportFile, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("period start must be before period end")
+	}
+
+	period, err := s.collect(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := from.Format("20060102") + "_" + to.AddDate(0, 0, -1).Format("20060102")
+	var files []*AccountingExportFile
+	switch format {
+	case AccountingExportSAGA:
+		content, err := s.sagaInvoicesXML(period)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &AccountingExportFile{FileName: "saga_facturi_" + suffix + ".xml", ContentType: "application/xml", Content: content})
+	case AccountingExportWinMentor:
+		files = append(files, &AccountingExportFile{FileName: "winmentor_facturi_" + suffix + ".txt", ContentType: "text/plain", Content: s.winMentorInvoicesTXT(period)})
+	case AccountingExportSAFT:
+		content, err := s.saftXML(period)
+		if err != nil {
+			return nil, err
+		}
+		return []*AccountingExportFile{{FileName: "D406_" + suffix + ".xml", ContentType: "application/xml", Content: content}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	payments, err := s.paymentsCSV(period)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := s.payoutsCSV(period)
+	if err != nil {
+		return nil, err
+	}
+	return append(files,
+		&AccountingExportFile{FileName: "incasari_" + suffix + ".csv", ContentType: "text/csv", Content: payments},
+		&AccountingExportFile{FileName: "plati_" + suffix + ".csv", ContentType: "text/csv", Content: payouts},
+	), nil
+}
+
+// collect loads the period's documents and the clients and cleaners they involve
+func (s *AccountingExportService) collect(from, to time.Time) (*accountingPeriod, error) {
+	period := &accountingPeriod{
+		From:        from,
+		To:          to,
+		Lines:       make(map[string][]*models.InvoiceLine),
+		CreditRates: make(map[string]float64),
+		Customers:   make(map[string]*accountingPartner),
+		Suppliers:   make(map[string]*accountingPartner),
+	}
+	byBooking := make(map[string]*accountingPartner)
+
+	var err error
+	if period.Invoices, err = s.invoiceRepo.GetIssuedBetween(from, to); err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+	for _, invoice := range period.Invoices {
+		lines, err := s.lineRepo.GetByInvoiceID(invoice.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lines of invoice %s: %w", invoice.InvoiceNumber, err)
+		}
+		period.Lines[invoice.ID] = exportLines(invoice, lines)
+
+		customer, err := s.invoiceCustomer(invoice, byBooking)
+		if err != nil {
+			return nil, err
+		}
+		period.Customers[invoice.ID] = customer
+	}
+
+	if period.CreditNotes, err = s.creditNoteRepo.GetIssuedBetween(from, to); err != nil {
+		return nil, fmt.Errorf("failed to get credit notes: %w", err)
+	}
+	for _, note := range period.CreditNotes {
+		invoice, err := s.invoiceRepo.GetByID(note.InvoiceID)
+		if err != nil || invoice == nil {
+			return nil, fmt.Errorf("failed to get invoice of credit note %s: %v", note.CreditNoteNumber, err)
+		}
+		customer, err := s.invoiceCustomer(invoice, byBooking)
+		if err != nil {
+			return nil, err
+		}
+		period.Customers[note.ID] = customer
+		period.CreditRates[note.ID] = impliedVATRate(note.Subtotal, note.TaxAmount)
+	}
+
+	if period.Payments, err = s.paymentRepo.GetSettledBetween(from, to); err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+	for _, payment := range period.Payments {
+		customer, ok := byBooking[payment.BookingID]
+		if !ok {
+			invoice, err := s.invoiceRepo.GetByBookingID(payment.BookingID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get invoice of booking %s: %w", payment.BookingID, err)
+			}
+			if invoice != nil {
+				customer, err = s.invoiceCustomer(invoice, byBooking)
+			} else {
+				customer, err = s.individualCustomer(payment.BookingID, "")
+			}
+			if err != nil {
+				return nil, err
+			}
+			byBooking[payment.BookingID] = customer
+		}
+		period.Customers[payment.ID] = customer
+	}
+
+	if period.Payouts, err = s.payoutRepo.GetPaidBetween(from, to); err != nil {
+		return nil, fmt.Errorf("failed to get payouts: %w", err)
+	}
+	for _, payout := range period.Payouts {
+		if _, ok := period.Suppliers[payout.CleanerID]; ok {
+			continue
+		}
+		supplier, err := s.cleanerSupplier(payout.CleanerID)
+		if err != nil {
+			return nil, err
+		}
+		supplier.IBAN = payout.IBAN.String
+		period.Suppliers[payout.CleanerID] = supplier
+	}
+
+	return period, nil
+}
+
+// invoiceCustomer returns the company a B2B invoice was issued to, or else the individual who booked
+func (s *AccountingExportService) invoiceCustomer(invoice *models.Invoice, byBooking map[string]*accountingPartner) (*accountingPartner, error) {
+	if customer, ok := byBooking[invoice.BookingID]; ok {
+		return customer, nil
+	}
+
+	billing, err := s.invoiceRepo.GetBillingDetails(invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing details of invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	var customer *accountingPartner
+	if billing != nil {
+		customer = &accountingPartner{
+			ID:                 utils.NormalizeCUI(billing.CUI),
+			Name:               billing.LegalName,
+			CUI:                utils.NormalizeCUI(billing.CUI),
+			RegistrationNumber: billing.RegistrationNumber.String,
+			StreetAddress:      billing.StreetAddress,
+			City:               billing.City,
+			County:             billing.County,
+			PostalCode:         billing.PostalCode.String,
+			Country:            billing.Country,
+		}
+	} else if customer, err = s.individualCustomer(invoice.BookingID, invoice.ClientName); err != nil {
+		return nil, err
+	}
+
+	byBooking[invoice.BookingID] = customer
+	return customer, nil
+}
+
+// individualCustomer returns the client of a booking, at the booking's address
+func (s *AccountingExportService) individualCustomer(bookingID, name string) (*accountingPartner, error) {
+	booking, err := s.bookingRepo.GetByID(bookingID)
+	if err != nil || booking == nil {
+		return nil, fmt.Errorf("failed to get booking %s: %v", bookingID, err)
+	}
+
+	customer := &accountingPartner{ID: booking.ClientID, Name: name, Country: "RO"}
+	if customer.Name == "" {
+		user, err := s.userRepo.GetByID(booking.ClientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client %s: %w", booking.ClientID, err)
+		}
+		if user != nil {
+			customer.Name = strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+		}
+	}
+
+	address, err := s.addressRepo.GetByID(booking.AddressID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get address of booking %s: %w", bookingID, err)
+	}
+	if address != nil {
+		customer.StreetAddress = address.StreetAddress
+		customer.City = address.City
+		customer.County = address.County
+		customer.PostalCode = address.PostalCode.String
+		customer.Country = address.Country
+	}
+	return customer, nil
+}
+
+// cleanerSupplier returns a cleaner, with the legal details of their PFA when they self-bill
+func (s *AccountingExportService) cleanerSupplier(cleanerID string) (*accountingPartner, error) {
+	cleaner, err := s.cleanerRepo.GetByID(cleanerID)
+	if err != nil || cleaner == nil {
+		return nil, fmt.Errorf("failed to get cleaner %s: %v", cleanerID, err)
+	}
+	supplier := &accountingPartner{
+		ID:            cleaner.ID,
+		StreetAddress: cleaner.StreetAddress.String,
+		City:          cleaner.City.String,
+		County:        cleaner.County.String,
+		PostalCode:    cleaner.PostalCode.String,
+		Country:       "RO",
+	}
+
+	user, err := s.userRepo.GetByID(cleaner.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner user %s: %w", cleaner.UserID, err)
+	}
+	if user != nil {
+		supplier.Name = strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+	}
+
+	settings, err := s.selfBillingRepo.GetSettings(cleaner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get self-billing settings of cleaner %s: %w", cleaner.ID, err)
+	}
+	if settings != nil && settings.CUI != "" {
+		supplier.Name = settings.LegalName
+		supplier.CUI = utils.NormalizeCUI(settings.CUI)
+		supplier.RegistrationNumber = settings.RegistrationNumber.String
+		supplier.StreetAddress = settings.Address
+		supplier.City = settings.City
+		supplier.County = settings.County
+	}
+	return supplier, nil
+}
+
+// exportLines returns an invoice's lines, or a single line with its totals for invoices issued
+// before itemized lines were stored
+func exportLines(invoice *models.Invoice, lines []*models.InvoiceLine) []*models.InvoiceLine {
+	if len(lines) > 0 {
+		return lines
+	}
+	return []*models.InvoiceLine{{
+		InvoiceID:   invoice.ID,
+		LineNumber:  1,
+		Description: invoice.ServiceDescription,
+		Quantity:    1,
+		UnitCode:    "C62",
+		VATRate:     impliedVATRate(invoice.Subtotal, invoice.TaxAmount),
+		NetAmount:   invoice.Subtotal,
+		VATAmount:   invoice.TaxAmount,
+		GrossAmount: invoice.TotalAmount,
+	}}
+}
+
+// impliedVATRate is the whole-percent VAT rate that gives taxAmount on net
+func impliedVATRate(net, taxAmount float64) float64 {
+	if net == 0 {
+		return 0
+	}
+	return math.Round(taxAmount/net*100) / 100
+}
+
+// account returns the chart of accounts code of a ledger account
+func (s *AccountingExportService) account(account models.LedgerAccount) string {
+	if code := s.cfg.Accounts[string(account)]; code != "" {
+		return code
+	}
+	return string(account)
+}
+
+// unitName is how SAGA and WinMentor name an e-Factura unit code
+func unitName(unitCode string) string {
+	switch unitCode {
+	case "HUR":
+		return "ora"
+	case "MTK":
+		return "mp"
+	default:
+		return "buc"
+	}
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func formatRODate(t time.Time) string {
+	return t.Format("02.01.2006")
+}
+
+// SAGA invoice import (Facturi XML)
+type sagaInvoices struct {
+	XMLName  xml.Name      `xml:"Facturi"`
+	Invoices []sagaInvoice `xml:"Factura"`
+}
+
+type sagaInvoice struct {
+	Header sagaInvoiceHeader `xml:"Antet"`
+	Lines  []sagaInvoiceLine `xml:"Detalii>Continut>Linie"`
+}
+
+type sagaInvoiceHeader struct {
+	FurnizorNume                  string
+	FurnizorCIF                   string
+	FurnizorNrRegCom              string
+	FurnizorTara                  string
+	FurnizorJudet                 string
+	FurnizorLocalitate            string
+	FurnizorAdresa                string
+	FurnizorBanca                 string
+	FurnizorIBAN                  string
+	ClientNume                    string
+	ClientCIF                     string
+	ClientNrRegCom                string
+	ClientTara                    string
+	ClientJudet                   string
+	ClientLocalitate              string
+	ClientAdresa                  string
+	FacturaNumar                  string
+	FacturaData                   string
+	FacturaScadenta               string
+	FacturaTaxareInversa          string
+	FacturaTVAIncasare            string
+	FacturaInformatiiSuplimentare string
+	FacturaMoneda                 string
+	FacturaIndexSPV               string
+}
+
+type sagaInvoiceLine struct {
+	LinieNrCrt int
+	Descriere  string
+	UM         string
+	Cantitate  string
+	Pret       string
+	Valoare    string
+	ProcTVA    string
+	TVA        string
+	Cont       string
+}
+
+// sagaInvoicesXML lists the period's invoices and credit notes in SAGA's invoice import format.
+// Credit notes are imported as invoices with negative quantities.
+func (s *AccountingExportService) sagaInvoicesXML(period *accountingPeriod) ([]byte, error) {
+	revenueAccount := s.account(models.LedgerAccountPlatformRevenue)
+	doc := sagaInvoices{}
+
+	for _, invoice := range period.Invoices {
+		entry := sagaInvoice{Header: s.sagaHeader(period.Customers[invoice.ID], invoice.InvoiceNumber, invoice.IssueDate, invoice.DueDate, invoice.Currency)}
+		entry.Header.FacturaIndexSPV = invoice.ANAFUploadIndex.String
+		if invoice.Status == models.InvoiceStatusCancelled {
+			entry.Header.FacturaInformatiiSuplimentare = "Anulata"
+		}
+		for _, line := range period.Lines[invoice.ID] {
+			unitPrice := 0.0
+			if line.Quantity != 0 {
+				unitPrice = line.NetAmount / line.Quantity
+			}
+			entry.Lines = append(entry.Lines, sagaInvoiceLine{
+				LinieNrCrt: line.LineNumber,
+				Descriere:  line.Description,
+				UM:         unitName(line.UnitCode),
+				Cantitate:  fmt.Sprintf("%.2f", line.Quantity),
+				Pret:       fmt.Sprintf("%.4f", unitPrice),
+				Valoare:    formatAmount(line.NetAmount),
+				ProcTVA:    fmt.Sprintf("%.0f", line.VATRate*100),
+				TVA:        formatAmount(line.VATAmount),
+				Cont:       revenueAccount,
+			})
+		}
+		doc.Invoices = append(doc.Invoices, entry)
+	}
+
+	for _, note := range period.CreditNotes {
+		entry := sagaInvoice{Header: s.sagaHeader(period.Customers[note.ID], note.CreditNoteNumber, note.IssueDate, note.IssueDate, note.Currency)}
+		entry.Header.FacturaIndexSPV = note.ANAFUploadIndex.String
+		entry.Header.FacturaInformatiiSuplimentare = note.Reason
+		entry.Lines = []sagaInvoiceLine{{
+			LinieNrCrt: 1,
+			Descriere:  note.Reason,
+			UM:         "buc",
+			Cantitate:  "-1.00",
+			Pret:       fmt.Sprintf("%.4f", note.Subtotal),
+			Valoare:    formatAmount(-note.Subtotal),
+			ProcTVA:    fmt.Sprintf("%.0f", period.CreditRates[note.ID]*100),
+			TVA:        formatAmount(-note.TaxAmount),
+			Cont:       revenueAccount,
+		}}
+		doc.Invoices = append(doc.Invoices, entry)
+	}
+
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SAGA invoices: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+func (s *AccountingExportService) sagaHeader(customer *accountingPartner, number string, issueDate, dueDate time.Time, currency string) sagaInvoiceHeader {
+	header := sagaInvoiceHeader{
+		FurnizorNume:         s.company.LegalName,
+		FurnizorCIF:          s.company.CUI,
+		FurnizorNrRegCom:     s.company.RegistrationNumber,
+		FurnizorTara:         s.company.Address.Country,
+		FurnizorJudet:        s.company.Address.County,
+		FurnizorLocalitate:   s.company.Address.City,
+		FurnizorAdresa:       s.company.Address.Street,
+		FurnizorBanca:        s.company.Bank.Name,
+		FurnizorIBAN:         s.company.Bank.IBAN,
+		FacturaNumar:         number,
+		FacturaData:          formatRODate(issueDate),
+		FacturaScadenta:      formatRODate(dueDate),
+		FacturaTaxareInversa: "Nu",
+		FacturaTVAIncasare:   "Nu",
+		FacturaMoneda:        currency,
+	}
+	if customer != nil {
+		header.ClientNume = customer.Name
+		header.ClientCIF = customer.CUI
+		header.ClientNrRegCom = customer.RegistrationNumber
+		header.ClientTara = customer.Country
+		header.ClientJudet = customer.County
+		header.ClientLocalitate = customer.City
+		header.ClientAdresa = customer.StreetAddress
+	}
+	return header
+}
+
+// winMentorInvoicesTXT lists the period's invoices and credit notes in WinMentor's sales invoice
+// import file: an [InfoPachet] section, then a [Factura_N] header and an [Items_N] section per
+// document. WinMentor reads the file as Windows-1250, so diacritics are stripped.
+func (s *AccountingExportService) winMentorInvoicesTXT(period *accountingPeriod) []byte {
+	var b strings.Builder
+	revenueAccount := s.account(models.LedgerAccountPlatformRevenue)
+	total := len(period.Invoices) + len(period.CreditNotes)
+
+	fmt.Fprintf(&b, "[InfoPachet]\r\nAnLucru=%d\r\nLunaLucru=%d\r\nTipDocument=FACTURA IESIRE\r\nTotalFacturi=%d\r\n",
+		period.From.Year(), int(period.From.Month()), total)
+
+	n := 0
+	writeDocument := func(customer *accountingPartner, number string, issueDate, dueDate time.Time, currency, notes string, items []string) {
+		n++
+		series, docNumber := splitDocumentNumber(number)
+		fmt.Fprintf(&b, "\r\n[Factura_%d]\r\nSerieCarnet=%s\r\nNrDoc=%s\r\nData=%s\r\nScadenta=%s\r\n",
+			n, series, docNumber, formatRODate(issueDate), formatRODate(dueDate))
+		if customer != nil {
+			fmt.Fprintf(&b, "CodPartener=%s\r\nNumePartener=%s\r\nCodFiscal=%s\r\nLocalitate=%s\r\n",
+				customer.ID, winMentorText(customer.Name), customer.CUI, winMentorText(customer.City))
+		}
+		fmt.Fprintf(&b, "Moneda=%s\r\nObservatii=%s\r\nTotalArticole=%d\r\n\r\n[Items_%d]\r\n",
+			currency, winMentorText(notes), len(items), n)
+		for i, item := range items {
+			fmt.Fprintf(&b, "Item_%d=%s\r\n", i+1, item)
+		}
+	}
+
+	// Item_k="Description";UM;Quantity;UnitPrice;VAT%;VAT;Account
+	for _, invoice := range period.Invoices {
+		var items []string
+		for _, line := range period.Lines[invoice.ID] {
+			unitPrice := 0.0
+			if line.Quantity != 0 {
+				unitPrice = line.NetAmount / line.Quantity
+			}
+			items = append(items, fmt.Sprintf("%q;%s;%.2f;%.4f;%.0f;%.2f;%s", winMentorText(line.Description),
+				unitName(line.UnitCode), line.Quantity, unitPrice, line.VATRate*100, line.VATAmount, revenueAccount))
+		}
+		notes := ""
+		if invoice.Status == models.InvoiceStatusCancelled {
+			notes = "Anulata"
+		}
+		writeDocument(period.Customers[invoice.ID], invoice.InvoiceNumber, invoice.IssueDate, invoice.DueDate, invoice.Currency, notes, items)
+	}
+
+	for _, note := range period.CreditNotes {
+		item := fmt.Sprintf("%q;buc;-1.00;%.4f;%.0f;%.2f;%s", winMentorText(note.Reason),
+			note.Subtotal, period.CreditRates[note.ID]*100, -note.TaxAmount, revenueAccount)
+		writeDocument(period.Customers[note.ID], note.CreditNoteNumber, note.IssueDate, note.IssueDate, note.Currency, note.Reason, []string{item})
+	}
+
+	return []byte(b.String())
+}
+
+// splitDocumentNumber splits a document number such as INV-2026-0042 into its series (INV)
+// and number (0042)
+func splitDocumentNumber(number string) (string, string) {
+	first := strings.Index(number, "-")
+	last := strings.LastIndex(number, "-")
+	if first < 0 {
+		return "", number
+	}
+	return number[:first], number[last+1:]
+}
+
+func winMentorText(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ", "\"", "'").Replace(utils.StripDiacritics(s))
+}
+
+// paymentsCSV lists the client payments collected and refunds paid in the period
+func (s *AccountingExportService) paymentsCSV(period *accountingPeriod) ([]byte, error) {
+	rows := [][]string{{"Data", "Tip", "Referinta", "Client", "CIF", "Suma", "Moneda", "Metoda", "Cont", "ContPartener", "Rezervare"}}
+	for _, payment := range period.Payments {
+		kind, date, amount := "INCASARE", payment.CapturedAt.Time, payment.Amount
+		if payment.PaymentType == models.PaymentTypeRefund {
+			kind, date, amount = "RESTITUIRE", payment.RefundedAt.Time, -payment.Amount
+		}
+		customer := period.Customers[payment.ID]
+		rows = append(rows, []string{
+			formatRODate(date), kind, paymentReference(payment), customer.Name, customer.CUI,
+			formatAmount(amount), payment.Currency, string(payment.Provider),
+			s.account(models.LedgerAccountCash), s.account(models.LedgerAccountClientReceivables), payment.BookingID,
+		})
+	}
+	return writeExportCSV(rows)
+}
+
+// payoutsCSV lists the payouts transferred to cleaners in the period
+func (s *AccountingExportService) payoutsCSV(period *accountingPeriod) ([]byte, error) {
+	rows := [][]string{{"Data", "Referinta", "Furnizor", "CIF", "IBAN", "Suma", "Moneda", "Cont", "ContPartener", "Perioada"}}
+	for _, payout := range period.Payouts {
+		supplier := period.Suppliers[payout.CleanerID]
+		rows = append(rows, []string{
+			formatRODate(payout.PaidAt.Time), payoutReference(payout), supplier.Name, supplier.CUI, payout.IBAN.String,
+			formatAmount(payout.NetAmount), "RON",
+			s.account(models.LedgerAccountCash), s.account(models.LedgerAccountCleanerPayables),
+			formatRODate(payout.PeriodStart) + " - " + formatRODate(payout.PeriodEnd),
+		})
+	}
+	return writeExportCSV(rows)
+}
+
+func paymentReference(payment *models.Payment) string {
+	if payment.ProviderTransactionID.Valid && payment.ProviderTransactionID.String != "" {
+		return payment.ProviderTransactionID.String
+	}
+	return payment.ID
+}
+
+func payoutReference(payout *models.Payout) string {
+	if payout.TransferReference.Valid && payout.TransferReference.String != "" {
+		return payout.TransferReference.String
+	}
+	return payout.ID
+}
+
+// writeExportCSV writes rows separated by semicolons, as SAGA and WinMentor import them
+func writeExportCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
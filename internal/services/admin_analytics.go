@@ -161,8 +161,8 @@ func (s *AdminAnalyticsService) getTopCleaners(startDate, endDate time.Time, lim
 			COALESCE(SUM(b.cleaner_payout), 0) as total_earnings,
 			c.average_rating
 		FROM bookings b
-		JOIN users u ON b.cleaner_id = u.id
-		LEFT JOIN cleaners c ON c.user_id = u.id
+		JOIN cleaners c ON c.id = b.cleaner_id
+		JOIN users u ON u.id = c.user_id
 		WHERE b.created_at >= $1 AND b.created_at <= $2
 			AND b.status = 'COMPLETED'
 			AND b.cleaner_id IS NOT NULL
@@ -212,7 +212,7 @@ func (s *AdminAnalyticsService) GetAllBookingsAdmin(limit, offset int, status *m
 			   includes_deep_cleaning, includes_windows, includes_carpet_cleaning,
 			   number_of_windows, carpet_area_sqm,
 			   special_instructions, access_instructions,
-			   base_price, addons_price, total_price, platform_fee, cleaner_payout, discount_applied,
+			   base_price, addons_price, total_price, platform_fee, platform_fee_rate, platform_fee_rule, cleaner_payout, discount_applied,
 			   status,
 			   confirmed_at, started_at, completed_at, cancelled_at,
 			   cancellation_reason, cancelled_by,
@@ -283,6 +283,8 @@ func (s *AdminAnalyticsService) GetAllBookingsAdmin(limit, offset int, status *m
 			&booking.AddonsPrice,
 			&booking.TotalPrice,
 			&booking.PlatformFee,
+			&booking.PlatformFeeRate,
+			&booking.PlatformFeeRule,
 			&booking.CleanerPayout,
 			&booking.DiscountApplied,
 			&booking.Status,
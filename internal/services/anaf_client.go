@@ -138,6 +138,16 @@ func (c *ANAFClient) getAccessToken(ctx context.Context) (string, error) {
 
 // UploadInvoice uploads a UBL XML invoice to ANAF SPV
 func (c *ANAFClient) UploadInvoice(ctx context.Context, xmlContent []byte, invoiceNumber string) (*ANAFUploadResponse, error) {
+	return c.upload(ctx, xmlContent, invoiceNumber, nil)
+}
+
+// UploadSelfBilledInvoice uploads an invoice issued on behalf of a supplier (autofactura), which
+// ANAF files in the supplier's SPV rather than only in ours
+func (c *ANAFClient) UploadSelfBilledInvoice(ctx context.Context, xmlContent []byte, invoiceNumber string) (*ANAFUploadResponse, error) {
+	return c.upload(ctx, xmlContent, invoiceNumber, map[string]string{"autofactura": "DA"})
+}
+
+func (c *ANAFClient) upload(ctx context.Context, xmlContent []byte, invoiceNumber string, extraFields map[string]string) (*ANAFUploadResponse, error) {
 	token, err := c.getAccessToken(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get access token: %w", err)
@@ -160,6 +170,11 @@ func (c *ANAFClient) UploadInvoice(ctx context.Context, xmlContent []byte, invoi
 	if err := writer.WriteField("cif", c.companyConfig.CUI); err != nil {
 		return nil, fmt.Errorf("failed to write CIF field: %w", err)
 	}
+	for name, value := range extraFields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("failed to write %s field: %w", name, err)
+		}
+	}
 
 	if err := writer.Close(); err != nil {
 		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
@@ -239,7 +254,7 @@ func (c *ANAFClient) GetInvoiceStatus(ctx context.Context, uploadIndex string) (
 	return &statusResp, nil
 }
 
-// DownloadConfirmation downloads the ANAF confirmation PDF for an accepted invoice
+// DownloadConfirmation downloads the confirmation of an accepted invoice: a ZIP with the invoice and ANAF's signature
 func (c *ANAFClient) DownloadConfirmation(ctx context.Context, downloadID string) ([]byte, error) {
 	token, err := c.getAccessToken(ctx)
 	if err != nil {
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// anafDocumentSubmitter is a service filing another document type (self-billed invoices,
+// commission invoices, credit notes) with ANAF
+type anafDocumentSubmitter interface {
+	ProcessPendingANAFSubmissions(batchSize int) error
+}
+
+// ANAFWorker files invoices, credit notes, commission invoices and self-billed invoices with
+// ANAF e-Factura in the background. Each run submits documents past the submission delay,
+// retries failed uploads with exponential backoff, polls uploads ANAF is still processing,
+// downloads confirmations, and alerts admins about documents close to the legal deadline or
+// that need manual correction.
+type ANAFWorker struct {
+	invoiceService *InvoiceService
+	submitters     []anafDocumentSubmitter
+	documentRepo   *models.ANAFDocumentRepository
+	alertRepo      *models.ANAFAlertRepository
+	userRepo       *models.UserRepository
+	emailService   *EmailService
+	cfg            *config.ANAFConfig
+}
+
+// NewANAFWorker creates a new ANAF worker
+func NewANAFWorker(db *sql.DB, invoiceService *InvoiceService, emailService *EmailService, anafConfig *config.ANAFConfig) *ANAFWorker {
+	return &ANAFWorker{
+		invoiceService: invoiceService,
+		documentRepo:   models.NewANAFDocumentRepository(db),
+		alertRepo:      models.NewANAFAlertRepository(db),
+		userRepo:       models.NewUserRepository(db),
+		emailService:   emailService,
+		cfg:            anafConfig,
+	}
+}
+
+// AddSubmitter has the worker also retry the pending submissions of another document type
+func (w *ANAFWorker) AddSubmitter(submitter anafDocumentSubmitter) {
+	w.submitters = append(w.submitters, submitter)
+}
+
+// Run runs the worker every interval until the process exits. A run that panics is logged
+// and the worker carries on with the next one.
+func (w *ANAFWorker) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.runSupervised()
+	for range ticker.C {
+		w.runSupervised()
+	}
+}
+
+func (w *ANAFWorker) runSupervised() {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Warning: ANAF worker run panicked: %v\n%s\n", r, debug.Stack())
+		}
+	}()
+	w.RunOnce()
+}
+
+// RunOnce performs one pass of the worker. Each step's failure is logged without stopping the others.
+func (w *ANAFWorker) RunOnce() {
+	batchSize := w.cfg.WorkerBatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	if w.cfg.AutoSubmit {
+		if err := w.invoiceService.ProcessPendingANAFSubmissions(batchSize); err != nil {
+			fmt.Printf("Warning: ANAF invoice submission failed: %v\n", err)
+		}
+		for _, submitter := range w.submitters {
+			if err := submitter.ProcessPendingANAFSubmissions(batchSize); err != nil {
+				fmt.Printf("Warning: ANAF document submission failed: %v\n", err)
+			}
+		}
+	}
+
+	if err := w.invoiceService.ProcessANAFStatusUpdates(); err != nil {
+		fmt.Printf("Warning: ANAF status polling failed: %v\n", err)
+	}
+	if downloaded, err := w.invoiceService.DownloadANAFConfirmations(batchSize); err != nil {
+		fmt.Printf("Warning: ANAF confirmation archiving failed: %v\n", err)
+	} else if downloaded > 0 {
+		fmt.Printf("Archived %d ANAF confirmation(s)\n", downloaded)
+	}
+
+	// The other documents are polled and archived without the invoice's client emails
+	for _, docType := range models.ANAFDocumentTypes {
+		if docType == models.ANAFDocumentInvoice {
+			continue
+		}
+		if err := w.pollDocuments(docType); err != nil {
+			fmt.Printf("Warning: ANAF status polling of %s documents failed: %v\n", docType, err)
+		}
+		if downloaded, err := w.archiveDocuments(docType, batchSize); err != nil {
+			fmt.Printf("Warning: ANAF confirmation archiving of %s documents failed: %v\n", docType, err)
+		} else if downloaded > 0 {
+			fmt.Printf("Archived %d ANAF confirmation(s) of %s documents\n", downloaded, docType)
+		}
+	}
+
+	if err := w.checkAlerts(); err != nil {
+		fmt.Printf("Warning: ANAF alert check failed: %v\n", err)
+	}
+}
+
+// pollDocuments checks the documents of a type ANAF is still processing and records the
+// outcome of those it has finished with
+func (w *ANAFWorker) pollDocuments(docType models.ANAFDocumentType) error {
+	docs, err := w.documentRepo.GetProcessing(docType)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if err := w.checkStatus(doc); err != nil {
+			fmt.Printf("Warning: failed to check ANAF status of %s: %v\n", doc.Number, err)
+		}
+	}
+	return nil
+}
+
+// checkStatus asks ANAF about a submitted document, keeping the download ID of its signed
+// response once accepted and the errors once rejected
+func (w *ANAFWorker) checkStatus(doc *models.ANAFDocument) error {
+	if !doc.ANAFUploadIndex.Valid || doc.ANAFUploadIndex.String == "" {
+		return fmt.Errorf("not submitted to ANAF")
+	}
+
+	statusResp, err := w.invoiceService.anafClient.GetInvoiceStatus(context.Background(), doc.ANAFUploadIndex.String)
+	if err != nil {
+		return fmt.Errorf("failed to check ANAF status: %w", err)
+	}
+
+	switch statusResp.Status {
+	case "accepted":
+		doc.ANAFStatus = models.ANAFStatusAccepted
+		doc.ANAFProcessedAt = sql.NullTime{Time: statusResp.DateProcessed, Valid: true}
+		if statusResp.DownloadID != "" {
+			doc.ANAFDownloadID = sql.NullString{String: statusResp.DownloadID, Valid: true}
+		}
+	case "rejected":
+		doc.ANAFStatus = models.ANAFStatusRejected
+		doc.ANAFProcessedAt = sql.NullTime{Time: statusResp.DateProcessed, Valid: true}
+		doc.ANAFErrors = make([]models.ANAFError, len(statusResp.Errors))
+		for i, e := range statusResp.Errors {
+			doc.ANAFErrors[i] = models.ANAFError{Code: e.Code, Message: e.Message, Field: e.Field}
+		}
+	default:
+		return nil
+	}
+	return w.documentRepo.UpdateStatus(doc)
+}
+
+// archiveDocuments archives the signed responses of accepted documents of a type and returns
+// how many were archived
+func (w *ANAFWorker) archiveDocuments(docType models.ANAFDocumentType, batchSize int) (int, error) {
+	docs, err := w.documentRepo.GetAwaitingConfirmation(docType, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, doc := range docs {
+		if _, err := w.invoiceService.archiveANAFResponse(doc); err != nil {
+			fmt.Printf("Warning: failed to archive ANAF confirmation of %s: %v\n", doc.Number, err)
+			continue
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// checkAlerts resolves the alerts of documents ANAF has accepted since, then raises alerts for
+// documents close to their deadline and for those that need correcting by hand
+func (w *ANAFWorker) checkAlerts() error {
+	if _, err := w.alertRepo.ResolveAccepted(); err != nil {
+		return err
+	}
+
+	deadlineDays := w.cfg.SubmissionDeadlineDays
+	if deadlineDays <= 0 {
+		deadlineDays = 5
+	}
+	warningDays := w.cfg.DeadlineWarningDays
+	if warningDays <= 0 || warningDays > deadlineDays {
+		warningDays = 2
+	}
+
+	// Deadlines run from the issue date, so alert on documents issued deadlineDays-warningDays ago or earlier
+	today := time.Now().Truncate(24 * time.Hour)
+	atRisk, err := w.documentRepo.GetNotSubmittedIssuedBefore(today.AddDate(0, 0, warningDays-deadlineDays))
+	if err != nil {
+		return fmt.Errorf("failed to get documents close to the deadline: %w", err)
+	}
+	for _, doc := range atRisk {
+		deadline := doc.IssueDate.AddDate(0, 0, deadlineDays)
+		message := fmt.Sprintf("%s %s must reach ANAF by %s and is still %s",
+			anafDocumentLabel(doc.Type), doc.Number, deadline.Format("02.01.2006"), doc.ANAFStatus)
+		if deadline.Before(today) {
+			message = fmt.Sprintf("%s %s missed the ANAF deadline of %s and is still %s",
+				anafDocumentLabel(doc.Type), doc.Number, deadline.Format("02.01.2006"), doc.ANAFStatus)
+		}
+		w.raise(doc, models.ANAFAlertDeadlineApproaching, message, deadline)
+	}
+
+	_, _, maxRetries := w.invoiceService.anafSchedule()
+	failed, err := w.documentRepo.GetPermanentlyFailed(maxRetries)
+	if err != nil {
+		return fmt.Errorf("failed to get rejected documents: %w", err)
+	}
+	for _, doc := range failed {
+		deadline := doc.IssueDate.AddDate(0, 0, deadlineDays)
+		if doc.ANAFStatus == models.ANAFStatusRejected {
+			message := fmt.Sprintf("%s %s was rejected", anafDocumentLabel(doc.Type), doc.Number)
+			if len(doc.ANAFErrors) > 0 {
+				message += ": " + doc.ANAFErrors[0].Message
+			}
+			w.raise(doc, models.ANAFAlertRejected, message, deadline)
+			continue
+		}
+		w.raise(doc, models.ANAFAlertRetriesExhausted, fmt.Sprintf("Submitting %s %s to ANAF failed %d times",
+			strings.ToLower(anafDocumentLabel(doc.Type)), doc.Number, doc.ANAFRetryCount), deadline)
+	}
+	return nil
+}
+
+// anafDocumentLabel names a document type in alert messages
+func anafDocumentLabel(docType models.ANAFDocumentType) string {
+	switch docType {
+	case models.ANAFDocumentCreditNote:
+		return "Credit note"
+	case models.ANAFDocumentCommissionInvoice:
+		return "Commission invoice"
+	case models.ANAFDocumentSelfBilledInvoice:
+		return "Self-billed invoice"
+	default:
+		return "Invoice"
+	}
+}
+
+// raise records an alert and, if the document had no open alert of that type, emails the platform admins
+func (w *ANAFWorker) raise(doc *models.ANAFDocument, alertType models.ANAFAlertType, message string, deadline time.Time) {
+	alert := &models.ANAFAlert{
+		DocumentType: doc.Type,
+		DocumentID:   doc.ID,
+		AlertType:    alertType,
+		Message:      message,
+		Deadline:     deadline,
+	}
+	created, err := w.alertRepo.CreateIfNew(alert)
+	if err != nil {
+		fmt.Printf("Warning: failed to record ANAF alert for %s: %v\n", doc.Number, err)
+		return
+	}
+	if !created || w.emailService == nil {
+		return
+	}
+
+	admins, err := w.userRepo.GetActiveByRole(models.RolePlatformAdmin)
+	if err != nil {
+		fmt.Printf("Warning: failed to get admins for ANAF alert: %v\n", err)
+		return
+	}
+	for _, admin := range admins {
+		if !admin.Email.Valid || admin.Email.String == "" {
+			continue
+		}
+		if err := w.emailService.SendANAFAlertEmail(context.Background(), admin.Email.String, doc.Number,
+			string(alertType), message, deadline.Format("02.01.2006")); err != nil {
+			fmt.Printf("Warning: failed to email ANAF alert to %s: %v\n", admin.Email.String, err)
+		}
+	}
+}
+
+// GetAlerts returns ANAF alerts newest first, only open ones unless includeResolved is set
+func (w *ANAFWorker) GetAlerts(includeResolved bool, limit, offset int) ([]*models.ANAFAlert, error) {
+	return w.alertRepo.List(includeResolved, limit, offset)
+}
+
+// ResolveAlert closes an alert an admin has dealt with
+func (w *ANAFWorker) ResolveAlert(alertID, adminID string) (*models.ANAFAlert, error) {
+	alert, err := w.alertRepo.GetByID(alertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ANAF alert: %w", err)
+	}
+	if alert == nil {
+		return nil, fmt.Errorf("ANAF alert not found")
+	}
+	if alert.ResolvedAt.Valid {
+		return alert, nil
+	}
+
+	if err := w.alertRepo.Resolve(alertID, adminID); err != nil {
+		return nil, err
+	}
+	return w.alertRepo.GetByID(alertID)
+}
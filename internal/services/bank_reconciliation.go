@@ -0,0 +1,639 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// Maximum accepted bank statement file size
+const maxBankStatementSize = 20 * 1024 * 1024
+
+// BankReconciliationService imports bank statements and matches their lines to
+// payouts (outgoing transfers), and to unpaid invoices, proformas and cash fees (incoming transfers)
+type BankReconciliationService struct {
+	statementRepo     *models.BankStatementRepository
+	payoutRepo        *models.PayoutRepository
+	companyPayoutRepo *models.CompanyPayoutRepository
+	payoutService     *PayoutService
+	invoiceService    *InvoiceService
+	proformaService   *ProformaService
+}
+
+// NewBankReconciliationService creates a new bank reconciliation service
+func NewBankReconciliationService(db *sql.DB, payoutService *PayoutService, invoiceService *InvoiceService) *BankReconciliationService {
+	return &BankReconciliationService{
+		statementRepo:     models.NewBankStatementRepository(db),
+		payoutRepo:        models.NewPayoutRepository(db),
+		companyPayoutRepo: models.NewCompanyPayoutRepository(db),
+		payoutService:     payoutService,
+		invoiceService:    invoiceService,
+	}
+}
+
+// SetProformaService sets the service that settles proformas paid by bank transfer
+func (s *BankReconciliationService) SetProformaService(proformaService *ProformaService) {
+	s.proformaService = proformaService
+}
+
+// How far back to look for the payout a reversal returns
+const reversalLookbackDays = 60
+
+// Payouts and cash fees are settled in lei
+const payoutCurrency = "RON"
+
+// reconciliationCandidates holds what one import run can match lines to
+type reconciliationCandidates struct {
+	payouts       []*payoutCandidate // Open payouts and unpaid cash fee invoices
+	sentPayouts   []*payoutCandidate // Recently settled payouts, for reversals
+	invoices      []*models.Invoice
+	proformas     []*models.Proforma
+	usedPayouts   map[string]bool
+	usedInvoices  map[string]bool
+	usedProformas map[string]bool
+}
+
+// payoutCandidate is a cleaner or company payout seen as the bank transfer that settles it
+type payoutCandidate struct {
+	payout        *models.Payout        // Set for a cleaner's own payout
+	companyPayout *models.CompanyPayout // Set for a payout to a cleaners' company
+	direction     string                // DEBIT when paid out, CREDIT for cash fees paid to the platform
+	amount        float64               // Transfer amount as it appears on the statement
+	iban          string                // Cleaner or company IBAN
+}
+
+func newPayoutCandidate(payout *models.Payout, iban string) *payoutCandidate {
+	c := &payoutCandidate{payout: payout, direction: utils.BankStatementDebit, amount: payout.NetAmount, iban: iban}
+	if payout.NetAmount < 0 {
+		c.direction, c.amount = utils.BankStatementCredit, -payout.NetAmount
+	}
+	return c
+}
+
+func newCompanyPayoutCandidate(companyPayout *models.CompanyPayout, iban string) *payoutCandidate {
+	c := &payoutCandidate{companyPayout: companyPayout, direction: utils.BankStatementDebit, amount: companyPayout.NetAmount, iban: iban}
+	if companyPayout.NetAmount < 0 {
+		c.direction, c.amount = utils.BankStatementCredit, -companyPayout.NetAmount
+	}
+	return c
+}
+
+func (c *payoutCandidate) id() string {
+	if c.companyPayout != nil {
+		return c.companyPayout.ID
+	}
+	return c.payout.ID
+}
+
+func (c *payoutCandidate) status() string {
+	if c.companyPayout != nil {
+		return c.companyPayout.Status
+	}
+	return c.payout.Status
+}
+
+func (c *payoutCandidate) transferReference() string {
+	if c.companyPayout != nil {
+		return c.companyPayout.TransferReference.String
+	}
+	return c.payout.TransferReference.String
+}
+
+// isOpen reports whether the payout still waits for its transfer
+func (c *payoutCandidate) isOpen() bool {
+	switch c.status() {
+	case models.PayoutStatusPending, models.PayoutStatusInvoiced:
+		return true
+	case models.PayoutStatusProcessing:
+		return c.payout != nil
+	case models.PayoutStatusFailed:
+		// Failed company payouts can be sent again
+		return c.companyPayout != nil
+	}
+	return false
+}
+
+func (c *payoutCandidate) match() models.BankLineMatch {
+	if c.companyPayout != nil {
+		return models.BankLineMatch{CompanyPayoutID: c.companyPayout.ID}
+	}
+	return models.BankLineMatch{PayoutID: c.payout.ID}
+}
+
+// ImportStatement parses an uploaded statement, stores the import and its lines in one
+// transaction, then auto-matches the new lines. Lines already imported from an overlapping
+// statement are skipped. A line that fails to settle stays in the queue with the reason.
+func (s *BankReconciliationService) ImportStatement(upload graphql.Upload, format, adminID string) (*models.BankStatementImport, error) {
+	if upload.Size > maxBankStatementSize {
+		return nil, fmt.Errorf("file too large: maximum size is 20MB")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload.File, maxBankStatementSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statement file: %w", err)
+	}
+
+	if format == "" {
+		format, err = utils.DetectBankStatementFormat(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parsed, err := utils.ParseBankStatement(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bank statement: %w", err)
+	}
+
+	lines := make([]*models.BankStatementLine, 0, len(parsed))
+	var reversalsFrom time.Time
+	for _, entry := range parsed {
+		lines = append(lines, &models.BankStatementLine{
+			BookingDate:      entry.BookingDate,
+			Direction:        entry.Direction,
+			Amount:           entry.Amount,
+			Currency:         entry.Currency,
+			CounterpartyName: nullIfEmpty(entry.CounterpartyName),
+			CounterpartyIBAN: nullIfEmpty(entry.CounterpartyIBAN),
+			Reference:        nullIfEmpty(entry.Reference),
+			BankReference:    nullIfEmpty(entry.BankReference),
+			Fingerprint:      statementLineFingerprint(entry),
+			Reversal:         entry.Reversal,
+			MatchStatus:      models.BankLineStatusUnmatched,
+		})
+		if entry.Reversal {
+			from := entry.BookingDate.AddDate(0, 0, -reversalLookbackDays)
+			if reversalsFrom.IsZero() || from.Before(reversalsFrom) {
+				reversalsFrom = from
+			}
+		}
+	}
+
+	candidates, err := s.loadCandidates(reversalsFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &models.BankStatementImport{
+		FileName:   upload.Filename,
+		Format:     format,
+		ImportedBy: sql.NullString{String: adminID, Valid: adminID != ""},
+	}
+	created, err := s.statementRepo.CreateImport(imp, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range created {
+		matched, err := s.autoMatch(line, candidates)
+		if err != nil {
+			// Leave the line in the queue with the reason; an admin resolves it
+			fmt.Printf("Warning: failed to reconcile statement line %s: %v\n", line.ID, err)
+			if noteErr := s.statementRepo.SetLineNotes(line.ID, err.Error()); noteErr != nil {
+				fmt.Printf("Warning: failed to save notes for statement line %s: %v\n", line.ID, noteErr)
+			}
+			continue
+		}
+		if matched {
+			imp.MatchedCount++
+		}
+	}
+
+	if err := s.statementRepo.UpdateImportCounts(imp); err != nil {
+		return nil, err
+	}
+
+	return imp, nil
+}
+
+// GetReconciliationQueue returns statement lines that still need manual matching
+func (s *BankReconciliationService) GetReconciliationQueue(limit, offset int) ([]*models.BankStatementLine, error) {
+	return s.statementRepo.GetUnmatchedLines(limit, offset)
+}
+
+// MatchLine manually settles a statement line with exactly one payout or company payout
+// (outgoing transfer, or cash fees paid in), invoice or proforma (incoming transfer)
+func (s *BankReconciliationService) MatchLine(lineID string, match models.BankLineMatch, adminID string) (*models.BankStatementLine, error) {
+	line, err := s.getUnmatchedLine(lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := 0
+	for _, id := range []string{match.PayoutID, match.CompanyPayoutID, match.InvoiceID, match.ProformaID} {
+		if id != "" {
+			matches++
+		}
+	}
+
+	switch {
+	case matches > 1:
+		return nil, fmt.Errorf("a statement line can match only one payout, company payout, invoice or proforma")
+	case match.PayoutID != "" || match.CompanyPayoutID != "":
+		payout, err := s.getPayoutCandidate(match)
+		if err != nil {
+			return nil, err
+		}
+		if !payout.isOpen() {
+			return nil, fmt.Errorf("payout is already %s", payout.status())
+		}
+		if payout.direction != line.Direction {
+			if payout.direction == utils.BankStatementDebit {
+				return nil, fmt.Errorf("only outgoing transfers can be matched to payouts")
+			}
+			return nil, fmt.Errorf("only incoming transfers can be matched to invoiced cash fees")
+		}
+		if err := s.settlePayout(line, payout); err != nil {
+			return nil, err
+		}
+	case match.InvoiceID != "":
+		if line.Direction != utils.BankStatementCredit {
+			return nil, fmt.Errorf("only incoming transfers can be matched to invoices")
+		}
+		if err := s.settleInvoice(line, match.InvoiceID); err != nil {
+			return nil, err
+		}
+	case match.ProformaID != "":
+		if line.Direction != utils.BankStatementCredit {
+			return nil, fmt.Errorf("only incoming transfers can be matched to proformas")
+		}
+		if s.proformaService == nil {
+			return nil, fmt.Errorf("proformas are not available")
+		}
+		if _, err := s.proformaService.RecordTransfer(match.ProformaID, lineReference(line)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("payoutId, companyPayoutId, invoiceId or proformaId is required")
+	}
+
+	if err := s.statementRepo.MarkLineMatched(line.ID, match, adminID); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.GetLineByID(line.ID)
+}
+
+// IgnoreLine removes a line that settles nothing on the platform (bank fees, own transfers)
+func (s *BankReconciliationService) IgnoreLine(lineID, reason, adminID string) (*models.BankStatementLine, error) {
+	line, err := s.getUnmatchedLine(lineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.statementRepo.MarkLineIgnored(line.ID, adminID, reason); err != nil {
+		return nil, err
+	}
+
+	return s.statementRepo.GetLineByID(line.ID)
+}
+
+func (s *BankReconciliationService) getUnmatchedLine(lineID string) (*models.BankStatementLine, error) {
+	line, err := s.statementRepo.GetLineByID(lineID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement line: %w", err)
+	}
+	if line == nil {
+		return nil, fmt.Errorf("statement line not found")
+	}
+	if line.MatchStatus != models.BankLineStatusUnmatched {
+		return nil, fmt.Errorf("statement line is already %s", strings.ToLower(line.MatchStatus))
+	}
+	return line, nil
+}
+
+// getPayoutCandidate loads the cleaner or company payout named by a manual match
+func (s *BankReconciliationService) getPayoutCandidate(match models.BankLineMatch) (*payoutCandidate, error) {
+	if match.CompanyPayoutID != "" {
+		companyPayout, err := s.companyPayoutRepo.GetByID(match.CompanyPayoutID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get company payout: %w", err)
+		}
+		if companyPayout == nil {
+			return nil, fmt.Errorf("company payout not found")
+		}
+		return newCompanyPayoutCandidate(companyPayout, ""), nil
+	}
+
+	payout, err := s.payoutRepo.GetByID(match.PayoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout: %w", err)
+	}
+	if payout == nil {
+		return nil, fmt.Errorf("payout not found")
+	}
+	if payout.CompanyPayoutID.Valid {
+		return nil, fmt.Errorf("payout is paid to the cleaner's company with company payout %s", payout.CompanyPayoutID.String)
+	}
+	return newPayoutCandidate(payout, ""), nil
+}
+
+// loadCandidates loads open payouts, invoices and proformas, and the payouts settled since
+// reversalsFrom (none when zero) that a reversal line may return
+func (s *BankReconciliationService) loadCandidates(reversalsFrom time.Time) (*reconciliationCandidates, error) {
+	candidates := &reconciliationCandidates{
+		usedPayouts:   map[string]bool{},
+		usedInvoices:  map[string]bool{},
+		usedProformas: map[string]bool{},
+	}
+
+	var open []*models.Payout
+	for _, status := range []string{models.PayoutStatusPending, models.PayoutStatusProcessing, models.PayoutStatusInvoiced} {
+		payouts, err := s.payoutRepo.GetByStatus(status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open payouts: %w", err)
+		}
+		open = append(open, payouts...)
+	}
+	candidates.payouts = s.cleanerPayoutCandidates(open)
+
+	companyPayouts, err := s.companyPayoutRepo.GetUnsettled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open company payouts: %w", err)
+	}
+	candidates.payouts = append(candidates.payouts, s.companyPayoutCandidates(companyPayouts)...)
+
+	if !reversalsFrom.IsZero() {
+		to := time.Now().AddDate(0, 0, 1)
+		sent, err := s.payoutRepo.GetPaidBetween(reversalsFrom, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sent payouts: %w", err)
+		}
+		candidates.sentPayouts = s.cleanerPayoutCandidates(sent)
+
+		sentCompany, err := s.companyPayoutRepo.GetPaidBetween(reversalsFrom, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sent company payouts: %w", err)
+		}
+		candidates.sentPayouts = append(candidates.sentPayouts, s.companyPayoutCandidates(sentCompany)...)
+	}
+
+	invoices, err := s.invoiceService.GetUnpaidInvoices()
+	if err != nil {
+		return nil, err
+	}
+	candidates.invoices = invoices
+
+	if s.proformaService != nil {
+		proformas, err := s.proformaService.GetUnpaidProformas()
+		if err != nil {
+			return nil, err
+		}
+		candidates.proformas = proformas
+	}
+
+	return candidates, nil
+}
+
+// cleanerPayoutCandidates skips payouts settled through a company payout, which the
+// company transfer settles
+func (s *BankReconciliationService) cleanerPayoutCandidates(payouts []*models.Payout) []*payoutCandidate {
+	var candidates []*payoutCandidate
+	for _, payout := range payouts {
+		if payout.CompanyPayoutID.Valid {
+			continue
+		}
+		iban, err := s.payoutService.GetCleanerIBAN(payout.CleanerID)
+		if err != nil {
+			fmt.Printf("Warning: failed to get IBAN for payout %s: %v\n", payout.ID, err)
+		}
+		candidates = append(candidates, newPayoutCandidate(payout, iban))
+	}
+	return candidates
+}
+
+func (s *BankReconciliationService) companyPayoutCandidates(companyPayouts []*models.CompanyPayout) []*payoutCandidate {
+	var candidates []*payoutCandidate
+	for _, companyPayout := range companyPayouts {
+		iban, err := s.payoutService.getCompanyPayoutIBAN(companyPayout.ID)
+		if err != nil {
+			fmt.Printf("Warning: failed to get IBAN for company payout %s: %v\n", companyPayout.ID, err)
+		}
+		candidates = append(candidates, newCompanyPayoutCandidate(companyPayout, iban))
+	}
+	return candidates
+}
+
+// autoMatch settles a line when exactly one open payout, invoice or proforma fits it.
+// Reversals are only linked to the payout they return; an admin fails and resends it.
+func (s *BankReconciliationService) autoMatch(line *models.BankStatementLine, c *reconciliationCandidates) (bool, error) {
+	if line.Reversal {
+		payout := matchPayout(line, c.sentPayouts, oppositeDirection(line.Direction), c.usedPayouts)
+		if payout == nil {
+			return false, nil
+		}
+		c.usedPayouts[payout.id()] = true
+		notes := fmt.Sprintf("Reverses payout %s sent with reference %s: mark it as failed and send it again",
+			payout.id(), payout.transferReference())
+		return false, s.statementRepo.LinkReversal(line.ID, payout.match(), notes)
+	}
+
+	if payout := matchPayout(line, c.payouts, line.Direction, c.usedPayouts); payout != nil {
+		if err := s.settlePayout(line, payout); err != nil {
+			return false, err
+		}
+		c.usedPayouts[payout.id()] = true
+		return true, s.statementRepo.MarkLineMatched(line.ID, payout.match(), "")
+	}
+	if line.Direction == utils.BankStatementDebit {
+		return false, nil
+	}
+
+	if invoice := matchInvoice(line, c); invoice != nil {
+		if err := s.settleInvoice(line, invoice.ID); err != nil {
+			return false, err
+		}
+		c.usedInvoices[invoice.ID] = true
+		return true, s.statementRepo.MarkLineMatched(line.ID, models.BankLineMatch{InvoiceID: invoice.ID}, "")
+	}
+
+	proforma := matchProforma(line, c)
+	if proforma == nil {
+		return false, nil
+	}
+	if _, err := s.proformaService.RecordTransfer(proforma.ID, lineReference(line)); err != nil {
+		return false, err
+	}
+	c.usedProformas[proforma.ID] = true
+	return true, s.statementRepo.MarkLineMatched(line.ID, models.BankLineMatch{ProformaID: proforma.ID}, "")
+}
+
+// settlePayout marks a payout as sent, or its invoiced cash fees as paid, using the bank's
+// reference for the transfer
+func (s *BankReconciliationService) settlePayout(line *models.BankStatementLine, payout *payoutCandidate) error {
+	reference := lineReference(line)
+	incoming := payout.direction == utils.BankStatementCredit
+
+	var err error
+	switch {
+	case payout.companyPayout != nil && incoming:
+		_, err = s.payoutService.MarkCompanyPayoutInvoicePaid(payout.companyPayout.ID, reference)
+	case payout.companyPayout != nil:
+		_, err = s.payoutService.MarkCompanyPayoutAsSent(payout.companyPayout.ID, reference)
+	case incoming:
+		err = s.payoutService.MarkPayoutInvoicePaid(payout.payout.ID, reference)
+	default:
+		err = s.payoutService.MarkPayoutAsSent(payout.payout.ID, reference)
+	}
+	return err
+}
+
+// settleInvoice marks an invoice as paid, along with the proforma it was converted from
+func (s *BankReconciliationService) settleInvoice(line *models.BankStatementLine, invoiceID string) error {
+	if err := s.invoiceService.MarkInvoiceAsPaid(invoiceID); err != nil {
+		return err
+	}
+	if s.proformaService != nil {
+		if err := s.proformaService.SettleInvoice(invoiceID, lineReference(line)); err != nil {
+			fmt.Printf("Warning: failed to settle proforma of invoice %s: %v\n", invoiceID, err)
+		}
+	}
+	return nil
+}
+
+// lineReference returns the bank's reference for a transfer, else the remittance text
+func lineReference(line *models.BankStatementLine) string {
+	if line.BankReference.String != "" {
+		return line.BankReference.String
+	}
+	return line.Reference.String
+}
+
+// matchPayout picks the payout in the given direction that a line settles (or, for a reversal,
+// returns). Payouts whose ID or transfer reference appears in the remittance text are preferred;
+// otherwise it falls back to the cleaner's or company's IBAN. Amount and currency must agree.
+func matchPayout(line *models.BankStatementLine, payouts []*payoutCandidate, direction string, used map[string]bool) *payoutCandidate {
+	if !sameCurrency(line.Currency, payoutCurrency) {
+		return nil
+	}
+	reference := strings.ToUpper(line.Reference.String)
+
+	var byReference, byIBAN []*payoutCandidate
+	for _, payout := range payouts {
+		if used[payout.id()] || payout.direction != direction || !amountsEqual(payout.amount, line.Amount) {
+			continue
+		}
+		transferRef := payout.transferReference()
+		if reference != "" && (strings.Contains(reference, strings.ToUpper(payout.id())) ||
+			(transferRef != "" && strings.Contains(reference, strings.ToUpper(transferRef)))) {
+			byReference = append(byReference, payout)
+			continue
+		}
+		if payout.iban != "" && line.CounterpartyIBAN.Valid && payout.iban == line.CounterpartyIBAN.String {
+			byIBAN = append(byIBAN, payout)
+		}
+	}
+
+	if len(byReference) == 1 {
+		return byReference[0]
+	}
+	if len(byReference) == 0 && len(byIBAN) == 1 {
+		return byIBAN[0]
+	}
+	return nil
+}
+
+// matchInvoice finds the single unpaid invoice whose number is quoted in the payment details
+func matchInvoice(line *models.BankStatementLine, c *reconciliationCandidates) *models.Invoice {
+	reference := compactReference(line.Reference.String)
+	if reference == "" {
+		return nil
+	}
+
+	var found []*models.Invoice
+	for _, invoice := range c.invoices {
+		if c.usedInvoices[invoice.ID] || !sameCurrency(line.Currency, invoice.Currency) || !amountsEqual(invoice.TotalAmount, line.Amount) {
+			continue
+		}
+		if strings.Contains(reference, compactReference(invoice.InvoiceNumber)) {
+			found = append(found, invoice)
+		}
+	}
+
+	if len(found) == 1 {
+		return found[0]
+	}
+	return nil
+}
+
+// matchProforma finds the single unpaid proforma whose number is quoted in the payment details
+// and whose amount due was transferred
+func matchProforma(line *models.BankStatementLine, c *reconciliationCandidates) *models.Proforma {
+	reference := compactReference(line.Reference.String)
+	if reference == "" {
+		return nil
+	}
+
+	var found []*models.Proforma
+	for _, proforma := range c.proformas {
+		if c.usedProformas[proforma.ID] || !sameCurrency(line.Currency, proforma.Currency) || !amountsEqual(proforma.AmountDue, line.Amount) {
+			continue
+		}
+		if strings.Contains(reference, compactReference(proforma.ProformaNumber)) {
+			found = append(found, proforma)
+		}
+	}
+
+	if len(found) == 1 {
+		return found[0]
+	}
+	return nil
+}
+
+// compactReference drops separators clients tend to omit or change ("INV 2025/0042")
+func compactReference(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// sameCurrency compares currency codes, taking a missing code as lei
+func sameCurrency(a, b string) bool {
+	if a == "" {
+		a = payoutCurrency
+	}
+	if b == "" {
+		b = payoutCurrency
+	}
+	return strings.EqualFold(a, b)
+}
+
+func oppositeDirection(direction string) string {
+	if direction == utils.BankStatementDebit {
+		return utils.BankStatementCredit
+	}
+	return utils.BankStatementDebit
+}
+
+func amountsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+func statementLineFingerprint(line utils.BankStatementLine) string {
+	raw := fmt.Sprintf("%s|%s|%.2f|%s|%s|%s|%s",
+		line.BookingDate.Format("2006-01-02"), line.Direction, line.Amount, line.Currency,
+		line.CounterpartyIBAN, line.BankReference, line.Reference)
+	// Marked only on reversals so fingerprints of earlier imports stay the same
+	if line.Reversal {
+		raw += "|REVERSAL"
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+func testStatementLine(direction string, amount float64, currency, reference, iban string) *models.BankStatementLine {
+	return &models.BankStatementLine{
+		ID:               "line-1",
+		Direction:        direction,
+		Amount:           amount,
+		Currency:         currency,
+		Reference:        nullIfEmpty(reference),
+		CounterpartyIBAN: nullIfEmpty(iban),
+	}
+}
+
+func TestMatchPayout(t *testing.T) {
+	const cleanerIBAN = "RO49AAAA1B31007593840000"
+	const companyIBAN = "RO66BACX0000001234567890"
+
+	payout := newPayoutCandidate(&models.Payout{ID: "payout-a1", Status: models.PayoutStatusPending, NetAmount: 850.50}, cleanerIBAN)
+	other := newPayoutCandidate(&models.Payout{ID: "payout-b2", Status: models.PayoutStatusPending, NetAmount: 300}, "RO09BCYP0000001234567890")
+	cashFees := newPayoutCandidate(&models.Payout{ID: "payout-c3", Status: models.PayoutStatusInvoiced, NetAmount: -42.30}, cleanerIBAN)
+	company := newCompanyPayoutCandidate(&models.CompanyPayout{ID: "company-d4", Status: models.PayoutStatusPending, NetAmount: 2400}, companyIBAN)
+	payouts := []*payoutCandidate{payout, other, cashFees, company}
+
+	tests := []struct {
+		name string
+		line *models.BankStatementLine
+		want *payoutCandidate
+	}{
+		{
+			name: "payout ID in the reference",
+			line: testStatementLine(utils.BankStatementDebit, 850.50, "RON", "CleanBuddy PAYOUT-A1", ""),
+			want: payout,
+		},
+		{
+			name: "falls back to the cleaner IBAN",
+			line: testStatementLine(utils.BankStatementDebit, 850.50, "RON", "Plata ianuarie", cleanerIBAN),
+			want: payout,
+		},
+		{
+			name: "amount must agree",
+			line: testStatementLine(utils.BankStatementDebit, 850.00, "RON", "payout-a1", cleanerIBAN),
+		},
+		{
+			name: "currency must agree",
+			line: testStatementLine(utils.BankStatementDebit, 850.50, "EUR", "payout-a1", cleanerIBAN),
+		},
+		{
+			name: "missing currency is taken as lei",
+			line: testStatementLine(utils.BankStatementDebit, 850.50, "", "payout-a1", ""),
+			want: payout,
+		},
+		{
+			name: "incoming transfer does not settle a payout",
+			line: testStatementLine(utils.BankStatementCredit, 850.50, "RON", "payout-a1", cleanerIBAN),
+		},
+		{
+			name: "invoiced cash fees paid in by the cleaner",
+			line: testStatementLine(utils.BankStatementCredit, 42.30, "RON", "Comisioane payout-c3", ""),
+			want: cashFees,
+		},
+		{
+			name: "company payout by company IBAN",
+			line: testStatementLine(utils.BankStatementDebit, 2400, "RON", "", companyIBAN),
+			want: company,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchPayout(tt.line, payouts, tt.line.Direction, map[string]bool{})
+			if got != tt.want {
+				t.Errorf("matchPayout() = %v, want %v", candidateID(got), candidateID(tt.want))
+			}
+		})
+	}
+}
+
+func TestMatchPayoutAmbiguousOrUsed(t *testing.T) {
+	const iban = "RO49AAAA1B31007593840000"
+	first := newPayoutCandidate(&models.Payout{ID: "payout-1", Status: models.PayoutStatusPending, NetAmount: 100}, iban)
+	second := newPayoutCandidate(&models.Payout{ID: "payout-2", Status: models.PayoutStatusPending, NetAmount: 100}, iban)
+	payouts := []*payoutCandidate{first, second}
+
+	line := testStatementLine(utils.BankStatementDebit, 100, "RON", "", iban)
+	if got := matchPayout(line, payouts, line.Direction, map[string]bool{}); got != nil {
+		t.Errorf("two payouts to the same IBAN matched %s, want none", got.id())
+	}
+
+	used := map[string]bool{"payout-1": true}
+	if got := matchPayout(line, payouts, line.Direction, used); got != second {
+		t.Errorf("matchPayout() = %v, want payout-2 once payout-1 is used", candidateID(got))
+	}
+}
+
+func TestMatchPayoutReversal(t *testing.T) {
+	sent := newPayoutCandidate(&models.Payout{
+		ID:                "payout-a1",
+		Status:            models.PayoutStatusSent,
+		NetAmount:         500,
+		TransferReference: sql.NullString{String: "BT998878", Valid: true},
+	}, "RO49AAAA1B31007593840000")
+
+	// An RD entry brings the payout back in as a credit
+	line := testStatementLine(utils.BankStatementCredit, 500, "RON", "Retur BT998878", "")
+	line.Reversal = true
+
+	got := matchPayout(line, []*payoutCandidate{sent}, oppositeDirection(line.Direction), map[string]bool{})
+	if got != sent {
+		t.Fatalf("reversal matched %v, want payout-a1", candidateID(got))
+	}
+	if got.match().PayoutID != "payout-a1" {
+		t.Errorf("reversal linked to %+v", got.match())
+	}
+}
+
+func TestPayoutCandidateIsOpen(t *testing.T) {
+	tests := []struct {
+		candidate *payoutCandidate
+		want      bool
+	}{
+		{newPayoutCandidate(&models.Payout{Status: models.PayoutStatusPending}, ""), true},
+		{newPayoutCandidate(&models.Payout{Status: models.PayoutStatusProcessing}, ""), true},
+		{newPayoutCandidate(&models.Payout{Status: models.PayoutStatusInvoiced, NetAmount: -10}, ""), true},
+		{newPayoutCandidate(&models.Payout{Status: models.PayoutStatusFailed}, ""), false},
+		{newPayoutCandidate(&models.Payout{Status: models.PayoutStatusSent}, ""), false},
+		{newCompanyPayoutCandidate(&models.CompanyPayout{Status: models.PayoutStatusFailed}, ""), true},
+		{newCompanyPayoutCandidate(&models.CompanyPayout{Status: models.PayoutStatusSent}, ""), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.candidate.isOpen(); got != tt.want {
+			t.Errorf("isOpen() for %s payout (company %v) = %v, want %v",
+				tt.candidate.status(), tt.candidate.companyPayout != nil, got, tt.want)
+		}
+	}
+}
+
+func TestMatchInvoice(t *testing.T) {
+	invoice := &models.Invoice{ID: "invoice-1", InvoiceNumber: "CB-2025-0042", TotalAmount: 1190, Currency: "RON"}
+	euro := &models.Invoice{ID: "invoice-2", InvoiceNumber: "CB-2025-0043", TotalAmount: 1190, Currency: "EUR"}
+	c := &reconciliationCandidates{
+		invoices:     []*models.Invoice{invoice, euro},
+		usedInvoices: map[string]bool{},
+	}
+
+	tests := []struct {
+		name string
+		line *models.BankStatementLine
+		want *models.Invoice
+	}{
+		{"number with other separators", testStatementLine(utils.BankStatementCredit, 1190, "RON", "Plata factura CB 2025/0042", ""), invoice},
+		{"amount must agree", testStatementLine(utils.BankStatementCredit, 1000, "RON", "CB-2025-0042", ""), nil},
+		{"currency must agree", testStatementLine(utils.BankStatementCredit, 1190, "RON", "CB-2025-0043", ""), nil},
+		{"euro invoice paid in euro", testStatementLine(utils.BankStatementCredit, 1190, "EUR", "CB-2025-0043", ""), euro},
+		{"no reference", testStatementLine(utils.BankStatementCredit, 1190, "RON", "", ""), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchInvoice(tt.line, c); got != tt.want {
+				t.Errorf("matchInvoice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchProforma(t *testing.T) {
+	proforma := &models.Proforma{ID: "proforma-1", ProformaNumber: "PF-2025-0007", AmountDue: 450, Currency: "RON"}
+	c := &reconciliationCandidates{
+		proformas:     []*models.Proforma{proforma},
+		usedProformas: map[string]bool{},
+	}
+
+	line := testStatementLine(utils.BankStatementCredit, 450, "RON", "proforma PF20250007", "")
+	if got := matchProforma(line, c); got != proforma {
+		t.Errorf("matchProforma() = %v, want proforma-1", got)
+	}
+
+	c.usedProformas[proforma.ID] = true
+	if got := matchProforma(line, c); got != nil {
+		t.Errorf("matchProforma() matched a proforma already settled by this import")
+	}
+}
+
+func candidateID(c *payoutCandidate) string {
+	if c == nil {
+		return "<nil>"
+	}
+	return c.id()
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// BillingProfileInput holds the company details of a billing profile. On update, nil fields
+// are left unchanged.
+type BillingProfileInput struct {
+	LegalName          *string
+	CUI                *string
+	RegistrationNumber *string
+	VATPayer           *bool
+	StreetAddress      *string
+	City               *string
+	County             *string
+	PostalCode         *string
+	Email              *string
+	IsDefault          *bool
+}
+
+// BillingProfileService handles the company details clients are invoiced with
+type BillingProfileService struct {
+	billingRepo *models.BillingProfileRepository
+	cuiLookup   CUILookup
+}
+
+// NewBillingProfileService creates a new billing profile service
+func NewBillingProfileService(db *sql.DB, cuiLookup CUILookup) *BillingProfileService {
+	return &BillingProfileService{
+		billingRepo: models.NewBillingProfileRepository(db),
+		cuiLookup:   cuiLookup,
+	}
+}
+
+// GetUserBillingProfiles gets all billing profiles of a user
+func (s *BillingProfileService) GetUserBillingProfiles(userID string) ([]*models.BillingProfile, error) {
+	profiles, err := s.billingRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// GetBillingProfile gets a billing profile owned by userID
+func (s *BillingProfileService) GetBillingProfile(id, userID string) (*models.BillingProfile, error) {
+	profile, err := s.billingRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("billing profile not found")
+	}
+	if profile.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	return profile, nil
+}
+
+// CreateBillingProfile creates a billing profile for a user
+func (s *BillingProfileService) CreateBillingProfile(userID string, input BillingProfileInput) (*models.BillingProfile, error) {
+	profile := &models.BillingProfile{
+		UserID:   userID,
+		Country:  "RO",
+		VATPayer: true,
+	}
+	applyBillingProfileInput(profile, input)
+	if err := validateBillingProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := s.billingRepo.Create(profile); err != nil {
+		return nil, fmt.Errorf("failed to create billing profile: %w", err)
+	}
+	if profile.IsDefault {
+		if err := s.billingRepo.ClearDefault(userID, profile.ID); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// UpdateBillingProfile updates a billing profile owned by userID. Invoices already issued keep
+// the details they were issued with.
+func (s *BillingProfileService) UpdateBillingProfile(id, userID string, input BillingProfileInput) (*models.BillingProfile, error) {
+	profile, err := s.GetBillingProfile(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	applyBillingProfileInput(profile, input)
+	if err := validateBillingProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := s.billingRepo.Update(profile); err != nil {
+		return nil, err
+	}
+	if profile.IsDefault {
+		if err := s.billingRepo.ClearDefault(userID, profile.ID); err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// DeleteBillingProfile deletes a billing profile owned by userID
+func (s *BillingProfileService) DeleteBillingProfile(id, userID string) error {
+	if _, err := s.GetBillingProfile(id, userID); err != nil {
+		return err
+	}
+	return s.billingRepo.Delete(id)
+}
+
+// LookupCUI looks a company up in the VAT registry so clients can fill in a billing profile
+func (s *BillingProfileService) LookupCUI(ctx context.Context, cui string) (*CompanyRegistryInfo, error) {
+	if err := utils.ValidateCUI(cui); err != nil {
+		return nil, fmt.Errorf("invalid CUI: %w", err)
+	}
+	info, err := s.cuiLookup.LookupCUI(ctx, utils.NormalizeCUI(cui))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up CUI: %w", err)
+	}
+	if info == nil {
+		return nil, fmt.Errorf("no company is registered with CUI %s", utils.NormalizeCUI(cui))
+	}
+	return info, nil
+}
+
+func applyBillingProfileInput(profile *models.BillingProfile, input BillingProfileInput) {
+	if input.LegalName != nil {
+		profile.LegalName = strings.TrimSpace(*input.LegalName)
+	}
+	if input.CUI != nil {
+		profile.CUI = utils.NormalizeCUI(*input.CUI)
+	}
+	if input.RegistrationNumber != nil {
+		profile.RegistrationNumber = optionalString(*input.RegistrationNumber)
+	}
+	if input.VATPayer != nil {
+		profile.VATPayer = *input.VATPayer
+	}
+	if input.StreetAddress != nil {
+		profile.StreetAddress = strings.TrimSpace(*input.StreetAddress)
+	}
+	if input.City != nil {
+		profile.City = strings.TrimSpace(*input.City)
+	}
+	if input.County != nil {
+		profile.County = strings.TrimSpace(*input.County)
+	}
+	if input.PostalCode != nil {
+		profile.PostalCode = optionalString(*input.PostalCode)
+	}
+	if input.Email != nil {
+		profile.Email = optionalString(*input.Email)
+	}
+	if input.IsDefault != nil {
+		profile.IsDefault = *input.IsDefault
+	}
+}
+
+// validateBillingProfile checks a profile has what e-Factura requires of a buyer
+func validateBillingProfile(profile *models.BillingProfile) error {
+	if profile.LegalName == "" {
+		return fmt.Errorf("legal name is required")
+	}
+	if err := utils.ValidateCUI(profile.CUI); err != nil {
+		return fmt.Errorf("invalid CUI: %w", err)
+	}
+	if profile.StreetAddress == "" || profile.City == "" {
+		return fmt.Errorf("billing address is required")
+	}
+	if _, ok := utils.RomanianCountyCode(profile.County); !ok {
+		return fmt.Errorf("unknown county: %s", profile.County)
+	}
+	return nil
+}
+
+func optionalString(value string) sql.NullString {
+	value = strings.TrimSpace(value)
+	return sql.NullString{String: value, Valid: value != ""}
+}
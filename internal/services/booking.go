@@ -14,17 +14,21 @@ import (
 
 // BookingService handles booking business logic
 type BookingService struct {
-	bookingRepo     *models.BookingRepository
-	cleanerRepo     *models.CleanerRepository
-	addressRepo     *models.AddressRepository
-	clientRepo      *models.ClientRepository
-	userRepo        *models.UserRepository
-	pricingService  *PricingService
-	invoiceService  *InvoiceService
-	paymentService  *PaymentService
-	matchingService *CleanerMatchingService
-	emailService    *EmailService
-	cfg             *config.Config
+	bookingRepo      *models.BookingRepository
+	cleanerRepo      *models.CleanerRepository
+	addressRepo      *models.AddressRepository
+	billingRepo      *models.BillingProfileRepository
+	clientRepo       *models.ClientRepository
+	userRepo         *models.UserRepository
+	pricingService   *PricingService
+	invoiceService   *InvoiceService
+	paymentService   *PaymentService
+	matchingService  *CleanerMatchingService
+	walletService    *WalletService
+	feePolicyService *FeePolicyService
+	proformaService  *ProformaService
+	emailService     *EmailService
+	cfg              *config.Config
 }
 
 // NewBookingService creates a new booking service
@@ -33,6 +37,7 @@ func NewBookingService(db *sql.DB, pricingService *PricingService, invoiceServic
 		bookingRepo:     models.NewBookingRepository(db),
 		cleanerRepo:     models.NewCleanerRepository(db),
 		addressRepo:     models.NewAddressRepository(db),
+		billingRepo:     models.NewBillingProfileRepository(db),
 		clientRepo:      models.NewClientRepository(db),
 		userRepo:        models.NewUserRepository(db),
 		pricingService:  pricingService,
@@ -44,6 +49,11 @@ func NewBookingService(db *sql.DB, pricingService *PricingService, invoiceServic
 	}
 }
 
+// SetFeePolicyService sets the fee policy used to fix the platform fee on cleaner assignment
+func (s *BookingService) SetFeePolicyService(feePolicyService *FeePolicyService) {
+	s.feePolicyService = feePolicyService
+}
+
 // SetPaymentService sets the payment service (to break circular dependency)
 func (s *BookingService) SetPaymentService(paymentService *PaymentService) {
 	s.paymentService = paymentService
@@ -54,6 +64,16 @@ func (s *BookingService) SetMatchingService(matchingService *CleanerMatchingServ
 	s.matchingService = matchingService
 }
 
+// SetWalletService sets the wallet service used to pay bookings with store credit
+func (s *BookingService) SetWalletService(walletService *WalletService) {
+	s.walletService = walletService
+}
+
+// SetProformaService sets the service that issues proformas for bookings paid by bank transfer
+func (s *BookingService) SetProformaService(proformaService *ProformaService) {
+	s.proformaService = proformaService
+}
+
 // generateReservationCode generates a unique reservation code in format CB-YYYY-XXXXXX
 func (s *BookingService) generateReservationCode() (string, error) {
 	year := time.Now().Year()
@@ -95,6 +115,7 @@ func (s *BookingService) CreateBooking(
 	supplies string, // Required: "client_provides" or "cleaner_provides"
 	timePreferences string,
 	frequency string,
+	billingProfileID string, // Optional: company to invoice instead of the client
 ) (*models.Booking, error) {
 	// Validate supplies
 	if supplies != "client_provides" && supplies != "cleaner_provides" {
@@ -112,6 +133,17 @@ func (s *BookingService) CreateBooking(
 		return nil, fmt.Errorf("address does not belong to client")
 	}
 
+	// Validate billing profile belongs to client
+	if billingProfileID != "" {
+		profile, err := s.billingRepo.GetByID(billingProfileID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get billing profile: %w", err)
+		}
+		if profile == nil || profile.UserID != clientID {
+			return nil, fmt.Errorf("billing profile not found")
+		}
+	}
+
 	// Validate scheduling (only if fixed date/time is provided, not for flexible scheduling)
 	if !scheduledDate.IsZero() && !scheduledTime.IsZero() {
 		if err := s.validateScheduling(scheduledDate, scheduledTime); err != nil {
@@ -167,6 +199,8 @@ func (s *BookingService) CreateBooking(
 		AddonsPrice:             quote.AddonsPrice,
 		TotalPrice:              quote.TotalPrice,
 		PlatformFee:             quote.PlatformFee,
+		PlatformFeeRate:         quote.Breakdown.PlatformFeePercentage,
+		PlatformFeeRule:         quote.PlatformFeeRule,
 		CleanerPayout:           quote.CleanerPayout,
 		DiscountApplied:         quote.Discount,
 		Status:                  models.BookingStatusPending,
@@ -196,10 +230,21 @@ func (s *BookingService) CreateBooking(
 		booking.Frequency = sql.NullString{String: frequency, Valid: true}
 	}
 
+	if billingProfileID != "" {
+		booking.BillingProfileID = sql.NullString{String: billingProfileID, Valid: true}
+	}
+
 	if err := s.bookingRepo.Create(booking); err != nil {
 		return nil, fmt.Errorf("failed to create booking: %w", err)
 	}
 
+	// Spend wallet credit first; the card preauthorization only covers the remainder
+	if s.walletService != nil {
+		if _, err := s.walletService.ApplyCreditToBooking(booking); err != nil {
+			fmt.Printf("Warning: failed to apply wallet credit to booking %s: %v\n", booking.ID, err)
+		}
+	}
+
 	// Send booking confirmation email to client (async, don't fail if email fails)
 	go func() {
 		ctx := context.Background()
@@ -377,6 +422,7 @@ func (s *BookingService) ConfirmBooking(bookingID string, cleanerID string) (*mo
 	if err := s.bookingRepo.Update(booking); err != nil {
 		return nil, fmt.Errorf("failed to confirm booking: %w", err)
 	}
+	s.issueProforma(booking)
 
 	// Notify client about confirmation
 	s.notifyBookingConfirmed(booking)
@@ -430,9 +476,8 @@ func (s *BookingService) CancelBooking(bookingID string, userID string, reason s
 	)
 
 	freeWindow := time.Duration(s.cfg.Booking.CancellationFreeHours) * time.Hour
-	if time.Now().Add(freeWindow).After(scheduled) {
-		// TODO: Apply cancellation fee (future feature)
-		// For now, we just note it in the reason
+	lateCancellation := time.Now().Add(freeWindow).After(scheduled)
+	if lateCancellation {
 		reason = fmt.Sprintf("[Late cancellation] %s", reason)
 	}
 
@@ -503,7 +548,8 @@ func (s *BookingService) CancelBooking(bookingID string, userID string, reason s
 		}
 	}()
 
-	// TODO: Process refund if applicable
+	// Release hold or refund payment (cancellation fee only applies to late client cancellations)
+	s.processCancellationRefund(booking, lateCancellation && userID == booking.ClientID)
 
 	return booking, nil
 }
@@ -537,16 +583,23 @@ func (s *BookingService) AssignCleaner(bookingID string, cleanerID string) (*mod
 	if !cleaner.IsActive || !cleaner.IsAvailable {
 		return nil, fmt.Errorf("cleaner is not available")
 	}
+	if err := s.checkCashJobLimit(booking, cleaner.ID); err != nil {
+		return nil, err
+	}
 
 	// Assign cleaner
 	booking.CleanerID = sql.NullString{String: cleanerID, Valid: true}
 	booking.Status = models.BookingStatusConfirmed
+	if err := s.applyPlatformFee(booking, cleaner); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	booking.ConfirmedAt = sql.NullTime{Time: now, Valid: true}
 
 	if err := s.bookingRepo.Update(booking); err != nil {
 		return nil, fmt.Errorf("failed to assign cleaner: %w", err)
 	}
+	s.issueProforma(booking)
 
 	// Notify client and cleaner about assignment
 	s.notifyCleanerAssigned(booking)
@@ -627,10 +680,15 @@ func (s *BookingService) CompleteBooking(bookingID string, cleanerID string) (*m
 
 	// Auto-create invoice for completed booking
 	if s.invoiceService != nil {
-		_, err := s.invoiceService.CreateInvoiceForBooking(bookingID)
+		invoice, err := s.invoiceService.CreateInvoiceForBooking(bookingID)
 		if err != nil {
 			// Log error but don't fail the completion
 			fmt.Printf("Warning: failed to create invoice for booking %s: %v\n", bookingID, err)
+		} else if s.proformaService != nil {
+			// The invoice replaces the proforma of a company paying by bank transfer
+			if err := s.proformaService.ConvertToInvoice(bookingID, invoice); err != nil {
+				fmt.Printf("Warning: failed to convert proforma for booking %s: %v\n", bookingID, err)
+			}
 		}
 	}
 
@@ -655,13 +713,20 @@ func (s *BookingService) CompleteBooking(bookingID string, cleanerID string) (*m
 		}
 	}()
 
+	// Credit spent on the booking is earned now that the service is delivered
+	if s.walletService != nil {
+		s.walletService.RecognizeRedemption(booking)
+	}
+
 	// Trigger payment capture for authorized payments
-	if s.paymentService != nil {
+	if s.paymentService != nil && s.cfg.Payment.CaptureOnCompletion {
 		payments, err := s.paymentService.GetPaymentsByBooking(bookingID, booking.ClientID)
 		if err == nil && len(payments) > 0 {
 			// Find authorized payment
 			for _, payment := range payments {
-				if payment.Status == models.PaymentStatusAuthorized {
+				// Cash is confirmed by the cleaner at check-out and bank transfers when the money arrives, not captured here
+				if payment.Status == models.PaymentStatusAuthorized && payment.Provider != models.PaymentProviderCash &&
+					payment.Provider != models.PaymentProviderBankTransfer {
 					_, err := s.paymentService.CapturePayment(payment.ID)
 					if err != nil {
 						// Log error but don't fail the completion
@@ -749,16 +814,23 @@ func (s *BookingService) AcceptBooking(bookingID string, cleanerID string) (*mod
 	if !cleaner.IsActive || !cleaner.IsAvailable {
 		return nil, fmt.Errorf("cleaner is not available")
 	}
+	if err := s.checkCashJobLimit(booking, cleaner.ID); err != nil {
+		return nil, err
+	}
 
 	// Assign cleaner and confirm booking
 	booking.CleanerID = sql.NullString{String: cleaner.ID, Valid: true}
 	booking.Status = models.BookingStatusConfirmed
+	if err := s.applyPlatformFee(booking, cleaner); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	booking.ConfirmedAt = sql.NullTime{Time: now, Valid: true}
 
 	if err := s.bookingRepo.Update(booking); err != nil {
 		return nil, fmt.Errorf("failed to accept booking: %w", err)
 	}
+	s.issueProforma(booking)
 
 	// Send booking accepted email to client (async)
 	go func() {
@@ -796,6 +868,41 @@ func (s *BookingService) AcceptBooking(bookingID string, cleanerID string) (*mod
 	return booking, nil
 }
 
+// issueProforma issues the proforma of a booking paid by bank transfer once it is confirmed.
+// Failures are logged; the proforma can be issued again later.
+func (s *BookingService) issueProforma(booking *models.Booking) {
+	if s.proformaService == nil || !booking.BillingProfileID.Valid {
+		return
+	}
+	if _, err := s.proformaService.IssueForBooking(booking.ID); err != nil {
+		fmt.Printf("Warning: failed to issue proforma for booking %s: %v\n", booking.ID, err)
+	}
+}
+
+// checkCashJobLimit stops a cleaner from taking a cash booking beyond payment.cash_max_jobs_per_month
+func (s *BookingService) checkCashJobLimit(booking *models.Booking, cleanerID string) error {
+	if s.paymentService == nil {
+		return nil
+	}
+	cashPayment, err := s.paymentService.GetPendingCashPayment(booking.ID)
+	if err != nil || cashPayment == nil {
+		return err
+	}
+	return s.paymentService.CheckCashJobLimit(booking, cleanerID)
+}
+
+// applyPlatformFee fixes the booking's platform fee for the cleaner taking the job
+func (s *BookingService) applyPlatformFee(booking *models.Booking, cleaner *models.Cleaner) error {
+	// The split is final once the booking is completed and its payment captured
+	if s.feePolicyService == nil || booking.Status == models.BookingStatusCompleted {
+		return nil
+	}
+	if err := s.feePolicyService.Apply(booking, cleaner); err != nil {
+		return fmt.Errorf("failed to apply platform fee: %w", err)
+	}
+	return nil
+}
+
 // AcceptBookingWithTime allows a cleaner to accept a job and optionally set the scheduled time
 func (s *BookingService) AcceptBookingWithTime(bookingID string, cleanerID string, scheduledDate *time.Time, scheduledTime *time.Time) (*models.Booking, error) {
 	booking, err := s.bookingRepo.GetByID(bookingID)
@@ -839,16 +946,23 @@ func (s *BookingService) AcceptBookingWithTime(bookingID string, cleanerID strin
 		booking.ScheduledTime = *scheduledTime
 	}
 	// If neither is provided, keep existing values (or they remain as zero values)
+	if err := s.checkCashJobLimit(booking, cleaner.ID); err != nil {
+		return nil, err
+	}
 
 	// Assign cleaner and confirm booking
 	booking.CleanerID = sql.NullString{String: cleaner.ID, Valid: true}
 	booking.Status = models.BookingStatusConfirmed
+	if err := s.applyPlatformFee(booking, cleaner); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	booking.ConfirmedAt = sql.NullTime{Time: now, Valid: true}
 
 	if err := s.bookingRepo.Update(booking); err != nil {
 		return nil, fmt.Errorf("failed to accept booking: %w", err)
 	}
+	s.issueProforma(booking)
 
 	// Send booking accepted email to client (async)
 	go func() {
@@ -931,9 +1045,15 @@ func (s *BookingService) AdminReassignBooking(bookingID string, cleanerID string
 	if cleaner.ApprovalStatus != models.ApprovalStatusApproved {
 		return nil, fmt.Errorf("cleaner is not approved")
 	}
+	if err := s.checkCashJobLimit(booking, cleaner.ID); err != nil {
+		return nil, err
+	}
 
 	// Update the booking
 	booking.CleanerID = sql.NullString{String: cleanerID, Valid: true}
+	if err := s.applyPlatformFee(booking, cleaner); err != nil {
+		return nil, err
+	}
 	if err := s.bookingRepo.Update(booking); err != nil {
 		return nil, fmt.Errorf("failed to reassign booking: %w", err)
 	}
@@ -963,6 +1083,9 @@ func (s *BookingService) AdminCancelBooking(bookingID string, reason string) (*m
 		return nil, fmt.Errorf("failed to cancel booking: %w", err)
 	}
 
+	// Admin cancellations are always refunded in full
+	s.processCancellationRefund(booking, false)
+
 	return booking, nil
 }
 
@@ -1083,6 +1206,9 @@ func (s *BookingService) ExpireOldPendingBookings(expirationHours int) (int, err
 
 		expiredCount++
 
+		// Release any payment hold placed for the booking
+		s.processCancellationRefund(booking, false)
+
 		// Notify client about booking expiration
 		s.notifyBookingExpired(booking)
 	}
@@ -1148,9 +1274,14 @@ func (s *BookingService) notifyBookingExpired(booking *models.Booking) {
 	}
 
 	go func() {
-		message := fmt.Sprintf("Your booking #%s has been automatically cancelled due to no cleaner availability.\n\nScheduled Date: %s\n\nWe apologize for the inconvenience. You have not been charged. Please try booking again or contact our support team.\n\nBest regards,\nCleanBuddy Team",
+		paymentNote := "You have not been charged."
+		if booking.RefundAmount > 0 {
+			paymentNote = fmt.Sprintf("%.2f RON will be returned to your card (%s).", booking.RefundAmount, booking.RefundStatus.String)
+		}
+		message := fmt.Sprintf("Your booking #%s has been automatically cancelled due to no cleaner availability.\n\nScheduled Date: %s\n\nWe apologize for the inconvenience. %s Please try booking again or contact our support team.\n\nBest regards,\nCleanBuddy Team",
 			booking.ID,
 			booking.ScheduledDate.Format("2006-01-02"),
+			paymentNote,
 		)
 		fmt.Printf("📧 Would send booking expired email to client:\n%s\n", message)
 		// s.emailService.SendEmail(clientEmail, "Booking Cancelled - CleanBuddy", message)
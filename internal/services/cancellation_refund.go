@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// CancellationRefund is the outcome of refund processing for a cancelled booking
+type CancellationRefund struct {
+	Status models.RefundStatus
+	Amount float64
+}
+
+// processCancellationRefund releases authorized holds and refunds captured payments for a cancelled booking.
+// Late client cancellations keep booking.late_cancellation_fee_percentage of the price; every other
+// cancellation (cleaner, admin, expiry, or client outside the late window) is refunded in full.
+// Captured payments older than payment.refund_window_days are not refunded automatically.
+// Wallet credit spent on the booking goes back to the wallet under the same fee rule.
+func (s *BookingService) processCancellationRefund(booking *models.Booking, lateClientCancellation bool) *CancellationRefund {
+	if s.walletService != nil {
+		feePercentage := 0.0
+		if lateClientCancellation {
+			feePercentage = s.cfg.Booking.LateCancellationFeePercentage
+		}
+		if err := s.walletService.ReleaseBookingCredit(booking, feePercentage); err != nil {
+			fmt.Printf("Warning: failed to return wallet credit for booking %s: %v\n", booking.ID, err)
+		}
+	}
+
+	if s.paymentService == nil {
+		return nil
+	}
+
+	result := s.applyCancellationRefund(booking, lateClientCancellation)
+
+	if err := s.bookingRepo.UpdateRefundOutcome(booking.ID, result.Status, result.Amount); err != nil {
+		fmt.Printf("Warning: failed to record refund outcome for booking %s: %v\n", booking.ID, err)
+	}
+	booking.RefundStatus = sql.NullString{String: string(result.Status), Valid: true}
+	booking.RefundAmount = result.Amount
+	booking.RefundProcessedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	if result.Status != models.RefundStatusNoPayment {
+		s.notifyRefundProcessed(booking, result)
+	}
+
+	return result
+}
+
+func (s *BookingService) applyCancellationRefund(booking *models.Booking, lateClientCancellation bool) *CancellationRefund {
+	payments, err := s.paymentService.GetPaymentsByBooking(booking.ID, booking.ClientID)
+	if err != nil {
+		fmt.Printf("Warning: failed to load payments for cancelled booking %s: %v\n", booking.ID, err)
+		return &CancellationRefund{Status: models.RefundStatusFailed}
+	}
+
+	feePercentage := 0.0
+	if lateClientCancellation {
+		feePercentage = s.cfg.Booking.LateCancellationFeePercentage
+	}
+
+	alreadyRefunded := 0.0
+	for _, payment := range payments {
+		if payment.PaymentType == models.PaymentTypeRefund && payment.Status == models.PaymentStatusRefunded {
+			alreadyRefunded += payment.Amount
+		}
+	}
+
+	var released, refunded, charged float64
+	var failed, notEligible bool
+	refundReason := fmt.Sprintf("Booking %s cancelled", booking.ID)
+
+	for _, payment := range payments {
+		if payment.PaymentType != models.PaymentTypePreauthorization {
+			continue
+		}
+
+		switch payment.Status {
+		case models.PaymentStatusAuthorized:
+			// Cash or a transfer that never arrived: close the payment without counting it as charged
+			if payment.Provider == models.PaymentProviderCash || payment.Provider == models.PaymentProviderBankTransfer {
+				if _, err := s.paymentService.CancelPreauthorization(payment.ID); err != nil {
+					fmt.Printf("Warning: failed to cancel unpaid payment %s for booking %s: %v\n", payment.ID, booking.ID, err)
+					failed = true
+				}
+				continue
+			}
+
+			charged += payment.Amount
+			if feePercentage <= 0 {
+				if _, err := s.paymentService.CancelPreauthorization(payment.ID); err != nil {
+					fmt.Printf("Warning: failed to release hold %s for booking %s: %v\n", payment.ID, booking.ID, err)
+					failed = true
+					continue
+				}
+				released += payment.Amount
+				continue
+			}
+
+			// Keep the cancellation fee: capture the hold, then refund the rest
+			if _, err := s.paymentService.CapturePayment(payment.ID); err != nil {
+				fmt.Printf("Warning: failed to capture hold %s for late cancellation of booking %s: %v\n", payment.ID, booking.ID, err)
+				failed = true
+				continue
+			}
+			amount := roundToCents(payment.Amount * (1 - feePercentage/100))
+			if amount <= 0 {
+				continue
+			}
+			if _, err := s.paymentService.RefundPayment(payment.ID, amount, refundReason); err != nil {
+				fmt.Printf("Warning: failed to refund payment %s for booking %s: %v\n", payment.ID, booking.ID, err)
+				failed = true
+				continue
+			}
+			refunded += amount
+
+		case models.PaymentStatusCaptured:
+			charged += payment.Amount
+			if !s.withinRefundWindow(payment) {
+				notEligible = true
+				continue
+			}
+
+			var amount float64
+			amount, alreadyRefunded = cancellationRefundDue(payment.Amount, feePercentage, alreadyRefunded)
+			if amount <= 0 {
+				continue
+			}
+			if _, err := s.paymentService.RefundPayment(payment.ID, amount, refundReason); err != nil {
+				fmt.Printf("Warning: failed to refund payment %s for booking %s: %v\n", payment.ID, booking.ID, err)
+				failed = true
+				continue
+			}
+			refunded += amount
+		}
+	}
+
+	returned := roundToCents(released + refunded)
+
+	switch {
+	case charged == 0:
+		return &CancellationRefund{Status: models.RefundStatusNoPayment}
+	case failed:
+		return &CancellationRefund{Status: models.RefundStatusFailed, Amount: returned}
+	case notEligible && returned == 0:
+		return &CancellationRefund{Status: models.RefundStatusNotEligible}
+	case returned < roundToCents(charged):
+		return &CancellationRefund{Status: models.RefundStatusPartiallyRefunded, Amount: returned}
+	case refunded == 0:
+		return &CancellationRefund{Status: models.RefundStatusHoldReleased, Amount: returned}
+	default:
+		return &CancellationRefund{Status: models.RefundStatusRefunded, Amount: returned}
+	}
+}
+
+// cancellationRefundDue returns how much of a captured payment to refund after the cancellation fee.
+// Refunds already made on the booking are not linked to a capture, so they are offset against the
+// captures in order; the unused part of alreadyRefunded is returned for the next capture.
+func cancellationRefundDue(paymentAmount, feePercentage, alreadyRefunded float64) (float64, float64) {
+	due := roundToCents(paymentAmount * (1 - feePercentage/100))
+	offset := math.Min(alreadyRefunded, due)
+	return roundToCents(due - offset), roundToCents(alreadyRefunded - offset)
+}
+
+// withinRefundWindow reports whether a captured payment can still be refunded automatically
+func (s *BookingService) withinRefundWindow(payment *models.Payment) bool {
+	if s.cfg.Payment.RefundWindowDays <= 0 || !payment.CapturedAt.Valid {
+		return true
+	}
+	deadline := payment.CapturedAt.Time.AddDate(0, 0, s.cfg.Payment.RefundWindowDays)
+	return time.Now().Before(deadline)
+}
+
+// notifyRefundProcessed emails the client what happened to their payment
+func (s *BookingService) notifyRefundProcessed(booking *models.Booking, refund *CancellationRefund) {
+	if s.emailService == nil {
+		return
+	}
+
+	go func() {
+		clientUser, err := s.userRepo.GetByID(booking.ClientID)
+		if err != nil || clientUser == nil || !clientUser.Email.Valid {
+			return
+		}
+
+		clientName := "Client"
+		if clientUser.FirstName.Valid {
+			clientName = clientUser.FirstName.String
+		}
+
+		err = s.emailService.SendBookingRefundEmail(
+			context.Background(),
+			clientUser.Email.String,
+			clientName,
+			booking.ID,
+			string(refund.Status),
+			refund.Amount,
+		)
+		if err != nil {
+			fmt.Printf("Warning: failed to send refund email for booking %s: %v\n", booking.ID, err)
+		}
+	}()
+}
+
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,63 @@
+package services
+
+import "testing"
+
+func TestCancellationRefundDue(t *testing.T) {
+	tests := []struct {
+		name            string
+		captures        []float64
+		feePercentage   float64
+		alreadyRefunded float64
+		want            []float64
+	}{
+		{
+			name:     "full refund of a single capture",
+			captures: []float64{200},
+			want:     []float64{200},
+		},
+		{
+			name:          "late cancellation keeps the fee",
+			captures:      []float64{200},
+			feePercentage: 50,
+			want:          []float64{100},
+		},
+		{
+			name:            "earlier refund offsets the first capture",
+			captures:        []float64{200, 50},
+			alreadyRefunded: 30,
+			want:            []float64{170, 50},
+		},
+		{
+			name:            "earlier refund larger than the first capture carries over",
+			captures:        []float64{100, 80},
+			alreadyRefunded: 150,
+			want:            []float64{0, 30},
+		},
+		{
+			name:            "fee and earlier refund across captures",
+			captures:        []float64{100, 100},
+			feePercentage:   50,
+			alreadyRefunded: 70,
+			want:            []float64{0, 30},
+		},
+		{
+			name:            "everything already refunded",
+			captures:        []float64{100, 100},
+			alreadyRefunded: 200,
+			want:            []float64{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining := tt.alreadyRefunded
+			for i, capture := range tt.captures {
+				var got float64
+				got, remaining = cancellationRefundDue(capture, tt.feePercentage, remaining)
+				if got != tt.want[i] {
+					t.Errorf("capture %d: refund = %.2f, want %.2f", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ type CheckinService struct {
 	addressRepo      *models.AddressRepository
 	cleanerRepo      *models.CleanerRepository
 	bookingService   *BookingService
+	paymentService   *PaymentService
 	geocodingService *GeocodingService
 }
 
@@ -31,6 +32,11 @@ func NewCheckinService(db *sql.DB, bookingService *BookingService) *CheckinServi
 	}
 }
 
+// SetPaymentService sets the payment service used to confirm cash payments at check-out
+func (s *CheckinService) SetPaymentService(paymentService *PaymentService) {
+	s.paymentService = paymentService
+}
+
 // CheckIn creates a check-in record and starts the booking
 func (s *CheckinService) CheckIn(bookingID string, cleanerID string, latitude, longitude float64) (*models.Checkin, error) {
 	// Get booking
@@ -146,8 +152,9 @@ func (s *CheckinService) CheckIn(bookingID string, cleanerID string, latitude, l
 	return checkin, nil
 }
 
-// CheckOut updates check-out time and completes the booking
-func (s *CheckinService) CheckOut(bookingID string, cleanerID string, latitude, longitude float64) (*models.Checkin, error) {
+// CheckOut updates check-out time and completes the booking.
+// For cash bookings the cleaner must report whether the cash was received.
+func (s *CheckinService) CheckOut(bookingID string, cleanerID string, latitude, longitude float64, cashReceived *bool) (*models.Checkin, error) {
 	// Get existing check-in
 	checkin, err := s.checkinRepo.GetByBookingID(bookingID)
 	if err != nil {
@@ -195,6 +202,22 @@ func (s *CheckinService) CheckOut(bookingID string, cleanerID string, latitude,
 		return nil, fmt.Errorf("booking must be in IN_PROGRESS status to check out")
 	}
 
+	// Confirm cash collected on site
+	if s.paymentService != nil {
+		cashPayment, err := s.paymentService.GetPendingCashPayment(bookingID)
+		if err != nil {
+			return nil, err
+		}
+		if cashPayment != nil {
+			if cashReceived == nil {
+				return nil, fmt.Errorf("this booking is paid in cash: confirm whether the cash was received")
+			}
+			if _, err := s.paymentService.ConfirmCashPayment(cashPayment, *cashReceived); err != nil {
+				return nil, fmt.Errorf("failed to confirm cash payment: %w", err)
+			}
+		}
+	}
+
 	// Calculate hours worked
 	now := time.Now()
 	hoursWorked := now.Sub(checkin.CheckInTime.Time).Hours()
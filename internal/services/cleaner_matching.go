@@ -17,6 +17,7 @@ type CleanerMatchingService struct {
 	bookingRepo       *models.BookingRepository
 	addressRepo       *models.AddressRepository
 	emailService      *EmailService
+	proformaService   *ProformaService
 }
 
 // NewCleanerMatchingService creates a new cleaner matching service
@@ -30,6 +31,11 @@ func NewCleanerMatchingService(db *sql.DB, emailService *EmailService) *CleanerM
 	}
 }
 
+// SetProformaService sets the service that issues proformas for auto-assigned bookings paid by bank transfer
+func (s *CleanerMatchingService) SetProformaService(proformaService *ProformaService) {
+	s.proformaService = proformaService
+}
+
 // CleanerMatch represents a cleaner with their match score
 type CleanerMatch struct {
 	Cleaner           *models.Cleaner
@@ -471,6 +477,12 @@ func (s *CleanerMatchingService) AutoAssignBestCleaner(bookingID string) (*model
 		return nil, fmt.Errorf("failed to assign cleaner: %w", err)
 	}
 
+	if s.proformaService != nil && booking.BillingProfileID.Valid {
+		if _, err := s.proformaService.IssueForBooking(booking.ID); err != nil {
+			fmt.Printf("Warning: failed to issue proforma for booking %s: %v\n", booking.ID, err)
+		}
+	}
+
 	return bestMatch.Cleaner, nil
 }
 
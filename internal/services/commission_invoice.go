@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// CommissionInvoiceService invoices the platform fees retained from payouts: one invoice per
+// recipient and month, addressed to the company for cleaners who work for one
+type CommissionInvoiceService struct {
+	repo              *models.CommissionInvoiceRepository
+	payoutRepo        *models.PayoutRepository
+	lineItemRepo      *models.PayoutLineItemRepository
+	cleanerRepo       *models.CleanerRepository
+	userRepo          *models.UserRepository
+	companyRepo       *models.CompanyRepository
+	companyPayoutRepo *models.CompanyPayoutRepository
+	companyAdminRepo  *models.CompanyAdminRepository
+	selfBillingRepo   *models.SelfBillingRepository
+	xmlGenerator      *XMLGenerator
+	pdfGenerator      *PDFGenerator
+	anafClient        *ANAFClient
+	anafConfig        *config.ANAFConfig
+	config            *config.CompanyConfig
+}
+
+// NewCommissionInvoiceService creates a new commission invoice service
+func NewCommissionInvoiceService(db *sql.DB, companyConfig *config.CompanyConfig, anafConfig *config.ANAFConfig) *CommissionInvoiceService {
+	return &CommissionInvoiceService{
+		repo:              models.NewCommissionInvoiceRepository(db),
+		payoutRepo:        models.NewPayoutRepository(db),
+		lineItemRepo:      models.NewPayoutLineItemRepository(db),
+		cleanerRepo:       models.NewCleanerRepository(db),
+		userRepo:          models.NewUserRepository(db),
+		companyRepo:       models.NewCompanyRepository(db),
+		companyPayoutRepo: models.NewCompanyPayoutRepository(db),
+		companyAdminRepo:  models.NewCompanyAdminRepository(db),
+		selfBillingRepo:   models.NewSelfBillingRepository(db),
+		xmlGenerator:      NewXMLGenerator("./invoices/xml", companyConfig),
+		pdfGenerator:      NewPDFGenerator("./invoices/pdf"),
+		anafClient:        NewANAFClient(anafConfig, companyConfig),
+		anafConfig:        anafConfig,
+		config:            companyConfig,
+	}
+}
+
+// commissionRecipient collects the payouts invoiced together
+type commissionRecipient struct {
+	invoice   *models.CommissionInvoice
+	payoutIDs []string
+	fees      []commissionFee
+}
+
+type commissionFee struct {
+	cleanerName string
+	bookings    int
+	amount      float64
+}
+
+// GenerateForPeriod issues the commission invoices for a month's payouts that have not been
+// invoiced yet. It is safe to run again: invoiced payouts are skipped. A recipient that fails
+// is reported as a warning and picked up by the next run.
+func (s *CommissionInvoiceService) GenerateForPeriod(year int, month time.Month) ([]*models.CommissionInvoice, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("invalid month: %d", month)
+	}
+	periodStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0).Add(-time.Second)
+
+	payouts, err := s.payoutRepo.GetWithoutCommissionInvoice(periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payouts to invoice: %w", err)
+	}
+
+	var order []string
+	recipients := map[string]*commissionRecipient{}
+	for _, payout := range payouts {
+		key, recipient, err := s.recipientFor(payout, periodStart, periodEnd, recipients)
+		if err != nil {
+			fmt.Printf("Warning: commission for payout %s not invoiced: %v\n", payout.ID, err)
+			continue
+		}
+		if _, ok := recipients[key]; !ok {
+			recipients[key] = recipient
+			order = append(order, key)
+		}
+
+		fee, err := s.payoutFee(payout)
+		if err != nil {
+			fmt.Printf("Warning: commission for payout %s not invoiced: %v\n", payout.ID, err)
+			continue
+		}
+		if fee.amount <= 0 {
+			continue
+		}
+		recipient.payoutIDs = append(recipient.payoutIDs, payout.ID)
+		recipient.fees = append(recipient.fees, *fee)
+	}
+
+	var invoices []*models.CommissionInvoice
+	for _, key := range order {
+		recipient := recipients[key]
+		if len(recipient.payoutIDs) == 0 {
+			continue
+		}
+		if err := s.issue(recipient); err != nil {
+			fmt.Printf("Warning: failed to issue commission invoice to %s: %v\n", recipient.invoice.CustomerName, err)
+			continue
+		}
+		invoices = append(invoices, recipient.invoice)
+	}
+	return invoices, nil
+}
+
+// GetInvoicesForUser returns the commission invoices of a cleaner and of the companies they administer
+func (s *CommissionInvoiceService) GetInvoicesForUser(userID string, limit, offset int) ([]*models.CommissionInvoice, error) {
+	var cleanerID string
+	cleaner, err := s.cleanerRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner != nil {
+		cleanerID = cleaner.ID
+	}
+
+	admins, err := s.companyAdminRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get companies: %w", err)
+	}
+	companyIDs := make([]string, len(admins))
+	for i, admin := range admins {
+		companyIDs[i] = admin.CompanyID
+	}
+
+	if cleanerID == "" && len(companyIDs) == 0 {
+		return []*models.CommissionInvoice{}, nil
+	}
+	return s.repo.GetByRecipients(cleanerID, companyIDs, limit, offset)
+}
+
+// SubmitToANAF uploads a commission invoice to ANAF e-Factura
+func (s *CommissionInvoiceService) SubmitToANAF(invoiceID string) error {
+	invoice, err := s.repo.GetByID(invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get commission invoice: %w", err)
+	}
+	if invoice == nil {
+		return fmt.Errorf("commission invoice not found")
+	}
+	if invoice.ANAFStatus == models.ANAFStatusAccepted || invoice.ANAFStatus == models.ANAFStatusProcessing {
+		return fmt.Errorf("invoice already submitted to ANAF")
+	}
+	if !invoice.XmlURL.Valid {
+		return fmt.Errorf("invoice has no XML to submit")
+	}
+
+	xmlContent, err := s.xmlGenerator.ReadXML(invoice.XmlURL.String)
+	if err != nil {
+		return fmt.Errorf("failed to read XML: %w", err)
+	}
+
+	// Don't upload what ANAF would reject: store the violations as the document's errors instead
+	if validationErrors := ValidateUBL(xmlContent); len(validationErrors) > 0 {
+		invoice.ANAFStatus = models.ANAFStatusRejected
+		invoice.ANAFErrors = validationErrors
+		if err := s.repo.UpdateANAFStatus(invoice); err != nil {
+			return fmt.Errorf("failed to update ANAF status after validation: %w", err)
+		}
+		return fmt.Errorf("commission invoice %s failed e-Factura validation: %s", invoice.InvoiceNumber, summarizeValidationErrors(validationErrors))
+	}
+
+	resp, err := s.anafClient.UploadInvoice(context.Background(), xmlContent, invoice.InvoiceNumber)
+	if err != nil {
+		invoice.ANAFStatus = models.ANAFStatusFailed
+		invoice.ANAFRetryCount++
+		if updateErr := s.repo.UpdateANAFStatus(invoice); updateErr != nil {
+			return fmt.Errorf("failed to update ANAF status after error: %w", updateErr)
+		}
+		return fmt.Errorf("failed to upload invoice to ANAF: %w", err)
+	}
+
+	invoice.ANAFUploadIndex = sql.NullString{String: resp.UploadIndex, Valid: true}
+	invoice.ANAFSubmittedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	switch {
+	case len(resp.Errors) > 0:
+		invoice.ANAFStatus = models.ANAFStatusRejected
+		invoice.ANAFErrors = make([]models.ANAFError, len(resp.Errors))
+		for i, e := range resp.Errors {
+			invoice.ANAFErrors[i] = models.ANAFError{Code: e.Code, Message: e.Message, Field: e.Field}
+		}
+	case resp.Status == "accepted":
+		invoice.ANAFStatus = models.ANAFStatusAccepted
+		invoice.ANAFProcessedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	default:
+		invoice.ANAFStatus = models.ANAFStatusProcessing
+	}
+
+	if err := s.repo.UpdateANAFStatus(invoice); err != nil {
+		return fmt.Errorf("failed to update commission invoice ANAF status: %w", err)
+	}
+	return nil
+}
+
+// ProcessPendingANAFSubmissions submits B2B commission invoices past the submission delay and
+// retries failed ones once their backoff has passed
+func (s *CommissionInvoiceService) ProcessPendingANAFSubmissions(batchSize int) error {
+	submissionDelay, retryDelay, maxRetries := anafSchedule(s.anafConfig)
+	invoices, err := s.repo.GetPendingANAFSubmission(submissionDelay, retryDelay, maxRetries, batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending commission invoices: %w", err)
+	}
+
+	for _, invoice := range invoices {
+		if err := s.SubmitToANAF(invoice.ID); err != nil {
+			fmt.Printf("Failed to submit commission invoice %s to ANAF: %v\n", invoice.InvoiceNumber, err)
+		}
+	}
+	return nil
+}
+
+// issue numbers and stores a recipient's invoice, renders its PDF and XML and, for VAT-registered
+// customers, files it with ANAF
+func (s *CommissionInvoiceService) issue(recipient *commissionRecipient) error {
+	invoice := recipient.invoice
+
+	// Commission is supplied over the period, so it is taxed at the rate in force at its end
+	vatRate := vatRateFor(s.config, VATCategoryService, invoice.PeriodEnd)
+	var lines []InvoiceDocumentLine
+	var subtotal, taxAmount, total float64
+	for _, fee := range recipient.fees {
+		// Fees are retained out of VAT-inclusive booking prices
+		net := roundToCents(fee.amount / (1 + vatRate))
+		tax := roundToCents(fee.amount - net)
+		lines = append(lines, InvoiceDocumentLine{
+			Description: fmt.Sprintf("Comision platforma %s - %s (%d rezervari)",
+				invoice.PeriodStart.Format("01.2006"), fee.cleanerName, fee.bookings),
+			NetAmount: net,
+			TaxAmount: tax,
+		})
+		subtotal += net
+		taxAmount += tax
+		total += fee.amount
+	}
+
+	invoice.VATRate = vatRate
+	invoice.Subtotal = roundToCents(subtotal)
+	invoice.TaxAmount = roundToCents(taxAmount)
+	invoice.TotalAmount = roundToCents(total)
+	invoice.Currency = "RON"
+	invoice.IssueDate = time.Now()
+	invoice.DueDate = invoice.IssueDate // Already retained from the payouts
+
+	if err := s.repo.CreateForPayouts(invoice, recipient.payoutIDs); err != nil {
+		return err
+	}
+
+	pdfPath, err := s.pdfGenerator.GenerateCommissionInvoicePDF(invoice, lines)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate PDF for commission invoice %s: %v\n", invoice.InvoiceNumber, err)
+	} else {
+		invoice.PdfURL = sql.NullString{String: pdfPath, Valid: true}
+	}
+
+	xmlPath, err := s.xmlGenerator.GenerateCommissionInvoiceXML(invoice, lines)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate XML for commission invoice %s: %v\n", invoice.InvoiceNumber, err)
+	} else {
+		invoice.XmlURL = sql.NullString{String: xmlPath, Valid: true}
+	}
+
+	if invoice.PdfURL.Valid || invoice.XmlURL.Valid {
+		if err := s.repo.UpdateFiles(invoice); err != nil {
+			fmt.Printf("Warning: failed to update commission invoice %s with file paths: %v\n", invoice.InvoiceNumber, err)
+		}
+	}
+
+	if s.anafConfig.Enabled && invoice.XmlURL.Valid && isVATRegistered(invoice.CustomerCUI) {
+		go func() {
+			if err := s.SubmitToANAF(invoice.ID); err != nil {
+				fmt.Printf("Warning: failed to submit commission invoice %s to ANAF: %v\n", invoice.InvoiceNumber, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// recipientFor finds who a payout's commission is billed to, reusing the recipient already
+// collected for the same cleaner or company
+func (s *CommissionInvoiceService) recipientFor(payout *models.Payout, periodStart, periodEnd time.Time, recipients map[string]*commissionRecipient) (string, *commissionRecipient, error) {
+	cleaner, err := s.cleanerRepo.GetByUserID(payout.CleanerID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return "", nil, fmt.Errorf("cleaner profile not found")
+	}
+
+	company, err := s.payoutCompany(payout)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key := "cleaner:" + cleaner.ID
+	if company != nil {
+		key = "company:" + company.ID
+	}
+	if recipient, ok := recipients[key]; ok {
+		return key, recipient, nil
+	}
+
+	invoice := &models.CommissionInvoice{PeriodStart: periodStart, PeriodEnd: periodEnd}
+	if company != nil {
+		invoice.RecipientType = models.CommissionRecipientCompany
+		invoice.CompanyID = sql.NullString{String: company.ID, Valid: true}
+		invoice.CustomerName = company.Name
+		invoice.CustomerCUI = sql.NullString{String: company.CUI, Valid: company.CUI != ""}
+		invoice.CustomerAddress = company.LegalAddress
+		invoice.CustomerEmail = company.ContactEmail
+	} else if err := s.fillCleanerCustomer(invoice, cleaner); err != nil {
+		return "", nil, err
+	}
+
+	return key, &commissionRecipient{invoice: invoice}, nil
+}
+
+// payoutCompany returns the company a payout was paid to, or nil when it went to the cleaner.
+// It follows the payout's own company payout, so a cleaner who has since joined or left a
+// company is still invoiced the way the fees were settled.
+func (s *CommissionInvoiceService) payoutCompany(payout *models.Payout) (*models.Company, error) {
+	if !payout.CompanyPayoutID.Valid {
+		return nil, nil
+	}
+
+	companyPayout, err := s.companyPayoutRepo.GetByID(payout.CompanyPayoutID.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company payout: %w", err)
+	}
+	if companyPayout == nil {
+		return nil, fmt.Errorf("company payout %s not found", payout.CompanyPayoutID.String)
+	}
+
+	company, err := s.companyRepo.GetByID(companyPayout.CompanyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+	if company == nil {
+		return nil, fmt.Errorf("company %s not found", companyPayout.CompanyID)
+	}
+	return company, nil
+}
+
+// fillCleanerCustomer sets an independent cleaner as the customer, under their PFA's details
+// when they gave them for self-billing
+func (s *CommissionInvoiceService) fillCleanerCustomer(invoice *models.CommissionInvoice, cleaner *models.Cleaner) error {
+	invoice.RecipientType = models.CommissionRecipientCleaner
+	invoice.CleanerID = sql.NullString{String: cleaner.ID, Valid: true}
+
+	user, err := s.userRepo.GetByID(cleaner.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if user != nil {
+		invoice.CustomerName = strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+		invoice.CustomerEmail = user.Email
+	}
+
+	settings, err := s.selfBillingRepo.GetSettings(cleaner.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get self-billing settings: %w", err)
+	}
+	if settings != nil {
+		invoice.CustomerName = settings.LegalName
+		invoice.CustomerCUI = sql.NullString{String: settings.CUI, Valid: true}
+		invoice.CustomerAddress = sql.NullString{
+			String: fmt.Sprintf("%s, %s, %s", settings.Address, settings.City, settings.County), Valid: true}
+		return nil
+	}
+
+	var parts []string
+	for _, part := range []string{cleaner.StreetAddress.String, cleaner.City.String, cleaner.County.String} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	invoice.CustomerAddress = sql.NullString{String: strings.Join(parts, ", "), Valid: len(parts) > 0}
+
+	if invoice.CustomerName == "" {
+		return fmt.Errorf("cleaner has no name to invoice")
+	}
+	return nil
+}
+
+// payoutFee adds up the platform fees on a payout's line items
+func (s *CommissionInvoiceService) payoutFee(payout *models.Payout) (*commissionFee, error) {
+	lineItems, err := s.lineItemRepo.GetByPayoutID(payout.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get line items: %w", err)
+	}
+
+	fee := &commissionFee{}
+	for _, item := range lineItems {
+		if item.PlatformFee == 0 {
+			continue
+		}
+		fee.amount += item.PlatformFee
+		fee.bookings++
+	}
+	fee.amount = roundToCents(fee.amount)
+
+	user, err := s.userRepo.GetByID(payout.CleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if user != nil {
+		fee.cleanerName = strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+	}
+	return fee, nil
+}
+
+// isVATRegistered reports whether a customer's CUI carries the RO VAT prefix
+func isVATRegistered(cui sql.NullString) bool {
+	return cui.Valid && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(cui.String)), "RO")
+}
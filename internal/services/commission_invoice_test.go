@@ -0,0 +1,44 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func TestGenerateCommissionInvoiceXML(t *testing.T) {
+	g := testXMLGenerator(t)
+	periodEnd := time.Date(2025, 9, 30, 23, 59, 59, 0, time.UTC)
+	rate := vatRateFor(g.config, VATCategoryService, periodEnd)
+	if rate != 0.21 {
+		t.Fatalf("VAT rate for September 2025 = %.2f, want 0.21", rate)
+	}
+
+	invoice := &models.CommissionInvoice{
+		InvoiceNumber:   "CBC-2025-0007",
+		PeriodStart:     time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:       periodEnd,
+		IssueDate:       time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:         time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		CustomerName:    "Curat Expert SRL",
+		CustomerCUI:     sql.NullString{String: "RO18547290", Valid: true},
+		CustomerAddress: sql.NullString{String: "Str. Florilor 5, Cluj-Napoca, Cluj", Valid: true},
+		Subtotal:        100,
+		VATRate:         rate,
+		TaxAmount:       21,
+		TotalAmount:     121,
+		Currency:        "RON",
+	}
+	lines := []InvoiceDocumentLine{
+		{Description: "Comision platforma 09.2025 - Ana Pop (3 rezervari)", NetAmount: 60, TaxAmount: 12.6},
+		{Description: "Comision platforma 09.2025 - Dan Ionescu (2 rezervari)", NetAmount: 40, TaxAmount: 8.4},
+	}
+
+	path, err := g.GenerateCommissionInvoiceXML(invoice, lines)
+	if err != nil {
+		t.Fatalf("GenerateCommissionInvoiceXML() returned error: %v", err)
+	}
+	assertValidUBL(t, path)
+}
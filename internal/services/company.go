@@ -367,6 +367,29 @@ func (s *CompanyService) RejectCompany(companyID, adminID, reason string) (*mode
 	return company, nil
 }
 
+// SetPlatformFee sets the contracted platform fee for a company's cleaners; nil reverts to the standard policy.
+// It applies to bookings assigned from now on.
+func (s *CompanyService) SetPlatformFee(companyID string, percentage *float64) (*models.Company, error) {
+	fee := sql.NullFloat64{}
+	if percentage != nil {
+		if *percentage < 0 || *percentage > 100 {
+			return nil, fmt.Errorf("platform fee must be between 0 and 100")
+		}
+		fee = sql.NullFloat64{Float64: *percentage, Valid: true}
+	}
+
+	if err := s.companyRepo.UpdatePlatformFee(companyID, fee); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.GetByID(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+
+	return company, nil
+}
+
 // GetPendingCompanies gets all pending companies (admin only)
 func (s *CompanyService) GetPendingCompanies() ([]*models.Company, error) {
 	companies, err := s.companyRepo.GetByApprovalStatus(models.CompanyApprovalStatusPending)
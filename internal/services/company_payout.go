@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// GetCompanyPayouts returns a company's payouts, newest first
+func (s *PayoutService) GetCompanyPayouts(companyID string, limit, offset int) ([]*models.CompanyPayout, error) {
+	return s.companyPayouts.GetByCompanyID(companyID, limit, offset)
+}
+
+// GetCompanyPayoutsByStatus returns company payouts with a status, oldest first
+func (s *PayoutService) GetCompanyPayoutsByStatus(status string, limit, offset int) ([]*models.CompanyPayout, error) {
+	return s.companyPayouts.GetByStatus(status, limit, offset)
+}
+
+// GetCompanyPayout returns a company payout, or nil if it does not exist
+func (s *PayoutService) GetCompanyPayout(id string) (*models.CompanyPayout, error) {
+	return s.companyPayouts.GetByID(id)
+}
+
+// GetCompanyPayoutBreakdown returns the payout of each cleaner included in a company payout,
+// with its line items
+func (s *PayoutService) GetCompanyPayoutBreakdown(companyPayoutID string) ([]*models.PayoutDraft, error) {
+	payouts, err := s.payoutRepo.GetByCompanyPayoutID(companyPayoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company payout breakdown: %w", err)
+	}
+
+	breakdown := make([]*models.PayoutDraft, len(payouts))
+	for i, payout := range payouts {
+		lineItems, err := s.lineItemRepo.GetByPayoutID(payout.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get line items of payout %s: %w", payout.ID, err)
+		}
+		breakdown[i] = &models.PayoutDraft{Payout: payout, LineItems: lineItems}
+	}
+	return breakdown, nil
+}
+
+// IsCompanyAdmin reports whether a user administers a company
+func (s *PayoutService) IsCompanyAdmin(companyID, userID string) (bool, error) {
+	return s.companyAdmins.IsAdmin(companyID, userID)
+}
+
+// MarkCompanyPayoutAsSent records the transfer of a company payout to the company IBAN and
+// settles the payouts of its cleaners
+func (s *PayoutService) MarkCompanyPayoutAsSent(id, transferReference string) (*models.CompanyPayout, error) {
+	companyPayout, company, err := s.getCompanyPayoutWithCompany(id)
+	if err != nil {
+		return nil, err
+	}
+	switch companyPayout.Status {
+	case models.PayoutStatusPending, models.PayoutStatusFailed:
+	case models.PayoutStatusInvoiced:
+		return nil, fmt.Errorf("company payout is an invoice for cash fees owed by the company")
+	default:
+		return nil, fmt.Errorf("company payout is %s, only pending or failed payouts can be sent", companyPayout.Status)
+	}
+
+	iban, err := decryptCompanyIBAN(company)
+	if err != nil {
+		return nil, err
+	}
+	if iban == "" {
+		return nil, fmt.Errorf("cannot send payout: company does not have an IBAN configured")
+	}
+	if err := checkPayoutIBAN(iban); err != nil {
+		return nil, fmt.Errorf("cannot send payout: %w", err)
+	}
+
+	companyPayout.IBAN = company.IBAN
+	if err := s.settleCompanyPayout(companyPayout, transferReference); err != nil {
+		return nil, err
+	}
+
+	s.notifyCompanyPayoutSent(companyPayout, company)
+	return companyPayout, nil
+}
+
+// MarkCompanyPayoutInvoicePaid settles an INVOICED company payout once the company has paid
+// the cash fees it owed
+func (s *PayoutService) MarkCompanyPayoutInvoicePaid(id, transferReference string) (*models.CompanyPayout, error) {
+	companyPayout, err := s.companyPayouts.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company payout: %w", err)
+	}
+	if companyPayout == nil {
+		return nil, fmt.Errorf("company payout not found")
+	}
+	if companyPayout.Status != models.PayoutStatusInvoiced {
+		return nil, fmt.Errorf("company payout is not invoiced (status: %s)", companyPayout.Status)
+	}
+
+	if err := s.settleCompanyPayout(companyPayout, transferReference); err != nil {
+		return nil, err
+	}
+	return companyPayout, nil
+}
+
+// MarkCompanyPayoutAsFailed records a failed transfer; it can be sent again later
+func (s *PayoutService) MarkCompanyPayoutAsFailed(id, reason string) (*models.CompanyPayout, error) {
+	companyPayout, err := s.companyPayouts.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company payout: %w", err)
+	}
+	if companyPayout == nil {
+		return nil, fmt.Errorf("company payout not found")
+	}
+	if companyPayout.Status != models.PayoutStatusPending {
+		return nil, fmt.Errorf("company payout is %s, only pending payouts can fail", companyPayout.Status)
+	}
+
+	companyPayout.Status = models.PayoutStatusFailed
+	companyPayout.FailedReason = sql.NullString{String: reason, Valid: true}
+	if err := s.companyPayouts.Update(companyPayout); err != nil {
+		return nil, fmt.Errorf("failed to update company payout: %w", err)
+	}
+	return companyPayout, nil
+}
+
+// settleCompanyPayout marks the company payout and every unsettled cleaner payout in it as SENT
+func (s *PayoutService) settleCompanyPayout(companyPayout *models.CompanyPayout, transferReference string) error {
+	payouts, err := s.payoutRepo.GetByCompanyPayoutID(companyPayout.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get company payout breakdown: %w", err)
+	}
+	for _, payout := range payouts {
+		if payout.Status == models.PayoutStatusSent {
+			continue
+		}
+		if err := s.settlePayout(payout, transferReference); err != nil {
+			return fmt.Errorf("failed to settle payout %s: %w", payout.ID, err)
+		}
+	}
+
+	companyPayout.Status = models.PayoutStatusSent
+	companyPayout.TransferReference = sql.NullString{String: transferReference, Valid: true}
+	companyPayout.PaidAt = sql.NullTime{Time: time.Now(), Valid: true}
+	companyPayout.FailedReason = sql.NullString{}
+	if err := s.companyPayouts.Update(companyPayout); err != nil {
+		return fmt.Errorf("failed to update company payout: %w", err)
+	}
+	return nil
+}
+
+func (s *PayoutService) getCompanyPayoutWithCompany(id string) (*models.CompanyPayout, *models.Company, error) {
+	companyPayout, err := s.companyPayouts.GetByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get company payout: %w", err)
+	}
+	if companyPayout == nil {
+		return nil, nil, fmt.Errorf("company payout not found")
+	}
+
+	company, err := s.companyRepo.GetByID(companyPayout.CompanyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get company: %w", err)
+	}
+	if company == nil {
+		return nil, nil, fmt.Errorf("company not found")
+	}
+	return companyPayout, company, nil
+}
+
+// getCompanyPayoutIBAN returns the decrypted IBAN of the company a company payout goes to
+func (s *PayoutService) getCompanyPayoutIBAN(companyPayoutID string) (string, error) {
+	_, company, err := s.getCompanyPayoutWithCompany(companyPayoutID)
+	if err != nil {
+		return "", err
+	}
+	return decryptCompanyIBAN(company)
+}
+
+// decryptCompanyIBAN returns the company's IBAN, or "" if none is set
+func decryptCompanyIBAN(company *models.Company) (string, error) {
+	if !company.IBAN.Valid || company.IBAN.String == "" {
+		return "", nil
+	}
+	iban, err := utils.DecryptIBAN(company.IBAN.String)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt company IBAN: %w", err)
+	}
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", "")), nil
+}
+
+func companyPayoutPeriod(companyPayout *models.CompanyPayout) string {
+	return fmt.Sprintf("%s - %s",
+		companyPayout.PeriodStart.Format("02 January 2006"),
+		companyPayout.PeriodEnd.Format("02 January 2006"))
+}
+
+// notifyCompanyPayoutSent emails the company contact that the payout was sent (async)
+func (s *PayoutService) notifyCompanyPayoutSent(companyPayout *models.CompanyPayout, company *models.Company) {
+	if s.emailService == nil || !company.ContactEmail.Valid {
+		return
+	}
+
+	go func() {
+		if err := s.emailService.SendPayoutProcessedEmail(context.Background(), company.ContactEmail.String, company.Name,
+			companyPayout.NetAmount, companyPayoutPeriod(companyPayout), companyPayout.TransferReference.String, nil); err != nil {
+			fmt.Printf("Warning: failed to send payout email for company payout %s: %v\n", companyPayout.ID, err)
+		}
+	}()
+}
+
+// notifyCompanyCashFeesDue emails the company contact the cash fees its cleaners owe for the period (async)
+func (s *PayoutService) notifyCompanyCashFeesDue(companyPayout *models.CompanyPayout) {
+	if s.emailService == nil {
+		return
+	}
+
+	go func() {
+		company, err := s.companyRepo.GetByID(companyPayout.CompanyID)
+		if err != nil || company == nil || !company.ContactEmail.Valid {
+			return
+		}
+
+		if err := s.emailService.SendCashFeesInvoiceEmail(context.Background(), company.ContactEmail.String, company.Name,
+			-companyPayout.NetAmount, companyPayoutPeriod(companyPayout), companyPayout.ID); err != nil {
+			fmt.Printf("Warning: failed to send cash fee invoice for company payout %s: %v\n", companyPayout.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// CreditNoteService issues credit notes (facturi storno) correcting client invoices after a
+// refund or cancellation, and files them with ANAF
+type CreditNoteService struct {
+	repo         *models.CreditNoteRepository
+	invoiceRepo  *models.InvoiceRepository
+	lineRepo     *models.InvoiceLineRepository
+	bookingRepo  *models.BookingRepository
+	addressRepo  *models.AddressRepository
+	xmlGenerator *XMLGenerator
+	pdfGenerator *PDFGenerator
+	anafClient   *ANAFClient
+	anafConfig   *config.ANAFConfig
+}
+
+// NewCreditNoteService creates a new credit note service
+func NewCreditNoteService(db *sql.DB, companyConfig *config.CompanyConfig, anafConfig *config.ANAFConfig) *CreditNoteService {
+	return &CreditNoteService{
+		repo:         models.NewCreditNoteRepository(db),
+		invoiceRepo:  models.NewInvoiceRepository(db),
+		lineRepo:     models.NewInvoiceLineRepository(db),
+		bookingRepo:  models.NewBookingRepository(db),
+		addressRepo:  models.NewAddressRepository(db),
+		xmlGenerator: NewXMLGenerator("./invoices/xml", companyConfig),
+		pdfGenerator: NewPDFGenerator("./invoices/pdf"),
+		anafClient:   NewANAFClient(anafConfig, companyConfig),
+		anafConfig:   anafConfig,
+	}
+}
+
+// IssueForRefund credits the booking's invoice with the refunded amount. It returns nil when the
+// booking has not been invoiced yet (e.g. a cancellation before completion), and the existing
+// credit note when the refund was already credited.
+func (s *CreditNoteService) IssueForRefund(refund *models.Payment, reason string) (*models.CreditNote, error) {
+	existing, err := s.repo.GetByRefundPaymentID(refund.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing credit note: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	invoice, err := s.invoiceRepo.GetByBookingID(refund.BookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, nil
+	}
+
+	remaining, err := s.remainingAmount(invoice)
+	if err != nil {
+		return nil, err
+	}
+	amount := refundCreditAmount(refund.Amount, remaining)
+	if amount <= 0 {
+		return nil, nil
+	}
+	if amount < refund.Amount {
+		fmt.Printf("Warning: refund %s of %.2f exceeds the %.2f left on invoice %s, crediting %.2f\n",
+			refund.ID, refund.Amount, remaining, invoice.InvoiceNumber, amount)
+	}
+
+	return s.issue(invoice, amount, reason, sql.NullString{String: refund.ID, Valid: true}, sql.NullString{})
+}
+
+// CreateCreditNote credits all or part of an invoice outside the refund flow (admin only)
+func (s *CreditNoteService) CreateCreditNote(invoiceID string, amount float64, reason, adminID string) (*models.CreditNote, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required")
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+
+	return s.issue(invoice, amount, reason, sql.NullString{}, sql.NullString{String: adminID, Valid: true})
+}
+
+func (s *CreditNoteService) issue(invoice *models.Invoice, amount float64, reason string, refundPaymentID, createdBy sql.NullString) (*models.CreditNote, error) {
+	amount = roundToCents(amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("credit amount must be positive")
+	}
+
+	lines, err := s.lineRepo.GetByInvoiceID(invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice lines: %w", err)
+	}
+	groups := s.creditVATGroups(invoice, lines, amount)
+	var subtotal, taxAmount float64
+	for _, group := range groups {
+		subtotal += group.net
+		taxAmount += group.vat
+	}
+
+	note := &models.CreditNote{
+		InvoiceID:       invoice.ID,
+		RefundPaymentID: refundPaymentID,
+		CreditType:      creditNoteType(amount, invoice.TotalAmount),
+		Reason:          reason,
+		IssueDate:       time.Now(),
+		ClientName:      invoice.ClientName,
+		ClientEmail:     invoice.ClientEmail,
+		Subtotal:        roundToCents(subtotal),
+		TaxAmount:       roundToCents(taxAmount),
+		TotalAmount:     amount,
+		Currency:        invoice.Currency,
+		CreatedBy:       createdBy,
+	}
+	if err := s.repo.Create(note); err != nil {
+		return nil, err
+	}
+
+	pdfPath, err := s.pdfGenerator.GenerateCreditNotePDF(note, invoice)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate PDF for credit note %s: %v\n", note.CreditNoteNumber, err)
+	} else {
+		note.PdfURL = sql.NullString{String: pdfPath, Valid: true}
+	}
+
+	xmlPath, err := s.generateXML(note, invoice, groups)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate XML for credit note %s: %v\n", note.CreditNoteNumber, err)
+	} else {
+		note.XmlURL = sql.NullString{String: xmlPath, Valid: true}
+	}
+
+	if note.PdfURL.Valid || note.XmlURL.Valid {
+		if err := s.repo.UpdateFiles(note); err != nil {
+			fmt.Printf("Warning: failed to update credit note %s with file paths: %v\n", note.CreditNoteNumber, err)
+		}
+	}
+
+	if s.anafConfig.Enabled && note.XmlURL.Valid {
+		go func() {
+			if err := s.SubmitToANAF(note.ID); err != nil {
+				fmt.Printf("Warning: failed to submit credit note %s to ANAF: %v\n", note.CreditNoteNumber, err)
+			}
+		}()
+	}
+
+	return note, nil
+}
+
+// generateXML generates a credit note's e-Factura XML, billed to the same buyer as the invoice
+// it corrects
+func (s *CreditNoteService) generateXML(note *models.CreditNote, invoice *models.Invoice, groups []*invoiceVATGroup) (string, error) {
+	buyerAddress, billing, err := loadInvoiceBuyer(s.invoiceRepo, s.bookingRepo, s.addressRepo, invoice)
+	if err != nil {
+		return "", err
+	}
+	return s.xmlGenerator.GenerateCreditNoteXML(note, invoice, groups, buyerAddress, billing)
+}
+
+// creditVATGroups splits a VAT-inclusive credit over the invoice's VAT rates. Invoices issued
+// without lines are credited at the standard rate, as in their XML.
+func (s *CreditNoteService) creditVATGroups(invoice *models.Invoice, lines []*models.InvoiceLine, amount float64) []*invoiceVATGroup {
+	groups := groupInvoiceLinesByRate(lines)
+	if len(groups) == 0 {
+		rate := s.xmlGenerator.vatRate(invoice.IssueDate)
+		net := invoice.Subtotal
+		vat := invoice.TaxAmount
+		if vat <= 0 || net <= 0 {
+			net = roundToCents(amount / (1 + rate))
+			vat = roundToCents(amount - net)
+		}
+		groups = []*invoiceVATGroup{{rate: rate, net: net, vat: vat}}
+	}
+	return splitCredit(groups, amount)
+}
+
+// splitCredit spreads a VAT-inclusive credit over an invoice's VAT groups in proportion to their
+// gross amount, and splits each share into net and VAT in the group's own proportion. The last
+// group absorbs rounding so the shares add up to the credit exactly.
+func splitCredit(groups []*invoiceVATGroup, amount float64) []*invoiceVATGroup {
+	var total float64
+	for _, group := range groups {
+		total += group.net + group.vat
+	}
+
+	credit := make([]*invoiceVATGroup, len(groups))
+	remaining := amount
+	for i, group := range groups {
+		gross := roundToCents(group.net + group.vat)
+		share := remaining
+		if i < len(groups)-1 && total > 0 {
+			share = roundToCents(amount * gross / total)
+		}
+		remaining = roundToCents(remaining - share)
+
+		var vat float64
+		if gross > 0 {
+			vat = roundToCents(share * group.vat / gross)
+		}
+		credit[i] = &invoiceVATGroup{rate: group.rate, net: roundToCents(share - vat), vat: vat}
+	}
+	return credit
+}
+
+// refundCreditAmount returns the part of a refund credited on an invoice that has remaining
+// left to credit. Refunds can include amounts that were never invoiced (e.g. tips); only the
+// invoiced part is credited.
+func refundCreditAmount(refund, remaining float64) float64 {
+	if remaining <= 0 {
+		return 0
+	}
+	return roundToCents(math.Min(refund, remaining))
+}
+
+// creditNoteType tells a credit note for the whole invoice from a partial one
+func creditNoteType(amount, invoiceTotal float64) string {
+	if math.Abs(amount-invoiceTotal) < 0.005 {
+		return models.CreditNoteTypeFull
+	}
+	return models.CreditNoteTypePartial
+}
+
+func (s *CreditNoteService) remainingAmount(invoice *models.Invoice) (float64, error) {
+	credited, err := s.repo.GetCreditedAmount(invoice.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get credited amount: %w", err)
+	}
+	return roundToCents(invoice.TotalAmount - credited), nil
+}
+
+// GetCreditNote returns a credit note, or nil if it does not exist
+func (s *CreditNoteService) GetCreditNote(id string) (*models.CreditNote, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetCreditNotesByInvoiceID returns the credit notes issued on an invoice
+func (s *CreditNoteService) GetCreditNotesByInvoiceID(invoiceID string) ([]*models.CreditNote, error) {
+	return s.repo.GetByInvoiceID(invoiceID)
+}
+
+// SubmitToANAF uploads a credit note's XML to ANAF e-Factura
+func (s *CreditNoteService) SubmitToANAF(creditNoteID string) error {
+	note, err := s.repo.GetByID(creditNoteID)
+	if err != nil {
+		return fmt.Errorf("failed to get credit note: %w", err)
+	}
+	if note == nil {
+		return fmt.Errorf("credit note not found")
+	}
+	if note.ANAFStatus == models.ANAFStatusAccepted || note.ANAFStatus == models.ANAFStatusProcessing {
+		return fmt.Errorf("credit note already submitted to ANAF")
+	}
+	if !note.XmlURL.Valid {
+		return fmt.Errorf("credit note has no XML to submit")
+	}
+
+	xmlContent, err := s.xmlGenerator.ReadXML(note.XmlURL.String)
+	if err != nil {
+		return fmt.Errorf("failed to read XML: %w", err)
+	}
+
+	// Don't upload what ANAF would reject: store the violations as the document's errors instead
+	if validationErrors := ValidateUBL(xmlContent); len(validationErrors) > 0 {
+		note.ANAFStatus = models.ANAFStatusRejected
+		note.ANAFErrors = validationErrors
+		if err := s.repo.UpdateANAFStatus(note); err != nil {
+			return fmt.Errorf("failed to update ANAF status after validation: %w", err)
+		}
+		return fmt.Errorf("credit note %s failed e-Factura validation: %s", note.CreditNoteNumber, summarizeValidationErrors(validationErrors))
+	}
+
+	resp, err := s.anafClient.UploadInvoice(context.Background(), xmlContent, note.CreditNoteNumber)
+	if err != nil {
+		note.ANAFStatus = models.ANAFStatusFailed
+		note.ANAFRetryCount++
+		if updateErr := s.repo.UpdateANAFStatus(note); updateErr != nil {
+			return fmt.Errorf("failed to update ANAF status after error: %w", updateErr)
+		}
+		return fmt.Errorf("failed to upload credit note to ANAF: %w", err)
+	}
+
+	note.ANAFUploadIndex = sql.NullString{String: resp.UploadIndex, Valid: true}
+	note.ANAFSubmittedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	switch {
+	case len(resp.Errors) > 0:
+		note.ANAFStatus = models.ANAFStatusRejected
+		note.ANAFErrors = make([]models.ANAFError, len(resp.Errors))
+		for i, e := range resp.Errors {
+			note.ANAFErrors[i] = models.ANAFError{Code: e.Code, Message: e.Message, Field: e.Field}
+		}
+	case resp.Status == "accepted":
+		note.ANAFStatus = models.ANAFStatusAccepted
+		note.ANAFProcessedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	default:
+		note.ANAFStatus = models.ANAFStatusProcessing
+	}
+
+	if err := s.repo.UpdateANAFStatus(note); err != nil {
+		return fmt.Errorf("failed to update credit note ANAF status: %w", err)
+	}
+	return nil
+}
+
+// ProcessPendingANAFSubmissions submits credit notes past the submission delay and retries failed
+// ones once their backoff has passed
+func (s *CreditNoteService) ProcessPendingANAFSubmissions(batchSize int) error {
+	submissionDelay, retryDelay, maxRetries := anafSchedule(s.anafConfig)
+	notes, err := s.repo.GetPendingANAFSubmission(submissionDelay, retryDelay, maxRetries, batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending credit notes: %w", err)
+	}
+
+	for _, note := range notes {
+		if err := s.SubmitToANAF(note.ID); err != nil {
+			fmt.Printf("Failed to submit credit note %s to ANAF: %v\n", note.CreditNoteNumber, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func TestRefundCreditAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		refund    float64
+		remaining float64
+		want      float64
+	}{
+		{"refund within the invoice", 80, 238, 80},
+		{"refund including a tip is capped at the invoice", 258, 238, 238},
+		{"capped at what earlier credit notes left", 100, 38.50, 38.50},
+		{"invoice fully credited", 50, 0, 0},
+		{"over-credited invoice", 50, -10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundCreditAmount(tt.refund, tt.remaining); got != tt.want {
+				t.Errorf("refundCreditAmount(%.2f, %.2f) = %.2f, want %.2f", tt.refund, tt.remaining, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreditNoteType(t *testing.T) {
+	if got := creditNoteType(238, 238); got != models.CreditNoteTypeFull {
+		t.Errorf("credit of the whole invoice = %s, want FULL", got)
+	}
+	if got := creditNoteType(237.99, 238); got != models.CreditNoteTypePartial {
+		t.Errorf("credit one cent short of the invoice = %s, want PARTIAL", got)
+	}
+}
+
+// Mixed rates: 21% on the service, 11% on supplies
+func testMixedRateLines() []*models.InvoiceLine {
+	return []*models.InvoiceLine{
+		{LineNumber: 1, Description: "Curatenie standard", Quantity: 1, UnitCode: "C62", VATRate: 0.21, NetAmount: 200, VATAmount: 42},
+		{LineNumber: 2, Description: "Produse de curatenie", Quantity: 1, UnitCode: "C62", VATRate: 0.11, NetAmount: 100, VATAmount: 11},
+	}
+}
+
+func TestSplitCredit(t *testing.T) {
+	groups := groupInvoiceLinesByRate(testMixedRateLines())
+
+	credit := splitCredit(groups, 176.5)
+	if len(credit) != 2 {
+		t.Fatalf("got %d VAT groups, want one per rate", len(credit))
+	}
+	if credit[0].rate != 0.21 || credit[0].net != 100 || credit[0].vat != 21 {
+		t.Errorf("21%% share = %.2f + %.2f VAT, want 100.00 + 21.00", credit[0].net, credit[0].vat)
+	}
+	if credit[1].rate != 0.11 || credit[1].net != 50 || credit[1].vat != 5.5 {
+		t.Errorf("11%% share = %.2f + %.2f VAT, want 50.00 + 5.50", credit[1].net, credit[1].vat)
+	}
+
+	var total float64
+	for _, group := range splitCredit(groups, 33.33) {
+		total += group.net + group.vat
+	}
+	if got := roundToCents(total); got != 33.33 {
+		t.Errorf("split of 33.33 adds up to %.2f", got)
+	}
+}
+
+func TestGenerateCreditNoteXML(t *testing.T) {
+	g := testXMLGenerator(t)
+	issued := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+	invoice := &models.Invoice{
+		ID:            "invoice-1",
+		InvoiceNumber: "CB-2025-0042",
+		IssueDate:     issued,
+		ClientName:    "Curat Expert SRL",
+		Subtotal:      300,
+		TaxAmount:     53,
+		TotalAmount:   353,
+		Currency:      "RON",
+	}
+	billing := &models.InvoiceBillingDetails{
+		LegalName:          "Curat Expert SRL",
+		CUI:                "RO18547290",
+		RegistrationNumber: sql.NullString{String: "J12/345/2019", Valid: true},
+		VATPayer:           true,
+		StreetAddress:      "Str. Florilor 5",
+		City:               "Cluj-Napoca",
+		County:             "Cluj",
+		Country:            "RO",
+	}
+
+	groups := splitCredit(groupInvoiceLinesByRate(testMixedRateLines()), 176.5)
+	note := &models.CreditNote{
+		CreditNoteNumber: "CN-2025-0003",
+		CreditType:       models.CreditNoteTypePartial,
+		Reason:           "Refund",
+		IssueDate:        issued.AddDate(0, 0, 5),
+		ClientName:       invoice.ClientName,
+		Subtotal:         150,
+		TaxAmount:        26.5,
+		TotalAmount:      176.5,
+		Currency:         "RON",
+	}
+
+	path, err := g.GenerateCreditNoteXML(note, invoice, groups, nil, billing)
+	if err != nil {
+		t.Fatalf("GenerateCreditNoteXML() returned error: %v", err)
+	}
+	assertValidUBL(t, path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read generated XML: %v", err)
+	}
+	if !strings.Contains(string(content), "<cbc:CompanyID>RO18547290</cbc:CompanyID>") {
+		t.Error("credit note does not identify the buyer by the VAT identifier on the invoice")
+	}
+}
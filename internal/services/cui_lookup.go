@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// CompanyRegistryInfo is what the VAT registry knows about a company
+type CompanyRegistryInfo struct {
+	CUI                string
+	LegalName          string
+	RegistrationNumber string
+	VATPayer           bool
+	Active             bool
+	StreetAddress      string
+	City               string
+	County             string
+	PostalCode         string
+}
+
+// CUILookup looks companies up by their fiscal code (CUI)
+type CUILookup interface {
+	// LookupCUI returns nil, nil if no company is registered under the CUI
+	LookupCUI(ctx context.Context, cui string) (*CompanyRegistryInfo, error)
+}
+
+// NewCUILookup returns a client for ANAF's public VAT registry at registryURL, or a local stub
+// when no URL is configured
+func NewCUILookup(registryURL string) CUILookup {
+	if registryURL == "" {
+		return &stubCUILookup{}
+	}
+	return &ANAFVATRegistry{
+		url: registryURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// ANAFVATRegistry queries ANAF's public web service for VAT payers (PlatitorTvaRest)
+type ANAFVATRegistry struct {
+	url        string
+	httpClient *http.Client
+}
+
+type anafVATRegistryResponse struct {
+	Code    int    `json:"cod"`
+	Message string `json:"message"`
+	Found   []struct {
+		General struct {
+			CUI                int64  `json:"cui"`
+			Name               string `json:"denumire"`
+			Address            string `json:"adresa"`
+			RegistrationNumber string `json:"nrRegCom"`
+			PostalCode         string `json:"codPostal"`
+			RegistrationStatus string `json:"stare_inregistrare"`
+		} `json:"date_generale"`
+		VAT struct {
+			VATPayer bool `json:"scpTVA"`
+		} `json:"inregistrare_scop_Tva"`
+		Inactive struct {
+			Inactive bool `json:"statusInactivi"`
+		} `json:"stare_inactiv"`
+		Headquarters struct {
+			Street     string `json:"sdenumire_Strada"`
+			Number     string `json:"snumar_Strada"`
+			City       string `json:"sdenumire_Localitate"`
+			County     string `json:"sdenumire_Judet"`
+			PostalCode string `json:"scod_Postal"`
+			Details    string `json:"sdetalii_Adresa"`
+		} `json:"adresa_sediu_social"`
+	} `json:"found"`
+}
+
+// LookupCUI looks a company up in ANAF's VAT registry as of today
+func (r *ANAFVATRegistry) LookupCUI(ctx context.Context, cui string) (*CompanyRegistryInfo, error) {
+	code, err := strconv.ParseInt(utils.NormalizeCUI(cui), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CUI: %s", cui)
+	}
+
+	body, err := json.Marshal([]map[string]interface{}{
+		{"cui": code, "data": time.Now().Format("2006-01-02")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode VAT registry request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VAT registry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("VAT registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VAT registry response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("VAT registry returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result anafVATRegistryResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse VAT registry response: %w", err)
+	}
+	if result.Code != http.StatusOK {
+		return nil, fmt.Errorf("VAT registry error: %s", result.Message)
+	}
+	if len(result.Found) == 0 {
+		return nil, nil
+	}
+
+	found := result.Found[0]
+	hq := found.Headquarters
+	street := strings.TrimSpace(strings.Join(strings.Fields(hq.Street+" "+hq.Number), " "))
+	if hq.Details != "" {
+		street = strings.TrimSpace(street + ", " + hq.Details)
+	}
+	if street == "" {
+		street = found.General.Address
+	}
+	postalCode := hq.PostalCode
+	if postalCode == "" {
+		postalCode = found.General.PostalCode
+	}
+
+	return &CompanyRegistryInfo{
+		CUI:                strconv.FormatInt(found.General.CUI, 10),
+		LegalName:          found.General.Name,
+		RegistrationNumber: found.General.RegistrationNumber,
+		VATPayer:           found.VAT.VATPayer,
+		Active:             !found.Inactive.Inactive && !strings.Contains(strings.ToLower(found.General.RegistrationStatus), "radiere"),
+		StreetAddress:      street,
+		City:               hq.City,
+		County:             hq.County,
+		PostalCode:         postalCode,
+	}, nil
+}
+
+// stubCUILookup answers every valid CUI with a made-up VAT-paying company, for development
+// without access to ANAF
+type stubCUILookup struct{}
+
+func (s *stubCUILookup) LookupCUI(ctx context.Context, cui string) (*CompanyRegistryInfo, error) {
+	cui = utils.NormalizeCUI(cui)
+	if utils.ValidateCUI(cui) != nil {
+		return nil, nil
+	}
+	return &CompanyRegistryInfo{
+		CUI:                cui,
+		LegalName:          fmt.Sprintf("Test Company %s SRL", cui),
+		RegistrationNumber: "J40/1234/2020",
+		VATPayer:           true,
+		Active:             true,
+		StreetAddress:      "Str. Exemplu nr. 1",
+		City:               "Sector 1",
+		County:             "Bucuresti",
+		PostalCode:         "010011",
+	}, nil
+}
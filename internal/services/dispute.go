@@ -10,11 +10,13 @@ import (
 
 // DisputeService handles dispute business logic
 type DisputeService struct {
-	disputeRepo    *models.DisputeRepository
-	bookingRepo    *models.BookingRepository
-	paymentService *PaymentService
-	bookingService *BookingService
-	emailService   *EmailService
+	disputeRepo       *models.DisputeRepository
+	bookingRepo       *models.BookingRepository
+	paymentService    *PaymentService
+	bookingService    *BookingService
+	emailService      *EmailService
+	walletService     *WalletService
+	adjustmentService *PayoutAdjustmentService
 }
 
 // NewDisputeService creates a new dispute service
@@ -40,6 +42,16 @@ func (s *DisputeService) SetEmailService(emailService *EmailService) {
 	s.emailService = emailService
 }
 
+// SetWalletService sets the wallet service (for refunds to store credit)
+func (s *DisputeService) SetWalletService(walletService *WalletService) {
+	s.walletService = walletService
+}
+
+// SetPayoutAdjustmentService sets the service used to claw back the cleaner's share of refunds
+func (s *DisputeService) SetPayoutAdjustmentService(adjustmentService *PayoutAdjustmentService) {
+	s.adjustmentService = adjustmentService
+}
+
 // CreateDispute creates a new dispute for a booking
 func (s *DisputeService) CreateDispute(bookingID, userID, disputeType, description string) (*models.Dispute, error) {
 	// Get booking to validate
@@ -221,15 +233,45 @@ func (s *DisputeService) ResolveDispute(disputeID, adminID, resolutionType, reso
 
 	// Validate resolution type
 	validResolutions := map[string]bool{
-		models.DisputeResolutionPartialRefund: true,
-		models.DisputeResolutionFullRefund:    true,
-		models.DisputeResolutionReclean:       true,
-		models.DisputeResolutionRejected:      true,
+		models.DisputeResolutionPartialRefund:  true,
+		models.DisputeResolutionFullRefund:     true,
+		models.DisputeResolutionRefundToCredit: true,
+		models.DisputeResolutionReclean:        true,
+		models.DisputeResolutionRejected:       true,
 	}
 	if !validResolutions[resolutionType] {
 		return nil, fmt.Errorf("invalid resolution type")
 	}
 
+	// Amount the client actually got back, in cash or credit
+	refunded := 0.0
+
+	// Store credit is issued before the dispute is closed so a failure leaves it open
+	if resolutionType == models.DisputeResolutionRefundToCredit {
+		if refundAmount <= 0 {
+			return nil, fmt.Errorf("refund amount is required for a refund to credit")
+		}
+		if s.walletService == nil {
+			return nil, fmt.Errorf("wallet is not available")
+		}
+
+		booking, err := s.bookingRepo.GetByID(dispute.BookingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get booking: %w", err)
+		}
+		if booking == nil {
+			return nil, fmt.Errorf("booking not found")
+		}
+		if refundAmount > booking.TotalPrice {
+			return nil, fmt.Errorf("refund amount cannot exceed the booking price")
+		}
+
+		if _, err := s.walletService.CreditDisputeRefund(dispute, booking, refundAmount, adminID); err != nil {
+			return nil, err
+		}
+		refunded = refundAmount
+	}
+
 	// Update dispute
 	now := time.Now()
 	dispute.Status = models.DisputeStatusResolved
@@ -267,6 +309,7 @@ func (s *DisputeService) ResolveDispute(disputeID, adminID, resolutionType, reso
 							// Log error but don't fail dispute resolution
 							fmt.Printf("Warning: Failed to process refund for dispute %s: %v\n", disputeID, err)
 						} else {
+							refunded = refundAmount
 							fmt.Printf("✅ Processed refund of %.2f RON for dispute %s\n", refundAmount, disputeID)
 						}
 						break
@@ -276,6 +319,13 @@ func (s *DisputeService) ResolveDispute(disputeID, adminID, resolutionType, reso
 		}
 	}
 
+	// The cleaner gives back their share of the refund with the next payout
+	if refunded > 0 && booking != nil && s.adjustmentService != nil {
+		if _, err := s.adjustmentService.RecordDisputeClawback(dispute, booking, refunded); err != nil {
+			fmt.Printf("Warning: failed to record clawback for dispute %s: %v\n", disputeID, err)
+		}
+	}
+
 	// Create reclean booking if needed
 	if resolutionType == models.DisputeResolutionReclean && s.bookingService != nil {
 		// Create a new booking with same details as original
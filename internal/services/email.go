@@ -49,6 +49,13 @@ type EmailRequest struct {
 	Subject       string                 `json:"subject,omitempty"`
 	HTML          string                 `json:"html,omitempty"`
 	Text          string                 `json:"text,omitempty"`
+	Attachments   []EmailAttachment      `json:"attachments,omitempty"`
+}
+
+// EmailAttachment is a file attached to an email; Content is base64 encoded
+type EmailAttachment struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
 }
 
 // EmailResponse represents the response from Sidemail API
@@ -91,6 +98,9 @@ func (s *EmailService) SendEmail(ctx context.Context, req EmailRequest) (*EmailR
 			fmt.Printf("Template: %s\n", req.TemplateName)
 			fmt.Printf("Template Props: %+v\n", req.TemplateProps)
 		}
+		for _, attachment := range req.Attachments {
+			fmt.Printf("Attachment: %s (%d bytes base64)\n", attachment.Name, len(attachment.Content))
+		}
 		fmt.Printf("=====================================\n\n")
 		return &EmailResponse{ID: "dev-mode-" + time.Now().Format("20060102150405"), Status: "development"}, nil
 	}
@@ -192,6 +202,24 @@ func (s *EmailService) SendBookingCancelledEmail(ctx context.Context, toEmail, r
 	return err
 }
 
+// SendBookingRefundEmail sends email with the refund outcome of a cancelled booking
+// The template words the refund timeline from refundStatus
+func (s *EmailService) SendBookingRefundEmail(ctx context.Context, toEmail, clientName, bookingID, refundStatus string, refundAmount float64) error {
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: "booking-refund",
+		TemplateProps: map[string]interface{}{
+			"clientName":   clientName,
+			"bookingID":    bookingID,
+			"refundStatus": refundStatus,
+			"refundAmount": fmt.Sprintf("%.2f RON", refundAmount),
+		},
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
 // SendBookingCompletedEmail sends email when booking is completed
 func (s *EmailService) SendBookingCompletedEmail(ctx context.Context, toEmail, clientName, bookingID string, totalPrice float64, reviewURL string) error {
 	req := EmailRequest{
@@ -238,8 +266,8 @@ func (s *EmailService) SendCleanerRejectedEmail(ctx context.Context, toEmail, cl
 	return err
 }
 
-// SendPayoutProcessedEmail sends email when payout is processed
-func (s *EmailService) SendPayoutProcessedEmail(ctx context.Context, toEmail, cleanerName string, amount float64, period, transferRef string) error {
+// SendPayoutProcessedEmail sends email when payout is processed, with the payout statement attached if given
+func (s *EmailService) SendPayoutProcessedEmail(ctx context.Context, toEmail, cleanerName string, amount float64, period, transferRef string, statement *EmailAttachment) error {
 	req := EmailRequest{
 		ToAddress:    toEmail,
 		TemplateName: "payout-processed",
@@ -250,6 +278,104 @@ func (s *EmailService) SendPayoutProcessedEmail(ctx context.Context, toEmail, cl
 			"transferRef": transferRef,
 		},
 	}
+	if statement != nil {
+		req.Attachments = []EmailAttachment{*statement}
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
+// SendCashFeesInvoiceEmail tells a cleaner how much they owe in platform fees on cash bookings
+func (s *EmailService) SendCashFeesInvoiceEmail(ctx context.Context, toEmail, cleanerName string, amount float64, period, paymentRef string) error {
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: "cash-fees-invoice",
+		TemplateProps: map[string]interface{}{
+			"cleanerName": cleanerName,
+			"amount":      fmt.Sprintf("%.2f RON", amount),
+			"period":      period,
+			"paymentRef":  paymentRef,
+		},
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
+// SendInvoiceEmail sends a client their invoice with its files attached. signedCopy is set
+// for the copy validated by ANAF, the legally valid e-invoice.
+func (s *EmailService) SendInvoiceEmail(ctx context.Context, toEmail, clientName, invoiceNumber string, totalAmount float64, currency string, signedCopy bool, attachments []EmailAttachment) error {
+	templateName := "invoice-issued"
+	if signedCopy {
+		templateName = "invoice-anaf-validated"
+	}
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: templateName,
+		TemplateProps: map[string]interface{}{
+			"clientName":    clientName,
+			"invoiceNumber": invoiceNumber,
+			"amount":        fmt.Sprintf("%.2f %s", totalAmount, currency),
+		},
+		Attachments: attachments,
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
+// SendANAFAlertEmail tells a platform admin that an invoice needs attention to reach ANAF in time
+func (s *EmailService) SendANAFAlertEmail(ctx context.Context, toEmail, invoiceNumber, alertType, message, deadline string) error {
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: "anaf-alert",
+		TemplateProps: map[string]interface{}{
+			"invoiceNumber": invoiceNumber,
+			"alertType":     alertType,
+			"message":       message,
+			"deadline":      deadline,
+		},
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
+// SendProformaEmail sends a company the proforma to pay its booking by bank transfer
+func (s *EmailService) SendProformaEmail(ctx context.Context, toEmail, clientName, proformaNumber string, amount float64, currency, dueDate, iban, bankName string, attachments []EmailAttachment) error {
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: "proforma-issued",
+		TemplateProps: map[string]interface{}{
+			"clientName":     clientName,
+			"proformaNumber": proformaNumber,
+			"amount":         fmt.Sprintf("%.2f %s", amount, currency),
+			"dueDate":        dueDate,
+			"iban":           iban,
+			"bankName":       bankName,
+		},
+		Attachments: attachments,
+	}
+
+	_, err := s.SendEmail(ctx, req)
+	return err
+}
+
+// SendProformaReminderEmail reminds a company that a proforma is past its due date
+func (s *EmailService) SendProformaReminderEmail(ctx context.Context, toEmail, clientName, proformaNumber string, amount float64, currency, dueDate, scheduledDate, iban string) error {
+	req := EmailRequest{
+		ToAddress:    toEmail,
+		TemplateName: "proforma-overdue",
+		TemplateProps: map[string]interface{}{
+			"clientName":     clientName,
+			"proformaNumber": proformaNumber,
+			"amount":         fmt.Sprintf("%.2f %s", amount, currency),
+			"dueDate":        dueDate,
+			"scheduledDate":  scheduledDate,
+			"iban":           iban,
+		},
+	}
 
 	_, err := s.SendEmail(ctx, req)
 	return err
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// PlatformFeeSplit is the fee policy outcome for a booking price
+type PlatformFeeSplit struct {
+	Rate          float64
+	Rule          models.PlatformFeeRule
+	PlatformFee   float64
+	CleanerPayout float64
+}
+
+// FeePolicyService decides the platform fee of a booking. The result is stored on the booking,
+// and pricing, payouts and analytics all read it from there.
+type FeePolicyService struct {
+	bookingRepo *models.BookingRepository
+	companyRepo *models.CompanyRepository
+	cfg         *config.Config
+}
+
+// NewFeePolicyService creates a new fee policy service
+func NewFeePolicyService(db *sql.DB) *FeePolicyService {
+	return &FeePolicyService{
+		bookingRepo: models.NewBookingRepository(db),
+		companyRepo: models.NewCompanyRepository(db),
+		cfg:         config.Get(),
+	}
+}
+
+// Evaluate returns the platform fee for a booking price. The cleaner is nil before assignment.
+// A company contract fee overrides everything else; otherwise the lowest of the default fee,
+// the cleaner's tier fee and the repeat-client fee applies.
+func (s *FeePolicyService) Evaluate(clientID string, cleaner *models.Cleaner, bookedAt time.Time, price float64) (*PlatformFeeSplit, error) {
+	if cleaner != nil {
+		contractFee, err := s.companyRepo.GetContractFeeForCleaner(cleaner.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get company contract fee: %w", err)
+		}
+		if contractFee.Valid {
+			return splitPrice(price, contractFee.Float64, models.PlatformFeeRuleCompanyContract), nil
+		}
+	}
+
+	rate := s.cfg.Pricing.DefaultPlatformFeePercentage
+	rule := models.PlatformFeeRuleDefault
+
+	if tier := s.cleanerTier(cleaner); tier != nil && tier.PlatformFeePercentage < rate {
+		rate = tier.PlatformFeePercentage
+		rule = models.PlatformFeeRuleCleanerTier
+	}
+
+	repeatFee := s.cfg.Pricing.RepeatCustomerDiscountPercentage
+	if repeatFee > 0 && repeatFee < rate {
+		previous, err := s.bookingRepo.GetCompletedBookingsByClientBeforeDate(clientID, bookedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check repeat client: %w", err)
+		}
+		if len(previous) > 0 {
+			rate = repeatFee
+			rule = models.PlatformFeeRuleRepeatClient
+		}
+	}
+
+	return splitPrice(price, rate, rule), nil
+}
+
+// Apply evaluates the policy for a booking and its cleaner and stores the split on the booking.
+// The caller persists the booking.
+func (s *FeePolicyService) Apply(booking *models.Booking, cleaner *models.Cleaner) error {
+	bookedAt := booking.CreatedAt
+	if bookedAt.IsZero() {
+		bookedAt = time.Now()
+	}
+
+	split, err := s.Evaluate(booking.ClientID, cleaner, bookedAt, booking.TotalPrice)
+	if err != nil {
+		return err
+	}
+
+	booking.PlatformFeeRate = split.Rate
+	booking.PlatformFeeRule = split.Rule
+	booking.PlatformFee = split.PlatformFee
+	booking.CleanerPayout = split.CleanerPayout
+	return nil
+}
+
+// cleanerTier returns the best fee tier the cleaner qualifies for, if any
+func (s *FeePolicyService) cleanerTier(cleaner *models.Cleaner) *config.CleanerFeeTier {
+	if cleaner == nil {
+		return nil
+	}
+
+	var best *config.CleanerFeeTier
+	for i := range s.cfg.Pricing.CleanerFeeTiers {
+		tier := &s.cfg.Pricing.CleanerFeeTiers[i]
+		if cleaner.TotalJobs < tier.MinCompletedJobs {
+			continue
+		}
+		if tier.MinRating > 0 && (!cleaner.AverageRating.Valid || cleaner.AverageRating.Float64 < tier.MinRating) {
+			continue
+		}
+		if best == nil || tier.PlatformFeePercentage < best.PlatformFeePercentage {
+			best = tier
+		}
+	}
+	return best
+}
+
+// splitPrice divides a price between platform and cleaner at the given fee rate
+func splitPrice(price, rate float64, rule models.PlatformFeeRule) *PlatformFeeSplit {
+	platformFee := roundToCents(price * rate / 100.0)
+	return &PlatformFeeSplit{
+		Rate:          rate,
+		Rule:          rule,
+		PlatformFee:   platformFee,
+		CleanerPayout: roundToCents(price - platformFee),
+	}
+}
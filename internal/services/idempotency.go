@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyKeyTTL is how long a stored response can be replayed
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyLeaseTTL is how long a key stays reserved without a heartbeat. A running request
+// renews its lease every IdempotencyLeaseRenewal, so only a crashed request lets the key free up.
+const IdempotencyLeaseTTL = 2 * time.Minute
+
+// IdempotencyLeaseRenewal is how often a running request renews the lease on its key
+const IdempotencyLeaseRenewal = IdempotencyLeaseTTL / 4
+
+// idempotencyCompleteAttempts is how many times storing a response is tried before giving up
+const idempotencyCompleteAttempts = 3
+
+var (
+	// ErrIdempotencyKeyInProgress is returned when a request with the same key is still executing
+	ErrIdempotencyKeyInProgress = errors.New("IDEMPOTENCY_KEY_IN_PROGRESS: a request with this idempotency key is already being processed")
+	// ErrIdempotencyKeyMismatch is returned when a key is reused with different arguments
+	ErrIdempotencyKeyMismatch = errors.New("IDEMPOTENCY_KEY_MISMATCH: this idempotency key was already used with different request parameters")
+)
+
+// IdempotencyService stores mutation responses so retried requests are not executed twice.
+// Postgres is the source of truth; Redis (when available) caches completed responses.
+type IdempotencyService struct {
+	repo        *models.IdempotencyKeyRepository
+	redisClient *redis.Client
+}
+
+// cachedIdempotentResponse is the Redis representation of a completed request
+type cachedIdempotentResponse struct {
+	Fingerprint string          `json:"fingerprint"`
+	Response    json.RawMessage `json:"response"`
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(db *sql.DB, redisClient *redis.Client) *IdempotencyService {
+	return &IdempotencyService{
+		repo:        models.NewIdempotencyKeyRepository(db),
+		redisClient: redisClient,
+	}
+}
+
+// Begin reserves the key for a new request or returns the stored response of a previous one.
+// A nil response with nil error means the caller should execute the mutation and call Complete.
+func (s *IdempotencyService) Begin(ctx context.Context, userID, operation, key string, args interface{}) (json.RawMessage, error) {
+	fingerprint, err := requestFingerprint(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached := s.getCached(ctx, userID, operation, key); cached != nil {
+		if cached.Fingerprint != fingerprint {
+			return nil, ErrIdempotencyKeyMismatch
+		}
+		return cached.Response, nil
+	}
+
+	reserved, err := s.repo.Reserve(userID, operation, key, fingerprint, IdempotencyLeaseTTL)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := s.repo.Get(userID, operation, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		// Expired between reserve and lookup; let the client retry
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if existing.RequestFingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyMismatch
+	}
+	if existing.Status != models.IdempotencyStatusCompleted {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return existing.Response, nil
+}
+
+// Complete stores the response for a key reserved by Begin
+func (s *IdempotencyService) Complete(ctx context.Context, userID, operation, key string, args interface{}, response interface{}) error {
+	fingerprint, err := requestFingerprint(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+
+	// The mutation already ran: retry briefly rather than leave the key to be executed again
+	for attempt := 1; ; attempt++ {
+		err = s.repo.Complete(userID, operation, key, data, IdempotencyKeyTTL)
+		if err == nil {
+			break
+		}
+		if attempt == idempotencyCompleteAttempts {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+	}
+
+	s.setCached(ctx, userID, operation, key, &cachedIdempotentResponse{
+		Fingerprint: fingerprint,
+		Response:    data,
+	})
+
+	return nil
+}
+
+// KeepAlive renews the lease on a key reserved by Begin until the returned stop function is
+// called, so a request running longer than IdempotencyLeaseTTL is not taken over and run twice
+func (s *IdempotencyService) KeepAlive(userID, operation, key string) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(IdempotencyLeaseRenewal)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				renewed, err := s.repo.Renew(userID, operation, key, IdempotencyLeaseTTL)
+				if err != nil {
+					fmt.Printf("Warning: failed to renew idempotency key %s for %s: %v\n", key, operation, err)
+				} else if !renewed {
+					fmt.Printf("Warning: idempotency key %s for %s is no longer reserved\n", key, operation)
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Abort releases a key reserved by Begin after the mutation failed, so the client can retry
+func (s *IdempotencyService) Abort(userID, operation, key string) {
+	if err := s.repo.Delete(userID, operation, key); err != nil {
+		fmt.Printf("Warning: failed to release idempotency key %s for %s: %v\n", key, operation, err)
+	}
+}
+
+// CleanupExpiredKeys periodically removes expired keys from the database
+// Should be called as a background goroutine
+func (s *IdempotencyService) CleanupExpiredKeys(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := s.repo.DeleteExpired(); err != nil {
+			fmt.Printf("Warning: failed to clean up idempotency keys: %v\n", err)
+		}
+	}
+}
+
+func (s *IdempotencyService) getCached(ctx context.Context, userID, operation, key string) *cachedIdempotentResponse {
+	if s.redisClient == nil {
+		return nil
+	}
+
+	data, err := s.redisClient.Get(ctx, idempotencyCacheKey(userID, operation, key)).Bytes()
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedIdempotentResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+func (s *IdempotencyService) setCached(ctx context.Context, userID, operation, key string, cached *cachedIdempotentResponse) {
+	if s.redisClient == nil {
+		return
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+
+	if err := s.redisClient.Set(ctx, idempotencyCacheKey(userID, operation, key), data, IdempotencyKeyTTL).Err(); err != nil {
+		fmt.Printf("Warning: failed to cache idempotent response in Redis: %v\n", err)
+	}
+}
+
+func idempotencyCacheKey(userID, operation, key string) string {
+	return fmt.Sprintf("idempotency:%s:%s:%s", userID, operation, key)
+}
+
+// requestFingerprint hashes the mutation arguments
+func requestFingerprint(args interface{}) (string, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
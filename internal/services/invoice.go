@@ -1,9 +1,16 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cleanbuddy/backend/internal/config"
@@ -13,27 +20,45 @@ import (
 // InvoiceService handles invoice business logic
 type InvoiceService struct {
 	invoiceRepo  *models.InvoiceRepository
+	lineRepo     *models.InvoiceLineRepository
+	archiveRepo  *models.ANAFConfirmationRepository
 	bookingRepo  *models.BookingRepository
+	addressRepo  *models.AddressRepository
+	billingRepo  *models.BillingProfileRepository
 	userRepo     *models.UserRepository
 	pdfGenerator *PDFGenerator
 	xmlGenerator *XMLGenerator
 	anafClient   *ANAFClient
+	pricing      *PricingService
+	emailService *EmailService
 	config       *config.CompanyConfig
+	anafConfig   *config.ANAFConfig
 }
 
 // NewInvoiceService creates a new invoice service
 func NewInvoiceService(db *sql.DB, companyConfig *config.CompanyConfig, anafConfig *config.ANAFConfig) *InvoiceService {
 	return &InvoiceService{
 		invoiceRepo:  models.NewInvoiceRepository(db),
+		lineRepo:     models.NewInvoiceLineRepository(db),
+		archiveRepo:  models.NewANAFConfirmationRepository(db),
 		bookingRepo:  models.NewBookingRepository(db),
+		addressRepo:  models.NewAddressRepository(db),
+		billingRepo:  models.NewBillingProfileRepository(db),
 		userRepo:     models.NewUserRepository(db),
 		pdfGenerator: NewPDFGenerator("./invoices/pdf"),
 		xmlGenerator: NewXMLGenerator("./invoices/xml", companyConfig),
 		anafClient:   NewANAFClient(anafConfig, companyConfig),
+		pricing:      NewPricingService(db),
 		config:       companyConfig,
+		anafConfig:   anafConfig,
 	}
 }
 
+// SetEmailService sets the email service invoices are delivered to clients with
+func (s *InvoiceService) SetEmailService(emailService *EmailService) {
+	s.emailService = emailService
+}
+
 // CreateInvoiceForBooking auto-creates invoice when booking is completed
 func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invoice, error) {
 	// Get booking details
@@ -80,12 +105,6 @@ func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invo
 		cleanerName = "CleanBuddy Cleaner"
 	}
 
-	// Generate invoice number
-	invoiceNumber, err := s.GenerateInvoiceNumber()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate invoice number: %w", err)
-	}
-
 	// Prepare client name
 	clientName := fmt.Sprintf("%s %s",
 		client.FirstName.String,
@@ -94,6 +113,21 @@ func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invo
 		clientName = "Client"
 	}
 
+	// Bookings made for a company are invoiced to it, with its details as they are today
+	var billing *models.InvoiceBillingDetails
+	var billingEmail sql.NullString
+	if booking.BillingProfileID.Valid {
+		profile, err := s.billingRepo.GetByID(booking.BillingProfileID.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get billing profile: %w", err)
+		}
+		if profile != nil {
+			billing = models.NewInvoiceBillingDetails(profile)
+			billingEmail = profile.Email
+			clientName = profile.LegalName
+		}
+	}
+
 	// Prepare service description
 	serviceDescription := s.buildServiceDescription(booking)
 
@@ -101,34 +135,46 @@ func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invo
 	issueDate := time.Now()
 	dueDate := issueDate.AddDate(0, 0, 14) // 14 days payment term
 
+	// Itemize the booking price with VAT per line
+	// Tips are paid straight to the cleaner and are never part of the service invoice
+	lines := s.invoiceLines(booking, issueDate)
+	var subtotal, taxAmount, totalAmount float64
+	for _, line := range lines {
+		subtotal += line.NetAmount
+		taxAmount += line.VATAmount
+		totalAmount += line.GrossAmount
+	}
+
 	// Create invoice
 	invoice := &models.Invoice{
 		BookingID:          bookingID,
-		InvoiceNumber:      invoiceNumber,
 		IssueDate:          issueDate,
 		DueDate:            dueDate,
 		ClientName:         clientName,
 		CleanerName:        cleanerName,
 		ServiceDescription: serviceDescription,
-		Subtotal:           booking.TotalPrice,
-		TaxAmount:          0, // No tax for now
-		TotalAmount:        booking.TotalPrice,
+		Subtotal:           roundToCents(subtotal),
+		TaxAmount:          roundToCents(taxAmount),
+		TotalAmount:        roundToCents(totalAmount),
 		Currency:           "RON",
 		Status:             models.InvoiceStatusIssued,
 	}
 
-	// Add client email if available
+	// Add client email if available, the company's invoicing address if it has one
 	if client.Email.Valid {
 		invoice.ClientEmail = sql.NullString{String: client.Email.String, Valid: true}
 	}
+	if billingEmail.Valid {
+		invoice.ClientEmail = billingEmail
+	}
 
-	// Create invoice in database
-	if err := s.invoiceRepo.Create(invoice); err != nil {
+	// Create invoice in database, numbered from the active invoice series
+	if err := s.invoiceRepo.CreateWithLines(invoice, lines, billing); err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
 
 	// Generate PDF
-	pdfPath, err := s.pdfGenerator.GenerateInvoicePDF(invoice)
+	pdfPath, err := s.pdfGenerator.GenerateInvoicePDF(invoice, lines, billing)
 	if err != nil {
 		// Log error but don't fail invoice creation
 		fmt.Printf("Warning: failed to generate PDF for invoice %s: %v\n", invoice.ID, err)
@@ -137,7 +183,7 @@ func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invo
 	}
 
 	// Generate XML for ANAF e-Factura
-	xmlPath, err := s.xmlGenerator.GenerateInvoiceXML(invoice, booking)
+	xmlPath, err := s.generateXML(invoice, lines)
 	if err != nil {
 		// Log error but don't fail invoice creation
 		fmt.Printf("Warning: failed to generate XML for invoice %s: %v\n", invoice.ID, err)
@@ -152,24 +198,51 @@ func (s *InvoiceService) CreateInvoiceForBooking(bookingID string) (*models.Invo
 		}
 	}
 
-	// Auto-submit to ANAF if enabled in config
-	// Note: This runs asynchronously to avoid blocking invoice creation
+	// Email the invoice to the client (async, failures are recorded on the invoice for resending)
+	delivered := *invoice
 	go func() {
-		if err := s.SubmitToANAF(invoice.ID); err != nil {
-			fmt.Printf("Warning: failed to auto-submit invoice %s to ANAF: %v\n", invoice.InvoiceNumber, err)
-			// Error is logged but doesn't block invoice creation
-			// Invoice will be picked up by the batch processor later
-		} else {
-			fmt.Printf("Successfully auto-submitted invoice %s to ANAF\n", invoice.InvoiceNumber)
+		if err := s.deliverInvoice(&delivered, false, ""); err != nil {
+			fmt.Printf("Warning: failed to email invoice %s: %v\n", delivered.InvoiceNumber, err)
 		}
 	}()
 
+	// ANAFWorker submits the invoice once anaf.submission_delay_minutes have passed
 	return invoice, nil
 }
 
-// GenerateInvoiceNumber generates next invoice number
-func (s *InvoiceService) GenerateInvoiceNumber() (string, error) {
-	return s.invoiceRepo.GenerateInvoiceNumber()
+// generateXML generates an invoice's e-Factura XML, billed to the company the invoice was issued
+// to or else to the address of its booking
+func (s *InvoiceService) generateXML(invoice *models.Invoice, lines []*models.InvoiceLine) (string, error) {
+	buyerAddress, billing, err := loadInvoiceBuyer(s.invoiceRepo, s.bookingRepo, s.addressRepo, invoice)
+	if err != nil {
+		return "", err
+	}
+	return s.xmlGenerator.GenerateInvoiceXML(invoice, lines, buyerAddress, billing)
+}
+
+// loadInvoiceBuyer returns who an invoice was issued to: the company billed, or else the address
+// of its booking (nil if it is no longer on file)
+func loadInvoiceBuyer(invoiceRepo *models.InvoiceRepository, bookingRepo *models.BookingRepository, addressRepo *models.AddressRepository, invoice *models.Invoice) (*models.Address, *models.InvoiceBillingDetails, error) {
+	billing, err := invoiceRepo.GetBillingDetails(invoice.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if billing != nil {
+		return nil, billing, nil
+	}
+
+	booking, err := bookingRepo.GetByID(invoice.BookingID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, nil, nil
+	}
+	buyerAddress, err := addressRepo.GetByID(booking.AddressID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get booking address: %w", err)
+	}
+	return buyerAddress, nil, nil
 }
 
 // GetInvoiceByID gets an invoice by ID (no auth check - for internal use)
@@ -213,6 +286,15 @@ func (s *InvoiceService) GetInvoice(invoiceID string, userID string) (*models.In
 	return invoice, nil
 }
 
+// IsInvoiceClient reports whether a user is the client billed by an invoice
+func (s *InvoiceService) IsInvoiceClient(invoice *models.Invoice, userID string) (bool, error) {
+	booking, err := s.bookingRepo.GetByID(invoice.BookingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get booking: %w", err)
+	}
+	return booking != nil && booking.ClientID == userID, nil
+}
+
 // GetInvoiceByBookingID gets an invoice by booking ID
 func (s *InvoiceService) GetInvoiceByBookingID(bookingID string, userID string) (*models.Invoice, error) {
 	// Verify ownership through booking
@@ -244,6 +326,108 @@ func (s *InvoiceService) GetMyInvoices(userID string) ([]*models.Invoice, error)
 	return s.invoiceRepo.GetByUserID(userID)
 }
 
+// GetUnpaidInvoices returns issued invoices that have not been paid yet
+func (s *InvoiceService) GetUnpaidInvoices() ([]*models.Invoice, error) {
+	invoices, err := s.invoiceRepo.GetByStatus(models.InvoiceStatusIssued)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unpaid invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// MarkInvoiceAsPaid records that payment for an issued invoice was received
+func (s *InvoiceService) MarkInvoiceAsPaid(invoiceID string) error {
+	if err := s.invoiceRepo.MarkAsPaid(invoiceID); err != nil {
+		return fmt.Errorf("failed to mark invoice %s as paid: %w", invoiceID, err)
+	}
+	return nil
+}
+
+// GetInvoiceLines returns an invoice's itemized lines. Invoices issued before lines were stored
+// get a single service line holding the total, split at the service VAT rate of their issue date.
+func (s *InvoiceService) GetInvoiceLines(invoice *models.Invoice) ([]*models.InvoiceLine, error) {
+	lines, err := s.lineRepo.GetByInvoiceID(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) > 0 {
+		return lines, nil
+	}
+
+	taxed := taxLines([]PriceLine{{
+		Description: invoice.ServiceDescription,
+		Category:    VATCategoryService,
+		Quantity:    1,
+		UnitCode:    "C62",
+		Amount:      invoice.TotalAmount,
+	}}, invoice.TotalAmount, s.config, invoice.IssueDate)
+	return toInvoiceLines(taxed), nil
+}
+
+// invoiceLines itemizes a booking's price into invoice lines with VAT per line, using the
+// add-on prices and VAT rates PricingService quoted with. Add-ons are scaled to the booking's
+// AddonsPrice so later price list changes don't move amounts between lines; supplies have no
+// booking flag and take whatever add-on amount the other add-ons don't account for.
+func (s *InvoiceService) invoiceLines(booking *models.Booking, issueDate time.Time) []*models.InvoiceLine {
+	service := fmt.Sprintf("Serviciu de curățenie - %s", s.translateServiceType(booking.ServiceType))
+	if booking.IncludesDeepCleaning {
+		service += " cu curățenie profundă"
+	}
+	if booking.AreaSqm.Valid {
+		service += fmt.Sprintf(", Suprafață: %d mp", booking.AreaSqm.Int32)
+	}
+	service += fmt.Sprintf(", Data: %s", booking.ScheduledDate.Format("02.01.2006"))
+
+	addons := s.pricing.addonLines(booking.IncludesWindows, booking.NumberOfWindows,
+		booking.IncludesCarpetCleaning, booking.CarpetAreaSqm, booking.IncludesFridgeCleaning,
+		booking.IncludesOvenCleaning, booking.IncludesBalconyCleaning, false)
+	itemized := 0.0
+	for _, line := range addons {
+		itemized += line.Amount
+	}
+	if itemized > booking.AddonsPrice {
+		for i := range addons {
+			addons[i].Amount *= booking.AddonsPrice / itemized
+		}
+	} else if rest := roundToCents(booking.AddonsPrice - itemized); rest > 0 {
+		addons = append(addons, PriceLine{
+			Item:        addonItemSupplies,
+			Description: "Produse de curățenie",
+			Category:    VATCategorySupplies,
+			Quantity:    1,
+			UnitCode:    "C62",
+			Amount:      rest,
+		})
+	}
+
+	lines := append([]PriceLine{{
+		Description: service,
+		Category:    VATCategoryService,
+		Quantity:    float64(booking.EstimatedHours),
+		UnitCode:    "HUR",
+		Amount:      booking.BasePrice,
+	}}, addons...)
+	return toInvoiceLines(taxLines(lines, booking.TotalPrice, s.config, vatDate(booking.ScheduledDate, issueDate)))
+}
+
+func toInvoiceLines(taxed []TaxedLine) []*models.InvoiceLine {
+	lines := make([]*models.InvoiceLine, len(taxed))
+	for i, line := range taxed {
+		lines[i] = &models.InvoiceLine{
+			LineNumber:  i + 1,
+			Description: line.Description,
+			VATCategory: line.Category,
+			Quantity:    line.Quantity,
+			UnitCode:    line.UnitCode,
+			VATRate:     line.VATRate,
+			NetAmount:   line.NetAmount,
+			VATAmount:   line.VATAmount,
+			GrossAmount: line.GrossAmount,
+		}
+	}
+	return lines
+}
+
 // buildServiceDescription builds a human-readable service description
 func (s *InvoiceService) buildServiceDescription(booking *models.Booking) string {
 	desc := fmt.Sprintf("Serviciu de curățenie - %s", s.translateServiceType(booking.ServiceType))
@@ -316,18 +500,15 @@ func (s *InvoiceService) SubmitToANAF(invoiceID string) error {
 		return fmt.Errorf("invoice already submitted to ANAF")
 	}
 
-	// Get booking details for XML generation
-	booking, err := s.bookingRepo.GetByID(invoice.BookingID)
-	if err != nil {
-		return fmt.Errorf("failed to get booking: %w", err)
-	}
-
-	// Read or generate XML content
-	xmlPath := fmt.Sprintf("./invoices/xml/%s.xml", invoice.InvoiceNumber)
-	xmlContent, err := s.xmlGenerator.ReadXML(xmlPath)
-	if err != nil {
-		// Try to regenerate XML if not found
-		_, genErr := s.xmlGenerator.GenerateInvoiceXML(invoice, booking)
+	// Read or generate XML content. A rejected invoice is regenerated so corrections to the
+	// company or client details apply.
+	xmlContent, err := s.xmlGenerator.ReadXML(invoice.XmlURL.String)
+	if !invoice.XmlURL.Valid || err != nil || invoice.ANAFStatus == models.ANAFStatusRejected {
+		lines, linesErr := s.GetInvoiceLines(invoice)
+		if linesErr != nil {
+			return fmt.Errorf("failed to get invoice lines: %w", linesErr)
+		}
+		xmlPath, genErr := s.generateXML(invoice, lines)
 		if genErr != nil {
 			return fmt.Errorf("failed to generate XML: %w", genErr)
 		}
@@ -335,6 +516,20 @@ func (s *InvoiceService) SubmitToANAF(invoiceID string) error {
 		if err != nil {
 			return fmt.Errorf("failed to read XML: %w", err)
 		}
+		invoice.XmlURL = sql.NullString{String: xmlPath, Valid: true}
+		if err := s.invoiceRepo.Update(invoice); err != nil {
+			return fmt.Errorf("failed to update invoice XML: %w", err)
+		}
+	}
+
+	// Don't upload what ANAF would reject: store the violations as the invoice's errors instead
+	if validationErrors := ValidateUBL(xmlContent); len(validationErrors) > 0 {
+		invoice.ANAFStatus = models.ANAFStatusRejected
+		invoice.ANAFErrors = validationErrors
+		if err := s.invoiceRepo.UpdateANAFStatus(invoice); err != nil {
+			return fmt.Errorf("failed to update ANAF status after validation: %w", err)
+		}
+		return fmt.Errorf("invoice %s failed e-Factura validation: %s", invoice.InvoiceNumber, summarizeValidationErrors(validationErrors))
 	}
 
 	// Submit to ANAF
@@ -439,8 +634,9 @@ func (s *InvoiceService) CheckANAFStatus(invoiceID string) error {
 
 // ProcessPendingANAFSubmissions processes invoices pending ANAF submission
 func (s *InvoiceService) ProcessPendingANAFSubmissions(batchSize int) error {
-	// Get pending invoices
-	invoices, err := s.invoiceRepo.GetPendingANAFSubmission(batchSize)
+	// Get invoices past the submission delay or their retry backoff
+	submissionDelay, retryDelay, maxRetries := s.anafSchedule()
+	invoices, err := s.invoiceRepo.GetPendingANAFSubmission(submissionDelay, retryDelay, maxRetries, batchSize)
 	if err != nil {
 		return fmt.Errorf("failed to get pending invoices: %w", err)
 	}
@@ -490,7 +686,8 @@ func (s *InvoiceService) ProcessANAFStatusUpdates() error {
 // RetryFailedANAFSubmissions retries invoices that failed ANAF submission
 func (s *InvoiceService) RetryFailedANAFSubmissions(maxRetries int) error {
 	// Get failed invoices with retry count below max
-	invoices, err := s.invoiceRepo.GetPendingANAFSubmission(10) // Get up to 10 failed invoices
+	submissionDelay, retryDelay, _ := s.anafSchedule()
+	invoices, err := s.invoiceRepo.GetPendingANAFSubmission(submissionDelay, retryDelay, maxRetries, 10) // Get up to 10 failed invoices
 	if err != nil {
 		return fmt.Errorf("failed to get failed invoices: %w", err)
 	}
@@ -510,3 +707,267 @@ func (s *InvoiceService) RetryFailedANAFSubmissions(maxRetries int) error {
 	fmt.Printf("Retried %d failed ANAF submissions\n", retryCount)
 	return nil
 }
+
+// DownloadANAFConfirmations archives the signed response of accepted invoices that don't have
+// one yet and returns how many were archived
+func (s *InvoiceService) DownloadANAFConfirmations(batchSize int) (int, error) {
+	invoices, err := s.invoiceRepo.GetANAFAwaitingConfirmation(batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get accepted invoices: %w", err)
+	}
+
+	archived := 0
+	for _, invoice := range invoices {
+		if err := s.archiveANAFConfirmation(invoice); err != nil {
+			fmt.Printf("Warning: failed to archive ANAF confirmation for invoice %s: %v\n", invoice.InvoiceNumber, err)
+			continue
+		}
+		archived++
+
+		// Send the client the legally valid copy
+		if !invoice.SignedCopySentAt.Valid {
+			if err := s.deliverInvoice(invoice, true, ""); err != nil {
+				fmt.Printf("Warning: failed to email ANAF validated invoice %s: %v\n", invoice.InvoiceNumber, err)
+			}
+		}
+	}
+	return archived, nil
+}
+
+// archiveANAFConfirmation archives an accepted invoice's signed response and links the
+// archived file from the invoice
+func (s *InvoiceService) archiveANAFConfirmation(invoice *models.Invoice) error {
+	path, err := s.archiveANAFResponse(&models.ANAFDocument{
+		Type:           models.ANAFDocumentInvoice,
+		ID:             invoice.ID,
+		Number:         invoice.InvoiceNumber,
+		IssueDate:      invoice.IssueDate,
+		ANAFDownloadID: invoice.ANAFDownloadID,
+	})
+	if err != nil {
+		return err
+	}
+
+	invoice.ANAFConfirmationURL = sql.NullString{String: path, Valid: true}
+	if err := s.invoiceRepo.UpdateANAFStatus(invoice); err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+	return nil
+}
+
+// archiveANAFResponse downloads an accepted document's signed response and stores it in the
+// archive with its checksum, returning the archived file's path. Files are grouped by year of
+// issue and never overwritten: a file left by an earlier attempt that crashed before recording
+// it is kept if it holds the same response.
+func (s *InvoiceService) archiveANAFResponse(doc *models.ANAFDocument) (string, error) {
+	content, err := s.anafClient.DownloadConfirmation(context.Background(), doc.ANAFDownloadID.String)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.HasPrefix(content, []byte("PK")) {
+		return "", fmt.Errorf("ANAF response for download %s is not a ZIP archive", doc.ANAFDownloadID.String)
+	}
+
+	dir := filepath.Join(s.anafArchiveDir(), doc.IssueDate.Format("2006"))
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.zip", doc.Number, doc.ANAFDownloadID.String))
+	checksum := sha256.Sum256(content)
+	if err := writeArchiveFile(path, content, checksum); err != nil {
+		return "", err
+	}
+
+	confirmation := &models.ANAFConfirmation{
+		DocumentType: doc.Type,
+		DocumentID:   doc.ID,
+		DownloadID:   doc.ANAFDownloadID.String,
+		FilePath:     path,
+		SHA256:       hex.EncodeToString(checksum[:]),
+		SizeBytes:    int64(len(content)),
+		RetainUntil:  s.anafRetainUntil(doc.IssueDate),
+	}
+	if err := s.archiveRepo.Create(confirmation); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeArchiveFile writes content to path without ever overwriting it. The content is written to
+// a temporary file first and linked into place, so path only ever holds a complete file. If path
+// already exists it is accepted when it holds the same content.
+func writeArchiveFile(path string, content []byte, checksum [sha256.Size]byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0440); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	err = os.Link(tmp.Name(), path)
+	if err == nil {
+		return nil
+	}
+	if !os.IsExist(err) {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read existing archive file: %w", err)
+	}
+	if sha256.Sum256(existing) != checksum {
+		return fmt.Errorf("archive file %s already exists with different content", path)
+	}
+	return nil
+}
+
+// GetANAFConfirmation returns the archived signed response of an invoice with its file name,
+// after checking the file still matches the checksum taken when it was archived
+func (s *InvoiceService) GetANAFConfirmation(invoice *models.Invoice) (*models.ANAFConfirmation, []byte, error) {
+	confirmation, err := s.archiveRepo.GetByInvoiceID(invoice.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if confirmation == nil {
+		return nil, nil, fmt.Errorf("the ANAF confirmation of invoice %s is not available yet", invoice.InvoiceNumber)
+	}
+
+	content, err := os.ReadFile(confirmation.FilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read archived ANAF confirmation: %w", err)
+	}
+	checksum := sha256.Sum256(content)
+	if hex.EncodeToString(checksum[:]) != confirmation.SHA256 {
+		return nil, nil, fmt.Errorf("archived ANAF confirmation of invoice %s does not match its checksum", invoice.InvoiceNumber)
+	}
+	return confirmation, content, nil
+}
+
+// ResendInvoiceEmail emails an invoice to its client again, or to toEmail if given: the copy
+// validated by ANAF once its signed response is archived, otherwise the invoice as issued
+func (s *InvoiceService) ResendInvoiceEmail(invoiceID, toEmail string) (*models.Invoice, error) {
+	invoice, err := s.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	confirmation, err := s.archiveRepo.GetByInvoiceID(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliverInvoice(invoice, confirmation != nil, strings.TrimSpace(toEmail)); err != nil {
+		return nil, fmt.Errorf("failed to email invoice %s: %w", invoice.InvoiceNumber, err)
+	}
+	return invoice, nil
+}
+
+// deliverInvoice emails an invoice and records the outcome on it
+func (s *InvoiceService) deliverInvoice(invoice *models.Invoice, signedCopy bool, toEmail string) error {
+	if s.emailService == nil {
+		return fmt.Errorf("email service not configured")
+	}
+	if toEmail == "" {
+		toEmail = invoice.ClientEmail.String
+	}
+
+	sendErr := s.sendInvoiceEmail(invoice, signedCopy, toEmail)
+	if err := s.invoiceRepo.RecordEmailDelivery(invoice, signedCopy, sendErr); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	return sendErr
+}
+
+// sendInvoiceEmail emails an invoice's PDF with its e-Factura XML, or with ANAF's signed
+// response for the validated copy
+func (s *InvoiceService) sendInvoiceEmail(invoice *models.Invoice, signedCopy bool, toEmail string) error {
+	if toEmail == "" {
+		return fmt.Errorf("invoice %s has no client email address", invoice.InvoiceNumber)
+	}
+
+	var attachments []EmailAttachment
+	if invoice.PdfURL.Valid {
+		content, err := os.ReadFile(invoice.PdfURL.String)
+		if err != nil {
+			return fmt.Errorf("failed to read invoice PDF: %w", err)
+		}
+		attachments = append(attachments, EmailAttachment{
+			Name:    invoice.InvoiceNumber + ".pdf",
+			Content: base64.StdEncoding.EncodeToString(content),
+		})
+	}
+	if signedCopy {
+		_, content, err := s.GetANAFConfirmation(invoice)
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, EmailAttachment{
+			Name:    invoice.InvoiceNumber + "_ANAF.zip",
+			Content: base64.StdEncoding.EncodeToString(content),
+		})
+	} else if invoice.XmlURL.Valid {
+		content, err := os.ReadFile(invoice.XmlURL.String)
+		if err != nil {
+			return fmt.Errorf("failed to read invoice XML: %w", err)
+		}
+		attachments = append(attachments, EmailAttachment{
+			Name:    invoice.InvoiceNumber + ".xml",
+			Content: base64.StdEncoding.EncodeToString(content),
+		})
+	}
+	if len(attachments) == 0 {
+		return fmt.Errorf("invoice %s has no files to send", invoice.InvoiceNumber)
+	}
+
+	return s.emailService.SendInvoiceEmail(context.Background(), toEmail, invoice.ClientName, invoice.InvoiceNumber,
+		invoice.TotalAmount, invoice.Currency, signedCopy, attachments)
+}
+
+// anafArchiveDir returns the directory ANAF confirmations are archived in
+func (s *InvoiceService) anafArchiveDir() string {
+	if s.anafConfig.ArchiveDir == "" {
+		return "./archive/anaf"
+	}
+	return s.anafConfig.ArchiveDir
+}
+
+// anafRetainUntil returns the date until which an invoice's e-invoice must be kept: the end of
+// the retention period counted from the end of the year of issue
+func (s *InvoiceService) anafRetainUntil(issueDate time.Time) time.Time {
+	years := s.anafConfig.RetentionYears
+	if years <= 0 {
+		years = 10
+	}
+	return time.Date(issueDate.Year()+years, time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// anafSchedule returns the configured submission delay, base retry delay and retry limit
+func (s *InvoiceService) anafSchedule() (time.Duration, time.Duration, int) {
+	return anafSchedule(s.anafConfig)
+}
+
+// anafSchedule returns the submission delay, base retry delay and retry limit of an ANAF
+// configuration, shared by every document type filed with ANAF
+func anafSchedule(cfg *config.ANAFConfig) (time.Duration, time.Duration, int) {
+	maxRetries := cfg.MaxRetryAttempts
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	return time.Duration(cfg.SubmissionDelayMinutes) * time.Minute,
+		time.Duration(cfg.RetryDelayMinutes) * time.Minute,
+		maxRetries
+}
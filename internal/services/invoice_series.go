@@ -0,0 +1,93 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// seriesPrefixPattern is what an invoice series prefix may look like: a letter followed by up
+// to nine letters or digits
+var seriesPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+
+// InvoiceSeriesService manages the numbering series of invoices, credit notes, commission
+// invoices and proformas
+type InvoiceSeriesService struct {
+	seriesRepo *models.InvoiceSeriesRepository
+}
+
+// NewInvoiceSeriesService creates a new invoice series service
+func NewInvoiceSeriesService(db *sql.DB) *InvoiceSeriesService {
+	return &InvoiceSeriesService{
+		seriesRepo: models.NewInvoiceSeriesRepository(db),
+	}
+}
+
+// ListSeries returns all numbering series
+func (s *InvoiceSeriesService) ListSeries() ([]*models.InvoiceSeries, error) {
+	return s.seriesRepo.List()
+}
+
+// CreateSeries adds a numbering series for a document type, its first document numbered
+// startNumber (to carry on from numbering done elsewhere). With activate, documents of the type
+// are numbered from it from now on.
+func (s *InvoiceSeriesService) CreateSeries(documentType models.DocumentType, prefix string, yearlyReset, activate bool, startNumber int, adminID string) (*models.InvoiceSeries, error) {
+	switch documentType {
+	case models.DocumentTypeInvoice, models.DocumentTypeCreditNote, models.DocumentTypeCommissionInvoice, models.DocumentTypeProforma:
+	default:
+		return nil, fmt.Errorf("invalid document type: %s", documentType)
+	}
+
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	if !seriesPrefixPattern.MatchString(prefix) {
+		return nil, fmt.Errorf("series prefix must be 1 to 10 letters or digits, starting with a letter")
+	}
+	if startNumber < 1 {
+		return nil, fmt.Errorf("start number must be at least 1")
+	}
+
+	existing, err := s.seriesRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if other.Prefix == prefix {
+			return nil, fmt.Errorf("series prefix %s is already in use", prefix)
+		}
+	}
+
+	series := &models.InvoiceSeries{
+		DocumentType: documentType,
+		Prefix:       prefix,
+		YearlyReset:  yearlyReset,
+		IsActive:     activate,
+		CreatedBy:    sql.NullString{String: adminID, Valid: adminID != ""},
+	}
+	if err := s.seriesRepo.Create(series, startNumber); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// ActivateSeries switches a document type to number from the series. The series it replaces
+// keeps its counters, so switching back carries on where it stopped.
+func (s *InvoiceSeriesService) ActivateSeries(id string) (*models.InvoiceSeries, error) {
+	series, err := s.seriesRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if series == nil {
+		return nil, fmt.Errorf("invoice series not found")
+	}
+	if series.IsActive {
+		return series, nil
+	}
+
+	if err := s.seriesRepo.Activate(id); err != nil {
+		return nil, err
+	}
+	return s.seriesRepo.GetByID(id)
+}
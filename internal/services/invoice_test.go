@@ -0,0 +1,73 @@
+package services
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// A tipped booking is invoiced for its price only: the tip is charged as a separate TIP payment
+// and never reaches the invoice lines, so it is outside the VAT base
+func TestInvoiceLinesExcludeTips(t *testing.T) {
+	cfg := &config.Config{Company: testLedgerService().cfg.Company}
+	s := &InvoiceService{config: &cfg.Company, pricing: &PricingService{cfg: cfg}}
+
+	booking := &models.Booking{
+		ID:             "booking-1",
+		ServiceType:    models.ServiceTypeStandard,
+		EstimatedHours: 3,
+		BasePrice:      242,
+		TotalPrice:     242,
+		ScheduledDate:  time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	tip := &models.Tip{BookingID: booking.ID, Amount: 30}
+
+	var net, vat, gross float64
+	for _, line := range s.invoiceLines(booking, booking.ScheduledDate) {
+		net += line.NetAmount
+		vat += line.VATAmount
+		gross += line.GrossAmount
+	}
+
+	if roundToCents(gross) != booking.TotalPrice {
+		t.Errorf("invoiced %.2f, want the booking price %.2f without the %.2f tip", gross, booking.TotalPrice, tip.Amount)
+	}
+	if roundToCents(net) != 200 || roundToCents(vat) != 42 {
+		t.Errorf("VAT base %.2f with VAT %.2f, want 200.00 and 42.00", net, vat)
+	}
+}
+
+// An archive file left by an attempt that crashed before recording it must not block the retry
+func TestWriteArchiveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CB-2025-0042_123.zip")
+	content := []byte("PK signed response")
+
+	if err := writeArchiveFile(path, content, sha256.Sum256(content)); err != nil {
+		t.Fatalf("writeArchiveFile() returned error: %v", err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != string(content) {
+		t.Fatalf("archived %q (%v), want %q", got, err, content)
+	}
+
+	if err := writeArchiveFile(path, content, sha256.Sum256(content)); err != nil {
+		t.Errorf("rewriting the same response returned error: %v", err)
+	}
+
+	other := []byte("PK another response")
+	if err := writeArchiveFile(path, other, sha256.Sum256(other)); err == nil {
+		t.Error("writeArchiveFile() accepted a different response over an archived file")
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(content) {
+		t.Errorf("archived file was overwritten with %q", got)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("archive directory holds %d files, want the archived file only", len(entries))
+	}
+}
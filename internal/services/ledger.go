@@ -0,0 +1,316 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// LedgerService posts balanced double-entry transactions for every money movement
+type LedgerService struct {
+	ledgerRepo  *models.LedgerRepository
+	bookingRepo *models.BookingRepository
+	cleanerRepo *models.CleanerRepository
+	cfg         *config.Config
+}
+
+// TrialBalance is the per-account summary of the ledger
+type TrialBalance struct {
+	AsOf        time.Time
+	Accounts    []*models.LedgerAccountBalance
+	TotalDebit  float64
+	TotalCredit float64
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(db *sql.DB) *LedgerService {
+	return &LedgerService{
+		ledgerRepo:  models.NewLedgerRepository(db),
+		bookingRepo: models.NewBookingRepository(db),
+		cleanerRepo: models.NewCleanerRepository(db),
+		cfg:         config.Get(),
+	}
+}
+
+// PostCapture records client money collected for a booking and splits it between
+// the cleaner's payable, platform revenue and VAT on the commission.
+// Cash is already in the cleaner's hands, so their payable is debited with the full amount
+// and the platform's share becomes a receivable from the cleaner. Bank transfers are already
+// in the platform's account.
+func (s *LedgerService) PostCapture(payment *models.Payment) error {
+	booking, err := s.bookingRepo.GetByID(payment.BookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return fmt.Errorf("booking not found")
+	}
+
+	collected := &models.LedgerEntry{Account: models.LedgerAccountClientReceivables, Debit: roundToCents(payment.Amount)}
+	if payment.Provider == models.PaymentProviderCash {
+		collected = &models.LedgerEntry{Account: models.LedgerAccountCleanerPayables, CleanerID: booking.CleanerID, Debit: roundToCents(payment.Amount)}
+	}
+	if payment.Provider == models.PaymentProviderBankTransfer {
+		collected = &models.LedgerEntry{Account: models.LedgerAccountCash, Debit: roundToCents(payment.Amount)}
+	}
+
+	entries := append([]*models.LedgerEntry{collected}, s.earningEntries(booking, payment.Amount)...)
+
+	return s.post(models.LedgerTransactionCapture, payment.ID, booking.ID,
+		fmt.Sprintf("Payment captured for booking %s", booking.ID), entries)
+}
+
+// PostTip records a tip collected from a client; the whole amount is owed to the cleaner,
+// with no commission and no VAT
+func (s *LedgerService) PostTip(payment *models.Payment, tip *models.Tip) error {
+	return s.post(models.LedgerTransactionTip, payment.ID, tip.BookingID,
+		fmt.Sprintf("Tip for booking %s", tip.BookingID), tipEntries(payment, tip))
+}
+
+func tipEntries(payment *models.Payment, tip *models.Tip) []*models.LedgerEntry {
+	amount := roundToCents(payment.Amount)
+	return []*models.LedgerEntry{
+		{Account: models.LedgerAccountClientReceivables, Debit: amount},
+		{Account: models.LedgerAccountCleanerPayables, CleanerID: sql.NullString{String: tip.CleanerID, Valid: true}, Credit: amount},
+	}
+}
+
+// PostWalletCredit records store credit issued to a client. Dispute refunds are funded like card
+// refunds (cleaner and platform shares); gift cards were paid for; other credits are a platform cost.
+func (s *LedgerService) PostWalletCredit(txn *models.WalletTransaction, booking *models.Booking) error {
+	amount := roundToCents(txn.Amount)
+
+	var entries []*models.LedgerEntry
+	switch {
+	case txn.Source == models.WalletSourceDisputeRefund && booking != nil:
+		cleanerShare, platformShare := s.splitAmount(booking, amount)
+		entries = []*models.LedgerEntry{
+			{Account: models.LedgerAccountCleanerPayables, CleanerID: booking.CleanerID, Debit: cleanerShare},
+			{Account: models.LedgerAccountRefunds, Debit: platformShare},
+		}
+	case txn.Source == models.WalletSourceGiftCard:
+		entries = []*models.LedgerEntry{{Account: models.LedgerAccountCash, Debit: amount}}
+	default:
+		entries = []*models.LedgerEntry{{Account: models.LedgerAccountPlatformRevenue, Debit: amount}}
+	}
+	entries = append(entries, &models.LedgerEntry{Account: models.LedgerAccountClientCredits, Credit: amount})
+
+	bookingID := ""
+	if booking != nil {
+		bookingID = booking.ID
+	}
+
+	return s.post(models.LedgerTransactionWalletCredit, txn.ID, bookingID,
+		fmt.Sprintf("%s credit issued to client %s", txn.Source, txn.UserID), entries)
+}
+
+// PostWalletRedemption records wallet credit spent on a booking once the service is earned;
+// the amount is split like a card capture
+func (s *LedgerService) PostWalletRedemption(booking *models.Booking, amount float64) error {
+	entries := append([]*models.LedgerEntry{
+		{Account: models.LedgerAccountClientCredits, Debit: roundToCents(amount)},
+	}, s.earningEntries(booking, amount)...)
+
+	return s.post(models.LedgerTransactionWalletRedemption, booking.ID, booking.ID,
+		fmt.Sprintf("Wallet credit redeemed for booking %s", booking.ID), entries)
+}
+
+// PostWalletExpiry releases expired, unspent credit back to the platform
+func (s *LedgerService) PostWalletExpiry(txn *models.WalletTransaction) error {
+	amount := roundToCents(-txn.Amount)
+	entries := []*models.LedgerEntry{
+		{Account: models.LedgerAccountClientCredits, Debit: amount},
+		{Account: models.LedgerAccountPlatformRevenue, Credit: amount},
+	}
+
+	return s.post(models.LedgerTransactionWalletExpiry, txn.ID, "",
+		fmt.Sprintf("Wallet credit expired for client %s", txn.UserID), entries)
+}
+
+// PostRefund records money returned to a client; the cleaner and the platform
+// give back their shares in the same proportion as the original capture
+func (s *LedgerService) PostRefund(refund *models.Payment) error {
+	booking, err := s.bookingRepo.GetByID(refund.BookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return fmt.Errorf("booking not found")
+	}
+
+	cleanerShare, platformShare := s.splitAmount(booking, refund.Amount)
+
+	entries := []*models.LedgerEntry{
+		{Account: models.LedgerAccountCleanerPayables, CleanerID: booking.CleanerID, Debit: cleanerShare},
+		{Account: models.LedgerAccountRefunds, Debit: platformShare},
+		{Account: models.LedgerAccountClientReceivables, Credit: roundToCents(refund.Amount)},
+	}
+
+	return s.post(models.LedgerTransactionRefund, refund.ID, booking.ID,
+		fmt.Sprintf("Refund for booking %s", booking.ID), entries)
+}
+
+// PostPayout records money transferred to a cleaner from the platform bank account,
+// or received from a cleaner when the payout was negative
+func (s *LedgerService) PostPayout(payout *models.Payout) error {
+	// Payouts reference the cleaner's user ID; the ledger sub-ledger uses cleaners.id
+	cleaner, err := s.cleanerRepo.GetByUserID(payout.CleanerID)
+	if err != nil {
+		return fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return fmt.Errorf("cleaner not found")
+	}
+
+	amount := roundToCents(payout.NetAmount)
+	cleanerAccount := sql.NullString{String: cleaner.ID, Valid: true}
+	entries := []*models.LedgerEntry{
+		{Account: models.LedgerAccountCleanerPayables, CleanerID: cleanerAccount, Debit: amount},
+		{Account: models.LedgerAccountCash, Credit: amount},
+	}
+	// An invoiced payout is money the cleaner paid us (cash fees)
+	if amount < 0 {
+		entries = []*models.LedgerEntry{
+			{Account: models.LedgerAccountCash, Debit: -amount},
+			{Account: models.LedgerAccountCleanerPayables, CleanerID: cleanerAccount, Credit: -amount},
+		}
+	}
+
+	return s.post(models.LedgerTransactionPayout, payout.ID, "",
+		fmt.Sprintf("Payout %s - %s", payout.PeriodStart.Format("2006-01-02"), payout.PeriodEnd.Format("2006-01-02")), entries)
+}
+
+// PostAdjustment records a manual change to a cleaner's balance.
+// Positive amounts (bonuses) are paid by the platform; negative amounts (clawbacks) return to it.
+func (s *LedgerService) PostAdjustment(referenceID, cleanerID, bookingID string, amount float64, description string) error {
+	amount = roundToCents(amount)
+	cleanerAccount := sql.NullString{String: cleanerID, Valid: true}
+
+	var entries []*models.LedgerEntry
+	if amount >= 0 {
+		entries = []*models.LedgerEntry{
+			{Account: models.LedgerAccountPlatformRevenue, Debit: amount},
+			{Account: models.LedgerAccountCleanerPayables, CleanerID: cleanerAccount, Credit: amount},
+		}
+	} else {
+		entries = []*models.LedgerEntry{
+			{Account: models.LedgerAccountCleanerPayables, CleanerID: cleanerAccount, Debit: -amount},
+			{Account: models.LedgerAccountPlatformRevenue, Credit: -amount},
+		}
+	}
+
+	return s.post(models.LedgerTransactionAdjustment, referenceID, bookingID, description, entries)
+}
+
+// GetTrialBalance returns per-account totals; total debits always equal total credits
+func (s *LedgerService) GetTrialBalance(asOf time.Time) (*TrialBalance, error) {
+	accounts, err := s.ledgerRepo.GetTrialBalance(asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trial balance: %w", err)
+	}
+
+	balance := &TrialBalance{AsOf: asOf, Accounts: accounts}
+	for _, account := range accounts {
+		balance.TotalDebit += account.TotalDebit
+		balance.TotalCredit += account.TotalCredit
+	}
+	balance.TotalDebit = roundToCents(balance.TotalDebit)
+	balance.TotalCredit = roundToCents(balance.TotalCredit)
+
+	return balance, nil
+}
+
+// GetCleanerBalance returns what the platform owes a cleaner (cleaners.id) as of the given time
+func (s *LedgerService) GetCleanerBalance(cleanerID string, asOf time.Time) (float64, error) {
+	balance, err := s.ledgerRepo.GetCleanerBalance(cleanerID, asOf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cleaner balance: %w", err)
+	}
+	return roundToCents(balance), nil
+}
+
+// GetCleanerBalanceForBookings returns the part of a cleaner's balance earned on the given bookings
+func (s *LedgerService) GetCleanerBalanceForBookings(cleanerID string, bookingIDs []string, asOf time.Time) (float64, error) {
+	balance, err := s.ledgerRepo.GetCleanerBalanceForBookings(cleanerID, bookingIDs, asOf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cleaner booking balance: %w", err)
+	}
+	return roundToCents(balance), nil
+}
+
+// GetCleanerBalanceByUserID returns the current balance for the cleaner profile of a user
+func (s *LedgerService) GetCleanerBalanceByUserID(userID string) (float64, error) {
+	cleaner, err := s.cleanerRepo.GetByUserID(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return 0, fmt.Errorf("cleaner profile not found")
+	}
+	return s.GetCleanerBalance(cleaner.ID, time.Now())
+}
+
+// HasCleanerEntries reports whether the ledger tracks any earnings for a cleaner
+func (s *LedgerService) HasCleanerEntries(cleanerID string) (bool, error) {
+	return s.ledgerRepo.HasCleanerEntries(cleanerID)
+}
+
+// earningEntries credits an amount paid for a booking to the cleaner's payable,
+// platform revenue and VAT on the commission
+func (s *LedgerService) earningEntries(booking *models.Booking, amount float64) []*models.LedgerEntry {
+	cleanerShare, platformShare := s.splitAmount(booking, amount)
+	vat := s.commissionVAT(platformShare, vatDate(booking.ScheduledDate, time.Now()))
+
+	return []*models.LedgerEntry{
+		{Account: models.LedgerAccountCleanerPayables, CleanerID: booking.CleanerID, Credit: cleanerShare},
+		{Account: models.LedgerAccountPlatformRevenue, Credit: roundToCents(platformShare - vat)},
+		{Account: models.LedgerAccountVATPayable, Credit: vat},
+	}
+}
+
+// splitAmount divides an amount between cleaner and platform using the booking's price split
+func (s *LedgerService) splitAmount(booking *models.Booking, amount float64) (float64, float64) {
+	if booking.TotalPrice <= 0 || !booking.CleanerID.Valid {
+		return 0, roundToCents(amount)
+	}
+
+	cleanerShare := roundToCents(amount * booking.CleanerPayout / booking.TotalPrice)
+	return cleanerShare, roundToCents(amount - cleanerShare)
+}
+
+// commissionVAT extracts the VAT included in the platform commission at the service rate
+// in force on the date of supply
+func (s *LedgerService) commissionVAT(commission float64, date time.Time) float64 {
+	rate := vatRateFor(&s.cfg.Company, VATCategoryService, date)
+	if rate <= 0 {
+		return 0
+	}
+	_, vat := utils.SplitGross(commission, rate)
+	return vat
+}
+
+func (s *LedgerService) post(txnType models.LedgerTransactionType, referenceID, bookingID, description string, entries []*models.LedgerEntry) error {
+	// Zero-amount lines carry no information and violate the entry constraint
+	var lines []*models.LedgerEntry
+	for _, entry := range entries {
+		if entry.Debit != 0 || entry.Credit != 0 {
+			lines = append(lines, entry)
+		}
+	}
+
+	txn := &models.LedgerTransaction{
+		TransactionType: txnType,
+		ReferenceID:     referenceID,
+		BookingID:       sql.NullString{String: bookingID, Valid: bookingID != ""},
+		Description:     description,
+	}
+
+	if _, err := s.ledgerRepo.Post(txn, lines); err != nil {
+		return fmt.Errorf("failed to post %s to ledger: %w", txnType, err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func testLedgerService() *LedgerService {
+	return &LedgerService{cfg: &config.Config{Company: config.CompanyConfig{
+		VATRegistered: true,
+		VATRate:       0.19,
+		VATRates: []config.VATRateConfig{
+			{Rate: 0.19, EffectiveFrom: "2017-01-01"},
+			{Rate: 0.21, EffectiveFrom: "2025-08-01"},
+		},
+	}}}
+}
+
+func TestCommissionVAT(t *testing.T) {
+	s := testLedgerService()
+
+	tests := []struct {
+		name       string
+		commission float64
+		date       time.Time
+		want       float64
+	}{
+		{"19% before August 2025", 119, time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), 19},
+		{"21% from August 2025", 121, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), 21},
+		{"rounded to cents", 30, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), 5.21},
+		{"no commission", 0, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.commissionVAT(tt.commission, tt.date); got != tt.want {
+				t.Errorf("commissionVAT(%.2f) = %.2f, want %.2f", tt.commission, got, tt.want)
+			}
+		})
+	}
+
+	s.cfg.Company.VATRegistered = false
+	if got := s.commissionVAT(121, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)); got != 0 {
+		t.Errorf("commissionVAT when not VAT registered = %.2f, want 0", got)
+	}
+}
+
+func TestEarningEntries(t *testing.T) {
+	s := testLedgerService()
+	booking := &models.Booking{
+		ID:            "booking-1",
+		CleanerID:     sql.NullString{String: "cleaner-1", Valid: true},
+		TotalPrice:    200,
+		CleanerPayout: 158.60,
+		ScheduledDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	entries := s.earningEntries(booking, 200)
+
+	credits := map[models.LedgerAccount]float64{}
+	total := 0.0
+	for _, entry := range entries {
+		if entry.Debit != 0 {
+			t.Errorf("unexpected debit on %s: %.2f", entry.Account, entry.Debit)
+		}
+		credits[entry.Account] += entry.Credit
+		total += entry.Credit
+	}
+
+	if got := roundToCents(total); got != 200 {
+		t.Errorf("entries credit %.2f, want the captured 200.00", got)
+	}
+	if got := credits[models.LedgerAccountCleanerPayables]; got != 158.60 {
+		t.Errorf("cleaner payable = %.2f, want 158.60", got)
+	}
+	if got := credits[models.LedgerAccountVATPayable]; got != 7.19 {
+		t.Errorf("VAT payable = %.2f, want 7.19 (21%% of the 41.40 commission)", got)
+	}
+	if got := credits[models.LedgerAccountPlatformRevenue]; got != 34.21 {
+		t.Errorf("platform revenue = %.2f, want 34.21", got)
+	}
+}
+
+func TestEarningEntriesWithoutCleaner(t *testing.T) {
+	s := testLedgerService()
+	booking := &models.Booking{
+		ID:            "booking-2",
+		TotalPrice:    121,
+		ScheduledDate: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	credits := map[models.LedgerAccount]float64{}
+	for _, entry := range s.earningEntries(booking, 121) {
+		credits[entry.Account] += entry.Credit
+	}
+
+	if got := credits[models.LedgerAccountCleanerPayables]; got != 0 {
+		t.Errorf("cleaner payable = %.2f, want 0 for an unassigned booking", got)
+	}
+	if got := credits[models.LedgerAccountVATPayable]; got != 21 {
+		t.Errorf("VAT payable = %.2f, want 21.00", got)
+	}
+	if got := credits[models.LedgerAccountPlatformRevenue]; got != 100 {
+		t.Errorf("platform revenue = %.2f, want 100.00", got)
+	}
+}
+
+func TestTipEntriesExcludeVAT(t *testing.T) {
+	payment := &models.Payment{ID: "payment-tip", Amount: 25, PaymentType: models.PaymentTypeTip}
+	tip := &models.Tip{BookingID: "booking-1", CleanerID: "cleaner-1", Amount: 25}
+
+	debit, credit := 0.0, 0.0
+	for _, entry := range tipEntries(payment, tip) {
+		switch entry.Account {
+		case models.LedgerAccountVATPayable, models.LedgerAccountPlatformRevenue:
+			t.Errorf("tip posted to %s; tips carry no commission and no VAT", entry.Account)
+		case models.LedgerAccountCleanerPayables:
+			if entry.CleanerID.String != "cleaner-1" {
+				t.Errorf("tip credited to cleaner %q, want cleaner-1", entry.CleanerID.String)
+			}
+		}
+		debit += entry.Debit
+		credit += entry.Credit
+	}
+
+	if debit != 25 || credit != 25 {
+		t.Errorf("tip entries debit %.2f and credit %.2f, want 25.00 each", debit, credit)
+	}
+}
@@ -12,9 +12,12 @@ import (
 
 // PaymentService handles payment processing
 type PaymentService struct {
-	paymentRepo *models.PaymentRepository
-	bookingRepo *models.BookingRepository
-	cfg         *config.Config
+	paymentRepo   *models.PaymentRepository
+	bookingRepo   *models.BookingRepository
+	ledgerService *LedgerService
+	creditNotes   *CreditNoteService
+	proformas     *ProformaService
+	cfg           *config.Config
 }
 
 // NewPaymentService creates a new payment service
@@ -26,6 +29,21 @@ func NewPaymentService(db *sql.DB) *PaymentService {
 	}
 }
 
+// SetLedgerService sets the ledger service used to record captures and refunds
+func (s *PaymentService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// SetCreditNoteService sets the service that credits the invoice of a refunded booking
+func (s *PaymentService) SetCreditNoteService(creditNotes *CreditNoteService) {
+	s.creditNotes = creditNotes
+}
+
+// SetProformaService sets the service that issues proformas for bank transfer payments
+func (s *PaymentService) SetProformaService(proformas *ProformaService) {
+	s.proformas = proformas
+}
+
 // PreauthorizePayment creates a payment preauthorization for a booking
 // This holds the funds on the customer's card without capturing them
 func (s *PaymentService) PreauthorizePayment(
@@ -46,6 +64,26 @@ func (s *PaymentService) PreauthorizePayment(
 		return nil, fmt.Errorf("booking does not belong to user")
 	}
 
+	// Wallet credit already covers part of the price; never hold more than the remainder
+	amountDue := roundToCents(booking.AmountDue())
+	if amountDue <= 0 {
+		return nil, fmt.Errorf("booking is fully paid with wallet credit")
+	}
+	if amount > amountDue {
+		amount = amountDue
+	}
+
+	if provider == models.PaymentProviderCash {
+		if err := s.validateCashBooking(booking); err != nil {
+			return nil, err
+		}
+	}
+	if provider == models.PaymentProviderBankTransfer {
+		if err := s.validateBankTransferBooking(booking); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create payment record
 	payment := &models.Payment{
 		BookingID:   bookingID,
@@ -63,11 +101,81 @@ func (s *PaymentService) PreauthorizePayment(
 		return s.netopiaPreauthorize(payment)
 	case models.PaymentProviderManual:
 		return s.manualPreauthorize(payment)
+	case models.PaymentProviderCash:
+		return s.cashPreauthorize(payment)
+	case models.PaymentProviderBankTransfer:
+		return s.bankTransferPreauthorize(payment, booking)
 	default:
 		return nil, fmt.Errorf("unsupported payment provider: %s", provider)
 	}
 }
 
+// AuthorizeTip places a hold for a tip on a completed booking; CaptureTip charges it once
+// the tip is recorded, CancelPreauthorization releases it otherwise.
+// With useSavedCard the card used for the booking is charged again; otherwise a new
+// payment is started with the given provider.
+func (s *PaymentService) AuthorizeTip(booking *models.Booking, userID string, amount float64, provider models.PaymentProvider, useSavedCard bool) (*models.Payment, error) {
+	payment := &models.Payment{
+		BookingID:   booking.ID,
+		UserID:      userID,
+		Provider:    provider,
+		PaymentType: models.PaymentTypeTip,
+		Status:      models.PaymentStatusPending,
+		Amount:      amount,
+		Currency:    "RON",
+	}
+
+	if useSavedCard {
+		saved, err := s.findSavedCard(booking.ID)
+		if err != nil {
+			return nil, err
+		}
+		payment.Provider = saved.Provider
+		payment.CardLastFour = saved.CardLastFour
+		payment.CardBrand = saved.CardBrand
+	}
+
+	switch payment.Provider {
+	case models.PaymentProviderNetopia:
+		return s.netopiaPreauthorize(payment)
+	case models.PaymentProviderManual:
+		return s.manualPreauthorize(payment)
+	default:
+		return nil, fmt.Errorf("unsupported payment provider: %s", payment.Provider)
+	}
+}
+
+// CaptureTip charges a tip authorized with AuthorizeTip
+func (s *PaymentService) CaptureTip(payment *models.Payment) (*models.Payment, error) {
+	if payment.PaymentType != models.PaymentTypeTip || payment.Status != models.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("payment is not an authorized tip")
+	}
+
+	switch payment.Provider {
+	case models.PaymentProviderNetopia:
+		return s.netopiaCapture(payment)
+	case models.PaymentProviderManual:
+		return s.manualCapture(payment)
+	default:
+		return nil, fmt.Errorf("unsupported payment provider: %s", payment.Provider)
+	}
+}
+
+// findSavedCard returns the captured booking payment whose card can be charged again
+func (s *PaymentService) findSavedCard(bookingID string) (*models.Payment, error) {
+	payments, err := s.paymentRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.PaymentType == models.PaymentTypePreauthorization &&
+			payment.Status == models.PaymentStatusCaptured && payment.CardLastFour.Valid {
+			return payment, nil
+		}
+	}
+	return nil, fmt.Errorf("no saved card found for this booking")
+}
+
 // CapturePayment captures a preauthorized payment
 // This actually charges the customer's card
 func (s *PaymentService) CapturePayment(paymentID string) (*models.Payment, error) {
@@ -86,14 +194,30 @@ func (s *PaymentService) CapturePayment(paymentID string) (*models.Payment, erro
 	}
 
 	// Call payment provider
+	var captured *models.Payment
 	switch payment.Provider {
 	case models.PaymentProviderNetopia:
-		return s.netopiaCapture(payment)
+		captured, err = s.netopiaCapture(payment)
 	case models.PaymentProviderManual:
-		return s.manualCapture(payment)
+		captured, err = s.manualCapture(payment)
+	case models.PaymentProviderCash:
+		return nil, fmt.Errorf("cash payments are confirmed by the cleaner at check-out")
+	case models.PaymentProviderBankTransfer:
+		return nil, fmt.Errorf("bank transfers are captured when the transfer for the proforma is received")
 	default:
 		return nil, fmt.Errorf("unsupported payment provider: %s", payment.Provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostCapture(captured); err != nil {
+			fmt.Printf("Warning: failed to record capture %s in ledger: %v\n", captured.ID, err)
+		}
+	}
+
+	return captured, nil
 }
 
 // RefundPayment refunds a captured payment
@@ -112,6 +236,11 @@ func (s *PaymentService) RefundPayment(paymentID string, amount float64, reason
 		return nil, fmt.Errorf("payment is not captured (status: %s)", originalPayment.Status)
 	}
 
+	// Tips are paid out to the cleaner in full and are not refunded automatically
+	if originalPayment.PaymentType == models.PaymentTypeTip {
+		return nil, fmt.Errorf("tip payments cannot be refunded")
+	}
+
 	// Validate amount
 	if amount > originalPayment.Amount {
 		return nil, fmt.Errorf("refund amount cannot exceed original amount")
@@ -129,14 +258,34 @@ func (s *PaymentService) RefundPayment(paymentID string, amount float64, reason
 	}
 
 	// Call payment provider
+	var refunded *models.Payment
 	switch originalPayment.Provider {
 	case models.PaymentProviderNetopia:
-		return s.netopiaRefund(refundPayment, originalPayment)
-	case models.PaymentProviderManual:
-		return s.manualRefund(refundPayment)
+		refunded, err = s.netopiaRefund(refundPayment, originalPayment)
+	case models.PaymentProviderManual, models.PaymentProviderCash, models.PaymentProviderBankTransfer:
+		// Cash and bank transfers are refunded by bank transfer, recorded like a manual refund
+		refunded, err = s.manualRefund(refundPayment)
 	default:
 		return nil, fmt.Errorf("unsupported payment provider: %s", originalPayment.Provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostRefund(refunded); err != nil {
+			fmt.Printf("Warning: failed to record refund %s in ledger: %v\n", refunded.ID, err)
+		}
+	}
+
+	// The refund stands even if the credit note fails; it can be issued by an admin afterwards
+	if s.creditNotes != nil {
+		if _, err := s.creditNotes.IssueForRefund(refunded, reason); err != nil {
+			fmt.Printf("Warning: failed to issue credit note for refund %s: %v\n", refunded.ID, err)
+		}
+	}
+
+	return refunded, nil
 }
 
 // CancelPreauthorization cancels a preauthorized payment
@@ -159,13 +308,123 @@ func (s *PaymentService) CancelPreauthorization(paymentID string) (*models.Payme
 	switch payment.Provider {
 	case models.PaymentProviderNetopia:
 		return s.netopiaCancel(payment)
-	case models.PaymentProviderManual:
+	case models.PaymentProviderManual, models.PaymentProviderCash:
 		return s.manualCancel(payment)
+	case models.PaymentProviderBankTransfer:
+		cancelled, err := s.manualCancel(payment)
+		if err != nil {
+			return nil, err
+		}
+		if s.proformas != nil {
+			if err := s.proformas.CancelForBooking(payment.BookingID); err != nil {
+				fmt.Printf("Warning: failed to cancel proforma for booking %s: %v\n", payment.BookingID, err)
+			}
+		}
+		return cancelled, nil
 	default:
 		return nil, fmt.Errorf("unsupported payment provider: %s", payment.Provider)
 	}
 }
 
+// GetPendingCashPayment returns the cash payment a booking's cleaner still has to collect, or nil
+func (s *PaymentService) GetPendingCashPayment(bookingID string) (*models.Payment, error) {
+	payments, err := s.paymentRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.Provider == models.PaymentProviderCash &&
+			payment.PaymentType == models.PaymentTypePreauthorization &&
+			payment.Status == models.PaymentStatusAuthorized {
+			return payment, nil
+		}
+	}
+	return nil, nil
+}
+
+// ConfirmCashPayment records whether the cleaner received the cash at check-out.
+// Received cash is captured, leaving the platform fee as a receivable from the cleaner;
+// otherwise the payment is marked failed for follow-up.
+func (s *PaymentService) ConfirmCashPayment(payment *models.Payment, received bool) (*models.Payment, error) {
+	if payment.Provider != models.PaymentProviderCash || payment.Status != models.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("payment is not an open cash payment")
+	}
+
+	now := time.Now()
+	if !received {
+		payment.Status = models.PaymentStatusFailed
+		payment.FailedAt = sql.NullTime{Time: now, Valid: true}
+		payment.ErrorCode = sql.NullString{String: "CASH_NOT_RECEIVED", Valid: true}
+		payment.ErrorMessage = sql.NullString{String: "Cleaner reported that the cash was not received", Valid: true}
+		if err := s.paymentRepo.Update(payment); err != nil {
+			return nil, fmt.Errorf("failed to update payment: %w", err)
+		}
+		return payment, nil
+	}
+
+	payment.Status = models.PaymentStatusCaptured
+	payment.CapturedAt = sql.NullTime{Time: now, Valid: true}
+	response := map[string]interface{}{
+		"status":  "captured",
+		"message": "Cash received by cleaner",
+	}
+	payment.ProviderResponse, _ = json.Marshal(response)
+
+	if err := s.paymentRepo.Update(payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostCapture(payment); err != nil {
+			fmt.Printf("Warning: failed to record cash payment %s in ledger: %v\n", payment.ID, err)
+		}
+	}
+
+	return payment, nil
+}
+
+// CheckCashJobLimit returns an error if the cleaner already has payment.cash_max_jobs_per_month
+// cash bookings in the month of the given booking
+func (s *PaymentService) CheckCashJobLimit(booking *models.Booking, cleanerID string) error {
+	limit := s.cfg.Payment.CashMaxJobsPerMonth
+	if limit <= 0 {
+		return nil
+	}
+
+	date := booking.ScheduledDate
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	count, err := s.paymentRepo.CountCashJobsByCleaner(cleanerID, monthStart, monthStart.AddDate(0, 1, 0), booking.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count cash bookings: %w", err)
+	}
+	if count >= limit {
+		return fmt.Errorf("cleaner has reached the limit of %d cash bookings per month", limit)
+	}
+	return nil
+}
+
+// validateCashBooking checks that a booking may be paid in cash
+func (s *PaymentService) validateCashBooking(booking *models.Booking) error {
+	if !s.cfg.Payment.CashEnabled {
+		return fmt.Errorf("cash payments are not available")
+	}
+	if booking.CleanerID.Valid {
+		return s.CheckCashJobLimit(booking, booking.CleanerID.String)
+	}
+	return nil
+}
+
+// validateBankTransferBooking checks that a booking may be paid by bank transfer against a proforma
+func (s *PaymentService) validateBankTransferBooking(booking *models.Booking) error {
+	if !s.cfg.Payment.BankTransferEnabled {
+		return fmt.Errorf("bank transfer payments are not available")
+	}
+	if !booking.BillingProfileID.Valid {
+		return fmt.Errorf("bank transfer is only available for bookings invoiced to a company")
+	}
+	return nil
+}
+
 // GetPaymentsByBooking retrieves all payments for a booking
 func (s *PaymentService) GetPaymentsByBooking(bookingID string, userID string) ([]*models.Payment, error) {
 	// Validate booking belongs to user
@@ -305,6 +564,58 @@ func (s *PaymentService) netopiaCancel(payment *models.Payment) (*models.Payment
 	return payment, nil
 }
 
+// --- Cash Payment Provider (collected on site) ---
+
+// cashPreauthorize records the client's choice to pay cash; nothing is held and the
+// payment stays authorized until the cleaner confirms receipt at check-out
+func (s *PaymentService) cashPreauthorize(payment *models.Payment) (*models.Payment, error) {
+	payment.ProviderTransactionID = sql.NullString{String: fmt.Sprintf("CASH-%d", time.Now().Unix()), Valid: true}
+	payment.Status = models.PaymentStatusAuthorized
+	payment.AuthorizedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	response := map[string]interface{}{
+		"status":  "authorized",
+		"message": "Cash on site",
+	}
+	payment.ProviderResponse, _ = json.Marshal(response)
+
+	err := s.paymentRepo.Create(payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// --- Bank Transfer Provider (companies paying against a proforma) ---
+
+// bankTransferPreauthorize records that the company will pay by bank transfer; the payment stays
+// authorized until the transfer arrives. A confirmed booking gets its proforma right away,
+// others get it when they are confirmed.
+func (s *PaymentService) bankTransferPreauthorize(payment *models.Payment, booking *models.Booking) (*models.Payment, error) {
+	payment.Status = models.PaymentStatusAuthorized
+	payment.AuthorizedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	response := map[string]interface{}{
+		"status":  "authorized",
+		"message": "Awaiting bank transfer",
+	}
+	payment.ProviderResponse, _ = json.Marshal(response)
+
+	err := s.paymentRepo.Create(payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	if s.proformas != nil && booking.Status == models.BookingStatusConfirmed {
+		if _, err := s.proformas.IssueForBooking(booking.ID); err != nil {
+			fmt.Printf("Warning: failed to issue proforma for booking %s: %v\n", booking.ID, err)
+		}
+	}
+
+	return payment, nil
+}
+
 // --- Manual Payment Provider (for testing/admin) ---
 
 func (s *PaymentService) manualPreauthorize(payment *models.Payment) (*models.Payment, error) {
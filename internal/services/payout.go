@@ -4,112 +4,392 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/cleanbuddy/backend/internal/config"
 	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
 )
 
 type PayoutService struct {
-	payoutRepo   *models.PayoutRepository
-	lineItemRepo *models.PayoutLineItemRepository
-	bookingRepo  *models.BookingRepository
-	cleanerRepo  *models.CleanerRepository
-	userRepo     *models.UserRepository
-	emailService *EmailService
+	payoutRepo     *models.PayoutRepository
+	lineItemRepo   *models.PayoutLineItemRepository
+	bookingRepo    *models.BookingRepository
+	tipRepo        *models.TipRepository
+	adjustmentRepo *models.PayoutAdjustmentRepository
+	paymentRepo    *models.PaymentRepository
+	disputeRepo    *models.DisputeRepository
+	cleanerRepo    *models.CleanerRepository
+	userRepo       *models.UserRepository
+	companyRepo    *models.CompanyRepository
+	companyAdmins  *models.CompanyAdminRepository
+	companyPayouts *models.CompanyPayoutRepository
+	emailService   *EmailService
+	ledgerService  *LedgerService
+	selfBilling    *SelfBillingService
+	commissions    *CommissionInvoiceService
+	pdfGenerator   *PDFGenerator
+	cfg            *config.Config
 }
 
 func NewPayoutService(db *sql.DB, emailService *EmailService) *PayoutService {
 	return &PayoutService{
-		payoutRepo:   models.NewPayoutRepository(db),
-		lineItemRepo: models.NewPayoutLineItemRepository(db),
-		bookingRepo:  models.NewBookingRepository(db),
-		cleanerRepo:  models.NewCleanerRepository(db),
-		userRepo:     models.NewUserRepository(db),
-		emailService: emailService,
+		payoutRepo:     models.NewPayoutRepository(db),
+		lineItemRepo:   models.NewPayoutLineItemRepository(db),
+		bookingRepo:    models.NewBookingRepository(db),
+		tipRepo:        models.NewTipRepository(db),
+		adjustmentRepo: models.NewPayoutAdjustmentRepository(db),
+		paymentRepo:    models.NewPaymentRepository(db),
+		disputeRepo:    models.NewDisputeRepository(db),
+		cleanerRepo:    models.NewCleanerRepository(db),
+		userRepo:       models.NewUserRepository(db),
+		companyRepo:    models.NewCompanyRepository(db),
+		companyAdmins:  models.NewCompanyAdminRepository(db),
+		companyPayouts: models.NewCompanyPayoutRepository(db),
+		emailService:   emailService,
+		pdfGenerator:   NewPDFGenerator("./invoices/pdf"),
+		cfg:            config.Get(),
 	}
 }
 
+// SetLedgerService sets the ledger service that payout amounts are derived from
+func (s *PayoutService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// SetSelfBillingService sets the service that issues invoices for cleaners who opted into self-billing
+func (s *PayoutService) SetSelfBillingService(selfBilling *SelfBillingService) {
+	s.selfBilling = selfBilling
+}
+
+// SetCommissionInvoiceService sets the service that invoices the platform fees retained from payouts
+func (s *PayoutService) SetCommissionInvoiceService(commissions *CommissionInvoiceService) {
+	s.commissions = commissions
+}
+
+// PayoutRun is the outcome of generating, or previewing, the payouts for one month
+type PayoutRun struct {
+	PeriodStart     time.Time
+	PeriodEnd       time.Time
+	DryRun          bool
+	Drafts          []*models.PayoutDraft
+	CompanyPayouts  []*models.CompanyPayout
+	SkippedCleaners int // Already had a payout for the period
+	HeldBookings    int // Left out while a dispute is open
+	TotalEarnings   float64
+	PlatformFees    float64
+	NetAmount       float64
+}
+
+// Payouts returns the payouts of the run
+func (r *PayoutRun) Payouts() []*models.Payout {
+	payouts := make([]*models.Payout, len(r.Drafts))
+	for i, draft := range r.Drafts {
+		payouts[i] = draft.Payout
+	}
+	return payouts
+}
+
 // GenerateMonthlyPayouts creates payout records for all cleaners for a given month
 func (s *PayoutService) GenerateMonthlyPayouts(year int, month time.Month) ([]*models.Payout, error) {
+	run, err := s.RunMonthlyPayouts(year, month, false)
+	if err != nil {
+		return nil, err
+	}
+	return run.Payouts(), nil
+}
+
+// RunMonthlyPayouts builds the payouts for a month and, unless dryRun is set, stores them in one transaction.
+// It is safe to run repeatedly: cleaners that already have a payout for the month are skipped and
+// bookings or tips already on a payout are never included again. Bookings with an open dispute are
+// held back and picked up by the run for the month in which the dispute is resolved.
+// Cleaners working for a company are paid through one company payout per company.
+func (s *PayoutService) RunMonthlyPayouts(year int, month time.Month, dryRun bool) (*PayoutRun, error) {
+	if month < time.January || month > time.December {
+		return nil, fmt.Errorf("invalid month: %d", month)
+	}
+
 	// Calculate period
 	periodStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
 	periodEnd := periodStart.AddDate(0, 1, 0).Add(-time.Second) // Last second of the month
+	run := &PayoutRun{PeriodStart: periodStart, PeriodEnd: periodEnd, DryRun: dryRun}
 
-	// Get all completed bookings for the period
-	bookings, err := s.bookingRepo.GetCompletedBookingsByPeriod(periodStart, periodEnd)
+	existing, err := s.payoutRepo.GetCleanerIDsByPeriod(periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing payouts: %w", err)
+	}
+
+	// Get all completed bookings not paid out yet
+	bookings, err := s.bookingRepo.GetPayableBookingsByPeriod(periodStart, periodEnd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get completed bookings: %w", err)
 	}
 
+	bookingIDs := make([]string, len(bookings))
+	for i, booking := range bookings {
+		bookingIDs[i] = booking.ID
+	}
+
+	disputed, err := s.disputeRepo.GetOpenBookingIDs(bookingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disputed bookings: %w", err)
+	}
+
+	// Cash the cleaners already collected on site
+	cashCollected, err := s.paymentRepo.GetCashCollectedByBookingIDs(bookingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash payments: %w", err)
+	}
+
 	// Group bookings by cleaner
 	cleanerBookings := make(map[string][]*models.Booking)
+	heldBookings := make(map[string][]string)
 	for _, booking := range bookings {
 		if booking.CleanerID.Valid {
 			cleanerID := booking.CleanerID.String
+			if disputed[booking.ID] {
+				heldBookings[cleanerID] = append(heldBookings[cleanerID], booking.ID)
+				continue
+			}
 			cleanerBookings[cleanerID] = append(cleanerBookings[cleanerID], booking)
 		}
 	}
 
-	// Generate payout for each cleaner
-	var payouts []*models.Payout
-	for cleanerID, bookings := range cleanerBookings {
+	// Tips paid in the period are passed on in full, even to cleaners with no bookings that month
+	tips, err := s.tipRepo.GetUnpaidByPeriod(periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tips: %w", err)
+	}
+	cleanerTips := make(map[string][]*models.Tip)
+	for _, tip := range tips {
+		cleanerTips[tip.CleanerID] = append(cleanerTips[tip.CleanerID], tip)
+		if _, ok := cleanerBookings[tip.CleanerID]; !ok {
+			cleanerBookings[tip.CleanerID] = nil
+		}
+	}
+
+	// Adjustments made up to the end of the period, unless tied to a held booking
+	held := make(map[string]bool)
+	for _, bookingIDs := range heldBookings {
+		for _, bookingID := range bookingIDs {
+			held[bookingID] = true
+		}
+	}
+	adjustments, err := s.adjustmentRepo.GetUnpaid(periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout adjustments: %w", err)
+	}
+	cleanerAdjustments := make(map[string][]*models.PayoutAdjustment)
+	for _, adjustment := range adjustments {
+		if adjustment.BookingID.Valid && held[adjustment.BookingID.String] {
+			continue
+		}
+		cleanerAdjustments[adjustment.CleanerID] = append(cleanerAdjustments[adjustment.CleanerID], adjustment)
+		if _, ok := cleanerBookings[adjustment.CleanerID]; !ok {
+			cleanerBookings[adjustment.CleanerID] = nil
+		}
+	}
+
+	cleanerIDs := make([]string, 0, len(cleanerBookings))
+	for cleanerID := range cleanerBookings {
+		cleanerIDs = append(cleanerIDs, cleanerID)
+	}
+	sort.Strings(cleanerIDs)
+
+	// Build payout for each cleaner
+	companyPayouts := make(map[string]*models.CompanyPayout)
+	for _, cleanerID := range cleanerIDs {
+		bookings := cleanerBookings[cleanerID]
+
 		// Get cleaner to retrieve user_id
 		cleaner, err := s.cleanerRepo.GetByID(cleanerID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get cleaner %s: %w", cleanerID, err)
 		}
+		if cleaner == nil {
+			return nil, fmt.Errorf("cleaner %s not found", cleanerID)
+		}
 
-		payout, err := s.calculatePayoutForCleaner(cleaner.UserID, bookings, periodStart, periodEnd)
+		if existing[cleaner.UserID] {
+			run.SkippedCleaners++
+			continue
+		}
+		run.HeldBookings += len(heldBookings[cleanerID])
+
+		payout, err := s.calculatePayoutForCleaner(cleaner.UserID, bookings, cleanerTips[cleanerID], cleanerAdjustments[cleanerID], cashCollected, periodStart, periodEnd)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate payout for cleaner %s: %w", cleanerID, err)
 		}
 
-		// Create payout record
 		// Note: IBAN validation happens when marking payout as SENT
-		if err := s.payoutRepo.Create(payout); err != nil {
-			return nil, fmt.Errorf("failed to create payout: %w", err)
+		draft := &models.PayoutDraft{Payout: payout}
+		for _, booking := range bookings {
+			draft.LineItems = append(draft.LineItems, s.createLineItem("", booking, cashCollected[booking.ID]))
+		}
+		for _, tip := range cleanerTips[cleanerID] {
+			lineItem, err := s.createTipLineItem("", tip)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tip line item: %w", err)
+			}
+			draft.LineItems = append(draft.LineItems, lineItem)
+		}
+		for _, adjustment := range cleanerAdjustments[cleanerID] {
+			draft.LineItems = append(draft.LineItems, s.createAdjustmentLineItem("", adjustment))
 		}
 
-		// Create line items
-		for _, booking := range bookings {
-			lineItem := s.createLineItem(payout.ID, booking)
-			if err := s.lineItemRepo.Create(lineItem); err != nil {
-				return nil, fmt.Errorf("failed to create line item: %w", err)
+		if err := s.applyLedgerBalance(draft, cleaner, heldBookings[cleanerID]); err != nil {
+			return nil, fmt.Errorf("failed to get ledger balance for cleaner %s: %w", cleanerID, err)
+		}
+
+		// Clawbacks that exceed the earnings are carried forward to the next payout
+		s.carryForwardAdjustments(draft, cleaner, cleanerAdjustments[cleanerID])
+
+		company, err := s.companyRepo.GetActiveByCleanerID(cleaner.ID)
+		if err != nil {
+			return nil, err
+		}
+		if company != nil {
+			companyPayout, ok := companyPayouts[company.ID]
+			if !ok {
+				companyPayout = &models.CompanyPayout{
+					CompanyID:   company.ID,
+					PeriodStart: periodStart,
+					PeriodEnd:   periodEnd,
+					Status:      models.PayoutStatusPending,
+				}
+				companyPayouts[company.ID] = companyPayout
+				run.CompanyPayouts = append(run.CompanyPayouts, companyPayout)
 			}
+			companyPayout.Add(payout)
+			draft.CompanyPayout = companyPayout
+		} else if payout.NetAmount < 0 {
+			// Cash fees exceeded what we owe: the cleaner is invoiced for the difference
+			payout.Status = models.PayoutStatusInvoiced
+		}
+
+		run.Drafts = append(run.Drafts, draft)
+		run.TotalEarnings += payout.TotalEarnings
+		run.PlatformFees += payout.PlatformFees
+		run.NetAmount += payout.NetAmount
+	}
+
+	run.TotalEarnings = roundToCents(run.TotalEarnings)
+	run.PlatformFees = roundToCents(run.PlatformFees)
+	run.NetAmount = roundToCents(run.NetAmount)
+
+	// A company's cleaners settle together: the company owes the platform only if their total is negative
+	for _, companyPayout := range run.CompanyPayouts {
+		companyPayout.TotalEarnings = roundToCents(companyPayout.TotalEarnings)
+		companyPayout.PlatformFees = roundToCents(companyPayout.PlatformFees)
+		companyPayout.NetAmount = roundToCents(companyPayout.NetAmount)
+		if companyPayout.NetAmount < 0 {
+			companyPayout.Status = models.PayoutStatusInvoiced
+		}
+	}
+
+	if dryRun || len(run.Drafts) == 0 {
+		return run, nil
+	}
+
+	if err := s.payoutRepo.CreateWithLineItems(run.Drafts); err != nil {
+		return nil, err
+	}
+
+	for _, draft := range run.Drafts {
+		if draft.CompanyPayout != nil {
+			continue
 		}
+		if draft.Payout.Status == models.PayoutStatusInvoiced {
+			s.notifyCashFeesDue(draft.Payout)
+			continue
+		}
+		s.issueSelfBilledInvoice(draft)
+	}
+	for _, companyPayout := range run.CompanyPayouts {
+		if companyPayout.Status == models.PayoutStatusInvoiced {
+			s.notifyCompanyCashFeesDue(companyPayout)
+		}
+	}
+
+	if s.commissions != nil {
+		if _, err := s.commissions.GenerateForPeriod(year, month); err != nil {
+			fmt.Printf("Warning: failed to issue commission invoices for %d-%02d: %v\n", year, month, err)
+		}
+	}
 
-		payouts = append(payouts, payout)
+	return run, nil
+}
+
+// issueSelfBilledInvoice issues the invoice of a self-billing cleaner for a new payout. A failure
+// does not undo the payout: the invoice can be issued later.
+func (s *PayoutService) issueSelfBilledInvoice(draft *models.PayoutDraft) {
+	if s.selfBilling == nil {
+		return
 	}
 
-	return payouts, nil
+	iban, err := s.GetCleanerIBAN(draft.Payout.CleanerID)
+	if err != nil {
+		fmt.Printf("Warning: no IBAN for self-billed invoice of payout %s: %v\n", draft.Payout.ID, err)
+	}
+	if _, err := s.selfBilling.IssueForPayout(draft.Payout, draft.LineItems, iban); err != nil {
+		fmt.Printf("Warning: failed to issue self-billed invoice for payout %s: %v\n", draft.Payout.ID, err)
+	}
 }
 
-// calculatePayoutForCleaner calculates earnings for a cleaner from their bookings
+// RunScheduledPayouts generates last month's payouts once payout.generation_day is reached
+// (run as a goroutine). Re-running is harmless, so it simply tries on every tick.
+func (s *PayoutService) RunScheduledPayouts(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now().UTC()
+		if now.Day() < s.cfg.Payout.GenerationDay {
+			continue
+		}
+
+		lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+		run, err := s.RunMonthlyPayouts(lastMonth.Year(), lastMonth.Month(), false)
+		if err != nil {
+			fmt.Printf("Warning: scheduled payout generation for %s failed: %v\n", lastMonth.Format("2006-01"), err)
+			continue
+		}
+		if len(run.Drafts) > 0 {
+			fmt.Printf("Generated %d payout(s) for %s\n", len(run.Drafts), lastMonth.Format("2006-01"))
+		}
+	}
+}
+
+// calculatePayoutForCleaner calculates earnings for a cleaner from their bookings, tips and adjustments
 // userID is the user_id from the cleaners table (which references users.id)
-func (s *PayoutService) calculatePayoutForCleaner(userID string, bookings []*models.Booking, periodStart, periodEnd time.Time) (*models.Payout, error) {
+// Cash the cleaner collected on site is deducted, so only the platform fee is settled for cash bookings.
+func (s *PayoutService) calculatePayoutForCleaner(userID string, bookings []*models.Booking, tips []*models.Tip, adjustments []*models.PayoutAdjustment, cashCollected map[string]float64, periodStart, periodEnd time.Time) (*models.Payout, error) {
 	var totalEarnings float64
 	var platformFees float64
+	var cashTotal float64
 	totalBookings := len(bookings)
 
 	for _, booking := range bookings {
-		// Calculate platform fee rate
-		// Repeat customers get reduced fee: 10% → 2%
-		platformFeeRate := 10.0 // Default 10% for first-time customers
-
-		// Check if this is a repeat customer
-		isRepeatCustomer, err := s.isRepeatCustomer(booking.ClientID, booking.CompletedAt.Time)
-		if err == nil && isRepeatCustomer {
-			platformFeeRate = 2.0 // Reduced fee for repeat customers
-		}
+		// The platform fee was fixed on the booking by the fee policy
+		totalEarnings += booking.TotalPrice
+		platformFees += booking.PlatformFee
+		cashTotal += cashCollected[booking.ID]
+	}
 
-		// Platform fee calculation
-		platformFee := booking.TotalPrice * (platformFeeRate / 100.0)
+	// Tips carry no platform fee
+	for _, tip := range tips {
+		totalEarnings += tip.Amount
+	}
 
-		totalEarnings += booking.TotalPrice
-		platformFees += platformFee
+	var adjustmentTotal float64
+	for _, adjustment := range adjustments {
+		adjustmentTotal += adjustment.Amount
 	}
 
-	netAmount := totalEarnings - platformFees
+	netAmount := roundToCents(totalEarnings - platformFees - cashTotal + adjustmentTotal)
 
 	payout := &models.Payout{
 		CleanerID:     userID, // This is actually user_id per the schema
@@ -125,32 +405,153 @@ func (s *PayoutService) calculatePayoutForCleaner(userID string, bookings []*mod
 	return payout, nil
 }
 
-// createLineItem creates a payout line item from a booking
-func (s *PayoutService) createLineItem(payoutID string, booking *models.Booking) *models.PayoutLineItem {
-	// Calculate platform fee (same logic as above)
-	platformFeeRate := 10.0 // Default 10% for first-time customers
+// applyLedgerBalance sets the payout net amount to what the ledger says the platform owes the cleaner
+// at the end of the period, minus payouts already generated but not yet sent and bookings held for disputes.
+// Cleaners whose history predates the ledger keep the booking-based amount.
+func (s *PayoutService) applyLedgerBalance(draft *models.PayoutDraft, cleaner *models.Cleaner, heldBookingIDs []string) error {
+	payout := draft.Payout
+	if s.ledgerService == nil {
+		return nil
+	}
 
-	// Check if this is a repeat customer
-	isRepeatCustomer, err := s.isRepeatCustomer(booking.ClientID, booking.CompletedAt.Time)
-	if err == nil && isRepeatCustomer {
-		platformFeeRate = 2.0 // Reduced fee for repeat customers
+	tracked, err := s.ledgerService.HasCleanerEntries(cleaner.ID)
+	if err != nil || !tracked {
+		return err
 	}
 
-	platformFee := booking.TotalPrice * (platformFeeRate / 100.0)
-	cleanerEarnings := booking.TotalPrice - platformFee
+	balance, err := s.ledgerService.GetCleanerBalance(cleaner.ID, payout.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	unsettled, err := s.payoutRepo.GetUnsettledAmountByCleanerID(cleaner.UserID)
+	if err != nil {
+		return err
+	}
+
+	held, err := s.ledgerService.GetCleanerBalanceForBookings(cleaner.ID, heldBookingIDs, payout.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	reconcileWithLedger(draft, roundToCents(balance-unsettled-held))
+	return nil
+}
+
+// reconcileWithLedger sets a payout's net amount to the ledger's, adding an adjustment line item
+// for any difference from its line items so that they still add up to the amount paid
+func reconcileWithLedger(draft *models.PayoutDraft, ledgerNet float64) {
+	var lineTotal float64
+	for _, item := range draft.LineItems {
+		lineTotal += item.CleanerEarnings
+	}
+
+	payout := draft.Payout
+	payout.NetAmount = ledgerNet
+	difference := roundToCents(ledgerNet - lineTotal)
+	if difference == 0 {
+		return
+	}
+	draft.LineItems = append(draft.LineItems, &models.PayoutLineItem{
+		Description:     sql.NullString{String: "Correction to the ledger balance", Valid: true},
+		ItemType:        models.PayoutLineItemTypeAdjustment,
+		BookingDate:     payout.PeriodEnd,
+		ServiceType:     string(models.PayoutAdjustmentTypeCorrection),
+		CleanerEarnings: difference,
+	})
+}
+
+// createLineItem creates a payout line item from a booking; cash collected on site is deducted
+func (s *PayoutService) createLineItem(payoutID string, booking *models.Booking, cashCollected float64) *models.PayoutLineItem {
+	cleanerEarnings := booking.CleanerPayout - cashCollected
+
+	itemType := models.PayoutLineItemTypeBooking
+	if cashCollected > 0 {
+		itemType = models.PayoutLineItemTypeCash
+	}
 
 	return &models.PayoutLineItem{
 		PayoutID:        payoutID,
 		BookingID:       booking.ID,
+		ItemType:        itemType,
 		BookingDate:     booking.ScheduledDate,
 		ServiceType:     string(booking.ServiceType),
 		BookingAmount:   booking.TotalPrice,
-		PlatformFeeRate: platformFeeRate,
-		PlatformFee:     platformFee,
+		PlatformFeeRate: booking.PlatformFeeRate,
+		PlatformFee:     booking.PlatformFee,
 		CleanerEarnings: cleanerEarnings,
 	}
 }
 
+// createTipLineItem creates a payout line item for a tip, paid to the cleaner in full
+func (s *PayoutService) createTipLineItem(payoutID string, tip *models.Tip) (*models.PayoutLineItem, error) {
+	booking, err := s.bookingRepo.GetByID(tip.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking == nil {
+		return nil, fmt.Errorf("booking %s not found", tip.BookingID)
+	}
+
+	return &models.PayoutLineItem{
+		PayoutID:        payoutID,
+		BookingID:       tip.BookingID,
+		ItemType:        models.PayoutLineItemTypeTip,
+		BookingDate:     booking.ScheduledDate,
+		ServiceType:     string(booking.ServiceType),
+		BookingAmount:   tip.Amount,
+		PlatformFeeRate: 0,
+		PlatformFee:     0,
+		CleanerEarnings: tip.Amount,
+	}, nil
+}
+
+// createAdjustmentLineItem creates a payout line item for a clawback, bonus or correction
+func (s *PayoutService) createAdjustmentLineItem(payoutID string, adjustment *models.PayoutAdjustment) *models.PayoutLineItem {
+	return &models.PayoutLineItem{
+		PayoutID:        payoutID,
+		BookingID:       adjustment.BookingID.String,
+		AdjustmentID:    sql.NullString{String: adjustment.ID, Valid: true},
+		Description:     sql.NullString{String: adjustment.Reason, Valid: true},
+		ItemType:        models.PayoutLineItemTypeAdjustment,
+		BookingDate:     adjustment.CreatedAt,
+		ServiceType:     string(adjustment.Type),
+		CleanerEarnings: adjustment.Amount,
+	}
+}
+
+// carryForwardAdjustments moves the part of a negative payout caused by adjustments to the next payout.
+// What remains negative is owed for cash fees and is invoiced as before.
+func (s *PayoutService) carryForwardAdjustments(draft *models.PayoutDraft, cleaner *models.Cleaner, adjustments []*models.PayoutAdjustment) {
+	var adjustmentTotal float64
+	for _, adjustment := range adjustments {
+		adjustmentTotal += adjustment.Amount
+	}
+
+	payout := draft.Payout
+	if payout.NetAmount >= 0 || adjustmentTotal >= 0 {
+		return
+	}
+
+	carried := roundToCents(math.Max(payout.NetAmount, adjustmentTotal))
+	reason := fmt.Sprintf("Negative balance carried forward from the %s payout", payout.PeriodStart.Format("January 2006"))
+
+	draft.CarryForward = &models.PayoutAdjustment{
+		CleanerID: cleaner.ID,
+		Type:      models.PayoutAdjustmentTypeCarryForward,
+		Amount:    carried,
+		Reason:    reason,
+	}
+	draft.LineItems = append(draft.LineItems, &models.PayoutLineItem{
+		Description:     sql.NullString{String: reason, Valid: true},
+		ItemType:        models.PayoutLineItemTypeCarryForward,
+		BookingDate:     payout.PeriodEnd,
+		ServiceType:     string(models.PayoutAdjustmentTypeCarryForward),
+		CleanerEarnings: -carried,
+	})
+	payout.NetAmount = roundToCents(payout.NetAmount - carried)
+}
+
 // GetPayoutsByCleanerID returns paginated payouts for a cleaner
 func (s *PayoutService) GetPayoutsByCleanerID(cleanerID string, limit, offset int) ([]*models.Payout, error) {
 	return s.payoutRepo.GetByCleanerID(cleanerID, limit, offset)
@@ -184,17 +585,19 @@ func (s *PayoutService) MarkPayoutAsSent(payoutID, transferReference string) err
 		return fmt.Errorf("payout not found")
 	}
 
+	if payout.Status == models.PayoutStatusInvoiced {
+		return fmt.Errorf("payout is an invoice for cash fees owed by the cleaner")
+	}
+	if payout.CompanyPayoutID.Valid {
+		return fmt.Errorf("payout is paid to the cleaner's company with company payout %s", payout.CompanyPayoutID.String)
+	}
+
 	// CRITICAL: Validate cleaner has IBAN before marking as sent
 	if err := s.validateCleanerIBAN(payout.CleanerID); err != nil {
 		return fmt.Errorf("cannot send payout: %w", err)
 	}
 
-	now := time.Now()
-	payout.Status = models.PayoutStatusSent
-	payout.TransferReference = sql.NullString{String: transferReference, Valid: true}
-	payout.PaidAt = sql.NullTime{Time: now, Valid: true}
-
-	if err := s.payoutRepo.Update(payout); err != nil {
+	if err := s.settlePayout(payout, transferReference); err != nil {
 		return err
 	}
 
@@ -224,6 +627,7 @@ func (s *PayoutService) MarkPayoutAsSent(payoutID, transferReference string) err
 				payout.NetAmount,
 				periodStr,
 				transferRef,
+				s.payoutStatementAttachment(payout.ID),
 			)
 		}
 	}()
@@ -231,6 +635,82 @@ func (s *PayoutService) MarkPayoutAsSent(payoutID, transferReference string) err
 	return nil
 }
 
+// settlePayout marks a payout SENT and records it in the ledger
+func (s *PayoutService) settlePayout(payout *models.Payout, transferReference string) error {
+	payout.Status = models.PayoutStatusSent
+	payout.TransferReference = sql.NullString{String: transferReference, Valid: true}
+	payout.PaidAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	if err := s.payoutRepo.Update(payout); err != nil {
+		return err
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostPayout(payout); err != nil {
+			fmt.Printf("Warning: failed to record payout %s in ledger: %v\n", payout.ID, err)
+		}
+	}
+	return nil
+}
+
+// MarkPayoutInvoicePaid settles an INVOICED payout once the cleaner has paid the cash fees they owed
+func (s *PayoutService) MarkPayoutInvoicePaid(payoutID, transferReference string) error {
+	payout, err := s.payoutRepo.GetByID(payoutID)
+	if err != nil {
+		return err
+	}
+	if payout == nil {
+		return fmt.Errorf("payout not found")
+	}
+	if payout.Status != models.PayoutStatusInvoiced {
+		return fmt.Errorf("payout is not invoiced (status: %s)", payout.Status)
+	}
+
+	// SENT marks the payout as settled in either direction
+	payout.Status = models.PayoutStatusSent
+	payout.TransferReference = sql.NullString{String: transferReference, Valid: true}
+	payout.PaidAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	if err := s.payoutRepo.Update(payout); err != nil {
+		return err
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostPayout(payout); err != nil {
+			fmt.Printf("Warning: failed to record cash fee payment %s in ledger: %v\n", payout.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// notifyCashFeesDue emails a cleaner the cash fees they owe for the period (async)
+func (s *PayoutService) notifyCashFeesDue(payout *models.Payout) {
+	if s.emailService == nil {
+		return
+	}
+
+	go func() {
+		user, err := s.userRepo.GetByID(payout.CleanerID)
+		if err != nil || user == nil || !user.Email.Valid {
+			return
+		}
+
+		cleanerName := "Cleaner"
+		if user.FirstName.Valid {
+			cleanerName = user.FirstName.String
+		}
+
+		periodStr := fmt.Sprintf("%s - %s",
+			payout.PeriodStart.Format("02 January 2006"),
+			payout.PeriodEnd.Format("02 January 2006"))
+
+		if err := s.emailService.SendCashFeesInvoiceEmail(context.Background(), user.Email.String, cleanerName, -payout.NetAmount, periodStr, payout.ID); err != nil {
+			fmt.Printf("Warning: failed to send cash fee invoice for payout %s: %v\n", payout.ID, err)
+		}
+	}()
+}
+
 // MarkPayoutAsFailed updates payout status to FAILED
 func (s *PayoutService) MarkPayoutAsFailed(payoutID, reason string) error {
 	payout, err := s.payoutRepo.GetByID(payoutID)
@@ -241,6 +721,10 @@ func (s *PayoutService) MarkPayoutAsFailed(payoutID, reason string) error {
 		return fmt.Errorf("payout not found")
 	}
 
+	if payout.CompanyPayoutID.Valid {
+		return fmt.Errorf("payout is paid to the cleaner's company with company payout %s", payout.CompanyPayoutID.String)
+	}
+
 	payout.Status = models.PayoutStatusFailed
 	payout.FailedReason = sql.NullString{String: reason, Valid: true}
 
@@ -272,37 +756,24 @@ func (s *PayoutService) GetPayoutsByStatus(status string, limit, offset *int) ([
 	return s.payoutRepo.GetByStatus(status)
 }
 
-// isRepeatCustomer checks if a client has completed bookings before the given date
-// Returns true if the client has at least one prior completed booking
-func (s *PayoutService) isRepeatCustomer(clientID string, currentBookingDate time.Time) (bool, error) {
-	// Get all completed bookings for this client before the current booking date
-	previousBookings, err := s.bookingRepo.GetCompletedBookingsByClientBeforeDate(clientID, currentBookingDate)
-	if err != nil {
-		return false, fmt.Errorf("failed to check repeat customer status: %w", err)
-	}
-
-	// If there are any previous completed bookings, this is a repeat customer
-	return len(previousBookings) > 0, nil
-}
-
-// validateCleanerIBAN checks if a cleaner has a valid IBAN for payouts
+// validateCleanerIBAN checks if a cleaner (users.id) has a valid IBAN for payouts
 // Returns error if IBAN is missing or invalid
-func (s *PayoutService) validateCleanerIBAN(cleanerID string) error {
-	cleaner, err := s.cleanerRepo.GetByID(cleanerID)
+func (s *PayoutService) validateCleanerIBAN(userID string) error {
+	iban, err := s.GetCleanerIBAN(userID)
 	if err != nil {
-		return fmt.Errorf("failed to get cleaner: %w", err)
-	}
-	if cleaner == nil {
-		return fmt.Errorf("cleaner not found")
+		return err
 	}
 
 	// Check if IBAN is present
-	if !cleaner.IBAN.Valid || cleaner.IBAN.String == "" {
+	if iban == "" {
 		return fmt.Errorf("cleaner does not have an IBAN configured - payouts cannot be sent")
 	}
+	return checkPayoutIBAN(iban)
+}
 
+// checkPayoutIBAN checks that an IBAN looks like a Romanian account
+func checkPayoutIBAN(iban string) error {
 	// Basic Romanian IBAN validation: RO + 2 digits + 24 alphanumeric characters (total 28)
-	iban := cleaner.IBAN.String
 	if len(iban) < 24 || len(iban) > 34 {
 		return fmt.Errorf("invalid IBAN length: %s", iban)
 	}
@@ -314,6 +785,27 @@ func (s *PayoutService) validateCleanerIBAN(cleanerID string) error {
 	return nil
 }
 
+// GetCleanerIBAN returns the decrypted payout IBAN of a cleaner (users.id), or "" if none is set
+func (s *PayoutService) GetCleanerIBAN(userID string) (string, error) {
+	cleaner, err := s.cleanerRepo.GetByUserID(userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return "", fmt.Errorf("cleaner not found")
+	}
+	if !cleaner.IBAN.Valid || cleaner.IBAN.String == "" {
+		return "", nil
+	}
+
+	iban, err := utils.DecryptIBAN(cleaner.IBAN.String)
+	if err != nil {
+		// Profiles created before encryption still hold the plaintext IBAN
+		return strings.ToUpper(strings.ReplaceAll(cleaner.IBAN.String, " ", "")), nil
+	}
+	return iban, nil
+}
+
 // GetCleanerPayouts gets payouts for a specific cleaner (admin only)
 func (s *PayoutService) GetCleanerPayouts(cleanerID string, limit int) ([]*models.Payout, error) {
 	offset := 0
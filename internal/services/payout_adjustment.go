@@ -0,0 +1,155 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// PayoutAdjustmentService handles clawbacks, bonuses and corrections to cleaner earnings.
+// Adjustments are paid as line items of the next monthly payout run.
+type PayoutAdjustmentService struct {
+	adjustmentRepo *models.PayoutAdjustmentRepository
+	bookingRepo    *models.BookingRepository
+	cleanerRepo    *models.CleanerRepository
+	ledgerService  *LedgerService
+}
+
+// NewPayoutAdjustmentService creates a new payout adjustment service
+func NewPayoutAdjustmentService(db *sql.DB) *PayoutAdjustmentService {
+	return &PayoutAdjustmentService{
+		adjustmentRepo: models.NewPayoutAdjustmentRepository(db),
+		bookingRepo:    models.NewBookingRepository(db),
+		cleanerRepo:    models.NewCleanerRepository(db),
+	}
+}
+
+// SetLedgerService sets the ledger service used to record adjustments
+func (s *PayoutAdjustmentService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// CreateAdjustment records an admin adjustment for a cleaner (cleaners.id).
+// Bonuses must be positive, clawbacks negative; corrections may go either way.
+func (s *PayoutAdjustmentService) CreateAdjustment(cleanerID string, adjustmentType models.PayoutAdjustmentType, amount float64, reason string, bookingID *string, adminID string) (*models.PayoutAdjustment, error) {
+	amount = roundToCents(amount)
+	reason = strings.TrimSpace(reason)
+
+	switch adjustmentType {
+	case models.PayoutAdjustmentTypeBonus:
+		if amount <= 0 {
+			return nil, fmt.Errorf("bonus amount must be positive")
+		}
+	case models.PayoutAdjustmentTypeClawback:
+		if amount >= 0 {
+			return nil, fmt.Errorf("clawback amount must be negative")
+		}
+	case models.PayoutAdjustmentTypeCorrection:
+		if amount == 0 {
+			return nil, fmt.Errorf("correction amount cannot be zero")
+		}
+	default:
+		return nil, fmt.Errorf("invalid adjustment type: %s", adjustmentType)
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required")
+	}
+
+	cleaner, err := s.cleanerRepo.GetByID(cleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return nil, fmt.Errorf("cleaner not found")
+	}
+
+	adjustment := &models.PayoutAdjustment{
+		CleanerID: cleaner.ID,
+		Type:      adjustmentType,
+		Amount:    amount,
+		Reason:    reason,
+		CreatedBy: sql.NullString{String: adminID, Valid: true},
+	}
+
+	if bookingID != nil && *bookingID != "" {
+		booking, err := s.bookingRepo.GetByID(*bookingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get booking: %w", err)
+		}
+		if booking == nil {
+			return nil, fmt.Errorf("booking not found")
+		}
+		if booking.CleanerID.String != cleaner.ID {
+			return nil, fmt.Errorf("booking was not done by this cleaner")
+		}
+		adjustment.BookingID = sql.NullString{String: booking.ID, Valid: true}
+	}
+
+	if err := s.adjustmentRepo.Create(adjustment); err != nil {
+		return nil, fmt.Errorf("failed to create adjustment: %w", err)
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostAdjustment(adjustment.ID, cleaner.ID, adjustment.BookingID.String, amount, reason); err != nil {
+			fmt.Printf("Warning: failed to record adjustment %s in ledger: %v\n", adjustment.ID, err)
+		}
+	}
+
+	return adjustment, nil
+}
+
+// RecordDisputeClawback takes back the cleaner's share of a dispute refund (card or store credit).
+// The share matches the booking's price split. It is not posted to the ledger, because the refund
+// entry already took it out of the cleaner's payables.
+func (s *PayoutAdjustmentService) RecordDisputeClawback(dispute *models.Dispute, booking *models.Booking, refundAmount float64) (*models.PayoutAdjustment, error) {
+	if !booking.CleanerID.Valid || booking.TotalPrice <= 0 {
+		return nil, nil
+	}
+
+	existing, err := s.adjustmentRepo.GetByDisputeID(dispute.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing clawback: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	cleanerShare := roundToCents(refundAmount * booking.CleanerPayout / booking.TotalPrice)
+	if cleanerShare <= 0 {
+		return nil, nil
+	}
+
+	adjustment := &models.PayoutAdjustment{
+		CleanerID: booking.CleanerID.String,
+		Type:      models.PayoutAdjustmentTypeClawback,
+		Amount:    -cleanerShare,
+		Reason:    fmt.Sprintf("Client refunded %.2f RON after a %s dispute", refundAmount, strings.ToLower(strings.ReplaceAll(dispute.DisputeType, "_", " "))),
+		BookingID: sql.NullString{String: booking.ID, Valid: true},
+		DisputeID: sql.NullString{String: dispute.ID, Valid: true},
+		CreatedBy: dispute.ResolvedBy,
+	}
+	if err := s.adjustmentRepo.Create(adjustment); err != nil {
+		return nil, fmt.Errorf("failed to create clawback: %w", err)
+	}
+
+	return adjustment, nil
+}
+
+// GetCleanerAdjustments returns a cleaner's (cleaners.id) adjustments, newest first
+func (s *PayoutAdjustmentService) GetCleanerAdjustments(cleanerID string, limit, offset int) ([]*models.PayoutAdjustment, error) {
+	return s.adjustmentRepo.GetByCleanerID(cleanerID, limit, offset)
+}
+
+// GetAdjustmentsForUser returns the adjustments of the cleaner profile of a user
+func (s *PayoutAdjustmentService) GetAdjustmentsForUser(userID string, limit, offset int) ([]*models.PayoutAdjustment, error) {
+	cleaner, err := s.cleanerRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return nil, fmt.Errorf("cleaner profile not found")
+	}
+	return s.adjustmentRepo.GetByCleanerID(cleaner.ID, limit, offset)
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+	"github.com/google/uuid"
+)
+
+// PayoutBatchService exports PENDING payouts as a bulk payment file for the bank
+// and settles the whole batch once the bank has executed it
+type PayoutBatchService struct {
+	batchRepo     *models.PayoutBatchRepository
+	payoutRepo    *models.PayoutRepository
+	userRepo      *models.UserRepository
+	payoutService *PayoutService
+	cfg           *config.Config
+}
+
+// NewPayoutBatchService creates a new payout batch service
+func NewPayoutBatchService(db *sql.DB, payoutService *PayoutService) *PayoutBatchService {
+	return &PayoutBatchService{
+		batchRepo:     models.NewPayoutBatchRepository(db),
+		payoutRepo:    models.NewPayoutRepository(db),
+		userRepo:      models.NewUserRepository(db),
+		payoutService: payoutService,
+		cfg:           config.Get(),
+	}
+}
+
+// ExportPayoutBatch builds a payment file for PENDING payouts and moves them to PROCESSING.
+// With no payout IDs every pending payout is exported, skipping cleaners without a valid IBAN;
+// payouts picked by ID must all be payable.
+func (s *PayoutBatchService) ExportPayoutBatch(format string, payoutIDs []string, adminID string) (*models.PayoutBatch, error) {
+	switch format {
+	case utils.PaymentFileFormatPain001, utils.PaymentFileFormatBTCSV, utils.PaymentFileFormatBCRCSV:
+	default:
+		return nil, fmt.Errorf("unsupported payment file format: %s", format)
+	}
+
+	payouts, err := s.selectPayouts(payoutIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reference := fmt.Sprintf("CB-%s-%s", now.Format("20060102150405"), strings.ToUpper(uuid.New().String()[:6]))
+	paymentBatch := &utils.PaymentBatch{
+		MessageID:     reference,
+		CreatedAt:     now,
+		ExecutionDate: now,
+		Debtor: utils.PaymentDebtor{
+			Name: s.cfg.Company.LegalName,
+			IBAN: s.cfg.Company.Bank.IBAN,
+			BIC:  s.cfg.Company.Bank.SWIFT,
+		},
+	}
+
+	var exportedIDs []string
+	for _, payout := range payouts {
+		transfer, err := s.buildTransfer(payout)
+		if err != nil {
+			if len(payoutIDs) > 0 {
+				return nil, fmt.Errorf("payout %s: %w", payout.ID, err)
+			}
+			fmt.Printf("Warning: payout %s left out of batch %s: %v\n", payout.ID, reference, err)
+			continue
+		}
+		paymentBatch.Transfers = append(paymentBatch.Transfers, *transfer)
+		exportedIDs = append(exportedIDs, payout.ID)
+	}
+	if len(exportedIDs) == 0 {
+		return nil, fmt.Errorf("no pending payouts can be exported")
+	}
+
+	content, err := utils.BuildPaymentFile(paymentBatch, format)
+	if err != nil {
+		return nil, err
+	}
+	fileName, _ := utils.PaymentFileName(reference, format)
+
+	batch := &models.PayoutBatch{
+		Reference:   reference,
+		Format:      format,
+		Status:      models.PayoutBatchStatusProcessing,
+		PayoutCount: len(exportedIDs),
+		TotalAmount: paymentBatch.ControlSum(),
+		FileName:    fileName,
+		FileContent: string(content),
+		CreatedBy:   sql.NullString{String: adminID, Valid: adminID != ""},
+	}
+	if err := s.batchRepo.CreateWithPayouts(batch, exportedIDs); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// MarkPayoutBatchAsSent marks every payout still PROCESSING in the batch as SENT with the
+// bank's transfer reference. Payouts marked FAILED in the meantime are left alone.
+func (s *PayoutBatchService) MarkPayoutBatchAsSent(batchID, transferReference string) (*models.PayoutBatch, error) {
+	batch, err := s.batchRepo.GetByID(batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payout batch: %w", err)
+	}
+	if batch == nil {
+		return nil, fmt.Errorf("payout batch not found")
+	}
+	if batch.Status == models.PayoutBatchStatusSent {
+		return nil, fmt.Errorf("payout batch was already sent")
+	}
+
+	payouts, err := s.payoutRepo.GetByBatchID(batch.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch payouts: %w", err)
+	}
+
+	var failed []string
+	for _, payout := range payouts {
+		if payout.Status != models.PayoutStatusProcessing {
+			continue
+		}
+		if err := s.payoutService.MarkPayoutAsSent(payout.ID, transferReference); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", payout.ID, err))
+		}
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("failed to mark %d payouts as sent: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	if err := s.batchRepo.MarkSent(batch, transferReference); err != nil {
+		return nil, fmt.Errorf("failed to update payout batch: %w", err)
+	}
+	return batch, nil
+}
+
+// GetPayoutBatch returns a batch and its payouts
+func (s *PayoutBatchService) GetPayoutBatch(batchID string) (*models.PayoutBatch, []*models.Payout, error) {
+	batch, err := s.batchRepo.GetByID(batchID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get payout batch: %w", err)
+	}
+	if batch == nil {
+		return nil, nil, nil
+	}
+
+	payouts, err := s.payoutRepo.GetByBatchID(batch.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get batch payouts: %w", err)
+	}
+	return batch, payouts, nil
+}
+
+// GetBatchPayouts returns the payouts exported in a batch
+func (s *PayoutBatchService) GetBatchPayouts(batchID string) ([]*models.Payout, error) {
+	return s.payoutRepo.GetByBatchID(batchID)
+}
+
+// GetPayoutBatches returns exported batches, newest first
+func (s *PayoutBatchService) GetPayoutBatches(limit, offset int) ([]*models.PayoutBatch, error) {
+	return s.batchRepo.GetAll(limit, offset)
+}
+
+// selectPayouts returns the requested payouts, or all pending ones, that can go in a batch
+func (s *PayoutBatchService) selectPayouts(payoutIDs []string) ([]*models.Payout, error) {
+	if len(payoutIDs) == 0 {
+		payouts, err := s.payoutRepo.GetByStatus(models.PayoutStatusPending)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending payouts: %w", err)
+		}
+
+		var payable []*models.Payout
+		for _, payout := range payouts {
+			if payout.NetAmount > 0 && !payout.BatchID.Valid && !payout.CompanyPayoutID.Valid {
+				payable = append(payable, payout)
+			}
+		}
+		return payable, nil
+	}
+
+	var payouts []*models.Payout
+	seen := map[string]bool{}
+	for _, id := range payoutIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		payout, err := s.payoutRepo.GetByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payout: %w", err)
+		}
+		if payout == nil {
+			return nil, fmt.Errorf("payout %s not found", id)
+		}
+		if payout.Status != models.PayoutStatusPending || payout.BatchID.Valid {
+			return nil, fmt.Errorf("payout %s is %s, only pending payouts can be exported", id, payout.Status)
+		}
+		if payout.NetAmount <= 0 {
+			return nil, fmt.Errorf("payout %s has nothing to pay", id)
+		}
+		if payout.CompanyPayoutID.Valid {
+			return nil, fmt.Errorf("payout %s is paid to the cleaner's company", id)
+		}
+		payouts = append(payouts, payout)
+	}
+	return payouts, nil
+}
+
+// buildTransfer turns a payout into a credit transfer to the cleaner's decrypted IBAN.
+// The remittance text quotes the payout ID so bank statement reconciliation can match it.
+func (s *PayoutBatchService) buildTransfer(payout *models.Payout) (*utils.CreditTransfer, error) {
+	if err := s.payoutService.validateCleanerIBAN(payout.CleanerID); err != nil {
+		return nil, err
+	}
+	iban, err := s.payoutService.GetCleanerIBAN(payout.CleanerID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(payout.CleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("cleaner not found")
+	}
+	name := strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+	if name == "" {
+		return nil, fmt.Errorf("cleaner has no name for the beneficiary")
+	}
+
+	return &utils.CreditTransfer{
+		EndToEndID:     strings.ReplaceAll(payout.ID, "-", ""),
+		CreditorName:   name,
+		CreditorIBAN:   iban,
+		Amount:         payout.NetAmount,
+		Currency:       "RON",
+		RemittanceInfo: fmt.Sprintf("CleanBuddy payout %s %s", payout.PeriodStart.Format("01.2006"), payout.ID),
+	}, nil
+}
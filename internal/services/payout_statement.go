@@ -0,0 +1,92 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// PayoutStatement is the data shown on a cleaner's monthly payout statement
+type PayoutStatement struct {
+	Payout         *models.Payout
+	LineItems      []*models.PayoutLineItem
+	CleanerName    string
+	CleanerAddress string
+	MaskedIBAN     string
+}
+
+// GetPayoutStatement gathers a payout, its line items and the cleaner's details for the statement
+func (s *PayoutService) GetPayoutStatement(payoutID string) (*PayoutStatement, error) {
+	payout, lineItems, err := s.GetPayoutWithLineItems(payoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &PayoutStatement{Payout: payout, LineItems: lineItems}
+
+	user, err := s.userRepo.GetByID(payout.CleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if user != nil {
+		statement.CleanerName = strings.TrimSpace(user.FirstName.String + " " + user.LastName.String)
+	}
+
+	cleaner, err := s.cleanerRepo.GetByUserID(payout.CleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner != nil {
+		var parts []string
+		for _, part := range []string{cleaner.StreetAddress.String, cleaner.City.String, cleaner.County.String} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		statement.CleanerAddress = strings.Join(parts, ", ")
+	}
+
+	// Company cleaners' earnings go to the company account
+	var iban string
+	if payout.CompanyPayoutID.Valid {
+		iban, err = s.getCompanyPayoutIBAN(payout.CompanyPayoutID.String)
+	} else {
+		iban, err = s.GetCleanerIBAN(payout.CleanerID)
+	}
+	if err == nil && len(iban) > 4 {
+		statement.MaskedIBAN = strings.Repeat("*", len(iban)-4) + iban[len(iban)-4:]
+	}
+
+	return statement, nil
+}
+
+// GeneratePayoutStatementPDF renders the statement of a payout as a PDF
+func (s *PayoutService) GeneratePayoutStatementPDF(payoutID string) ([]byte, string, error) {
+	statement, err := s.GetPayoutStatement(payoutID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := s.pdfGenerator.GeneratePayoutStatementPDF(statement)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, payoutStatementFileName(statement.Payout), nil
+}
+
+// payoutStatementAttachment renders the statement for the payout email; it returns nil on failure
+// so the email still goes out without it
+func (s *PayoutService) payoutStatementAttachment(payoutID string) *EmailAttachment {
+	content, fileName, err := s.GeneratePayoutStatementPDF(payoutID)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate statement for payout %s: %v\n", payoutID, err)
+		return nil
+	}
+	return &EmailAttachment{Name: fileName, Content: base64.StdEncoding.EncodeToString(content)}
+}
+
+func payoutStatementFileName(payout *models.Payout) string {
+	return fmt.Sprintf("decont_cleanbuddy_%s.pdf", payout.PeriodStart.Format("2006_01"))
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func TestReconcileWithLedger(t *testing.T) {
+	tests := []struct {
+		name      string
+		ledgerNet float64
+		wantLines int
+	}{
+		{"ledger agrees with the line items", 180, 2},
+		{"ledger owes more", 200, 3},
+		{"ledger owes less", 150.5, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft := &models.PayoutDraft{
+				Payout: &models.Payout{NetAmount: 180},
+				LineItems: []*models.PayoutLineItem{
+					{ItemType: models.PayoutLineItemTypeBooking, CleanerEarnings: 160},
+					{ItemType: models.PayoutLineItemTypeTip, CleanerEarnings: 20},
+				},
+			}
+			reconcileWithLedger(draft, tt.ledgerNet)
+
+			if draft.Payout.NetAmount != tt.ledgerNet {
+				t.Errorf("net amount = %.2f, want the ledger's %.2f", draft.Payout.NetAmount, tt.ledgerNet)
+			}
+			if len(draft.LineItems) != tt.wantLines {
+				t.Fatalf("got %d line items, want %d", len(draft.LineItems), tt.wantLines)
+			}
+			var total float64
+			for _, item := range draft.LineItems {
+				total += item.CleanerEarnings
+			}
+			if roundToCents(total) != tt.ledgerNet {
+				t.Errorf("line items add up to %.2f, want %.2f", total, tt.ledgerNet)
+			}
+		})
+	}
+}
@@ -1,16 +1,20 @@
 package services
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/cleanbuddy/backend/internal/config"
 	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
 	"github.com/jung-kurt/gofpdf"
 )
 
-// PDFGenerator handles invoice PDF generation
+// PDFGenerator handles invoice and payout statement PDF generation
 type PDFGenerator struct {
 	outputDir string
 }
@@ -27,8 +31,9 @@ func NewPDFGenerator(outputDir string) *PDFGenerator {
 	}
 }
 
-// GenerateInvoicePDF generates a PDF invoice and returns the file path
-func (g *PDFGenerator) GenerateInvoicePDF(invoice *models.Invoice) (string, error) {
+// GenerateInvoicePDF generates a PDF invoice with its lines and VAT breakdown and returns the file path.
+// billing holds the details of the company invoiced, nil for invoices to private clients.
+func (g *PDFGenerator) GenerateInvoicePDF(invoice *models.Invoice, lines []*models.InvoiceLine, billing *models.InvoiceBillingDetails) (string, error) {
 	// Create PDF
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
@@ -75,9 +80,24 @@ func (g *PDFGenerator) GenerateInvoicePDF(invoice *models.Invoice) (string, erro
 
 	pdf.SetFont("Arial", "", 10)
 	pdf.SetXY(15, 82)
-	pdf.Cell(0, 5, invoice.ClientName)
+	pdf.Cell(0, 5, utils.StripDiacritics(invoice.ClientName))
 
-	if invoice.ClientEmail.Valid {
+	if billing != nil {
+		cui := billing.CUI
+		if billing.VATPayer {
+			cui = "RO" + cui
+		}
+		pdf.SetXY(15, 88)
+		pdf.Cell(0, 5, fmt.Sprintf("CUI: %s", cui))
+		if billing.RegistrationNumber.Valid {
+			pdf.SetXY(15, 94)
+			pdf.Cell(0, 5, fmt.Sprintf("Reg. Com.: %s", billing.RegistrationNumber.String))
+		}
+		pdf.SetXY(15, 100)
+		pdf.Cell(0, 5, truncateRunes(utils.StripDiacritics(billing.StreetAddress), 50))
+		pdf.SetXY(15, 106)
+		pdf.Cell(0, 5, utils.StripDiacritics(fmt.Sprintf("%s, %s", billing.City, billing.County)))
+	} else if invoice.ClientEmail.Valid {
 		pdf.SetXY(15, 88)
 		pdf.Cell(0, 5, invoice.ClientEmail.String)
 	}
@@ -97,44 +117,54 @@ func (g *PDFGenerator) GenerateInvoicePDF(invoice *models.Invoice) (string, erro
 
 	// Table Header
 	pdf.SetFillColor(240, 240, 240)
-	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFont("Arial", "B", 9)
 
 	startY := 115.0
 	pdf.SetXY(15, startY)
-	pdf.CellFormat(90, 8, "Descriere Serviciu", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(30, 8, "Cant.", "1", 0, "C", true, 0, "")
-	pdf.CellFormat(35, 8, "Pret Unitar", "1", 0, "R", true, 0, "")
-	pdf.CellFormat(35, 8, "Total", "1", 1, "R", true, 0, "")
+	pdf.CellFormat(80, 8, "Descriere Serviciu", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(20, 8, "Cant.", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(30, 8, "Valoare fara TVA", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(20, 8, "Cota TVA", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(30, 8, "TVA", "1", 1, "R", true, 0, "")
 
-	// Table Row - Service
-	pdf.SetFont("Arial", "", 10)
-	pdf.SetXY(15, startY+8)
-	pdf.CellFormat(90, 8, invoice.ServiceDescription, "1", 0, "L", false, 0, "")
-	pdf.CellFormat(30, 8, "1", "1", 0, "C", false, 0, "")
-	pdf.CellFormat(35, 8, fmt.Sprintf("%.2f %s", invoice.Subtotal, invoice.Currency), "1", 0, "R", false, 0, "")
-	pdf.CellFormat(35, 8, fmt.Sprintf("%.2f %s", invoice.Subtotal, invoice.Currency), "1", 1, "R", false, 0, "")
+	// One row per invoice line
+	pdf.SetFont("Arial", "", 9)
+	for _, line := range lines {
+		pdf.SetX(15)
+		pdf.CellFormat(80, 8, truncateRunes(utils.StripDiacritics(line.Description), 48), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 8, strconv.FormatFloat(line.Quantity, 'f', -1, 64), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%.2f %s", line.NetAmount, invoice.Currency), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(20, 8, fmt.Sprintf("%g%%", roundToCents(line.VATRate*100)), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(30, 8, fmt.Sprintf("%.2f %s", line.VATAmount, invoice.Currency), "1", 1, "R", false, 0, "")
+	}
 
 	// Subtotal
-	summaryY := startY + 20
-	pdf.SetXY(125, summaryY)
+	summaryY := pdf.GetY() + 4
+	pdf.SetXY(115, summaryY)
 	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(35, 6, "Subtotal:")
+	pdf.Cell(45, 6, "Total fara TVA:")
 	pdf.SetXY(160, summaryY)
 	pdf.CellFormat(30, 6, fmt.Sprintf("%.2f %s", invoice.Subtotal, invoice.Currency), "", 1, "R", false, 0, "")
 
-	// Tax (TVA)
-	if invoice.TaxAmount > 0 {
-		pdf.SetXY(125, summaryY+6)
-		pdf.Cell(35, 6, "TVA (19%):")
-		pdf.SetXY(160, summaryY+6)
-		pdf.CellFormat(30, 6, fmt.Sprintf("%.2f %s", invoice.TaxAmount, invoice.Currency), "", 1, "R", false, 0, "")
+	// VAT breakdown per rate
+	if config.Get().Company.VATRegistered {
+		for _, group := range groupInvoiceLinesByRate(lines) {
+			summaryY += 6
+			pdf.SetXY(115, summaryY)
+			pdf.Cell(45, 6, fmt.Sprintf("TVA %g%% (baza %.2f):", roundToCents(group.rate*100), group.net))
+			pdf.SetXY(160, summaryY)
+			pdf.CellFormat(30, 6, fmt.Sprintf("%.2f %s", group.vat, invoice.Currency), "", 1, "R", false, 0, "")
+		}
+	} else {
 		summaryY += 6
+		pdf.SetXY(115, summaryY)
+		pdf.Cell(45, 6, "Neplatitor de TVA")
 	}
 
 	// Total
 	pdf.SetFont("Arial", "B", 11)
-	pdf.SetXY(125, summaryY+6)
-	pdf.Cell(35, 7, "TOTAL:")
+	pdf.SetXY(115, summaryY+6)
+	pdf.Cell(45, 7, "TOTAL:")
 	pdf.SetXY(160, summaryY+6)
 	pdf.CellFormat(30, 7, fmt.Sprintf("%.2f %s", invoice.TotalAmount, invoice.Currency), "", 1, "R", false, 0, "")
 
@@ -173,3 +203,489 @@ func (g *PDFGenerator) GenerateInvoicePDF(invoice *models.Invoice) (string, erro
 
 	return filepath, nil
 }
+
+// GeneratePayoutStatementPDF renders a cleaner's monthly payout statement: every booking, tip
+// and adjustment with its fee, and the totals paid out
+func (g *PDFGenerator) GeneratePayoutStatementPDF(statement *PayoutStatement) ([]byte, error) {
+	cfg := config.Get()
+	payout := statement.Payout
+	text := utils.StripDiacritics
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	primaryColor := struct{ R, G, B int }{16, 185, 129} // Green-500
+
+	// Header
+	pdf.SetFillColor(primaryColor.R, primaryColor.G, primaryColor.B)
+	pdf.Rect(0, 0, 210, 40, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 24)
+	pdf.SetXY(15, 12)
+	pdf.Cell(0, 10, "CleanBuddy")
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(15, 24)
+	pdf.Cell(0, 5, "Decont lunar de castiguri")
+	pdf.SetTextColor(0, 0, 0)
+
+	// Title and period
+	pdf.SetFont("Arial", "B", 16)
+	pdf.SetXY(15, 50)
+	pdf.Cell(0, 8, "DECONT PLATA")
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(130, 50)
+	pdf.Cell(0, 5, fmt.Sprintf("Perioada: %s - %s", payout.PeriodStart.Format("02.01.2006"), payout.PeriodEnd.Format("02.01.2006")))
+	pdf.SetXY(130, 56)
+	pdf.Cell(0, 5, fmt.Sprintf("Status: %s", payout.Status))
+	if payout.PaidAt.Valid {
+		pdf.SetXY(130, 62)
+		pdf.Cell(0, 5, fmt.Sprintf("Platit la: %s", payout.PaidAt.Time.Format("02.01.2006")))
+	}
+	if payout.TransferReference.Valid {
+		pdf.SetXY(130, 68)
+		pdf.Cell(0, 5, text(fmt.Sprintf("Referinta: %s", payout.TransferReference.String)))
+	}
+
+	// Cleaner (beneficiary) and platform
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetXY(15, 78)
+	pdf.Cell(0, 6, "Beneficiar:")
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(15, 85)
+	pdf.Cell(0, 5, text(statement.CleanerName))
+	if statement.CleanerAddress != "" {
+		pdf.SetXY(15, 91)
+		pdf.Cell(0, 5, text(statement.CleanerAddress))
+	}
+	if statement.MaskedIBAN != "" {
+		pdf.SetXY(15, 97)
+		pdf.Cell(0, 5, fmt.Sprintf("IBAN: %s", statement.MaskedIBAN))
+	}
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetXY(120, 78)
+	pdf.Cell(0, 6, "Platforma:")
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(120, 85)
+	pdf.Cell(0, 5, text(cfg.Company.LegalName))
+	pdf.SetXY(120, 91)
+	pdf.Cell(0, 5, fmt.Sprintf("CUI: %s", cfg.Company.CUI))
+	pdf.SetXY(120, 97)
+	pdf.Cell(0, 5, text(fmt.Sprintf("%s, %s", cfg.Company.Address.City, cfg.Company.Address.Country)))
+
+	// Bookings and tips
+	var bookings, adjustments []*models.PayoutLineItem
+	for _, item := range statement.LineItems {
+		switch item.ItemType {
+		case models.PayoutLineItemTypeAdjustment, models.PayoutLineItemTypeCarryForward:
+			adjustments = append(adjustments, item)
+		default:
+			bookings = append(bookings, item)
+		}
+	}
+
+	pdf.SetXY(15, 112)
+	pdf.SetFont("Arial", "B", 11)
+	pdf.Cell(0, 6, "Servicii efectuate")
+	pdf.Ln(8)
+
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetFont("Arial", "B", 9)
+	pdf.CellFormat(22, 7, "Data", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(58, 7, "Serviciu", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(26, 7, "Valoare", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(20, 7, "Comision %", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(26, 7, "Comision", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(28, 7, "Castig", "1", 1, "R", true, 0, "")
+
+	pdf.SetFont("Arial", "", 9)
+	for _, item := range bookings {
+		service := text(item.ServiceType)
+		switch item.ItemType {
+		case models.PayoutLineItemTypeTip:
+			service = "Bacsis"
+		case models.PayoutLineItemTypeCash:
+			service += " (numerar)"
+		}
+		pdf.CellFormat(22, 7, item.BookingDate.Format("02.01.2006"), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(58, 7, service, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(26, 7, fmt.Sprintf("%.2f RON", item.BookingAmount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(20, 7, fmt.Sprintf("%.2f%%", item.PlatformFeeRate), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(26, 7, fmt.Sprintf("%.2f RON", item.PlatformFee), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(28, 7, fmt.Sprintf("%.2f RON", item.CleanerEarnings), "1", 1, "R", false, 0, "")
+	}
+	if len(bookings) == 0 {
+		pdf.CellFormat(180, 7, "Niciun serviciu in aceasta perioada", "1", 1, "C", false, 0, "")
+	}
+
+	// Adjustments and carried balances
+	if len(adjustments) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "B", 11)
+		pdf.Cell(0, 6, "Ajustari")
+		pdf.Ln(8)
+
+		pdf.SetFont("Arial", "B", 9)
+		pdf.CellFormat(22, 7, "Data", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(130, 7, "Descriere", "1", 0, "L", true, 0, "")
+		pdf.CellFormat(28, 7, "Suma", "1", 1, "R", true, 0, "")
+
+		pdf.SetFont("Arial", "", 9)
+		for _, item := range adjustments {
+			description := item.Description.String
+			if description == "" {
+				description = string(item.ItemType)
+			}
+			pdf.CellFormat(22, 7, item.BookingDate.Format("02.01.2006"), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(130, 7, text(truncateRunes(description, 80)), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(28, 7, fmt.Sprintf("%.2f RON", item.CleanerEarnings), "1", 1, "R", false, 0, "")
+		}
+	}
+
+	// Totals
+	pdf.Ln(6)
+	totals := []struct {
+		label string
+		value float64
+	}{
+		{"Total servicii:", payout.TotalEarnings},
+		{"Comision platforma:", payout.PlatformFees},
+	}
+	pdf.SetFont("Arial", "", 10)
+	for _, total := range totals {
+		pdf.SetX(115)
+		pdf.Cell(45, 6, total.label)
+		pdf.CellFormat(35, 6, fmt.Sprintf("%.2f RON", total.value), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetX(115)
+	if payout.Status == models.PayoutStatusInvoiced {
+		pdf.Cell(45, 7, "De plata catre platforma:")
+		pdf.CellFormat(35, 7, fmt.Sprintf("%.2f RON", -payout.NetAmount), "", 1, "R", false, 0, "")
+	} else {
+		pdf.Cell(45, 7, "NET DE PLATA:")
+		pdf.CellFormat(35, 7, fmt.Sprintf("%.2f RON", payout.NetAmount), "", 1, "R", false, 0, "")
+	}
+
+	// Footer note for tax filings
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "I", 8)
+	pdf.SetTextColor(128, 128, 128)
+	pdf.MultiCell(180, 4, "Acest decont insumeaza veniturile incasate prin platforma CleanBuddy in perioada de mai sus "+
+		"si poate fi folosit la completarea declaratiei unice (PFA). Documentul nu este o factura fiscala.", "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to generate payout statement PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// InvoiceDocumentLine is one line of an invoice rendered from payout data, split into net amount and VAT
+type InvoiceDocumentLine struct {
+	Description string
+	NetAmount   float64
+	TaxAmount   float64
+}
+
+// invoiceDocument is the content of an invoice laid out by renderInvoiceDocument
+type invoiceDocument struct {
+	Header        string // Issuer shown in the banner
+	Subtitle      string
+	Title         string
+	Number        string
+	IssueDate     time.Time
+	DueDate       *time.Time
+	SupplierTitle string
+	Supplier      []string
+	CustomerTitle string
+	Customer      []string
+	Lines         []InvoiceDocumentLine
+	Currency      string
+	Subtotal      float64
+	TaxAmount     float64
+	TotalAmount   float64
+	Note          string
+	FilePrefix    string
+}
+
+// GenerateSelfBilledInvoicePDF renders an invoice issued on behalf of a PFA cleaner and returns
+// the file path. The cleaner is the supplier; CleanBuddy is the customer and issuer.
+func (g *PDFGenerator) GenerateSelfBilledInvoicePDF(invoice *models.SelfBilledInvoice, lines []InvoiceDocumentLine) (string, error) {
+	cfg := config.Get()
+
+	supplier := []string{invoice.SupplierName, "CUI: " + invoice.SupplierCUI}
+	if invoice.SupplierRegistrationNumber.Valid {
+		supplier = append(supplier, "Reg. Com.: "+invoice.SupplierRegistrationNumber.String)
+	}
+	supplier = append(supplier, invoice.SupplierAddress)
+	if !invoice.SupplierVATPayer {
+		supplier = append(supplier, "Neplatitor de TVA")
+	}
+
+	return g.renderInvoiceDocument(&invoiceDocument{
+		Header:        invoice.SupplierName,
+		Subtitle:      "Factura emisa prin autofacturare de CleanBuddy",
+		Title:         "FACTURA - AUTOFACTURARE",
+		Number:        invoice.InvoiceNumber,
+		IssueDate:     invoice.IssueDate,
+		SupplierTitle: "Furnizor:",
+		Supplier:      supplier,
+		CustomerTitle: "Cumparator:",
+		Customer:      companyPartyLines(cfg),
+		Lines:         lines,
+		Currency:      invoice.Currency,
+		Subtotal:      invoice.Subtotal,
+		TaxAmount:     invoice.TaxAmount,
+		TotalAmount:   invoice.TotalAmount,
+		Note: "Autofactura - factura emisa de beneficiar in numele si in contul furnizorului, " +
+			"conform art. 319 alin. (9) din Codul fiscal si acordului de autofacturare acceptat de furnizor.",
+		FilePrefix: "selfbilled",
+	})
+}
+
+// GenerateCommissionInvoicePDF renders the platform's invoice for the commission retained
+// from a cleaner's or company's payouts and returns the file path
+func (g *PDFGenerator) GenerateCommissionInvoicePDF(invoice *models.CommissionInvoice, lines []InvoiceDocumentLine) (string, error) {
+	cfg := config.Get()
+
+	customer := []string{invoice.CustomerName}
+	if invoice.CustomerCUI.Valid {
+		customer = append(customer, "CUI: "+invoice.CustomerCUI.String)
+	}
+	if invoice.CustomerAddress.Valid {
+		customer = append(customer, invoice.CustomerAddress.String)
+	}
+	if invoice.CustomerEmail.Valid {
+		customer = append(customer, invoice.CustomerEmail.String)
+	}
+
+	dueDate := invoice.DueDate
+	return g.renderInvoiceDocument(&invoiceDocument{
+		Header:        "CleanBuddy",
+		Subtitle:      "Servicii Profesionale de Curatenie",
+		Title:         "FACTURA - COMISION PLATFORMA",
+		Number:        invoice.InvoiceNumber,
+		IssueDate:     invoice.IssueDate,
+		DueDate:       &dueDate,
+		SupplierTitle: "Furnizor:",
+		Supplier:      companyPartyLines(cfg),
+		CustomerTitle: "Client:",
+		Customer:      customer,
+		Lines:         lines,
+		Currency:      invoice.Currency,
+		Subtotal:      invoice.Subtotal,
+		TaxAmount:     invoice.TaxAmount,
+		TotalAmount:   invoice.TotalAmount,
+		Note: fmt.Sprintf("Comision pentru rezervarile intermediate prin platforma CleanBuddy in perioada %s - %s, "+
+			"retinut din platile efectuate catre client.", invoice.PeriodStart.Format("02.01.2006"), invoice.PeriodEnd.Format("02.01.2006")),
+		FilePrefix: "commission",
+	})
+}
+
+// GenerateCreditNotePDF renders a credit note (factura storno) and returns the file path.
+// Following Romanian practice, the credited amounts are shown negative.
+func (g *PDFGenerator) GenerateCreditNotePDF(note *models.CreditNote, invoice *models.Invoice) (string, error) {
+	cfg := config.Get()
+
+	customer := []string{note.ClientName}
+	if note.ClientEmail.Valid {
+		customer = append(customer, note.ClientEmail.String)
+	}
+
+	return g.renderInvoiceDocument(&invoiceDocument{
+		Header:        "CleanBuddy",
+		Subtitle:      "Servicii Profesionale de Curatenie",
+		Title:         "FACTURA STORNO",
+		Number:        note.CreditNoteNumber,
+		IssueDate:     note.IssueDate,
+		SupplierTitle: "Furnizor:",
+		Supplier:      companyPartyLines(cfg),
+		CustomerTitle: "Client:",
+		Customer:      customer,
+		Lines: []InvoiceDocumentLine{{
+			Description: creditNoteDescription(note, invoice),
+			NetAmount:   -note.Subtotal,
+			TaxAmount:   -note.TaxAmount,
+		}},
+		Currency:    note.Currency,
+		Subtotal:    -note.Subtotal,
+		TaxAmount:   -note.TaxAmount,
+		TotalAmount: -note.TotalAmount,
+		Note: fmt.Sprintf("Factura storno la factura %s din %s. Motiv: %s",
+			invoice.InvoiceNumber, invoice.IssueDate.Format("02.01.2006"), note.Reason),
+		FilePrefix: "creditnote",
+	})
+}
+
+// GenerateProformaPDF renders a proforma with the bank details to pay it by and returns the
+// file path. lines are the invoice lines the booking will be invoiced with.
+func (g *PDFGenerator) GenerateProformaPDF(proforma *models.Proforma, lines []*models.InvoiceLine, profile *models.BillingProfile) (string, error) {
+	cfg := config.Get()
+
+	cui := profile.CUI
+	if profile.VATPayer {
+		cui = "RO" + cui
+	}
+	customer := []string{profile.LegalName, "CUI: " + cui}
+	if profile.RegistrationNumber.Valid {
+		customer = append(customer, "Reg. Com.: "+profile.RegistrationNumber.String)
+	}
+	customer = append(customer, profile.StreetAddress, profile.City+", "+profile.County)
+
+	documentLines := make([]InvoiceDocumentLine, len(lines))
+	for i, line := range lines {
+		documentLines[i] = InvoiceDocumentLine{
+			Description: line.Description,
+			NetAmount:   line.NetAmount,
+			TaxAmount:   line.VATAmount,
+		}
+	}
+
+	note := fmt.Sprintf("Plata prin transfer bancar in contul %s (%s) pana la %s, mentionand in detaliile platii numarul %s.",
+		cfg.Company.Bank.IBAN, cfg.Company.Bank.Name, proforma.DueDate.Format("02.01.2006"), proforma.ProformaNumber)
+	if proforma.AmountDue < proforma.TotalAmount {
+		note += fmt.Sprintf(" Din total se scade creditul CleanBuddy de %.2f %s: suma de plata este %.2f %s.",
+			proforma.TotalAmount-proforma.AmountDue, proforma.Currency, proforma.AmountDue, proforma.Currency)
+	}
+	note += " Document fara valoare fiscala; factura se emite dupa efectuarea serviciului."
+
+	dueDate := proforma.DueDate
+	return g.renderInvoiceDocument(&invoiceDocument{
+		Header:        "CleanBuddy",
+		Subtitle:      "Servicii Profesionale de Curatenie",
+		Title:         "FACTURA PROFORMA",
+		Number:        proforma.ProformaNumber,
+		IssueDate:     proforma.IssueDate,
+		DueDate:       &dueDate,
+		SupplierTitle: "Furnizor:",
+		Supplier:      companyPartyLines(cfg),
+		CustomerTitle: "Client:",
+		Customer:      customer,
+		Lines:         documentLines,
+		Currency:      proforma.Currency,
+		Subtotal:      proforma.Subtotal,
+		TaxAmount:     proforma.TaxAmount,
+		TotalAmount:   proforma.TotalAmount,
+		Note:          note,
+		FilePrefix:    "proforma",
+	})
+}
+
+// renderInvoiceDocument lays out an invoice built from payout data and saves it in the output directory
+func (g *PDFGenerator) renderInvoiceDocument(doc *invoiceDocument) (string, error) {
+	text := utils.StripDiacritics
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+
+	primaryColor := struct{ R, G, B int }{16, 185, 129} // Green-500
+
+	// Header
+	pdf.SetFillColor(primaryColor.R, primaryColor.G, primaryColor.B)
+	pdf.Rect(0, 0, 210, 40, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 24)
+	pdf.SetXY(15, 12)
+	pdf.Cell(0, 10, text(doc.Header))
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(15, 24)
+	pdf.Cell(0, 5, text(doc.Subtitle))
+	pdf.SetTextColor(0, 0, 0)
+
+	// Title, number and dates
+	pdf.SetFont("Arial", "B", 16)
+	pdf.SetXY(15, 50)
+	pdf.Cell(0, 8, doc.Title)
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetXY(150, 50)
+	pdf.Cell(0, 5, fmt.Sprintf("Nr: %s", doc.Number))
+	pdf.SetXY(150, 56)
+	pdf.Cell(0, 5, fmt.Sprintf("Data: %s", doc.IssueDate.Format("02.01.2006")))
+	if doc.DueDate != nil {
+		pdf.SetXY(150, 62)
+		pdf.Cell(0, 5, fmt.Sprintf("Scadenta: %s", doc.DueDate.Format("02.01.2006")))
+	}
+
+	// Parties
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetXY(15, 70)
+	pdf.Cell(0, 6, doc.SupplierTitle)
+	pdf.SetXY(120, 70)
+	pdf.Cell(0, 6, doc.CustomerTitle)
+
+	pdf.SetFont("Arial", "", 10)
+	for i, line := range doc.Supplier {
+		pdf.SetXY(15, 77+float64(i)*6)
+		pdf.Cell(100, 5, truncateRunes(text(line), 55))
+	}
+	for i, line := range doc.Customer {
+		pdf.SetXY(120, 77+float64(i)*6)
+		pdf.Cell(0, 5, truncateRunes(text(line), 45))
+	}
+
+	// Lines
+	pdf.SetXY(15, 115)
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.CellFormat(10, 8, "Nr.", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(100, 8, "Descriere", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(35, 8, "Valoare", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(35, 8, "TVA", "1", 1, "R", true, 0, "")
+
+	pdf.SetFont("Arial", "", 9)
+	for i, line := range doc.Lines {
+		pdf.SetX(15)
+		pdf.CellFormat(10, 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(100, 7, truncateRunes(text(line.Description), 60), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(35, 7, fmt.Sprintf("%.2f %s", line.NetAmount, doc.Currency), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, fmt.Sprintf("%.2f %s", line.TaxAmount, doc.Currency), "1", 1, "R", false, 0, "")
+	}
+
+	// Totals
+	pdf.Ln(4)
+	pdf.SetFont("Arial", "", 10)
+	pdf.SetX(115)
+	pdf.Cell(45, 6, "Total fara TVA:")
+	pdf.CellFormat(35, 6, fmt.Sprintf("%.2f %s", doc.Subtotal, doc.Currency), "", 1, "R", false, 0, "")
+	pdf.SetX(115)
+	pdf.Cell(45, 6, "TVA:")
+	pdf.CellFormat(35, 6, fmt.Sprintf("%.2f %s", doc.TaxAmount, doc.Currency), "", 1, "R", false, 0, "")
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetX(115)
+	pdf.Cell(45, 7, "TOTAL DE PLATA:")
+	pdf.CellFormat(35, 7, fmt.Sprintf("%.2f %s", doc.TotalAmount, doc.Currency), "", 1, "R", false, 0, "")
+
+	if doc.Note != "" {
+		pdf.Ln(8)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.MultiCell(180, 4, text(doc.Note), "", "L", false)
+	}
+
+	filename := fmt.Sprintf("%s_%s_%d.pdf", doc.FilePrefix, doc.Number, time.Now().Unix())
+	path := filepath.Join(g.outputDir, filename)
+	if err := pdf.OutputFileAndClose(path); err != nil {
+		return "", fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	return path, nil
+}
+
+// companyPartyLines returns CleanBuddy's identification block for invoices
+func companyPartyLines(cfg *config.Config) []string {
+	return []string{
+		cfg.Company.LegalName,
+		"CUI: " + cfg.Company.CUI,
+		cfg.Company.Address.City + ", " + cfg.Company.Address.Country,
+	}
+}
@@ -17,9 +17,12 @@ type PriceQuote struct {
 	Subtotal        float64
 	Discount        float64
 	PlatformFee     float64
+	PlatformFeeRule models.PlatformFeeRule
 	TotalPrice      float64
+	VATAmount       float64 // VAT included in TotalPrice
 	CleanerPayout   float64
 	EstimatedHours  int
+	Lines           []TaxedLine
 	Breakdown       PriceBreakdown
 }
 
@@ -37,8 +40,9 @@ type PriceBreakdown struct {
 
 // PricingService handles pricing calculations
 type PricingService struct {
-	clientRepo *models.ClientRepository
-	cfg        *config.Config
+	clientRepo       *models.ClientRepository
+	feePolicyService *FeePolicyService
+	cfg              *config.Config
 }
 
 // NewPricingService creates a new pricing service
@@ -49,6 +53,11 @@ func NewPricingService(db *sql.DB) *PricingService {
 	}
 }
 
+// SetFeePolicyService sets the fee policy used to split the price between platform and cleaner
+func (s *PricingService) SetFeePolicyService(feePolicyService *FeePolicyService) {
+	s.feePolicyService = feePolicyService
+}
+
 // CalculatePrice calculates the total price for a booking
 func (s *PricingService) CalculatePrice(
 	clientID string,
@@ -88,31 +97,19 @@ func (s *PricingService) CalculatePrice(
 	}
 
 	// Calculate add-ons
+	addonLines := s.addonLines(includesWindows, numberOfWindows, includesCarpet, carpetAreaSqm,
+		includesFridge, includesOven, includesBalcony, includesSupplies)
 	addonsPrice := 0.0
 	windowsPrice := 0.0
-	if includesWindows && numberOfWindows > 0 {
-		windowsPrice = s.cfg.Pricing.Addons.WindowCleaningPerWindow * float64(numberOfWindows)
-		addonsPrice += windowsPrice
-	}
-
 	carpetPrice := 0.0
-	if includesCarpet && carpetAreaSqm > 0 {
-		carpetPrice = s.cfg.Pricing.Addons.CarpetCleaningPerSqm * float64(carpetAreaSqm)
-		addonsPrice += carpetPrice
-	}
-
-	// Add fixed-price addons
-	if includesFridge {
-		addonsPrice += s.cfg.Pricing.Addons.FridgeCleaning
-	}
-	if includesOven {
-		addonsPrice += s.cfg.Pricing.Addons.OvenCleaning
-	}
-	if includesBalcony {
-		addonsPrice += s.cfg.Pricing.Addons.BalconyCleaning
-	}
-	if includesSupplies {
-		addonsPrice += s.cfg.Pricing.Addons.CleaningSupplies
+	for _, line := range addonLines {
+		addonsPrice += line.Amount
+		switch line.Item {
+		case addonItemWindows:
+			windowsPrice = line.Amount
+		case addonItemCarpet:
+			carpetPrice = line.Amount
+		}
 	}
 
 	// Apply time-based multipliers
@@ -143,25 +140,43 @@ func (s *PricingService) CalculatePrice(
 
 	totalAfterDiscount := subtotal - discount
 
-	// Calculate platform fee
-	platformFeePercentage := s.cfg.Pricing.DefaultPlatformFeePercentage
-	platformFee := totalAfterDiscount * (platformFeePercentage / 100.0)
-
-	// Total price to client
+	// Total price to client; VAT is added on top only when prices are configured net of VAT
+	lines := append([]PriceLine{{
+		Description: "Servicii de curățenie",
+		Category:    VATCategoryService,
+		Quantity:    float64(hoursToCharge),
+		UnitCode:    "HUR",
+		Amount:      basePrice + areaPrice,
+	}}, addonLines...)
+	vatDay := vatDate(scheduledDate, time.Now())
 	totalPrice := totalAfterDiscount
-
-	// Cleaner payout (total - platform fee)
-	cleanerPayout := totalAfterDiscount - platformFee
+	if s.cfg.Company.PricesExcludeVAT {
+		totalPrice = roundToCents(totalAfterDiscount + vatOnNet(lines, totalAfterDiscount, &s.cfg.Company, vatDay))
+	}
+	taxedLines := taxLines(lines, totalPrice, &s.cfg.Company, vatDay)
+	_, vatAmount, _ := sumTaxedLines(taxedLines)
+
+	// Platform fee before a cleaner is assigned; fixed again on assignment
+	feeSplit := splitPrice(totalPrice, s.cfg.Pricing.DefaultPlatformFeePercentage, models.PlatformFeeRuleDefault)
+	if s.feePolicyService != nil {
+		feeSplit, err = s.feePolicyService.Evaluate(clientID, nil, time.Now(), totalPrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate platform fee: %w", err)
+		}
+	}
 
 	return &PriceQuote{
-		BasePrice:      basePrice + areaPrice,
-		AddonsPrice:    addonsPrice,
-		Subtotal:       subtotal,
-		Discount:       discount,
-		PlatformFee:    platformFee,
-		TotalPrice:     totalPrice,
-		CleanerPayout:  cleanerPayout,
-		EstimatedHours: hoursToCharge,
+		BasePrice:       basePrice + areaPrice,
+		AddonsPrice:     addonsPrice,
+		Subtotal:        subtotal,
+		Discount:        discount,
+		PlatformFee:     feeSplit.PlatformFee,
+		PlatformFeeRule: feeSplit.Rule,
+		TotalPrice:      totalPrice,
+		VATAmount:       vatAmount,
+		CleanerPayout:   feeSplit.CleanerPayout,
+		EstimatedHours:  hoursToCharge,
+		Lines:           taxedLines,
 		Breakdown: PriceBreakdown{
 			BasePricePerHour:      servicePricing.BasePricePerHour,
 			HoursCharged:          hoursToCharge,
@@ -170,11 +185,83 @@ func (s *PricingService) CalculatePrice(
 			CarpetPrice:           carpetPrice,
 			TimeMultiplier:        timeMultiplier,
 			DiscountPercentage:    discountPercentage,
-			PlatformFeePercentage: platformFeePercentage,
+			PlatformFeePercentage: feeSplit.Rate,
 		},
 	}, nil
 }
 
+// Add-on items of a booking's price lines
+const (
+	addonItemWindows  = "windows"
+	addonItemCarpet   = "carpet"
+	addonItemFridge   = "fridge"
+	addonItemOven     = "oven"
+	addonItemBalcony  = "balcony"
+	addonItemSupplies = "supplies"
+)
+
+// addonLines itemizes the selected add-ons at list price
+func (s *PricingService) addonLines(
+	includesWindows bool,
+	numberOfWindows int,
+	includesCarpet bool,
+	carpetAreaSqm int,
+	includesFridge bool,
+	includesOven bool,
+	includesBalcony bool,
+	includesSupplies bool,
+) []PriceLine {
+	addons := s.cfg.Pricing.Addons
+	var lines []PriceLine
+	if includesWindows && numberOfWindows > 0 {
+		lines = append(lines, PriceLine{
+			Item:        addonItemWindows,
+			Description: fmt.Sprintf("Curățare geamuri - %d bucăți", numberOfWindows),
+			Category:    VATCategoryAddons,
+			Quantity:    float64(numberOfWindows),
+			UnitCode:    "C62",
+			Amount:      addons.WindowCleaningPerWindow * float64(numberOfWindows),
+		})
+	}
+	if includesCarpet && carpetAreaSqm > 0 {
+		lines = append(lines, PriceLine{
+			Item:        addonItemCarpet,
+			Description: fmt.Sprintf("Curățare covoare/mochetă - %d mp", carpetAreaSqm),
+			Category:    VATCategoryAddons,
+			Quantity:    float64(carpetAreaSqm),
+			UnitCode:    "MTK",
+			Amount:      addons.CarpetCleaningPerSqm * float64(carpetAreaSqm),
+		})
+	}
+
+	// Fixed-price add-ons
+	fixed := []struct {
+		included    bool
+		item        string
+		description string
+		category    string
+		price       float64
+	}{
+		{includesFridge, addonItemFridge, "Curățare frigider", VATCategoryAddons, addons.FridgeCleaning},
+		{includesOven, addonItemOven, "Curățare cuptor", VATCategoryAddons, addons.OvenCleaning},
+		{includesBalcony, addonItemBalcony, "Curățare balcon", VATCategoryAddons, addons.BalconyCleaning},
+		{includesSupplies, addonItemSupplies, "Produse de curățenie", VATCategorySupplies, addons.CleaningSupplies},
+	}
+	for _, f := range fixed {
+		if f.included {
+			lines = append(lines, PriceLine{
+				Item:        f.item,
+				Description: f.description,
+				Category:    f.category,
+				Quantity:    1,
+				UnitCode:    "C62",
+				Amount:      f.price,
+			})
+		}
+	}
+	return lines
+}
+
 // getServicePricing returns pricing config for a service type
 func (s *PricingService) getServicePricing(serviceType models.ServiceType) *servicePricingConfig {
 	switch serviceType {
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// ProformaService issues proformas to companies paying their bookings by bank transfer, tracks
+// the transfers, converts paid proformas into the booking's invoice and reminds late payers
+type ProformaService struct {
+	proformaRepo   *models.ProformaRepository
+	bookingRepo    *models.BookingRepository
+	paymentRepo    *models.PaymentRepository
+	billingRepo    *models.BillingProfileRepository
+	userRepo       *models.UserRepository
+	invoiceService *InvoiceService
+	ledgerService  *LedgerService
+	emailService   *EmailService
+	pdfGenerator   *PDFGenerator
+	companyConfig  *config.CompanyConfig
+	paymentConfig  *config.PaymentConfig
+}
+
+// NewProformaService creates a new proforma service
+func NewProformaService(db *sql.DB, invoiceService *InvoiceService, companyConfig *config.CompanyConfig, paymentConfig *config.PaymentConfig) *ProformaService {
+	return &ProformaService{
+		proformaRepo:   models.NewProformaRepository(db),
+		bookingRepo:    models.NewBookingRepository(db),
+		paymentRepo:    models.NewPaymentRepository(db),
+		billingRepo:    models.NewBillingProfileRepository(db),
+		userRepo:       models.NewUserRepository(db),
+		invoiceService: invoiceService,
+		pdfGenerator:   NewPDFGenerator("./invoices/pdf"),
+		companyConfig:  companyConfig,
+		paymentConfig:  paymentConfig,
+	}
+}
+
+// SetLedgerService sets the ledger service transfers are recorded in
+func (s *ProformaService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// SetEmailService sets the email service proformas and reminders are sent with
+func (s *ProformaService) SetEmailService(emailService *EmailService) {
+	s.emailService = emailService
+}
+
+// IssueForBooking issues the proforma of a confirmed booking paid by bank transfer and emails it
+// to the company. It returns nil when the booking is not confirmed yet or is paid another way,
+// and the existing proforma when one was already issued.
+func (s *ProformaService) IssueForBooking(bookingID string) (*models.Proforma, error) {
+	existing, err := s.proformaRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing proforma: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	booking, err := s.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return nil, nil
+	}
+
+	payment, err := s.openTransfer(bookingID)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+
+	if !booking.BillingProfileID.Valid {
+		return nil, fmt.Errorf("booking %s is paid by bank transfer but has no billing profile", bookingID)
+	}
+	profile, err := s.billingRepo.GetByID(booking.BillingProfileID.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billing profile: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("billing profile not found")
+	}
+
+	issueDate := time.Now()
+	lines := s.invoiceService.invoiceLines(booking, issueDate)
+	var subtotal, taxAmount, totalAmount float64
+	for _, line := range lines {
+		subtotal += line.NetAmount
+		taxAmount += line.VATAmount
+		totalAmount += line.GrossAmount
+	}
+
+	proforma := &models.Proforma{
+		BookingID:        bookingID,
+		PaymentID:        payment.ID,
+		BillingProfileID: booking.BillingProfileID,
+		IssueDate:        issueDate,
+		DueDate:          s.dueDate(issueDate, booking.ScheduledDate),
+		ClientName:       profile.LegalName,
+		ClientEmail:      profile.Email,
+		Subtotal:         roundToCents(subtotal),
+		TaxAmount:        roundToCents(taxAmount),
+		TotalAmount:      roundToCents(totalAmount),
+		AmountDue:        roundToCents(payment.Amount),
+		Currency:         payment.Currency,
+		Status:           models.ProformaStatusIssued,
+	}
+	if !proforma.ClientEmail.Valid {
+		proforma.ClientEmail = s.clientEmail(booking)
+	}
+
+	if err := s.proformaRepo.Create(proforma); err != nil {
+		return nil, err
+	}
+
+	pdfPath, err := s.pdfGenerator.GenerateProformaPDF(proforma, lines, profile)
+	if err != nil {
+		// Log error but don't fail the proforma; the bank details are also in the email
+		fmt.Printf("Warning: failed to generate PDF for proforma %s: %v\n", proforma.ProformaNumber, err)
+	} else {
+		proforma.PdfURL = sql.NullString{String: pdfPath, Valid: true}
+		if err := s.proformaRepo.UpdatePdfURL(proforma.ID, pdfPath); err != nil {
+			fmt.Printf("Warning: failed to update proforma %s with its PDF: %v\n", proforma.ProformaNumber, err)
+		}
+	}
+
+	// Email the proforma to the company (async)
+	sent := *proforma
+	go func() {
+		if err := s.sendProformaEmail(&sent); err != nil {
+			fmt.Printf("Warning: failed to email proforma %s: %v\n", sent.ProformaNumber, err)
+		}
+	}()
+
+	return proforma, nil
+}
+
+// RecordTransfer settles an issued proforma once its bank transfer has arrived: the BANK_TRANSFER
+// payment is captured and, if the booking was already invoiced, the invoice is marked as paid
+func (s *ProformaService) RecordTransfer(proformaID, reference string) (*models.Proforma, error) {
+	proforma, err := s.proformaRepo.GetByID(proformaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proforma: %w", err)
+	}
+	if proforma == nil {
+		return nil, fmt.Errorf("proforma not found")
+	}
+	if proforma.Status != models.ProformaStatusIssued {
+		return nil, fmt.Errorf("proforma is already %s", proforma.Status)
+	}
+
+	payment, err := s.paymentRepo.GetByID(proforma.PaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return nil, fmt.Errorf("payment not found")
+	}
+	switch payment.Status {
+	case models.PaymentStatusAuthorized:
+		if err := s.captureTransfer(payment, reference); err != nil {
+			return nil, err
+		}
+	case models.PaymentStatusCaptured:
+		// Already recorded, e.g. by an earlier attempt that failed further on
+	default:
+		return nil, fmt.Errorf("payment of proforma %s is %s", proforma.ProformaNumber, payment.Status)
+	}
+
+	if err := s.proformaRepo.MarkPaid(proforma.ID, reference); err != nil {
+		return nil, err
+	}
+
+	if proforma.InvoiceID.Valid {
+		if err := s.markInvoicePaid(proforma.InvoiceID.String); err != nil {
+			fmt.Printf("Warning: failed to mark invoice of proforma %s as paid: %v\n", proforma.ProformaNumber, err)
+		}
+	}
+
+	return s.proformaRepo.GetByID(proforma.ID)
+}
+
+// SettleInvoice records the transfer of the proforma an invoice was converted from, when the
+// client paid quoting the invoice rather than the proforma
+func (s *ProformaService) SettleInvoice(invoiceID, reference string) error {
+	proforma, err := s.proformaRepo.GetByInvoiceID(invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get proforma: %w", err)
+	}
+	if proforma == nil || proforma.Status != models.ProformaStatusIssued {
+		return nil
+	}
+	_, err = s.RecordTransfer(proforma.ID, reference)
+	return err
+}
+
+// ConvertToInvoice links a booking's proforma to the invoice issued when the booking was completed.
+// The invoice is marked as paid right away when the transfer has already arrived.
+func (s *ProformaService) ConvertToInvoice(bookingID string, invoice *models.Invoice) error {
+	proforma, err := s.proformaRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get proforma: %w", err)
+	}
+	if proforma == nil || proforma.InvoiceID.Valid {
+		return nil
+	}
+
+	if err := s.proformaRepo.MarkConverted(proforma.ID, invoice.ID); err != nil {
+		return err
+	}
+
+	if proforma.Status == models.ProformaStatusPaid {
+		return s.markInvoicePaid(invoice.ID)
+	}
+	return nil
+}
+
+// CancelForBooking cancels a booking's proforma once its transfer is no longer expected
+func (s *ProformaService) CancelForBooking(bookingID string) error {
+	proforma, err := s.proformaRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get proforma: %w", err)
+	}
+	if proforma == nil || proforma.Status != models.ProformaStatusIssued {
+		return nil
+	}
+	return s.proformaRepo.Cancel(proforma.ID)
+}
+
+// GetUnpaidProformas returns the proformas still waiting for their transfer
+func (s *ProformaService) GetUnpaidProformas() ([]*models.Proforma, error) {
+	proformas, err := s.proformaRepo.GetUnpaid()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unpaid proformas: %w", err)
+	}
+	return proformas, nil
+}
+
+// GetProformas returns proformas newest first, filtered by status if one is given
+func (s *ProformaService) GetProformas(status models.ProformaStatus, limit, offset int) ([]*models.Proforma, error) {
+	return s.proformaRepo.GetByStatus(status, limit, offset)
+}
+
+// GetProformaByBookingID returns a booking's proforma to its client, or to anyone when userID is empty
+func (s *ProformaService) GetProformaByBookingID(bookingID, userID string) (*models.Proforma, error) {
+	if userID != "" {
+		booking, err := s.bookingRepo.GetByID(bookingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get booking: %w", err)
+		}
+		if booking == nil {
+			return nil, fmt.Errorf("booking not found")
+		}
+		if booking.ClientID != userID {
+			return nil, fmt.Errorf("unauthorized")
+		}
+	}
+
+	return s.proformaRepo.GetByBookingID(bookingID)
+}
+
+// SendOverdueReminders closes proformas of cancelled payments and reminds companies of proformas
+// past their due date, every payment.proforma_reminder_days up to payment.proforma_max_reminders
+// times. It returns the number of reminders sent.
+func (s *ProformaService) SendOverdueReminders() (int, error) {
+	if closed, err := s.proformaRepo.CancelForClosedPayments(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else if closed > 0 {
+		fmt.Printf("Cancelled %d proforma(s) of cancelled payments\n", closed)
+	}
+
+	intervalDays := s.paymentConfig.ProformaReminderDays
+	if intervalDays <= 0 {
+		intervalDays = 3
+	}
+	maxReminders := s.paymentConfig.ProformaMaxReminders
+	if maxReminders <= 0 {
+		maxReminders = 3
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	overdue, err := s.proformaRepo.GetOverdue(today, now.AddDate(0, 0, -intervalDays), maxReminders)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get overdue proformas: %w", err)
+	}
+	if s.emailService == nil || len(overdue) == 0 {
+		return 0, nil
+	}
+
+	sent := 0
+	for _, proforma := range overdue {
+		if !proforma.ClientEmail.Valid || proforma.ClientEmail.String == "" {
+			fmt.Printf("Warning: overdue proforma %s has no email address to remind\n", proforma.ProformaNumber)
+			continue
+		}
+		booking, err := s.bookingRepo.GetByID(proforma.BookingID)
+		if err != nil || booking == nil {
+			fmt.Printf("Warning: failed to get booking of proforma %s: %v\n", proforma.ProformaNumber, err)
+			continue
+		}
+
+		if err := s.emailService.SendProformaReminderEmail(context.Background(), proforma.ClientEmail.String,
+			proforma.ClientName, proforma.ProformaNumber, proforma.AmountDue, proforma.Currency,
+			proforma.DueDate.Format("02.01.2006"), booking.ScheduledDate.Format("02.01.2006"), s.companyConfig.Bank.IBAN); err != nil {
+			fmt.Printf("Warning: failed to remind about proforma %s: %v\n", proforma.ProformaNumber, err)
+			continue
+		}
+		if err := s.proformaRepo.RecordReminder(proforma.ID); err != nil {
+			fmt.Printf("Warning: failed to record reminder for proforma %s: %v\n", proforma.ProformaNumber, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// RunOverdueReminders periodically sends overdue proforma reminders (run as a goroutine)
+func (s *ProformaService) RunOverdueReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := s.SendOverdueReminders()
+		if err != nil {
+			fmt.Printf("Warning: failed to send proforma reminders: %v\n", err)
+			continue
+		}
+		if count > 0 {
+			fmt.Printf("Sent %d overdue proforma reminder(s)\n", count)
+		}
+	}
+}
+
+// openTransfer returns the BANK_TRANSFER payment of a booking still waiting for the money, or nil
+func (s *ProformaService) openTransfer(bookingID string) (*models.Payment, error) {
+	payments, err := s.paymentRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking payments: %w", err)
+	}
+	for _, payment := range payments {
+		if payment.Provider == models.PaymentProviderBankTransfer &&
+			payment.PaymentType == models.PaymentTypePreauthorization &&
+			payment.Status == models.PaymentStatusAuthorized {
+			return payment, nil
+		}
+	}
+	return nil, nil
+}
+
+// captureTransfer records the money of a BANK_TRANSFER payment as received
+func (s *ProformaService) captureTransfer(payment *models.Payment, reference string) error {
+	payment.Status = models.PaymentStatusCaptured
+	payment.CapturedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if reference != "" {
+		payment.ProviderTransactionID = sql.NullString{String: reference, Valid: true}
+	}
+	response := map[string]interface{}{
+		"status":    "captured",
+		"message":   "Bank transfer received",
+		"reference": reference,
+	}
+	payment.ProviderResponse, _ = json.Marshal(response)
+
+	if err := s.paymentRepo.Update(payment); err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostCapture(payment); err != nil {
+			fmt.Printf("Warning: failed to record bank transfer %s in ledger: %v\n", payment.ID, err)
+		}
+	}
+	return nil
+}
+
+// markInvoicePaid marks an invoice as paid unless it already is
+func (s *ProformaService) markInvoicePaid(invoiceID string) error {
+	invoice, err := s.invoiceService.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice.Status != models.InvoiceStatusIssued {
+		return nil
+	}
+	return s.invoiceService.MarkInvoiceAsPaid(invoiceID)
+}
+
+// dueDate gives the company payment.proforma_due_days to pay, but no later than the booking date
+func (s *ProformaService) dueDate(issueDate, scheduledDate time.Time) time.Time {
+	days := s.paymentConfig.ProformaDueDays
+	if days <= 0 {
+		days = 5
+	}
+	due := issueDate.AddDate(0, 0, days)
+	if scheduledDate.Before(due) {
+		due = scheduledDate
+	}
+	if due.Before(issueDate) {
+		due = issueDate
+	}
+	return due
+}
+
+// clientEmail falls back to the booking client's own address when the billing profile has none
+func (s *ProformaService) clientEmail(booking *models.Booking) sql.NullString {
+	client, err := s.userRepo.GetByID(booking.ClientID)
+	if err != nil || client == nil {
+		return sql.NullString{}
+	}
+	return client.Email
+}
+
+// sendProformaEmail emails a proforma with its PDF and the bank details to pay it to
+func (s *ProformaService) sendProformaEmail(proforma *models.Proforma) error {
+	if s.emailService == nil {
+		return fmt.Errorf("email service not configured")
+	}
+	if !proforma.ClientEmail.Valid || proforma.ClientEmail.String == "" {
+		return fmt.Errorf("proforma %s has no client email address", proforma.ProformaNumber)
+	}
+
+	var attachments []EmailAttachment
+	if proforma.PdfURL.Valid {
+		content, err := os.ReadFile(proforma.PdfURL.String)
+		if err != nil {
+			return fmt.Errorf("failed to read proforma PDF: %w", err)
+		}
+		attachments = append(attachments, EmailAttachment{
+			Name:    proforma.ProformaNumber + ".pdf",
+			Content: base64.StdEncoding.EncodeToString(content),
+		})
+	}
+
+	return s.emailService.SendProformaEmail(context.Background(), proforma.ClientEmail.String, proforma.ClientName,
+		proforma.ProformaNumber, proforma.AmountDue, proforma.Currency, proforma.DueDate.Format("02.01.2006"),
+		s.companyConfig.Bank.IBAN, s.companyConfig.Bank.Name, attachments)
+}
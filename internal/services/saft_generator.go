@@ -0,0 +1,740 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+const (
+	saftNamespace    = "mfp:anaf:dgti:d406:declaratie:v1"
+	saftVersion      = "2.4.6"
+	saftVATTaxType   = "300"           // TVA in ANAF's tax type nomenclature
+	saftAnonymousTIN = "0000000000000" // Registration number reported for individuals
+)
+
+// SAF-T (D406) audit file, the monthly declaration of the accounting records to ANAF
+type SAFTAuditFile struct {
+	XMLName         xml.Name            `xml:"AuditFile"`
+	XMLNS           string              `xml:"xmlns,attr"`
+	Header          SAFTHeader          `xml:"Header"`
+	MasterFiles     SAFTMasterFiles     `xml:"MasterFiles"`
+	GeneralLedger   SAFTGeneralLedger   `xml:"GeneralLedgerEntries"`
+	SourceDocuments SAFTSourceDocuments `xml:"SourceDocuments"`
+}
+
+type SAFTHeader struct {
+	AuditFileVersion        string
+	AuditFileCountry        string
+	AuditFileDateCreated    string
+	SoftwareCompanyName     string
+	SoftwareID              string
+	SoftwareVersion         string
+	Company                 SAFTCompany
+	DefaultCurrencyCode     string
+	SelectionCriteria       SAFTSelectionCriteria
+	HeaderComment           string // L: monthly declaration
+	SegmentIndex            int
+	TotalSegmentsInsequence int
+	TaxAccountingBasis      string // A: general commercial company
+}
+
+type SAFTCompany struct {
+	RegistrationNumber string
+	Name               string
+	Address            SAFTAddress
+	IBANNumber         string `xml:"BankAccount>IBANNumber,omitempty"`
+}
+
+type SAFTAddress struct {
+	StreetName string `xml:"StreetName,omitempty"`
+	City       string
+	PostalCode string `xml:"PostalCode,omitempty"`
+	Region     string `xml:"Region,omitempty"`
+	Country    string
+}
+
+type SAFTSelectionCriteria struct {
+	PeriodStart     int
+	PeriodStartYear int
+	PeriodEnd       int
+	PeriodEndYear   int
+}
+
+type SAFTMasterFiles struct {
+	Accounts  []SAFTAccount       `xml:"GeneralLedgerAccounts>Account"`
+	Customers []SAFTPartner       `xml:"Customers>Customer"`
+	Suppliers []SAFTPartner       `xml:"Suppliers>Supplier"`
+	TaxTable  []SAFTTaxTableEntry `xml:"TaxTable>TaxTableEntry"`
+}
+
+type SAFTAccount struct {
+	AccountID            string
+	AccountDescription   string
+	StandardAccountID    string
+	AccountType          string // Activ, Pasiv or Bifunctional
+	OpeningDebitBalance  string `xml:"OpeningDebitBalance,omitempty"`
+	OpeningCreditBalance string `xml:"OpeningCreditBalance,omitempty"`
+	ClosingDebitBalance  string `xml:"ClosingDebitBalance,omitempty"`
+	ClosingCreditBalance string `xml:"ClosingCreditBalance,omitempty"`
+}
+
+// SAFTPartner is a customer or a supplier; only the ID element's name differs
+type SAFTPartner struct {
+	CompanyStructure     SAFTCompanyStructure
+	CustomerID           string `xml:"CustomerID,omitempty"`
+	SupplierID           string `xml:"SupplierID,omitempty"`
+	AccountID            string
+	OpeningDebitBalance  string `xml:"OpeningDebitBalance,omitempty"`
+	OpeningCreditBalance string `xml:"OpeningCreditBalance,omitempty"`
+	ClosingDebitBalance  string `xml:"ClosingDebitBalance,omitempty"`
+	ClosingCreditBalance string `xml:"ClosingCreditBalance,omitempty"`
+}
+
+type SAFTCompanyStructure struct {
+	RegistrationNumber string
+	Name               string
+	Address            SAFTAddress
+}
+
+type SAFTTaxTableEntry struct {
+	TaxType     string
+	Description string
+	TaxCodes    []SAFTTaxCodeDetails `xml:"TaxCodeDetails"`
+}
+
+type SAFTTaxCodeDetails struct {
+	TaxCode       string
+	Description   string
+	TaxPercentage string
+	Country       string
+}
+
+type SAFTGeneralLedger struct {
+	NumberOfEntries int
+	TotalDebit      string
+	TotalCredit     string
+	Journals        []SAFTJournal `xml:"Journal"`
+}
+
+type SAFTJournal struct {
+	JournalID    string
+	Description  string
+	Type         string
+	Transactions []SAFTTransaction `xml:"Transaction"`
+}
+
+type SAFTTransaction struct {
+	TransactionID   string
+	Period          int
+	PeriodYear      int
+	TransactionDate string
+	Description     string
+	SystemEntryDate string
+	GLPostingDate   string
+	Lines           []SAFTTransactionLine `xml:"TransactionLine"`
+}
+
+type SAFTTransactionLine struct {
+	RecordID     string
+	AccountID    string
+	SupplierID   string `xml:"SupplierID,omitempty"`
+	Description  string
+	DebitAmount  *SAFTAmount `xml:"DebitAmount,omitempty"`
+	CreditAmount *SAFTAmount `xml:"CreditAmount,omitempty"`
+}
+
+type SAFTAmount struct {
+	Amount string
+}
+
+type SAFTSourceDocuments struct {
+	SalesInvoices SAFTSalesInvoices `xml:"SalesInvoices"`
+	Payments      SAFTPayments      `xml:"Payments"`
+}
+
+type SAFTSalesInvoices struct {
+	NumberOfEntries int
+	TotalDebit      string
+	TotalCredit     string
+	Invoices        []SAFTInvoice `xml:"Invoice"`
+}
+
+type SAFTInvoice struct {
+	InvoiceNo            string
+	CustomerID           string      `xml:"CustomerInfo>CustomerID"`
+	BillingAddress       SAFTAddress `xml:"CustomerInfo>BillingAddress"`
+	AccountID            string
+	Period               int
+	PeriodYear           int
+	InvoiceDate          string
+	InvoiceType          string // 380 invoice, 381 credit note
+	SelfBillingIndicator string
+	GLPostingDate        string
+	Lines                []SAFTInvoiceLine    `xml:"InvoiceLine"`
+	TaxTotals            []SAFTTaxInformation `xml:"DocumentTotals>TaxInformationTotals"`
+	NetTotal             string               `xml:"DocumentTotals>NetTotal"`
+	GrossTotal           string               `xml:"DocumentTotals>GrossTotal"`
+}
+
+type SAFTInvoiceLine struct {
+	LineNumber           int
+	AccountID            string
+	Quantity             string
+	InvoiceUOM           string
+	UnitPrice            string
+	TaxPointDate         string
+	Description          string
+	InvoiceLineAmount    SAFTAmount
+	DebitCreditIndicator string // C for sales, D for credit notes
+	TaxInformation       SAFTTaxInformation
+}
+
+type SAFTTaxInformation struct {
+	TaxType       string
+	TaxCode       string
+	TaxPercentage string
+	TaxBase       string
+	TaxAmount     SAFTAmount
+}
+
+type SAFTPayments struct {
+	NumberOfEntries int
+	TotalDebit      string
+	TotalCredit     string
+	Payments        []SAFTPayment `xml:"Payment"`
+}
+
+type SAFTPayment struct {
+	PaymentRefNo    string
+	Period          int
+	PeriodYear      int
+	TransactionDate string
+	PaymentMethod   string // UNCL4461 code: 10 cash, 42 bank transfer, 48 card
+	Description     string
+	Lines           []SAFTPaymentLine `xml:"PaymentLine"`
+}
+
+type SAFTPaymentLine struct {
+	LineNumber           int
+	AccountID            string
+	CustomerID           string `xml:"CustomerID,omitempty"`
+	SupplierID           string `xml:"SupplierID,omitempty"`
+	DebitCreditIndicator string
+	PaymentLineAmount    SAFTAmount
+}
+
+// saftLedgerAccounts are the ledger accounts reported in the general ledger, with their
+// description and type in the Romanian chart of accounts
+var saftLedgerAccounts = []struct {
+	Account     models.LedgerAccount
+	Description string
+	Type        string
+}{
+	{models.LedgerAccountClientReceivables, "Clienti", "Activ"},
+	{models.LedgerAccountCleanerPayables, "Furnizori", "Pasiv"},
+	{models.LedgerAccountPlatformRevenue, "Venituri din servicii prestate", "Pasiv"},
+	{models.LedgerAccountRefunds, "Reduceri comerciale acordate", "Activ"},
+	{models.LedgerAccountVATPayable, "TVA colectata", "Pasiv"},
+	{models.LedgerAccountCash, "Conturi la banci in lei", "Activ"},
+	{models.LedgerAccountClientCredits, "Clienti - creditori", "Pasiv"},
+}
+
+// saftXML generates the SAF-T (D406) declaration of the period: the general ledger from the
+// double-entry ledger, and the invoices, credit notes, payments and payouts as source documents
+func (s *AccountingExportService) saftXML(period *accountingPeriod) ([]byte, error) {
+	last := period.To.Add(-time.Nanosecond)
+	doc := SAFTAuditFile{
+		XMLNS: saftNamespace,
+		Header: SAFTHeader{
+			AuditFileVersion:     saftVersion,
+			AuditFileCountry:     "RO",
+			AuditFileDateCreated: time.Now().Format("2006-01-02"),
+			SoftwareCompanyName:  s.company.LegalName,
+			SoftwareID:           s.company.TradeName,
+			SoftwareVersion:      "1.0",
+			Company: SAFTCompany{
+				RegistrationNumber: utils.NormalizeCUI(s.company.CUI),
+				Name:               s.company.LegalName,
+				Address: saftAddress(&accountingPartner{
+					StreetAddress: s.company.Address.Street,
+					City:          s.company.Address.City,
+					County:        s.company.Address.County,
+					PostalCode:    s.company.Address.PostalCode,
+					Country:       s.company.Address.Country,
+				}),
+				IBANNumber: s.company.Bank.IBAN,
+			},
+			DefaultCurrencyCode: "RON",
+			SelectionCriteria: SAFTSelectionCriteria{
+				PeriodStart:     int(period.From.Month()),
+				PeriodStartYear: period.From.Year(),
+				PeriodEnd:       int(last.Month()),
+				PeriodEndYear:   last.Year(),
+			},
+			HeaderComment:           "L",
+			SegmentIndex:            1,
+			TotalSegmentsInsequence: 1,
+			TaxAccountingBasis:      "A",
+		},
+	}
+
+	if err := s.saftAccounts(&doc, period); err != nil {
+		return nil, err
+	}
+	if err := s.saftGeneralLedger(&doc, period); err != nil {
+		return nil, err
+	}
+	if err := s.saftSalesInvoices(&doc, period); err != nil {
+		return nil, err
+	}
+	s.saftPayments(&doc, period)
+	if err := s.saftPartners(&doc, period, last); err != nil {
+		return nil, err
+	}
+
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SAF-T: %w", err)
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+// saftAccounts lists the general ledger accounts with their balances at the start and end of the period
+func (s *AccountingExportService) saftAccounts(doc *SAFTAuditFile, period *accountingPeriod) error {
+	opening, err := s.ledgerRepo.GetTrialBalance(period.From.Add(-time.Nanosecond))
+	if err != nil {
+		return fmt.Errorf("failed to get opening balances: %w", err)
+	}
+	closing, err := s.ledgerRepo.GetTrialBalance(period.To.Add(-time.Nanosecond))
+	if err != nil {
+		return fmt.Errorf("failed to get closing balances: %w", err)
+	}
+	net := func(balances []*models.LedgerAccountBalance, account models.LedgerAccount) float64 {
+		for _, balance := range balances {
+			if balance.Account == account {
+				return balance.TotalDebit - balance.TotalCredit
+			}
+		}
+		return 0
+	}
+
+	for _, a := range saftLedgerAccounts {
+		code := s.account(a.Account)
+		account := SAFTAccount{
+			AccountID:          code,
+			AccountDescription: a.Description,
+			StandardAccountID:  code,
+			AccountType:        a.Type,
+		}
+		account.OpeningDebitBalance, account.OpeningCreditBalance = saftBalance(net(opening, a.Account))
+		account.ClosingDebitBalance, account.ClosingCreditBalance = saftBalance(net(closing, a.Account))
+		doc.MasterFiles.Accounts = append(doc.MasterFiles.Accounts, account)
+	}
+	return nil
+}
+
+// saftGeneralLedger reports the period's ledger transactions, in one journal per transaction type
+func (s *AccountingExportService) saftGeneralLedger(doc *SAFTAuditFile, period *accountingPeriod) error {
+	txns, entries, err := s.ledgerRepo.GetTransactionsBetween(period.From, period.To)
+	if err != nil {
+		return fmt.Errorf("failed to get ledger transactions: %w", err)
+	}
+
+	var totalDebit, totalCredit float64
+	journals := make(map[models.LedgerTransactionType]*SAFTJournal)
+	var order []models.LedgerTransactionType
+	for _, txn := range txns {
+		journal, ok := journals[txn.TransactionType]
+		if !ok {
+			journal = &SAFTJournal{
+				JournalID:   string(txn.TransactionType),
+				Description: strings.ReplaceAll(string(txn.TransactionType), "_", " "),
+				Type:        string(txn.TransactionType),
+			}
+			journals[txn.TransactionType] = journal
+			order = append(order, txn.TransactionType)
+		}
+
+		transaction := SAFTTransaction{
+			TransactionID:   txn.ID,
+			Period:          int(txn.CreatedAt.Month()),
+			PeriodYear:      txn.CreatedAt.Year(),
+			TransactionDate: txn.CreatedAt.Format("2006-01-02"),
+			Description:     txn.Description,
+			SystemEntryDate: txn.CreatedAt.Format("2006-01-02"),
+			GLPostingDate:   txn.CreatedAt.Format("2006-01-02"),
+		}
+		for _, entry := range entries[txn.ID] {
+			line := SAFTTransactionLine{
+				RecordID:    entry.ID,
+				AccountID:   s.account(entry.Account),
+				Description: txn.Description,
+			}
+			if entry.CleanerID.Valid {
+				line.SupplierID = entry.CleanerID.String
+				if _, ok := period.Suppliers[entry.CleanerID.String]; !ok {
+					supplier, err := s.cleanerSupplier(entry.CleanerID.String)
+					if err != nil {
+						return err
+					}
+					period.Suppliers[entry.CleanerID.String] = supplier
+				}
+			}
+			if entry.Debit > 0 {
+				line.DebitAmount = &SAFTAmount{Amount: formatAmount(entry.Debit)}
+				totalDebit += entry.Debit
+			} else {
+				line.CreditAmount = &SAFTAmount{Amount: formatAmount(entry.Credit)}
+				totalCredit += entry.Credit
+			}
+			transaction.Lines = append(transaction.Lines, line)
+		}
+		journal.Transactions = append(journal.Transactions, transaction)
+	}
+
+	doc.GeneralLedger = SAFTGeneralLedger{
+		NumberOfEntries: len(txns),
+		TotalDebit:      formatAmount(totalDebit),
+		TotalCredit:     formatAmount(totalCredit),
+	}
+	for _, transactionType := range order {
+		doc.GeneralLedger.Journals = append(doc.GeneralLedger.Journals, *journals[transactionType])
+	}
+	return nil
+}
+
+// saftSalesInvoices reports the period's invoices and, as type 381, its credit notes, and the
+// VAT codes they use in the tax table
+func (s *AccountingExportService) saftSalesInvoices(doc *SAFTAuditFile, period *accountingPeriod) error {
+	revenueAccount := s.account(models.LedgerAccountPlatformRevenue)
+	receivablesAccount := s.account(models.LedgerAccountClientReceivables)
+	usedRates := make(map[float64]string)
+	taxInformation := func(rate, base, amount float64) (SAFTTaxInformation, error) {
+		code, ok := usedRates[rate]
+		if !ok {
+			if code = s.saftTaxCode(rate); code == "" {
+				return SAFTTaxInformation{}, fmt.Errorf("no SAF-T tax code configured for VAT rate %.0f%%", rate*100)
+			}
+			usedRates[rate] = code
+		}
+		return SAFTTaxInformation{
+			TaxType:       saftVATTaxType,
+			TaxCode:       code,
+			TaxPercentage: fmt.Sprintf("%.2f", rate*100),
+			TaxBase:       formatAmount(base),
+			TaxAmount:     SAFTAmount{Amount: formatAmount(amount)},
+		}, nil
+	}
+
+	var totalDebit, totalCredit float64
+	invoices := &doc.SourceDocuments.SalesInvoices
+	for _, invoice := range period.Invoices {
+		if invoice.Status == models.InvoiceStatusCancelled {
+			continue
+		}
+		customer := period.Customers[invoice.ID]
+		entry := SAFTInvoice{
+			InvoiceNo:            invoice.InvoiceNumber,
+			CustomerID:           customer.ID,
+			BillingAddress:       saftAddress(customer),
+			AccountID:            receivablesAccount,
+			Period:               int(invoice.IssueDate.Month()),
+			PeriodYear:           invoice.IssueDate.Year(),
+			InvoiceDate:          invoice.IssueDate.Format("2006-01-02"),
+			InvoiceType:          "380",
+			SelfBillingIndicator: "0",
+			GLPostingDate:        invoice.IssueDate.Format("2006-01-02"),
+			NetTotal:             formatAmount(invoice.Subtotal),
+			GrossTotal:           formatAmount(invoice.TotalAmount),
+		}
+
+		totals := make(map[float64]*[2]float64)
+		var rates []float64
+		for _, line := range period.Lines[invoice.ID] {
+			tax, err := taxInformation(line.VATRate, line.NetAmount, line.VATAmount)
+			if err != nil {
+				return fmt.Errorf("invoice %s: %w", invoice.InvoiceNumber, err)
+			}
+			unitPrice := 0.0
+			if line.Quantity != 0 {
+				unitPrice = line.NetAmount / line.Quantity
+			}
+			entry.Lines = append(entry.Lines, SAFTInvoiceLine{
+				LineNumber:           line.LineNumber,
+				AccountID:            revenueAccount,
+				Quantity:             fmt.Sprintf("%.2f", line.Quantity),
+				InvoiceUOM:           line.UnitCode,
+				UnitPrice:            fmt.Sprintf("%.4f", unitPrice),
+				TaxPointDate:         invoice.IssueDate.Format("2006-01-02"),
+				Description:          line.Description,
+				InvoiceLineAmount:    SAFTAmount{Amount: formatAmount(line.NetAmount)},
+				DebitCreditIndicator: "C",
+				TaxInformation:       tax,
+			})
+			if totals[line.VATRate] == nil {
+				totals[line.VATRate] = &[2]float64{}
+				rates = append(rates, line.VATRate)
+			}
+			totals[line.VATRate][0] += line.NetAmount
+			totals[line.VATRate][1] += line.VATAmount
+			totalCredit += line.NetAmount
+		}
+		for _, rate := range rates {
+			tax, err := taxInformation(rate, roundToCents(totals[rate][0]), roundToCents(totals[rate][1]))
+			if err != nil {
+				return fmt.Errorf("invoice %s: %w", invoice.InvoiceNumber, err)
+			}
+			entry.TaxTotals = append(entry.TaxTotals, tax)
+		}
+		invoices.Invoices = append(invoices.Invoices, entry)
+	}
+
+	for _, note := range period.CreditNotes {
+		customer := period.Customers[note.ID]
+		rate := period.CreditRates[note.ID]
+		tax, err := taxInformation(rate, note.Subtotal, note.TaxAmount)
+		if err != nil {
+			return fmt.Errorf("credit note %s: %w", note.CreditNoteNumber, err)
+		}
+		invoices.Invoices = append(invoices.Invoices, SAFTInvoice{
+			InvoiceNo:            note.CreditNoteNumber,
+			CustomerID:           customer.ID,
+			BillingAddress:       saftAddress(customer),
+			AccountID:            receivablesAccount,
+			Period:               int(note.IssueDate.Month()),
+			PeriodYear:           note.IssueDate.Year(),
+			InvoiceDate:          note.IssueDate.Format("2006-01-02"),
+			InvoiceType:          "381",
+			SelfBillingIndicator: "0",
+			GLPostingDate:        note.IssueDate.Format("2006-01-02"),
+			Lines: []SAFTInvoiceLine{{
+				LineNumber:           1,
+				AccountID:            revenueAccount,
+				Quantity:             "1.00",
+				InvoiceUOM:           "C62",
+				UnitPrice:            fmt.Sprintf("%.4f", note.Subtotal),
+				TaxPointDate:         note.IssueDate.Format("2006-01-02"),
+				Description:          note.Reason,
+				InvoiceLineAmount:    SAFTAmount{Amount: formatAmount(note.Subtotal)},
+				DebitCreditIndicator: "D",
+				TaxInformation:       tax,
+			}},
+			TaxTotals:  []SAFTTaxInformation{tax},
+			NetTotal:   formatAmount(note.Subtotal),
+			GrossTotal: formatAmount(note.TotalAmount),
+		})
+		totalDebit += note.Subtotal
+	}
+
+	invoices.NumberOfEntries = len(invoices.Invoices)
+	invoices.TotalDebit = formatAmount(totalDebit)
+	invoices.TotalCredit = formatAmount(totalCredit)
+
+	rates := make([]float64, 0, len(usedRates))
+	for rate := range usedRates {
+		rates = append(rates, rate)
+	}
+	sort.Float64s(rates)
+	entry := SAFTTaxTableEntry{TaxType: saftVATTaxType, Description: "TVA"}
+	for _, rate := range rates {
+		entry.TaxCodes = append(entry.TaxCodes, SAFTTaxCodeDetails{
+			TaxCode:       usedRates[rate],
+			Description:   fmt.Sprintf("TVA colectata %.0f%%", rate*100),
+			TaxPercentage: fmt.Sprintf("%.2f", rate*100),
+			Country:       "RO",
+		})
+	}
+	doc.MasterFiles.TaxTable = []SAFTTaxTableEntry{entry}
+	return nil
+}
+
+// saftTaxCode returns the configured SAF-T code of a VAT rate, or "" if there is none
+func (s *AccountingExportService) saftTaxCode(rate float64) string {
+	for _, code := range s.cfg.SAFTTaxCodes {
+		if math.Abs(code.Rate-rate) < 0.0001 {
+			return code.Code
+		}
+	}
+	return ""
+}
+
+// saftPayments reports the payments collected from clients, refunds and payouts to cleaners
+func (s *AccountingExportService) saftPayments(doc *SAFTAuditFile, period *accountingPeriod) {
+	receivablesAccount := s.account(models.LedgerAccountClientReceivables)
+	payablesAccount := s.account(models.LedgerAccountCleanerPayables)
+	var totalDebit, totalCredit float64
+	payments := &doc.SourceDocuments.Payments
+
+	for _, payment := range period.Payments {
+		date, indicator, description := payment.CapturedAt.Time, "C", "Incasare rezervare "+payment.BookingID
+		if payment.PaymentType == models.PaymentTypeRefund {
+			date, indicator, description = payment.RefundedAt.Time, "D", "Restituire rezervare "+payment.BookingID
+			totalDebit += payment.Amount
+		} else {
+			totalCredit += payment.Amount
+		}
+		method := "48"
+		switch payment.Provider {
+		case models.PaymentProviderCash:
+			method = "10"
+		case models.PaymentProviderManual, models.PaymentProviderBankTransfer:
+			method = "42"
+		}
+		payments.Payments = append(payments.Payments, SAFTPayment{
+			PaymentRefNo:    paymentReference(payment),
+			Period:          int(date.Month()),
+			PeriodYear:      date.Year(),
+			TransactionDate: date.Format("2006-01-02"),
+			PaymentMethod:   method,
+			Description:     description,
+			Lines: []SAFTPaymentLine{{
+				LineNumber:           1,
+				AccountID:            receivablesAccount,
+				CustomerID:           period.Customers[payment.ID].ID,
+				DebitCreditIndicator: indicator,
+				PaymentLineAmount:    SAFTAmount{Amount: formatAmount(payment.Amount)},
+			}},
+		})
+	}
+
+	for _, payout := range period.Payouts {
+		payments.Payments = append(payments.Payments, SAFTPayment{
+			PaymentRefNo:    payoutReference(payout),
+			Period:          int(payout.PaidAt.Time.Month()),
+			PeriodYear:      payout.PaidAt.Time.Year(),
+			TransactionDate: payout.PaidAt.Time.Format("2006-01-02"),
+			PaymentMethod:   "42",
+			Description: fmt.Sprintf("Plata castiguri %s - %s",
+				formatRODate(payout.PeriodStart), formatRODate(payout.PeriodEnd)),
+			Lines: []SAFTPaymentLine{{
+				LineNumber:           1,
+				AccountID:            payablesAccount,
+				SupplierID:           payout.CleanerID,
+				DebitCreditIndicator: "D",
+				PaymentLineAmount:    SAFTAmount{Amount: formatAmount(payout.NetAmount)},
+			}},
+		})
+		totalDebit += payout.NetAmount
+	}
+
+	payments.NumberOfEntries = len(payments.Payments)
+	payments.TotalDebit = formatAmount(totalDebit)
+	payments.TotalCredit = formatAmount(totalCredit)
+}
+
+// saftPartners lists the customers and suppliers the period's documents refer to. Supplier
+// balances come from the cleaner sub-ledger. Clients pay when they book, so customer balances
+// are the period's own invoicing less collections, opening at zero.
+func (s *AccountingExportService) saftPartners(doc *SAFTAuditFile, period *accountingPeriod, last time.Time) error {
+	receivablesAccount := s.account(models.LedgerAccountClientReceivables)
+	payablesAccount := s.account(models.LedgerAccountCleanerPayables)
+
+	customers := make(map[string]*accountingPartner)
+	balances := make(map[string]float64)
+	for _, invoice := range period.Invoices {
+		if invoice.Status == models.InvoiceStatusCancelled {
+			continue
+		}
+		customer := period.Customers[invoice.ID]
+		customers[customer.ID] = customer
+		balances[customer.ID] += invoice.TotalAmount
+	}
+	for _, note := range period.CreditNotes {
+		customer := period.Customers[note.ID]
+		customers[customer.ID] = customer
+		balances[customer.ID] -= note.TotalAmount
+	}
+	for _, payment := range period.Payments {
+		customer := period.Customers[payment.ID]
+		customers[customer.ID] = customer
+		if payment.PaymentType == models.PaymentTypeRefund {
+			balances[customer.ID] += payment.Amount
+		} else {
+			balances[customer.ID] -= payment.Amount
+		}
+	}
+
+	for _, id := range sortedPartnerIDs(customers) {
+		partner := SAFTPartner{
+			CompanyStructure:    saftCompanyStructure(customers[id]),
+			CustomerID:          id,
+			AccountID:           receivablesAccount,
+			OpeningDebitBalance: formatAmount(0),
+		}
+		partner.ClosingDebitBalance, partner.ClosingCreditBalance = saftBalance(roundToCents(balances[id]))
+		doc.MasterFiles.Customers = append(doc.MasterFiles.Customers, partner)
+	}
+
+	for _, id := range sortedPartnerIDs(period.Suppliers) {
+		opening, err := s.ledgerRepo.GetCleanerBalance(id, period.From.Add(-time.Nanosecond))
+		if err != nil {
+			return fmt.Errorf("failed to get opening balance of cleaner %s: %w", id, err)
+		}
+		closing, err := s.ledgerRepo.GetCleanerBalance(id, last)
+		if err != nil {
+			return fmt.Errorf("failed to get closing balance of cleaner %s: %w", id, err)
+		}
+		partner := SAFTPartner{
+			CompanyStructure: saftCompanyStructure(period.Suppliers[id]),
+			SupplierID:       id,
+			AccountID:        payablesAccount,
+		}
+		// Cleaner balances are what the platform owes, i.e. credit balances
+		partner.OpeningDebitBalance, partner.OpeningCreditBalance = saftBalance(-opening)
+		partner.ClosingDebitBalance, partner.ClosingCreditBalance = saftBalance(-closing)
+		doc.MasterFiles.Suppliers = append(doc.MasterFiles.Suppliers, partner)
+	}
+	return nil
+}
+
+func sortedPartnerIDs(partners map[string]*accountingPartner) []string {
+	ids := make([]string, 0, len(partners))
+	for id := range partners {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func saftCompanyStructure(partner *accountingPartner) SAFTCompanyStructure {
+	registration := saftAnonymousTIN
+	if partner.CUI != "" {
+		registration = partner.CUI
+	}
+	return SAFTCompanyStructure{
+		RegistrationNumber: registration,
+		Name:               partner.Name,
+		Address:            saftAddress(partner),
+	}
+}
+
+func saftAddress(partner *accountingPartner) SAFTAddress {
+	address := SAFTAddress{
+		StreetName: partner.StreetAddress,
+		City:       partner.City,
+		PostalCode: partner.PostalCode,
+		Country:    partner.Country,
+	}
+	if code, ok := utils.RomanianCountyCode(partner.County); ok {
+		address.Region = code
+	}
+	if address.Country == "" {
+		address.Country = "RO"
+	}
+	return address
+}
+
+// saftBalance splits a net debit balance into the debit or credit balance element SAF-T reports
+func saftBalance(net float64) (debit, credit string) {
+	if net < 0 {
+		return "", formatAmount(-net)
+	}
+	return formatAmount(net), ""
+}
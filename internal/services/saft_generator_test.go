@@ -0,0 +1,71 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func testSAFTPeriod(rate float64) *accountingPeriod {
+	issued := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+	invoice := &models.Invoice{
+		ID:            "invoice-1",
+		InvoiceNumber: "INV-2025-0042",
+		IssueDate:     issued,
+		Subtotal:      100,
+		TaxAmount:     100 * rate,
+		TotalAmount:   100 + 100*rate,
+	}
+	return &accountingPeriod{
+		From:     time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		Invoices: []*models.Invoice{invoice},
+		Lines: map[string][]*models.InvoiceLine{invoice.ID: {{
+			LineNumber:  1,
+			Description: "Curatenie standard",
+			Quantity:    1,
+			UnitCode:    "C62",
+			VATRate:     rate,
+			NetAmount:   100,
+			VATAmount:   100 * rate,
+		}}},
+		Customers: map[string]*accountingPartner{invoice.ID: {ID: "client-1", Name: "Ion Popescu"}},
+	}
+}
+
+func TestSAFTSalesInvoicesTaxCodes(t *testing.T) {
+	s := &AccountingExportService{cfg: &config.AccountingConfig{SAFTTaxCodes: []config.SAFTTaxCode{
+		{Rate: 0.21, Code: "310344"},
+		{Rate: 0.19, Code: "310309"},
+	}}}
+
+	doc := &SAFTAuditFile{}
+	if err := s.saftSalesInvoices(doc, testSAFTPeriod(0.21)); err != nil {
+		t.Fatalf("saftSalesInvoices() returned error: %v", err)
+	}
+	codes := doc.MasterFiles.TaxTable[0].TaxCodes
+	if len(codes) != 1 || codes[0].TaxCode != "310344" {
+		t.Errorf("tax table = %+v, want the 21%% code 310344 only", codes)
+	}
+	line := doc.SourceDocuments.SalesInvoices.Invoices[0].Lines[0]
+	if line.TaxInformation.TaxCode != "310344" {
+		t.Errorf("line tax code = %q, want 310344", line.TaxInformation.TaxCode)
+	}
+}
+
+func TestSAFTSalesInvoicesUnmappedRate(t *testing.T) {
+	s := &AccountingExportService{cfg: &config.AccountingConfig{SAFTTaxCodes: []config.SAFTTaxCode{
+		{Rate: 0.19, Code: "310309"},
+	}}}
+
+	err := s.saftSalesInvoices(&SAFTAuditFile{}, testSAFTPeriod(0.11))
+	if err == nil {
+		t.Fatal("saftSalesInvoices() wrote an 11% invoice without a SAF-T tax code")
+	}
+	if !strings.Contains(err.Error(), "INV-2025-0042") || !strings.Contains(err.Error(), "11%") {
+		t.Errorf("error %q should name the invoice and the rate", err)
+	}
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// SelfBillingService issues invoices on behalf of PFA cleaners who opted into self-billing
+// (autofacturare): when their payout is generated, CleanBuddy issues the cleaner's invoice to
+// itself in the cleaner's own numbering series and files it with ANAF
+type SelfBillingService struct {
+	repo         *models.SelfBillingRepository
+	cleanerRepo  *models.CleanerRepository
+	xmlGenerator *XMLGenerator
+	pdfGenerator *PDFGenerator
+	anafClient   *ANAFClient
+	anafConfig   *config.ANAFConfig
+	config       *config.CompanyConfig
+}
+
+// NewSelfBillingService creates a new self-billing service
+func NewSelfBillingService(db *sql.DB, companyConfig *config.CompanyConfig, anafConfig *config.ANAFConfig) *SelfBillingService {
+	return &SelfBillingService{
+		repo:         models.NewSelfBillingRepository(db),
+		cleanerRepo:  models.NewCleanerRepository(db),
+		xmlGenerator: NewXMLGenerator("./invoices/xml", companyConfig),
+		pdfGenerator: NewPDFGenerator("./invoices/pdf"),
+		anafClient:   NewANAFClient(anafConfig, companyConfig),
+		anafConfig:   anafConfig,
+		config:       companyConfig,
+	}
+}
+
+// SelfBillingSettingsInput is the PFA's legal identity the invoices are issued under
+type SelfBillingSettingsInput struct {
+	LegalName          string
+	CUI                string
+	RegistrationNumber *string
+	Address            string
+	City               string
+	County             string
+	VATPayer           bool
+	AcceptAgreement    bool
+}
+
+// EnableSelfBilling opts a cleaner into self-billing. Accepting the self-billing agreement is
+// mandatory: the invoices are legally the cleaner's.
+func (s *SelfBillingService) EnableSelfBilling(userID string, input SelfBillingSettingsInput) (*models.CleanerSelfBilling, error) {
+	if !input.AcceptAgreement {
+		return nil, fmt.Errorf("the self-billing agreement must be accepted")
+	}
+	if err := utils.ValidateCUI(input.CUI); err != nil {
+		return nil, err
+	}
+	for field, value := range map[string]string{
+		"legal name": input.LegalName,
+		"address":    input.Address,
+		"city":       input.City,
+		"county":     input.County,
+	} {
+		if strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("%s is required", field)
+		}
+	}
+
+	cleaner, err := s.getCleaner(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cui := utils.NormalizeCUI(input.CUI)
+	if input.VATPayer {
+		cui = "RO" + cui
+	}
+
+	settings := &models.CleanerSelfBilling{
+		CleanerID: cleaner.ID,
+		Enabled:   true,
+		LegalName: strings.TrimSpace(input.LegalName),
+		CUI:       cui,
+		Address:   strings.TrimSpace(input.Address),
+		City:      strings.TrimSpace(input.City),
+		County:    strings.TrimSpace(input.County),
+		VATPayer:  input.VATPayer,
+		AgreedAt:  time.Now(),
+	}
+	if input.RegistrationNumber != nil && strings.TrimSpace(*input.RegistrationNumber) != "" {
+		settings.RegistrationNumber = sql.NullString{String: strings.TrimSpace(*input.RegistrationNumber), Valid: true}
+	}
+
+	if err := s.repo.SaveSettings(settings); err != nil {
+		return nil, fmt.Errorf("failed to save self-billing settings: %w", err)
+	}
+	return settings, nil
+}
+
+// DisableSelfBilling stops self-billing for future payouts; invoices already issued stay valid
+func (s *SelfBillingService) DisableSelfBilling(userID string) (*models.CleanerSelfBilling, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, fmt.Errorf("self-billing is not enabled")
+	}
+
+	if err := s.repo.SetEnabled(settings.CleanerID, false); err != nil {
+		return nil, fmt.Errorf("failed to disable self-billing: %w", err)
+	}
+	settings.Enabled = false
+	return settings, nil
+}
+
+// GetSettings returns a cleaner's self-billing settings, or nil if they never opted in
+func (s *SelfBillingService) GetSettings(userID string) (*models.CleanerSelfBilling, error) {
+	cleaner, err := s.getCleaner(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetSettings(cleaner.ID)
+}
+
+// GetInvoicesForUser returns the invoices issued on behalf of a cleaner, newest first
+func (s *SelfBillingService) GetInvoicesForUser(userID string, limit, offset int) ([]*models.SelfBilledInvoice, error) {
+	cleaner, err := s.getCleaner(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetInvoicesByCleanerID(cleaner.ID, limit, offset)
+}
+
+// GetInvoiceForPayout returns the invoice issued for a payout, if any
+func (s *SelfBillingService) GetInvoiceForPayout(payoutID string) (*models.SelfBilledInvoice, error) {
+	return s.repo.GetInvoiceByPayoutID(payoutID)
+}
+
+// IssueForPayout issues the cleaner's invoice for the bookings, tips and adjustments of a payout.
+// It returns nil without an error when the cleaner has not opted in or there is nothing to invoice.
+func (s *SelfBillingService) IssueForPayout(payout *models.Payout, lineItems []*models.PayoutLineItem, payeeIBAN string) (*models.SelfBilledInvoice, error) {
+	existing, err := s.repo.GetInvoiceByPayoutID(payout.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing self-billed invoice: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	cleaner, err := s.cleanerRepo.GetByUserID(payout.CleanerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return nil, nil
+	}
+	settings, err := s.repo.GetSettings(cleaner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get self-billing settings: %w", err)
+	}
+	if settings == nil || !settings.Enabled {
+		return nil, nil
+	}
+
+	lines, subtotal, taxAmount := s.buildLines(lineItems, settings.VATPayer)
+	if len(lines) == 0 || subtotal <= 0 {
+		return nil, nil
+	}
+
+	invoice := &models.SelfBilledInvoice{
+		PayoutID:                   payout.ID,
+		CleanerID:                  cleaner.ID,
+		IssueDate:                  time.Now(),
+		SupplierName:               settings.LegalName,
+		SupplierCUI:                settings.CUI,
+		SupplierRegistrationNumber: settings.RegistrationNumber,
+		SupplierAddress:            fmt.Sprintf("%s, %s, %s", settings.Address, settings.City, settings.County),
+		SupplierVATPayer:           settings.VATPayer,
+		Subtotal:                   subtotal,
+		TaxAmount:                  taxAmount,
+		TotalAmount:                roundToCents(subtotal + taxAmount),
+		Currency:                   "RON",
+	}
+	if err := s.repo.CreateInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	pdfPath, err := s.pdfGenerator.GenerateSelfBilledInvoicePDF(invoice, lines)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate PDF for self-billed invoice %s: %v\n", invoice.InvoiceNumber, err)
+	} else {
+		invoice.PdfURL = sql.NullString{String: pdfPath, Valid: true}
+	}
+
+	xmlPath, err := s.xmlGenerator.GenerateSelfBilledInvoiceXML(invoice, lines, payeeIBAN)
+	if err != nil {
+		fmt.Printf("Warning: failed to generate XML for self-billed invoice %s: %v\n", invoice.InvoiceNumber, err)
+	} else {
+		invoice.XmlURL = sql.NullString{String: xmlPath, Valid: true}
+	}
+
+	if invoice.PdfURL.Valid || invoice.XmlURL.Valid {
+		if err := s.repo.UpdateFiles(invoice); err != nil {
+			fmt.Printf("Warning: failed to update self-billed invoice %s with file paths: %v\n", invoice.InvoiceNumber, err)
+		}
+	}
+
+	if s.anafConfig.Enabled && invoice.XmlURL.Valid {
+		go func() {
+			if err := s.SubmitToANAF(invoice.ID); err != nil {
+				fmt.Printf("Warning: failed to submit self-billed invoice %s to ANAF: %v\n", invoice.InvoiceNumber, err)
+			}
+		}()
+	}
+
+	return invoice, nil
+}
+
+// SubmitToANAF uploads a self-billed invoice to ANAF flagged as autofactura
+func (s *SelfBillingService) SubmitToANAF(invoiceID string) error {
+	invoice, err := s.repo.GetInvoiceByID(invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get self-billed invoice: %w", err)
+	}
+	if invoice == nil {
+		return fmt.Errorf("self-billed invoice not found")
+	}
+	if invoice.ANAFStatus == models.ANAFStatusAccepted || invoice.ANAFStatus == models.ANAFStatusProcessing {
+		return fmt.Errorf("invoice already submitted to ANAF")
+	}
+	if !invoice.XmlURL.Valid {
+		return fmt.Errorf("invoice has no XML to submit")
+	}
+
+	xmlContent, err := s.xmlGenerator.ReadXML(invoice.XmlURL.String)
+	if err != nil {
+		return fmt.Errorf("failed to read XML: %w", err)
+	}
+
+	// Don't upload what ANAF would reject: store the violations as the document's errors instead
+	if validationErrors := ValidateUBL(xmlContent); len(validationErrors) > 0 {
+		invoice.ANAFStatus = models.ANAFStatusRejected
+		invoice.ANAFErrors = validationErrors
+		if err := s.repo.UpdateANAFStatus(invoice); err != nil {
+			return fmt.Errorf("failed to update ANAF status after validation: %w", err)
+		}
+		return fmt.Errorf("self-billed invoice %s failed e-Factura validation: %s", invoice.InvoiceNumber, summarizeValidationErrors(validationErrors))
+	}
+
+	resp, err := s.anafClient.UploadSelfBilledInvoice(context.Background(), xmlContent, invoice.InvoiceNumber)
+	if err != nil {
+		invoice.ANAFStatus = models.ANAFStatusFailed
+		invoice.ANAFRetryCount++
+		if updateErr := s.repo.UpdateANAFStatus(invoice); updateErr != nil {
+			return fmt.Errorf("failed to update ANAF status after error: %w", updateErr)
+		}
+		return fmt.Errorf("failed to upload invoice to ANAF: %w", err)
+	}
+
+	invoice.ANAFUploadIndex = sql.NullString{String: resp.UploadIndex, Valid: true}
+	invoice.ANAFSubmittedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	switch {
+	case len(resp.Errors) > 0:
+		invoice.ANAFStatus = models.ANAFStatusRejected
+		invoice.ANAFErrors = make([]models.ANAFError, len(resp.Errors))
+		for i, e := range resp.Errors {
+			invoice.ANAFErrors[i] = models.ANAFError{Code: e.Code, Message: e.Message, Field: e.Field}
+		}
+	case resp.Status == "accepted":
+		invoice.ANAFStatus = models.ANAFStatusAccepted
+		invoice.ANAFProcessedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	default:
+		invoice.ANAFStatus = models.ANAFStatusProcessing
+	}
+
+	if err := s.repo.UpdateANAFStatus(invoice); err != nil {
+		return fmt.Errorf("failed to update self-billed invoice ANAF status: %w", err)
+	}
+	return nil
+}
+
+// ProcessPendingANAFSubmissions submits self-billed invoices past the submission delay and retries failed
+// ones once their backoff has passed
+func (s *SelfBillingService) ProcessPendingANAFSubmissions(batchSize int) error {
+	submissionDelay, retryDelay, maxRetries := anafSchedule(s.anafConfig)
+	invoices, err := s.repo.GetPendingANAFSubmission(submissionDelay, retryDelay, maxRetries, batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending self-billed invoices: %w", err)
+	}
+
+	for _, invoice := range invoices {
+		if err := s.SubmitToANAF(invoice.ID); err != nil {
+			fmt.Printf("Failed to submit self-billed invoice %s to ANAF: %v\n", invoice.InvoiceNumber, err)
+		}
+	}
+	return nil
+}
+
+// buildLines turns the payout line items into invoice lines at the VAT rate in force today
+func (s *SelfBillingService) buildLines(lineItems []*models.PayoutLineItem, vatPayer bool) ([]InvoiceDocumentLine, float64, float64) {
+	vatRate := 0.0
+	if vatPayer {
+		vatRate = s.xmlGenerator.vatRate(time.Now())
+	}
+	return selfBilledLines(lineItems, vatRate)
+}
+
+// selfBilledLines bills every item of a payout. A VAT-paying PFA's earnings are taken as
+// VAT-inclusive. Cash bookings are billed at the cleaner's full share, since the cash kept on
+// site settles part of the invoice; clawbacks and carried-forward balances reduce the total.
+func selfBilledLines(lineItems []*models.PayoutLineItem, vatRate float64) ([]InvoiceDocumentLine, float64, float64) {
+	var lines []InvoiceDocumentLine
+	var subtotal, taxAmount float64
+	for _, item := range lineItems {
+		amount := item.CleanerEarnings
+		var description string
+		switch item.ItemType {
+		case models.PayoutLineItemTypeBooking:
+			description = fmt.Sprintf("Servicii de curatenie %s (rezervare %s)",
+				item.BookingDate.Format("02.01.2006"), shortID(item.BookingID))
+		case models.PayoutLineItemTypeCash:
+			amount = roundToCents(item.BookingAmount - item.PlatformFee)
+			description = fmt.Sprintf("Servicii de curatenie %s (rezervare %s, incasat numerar)",
+				item.BookingDate.Format("02.01.2006"), shortID(item.BookingID))
+		case models.PayoutLineItemTypeTip:
+			description = fmt.Sprintf("Bacsis %s (rezervare %s)",
+				item.BookingDate.Format("02.01.2006"), shortID(item.BookingID))
+		case models.PayoutLineItemTypeCarryForward:
+			description = "Sold reportat: " + item.Description.String
+		default:
+			description = "Ajustare: " + item.Description.String
+		}
+		if amount == 0 {
+			continue
+		}
+
+		net := roundToCents(amount / (1 + vatRate))
+		tax := roundToCents(amount - net)
+		lines = append(lines, InvoiceDocumentLine{
+			Description: description,
+			NetAmount:   net,
+			TaxAmount:   tax,
+		})
+		subtotal += net
+		taxAmount += tax
+	}
+	return lines, roundToCents(subtotal), roundToCents(taxAmount)
+}
+
+func (s *SelfBillingService) getCleaner(userID string) (*models.Cleaner, error) {
+	cleaner, err := s.cleanerRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cleaner: %w", err)
+	}
+	if cleaner == nil {
+		return nil, fmt.Errorf("cleaner profile not found")
+	}
+	return cleaner, nil
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return strings.ToUpper(id)
+}
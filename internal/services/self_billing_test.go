@@ -0,0 +1,115 @@
+package services
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+func TestSelfBilledLines(t *testing.T) {
+	date := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	items := []*models.PayoutLineItem{
+		{ItemType: models.PayoutLineItemTypeBooking, BookingID: "booking-1", BookingDate: date, BookingAmount: 200, PlatformFee: 40, CleanerEarnings: 160},
+		// 150 collected in cash against a 120 share: the payout takes the 30 fee back
+		{ItemType: models.PayoutLineItemTypeCash, BookingID: "booking-2", BookingDate: date, BookingAmount: 150, PlatformFee: 30, CleanerEarnings: -30},
+		{ItemType: models.PayoutLineItemTypeTip, BookingID: "booking-1", BookingDate: date, BookingAmount: 20, CleanerEarnings: 20},
+		{ItemType: models.PayoutLineItemTypeAdjustment, Description: sql.NullString{String: "Damage clawback", Valid: true}, CleanerEarnings: -50},
+		{ItemType: models.PayoutLineItemTypeAdjustment, Description: sql.NullString{String: "Zero correction", Valid: true}},
+	}
+
+	t.Run("outside the VAT system", func(t *testing.T) {
+		lines, subtotal, tax := selfBilledLines(items, 0)
+		if len(lines) != 4 {
+			t.Fatalf("got %d lines, want 4 (zero amounts skipped): %+v", len(lines), lines)
+		}
+		want := []float64{160, 120, 20, -50}
+		for i, line := range lines {
+			if line.NetAmount != want[i] || line.TaxAmount != 0 {
+				t.Errorf("line %d (%s) = %.2f + %.2f VAT, want %.2f", i, line.Description, line.NetAmount, line.TaxAmount, want[i])
+			}
+		}
+		if subtotal != 250 || tax != 0 {
+			t.Errorf("subtotal %.2f and tax %.2f, want 250.00 and 0", subtotal, tax)
+		}
+	})
+
+	t.Run("VAT payer", func(t *testing.T) {
+		_, subtotal, tax := selfBilledLines(items, 0.21)
+		if got := roundToCents(subtotal + tax); got != 250 {
+			t.Errorf("total = %.2f, want the VAT-inclusive 250.00", got)
+		}
+		if subtotal != 206.61 {
+			t.Errorf("subtotal = %.2f, want 206.61 (rounded per line)", subtotal)
+		}
+	})
+}
+
+// testXMLGenerator returns an XML generator writing to a temporary directory, for a VAT-registered
+// CleanBuddy
+func testXMLGenerator(t *testing.T) *XMLGenerator {
+	company := testLedgerService().cfg.Company
+	company.LegalName = "CleanBuddy SRL"
+	company.CUI = "RO12345674"
+	company.RegistrationNumber = "J40/1234/2025"
+	company.Address = config.CompanyAddress{Street: "Str. Exemplu, Nr. 1", City: "București", County: "București", PostalCode: "010101", Country: "RO"}
+	return NewXMLGenerator(t.TempDir(), &company)
+}
+
+// assertValidUBL fails the test with every e-Factura rule the XML file at path breaks
+func assertValidUBL(t *testing.T, path string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read generated XML: %v", err)
+	}
+	for _, e := range ValidateUBL(content) {
+		t.Errorf("[%s] %s: %s", e.Code, e.Field, e.Message)
+	}
+}
+
+func TestGenerateSelfBilledInvoiceXML(t *testing.T) {
+	date := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	items := []*models.PayoutLineItem{
+		{ItemType: models.PayoutLineItemTypeBooking, BookingID: "booking-1", BookingDate: date, BookingAmount: 200, PlatformFee: 40, CleanerEarnings: 160},
+		{ItemType: models.PayoutLineItemTypeTip, BookingID: "booking-1", BookingDate: date, BookingAmount: 20, CleanerEarnings: 20},
+		{ItemType: models.PayoutLineItemTypeAdjustment, Description: sql.NullString{String: "Damage clawback", Valid: true}, CleanerEarnings: -50},
+	}
+
+	for _, vatPayer := range []bool{false, true} {
+		g := testXMLGenerator(t)
+		rate := 0.0
+		if vatPayer {
+			rate = g.vatRate(date)
+		}
+		lines, subtotal, tax := selfBilledLines(items, rate)
+		invoice := &models.SelfBilledInvoice{
+			InvoiceNumber:    "AFX00001-0001",
+			IssueDate:        date,
+			SupplierName:     "Popescu Ion PFA",
+			SupplierCUI:      "18547290",
+			SupplierAddress:  "Str. Florilor 5, Bl. A2, Cluj-Napoca, Cluj",
+			SupplierVATPayer: vatPayer,
+			Subtotal:         subtotal,
+			TaxAmount:        tax,
+			TotalAmount:      roundToCents(subtotal + tax),
+			Currency:         "RON",
+		}
+
+		path, err := g.GenerateSelfBilledInvoiceXML(invoice, lines, "RO49AAAA1B31007593840000")
+		if err != nil {
+			t.Fatalf("GenerateSelfBilledInvoiceXML() returned error: %v", err)
+		}
+		assertValidUBL(t, path)
+	}
+}
+
+func TestSplitPartyAddress(t *testing.T) {
+	street, city, county := splitPartyAddress("Str. Florilor 5, Bl. A2, Cluj-Napoca, Cluj")
+	if street != "Str. Florilor 5, Bl. A2" || city != "Cluj-Napoca" || county != "Cluj" {
+		t.Errorf("splitPartyAddress() = %q, %q, %q", street, city, county)
+	}
+}
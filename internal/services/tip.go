@@ -0,0 +1,137 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// TipService handles client tips for completed bookings
+type TipService struct {
+	tipRepo        *models.TipRepository
+	bookingRepo    *models.BookingRepository
+	cleanerRepo    *models.CleanerRepository
+	paymentService *PaymentService
+	ledgerService  *LedgerService
+	cfg            *config.Config
+}
+
+// NewTipService creates a new tip service
+func NewTipService(db *sql.DB, paymentService *PaymentService) *TipService {
+	return &TipService{
+		tipRepo:        models.NewTipRepository(db),
+		bookingRepo:    models.NewBookingRepository(db),
+		cleanerRepo:    models.NewCleanerRepository(db),
+		paymentService: paymentService,
+		cfg:            config.Get(),
+	}
+}
+
+// SetLedgerService sets the ledger service used to record tips
+func (s *TipService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// TipCleaner charges the client a tip for a completed booking and credits it in full to the cleaner.
+// Tips are allowed once per booking, within booking.tip_window_days of completion.
+func (s *TipService) TipCleaner(bookingID, clientID string, amount float64, provider models.PaymentProvider, useSavedCard bool) (*models.Tip, error) {
+	amount = roundToCents(amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("tip amount must be positive")
+	}
+
+	booking, err := s.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, fmt.Errorf("booking not found")
+	}
+	if booking.ClientID != clientID {
+		return nil, fmt.Errorf("booking does not belong to user")
+	}
+	if booking.Status != models.BookingStatusCompleted || !booking.CompletedAt.Valid || !booking.CleanerID.Valid {
+		return nil, fmt.Errorf("only completed bookings can be tipped")
+	}
+
+	windowDays := s.cfg.Booking.TipWindowDays
+	if windowDays <= 0 {
+		return nil, fmt.Errorf("tipping is not available")
+	}
+	if time.Now().After(booking.CompletedAt.Time.AddDate(0, 0, windowDays)) {
+		return nil, fmt.Errorf("tips can only be left within %d days of completion", windowDays)
+	}
+
+	existing, err := s.tipRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing tip: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("this booking has already been tipped")
+	}
+
+	// Only hold the amount until the tip is stored: the one-tip-per-booking constraint can still
+	// reject it, and the client must not be charged for a tip that was not recorded
+	payment, err := s.paymentService.AuthorizeTip(booking, clientID, amount, provider, useSavedCard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge tip: %w", err)
+	}
+
+	tip := &models.Tip{
+		BookingID: booking.ID,
+		ClientID:  clientID,
+		CleanerID: booking.CleanerID.String,
+		PaymentID: payment.ID,
+		Amount:    amount,
+		Currency:  payment.Currency,
+	}
+	if err := s.tipRepo.Create(tip); err != nil {
+		s.releaseTipHold(payment)
+		return nil, fmt.Errorf("failed to save tip: %w", err)
+	}
+
+	captured, err := s.paymentService.CaptureTip(payment)
+	if err != nil {
+		if deleteErr := s.tipRepo.Delete(tip.ID); deleteErr != nil {
+			fmt.Printf("Warning: failed to remove unpaid tip %s: %v\n", tip.ID, deleteErr)
+		}
+		s.releaseTipHold(payment)
+		return nil, fmt.Errorf("failed to charge tip: %w", err)
+	}
+	payment = captured
+
+	if s.ledgerService != nil {
+		if err := s.ledgerService.PostTip(payment, tip); err != nil {
+			fmt.Printf("Warning: failed to record tip %s in ledger: %v\n", tip.ID, err)
+		}
+	}
+
+	// Tips count towards the cleaner's earnings
+	cleaner, err := s.cleanerRepo.GetByID(tip.CleanerID)
+	if err == nil && cleaner != nil {
+		cleaner.TotalEarnings += amount
+		if err := s.cleanerRepo.Update(cleaner); err != nil {
+			fmt.Printf("Warning: failed to update cleaner earnings for %s: %v\n", cleaner.ID, err)
+		}
+	}
+
+	return tip, nil
+}
+
+// releaseTipHold cancels the hold placed for a tip that was not recorded
+func (s *TipService) releaseTipHold(payment *models.Payment) {
+	if payment == nil {
+		return
+	}
+	if _, err := s.paymentService.CancelPreauthorization(payment.ID); err != nil {
+		fmt.Printf("Warning: failed to release tip hold %s: %v\n", payment.ID, err)
+	}
+}
+
+// GetTipForBooking returns the tip left on a booking, if any
+func (s *TipService) GetTipForBooking(bookingID string) (*models.Tip, error) {
+	return s.tipRepo.GetByBookingID(bookingID)
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// ciusROCustomizationID identifies documents following the Romanian CIUS of EN 16931
+const ciusROCustomizationID = "urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1"
+
+// ublDocument is the part of a UBL Invoice or CreditNote the validator checks. Tags use local
+// names only so they match whatever namespace prefixes the document uses.
+type ublDocument struct {
+	XMLName              xml.Name
+	CustomizationID      string      `xml:"CustomizationID"`
+	ID                   string      `xml:"ID"`
+	IssueDate            string      `xml:"IssueDate"`
+	InvoiceTypeCode      string      `xml:"InvoiceTypeCode"`
+	CreditNoteTypeCode   string      `xml:"CreditNoteTypeCode"`
+	DocumentCurrencyCode string      `xml:"DocumentCurrencyCode"`
+	TaxCurrencyCode      string      `xml:"TaxCurrencyCode"`
+	Supplier             ublDocParty `xml:"AccountingSupplierParty>Party"`
+	Customer             ublDocParty `xml:"AccountingCustomerParty>Party"`
+	TaxTotals            []struct {
+		TaxAmount    ublDocAmount `xml:"TaxAmount"`
+		TaxSubtotals []struct {
+			TaxableAmount ublDocAmount      `xml:"TaxableAmount"`
+			TaxAmount     ublDocAmount      `xml:"TaxAmount"`
+			TaxCategory   ublDocTaxCategory `xml:"TaxCategory"`
+		} `xml:"TaxSubtotal"`
+	} `xml:"TaxTotal"`
+	MonetaryTotal struct {
+		LineExtensionAmount *ublDocAmount `xml:"LineExtensionAmount"`
+		TaxExclusiveAmount  *ublDocAmount `xml:"TaxExclusiveAmount"`
+		TaxInclusiveAmount  *ublDocAmount `xml:"TaxInclusiveAmount"`
+		PayableAmount       *ublDocAmount `xml:"PayableAmount"`
+	} `xml:"LegalMonetaryTotal"`
+	InvoiceLines    []ublDocLine `xml:"InvoiceLine"`
+	CreditNoteLines []ublDocLine `xml:"CreditNoteLine"`
+}
+
+type ublDocParty struct {
+	Name          string `xml:"PartyName>Name"`
+	PostalAddress *struct {
+		StreetName       string `xml:"StreetName"`
+		CityName         string `xml:"CityName"`
+		CountrySubentity string `xml:"CountrySubentity"`
+		Country          string `xml:"Country>IdentificationCode"`
+	} `xml:"PostalAddress"`
+	VATID            string `xml:"PartyTaxScheme>CompanyID"`
+	RegistrationName string `xml:"PartyLegalEntity>RegistrationName"`
+	LegalID          string `xml:"PartyLegalEntity>CompanyID"`
+}
+
+type ublDocAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"currencyID,attr"`
+}
+
+type ublDocTaxCategory struct {
+	ID      string `xml:"ID"`
+	Percent string `xml:"Percent"`
+}
+
+type ublDocLine struct {
+	ID               string `xml:"ID"`
+	InvoicedQuantity *struct {
+		Value    string `xml:",chardata"`
+		UnitCode string `xml:"unitCode,attr"`
+	} `xml:"InvoicedQuantity"`
+	CreditedQuantity *struct {
+		Value    string `xml:",chardata"`
+		UnitCode string `xml:"unitCode,attr"`
+	} `xml:"CreditedQuantity"`
+	LineExtensionAmount ublDocAmount       `xml:"LineExtensionAmount"`
+	ItemName            string             `xml:"Item>Name"`
+	TaxCategory         *ublDocTaxCategory `xml:"Item>ClassifiedTaxCategory"`
+	PriceAmount         *ublDocAmount      `xml:"Price>PriceAmount"`
+}
+
+// ublValidation collects the rule violations found in a document
+type ublValidation struct {
+	errors []models.ANAFError
+}
+
+func (v *ublValidation) fail(rule, field, format string, args ...interface{}) {
+	v.errors = append(v.errors, models.ANAFError{Code: rule, Message: fmt.Sprintf(format, args...), Field: field})
+}
+
+func (v *ublValidation) required(rule, field, value, what string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(rule, field, "%s is missing", what)
+	}
+}
+
+// amount parses a monetary amount, recording a violation if it is not a number with at most
+// two decimals in the document currency
+func (v *ublValidation) amount(field string, a *ublDocAmount, currency string) float64 {
+	if a == nil || strings.TrimSpace(a.Value) == "" {
+		v.fail("AMOUNT", field, "amount is missing")
+		return 0
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(a.Value), 64)
+	if err != nil {
+		v.fail("AMOUNT", field, "%q is not a number", a.Value)
+		return 0
+	}
+	if i := strings.IndexByte(a.Value, '.'); i >= 0 && len(strings.TrimSpace(a.Value))-i-1 > 2 {
+		v.fail("BR-DEC", field, "amount %s has more than two decimals", a.Value)
+	}
+	if a.Currency != currency {
+		v.fail("BR-CL-03", field, "amount is in %q instead of the document currency %q", a.Currency, currency)
+	}
+	return value
+}
+
+// ValidateUBL checks a UBL Invoice or CreditNote against the EN 16931 business rules and the
+// RO CIUS national rules e-Factura applies: mandatory fields, party identifiers and addresses,
+// VAT category consistency and totals arithmetic. It returns every violation found, with the
+// rule and the element it concerns, or none for a valid document.
+func ValidateUBL(content []byte) []models.ANAFError {
+	var doc ublDocument
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(&doc); err != nil {
+		return []models.ANAFError{{Code: "XML", Message: fmt.Sprintf("document is not well-formed XML: %v", err)}}
+	}
+
+	v := &ublValidation{}
+	isCreditNote := doc.XMLName.Local == "CreditNote"
+	if !isCreditNote && doc.XMLName.Local != "Invoice" {
+		v.fail("XML", "/", "root element %q is neither Invoice nor CreditNote", doc.XMLName.Local)
+		return v.errors
+	}
+
+	// Document header
+	if doc.CustomizationID != ciusROCustomizationID {
+		v.fail("BR-01", "cbc:CustomizationID", "specification identifier must be %s", ciusROCustomizationID)
+	}
+	v.required("BR-02", "cbc:ID", doc.ID, "invoice number")
+	if doc.ID != "" && !strings.ContainsAny(doc.ID, "0123456789") {
+		v.fail("BR-RO-010", "cbc:ID", "invoice number %q must contain at least one digit", doc.ID)
+	}
+	if doc.IssueDate == "" {
+		v.fail("BR-03", "cbc:IssueDate", "issue date is missing")
+	} else if _, err := time.Parse("2006-01-02", doc.IssueDate); err != nil {
+		v.fail("BR-03", "cbc:IssueDate", "issue date %q is not in YYYY-MM-DD format", doc.IssueDate)
+	}
+	if isCreditNote {
+		if doc.CreditNoteTypeCode != "381" {
+			v.fail("BR-RO-020", "cbc:CreditNoteTypeCode", "credit note type code %q must be 381", doc.CreditNoteTypeCode)
+		}
+	} else {
+		switch doc.InvoiceTypeCode {
+		case "380", "384", "389", "751":
+		default:
+			v.fail("BR-RO-020", "cbc:InvoiceTypeCode", "invoice type code %q must be 380, 384, 389 or 751", doc.InvoiceTypeCode)
+		}
+	}
+	currency := doc.DocumentCurrencyCode
+	v.required("BR-05", "cbc:DocumentCurrencyCode", currency, "document currency")
+	if currency != "" && currency != "RON" && doc.TaxCurrencyCode != "RON" {
+		v.fail("BR-RO-030", "cbc:TaxCurrencyCode", "VAT must be accounted in RON when the invoice is in %s", currency)
+	}
+
+	// Parties
+	validateUBLParty(v, &doc.Supplier, "cac:AccountingSupplierParty", true)
+	validateUBLParty(v, &doc.Customer, "cac:AccountingCustomerParty", false)
+
+	// Lines
+	lines := doc.InvoiceLines
+	lineField := "cac:InvoiceLine"
+	if isCreditNote {
+		lines = doc.CreditNoteLines
+		lineField = "cac:CreditNoteLine"
+	}
+	if len(lines) == 0 {
+		v.fail("BR-16", lineField, "document has no lines")
+	}
+	var lineTotal float64
+	lineNetByCategory := map[string]float64{}
+	for i, line := range lines {
+		field := fmt.Sprintf("%s[%d]", lineField, i+1)
+		v.required("BR-21", field+"/cbc:ID", line.ID, "line identifier")
+		quantity := line.InvoicedQuantity
+		if isCreditNote {
+			quantity = line.CreditedQuantity
+		}
+		if quantity == nil || strings.TrimSpace(quantity.Value) == "" {
+			v.fail("BR-22", field, "invoiced quantity is missing")
+		} else if quantity.UnitCode == "" {
+			v.fail("BR-23", field, "unit of measure code is missing")
+		}
+		net := v.amount(field+"/cbc:LineExtensionAmount", &line.LineExtensionAmount, currency)
+		lineTotal += net
+		v.required("BR-25", field+"/cac:Item/cbc:Name", line.ItemName, "item name")
+		if line.PriceAmount == nil {
+			v.fail("BR-26", field+"/cac:Price", "item net price is missing")
+		} else if price := v.amount(field+"/cac:Price/cbc:PriceAmount", line.PriceAmount, currency); price < 0 {
+			v.fail("BR-27", field+"/cac:Price/cbc:PriceAmount", "item net price must not be negative")
+		}
+		if line.TaxCategory == nil {
+			v.fail("BR-CO-04", field+"/cac:Item/cac:ClassifiedTaxCategory", "line VAT category is missing")
+			continue
+		}
+		validateUBLTaxCategory(v, line.TaxCategory, field+"/cac:Item/cac:ClassifiedTaxCategory")
+		lineNetByCategory[taxCategoryKey(line.TaxCategory)] += net
+	}
+
+	// VAT breakdown
+	if len(doc.TaxTotals) == 0 {
+		v.fail("BR-CO-18", "cac:TaxTotal", "VAT breakdown is missing")
+		return v.errors
+	}
+	taxTotal := doc.TaxTotals[0]
+	totalVAT := v.amount("cac:TaxTotal/cbc:TaxAmount", &taxTotal.TaxAmount, currency)
+	if len(taxTotal.TaxSubtotals) == 0 {
+		v.fail("BR-CO-18", "cac:TaxTotal/cac:TaxSubtotal", "VAT breakdown is missing")
+	}
+	var subtotalVAT float64
+	categories := map[string]bool{}
+	for i, subtotal := range taxTotal.TaxSubtotals {
+		field := fmt.Sprintf("cac:TaxTotal/cac:TaxSubtotal[%d]", i+1)
+		taxable := v.amount(field+"/cbc:TaxableAmount", &subtotal.TaxableAmount, currency)
+		tax := v.amount(field+"/cbc:TaxAmount", &subtotal.TaxAmount, currency)
+		subtotalVAT += tax
+		category := &subtotal.TaxCategory
+		validateUBLTaxCategory(v, category, field+"/cac:TaxCategory")
+		key := taxCategoryKey(category)
+		categories[category.ID] = true
+
+		if net, ok := lineNetByCategory[key]; ok && !centsEqual(net, taxable) {
+			v.fail("BR-"+category.ID+"-08", field+"/cbc:TaxableAmount",
+				"taxable amount %.2f differs from the sum of the lines in category %s (%.2f)", taxable, key, net)
+		}
+		delete(lineNetByCategory, key)
+		if rate, err := strconv.ParseFloat(category.Percent, 64); err == nil && category.ID == "S" &&
+			!centsEqual(tax, roundToCents(taxable*rate/100)) {
+			v.fail("BR-CO-17", field+"/cbc:TaxAmount",
+				"VAT %.2f differs from taxable amount × rate (%.2f)", tax, roundToCents(taxable*rate/100))
+		}
+		if (category.ID == "Z" || category.ID == "O") && tax != 0 {
+			v.fail("BR-"+category.ID+"-09", field+"/cbc:TaxAmount", "VAT must be 0 in category %s", category.ID)
+		}
+	}
+	for key := range lineNetByCategory {
+		v.fail("BR-CO-18", "cac:TaxTotal", "lines in category %s have no VAT breakdown", key)
+	}
+	if len(taxTotal.TaxSubtotals) > 0 && !centsEqual(totalVAT, subtotalVAT) {
+		v.fail("BR-CO-14", "cac:TaxTotal/cbc:TaxAmount",
+			"VAT total %.2f differs from the sum of the VAT breakdown (%.2f)", totalVAT, subtotalVAT)
+	}
+	if categories["O"] && len(categories) > 1 {
+		v.fail("BR-O-11", "cac:TaxTotal", "a document not subject to VAT cannot have other VAT categories")
+	}
+	if categories["O"] && doc.Supplier.VATID != "" {
+		v.fail("BR-O-02", "cac:AccountingSupplierParty/cac:PartyTaxScheme", "seller VAT identifier must be absent when not subject to VAT")
+	}
+	if (categories["S"] || categories["Z"]) && doc.Supplier.VATID == "" {
+		v.fail("BR-S-02", "cac:AccountingSupplierParty/cac:PartyTaxScheme", "seller VAT identifier is missing")
+	}
+
+	// Document totals
+	totals := doc.MonetaryTotal
+	lineExtension := v.amount("cac:LegalMonetaryTotal/cbc:LineExtensionAmount", totals.LineExtensionAmount, currency)
+	taxExclusive := v.amount("cac:LegalMonetaryTotal/cbc:TaxExclusiveAmount", totals.TaxExclusiveAmount, currency)
+	taxInclusive := v.amount("cac:LegalMonetaryTotal/cbc:TaxInclusiveAmount", totals.TaxInclusiveAmount, currency)
+	payable := v.amount("cac:LegalMonetaryTotal/cbc:PayableAmount", totals.PayableAmount, currency)
+	if !centsEqual(lineExtension, lineTotal) {
+		v.fail("BR-CO-10", "cac:LegalMonetaryTotal/cbc:LineExtensionAmount",
+			"sum of line net amounts %.2f differs from the lines (%.2f)", lineExtension, lineTotal)
+	}
+	if !centsEqual(taxExclusive, lineExtension) {
+		v.fail("BR-CO-13", "cac:LegalMonetaryTotal/cbc:TaxExclusiveAmount",
+			"total without VAT %.2f differs from the sum of line net amounts (%.2f)", taxExclusive, lineExtension)
+	}
+	if !centsEqual(taxInclusive, taxExclusive+totalVAT) {
+		v.fail("BR-CO-15", "cac:LegalMonetaryTotal/cbc:TaxInclusiveAmount",
+			"total with VAT %.2f differs from total without VAT plus VAT (%.2f)", taxInclusive, roundToCents(taxExclusive+totalVAT))
+	}
+	if !centsEqual(payable, taxInclusive) {
+		v.fail("BR-CO-16", "cac:LegalMonetaryTotal/cbc:PayableAmount",
+			"amount due %.2f differs from the total with VAT (%.2f)", payable, taxInclusive)
+	}
+
+	return v.errors
+}
+
+// ublPartyRules are the rules covering one party, seller or buyer
+type ublPartyRules struct {
+	role, name, address, country, street, city, county, sector string
+}
+
+var (
+	ublSellerRules = ublPartyRules{"seller", "BR-06", "BR-08", "BR-09", "BR-RO-080", "BR-RO-090", "BR-RO-100", "BR-RO-110"}
+	ublBuyerRules  = ublPartyRules{"buyer", "BR-07", "BR-10", "BR-11", "BR-RO-081", "BR-RO-091", "BR-RO-101", "BR-RO-111"}
+)
+
+// validateUBLParty checks a party's name, identifiers and postal address
+func validateUBLParty(v *ublValidation, party *ublDocParty, field string, seller bool) {
+	rules := ublBuyerRules
+	if seller {
+		rules = ublSellerRules
+	}
+
+	if strings.TrimSpace(party.RegistrationName) == "" && strings.TrimSpace(party.Name) == "" {
+		v.fail(rules.name, field+"/cac:PartyLegalEntity/cbc:RegistrationName", "%s name is missing", rules.role)
+	}
+
+	if party.VATID != "" {
+		if !hasCountryPrefix(party.VATID) {
+			v.fail("BR-CO-09", field+"/cac:PartyTaxScheme/cbc:CompanyID",
+				"%s VAT identifier %q must start with its country code", rules.role, party.VATID)
+		} else if strings.HasPrefix(party.VATID, "RO") {
+			if err := utils.ValidateCUI(party.VATID); err != nil {
+				v.fail("CUI", field+"/cac:PartyTaxScheme/cbc:CompanyID", "%s VAT identifier %q: %v", rules.role, party.VATID, err)
+			}
+		}
+	}
+	if seller && party.VATID == "" {
+		if strings.TrimSpace(party.LegalID) == "" {
+			v.fail("BR-CO-26", field+"/cac:PartyLegalEntity/cbc:CompanyID", "seller VAT identifier or registration identifier is missing")
+		} else if err := utils.ValidateCUI(party.LegalID); err != nil {
+			v.fail("CUI", field+"/cac:PartyLegalEntity/cbc:CompanyID", "seller CUI %q: %v", party.LegalID, err)
+		}
+	}
+
+	address := party.PostalAddress
+	addressField := field + "/cac:PostalAddress"
+	if address == nil {
+		v.fail(rules.address, addressField, "%s postal address is missing", rules.role)
+		return
+	}
+	if address.Country == "" {
+		v.fail(rules.country, addressField+"/cac:Country/cbc:IdentificationCode", "%s country code is missing", rules.role)
+		return
+	}
+	if address.Country != "RO" {
+		return
+	}
+
+	// RO CIUS requires a full Romanian address with coded county and, in Bucharest, sector
+	v.required(rules.street, addressField+"/cbc:StreetName", address.StreetName, rules.role+" street")
+	v.required(rules.city, addressField+"/cbc:CityName", address.CityName, rules.role+" city")
+	if !utils.IsRomanianCountyCode(address.CountrySubentity) {
+		v.fail(rules.county, addressField+"/cbc:CountrySubentity",
+			"%s county %q must be an ISO 3166-2:RO code such as RO-B or RO-CJ", rules.role, address.CountrySubentity)
+	} else if address.CountrySubentity == "RO-B" {
+		if sector, ok := utils.BucharestSector(address.CityName, ""); !ok || sector != address.CityName {
+			v.fail(rules.sector, addressField+"/cbc:CityName",
+				"%s city in Bucharest must be the sector, SECTOR1 to SECTOR6, not %q", rules.role, address.CityName)
+		}
+	}
+}
+
+// hasCountryPrefix reports whether a VAT identifier starts with a two-letter country code
+func hasCountryPrefix(vatID string) bool {
+	return len(vatID) > 2 && vatID[0] >= 'A' && vatID[0] <= 'Z' && vatID[1] >= 'A' && vatID[1] <= 'Z'
+}
+
+// validateUBLTaxCategory checks a VAT category code against its rate
+func validateUBLTaxCategory(v *ublValidation, category *ublDocTaxCategory, field string) {
+	switch category.ID {
+	case "S":
+		if rate, err := strconv.ParseFloat(category.Percent, 64); err != nil || rate <= 0 {
+			v.fail("BR-S-05", field+"/cbc:Percent", "standard rated VAT category needs a rate above 0, not %q", category.Percent)
+		}
+	case "Z":
+		if rate, err := strconv.ParseFloat(category.Percent, 64); err != nil || rate != 0 {
+			v.fail("BR-Z-05", field+"/cbc:Percent", "zero rated VAT category needs a rate of 0, not %q", category.Percent)
+		}
+	case "O":
+		if category.Percent != "" {
+			v.fail("BR-O-05", field+"/cbc:Percent", "VAT category not subject to VAT must not have a rate")
+		}
+	case "E", "AE", "K", "G", "L", "M":
+	case "":
+		v.fail("BR-CO-04", field+"/cbc:ID", "VAT category code is missing")
+	default:
+		v.fail("BR-CL-17", field+"/cbc:ID", "unknown VAT category code %q", category.ID)
+	}
+}
+
+// taxCategoryKey identifies a VAT breakdown: category code and rate
+func taxCategoryKey(category *ublDocTaxCategory) string {
+	if category.Percent == "" {
+		return category.ID
+	}
+	rate, err := strconv.ParseFloat(category.Percent, 64)
+	if err != nil {
+		return category.ID + " " + category.Percent
+	}
+	return category.ID + " " + strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
+
+func centsEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// summarizeValidationErrors joins validation errors into one message, for logs and API errors
+func summarizeValidationErrors(validationErrors []models.ANAFError) string {
+	messages := make([]string, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		messages = append(messages, fmt.Sprintf("[%s] %s", e.Code, e.Message))
+	}
+	return strings.Join(messages, "; ")
+}
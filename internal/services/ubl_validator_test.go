@@ -0,0 +1,124 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// testUBLInvoice is a minimal invoice that meets every rule ValidateUBL checks
+const testUBLInvoice = `<?xml version="1.0" encoding="UTF-8"?>
+<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+  xmlns:cac="urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+  xmlns:cbc="urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2">
+  <cbc:CustomizationID>urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1</cbc:CustomizationID>
+  <cbc:ID>CB-2025-0042</cbc:ID>
+  <cbc:IssueDate>2025-09-15</cbc:IssueDate>
+  <cbc:InvoiceTypeCode>380</cbc:InvoiceTypeCode>
+  <cbc:DocumentCurrencyCode>RON</cbc:DocumentCurrencyCode>
+  <cac:AccountingSupplierParty>
+    <cac:Party>
+      <cac:PartyName><cbc:Name>CleanBuddy SRL</cbc:Name></cac:PartyName>
+      <cac:PostalAddress>
+        <cbc:StreetName>Str. Memorandumului 28</cbc:StreetName>
+        <cbc:CityName>Cluj-Napoca</cbc:CityName>
+        <cbc:CountrySubentity>RO-CJ</cbc:CountrySubentity>
+        <cac:Country><cbc:IdentificationCode>RO</cbc:IdentificationCode></cac:Country>
+      </cac:PostalAddress>
+      <cac:PartyTaxScheme>
+        <cbc:CompanyID>RO12345674</cbc:CompanyID>
+        <cac:TaxScheme><cbc:ID>VAT</cbc:ID></cac:TaxScheme>
+      </cac:PartyTaxScheme>
+      <cac:PartyLegalEntity>
+        <cbc:RegistrationName>CleanBuddy SRL</cbc:RegistrationName>
+        <cbc:CompanyID>J12/1234/2020</cbc:CompanyID>
+      </cac:PartyLegalEntity>
+    </cac:Party>
+  </cac:AccountingSupplierParty>
+  <cac:AccountingCustomerParty>
+    <cac:Party>
+      <cac:PartyName><cbc:Name>Ion Popescu</cbc:Name></cac:PartyName>
+      <cac:PostalAddress>
+        <cbc:StreetName>Bd. Unirii 10</cbc:StreetName>
+        <cbc:CityName>SECTOR3</cbc:CityName>
+        <cbc:CountrySubentity>RO-B</cbc:CountrySubentity>
+        <cac:Country><cbc:IdentificationCode>RO</cbc:IdentificationCode></cac:Country>
+      </cac:PostalAddress>
+      <cac:PartyLegalEntity><cbc:RegistrationName>Ion Popescu</cbc:RegistrationName></cac:PartyLegalEntity>
+    </cac:Party>
+  </cac:AccountingCustomerParty>
+  <cac:TaxTotal>
+    <cbc:TaxAmount currencyID="RON">42</cbc:TaxAmount>
+    <cac:TaxSubtotal>
+      <cbc:TaxableAmount currencyID="RON">200</cbc:TaxableAmount>
+      <cbc:TaxAmount currencyID="RON">42</cbc:TaxAmount>
+      <cac:TaxCategory><cbc:ID>S</cbc:ID><cbc:Percent>21</cbc:Percent><cac:TaxScheme><cbc:ID>VAT</cbc:ID></cac:TaxScheme></cac:TaxCategory>
+    </cac:TaxSubtotal>
+  </cac:TaxTotal>
+  <cac:LegalMonetaryTotal>
+    <cbc:LineExtensionAmount currencyID="RON">200</cbc:LineExtensionAmount>
+    <cbc:TaxExclusiveAmount currencyID="RON">200</cbc:TaxExclusiveAmount>
+    <cbc:TaxInclusiveAmount currencyID="RON">242</cbc:TaxInclusiveAmount>
+    <cbc:PayableAmount currencyID="RON">242</cbc:PayableAmount>
+  </cac:LegalMonetaryTotal>
+  <cac:InvoiceLine>
+    <cbc:ID>1</cbc:ID>
+    <cbc:InvoicedQuantity unitCode="HUR">4</cbc:InvoicedQuantity>
+    <cbc:LineExtensionAmount currencyID="RON">200</cbc:LineExtensionAmount>
+    <cac:Item>
+      <cbc:Name>Servicii de curățenie profesionale</cbc:Name>
+      <cac:ClassifiedTaxCategory><cbc:ID>S</cbc:ID><cbc:Percent>21</cbc:Percent><cac:TaxScheme><cbc:ID>VAT</cbc:ID></cac:TaxScheme></cac:ClassifiedTaxCategory>
+    </cac:Item>
+    <cac:Price><cbc:PriceAmount currencyID="RON">50</cbc:PriceAmount></cac:Price>
+  </cac:InvoiceLine>
+</Invoice>`
+
+func TestValidateUBL(t *testing.T) {
+	tests := []struct {
+		name  string
+		old   string // Replaced in testUBLInvoice by new, "" for the valid document
+		new   string
+		rules []string
+	}{
+		{"valid", "", "", nil},
+		{"not XML", testUBLInvoice, "<Invoice>", []string{"XML"}},
+		{"wrong customization", "CIUS-RO:1.0.1", "CIUS-RO:1.0.0", []string{"BR-01"}},
+		{"number without digits", "<cbc:ID>CB-2025-0042</cbc:ID>", "<cbc:ID>CB</cbc:ID>", []string{"BR-RO-010"}},
+		{"bad issue date", "2025-09-15", "15.09.2025", []string{"BR-03"}},
+		{"unknown type code", "<cbc:InvoiceTypeCode>380", "<cbc:InvoiceTypeCode>381", []string{"BR-RO-020"}},
+		{"seller CUI check digit", "RO12345674", "RO12345678", []string{"CUI"}},
+		{"seller county not coded", "RO-CJ", "Cluj", []string{"BR-RO-100"}},
+		{"buyer without street", "<cbc:StreetName>Bd. Unirii 10</cbc:StreetName>", "", []string{"BR-RO-081"}},
+		{"Bucharest buyer without sector", "SECTOR3", "Bucuresti", []string{"BR-RO-111"}},
+		{"line without VAT category", "<cac:ClassifiedTaxCategory><cbc:ID>S</cbc:ID><cbc:Percent>21</cbc:Percent><cac:TaxScheme><cbc:ID>VAT</cbc:ID></cac:TaxScheme></cac:ClassifiedTaxCategory>", "", []string{"BR-CO-04"}},
+		{"VAT not rate × base", "<cbc:TaxAmount currencyID=\"RON\">42</cbc:TaxAmount>\n      <cac:TaxCategory>", "<cbc:TaxAmount currencyID=\"RON\">40</cbc:TaxAmount>\n      <cac:TaxCategory>", []string{"BR-CO-17", "BR-CO-14"}},
+		{"payable differs from total", "<cbc:PayableAmount currencyID=\"RON\">242", "<cbc:PayableAmount currencyID=\"RON\">240", []string{"BR-CO-16"}},
+		{"amount in another currency", "<cbc:PayableAmount currencyID=\"RON\">", "<cbc:PayableAmount currencyID=\"EUR\">", []string{"BR-CL-03"}},
+		{"three decimals", "<cbc:PriceAmount currencyID=\"RON\">50", "<cbc:PriceAmount currencyID=\"RON\">50.125", []string{"BR-DEC"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := testUBLInvoice
+			if tt.old != "" {
+				if !strings.Contains(content, tt.old) {
+					t.Fatalf("test document does not contain %q", tt.old)
+				}
+				content = strings.Replace(content, tt.old, tt.new, 1)
+			}
+
+			got := map[string]bool{}
+			for _, e := range ValidateUBL([]byte(content)) {
+				got[e.Code] = true
+			}
+			for _, rule := range tt.rules {
+				if !got[rule] {
+					t.Errorf("ValidateUBL() did not report %s, got %v", rule, got)
+				}
+				delete(got, rule)
+			}
+			if len(got) > 0 {
+				t.Errorf("ValidateUBL() reported unexpected rules %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/utils"
+)
+
+// VAT categories of booking price lines, matched against company.vat_rates
+const (
+	VATCategoryService  = "service"
+	VATCategoryAddons   = "addons"
+	VATCategorySupplies = "supplies"
+)
+
+// PriceLine is one priced component of a booking at list price, before time multipliers and discounts
+type PriceLine struct {
+	Item        string // Add-on key, empty for the cleaning service
+	Description string
+	Category    string
+	Quantity    float64
+	UnitCode    string // UN/ECE Recommendation 20 unit: HUR, C62, MTK
+	Amount      float64
+}
+
+// TaxedLine is a price line as charged to the client, split into net amount and VAT
+type TaxedLine struct {
+	PriceLine
+	VATRate     float64
+	NetAmount   float64
+	VATAmount   float64
+	GrossAmount float64
+}
+
+// vatRateFor returns the VAT rate of a line category on the date of supply, or 0 when the
+// company is not registered for VAT
+func vatRateFor(cfg *config.CompanyConfig, category string, date time.Time) float64 {
+	if !cfg.VATRegistered {
+		return 0
+	}
+	if rate, ok := utils.VATRateFor(vatRates(cfg), category, date); ok {
+		return rate
+	}
+	if cfg.VATRate > 0 {
+		return cfg.VATRate
+	}
+	return 0.19 // Romanian standard rate before August 2025
+}
+
+// vatRates parses the configured rate table, skipping entries with an invalid date
+func vatRates(cfg *config.CompanyConfig) []utils.VATRate {
+	rates := make([]utils.VATRate, 0, len(cfg.VATRates))
+	for _, r := range cfg.VATRates {
+		from, err := time.Parse("2006-01-02", r.EffectiveFrom)
+		if err != nil {
+			fmt.Printf("Warning: ignoring VAT rate with invalid effective_from %q: %v\n", r.EffectiveFrom, err)
+			continue
+		}
+		rates = append(rates, utils.VATRate{Category: r.Category, Rate: r.Rate, EffectiveFrom: from})
+	}
+	return rates
+}
+
+// vatDate is the date of supply VAT rates are looked up by: the scheduled service date, or
+// fallback for bookings without a fixed date
+func vatDate(scheduledDate, fallback time.Time) time.Time {
+	if scheduledDate.IsZero() {
+		return fallback
+	}
+	return scheduledDate
+}
+
+// taxLines spreads a VAT-inclusive total over price lines in proportion to their list price
+// (their list price plus VAT when prices exclude VAT) and splits each line into net and VAT.
+// The last line absorbs rounding so the lines add up to the total exactly, and VAT is split
+// per rate so that each rate's lines add up to that rate's VAT breakdown.
+func taxLines(lines []PriceLine, total float64, cfg *config.CompanyConfig, date time.Time) []TaxedLine {
+	taxed := make([]TaxedLine, len(lines))
+	weights := make([]float64, len(lines))
+	totalWeight := 0.0
+	for i, line := range lines {
+		taxed[i] = TaxedLine{PriceLine: line, VATRate: vatRateFor(cfg, line.Category, date)}
+		weights[i] = line.Amount
+		if cfg.PricesExcludeVAT {
+			weights[i] *= 1 + taxed[i].VATRate
+		}
+		totalWeight += weights[i]
+	}
+
+	allocated := 0.0
+	byRate := map[float64][]int{}
+	for i := range taxed {
+		var gross float64
+		switch {
+		case i == len(taxed)-1:
+			gross = roundToCents(total - allocated)
+		case totalWeight > 0:
+			gross = roundToCents(total * weights[i] / totalWeight)
+		}
+		allocated += gross
+		taxed[i].GrossAmount = gross
+		byRate[taxed[i].VATRate] = append(byRate[taxed[i].VATRate], i)
+	}
+
+	for rate, indexes := range byRate {
+		rateGross := 0.0
+		for _, i := range indexes {
+			rateGross += taxed[i].GrossAmount
+		}
+		rateNet, _ := utils.SplitGross(rateGross, rate)
+
+		for n, i := range indexes {
+			if n == len(indexes)-1 {
+				taxed[i].NetAmount = roundToCents(rateNet)
+				taxed[i].VATAmount = roundToCents(taxed[i].GrossAmount - taxed[i].NetAmount)
+				break
+			}
+			taxed[i].NetAmount, taxed[i].VATAmount = utils.SplitGross(taxed[i].GrossAmount, rate)
+			rateNet -= taxed[i].NetAmount
+		}
+	}
+	return taxed
+}
+
+// vatOnNet returns the VAT due on a net total spread over price lines in proportion to their list price
+func vatOnNet(lines []PriceLine, netTotal float64, cfg *config.CompanyConfig, date time.Time) float64 {
+	listTotal := 0.0
+	for _, line := range lines {
+		listTotal += line.Amount
+	}
+	if listTotal <= 0 {
+		return 0
+	}
+
+	vat := 0.0
+	for _, line := range lines {
+		vat += utils.AddVAT(netTotal*line.Amount/listTotal, vatRateFor(cfg, line.Category, date))
+	}
+	return roundToCents(vat)
+}
+
+// sumTaxedLines returns the net, VAT and gross totals of taxed lines
+func sumTaxedLines(lines []TaxedLine) (net, vat, gross float64) {
+	for _, line := range lines {
+		net += line.NetAmount
+		vat += line.VATAmount
+		gross += line.GrossAmount
+	}
+	return roundToCents(net), roundToCents(vat), roundToCents(gross)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/models"
+)
+
+// WalletService manages client store credit
+type WalletService struct {
+	walletRepo    *models.WalletRepository
+	userRepo      *models.UserRepository
+	ledgerService *LedgerService
+}
+
+// Wallet is a client's spendable balance with its recent history
+type Wallet struct {
+	UserID       string
+	Balance      float64
+	Transactions []*models.WalletTransaction
+}
+
+// NewWalletService creates a new wallet service
+func NewWalletService(db *sql.DB) *WalletService {
+	return &WalletService{
+		walletRepo: models.NewWalletRepository(db),
+		userRepo:   models.NewUserRepository(db),
+	}
+}
+
+// SetLedgerService sets the ledger service used to record credit issue, use and expiry
+func (s *WalletService) SetLedgerService(ledgerService *LedgerService) {
+	s.ledgerService = ledgerService
+}
+
+// GetWallet returns a user's balance and wallet history
+func (s *WalletService) GetWallet(userID string, limit, offset int) (*Wallet, error) {
+	balance, err := s.walletRepo.GetBalance(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.walletRepo.GetTransactions(userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet transactions: %w", err)
+	}
+
+	return &Wallet{UserID: userID, Balance: roundToCents(balance), Transactions: transactions}, nil
+}
+
+// GrantCredit adds referral, goodwill or gift card credit to a client's wallet (admin only)
+func (s *WalletService) GrantCredit(userID string, amount float64, source models.WalletSource, reason string, expiresAt *time.Time, adminID string) (*models.WalletTransaction, error) {
+	switch source {
+	case models.WalletSourceReferral, models.WalletSourceGoodwill, models.WalletSourceGiftCard:
+	default:
+		return nil, fmt.Errorf("credit source %s cannot be granted manually", source)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("credit amount must be positive")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry date must be in the future")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	txn := &models.WalletTransaction{
+		UserID:    userID,
+		Amount:    roundToCents(amount),
+		Source:    source,
+		Reason:    sql.NullString{String: reason, Valid: reason != ""},
+		CreatedBy: sql.NullString{String: adminID, Valid: adminID != ""},
+	}
+	if expiresAt != nil {
+		txn.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	if err := s.walletRepo.AddCredit(txn); err != nil {
+		return nil, fmt.Errorf("failed to add wallet credit: %w", err)
+	}
+
+	s.postCredit(txn, nil)
+	return txn, nil
+}
+
+// CreditDisputeRefund refunds a dispute to the client's wallet instead of the card
+func (s *WalletService) CreditDisputeRefund(dispute *models.Dispute, booking *models.Booking, amount float64, adminID string) (*models.WalletTransaction, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("refund amount must be positive")
+	}
+
+	txn := &models.WalletTransaction{
+		UserID:    booking.ClientID,
+		Amount:    roundToCents(amount),
+		Source:    models.WalletSourceDisputeRefund,
+		Reason:    sql.NullString{String: fmt.Sprintf("Dispute resolution for booking %s", booking.ID), Valid: true},
+		BookingID: sql.NullString{String: booking.ID, Valid: true},
+		DisputeID: sql.NullString{String: dispute.ID, Valid: true},
+		CreatedBy: sql.NullString{String: adminID, Valid: adminID != ""},
+	}
+
+	if err := s.walletRepo.AddCredit(txn); err != nil {
+		return nil, fmt.Errorf("failed to add wallet credit: %w", err)
+	}
+
+	s.postCredit(txn, booking)
+	return txn, nil
+}
+
+// ApplyCreditToBooking spends available credit on a new booking before the card is charged.
+// Returns the amount applied; booking.CreditApplied is updated in place.
+func (s *WalletService) ApplyCreditToBooking(booking *models.Booking) (float64, error) {
+	applied, err := s.walletRepo.ApplyToBooking(booking.ClientID, booking.ID, roundToCents(booking.TotalPrice))
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply wallet credit: %w", err)
+	}
+	if applied > 0 {
+		booking.CreditApplied = applied
+	}
+	return applied, nil
+}
+
+// ReleaseBookingCredit returns the credit spent on a cancelled booking to the wallet,
+// keeping feePercentage of it when a late cancellation fee applies
+func (s *WalletService) ReleaseBookingCredit(booking *models.Booking, feePercentage float64) error {
+	returned, kept, err := s.walletRepo.ReleaseBookingCredit(booking.ClientID, booking.ID, feePercentage)
+	if err != nil {
+		return fmt.Errorf("failed to return wallet credit: %w", err)
+	}
+
+	if kept > 0 && s.ledgerService != nil {
+		if err := s.ledgerService.PostWalletRedemption(booking, kept); err != nil {
+			fmt.Printf("Warning: failed to record kept wallet credit for booking %s in ledger: %v\n", booking.ID, err)
+		}
+	}
+
+	if returned > 0 || kept > 0 {
+		booking.CreditApplied = kept
+	}
+	return nil
+}
+
+// RecognizeRedemption records credit spent on a completed booking as earned revenue
+func (s *WalletService) RecognizeRedemption(booking *models.Booking) {
+	if booking.CreditApplied <= 0 || s.ledgerService == nil {
+		return
+	}
+	if err := s.ledgerService.PostWalletRedemption(booking, booking.CreditApplied); err != nil {
+		fmt.Printf("Warning: failed to record wallet redemption for booking %s in ledger: %v\n", booking.ID, err)
+	}
+}
+
+// ExpireCredits zeroes expired credit lots and records the expiry
+func (s *WalletService) ExpireCredits() (int, error) {
+	debits, err := s.walletRepo.ExpireLots()
+	if err != nil {
+		return 0, err
+	}
+
+	if s.ledgerService != nil {
+		for _, debit := range debits {
+			if err := s.ledgerService.PostWalletExpiry(debit); err != nil {
+				fmt.Printf("Warning: failed to record wallet expiry %s in ledger: %v\n", debit.ID, err)
+			}
+		}
+	}
+
+	return len(debits), nil
+}
+
+// RunCreditExpiry periodically expires credit (run as a goroutine)
+func (s *WalletService) RunCreditExpiry(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := s.ExpireCredits()
+		if err != nil {
+			fmt.Printf("Warning: failed to expire wallet credit: %v\n", err)
+			continue
+		}
+		if count > 0 {
+			fmt.Printf("Expired %d wallet credit lot(s)\n", count)
+		}
+	}
+}
+
+func (s *WalletService) postCredit(txn *models.WalletTransaction, booking *models.Booking) {
+	if s.ledgerService == nil {
+		return
+	}
+	if err := s.ledgerService.PostWalletCredit(txn, booking); err != nil {
+		fmt.Printf("Warning: failed to record wallet credit %s in ledger: %v\n", txn.ID, err)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cleanbuddy/backend/internal/config"
 	"github.com/cleanbuddy/backend/internal/models"
+	"github.com/cleanbuddy/backend/internal/utils"
 )
 
 // XMLGenerator handles UBL 2.1 XML generation for ANAF e-Factura
@@ -59,44 +62,179 @@ type UBLParty struct {
 			Name string `xml:"cbc:Name"`
 		} `xml:"cac:PartyName"`
 		PostalAddress struct {
-			Country struct {
+			StreetName       string `xml:"cbc:StreetName,omitempty"`
+			CityName         string `xml:"cbc:CityName,omitempty"`
+			PostalZone       string `xml:"cbc:PostalZone,omitempty"`
+			CountrySubentity string `xml:"cbc:CountrySubentity,omitempty"`
+			Country          struct {
 				IdentificationCode string `xml:"cbc:IdentificationCode"`
 			} `xml:"cac:Country"`
 		} `xml:"cac:PostalAddress"`
-		PartyTaxScheme struct {
-			CompanyID string `xml:"cbc:CompanyID"`
-			TaxScheme struct {
-				ID string `xml:"cbc:ID"`
-			} `xml:"cac:TaxScheme"`
-		} `xml:"cac:PartyTaxScheme"`
+		PartyTaxScheme   *UBLPartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+		PartyLegalEntity struct {
+			RegistrationName string `xml:"cbc:RegistrationName"`
+			CompanyID        string `xml:"cbc:CompanyID,omitempty"`
+		} `xml:"cac:PartyLegalEntity"`
 		Contact struct {
 			ElectronicMail string `xml:"cbc:ElectronicMail,omitempty"`
 		} `xml:"cac:Contact"`
 	} `xml:"cac:Party"`
 }
 
+// UBLPartyTaxScheme is a party's VAT identifier, present only for VAT payers
+type UBLPartyTaxScheme struct {
+	CompanyID string `xml:"cbc:CompanyID"`
+	TaxScheme struct {
+		ID string `xml:"cbc:ID"`
+	} `xml:"cac:TaxScheme"`
+}
+
+// setName sets a party's trading and legal name
+func (p *UBLParty) setName(name string) {
+	p.Party.PartyName.Name = name
+	p.Party.PartyLegalEntity.RegistrationName = name
+}
+
+// setAddress sets a party's postal address. Romanian counties are coded ISO 3166-2:RO (RO-CJ)
+// and Bucharest cities by sector (SECTOR1 to SECTOR6), as CIUS-RO requires.
+func (p *UBLParty) setAddress(street, city, county, postalCode, country string) {
+	if country == "" {
+		country = "RO"
+	}
+	address := &p.Party.PostalAddress
+	address.StreetName = street
+	address.CityName = city
+	address.PostalZone = postalCode
+	address.CountrySubentity = county
+	address.Country.IdentificationCode = country
+	if country != "RO" {
+		return
+	}
+	if code, ok := utils.RomanianCountyCode(county); ok {
+		address.CountrySubentity = code
+	}
+	if address.CountrySubentity == "RO-B" {
+		if sector, ok := utils.BucharestSector(city, postalCode); ok {
+			address.CityName = sector
+		}
+	}
+}
+
+// setVATID sets a Romanian party's VAT identifier, RO followed by the CUI
+func (p *UBLParty) setVATID(cui string) {
+	taxScheme := &UBLPartyTaxScheme{CompanyID: "RO" + utils.NormalizeCUI(cui)}
+	taxScheme.TaxScheme.ID = "VAT"
+	p.Party.PartyTaxScheme = taxScheme
+}
+
+// setCompanyBuyer fills a party with the details of a company billed. A VAT payer is identified
+// by its VAT identifier and trade register number, otherwise by its CUI alone.
+func (p *UBLParty) setCompanyBuyer(billing *models.InvoiceBillingDetails) {
+	p.setName(billing.LegalName)
+	p.setAddress(billing.StreetAddress, billing.City, billing.County, billing.PostalCode.String, billing.Country)
+	if billing.VATPayer {
+		p.setVATID(billing.CUI)
+		p.Party.PartyLegalEntity.CompanyID = billing.RegistrationNumber.String
+	} else {
+		p.Party.PartyLegalEntity.CompanyID = utils.NormalizeCUI(billing.CUI)
+	}
+}
+
+// setInvoiceBuyer fills a party with the buyer of a client invoice: the company in billing for
+// B2B invoices, otherwise the client at buyerAddress (nil if it is no longer on file)
+func (p *UBLParty) setInvoiceBuyer(invoice *models.Invoice, buyerAddress *models.Address, billing *models.InvoiceBillingDetails) {
+	p.setName(invoice.ClientName)
+	if billing != nil {
+		p.setCompanyBuyer(billing)
+	} else if buyerAddress != nil {
+		street := buyerAddress.StreetAddress
+		if buyerAddress.Apartment.Valid && buyerAddress.Apartment.String != "" {
+			street += ", " + buyerAddress.Apartment.String
+		}
+		p.setAddress(street, buyerAddress.City, buyerAddress.County, buyerAddress.PostalCode.String, buyerAddress.Country)
+	} else {
+		p.Party.PostalAddress.Country.IdentificationCode = "RO"
+	}
+	if invoice.ClientEmail.Valid {
+		p.Party.Contact.ElectronicMail = invoice.ClientEmail.String
+	}
+}
+
+// setCompanyParty fills a party with CleanBuddy's details. A VAT payer is identified by its VAT
+// identifier and trade register number, otherwise by its CUI alone.
+func (g *XMLGenerator) setCompanyParty(p *UBLParty) {
+	p.setName(g.config.LegalName)
+	p.setAddress(g.config.Address.Street, g.config.Address.City, g.config.Address.County,
+		g.config.Address.PostalCode, g.config.Address.Country)
+	if g.config.VATRegistered {
+		p.setVATID(g.config.CUI)
+		p.Party.PartyLegalEntity.CompanyID = g.config.RegistrationNumber
+	} else {
+		p.Party.PartyLegalEntity.CompanyID = utils.NormalizeCUI(g.config.CUI)
+	}
+	p.Party.Contact.ElectronicMail = g.config.Contact.Email
+}
+
 type UBLTaxTotal struct {
 	TaxAmount struct {
 		Value    float64 `xml:",chardata"`
 		Currency string  `xml:"currencyID,attr"`
 	} `xml:"cbc:TaxAmount"`
-	TaxSubtotal struct {
-		TaxableAmount struct {
-			Value    float64 `xml:",chardata"`
-			Currency string  `xml:"currencyID,attr"`
-		} `xml:"cbc:TaxableAmount"`
-		TaxAmount struct {
-			Value    float64 `xml:",chardata"`
-			Currency string  `xml:"currencyID,attr"`
-		} `xml:"cbc:TaxAmount"`
-		TaxCategory struct {
-			ID      string `xml:"cbc:ID"`
-			Percent string `xml:"cbc:Percent"`
-			TaxScheme struct {
-				ID string `xml:"cbc:ID"`
-			} `xml:"cac:TaxScheme"`
-		} `xml:"cac:TaxCategory"`
-	} `xml:"cac:TaxSubtotal"`
+	TaxSubtotals []UBLTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+// UBLTaxSubtotal is the VAT breakdown of one tax category and rate
+type UBLTaxSubtotal struct {
+	TaxableAmount struct {
+		Value    float64 `xml:",chardata"`
+		Currency string  `xml:"currencyID,attr"`
+	} `xml:"cbc:TaxableAmount"`
+	TaxAmount struct {
+		Value    float64 `xml:",chardata"`
+		Currency string  `xml:"currencyID,attr"`
+	} `xml:"cbc:TaxAmount"`
+	TaxCategory UBLTaxCategory `xml:"cac:TaxCategory"`
+}
+
+// UBLTaxCategory identifies a VAT category (S standard, Z zero rated, O not subject to VAT)
+type UBLTaxCategory struct {
+	ID                     string `xml:"cbc:ID"`
+	Percent                string `xml:"cbc:Percent,omitempty"`
+	TaxExemptionReasonCode string `xml:"cbc:TaxExemptionReasonCode,omitempty"`
+	TaxScheme              struct {
+		ID string `xml:"cbc:ID"`
+	} `xml:"cac:TaxScheme"`
+}
+
+// newUBLTaxCategory returns the VAT category of a rate; vatPayer is false for a supplier outside
+// the VAT system, whose invoices are not subject to VAT
+func newUBLTaxCategory(vatPayer bool, rate float64) UBLTaxCategory {
+	var category UBLTaxCategory
+	category.TaxScheme.ID = "VAT"
+	switch {
+	case !vatPayer:
+		category.ID = "O"
+	case rate == 0:
+		category.ID = "Z"
+		category.Percent = "0"
+	default:
+		category.ID = "S"
+		category.Percent = strconv.FormatFloat(roundToCents(rate*100), 'f', -1, 64)
+	}
+	return category
+}
+
+// addSubtotal adds the VAT breakdown of one category and rate to the document's tax total
+func (t *UBLTaxTotal) addSubtotal(category UBLTaxCategory, taxable, tax float64, currency string) {
+	subtotal := UBLTaxSubtotal{TaxCategory: category}
+	subtotal.TaxableAmount.Value = taxable
+	subtotal.TaxableAmount.Currency = currency
+	subtotal.TaxAmount.Value = tax
+	subtotal.TaxAmount.Currency = currency
+	if category.ID == "O" {
+		subtotal.TaxCategory.TaxExemptionReasonCode = "VATEX-EU-O"
+	}
+	t.TaxSubtotals = append(t.TaxSubtotals, subtotal)
 }
 
 type UBLMonetaryTotal struct {
@@ -129,8 +267,9 @@ type UBLInvoiceLine struct {
 		Currency string  `xml:"currencyID,attr"`
 	} `xml:"cbc:LineExtensionAmount"`
 	Item struct {
-		Description string `xml:"cbc:Description"`
-		Name        string `xml:"cbc:Name"`
+		Description           string          `xml:"cbc:Description"`
+		Name                  string          `xml:"cbc:Name"`
+		ClassifiedTaxCategory *UBLTaxCategory `xml:"cac:ClassifiedTaxCategory,omitempty"`
 	} `xml:"cac:Item"`
 	Price struct {
 		PriceAmount struct {
@@ -151,31 +290,17 @@ type UBLPaymentMeans struct {
 	} `xml:"cac:PayeeFinancialAccount"`
 }
 
-// GenerateInvoiceXML generates UBL 2.1 XML for ANAF e-Factura
-func (g *XMLGenerator) GenerateInvoiceXML(invoice *models.Invoice, booking *models.Booking) (string, error) {
-	// Calculate VAT breakdown
-	// If invoice already has VAT calculated, use it; otherwise calculate from total
-	var subtotal, vatAmount float64
-	if invoice.TaxAmount > 0 {
-		// VAT already calculated
-		subtotal = invoice.Subtotal
-		vatAmount = invoice.TaxAmount
-	} else {
-		// Calculate VAT from total (assuming total includes 19% VAT)
-		vatRate := g.config.VATRate
-		if vatRate == 0 {
-			vatRate = 0.19 // Default 19% VAT for Romania
-		}
-		subtotal = invoice.TotalAmount / (1 + vatRate)
-		vatAmount = invoice.TotalAmount - subtotal
-	}
-
+// GenerateInvoiceXML generates UBL 2.1 XML for ANAF e-Factura, with one UBL line per stored
+// invoice line and a VAT breakdown per tax category and rate. The buyer is the company in
+// billing for B2B invoices, otherwise the client at buyerAddress, the booking's address (nil if
+// it is no longer on file).
+func (g *XMLGenerator) GenerateInvoiceXML(invoice *models.Invoice, lines []*models.InvoiceLine, buyerAddress *models.Address, billing *models.InvoiceBillingDetails) (string, error) {
 	// Create UBL structure
 	ubl := UBLInvoice{
 		XMLNS:           "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2",
 		CAC:             "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
 		CBC:             "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
-		CustomizationID: "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0",
+		CustomizationID: "urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1",
 		ID:              invoice.InvoiceNumber,
 		IssueDate:       invoice.IssueDate.Format("2006-01-02"),
 		DueDate:         invoice.DueDate.Format("2006-01-02"),
@@ -184,38 +309,22 @@ func (g *XMLGenerator) GenerateInvoiceXML(invoice *models.Invoice, booking *mode
 	}
 
 	// Supplier (CleanBuddy) - Use config values
-	ubl.AccountingSupplierParty.Party.PartyName.Name = g.config.LegalName
-	ubl.AccountingSupplierParty.Party.PostalAddress.Country.IdentificationCode = g.config.Address.Country
-	ubl.AccountingSupplierParty.Party.PartyTaxScheme.CompanyID = g.config.CUI
-	ubl.AccountingSupplierParty.Party.PartyTaxScheme.TaxScheme.ID = "VAT"
-	ubl.AccountingSupplierParty.Party.Contact.ElectronicMail = g.config.Contact.Email
-
-	// Customer
-	ubl.AccountingCustomerParty.Party.PartyName.Name = invoice.ClientName
-	ubl.AccountingCustomerParty.Party.PostalAddress.Country.IdentificationCode = "RO"
-	if invoice.ClientEmail.Valid {
-		ubl.AccountingCustomerParty.Party.Contact.ElectronicMail = invoice.ClientEmail.String
-	}
+	g.setCompanyParty(&ubl.AccountingSupplierParty)
 
-	// Tax breakdown
-	vatPercent := g.config.VATRate * 100
-	if vatPercent == 0 {
-		vatPercent = 19 // Default 19%
-	}
-	ubl.TaxTotal.TaxAmount.Value = vatAmount
+	// Customer: the company billed, or the client at the address the service was performed
+	ubl.AccountingCustomerParty.setInvoiceBuyer(invoice, buyerAddress, billing)
+
+	// Tax breakdown, one subtotal per rate in the order the rates first appear
+	ubl.TaxTotal.TaxAmount.Value = invoice.TaxAmount
 	ubl.TaxTotal.TaxAmount.Currency = invoice.Currency
-	ubl.TaxTotal.TaxSubtotal.TaxableAmount.Value = subtotal
-	ubl.TaxTotal.TaxSubtotal.TaxableAmount.Currency = invoice.Currency
-	ubl.TaxTotal.TaxSubtotal.TaxAmount.Value = vatAmount
-	ubl.TaxTotal.TaxSubtotal.TaxAmount.Currency = invoice.Currency
-	ubl.TaxTotal.TaxSubtotal.TaxCategory.ID = "S" // Standard rate
-	ubl.TaxTotal.TaxSubtotal.TaxCategory.Percent = fmt.Sprintf("%.0f", vatPercent)
-	ubl.TaxTotal.TaxSubtotal.TaxCategory.TaxScheme.ID = "VAT"
+	for _, group := range groupInvoiceLinesByRate(lines) {
+		ubl.TaxTotal.addSubtotal(newUBLTaxCategory(g.config.VATRegistered, group.rate), group.net, group.vat, invoice.Currency)
+	}
 
 	// Monetary totals
-	ubl.LegalMonetaryTotal.LineExtensionAmount.Value = subtotal
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Value = invoice.Subtotal
 	ubl.LegalMonetaryTotal.LineExtensionAmount.Currency = invoice.Currency
-	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Value = subtotal
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Value = invoice.Subtotal
 	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Currency = invoice.Currency
 	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Value = invoice.TotalAmount
 	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Currency = invoice.Currency
@@ -228,8 +337,23 @@ func (g *XMLGenerator) GenerateInvoiceXML(invoice *models.Invoice, booking *mode
 	ubl.PaymentMeans.PayeeFinancialAccount.Name = g.config.LegalName
 	ubl.PaymentMeans.PayeeFinancialAccount.FinancialInstitutionBranch.ID = g.config.Bank.SWIFT
 
-	// Generate detailed invoice lines from booking
-	ubl.InvoiceLines = g.generateInvoiceLines(booking, subtotal, invoice.Currency)
+	for _, line := range lines {
+		invoiceLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", line.LineNumber)}
+		invoiceLine.InvoicedQuantity.Value = line.Quantity
+		invoiceLine.InvoicedQuantity.Unit = line.UnitCode
+		invoiceLine.LineExtensionAmount.Value = line.NetAmount
+		invoiceLine.LineExtensionAmount.Currency = invoice.Currency
+		invoiceLine.Item.Name = invoiceLineName(line)
+		invoiceLine.Item.Description = line.Description
+		category := newUBLTaxCategory(g.config.VATRegistered, line.VATRate)
+		invoiceLine.Item.ClassifiedTaxCategory = &category
+		invoiceLine.Price.PriceAmount.Value = line.NetAmount
+		if line.Quantity > 0 {
+			invoiceLine.Price.PriceAmount.Value = roundToCents(line.NetAmount / line.Quantity)
+		}
+		invoiceLine.Price.PriceAmount.Currency = invoice.Currency
+		ubl.InvoiceLines = append(ubl.InvoiceLines, invoiceLine)
+	}
 
 	// Marshal to XML
 	output, err := xml.MarshalIndent(ubl, "", "  ")
@@ -254,130 +378,357 @@ func (g *XMLGenerator) GenerateInvoiceXML(invoice *models.Invoice, booking *mode
 	return filepath, nil
 }
 
-// generateInvoiceLines creates detailed invoice lines from booking information
-func (g *XMLGenerator) generateInvoiceLines(booking *models.Booking, totalSubtotal float64, currency string) []UBLInvoiceLine {
-	if booking == nil {
-		// Fallback to single line if no booking data
-		line := UBLInvoiceLine{ID: "1"}
-		line.InvoicedQuantity.Value = 1
-		line.InvoicedQuantity.Unit = "HUR"
-		line.LineExtensionAmount.Value = totalSubtotal
-		line.LineExtensionAmount.Currency = currency
-		line.Item.Name = "Servicii de curățenie profesionale"
-		line.Item.Description = "Servicii de curățenie"
-		line.Price.PriceAmount.Value = totalSubtotal
-		line.Price.PriceAmount.Currency = currency
-		return []UBLInvoiceLine{line}
-	}
-
-	lines := []UBLInvoiceLine{}
-	lineID := 1
-
-	// Calculate base service price (exclude add-ons from base price)
-	basePrice := totalSubtotal
-	windowPrice := 0.0
-	carpetPrice := 0.0
-
-	// Estimate add-on prices if included
-	if booking.IncludesWindows {
-		windowPrice = float64(booking.NumberOfWindows) * 10 // 10 RON per window estimate
-		basePrice -= windowPrice
-	}
-	if booking.IncludesCarpetCleaning {
-		carpetPrice = float64(booking.CarpetAreaSqm) * 5 // 5 RON per sqm estimate
-		basePrice -= carpetPrice
-	}
-
-	// Ensure base price is not negative
-	if basePrice < 0 {
-		basePrice = totalSubtotal * 0.8 // Default 80% for base service
-		if booking.IncludesWindows {
-			windowPrice = totalSubtotal * 0.1
-		}
-		if booking.IncludesCarpetCleaning {
-			carpetPrice = totalSubtotal * 0.1
-		}
+// invoiceLineName is the short item name of an invoice line, by VAT category
+func invoiceLineName(line *models.InvoiceLine) string {
+	switch line.VATCategory {
+	case VATCategoryAddons:
+		return "Servicii suplimentare de curățenie"
+	case VATCategorySupplies:
+		return "Produse de curățenie"
+	default:
+		return "Servicii de curățenie profesionale"
 	}
+}
 
-	// Line 1: Base cleaning service
-	baseLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", lineID)}
-	baseLine.InvoicedQuantity.Value = float64(booking.EstimatedHours)
-	baseLine.InvoicedQuantity.Unit = "HUR" // Hours
-	baseLine.LineExtensionAmount.Value = basePrice
-	baseLine.LineExtensionAmount.Currency = currency
-
-	// Build service name
-	serviceName := g.translateServiceType(booking.ServiceType)
-	if booking.IncludesDeepCleaning {
-		serviceName += " cu curățenie profundă"
-	}
-	baseLine.Item.Name = serviceName
-
-	// Build description
-	description := fmt.Sprintf("Serviciu de curățenie - %s", serviceName)
-	if booking.AreaSqm.Valid {
-		description += fmt.Sprintf(", Suprafață: %d mp", booking.AreaSqm.Int32)
-	}
-	description += fmt.Sprintf(", Durata: %d ore", booking.EstimatedHours)
-	description += fmt.Sprintf(", Data: %s", booking.ScheduledDate.Format("02.01.2006"))
-	baseLine.Item.Description = description
-
-	baseLine.Price.PriceAmount.Value = basePrice / float64(booking.EstimatedHours)
-	baseLine.Price.PriceAmount.Currency = currency
-	lines = append(lines, baseLine)
-	lineID++
-
-	// Line 2: Window cleaning (if applicable)
-	if booking.IncludesWindows && booking.NumberOfWindows > 0 {
-		windowLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", lineID)}
-		windowLine.InvoicedQuantity.Value = float64(booking.NumberOfWindows)
-		windowLine.InvoicedQuantity.Unit = "C62" // Unit (pieces)
-		windowLine.LineExtensionAmount.Value = windowPrice
-		windowLine.LineExtensionAmount.Currency = currency
-		windowLine.Item.Name = "Curățare geamuri"
-		windowLine.Item.Description = fmt.Sprintf("Curățare geamuri - %d bucăți", booking.NumberOfWindows)
-		windowLine.Price.PriceAmount.Value = windowPrice / float64(booking.NumberOfWindows)
-		windowLine.Price.PriceAmount.Currency = currency
-		lines = append(lines, windowLine)
-		lineID++
-	}
-
-	// Line 3: Carpet cleaning (if applicable)
-	if booking.IncludesCarpetCleaning && booking.CarpetAreaSqm > 0 {
-		carpetLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", lineID)}
-		carpetLine.InvoicedQuantity.Value = float64(booking.CarpetAreaSqm)
-		carpetLine.InvoicedQuantity.Unit = "MTK" // Square meters
-		carpetLine.LineExtensionAmount.Value = carpetPrice
-		carpetLine.LineExtensionAmount.Currency = currency
-		carpetLine.Item.Name = "Curățare covoare/mochetă"
-		carpetLine.Item.Description = fmt.Sprintf("Curățare covoare/mochetă - %d mp", booking.CarpetAreaSqm)
-		carpetLine.Price.PriceAmount.Value = carpetPrice / float64(booking.CarpetAreaSqm)
-		carpetLine.Price.PriceAmount.Currency = currency
-		lines = append(lines, carpetLine)
-	}
-
-	return lines
-}
-
-// translateServiceType translates service type enum to Romanian
-func (g *XMLGenerator) translateServiceType(serviceType models.ServiceType) string {
-	switch serviceType {
-	case models.ServiceTypeStandard:
-		return "Curățenie Standard"
-	case models.ServiceTypeDeepCleaning:
-		return "Curățenie Profundă"
-	case models.ServiceTypeOffice:
-		return "Curățenie Birou"
-	case models.ServiceTypePostRenovation:
-		return "Curățenie Post-Renovare"
-	case models.ServiceTypeMoveInOut:
-		return "Curățenie Mutare"
-	default:
-		return "Servicii de curățenie"
+// invoiceVATGroup is the VAT breakdown of the invoice lines taxed at one rate
+type invoiceVATGroup struct {
+	rate float64
+	net  float64
+	vat  float64
+}
+
+// groupInvoiceLinesByRate sums invoice lines per VAT rate, in the order the rates first appear
+func groupInvoiceLinesByRate(lines []*models.InvoiceLine) []*invoiceVATGroup {
+	var groups []*invoiceVATGroup
+	byRate := map[float64]*invoiceVATGroup{}
+	for _, line := range lines {
+		group, ok := byRate[line.VATRate]
+		if !ok {
+			group = &invoiceVATGroup{rate: line.VATRate}
+			byRate[line.VATRate] = group
+			groups = append(groups, group)
+		}
+		group.net = roundToCents(group.net + line.NetAmount)
+		group.vat = roundToCents(group.vat + line.VATAmount)
 	}
+	return groups
 }
 
 // ReadXML reads an XML file from disk
 func (g *XMLGenerator) ReadXML(filePath string) ([]byte, error) {
 	return os.ReadFile(filePath)
 }
+
+// GenerateSelfBilledInvoiceXML generates the UBL XML of an invoice CleanBuddy issues on behalf of
+// a PFA cleaner (self-billing, type code 389): the cleaner is the supplier and CleanBuddy the
+// customer, with one line per booking paid out
+func (g *XMLGenerator) GenerateSelfBilledInvoiceXML(invoice *models.SelfBilledInvoice, lines []InvoiceDocumentLine, payeeIBAN string) (string, error) {
+	ubl := UBLInvoice{
+		XMLNS:                "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2",
+		CAC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		CBC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		CustomizationID:      "urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1",
+		ID:                   invoice.InvoiceNumber,
+		IssueDate:            invoice.IssueDate.Format("2006-01-02"),
+		DueDate:              invoice.IssueDate.Format("2006-01-02"),
+		InvoiceTypeCode:      "389", // Self-billed invoice
+		DocumentCurrencyCode: invoice.Currency,
+	}
+
+	// Supplier is the cleaner's PFA
+	ubl.AccountingSupplierParty.setName(invoice.SupplierName)
+	street, city, county := splitPartyAddress(invoice.SupplierAddress)
+	ubl.AccountingSupplierParty.setAddress(street, city, county, "", "RO")
+	if invoice.SupplierVATPayer {
+		ubl.AccountingSupplierParty.setVATID(invoice.SupplierCUI)
+	}
+	ubl.AccountingSupplierParty.Party.PartyLegalEntity.CompanyID = utils.NormalizeCUI(invoice.SupplierCUI)
+
+	// Customer is CleanBuddy
+	g.setCompanyParty(&ubl.AccountingCustomerParty)
+
+	// A PFA outside the VAT system invoices without VAT ("O"); a VAT payer at the standard rate
+	category := newUBLTaxCategory(invoice.SupplierVATPayer, g.vatRate(invoice.IssueDate))
+	ubl.TaxTotal.TaxAmount.Value = invoice.TaxAmount
+	ubl.TaxTotal.TaxAmount.Currency = invoice.Currency
+	ubl.TaxTotal.addSubtotal(category, invoice.Subtotal, invoice.TaxAmount, invoice.Currency)
+
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Value = invoice.Subtotal
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Value = invoice.Subtotal
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Value = invoice.TotalAmount
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.PayableAmount.Value = invoice.TotalAmount
+	ubl.LegalMonetaryTotal.PayableAmount.Currency = invoice.Currency
+
+	// Paid by credit transfer to the cleaner's account
+	ubl.PaymentMeans.PaymentMeansCode = "30"
+	ubl.PaymentMeans.PayeeFinancialAccount.ID = payeeIBAN
+	ubl.PaymentMeans.PayeeFinancialAccount.Name = invoice.SupplierName
+
+	for i, line := range lines {
+		// Prices cannot be negative, so a clawback is billed as a negative quantity
+		quantity, price := 1.0, line.NetAmount
+		if price < 0 {
+			quantity, price = -1, -price
+		}
+		invoiceLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", i+1)}
+		invoiceLine.InvoicedQuantity.Value = quantity
+		invoiceLine.InvoicedQuantity.Unit = "C62"
+		invoiceLine.LineExtensionAmount.Value = line.NetAmount
+		invoiceLine.LineExtensionAmount.Currency = invoice.Currency
+		invoiceLine.Item.Name = "Servicii de curatenie"
+		invoiceLine.Item.Description = line.Description
+		lineCategory := category
+		invoiceLine.Item.ClassifiedTaxCategory = &lineCategory
+		invoiceLine.Price.PriceAmount.Value = price
+		invoiceLine.Price.PriceAmount.Currency = invoice.Currency
+		ubl.InvoiceLines = append(ubl.InvoiceLines, invoiceLine)
+	}
+
+	output, err := xml.MarshalIndent(ubl, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal XML: %w", err)
+	}
+
+	filename := fmt.Sprintf("selfbilled_%s_%d.xml", invoice.InvoiceNumber, time.Now().Unix())
+	path := filepath.Join(g.outputDir, filename)
+	if err := os.WriteFile(path, []byte(xml.Header+string(output)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write XML file: %w", err)
+	}
+
+	return path, nil
+}
+
+// splitPartyAddress splits an address stored as "street, city, county" into its parts. The
+// street can itself contain commas, so the city and county are taken from the end.
+func splitPartyAddress(address string) (street, city, county string) {
+	parts := strings.Split(address, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) < 3 {
+		return address, "", ""
+	}
+	n := len(parts)
+	return strings.Join(parts[:n-2], ", "), parts[n-2], parts[n-1]
+}
+
+// vatRate returns the standard (service) VAT rate in force on a date
+func (g *XMLGenerator) vatRate(date time.Time) float64 {
+	if rate, ok := utils.VATRateFor(vatRates(g.config), VATCategoryService, date); ok {
+		return rate
+	}
+	if g.config.VATRate == 0 {
+		return 0.19
+	}
+	return g.config.VATRate
+}
+
+// GenerateCommissionInvoiceXML generates the UBL XML of the platform's commission invoice to a
+// cleaner or company, with one line per cleaner at the VAT rate of the period invoiced
+func (g *XMLGenerator) GenerateCommissionInvoiceXML(invoice *models.CommissionInvoice, lines []InvoiceDocumentLine) (string, error) {
+	ubl := UBLInvoice{
+		XMLNS:                "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2",
+		CAC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		CBC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		CustomizationID:      "urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1",
+		ID:                   invoice.InvoiceNumber,
+		IssueDate:            invoice.IssueDate.Format("2006-01-02"),
+		DueDate:              invoice.DueDate.Format("2006-01-02"),
+		InvoiceTypeCode:      "380", // Commercial invoice
+		DocumentCurrencyCode: invoice.Currency,
+	}
+
+	// Supplier (CleanBuddy)
+	g.setCompanyParty(&ubl.AccountingSupplierParty)
+
+	// Customer (cleaner or company), identified by its VAT identifier when it pays VAT
+	ubl.AccountingCustomerParty.setName(invoice.CustomerName)
+	street, city, county := splitPartyAddress(invoice.CustomerAddress.String)
+	ubl.AccountingCustomerParty.setAddress(street, city, county, "", "RO")
+	if isVATRegistered(invoice.CustomerCUI) {
+		ubl.AccountingCustomerParty.setVATID(invoice.CustomerCUI.String)
+	}
+	if invoice.CustomerCUI.Valid {
+		ubl.AccountingCustomerParty.Party.PartyLegalEntity.CompanyID = utils.NormalizeCUI(invoice.CustomerCUI.String)
+	}
+	ubl.AccountingCustomerParty.Party.Contact.ElectronicMail = invoice.CustomerEmail.String
+
+	category := newUBLTaxCategory(g.config.VATRegistered, invoice.VATRate)
+	ubl.TaxTotal.TaxAmount.Value = invoice.TaxAmount
+	ubl.TaxTotal.TaxAmount.Currency = invoice.Currency
+	ubl.TaxTotal.addSubtotal(category, invoice.Subtotal, invoice.TaxAmount, invoice.Currency)
+
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Value = invoice.Subtotal
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Value = invoice.Subtotal
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Value = invoice.TotalAmount
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Currency = invoice.Currency
+	ubl.LegalMonetaryTotal.PayableAmount.Value = invoice.TotalAmount
+	ubl.LegalMonetaryTotal.PayableAmount.Currency = invoice.Currency
+
+	ubl.PaymentMeans.PaymentMeansCode = "30" // Credit transfer
+	ubl.PaymentMeans.PayeeFinancialAccount.ID = g.config.Bank.IBAN
+	ubl.PaymentMeans.PayeeFinancialAccount.Name = g.config.LegalName
+	ubl.PaymentMeans.PayeeFinancialAccount.FinancialInstitutionBranch.ID = g.config.Bank.SWIFT
+
+	for i, line := range lines {
+		invoiceLine := UBLInvoiceLine{ID: fmt.Sprintf("%d", i+1)}
+		invoiceLine.InvoicedQuantity.Value = 1
+		invoiceLine.InvoicedQuantity.Unit = "C62"
+		invoiceLine.LineExtensionAmount.Value = line.NetAmount
+		invoiceLine.LineExtensionAmount.Currency = invoice.Currency
+		invoiceLine.Item.Name = "Comision intermediere platforma"
+		invoiceLine.Item.Description = line.Description
+		lineCategory := category
+		invoiceLine.Item.ClassifiedTaxCategory = &lineCategory
+		invoiceLine.Price.PriceAmount.Value = line.NetAmount
+		invoiceLine.Price.PriceAmount.Currency = invoice.Currency
+		ubl.InvoiceLines = append(ubl.InvoiceLines, invoiceLine)
+	}
+
+	output, err := xml.MarshalIndent(ubl, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal XML: %w", err)
+	}
+
+	filename := fmt.Sprintf("commission_%s_%d.xml", invoice.InvoiceNumber, time.Now().Unix())
+	path := filepath.Join(g.outputDir, filename)
+	if err := os.WriteFile(path, []byte(xml.Header+string(output)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write XML file: %w", err)
+	}
+
+	return path, nil
+}
+
+// UBLCreditNote is the UBL 2.1 CreditNote document, referencing the invoice it corrects
+type UBLCreditNote struct {
+	XMLName xml.Name `xml:"CreditNote"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	CAC     string   `xml:"xmlns:cac,attr"`
+	CBC     string   `xml:"xmlns:cbc,attr"`
+
+	CustomizationID      string `xml:"cbc:CustomizationID"`
+	ID                   string `xml:"cbc:ID"`
+	IssueDate            string `xml:"cbc:IssueDate"`
+	CreditNoteTypeCode   string `xml:"cbc:CreditNoteTypeCode"`
+	Note                 string `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode string `xml:"cbc:DocumentCurrencyCode"`
+
+	BillingReference struct {
+		InvoiceDocumentReference struct {
+			ID        string `xml:"cbc:ID"`
+			IssueDate string `xml:"cbc:IssueDate"`
+		} `xml:"cac:InvoiceDocumentReference"`
+	} `xml:"cac:BillingReference"`
+
+	AccountingSupplierParty UBLParty `xml:"cac:AccountingSupplierParty"`
+	AccountingCustomerParty UBLParty `xml:"cac:AccountingCustomerParty"`
+
+	TaxTotal           UBLTaxTotal         `xml:"cac:TaxTotal"`
+	LegalMonetaryTotal UBLMonetaryTotal    `xml:"cac:LegalMonetaryTotal"`
+	CreditNoteLines    []UBLCreditNoteLine `xml:"cac:CreditNoteLine"`
+}
+
+type UBLCreditNoteLine struct {
+	ID               string `xml:"cbc:ID"`
+	CreditedQuantity struct {
+		Value float64 `xml:",chardata"`
+		Unit  string  `xml:"unitCode,attr"`
+	} `xml:"cbc:CreditedQuantity"`
+	LineExtensionAmount struct {
+		Value    float64 `xml:",chardata"`
+		Currency string  `xml:"currencyID,attr"`
+	} `xml:"cbc:LineExtensionAmount"`
+	Item struct {
+		Description           string          `xml:"cbc:Description"`
+		Name                  string          `xml:"cbc:Name"`
+		ClassifiedTaxCategory *UBLTaxCategory `xml:"cac:ClassifiedTaxCategory,omitempty"`
+	} `xml:"cac:Item"`
+	Price struct {
+		PriceAmount struct {
+			Value    float64 `xml:",chardata"`
+			Currency string  `xml:"currencyID,attr"`
+		} `xml:"cbc:PriceAmount"`
+	} `xml:"cac:Price"`
+}
+
+// GenerateCreditNoteXML generates the UBL CreditNote (type code 381) for a credit note on a
+// client invoice, with one line and VAT breakdown per rate credited (groups) and the buyer of
+// the invoice. Amounts are positive; the document type makes them a credit.
+func (g *XMLGenerator) GenerateCreditNoteXML(note *models.CreditNote, invoice *models.Invoice, groups []*invoiceVATGroup, buyerAddress *models.Address, billing *models.InvoiceBillingDetails) (string, error) {
+	ubl := UBLCreditNote{
+		XMLNS:                "urn:oasis:names:specification:ubl:schema:xsd:CreditNote-2",
+		CAC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		CBC:                  "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		CustomizationID:      "urn:cen.eu:en16931:2017#compliant#urn:efactura.mfinante.ro:CIUS-RO:1.0.1",
+		ID:                   note.CreditNoteNumber,
+		IssueDate:            note.IssueDate.Format("2006-01-02"),
+		CreditNoteTypeCode:   "381", // Credit note
+		Note:                 note.Reason,
+		DocumentCurrencyCode: note.Currency,
+	}
+	ubl.BillingReference.InvoiceDocumentReference.ID = invoice.InvoiceNumber
+	ubl.BillingReference.InvoiceDocumentReference.IssueDate = invoice.IssueDate.Format("2006-01-02")
+
+	// Supplier (CleanBuddy)
+	g.setCompanyParty(&ubl.AccountingSupplierParty)
+
+	// Customer, as on the original invoice
+	ubl.AccountingCustomerParty.setInvoiceBuyer(invoice, buyerAddress, billing)
+
+	// Credited at the rates of the invoice, one subtotal per rate
+	ubl.TaxTotal.TaxAmount.Value = note.TaxAmount
+	ubl.TaxTotal.TaxAmount.Currency = note.Currency
+	for _, group := range groups {
+		ubl.TaxTotal.addSubtotal(newUBLTaxCategory(g.config.VATRegistered, group.rate), group.net, group.vat, note.Currency)
+	}
+
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Value = note.Subtotal
+	ubl.LegalMonetaryTotal.LineExtensionAmount.Currency = note.Currency
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Value = note.Subtotal
+	ubl.LegalMonetaryTotal.TaxExclusiveAmount.Currency = note.Currency
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Value = note.TotalAmount
+	ubl.LegalMonetaryTotal.TaxInclusiveAmount.Currency = note.Currency
+	ubl.LegalMonetaryTotal.PayableAmount.Value = note.TotalAmount
+	ubl.LegalMonetaryTotal.PayableAmount.Currency = note.Currency
+
+	for i, group := range groups {
+		line := UBLCreditNoteLine{ID: fmt.Sprintf("%d", i+1)}
+		line.CreditedQuantity.Value = 1
+		line.CreditedQuantity.Unit = "C62"
+		line.LineExtensionAmount.Value = group.net
+		line.LineExtensionAmount.Currency = note.Currency
+		line.Item.Name = "Stornare servicii de curatenie"
+		line.Item.Description = creditNoteDescription(note, invoice)
+		category := newUBLTaxCategory(g.config.VATRegistered, group.rate)
+		line.Item.ClassifiedTaxCategory = &category
+		line.Price.PriceAmount.Value = group.net
+		line.Price.PriceAmount.Currency = note.Currency
+		ubl.CreditNoteLines = append(ubl.CreditNoteLines, line)
+	}
+
+	output, err := xml.MarshalIndent(ubl, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal XML: %w", err)
+	}
+
+	filename := fmt.Sprintf("creditnote_%s_%d.xml", note.CreditNoteNumber, time.Now().Unix())
+	path := filepath.Join(g.outputDir, filename)
+	if err := os.WriteFile(path, []byte(xml.Header+string(output)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write XML file: %w", err)
+	}
+
+	return path, nil
+}
+
+// creditNoteDescription describes what a credit note corrects, for its XML and PDF line
+func creditNoteDescription(note *models.CreditNote, invoice *models.Invoice) string {
+	if note.CreditType == models.CreditNoteTypeFull {
+		return fmt.Sprintf("Stornare integrala factura %s din %s", invoice.InvoiceNumber, invoice.IssueDate.Format("02.01.2006"))
+	}
+	return fmt.Sprintf("Stornare partiala factura %s din %s", invoice.InvoiceNumber, invoice.IssueDate.Format("02.01.2006"))
+}
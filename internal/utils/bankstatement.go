@@ -0,0 +1,392 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bank statement formats
+const (
+	BankStatementFormatCAMT053 = "CAMT053"
+	BankStatementFormatMT940   = "MT940"
+)
+
+// Bank statement line directions
+const (
+	BankStatementCredit = "CREDIT" // Incoming money
+	BankStatementDebit  = "DEBIT"  // Outgoing money
+)
+
+// BankStatementLine is a single booked transaction from a bank statement
+type BankStatementLine struct {
+	BookingDate      time.Time
+	Direction        string
+	Amount           float64
+	Currency         string
+	CounterpartyName string
+	CounterpartyIBAN string
+	Reference        string // Remittance information / payment details
+	BankReference    string // Reference assigned by the bank
+	Reversal         bool   // MT940 RD/RC: reverses an earlier transfer, e.g. a returned payout
+}
+
+var ibanPattern = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`)
+
+// DetectBankStatementFormat guesses the statement format from its content
+func DetectBankStatementFormat(data []byte) (string, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return BankStatementFormatCAMT053, nil
+	case bytes.Contains(trimmed, []byte(":61:")):
+		return BankStatementFormatMT940, nil
+	default:
+		return "", fmt.Errorf("unrecognized bank statement format")
+	}
+}
+
+// ParseBankStatement parses a statement in the given format (empty format auto-detects)
+func ParseBankStatement(data []byte, format string) ([]BankStatementLine, error) {
+	if format == "" {
+		detected, err := DetectBankStatementFormat(data)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+
+	switch format {
+	case BankStatementFormatCAMT053:
+		return ParseCAMT053(data)
+	case BankStatementFormatMT940:
+		return ParseMT940(data)
+	default:
+		return nil, fmt.Errorf("unsupported bank statement format: %s", format)
+	}
+}
+
+// --- ISO 20022 CAMT.053 ---
+
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	EntryRef       string          `xml:"NtryRef"`
+	Amount         camtAmount      `xml:"Amt"`
+	CreditDebit    string          `xml:"CdtDbtInd"`
+	Status         camtStatus      `xml:"Sts"`
+	BookingDate    string          `xml:"BookgDt>Dt"`
+	BookingDateTm  string          `xml:"BookgDt>DtTm"`
+	ServicerRef    string          `xml:"AcctSvcrRef"`
+	AddtlEntryInfo string          `xml:"AddtlNtryInf"`
+	Transactions   []camtTxDetails `xml:"NtryDtls>TxDtls"`
+}
+
+// camtStatus is <Sts>BOOK</Sts> up to camt.053.001.07 and <Sts><Cd>BOOK</Cd></Sts> from .08 on
+type camtStatus struct {
+	Code  string `xml:"Cd"`
+	Value string `xml:",chardata"`
+}
+
+func (s camtStatus) code() string {
+	if code := strings.TrimSpace(s.Code); code != "" {
+		return code
+	}
+	return strings.TrimSpace(s.Value)
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtTxDetails struct {
+	Amount       *camtAmount `xml:"Amt"`
+	EndToEndID   string      `xml:"Refs>EndToEndId"`
+	DebtorName   string      `xml:"RltdPties>Dbtr>Nm"`
+	DebtorIBAN   string      `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+	CreditorName string      `xml:"RltdPties>Cdtr>Nm"`
+	CreditorIBAN string      `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+	Unstructured []string    `xml:"RmtInf>Ustrd"`
+	Structured   []string    `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+}
+
+// ParseCAMT053 parses booked entries from an ISO 20022 camt.053 statement
+func ParseCAMT053(data []byte) ([]BankStatementLine, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 XML: %w", err)
+	}
+
+	var lines []BankStatementLine
+	for _, stmt := range doc.Statements {
+		for _, entry := range stmt.Entries {
+			// Pending entries may still change; only booked ones are reconciled
+			if status := entry.Status.code(); status != "" && status != "BOOK" {
+				continue
+			}
+
+			direction := BankStatementCredit
+			if entry.CreditDebit == "DBIT" {
+				direction = BankStatementDebit
+			}
+
+			bookingDate, err := parseCAMTDate(entry.BookingDate, entry.BookingDateTm)
+			if err != nil {
+				return nil, err
+			}
+
+			bankRef := entry.ServicerRef
+			if bankRef == "" {
+				bankRef = entry.EntryRef
+			}
+
+			// Batched entries carry one TxDtls per transfer; each becomes its own line
+			txs := entry.Transactions
+			if len(txs) == 0 {
+				txs = []camtTxDetails{{}}
+			}
+
+			for i, tx := range txs {
+				amt := entry.Amount
+				if tx.Amount != nil && len(txs) > 1 {
+					amt = *tx.Amount
+				}
+				amount, err := parseStatementAmount(amt.Value)
+				if err != nil {
+					return nil, err
+				}
+
+				line := BankStatementLine{
+					BookingDate:   bookingDate,
+					Direction:     direction,
+					Amount:        amount,
+					Currency:      amt.Currency,
+					BankReference: bankRef,
+				}
+				if len(txs) > 1 {
+					line.BankReference = fmt.Sprintf("%s/%d", bankRef, i+1)
+				}
+
+				if direction == BankStatementDebit {
+					line.CounterpartyName = strings.TrimSpace(tx.CreditorName)
+					line.CounterpartyIBAN = normalizeIBAN(tx.CreditorIBAN)
+				} else {
+					line.CounterpartyName = strings.TrimSpace(tx.DebtorName)
+					line.CounterpartyIBAN = normalizeIBAN(tx.DebtorIBAN)
+				}
+
+				refs := append([]string{}, tx.Unstructured...)
+				refs = append(refs, tx.Structured...)
+				if tx.EndToEndID != "" && tx.EndToEndID != "NOTPROVIDED" {
+					refs = append(refs, tx.EndToEndID)
+				}
+				if len(refs) == 0 && entry.AddtlEntryInfo != "" {
+					refs = append(refs, entry.AddtlEntryInfo)
+				}
+				line.Reference = strings.TrimSpace(strings.Join(refs, " "))
+
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+func parseCAMTDate(date, dateTime string) (time.Time, error) {
+	if date != "" {
+		return time.Parse("2006-01-02", date)
+	}
+	if dateTime != "" {
+		return time.Parse(time.RFC3339, dateTime)
+	}
+	return time.Time{}, fmt.Errorf("camt.053 entry has no booking date")
+}
+
+// --- SWIFT MT940 (Banca Transilvania export) ---
+
+// :61: value date (YYMMDD), optional entry date (MMDD), D/C/RD/RC, optional funds code, amount,
+// transaction type (N + 3 chars), customer reference, optional //bank reference
+var mt940StatementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(RD|RC|D|C)[A-Z]?(\d+,\d{0,2})([NSF][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+// ParseMT940 parses transactions from an MT940 statement
+func ParseMT940(data []byte) ([]BankStatementLine, error) {
+	fields, err := splitMT940Fields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := "RON"
+	var lines []BankStatementLine
+	var current *BankStatementLine
+
+	flush := func() {
+		if current != nil {
+			lines = append(lines, *current)
+			current = nil
+		}
+	}
+
+	for _, field := range fields {
+		switch field.tag {
+		case "60F", "60M":
+			// Opening balance: D/C + YYMMDD + currency + amount
+			if len(field.value) >= 10 {
+				currency = field.value[7:10]
+			}
+		case "61":
+			flush()
+			line, err := parseMT940StatementLine(field.value, currency)
+			if err != nil {
+				return nil, err
+			}
+			current = line
+		case "86":
+			if current != nil {
+				applyMT940Details(current, field.value)
+			}
+		}
+	}
+	flush()
+
+	return lines, nil
+}
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// splitMT940Fields groups the statement into :TAG: fields, joining continuation lines
+func splitMT940Fields(data []byte) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	tagPattern := regexp.MustCompile(`^:([0-9]{2}[A-Z]?):(.*)$`)
+
+	for scanner.Scan() {
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		if raw == "" || raw == "-" || strings.HasPrefix(raw, "{") {
+			continue
+		}
+		if m := tagPattern.FindStringSubmatch(raw); m != nil {
+			fields = append(fields, mt940Field{tag: m[1], value: m[2]})
+			continue
+		}
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + raw
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read MT940 statement: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid MT940 statement: no fields found")
+	}
+
+	return fields, nil
+}
+
+func parseMT940StatementLine(value, currency string) (*BankStatementLine, error) {
+	first := strings.SplitN(value, "\n", 2)[0]
+	m := mt940StatementLine.FindStringSubmatch(first)
+	if m == nil {
+		return nil, fmt.Errorf("invalid MT940 :61: line: %q", first)
+	}
+
+	bookingDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid MT940 value date %q: %w", m[1], err)
+	}
+
+	amount, err := parseStatementAmount(m[4])
+	if err != nil {
+		return nil, err
+	}
+
+	// RD/RC are reversals: a reversed debit is money coming back in
+	direction := BankStatementCredit
+	if m[3] == "D" || m[3] == "RC" {
+		direction = BankStatementDebit
+	}
+
+	bankRef := strings.TrimSpace(m[7])
+	customerRef := strings.TrimSpace(m[6])
+	if bankRef == "" && customerRef != "NONREF" {
+		bankRef = customerRef
+	}
+
+	line := &BankStatementLine{
+		BookingDate:   bookingDate,
+		Direction:     direction,
+		Amount:        amount,
+		Currency:      currency,
+		BankReference: bankRef,
+		Reversal:      strings.HasPrefix(m[3], "R"),
+	}
+	if customerRef != "" && customerRef != "NONREF" {
+		line.Reference = customerRef
+	}
+
+	return line, nil
+}
+
+// applyMT940Details reads the :86: information field.
+// Structured ?NN subfields are used when present (?20-?29 details, ?31 account, ?32-?33 name);
+// otherwise the free text is kept as reference and the first IBAN in it is taken as counterparty.
+func applyMT940Details(line *BankStatementLine, value string) {
+	text := strings.ReplaceAll(value, "\n", "")
+
+	if strings.Contains(text, "?2") || strings.Contains(text, "?3") {
+		var details, name []string
+		for _, part := range strings.Split(text, "?")[1:] {
+			if len(part) < 2 {
+				continue
+			}
+			code, content := part[:2], strings.TrimSpace(part[2:])
+			switch {
+			case code >= "20" && code <= "29":
+				details = append(details, content)
+			case code == "31":
+				line.CounterpartyIBAN = normalizeIBAN(content)
+			case code == "32" || code == "33":
+				name = append(name, content)
+			}
+		}
+		line.CounterpartyName = strings.TrimSpace(strings.Join(name, " "))
+		line.Reference = strings.TrimSpace(strings.Join(append([]string{line.Reference}, details...), " "))
+		return
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(value, "\n", " "))
+	if iban := ibanPattern.FindString(text); iban != "" {
+		line.CounterpartyIBAN = iban
+	}
+	line.Reference = strings.TrimSpace(line.Reference + " " + text)
+}
+
+// parseStatementAmount accepts both "1234.56" and "1234,56"
+func parseStatementAmount(value string) (float64, error) {
+	normalized := strings.ReplaceAll(strings.TrimSpace(value), ",", ".")
+	amount, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid statement amount %q: %w", value, err)
+	}
+	return amount, nil
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(iban), " ", ""))
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+const testCAMT053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-2025-01</Id>
+      <Ntry>
+        <Amt Ccy="RON">850.50</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2025-02-03</Dt></BookgDt>
+        <AcctSvcrRef>BT123456</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>PAYOUT-abc</EndToEndId></Refs>
+            <RltdPties>
+              <Cdtr><Nm>Maria Popescu</Nm></Cdtr>
+              <CdtrAcct><Id><IBAN>RO49AAAA1B31007593840000</IBAN></Id></CdtrAcct>
+            </RltdPties>
+            <RmtInf><Ustrd>CleanBuddy payout ianuarie</Ustrd></RmtInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="RON">300.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <BookgDt><Dt>2025-02-04</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+const testCAMT053v08 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-2025-09</Id>
+      <Ntry>
+        <Amt Ccy="RON">1190.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2025-09-02</Dt></BookgDt>
+        <AcctSvcrRef>BT777001</AcctSvcrRef>
+        <AddtlNtryInf>Plata factura INV-2025-2001</AddtlNtryInf>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="RON">300.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>PDNG</Cd></Sts>
+        <BookgDt><Dt>2025-09-03</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+const testMT940 = `:20:BT20250203
+:25:RO12BTRLRONCRT0000000001
+:28C:00001/001
+:60F:C250131RON10000,00
+:61:2502030203C1190,00NTRFNONREF//BT998877
+:86:Plata factura INV-2025-1042 SC Client SRL RO66BACX0000001234567890
+:61:2502030203D500,00NTRFPAYOUT-xyz//BT998878
+:86:?20Payout CleanBuddy?31RO49AAAA1B31007593840000?32Ion Ionescu
+:62F:C250203RON10690,00
+-`
+
+func TestParseCAMT053(t *testing.T) {
+	lines, err := ParseBankStatement([]byte(testCAMT053), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The pending entry must be skipped
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 booked line, got %d", len(lines))
+	}
+
+	line := lines[0]
+	if line.Direction != BankStatementDebit || line.Amount != 850.50 || line.Currency != "RON" {
+		t.Errorf("unexpected amount/direction: %+v", line)
+	}
+	if !line.BookingDate.Equal(time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected booking date: %v", line.BookingDate)
+	}
+	if line.CounterpartyIBAN != "RO49AAAA1B31007593840000" || line.CounterpartyName != "Maria Popescu" {
+		t.Errorf("unexpected counterparty: %+v", line)
+	}
+	if line.BankReference != "BT123456" || line.Reference != "CleanBuddy payout ianuarie PAYOUT-abc" {
+		t.Errorf("unexpected references: %+v", line)
+	}
+}
+
+func TestParseCAMT053CodedStatus(t *testing.T) {
+	lines, err := ParseBankStatement([]byte(testCAMT053v08), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// <Sts><Cd>BOOK</Cd></Sts> is booked, <Sts><Cd>PDNG</Cd></Sts> is skipped
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 booked line, got %d", len(lines))
+	}
+	if lines[0].Amount != 1190 || lines[0].BankReference != "BT777001" || lines[0].Reference != "Plata factura INV-2025-2001" {
+		t.Errorf("unexpected line: %+v", lines[0])
+	}
+}
+
+func TestParseMT940Reversal(t *testing.T) {
+	statement := `:20:BT20250210
+:25:RO12BTRLRONCRT0000000001
+:60F:C250209RON10000,00
+:61:2502100210RD500,00NTRFPAYOUT-xyz//BT998901
+:86:?20Retur payout cont inchis?31RO49AAAA1B31007593840000?32Ion Ionescu
+-`
+
+	lines, err := ParseBankStatement([]byte(statement), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d", len(lines))
+	}
+
+	// A reversed debit is money coming back in
+	line := lines[0]
+	if !line.Reversal || line.Direction != BankStatementCredit || line.Amount != 500 {
+		t.Errorf("unexpected reversal line: %+v", line)
+	}
+}
+
+func TestParseMT940(t *testing.T) {
+	lines, err := ParseBankStatement([]byte(testMT940), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	incoming := lines[0]
+	if incoming.Direction != BankStatementCredit || incoming.Amount != 1190 || incoming.Currency != "RON" {
+		t.Errorf("unexpected incoming line: %+v", incoming)
+	}
+	if incoming.BankReference != "BT998877" || incoming.CounterpartyIBAN != "RO66BACX0000001234567890" {
+		t.Errorf("unexpected incoming references: %+v", incoming)
+	}
+
+	if incoming.Reversal {
+		t.Errorf("plain credit parsed as reversal: %+v", incoming)
+	}
+
+	outgoing := lines[1]
+	if outgoing.Direction != BankStatementDebit || outgoing.Amount != 500 {
+		t.Errorf("unexpected outgoing line: %+v", outgoing)
+	}
+	if outgoing.CounterpartyIBAN != "RO49AAAA1B31007593840000" || outgoing.CounterpartyName != "Ion Ionescu" {
+		t.Errorf("unexpected counterparty: %+v", outgoing)
+	}
+	if outgoing.Reference != "PAYOUT-xyz Payout CleanBuddy" {
+		t.Errorf("unexpected reference: %q", outgoing.Reference)
+	}
+}
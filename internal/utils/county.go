@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"strings"
+)
+
+// romanianCounties maps county names, without diacritics and in lower case, to their
+// ISO 3166-2:RO subdivision codes
+var romanianCounties = map[string]string{
+	"alba":            "AB",
+	"arad":            "AR",
+	"arges":           "AG",
+	"bacau":           "BC",
+	"bihor":           "BH",
+	"bistrita-nasaud": "BN",
+	"botosani":        "BT",
+	"braila":          "BR",
+	"brasov":          "BV",
+	"bucuresti":       "B",
+	"buzau":           "BZ",
+	"calarasi":        "CL",
+	"caras-severin":   "CS",
+	"cluj":            "CJ",
+	"constanta":       "CT",
+	"covasna":         "CV",
+	"dambovita":       "DB",
+	"dolj":            "DJ",
+	"galati":          "GL",
+	"giurgiu":         "GR",
+	"gorj":            "GJ",
+	"harghita":        "HR",
+	"hunedoara":       "HD",
+	"ialomita":        "IL",
+	"iasi":            "IS",
+	"ilfov":           "IF",
+	"maramures":       "MM",
+	"mehedinti":       "MH",
+	"mures":           "MS",
+	"neamt":           "NT",
+	"olt":             "OT",
+	"prahova":         "PH",
+	"salaj":           "SJ",
+	"satu mare":       "SM",
+	"sibiu":           "SB",
+	"suceava":         "SV",
+	"teleorman":       "TR",
+	"timis":           "TM",
+	"tulcea":          "TL",
+	"valcea":          "VL",
+	"vaslui":          "VS",
+	"vrancea":         "VN",
+}
+
+// RomanianCountyCode returns the ISO 3166-2:RO code (e.g. RO-CJ, RO-B for Bucharest) of a county
+// given by name, with or without diacritics, or already as a code
+func RomanianCountyCode(county string) (string, bool) {
+	name := strings.ToLower(StripDiacritics(strings.TrimSpace(county)))
+	name = strings.Join(strings.Fields(strings.ReplaceAll(name, " - ", "-")), " ")
+	name = strings.TrimPrefix(name, "judetul ")
+	name = strings.TrimPrefix(name, "municipiul ")
+	if name == "bucharest" || strings.HasPrefix(name, "sector") {
+		name = "bucuresti"
+	}
+	if code, ok := romanianCounties[name]; ok {
+		return "RO-" + code, true
+	}
+
+	code := "RO-" + strings.TrimPrefix(strings.ToUpper(name), "RO-")
+	if IsRomanianCountyCode(code) {
+		return code, true
+	}
+	return "", false
+}
+
+// IsRomanianCountyCode reports whether code is an ISO 3166-2:RO county code such as RO-CJ
+func IsRomanianCountyCode(code string) bool {
+	if !strings.HasPrefix(code, "RO-") {
+		return false
+	}
+	for _, c := range romanianCounties {
+		if code[3:] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// BucharestSector returns the Bucharest sector of an address as e-Factura codes it (SECTOR1 to
+// SECTOR6), from a city such as "Sector 3" or else from the postal code, whose first two digits
+// are the sector
+func BucharestSector(city, postalCode string) (string, bool) {
+	name := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(city), " ", ""))
+	if len(name) == len("SECTOR1") && strings.HasPrefix(name, "SECTOR") && name[6] >= '1' && name[6] <= '6' {
+		return name, true
+	}
+
+	postalCode = strings.TrimSpace(postalCode)
+	if len(postalCode) == 6 && postalCode[0] == '0' && postalCode[1] >= '1' && postalCode[1] <= '6' {
+		return "SECTOR" + postalCode[1:2], true
+	}
+	return "", false
+}
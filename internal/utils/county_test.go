@@ -0,0 +1,50 @@
+package utils
+
+import "testing"
+
+func TestRomanianCountyCode(t *testing.T) {
+	tests := []struct {
+		county string
+		code   string
+		ok     bool
+	}{
+		{"Cluj", "RO-CJ", true},
+		{"București", "RO-B", true},
+		{"Municipiul Bucuresti", "RO-B", true},
+		{"Sector 3", "RO-B", true},
+		{"Caraș - Severin", "RO-CS", true},
+		{"Județul Satu  Mare", "RO-SM", true},
+		{"RO-IS", "RO-IS", true},
+		{"tm", "RO-TM", true},
+		{"Atlantis", "", false},
+		{"RO-XX", "", false},
+	}
+
+	for _, tt := range tests {
+		code, ok := RomanianCountyCode(tt.county)
+		if code != tt.code || ok != tt.ok {
+			t.Errorf("RomanianCountyCode(%q) = %q, %v; want %q, %v", tt.county, code, ok, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestBucharestSector(t *testing.T) {
+	tests := []struct {
+		city, postalCode string
+		sector           string
+		ok               bool
+	}{
+		{"Sector 2", "", "SECTOR2", true},
+		{"SECTOR6", "", "SECTOR6", true},
+		{"București", "030167", "SECTOR3", true},
+		{"București", "", "", false},
+		{"București", "077190", "", false}, // Ilfov
+	}
+
+	for _, tt := range tests {
+		sector, ok := BucharestSector(tt.city, tt.postalCode)
+		if sector != tt.sector || ok != tt.ok {
+			t.Errorf("BucharestSector(%q, %q) = %q, %v; want %q, %v", tt.city, tt.postalCode, sector, ok, tt.sector, tt.ok)
+		}
+	}
+}
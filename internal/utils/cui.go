@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cuiControlKey is the weighting key of the Romanian fiscal code (CUI/CIF) control digit
+const cuiControlKey = "753217532"
+
+// NormalizeCUI strips spaces and the optional RO prefix, returning the digits of a CUI
+func NormalizeCUI(cui string) string {
+	cui = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(cui), " ", ""))
+	return strings.TrimPrefix(cui, "RO")
+}
+
+// ValidateCUI checks the length and control digit of a Romanian CUI, with or without RO prefix
+func ValidateCUI(cui string) error {
+	digits := NormalizeCUI(cui)
+	if len(digits) < 2 || len(digits) > 10 {
+		return fmt.Errorf("CUI must have between 2 and 10 digits")
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("CUI must contain only digits")
+		}
+	}
+
+	body := fmt.Sprintf("%09s", digits[:len(digits)-1])
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * int(cuiControlKey[i]-'0')
+	}
+	control := sum * 10 % 11
+	if control == 10 {
+		control = 0
+	}
+	if int(digits[len(digits)-1]-'0') != control {
+		return fmt.Errorf("invalid CUI control digit")
+	}
+	return nil
+}
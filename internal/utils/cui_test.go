@@ -0,0 +1,38 @@
+package utils
+
+import "testing"
+
+func TestValidateCUI(t *testing.T) {
+	tests := []struct {
+		name  string
+		cui   string
+		valid bool
+	}{
+		{name: "Valid without prefix", cui: "14399840", valid: true},
+		{name: "Valid with RO prefix", cui: "RO18547290", valid: true},
+		{name: "Valid with spaces and lowercase prefix", cui: " ro 1590082 ", valid: true},
+		{name: "Wrong control digit", cui: "14399841", valid: false},
+		{name: "Too short", cui: "1", valid: false},
+		{name: "Too long", cui: "12345678901", valid: false},
+		{name: "Letters", cui: "1439A840", valid: false},
+		{name: "Empty", cui: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCUI(tt.cui)
+			if tt.valid && err != nil {
+				t.Errorf("ValidateCUI(%q) returned error: %v", tt.cui, err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("ValidateCUI(%q) expected an error", tt.cui)
+			}
+		})
+	}
+}
+
+func TestNormalizeCUI(t *testing.T) {
+	if got := NormalizeCUI(" ro 18547290"); got != "18547290" {
+		t.Errorf("NormalizeCUI() = %q, want %q", got, "18547290")
+	}
+}
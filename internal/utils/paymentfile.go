@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Bulk payment file formats
+const (
+	PaymentFileFormatPain001 = "PAIN001" // ISO 20022 pain.001.001.03 customer credit transfer
+	PaymentFileFormatBTCSV   = "BT_CSV"  // Banca Transilvania multiple payments import
+	PaymentFileFormatBCRCSV  = "BCR_CSV" // BCR multiple payments import
+)
+
+// PaymentDebtor is the account the transfers are paid from
+type PaymentDebtor struct {
+	Name string
+	IBAN string
+	BIC  string
+}
+
+// CreditTransfer is one outgoing transfer in a payment file
+type CreditTransfer struct {
+	EndToEndID     string // Max 35 characters
+	CreditorName   string
+	CreditorIBAN   string
+	Amount         float64
+	Currency       string
+	RemittanceInfo string // Payment details shown to the beneficiary
+}
+
+// PaymentBatch is a set of transfers submitted to the bank in one file
+type PaymentBatch struct {
+	MessageID     string // Max 35 characters
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	Debtor        PaymentDebtor
+	Transfers     []CreditTransfer
+}
+
+// ControlSum returns the batch total, added up in cents
+func (b *PaymentBatch) ControlSum() float64 {
+	var cents int64
+	for _, t := range b.Transfers {
+		cents += int64(math.Round(t.Amount * 100))
+	}
+	return float64(cents) / 100
+}
+
+// BuildPaymentFile renders a batch in the given format
+func BuildPaymentFile(batch *PaymentBatch, format string) ([]byte, error) {
+	if len(batch.Transfers) == 0 {
+		return nil, fmt.Errorf("payment batch has no transfers")
+	}
+
+	switch format {
+	case PaymentFileFormatPain001:
+		return BuildPain001(batch)
+	case PaymentFileFormatBTCSV:
+		return BuildBTPaymentCSV(batch)
+	case PaymentFileFormatBCRCSV:
+		return BuildBCRPaymentCSV(batch)
+	default:
+		return nil, fmt.Errorf("unsupported payment file format: %s", format)
+	}
+}
+
+// PaymentFileName returns the file name and content type for a batch export
+func PaymentFileName(reference, format string) (string, string) {
+	if format == PaymentFileFormatPain001 {
+		return reference + ".xml", "application/xml"
+	}
+	return reference + ".csv", "text/csv"
+}
+
+// --- ISO 20022 pain.001.001.03 ---
+
+type painDocument struct {
+	XMLName  xml.Name     `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Initiate painInitiate `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiate struct {
+	GroupHeader painGroupHeader `xml:"GrpHdr"`
+	PaymentInfo painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MessageID     string `xml:"MsgId"`
+	CreatedAt     string `xml:"CreDtTm"`
+	Transactions  int    `xml:"NbOfTxs"`
+	ControlSum    string `xml:"CtrlSum"`
+	InitiatorName string `xml:"InitgPty>Nm"`
+}
+
+type painPaymentInfo struct {
+	PaymentInfoID string               `xml:"PmtInfId"`
+	Method        string               `xml:"PmtMtd"`
+	BatchBooking  bool                 `xml:"BtchBookg"`
+	Transactions  int                  `xml:"NbOfTxs"`
+	ControlSum    string               `xml:"CtrlSum"`
+	ServiceLevel  string               `xml:"PmtTpInf>SvcLvl>Cd,omitempty"`
+	ExecutionDate string               `xml:"ReqdExctnDt"`
+	DebtorName    string               `xml:"Dbtr>Nm"`
+	DebtorIBAN    string               `xml:"DbtrAcct>Id>IBAN"`
+	DebtorBIC     string               `xml:"DbtrAgt>FinInstnId>BIC,omitempty"`
+	ChargeBearer  string               `xml:"ChrgBr"`
+	Transfers     []painCreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+type painCreditTransfer struct {
+	EndToEndID   string     `xml:"PmtId>EndToEndId"`
+	Amount       painAmount `xml:"Amt>InstdAmt"`
+	CreditorName string     `xml:"Cdtr>Nm"`
+	CreditorIBAN string     `xml:"CdtrAcct>Id>IBAN"`
+	Remittance   string     `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type painAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// BuildPain001 renders a batch as a pain.001.001.03 credit transfer initiation.
+// All transfers go in one payment information block, booked as a single debit.
+func BuildPain001(batch *PaymentBatch) ([]byte, error) {
+	controlSum := formatPaymentAmount(batch.ControlSum())
+
+	info := painPaymentInfo{
+		PaymentInfoID: batch.MessageID,
+		Method:        "TRF",
+		BatchBooking:  true,
+		Transactions:  len(batch.Transfers),
+		ControlSum:    controlSum,
+		ExecutionDate: batch.ExecutionDate.Format("2006-01-02"),
+		DebtorName:    PaymentText(batch.Debtor.Name, 70),
+		DebtorIBAN:    compactIBAN(batch.Debtor.IBAN),
+		DebtorBIC:     batch.Debtor.BIC,
+		ChargeBearer:  "SLEV",
+	}
+
+	sepa := true
+	for _, t := range batch.Transfers {
+		if t.Currency != "EUR" {
+			sepa = false
+		}
+		info.Transfers = append(info.Transfers, painCreditTransfer{
+			EndToEndID:   PaymentText(t.EndToEndID, 35),
+			Amount:       painAmount{Currency: t.Currency, Value: formatPaymentAmount(t.Amount)},
+			CreditorName: PaymentText(t.CreditorName, 70),
+			CreditorIBAN: compactIBAN(t.CreditorIBAN),
+			Remittance:   PaymentText(t.RemittanceInfo, 140),
+		})
+	}
+	// The SEPA service level only applies to euro transfers; domestic RON transfers go without it
+	if sepa {
+		info.ServiceLevel = "SEPA"
+	}
+
+	doc := painDocument{
+		Initiate: painInitiate{
+			GroupHeader: painGroupHeader{
+				MessageID:     batch.MessageID,
+				CreatedAt:     batch.CreatedAt.Format("2006-01-02T15:04:05"),
+				Transactions:  len(batch.Transfers),
+				ControlSum:    controlSum,
+				InitiatorName: PaymentText(batch.Debtor.Name, 70),
+			},
+			PaymentInfo: info,
+		},
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pain.001: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// --- Romanian bank multiple payment imports ---
+
+// BuildBTPaymentCSV renders a batch in the Banca Transilvania multiple payments import layout
+func BuildBTPaymentCSV(batch *PaymentBatch) ([]byte, error) {
+	header := []string{"Nume beneficiar", "IBAN beneficiar", "Suma", "Moneda", "Detalii plata", "Referinta"}
+	return writePaymentCSV(',', header, batch, func(t CreditTransfer) []string {
+		return []string{
+			PaymentText(t.CreditorName, 70),
+			compactIBAN(t.CreditorIBAN),
+			formatPaymentAmount(t.Amount),
+			t.Currency,
+			PaymentText(t.RemittanceInfo, 140),
+			PaymentText(t.EndToEndID, 35),
+		}
+	})
+}
+
+// BuildBCRPaymentCSV renders a batch in the BCR multiple payments import layout,
+// which also names the paying account on every row
+func BuildBCRPaymentCSV(batch *PaymentBatch) ([]byte, error) {
+	header := []string{"Cont platitor", "Nume beneficiar", "Cont beneficiar", "Suma", "Valuta", "Data plata", "Explicatii", "Referinta client"}
+	debtorIBAN := compactIBAN(batch.Debtor.IBAN)
+	executionDate := batch.ExecutionDate.Format("02.01.2006")
+	return writePaymentCSV(';', header, batch, func(t CreditTransfer) []string {
+		return []string{
+			debtorIBAN,
+			PaymentText(t.CreditorName, 70),
+			compactIBAN(t.CreditorIBAN),
+			formatPaymentAmount(t.Amount),
+			t.Currency,
+			executionDate,
+			PaymentText(t.RemittanceInfo, 140),
+			PaymentText(t.EndToEndID, 35),
+		}
+	})
+}
+
+func writePaymentCSV(separator rune, header []string, batch *PaymentBatch, row func(CreditTransfer) []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = separator
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, t := range batch.Transfers {
+		if err := w.Write(row(t)); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render payment CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PaymentText restricts text to the character set banks accept in payment files
+// (Latin letters, digits and / - ? : ( ) . , ' + space), transliterating Romanian
+// diacritics and truncating to maxLen
+func PaymentText(s string, maxLen int) string {
+	s = StripDiacritics(s)
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune("/-?:().,'+ ", r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	out := strings.Join(strings.Fields(b.String()), " ")
+	if len(out) > maxLen {
+		out = strings.TrimSpace(out[:maxLen])
+	}
+	return out
+}
+
+func compactIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+func formatPaymentAmount(amount float64) string {
+	return fmt.Sprintf("%.2f", math.Round(amount*100)/100)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPaymentBatch() *PaymentBatch {
+	return &PaymentBatch{
+		MessageID:     "PB-20250203-0001",
+		CreatedAt:     time.Date(2025, 2, 3, 10, 30, 0, 0, time.UTC),
+		ExecutionDate: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+		Debtor:        PaymentDebtor{Name: "CleanBuddy SRL", IBAN: "RO12 BTRL RONC RT00 0000 0001", BIC: "BTRLRO22"},
+		Transfers: []CreditTransfer{
+			{EndToEndID: "payout1", CreditorName: "Maria Popescu", CreditorIBAN: "RO49AAAA1B31007593840000", Amount: 850.5, Currency: "RON", RemittanceInfo: "CleanBuddy payout 01.2025"},
+			{EndToEndID: "payout2", CreditorName: "Ștefan Țăranu", CreditorIBAN: "RO66BACX0000001234567890", Amount: 0.1 + 0.2, Currency: "RON", RemittanceInfo: "CleanBuddy payout 01.2025"},
+		},
+	}
+}
+
+func TestBuildPain001(t *testing.T) {
+	out, err := BuildPaymentFile(testPaymentBatch(), PaymentFileFormatPain001)
+	if err != nil {
+		t.Fatalf("BuildPaymentFile() error = %v", err)
+	}
+
+	var doc painDocument
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+
+	header := doc.Initiate.GroupHeader
+	if header.Transactions != 2 || header.ControlSum != "850.80" {
+		t.Errorf("group header = %d txs / %s, want 2 / 850.80", header.Transactions, header.ControlSum)
+	}
+
+	info := doc.Initiate.PaymentInfo
+	if info.DebtorIBAN != "RO12BTRLRONCRT0000000001" {
+		t.Errorf("debtor IBAN = %q", info.DebtorIBAN)
+	}
+	if info.ServiceLevel != "" {
+		t.Errorf("RON batch has service level %q, want none", info.ServiceLevel)
+	}
+	if got := info.Transfers[1]; got.CreditorName != "Stefan Taranu" || got.Amount.Value != "0.30" || got.Amount.Currency != "RON" {
+		t.Errorf("second transfer = %+v", got)
+	}
+	if !strings.Contains(string(out), "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03") {
+		t.Error("missing pain.001.001.03 namespace")
+	}
+}
+
+func TestBuildPaymentCSV(t *testing.T) {
+	tests := []struct {
+		format    string
+		separator rune
+		ibanCol   int
+		amountCol int
+	}{
+		{PaymentFileFormatBTCSV, ',', 1, 2},
+		{PaymentFileFormatBCRCSV, ';', 2, 3},
+	}
+
+	for _, tt := range tests {
+		out, err := BuildPaymentFile(testPaymentBatch(), tt.format)
+		if err != nil {
+			t.Fatalf("%s: BuildPaymentFile() error = %v", tt.format, err)
+		}
+
+		r := csv.NewReader(strings.NewReader(string(out)))
+		r.Comma = tt.separator
+		rows, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("%s: invalid CSV: %v", tt.format, err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("%s: got %d rows, want header + 2", tt.format, len(rows))
+		}
+		if rows[1][tt.ibanCol] != "RO49AAAA1B31007593840000" || rows[1][tt.amountCol] != "850.50" {
+			t.Errorf("%s: first row = %v", tt.format, rows[1])
+		}
+	}
+}
+
+func TestBuildPaymentFileEmpty(t *testing.T) {
+	batch := testPaymentBatch()
+	batch.Transfers = nil
+	if _, err := BuildPaymentFile(batch, PaymentFileFormatPain001); err == nil {
+		t.Error("expected an error for an empty batch")
+	}
+	if _, err := BuildPaymentFile(testPaymentBatch(), "MT101"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestPaymentText(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"Ioana Mărgărit", 70, "Ioana Margarit"},
+		{"Plată #42 & co", 70, "Plata 42 co"},
+		{"  spaced   out  ", 70, "spaced out"},
+		{"abcdefghij", 5, "abcde"},
+	}
+	for _, tt := range tests {
+		if got := PaymentText(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("PaymentText(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "strings"
+
+var diacriticReplacer = strings.NewReplacer(
+	"ă", "a", "â", "a", "î", "i", "ș", "s", "ş", "s", "ț", "t", "ţ", "t",
+	"Ă", "A", "Â", "A", "Î", "I", "Ș", "S", "Ş", "S", "Ț", "T", "Ţ", "T",
+)
+
+// StripDiacritics replaces Romanian diacritics with their plain Latin letters,
+// for output that only supports ASCII (bank files, core PDF fonts)
+func StripDiacritics(s string) string {
+	return diacriticReplacer.Replace(s)
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// VATRate is a VAT rate applying to one category of supplies from a given date
+type VATRate struct {
+	Category      string // Empty applies to every category without a rate of its own
+	Rate          float64
+	EffectiveFrom time.Time
+}
+
+// VATRateFor returns the rate in force for a category on a date: the most recent entry for
+// the category effective on or before the date, falling back to the entries without category
+func VATRateFor(rates []VATRate, category string, date time.Time) (float64, bool) {
+	if rate, ok := latestVATRate(rates, category, date); ok {
+		return rate, true
+	}
+	if category != "" {
+		return latestVATRate(rates, "", date)
+	}
+	return 0, false
+}
+
+func latestVATRate(rates []VATRate, category string, date time.Time) (float64, bool) {
+	var found *VATRate
+	for i := range rates {
+		r := &rates[i]
+		if r.Category != category || r.EffectiveFrom.After(date) {
+			continue
+		}
+		if found == nil || r.EffectiveFrom.After(found.EffectiveFrom) {
+			found = r
+		}
+	}
+	if found == nil {
+		return 0, false
+	}
+	return found.Rate, true
+}
+
+// SplitGross splits a VAT-inclusive amount into net amount and VAT, rounded to cents, with
+// net + VAT equal to the gross amount. It prefers the net amount whose VAT rounds exactly to
+// net × rate, as EN 16931 requires of each VAT breakdown; when no cent amount does, the VAT
+// is the remainder.
+func SplitGross(gross, rate float64) (net, vat float64) {
+	gross = roundCents(gross)
+	net = roundCents(gross / (1 + rate))
+	for _, candidate := range []float64{net, roundCents(net - 0.01), roundCents(net + 0.01)} {
+		if roundCents(candidate+AddVAT(candidate, rate)) == gross {
+			return candidate, AddVAT(candidate, rate)
+		}
+	}
+	return net, roundCents(gross - net)
+}
+
+// AddVAT returns the VAT due on a net amount, rounded to cents
+func AddVAT(net, rate float64) float64 {
+	return roundCents(net * rate)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVATRateFor(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	rates := []VATRate{
+		{Rate: 0.19, EffectiveFrom: date(2017, 1, 1)},
+		{Rate: 0.21, EffectiveFrom: date(2025, 8, 1)},
+		{Category: "supplies", Rate: 0.09, EffectiveFrom: date(2017, 1, 1)},
+		{Category: "supplies", Rate: 0.11, EffectiveFrom: date(2025, 8, 1)},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		date     time.Time
+		expected float64
+		ok       bool
+	}{
+		{"standard before change", "service", date(2025, 7, 31), 0.19, true},
+		{"standard on change date", "service", date(2025, 8, 1), 0.21, true},
+		{"category rate before change", "supplies", date(2025, 7, 31), 0.09, true},
+		{"category rate after change", "supplies", date(2026, 1, 15), 0.11, true},
+		{"before any rate", "service", date(2016, 12, 31), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := VATRateFor(rates, tt.category, tt.date)
+			if ok != tt.ok || rate != tt.expected {
+				t.Errorf("VATRateFor(%q, %s) = %v, %v; want %v, %v",
+					tt.category, tt.date.Format("2006-01-02"), rate, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSplitGross(t *testing.T) {
+	tests := []struct {
+		gross, rate, net, vat float64
+		exact                 bool // VAT equals net × rate rounded
+	}{
+		{119, 0.19, 100, 19, true},
+		{121, 0.21, 100, 21, true},
+		{150, 0.21, 123.97, 26.03, true},
+		{99.99, 0.19, 84.03, 15.96, false}, // No cent amount splits exactly
+		{50, 0, 50, 0, true},
+	}
+
+	for _, tt := range tests {
+		net, vat := SplitGross(tt.gross, tt.rate)
+		if net != tt.net || vat != tt.vat {
+			t.Errorf("SplitGross(%v, %v) = %v, %v; want %v, %v", tt.gross, tt.rate, net, vat, tt.net, tt.vat)
+		}
+		if tt.exact && vat != AddVAT(net, tt.rate) {
+			t.Errorf("SplitGross(%v, %v): VAT %v differs from net × rate", tt.gross, tt.rate, vat)
+		}
+		if roundCents(net+vat) != tt.gross {
+			t.Errorf("SplitGross(%v, %v): net + vat = %v, want the gross amount", tt.gross, tt.rate, net+vat)
+		}
+	}
+}
+
+func TestAddVAT(t *testing.T) {
+	if vat := AddVAT(123.97, 0.21); vat != 26.03 {
+		t.Errorf("AddVAT(123.97, 0.21) = %v, want 26.03", vat)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cleanbuddy/backend/internal/config"
+	"github.com/cleanbuddy/backend/internal/db"
+	"github.com/cleanbuddy/backend/internal/services"
+)
+
+// Export a month's invoices, credit notes, payments and payouts for the accountant
+// Run with: go run scripts/export_accounting.go -format saga -month 2026-09 -out ./exports
+
+func main() {
+	format := flag.String("format", "saga", "saga, winmentor or saft (D406)")
+	month := flag.String("month", time.Now().AddDate(0, -1, 0).Format("2006-01"), "month to export (YYYY-MM), last month by default")
+	outDir := flag.String("out", "./exports", "directory the files are written to")
+	flag.Parse()
+
+	from, err := time.ParseInLocation("2006-01", *month, time.Local)
+	if err != nil {
+		log.Fatalf("Invalid month %q: %v", *month, err)
+	}
+
+	configPath := os.Getenv("CONFIG_PATH")
+	if configPath == "" {
+		configPath = "config/config.yaml"
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	database, err := db.New()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	exporter := services.NewAccountingExportService(database.DB, &cfg.Company, &cfg.Accounting)
+	files, err := exporter.Export(services.AccountingExportFormat(strings.ToUpper(*format)), from, from.AddDate(0, 1, 0))
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		log.Fatalf("Failed to create %s: %v", *outDir, err)
+	}
+	for _, file := range files {
+		path := filepath.Join(*outDir, file.FileName)
+		if err := os.WriteFile(path, file.Content, 0644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		log.Printf("✅ Wrote %s", path)
+	}
+}